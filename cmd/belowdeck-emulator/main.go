@@ -2,10 +2,11 @@ package main
 
 import (
 	"context"
-	"image"
-	"log"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
 	"syscall"
 	"time"
@@ -14,22 +15,53 @@ import (
 	"github.com/phinze/belowdeck/internal/coordinator"
 	"github.com/phinze/belowdeck/internal/device"
 	"github.com/phinze/belowdeck/internal/device/emulator"
-	"github.com/phinze/belowdeck/internal/module"
-	"github.com/phinze/belowdeck/internal/modules/github"
-	"github.com/phinze/belowdeck/internal/modules/homeassistant"
-	"github.com/phinze/belowdeck/internal/modules/nowplaying"
-	"github.com/phinze/belowdeck/internal/modules/weather"
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/layout"
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/phinze/belowdeck/internal/power"
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/phinze/belowdeck/internal/replay"
+	"github.com/phinze/belowdeck/internal/state"
 )
 
 func main() {
-	log.Println("=== Stream Deck Emulator ===")
-	log.Println("Close window or press Ctrl+C to exit")
+	modelName := flag.String("model", "plus", "Stream Deck model to emulate: plus, mk2, mini, or xl")
+	captureDir := flag.String("capture-dir", ".", "Directory for F12 screenshots and F9 recordings")
+	recordFile := flag.String("record", "", "Record key, dial, and strip input to this file")
+	replayFile := flag.String("replay", "", "Replay input recorded with --record, here or by the daemon")
+	replaySpeed := flag.Float64("replay-speed", 1, "How fast to replay, e.g. 2 for twice as fast")
+	profile := flag.String("profile", "", "Config profile to use instead of choosing one by Wi-Fi network or monitor")
+	flag.Parse()
 
-	// Check if media-control is available
-	if _, err := exec.LookPath("media-control"); err != nil {
-		log.Fatal("media-control not found. Install with: brew tap ungive/media-control && brew install media-control")
+	model, err := emulator.ParseModel(*modelName)
+	if err != nil {
+		fatal("Invalid model", err)
 	}
 
+	// Load configuration
+	if *profile != "" {
+		config.UseProfile(*profile)
+	}
+	cfg, err := config.Load()
+
+	// Set up logging first so everything after lands in the configured target
+	var logCfg config.LoggingConfig
+	if cfg != nil {
+		logCfg = cfg.Logging
+	}
+	logCloser, logErr := logging.Setup(logCfg)
+	if logErr != nil {
+		fatal("Failed to set up logging", logErr)
+	}
+	defer logCloser.Close()
+
+	slog.Info("=== Stream Deck Emulator ===")
+	slog.Info("Close window or press Ctrl+C to exit, F8 simulates sleep and wake, F7 unplugging")
+	if err != nil {
+		slog.Warn("Config load failed", "err", err)
+	}
+	render.SetIconDir(cfg.IconPack())
+
 	// Setup signal handling
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -39,82 +71,154 @@ func main() {
 
 	go func() {
 		<-sigChan
-		log.Println("\nReceived shutdown signal")
+		slog.Info("Received shutdown signal")
 		cancel()
 	}()
 
-	emu := emulator.New()
+	emu := emulator.New(model)
+	emu.SetCaptureDir(*captureDir)
 	if err := emu.Open(); err != nil {
-		log.Fatalf("Failed to open emulator: %v", err)
+		fatal("Failed to open emulator", err)
 	}
 
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		log.Printf("Warning: config load: %v", err)
+	// Optional WebSocket event stream, so external tools can be tried without hardware
+	if cfg != nil && cfg.Events.Listen != "" {
+		go func() {
+			if err := events.Serve(ctx, cfg.Events.Listen, map[string]http.Handler{"/privacy": coordinator.PrivacyHandler()}); err != nil {
+				slog.Error("Event stream failed", "err", err)
+			}
+		}()
 	}
 
+	// F8 stands in for the system sleeping, to exercise reconnects
+	sim := power.NewSimulator()
+	emu.SimulatePower(sim)
+	wakes := power.WatchWakes(ctx, sim)
+
 	// Start coordinator in background goroutine
-	go runWithDevice(ctx, cfg, emu)
+	go runWithDevice(ctx, cfg, emu, wakes)
+
+	// Input recording and replay, for reproducing interaction bugs
+	if *recordFile != "" {
+		go recordInput(ctx, *recordFile)
+	}
+	if *replayFile != "" {
+		go replayInput(ctx, emu, *replayFile, *replaySpeed)
+	}
 
 	// Run GUI on main thread (required for macOS)
 	if err := emu.RunGUI(); err != nil {
-		log.Printf("Emulator GUI error: %v", err)
+		slog.Error("Emulator GUI error", "err", err)
+	}
+}
+
+// fatal logs msg with err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
+
+// recordInput records the deck's input to path until ctx is done.
+func recordInput(ctx context.Context, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		slog.Error("Failed to record input", "err", err)
+		return
+	}
+	defer f.Close()
+
+	slog.Info("Recording input", "file", path)
+	if err := replay.Record(ctx, f); err != nil {
+		slog.Error("Failed to record input", "err", err)
 	}
 }
 
-// runWithDevice runs the coordinator with the given device until context cancel.
-func runWithDevice(ctx context.Context, cfg *config.Config, dev device.Device) {
-	log.Printf("Connected to: %s", dev.GetModelName())
+// replayInput plays the input recorded in path into emu once the
+// coordinator is listening.
+func replayInput(ctx context.Context, emu *emulator.Emulator, path string, speed float64) {
+	f, err := os.Open(path)
+	if err != nil {
+		slog.Error("Failed to replay input", "err", err)
+		return
+	}
+	inputs, err := replay.Load(f)
+	f.Close()
+	if err != nil {
+		slog.Error("Failed to replay input", "file", path, "err", err)
+		return
+	}
 
-	// Set brightness and clear keys
-	dev.SetBrightness(80)
-	dev.ForEachKey(func(key device.KeyID) error {
-		return dev.ClearKey(key)
-	})
+	for !emu.Listening() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
 
-	// Create coordinator and modules
+	slog.Info("Replaying input", "file", path, "inputs", len(inputs))
+	if err := replay.Replay(ctx, emu, inputs, speed); err != nil {
+		slog.Warn("Replayed input returned errors", "err", err)
+	}
+	slog.Info("Replay finished")
+}
+
+// runWithDevice runs the coordinator with the given device until context
+// cancel. On wake it detaches and reattaches, and when unplugged it closes
+// the device and opens it again once it's back, as the daemon reconnects.
+func runWithDevice(ctx context.Context, cfg *config.Config, emu *emulator.Emulator, wakes *power.Wakes) {
+	var dev device.Device = emu
+	slog.Info("Connected", "model", dev.GetModelName())
+
+	// Create coordinator and modules from the configured layout
 	coord := coordinator.New(dev)
 
-	np := nowplaying.New(dev)
-	coord.RegisterModule(np, module.Resources{
-		Keys:      []module.KeyID{module.Key5, module.Key6},
-		StripRect: image.Rect(0, 0, 400, 100),
-		Dials:     []module.DialID{module.Dial1, module.Dial2},
-	})
-
-	w := weather.New(dev, cfg)
-	coord.RegisterModule(w, module.Resources{
-		StripRect: image.Rect(400, 0, 800, 100),
-	})
-
-	ha := homeassistant.New(dev, cfg)
-	coord.RegisterModule(ha, module.Resources{
-		Keys:  []module.KeyID{module.Key1, module.Key2},
-		Dials: []module.DialID{module.Dial4},
-	})
-
-	gh := github.New(dev)
-	coord.RegisterModule(gh, module.Resources{
-		Keys: []module.KeyID{module.Key3, module.Key4},
-	})
-
-	// Run coordinator
-	errChan := make(chan error, 1)
-	go func() {
-		errChan <- coord.Start(ctx)
-	}()
+	if err := layout.Register(coord, dev, cfg); err != nil {
+		for _, err := range layout.Errors(err) {
+			slog.Error("Module not registered, fix the layout in config.yaml", "err", err)
+		}
+	}
 
-	log.Println("Ready! Media on left, weather on right")
+	for attached := true; attached; {
+		// Set brightness and clear keys
+		dev.SetBrightness(cfg.EffectiveBrightness())
+		dev.ForEachKey(func(key device.KeyID) error {
+			return dev.ClearKey(key)
+		})
 
-	// Wait for context cancel or error
-	select {
-	case <-ctx.Done():
-		log.Println("Shutting down...")
-	case err := <-errChan:
-		if err != nil {
-			log.Printf("Coordinator error: %v", err)
+		// Run coordinator
+		runCtx, runCancel := context.WithCancel(ctx)
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- coord.Start(runCtx)
+		}()
+
+		slog.Info("Ready")
+
+		// Wait for context cancel, error, or simulated wake
+		select {
+		case <-ctx.Done():
+			slog.Info("Shutting down")
+			attached = false
+		case err := <-errChan:
+			if errors.Is(err, emulator.ErrUnplugged) {
+				slog.Warn("Device disconnected", "err", err)
+				attached = reconnect(ctx, emu)
+				wakes.Drain()
+				break
+			}
+			if err != nil {
+				slog.Error("Coordinator error", "err", err)
+			}
+			attached = false
+		case <-wakes.C():
+			slog.Info("Reconnecting device after wake")
+			runCancel()
+			<-errChan
+			emu.Detach()
+			wakes.Drain()
 		}
+		runCancel()
 	}
 
 	// Stop coordinator with timeout
@@ -127,8 +231,36 @@ func runWithDevice(ctx context.Context, cfg *config.Config, dev device.Device) {
 	select {
 	case <-done:
 	case <-time.After(2 * time.Second):
-		log.Println("Cleanup timed out")
+		slog.Warn("Cleanup timed out")
+	}
+	if err := state.Flush(); err != nil {
+		slog.Warn("Failed to save state", "err", err)
 	}
 
+	// An unplugged emulator is already closed, but its window isn't
 	dev.Close()
+	emu.Quit()
+}
+
+// reconnect closes the unplugged emu and opens it again once it's plugged
+// back in, returning false if ctx is done first.
+func reconnect(ctx context.Context, emu *emulator.Emulator) bool {
+	if err := emu.Close(); err != nil {
+		slog.Warn("Failed to close device", "err", err)
+	}
+	slog.Info("Waiting for device reconnect")
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-emu.Arrivals():
+		}
+		slog.Info("USB device arrival detected, probing")
+		if err := emu.Open(); err != nil {
+			slog.Warn("Failed to open device", "err", err)
+			continue
+		}
+		slog.Info("Connected", "model", emu.GetModelName())
+		return true
+	}
 }
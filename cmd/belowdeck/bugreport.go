@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bugreport"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/spf13/cobra"
+)
+
+var bugreportCmd = &cobra.Command{
+	Use:   "bugreport [description]",
+	Short: "Write a diagnostic bundle to attach to a bug report",
+	Long: "Writes the recent log, goroutine stacks, the config file with its secrets\n" +
+		"stripped, and the connected decks to a new temporary directory, and prints\n" +
+		"its path. The daemon writes one itself when it panics or the deck keeps\n" +
+		"failing. A deck the daemon has open can't report its firmware.",
+	RunE:         runBugreport,
+	SilenceUsage: true,
+}
+
+func runBugreport(cmd *cobra.Command, args []string) error {
+	reason := "requested with 'belowdeck bugreport'"
+	if len(args) > 0 {
+		reason = strings.Join(args, " ")
+	}
+
+	var devs []device.Device
+	sds, err := enumerateWithTimeout(2 * time.Second)
+	if err != nil {
+		fmt.Printf("Stream Deck not detected: %v\n", err)
+	}
+	for _, sd := range sds {
+		dev := device.NewHardware(sd)
+		if err := dev.Open(); err == nil {
+			defer dev.Close()
+		}
+		devs = append(devs, dev)
+	}
+
+	path, err := bugreport.Write(reason, devs)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Bug report written to %s\n", path)
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the config file",
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the config file to the current format",
+	Long: "Rewrites config.yaml in the format this belowdeck uses, keeping comments and\n" +
+		"saving the original as config.yaml.bak. Older files work without this, as\n" +
+		"they're upgraded each time they load.",
+	Args:         cobra.NoArgs,
+	RunE:         runConfigMigrate,
+	SilenceUsage: true,
+}
+
+func init() {
+	configCmd.AddCommand(configMigrateCmd)
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	path := config.DefaultConfigPath()
+	from, err := config.MigrateFile()
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s not found; run 'belowdeck setup' to create it", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if from == config.CurrentVersion {
+		fmt.Printf("%s is already version %d.\n", path, from)
+		return nil
+	}
+	fmt.Printf("Upgraded %s from version %d to %d; the original is in %s.bak.\n", path, from, config.CurrentVersion, path)
+	return nil
+}
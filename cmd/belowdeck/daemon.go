@@ -2,43 +2,70 @@ package main
 
 import (
 	"context"
-	"image"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
+	"runtime/debug"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/phinze/belowdeck/internal/bugreport"
 	"github.com/phinze/belowdeck/internal/config"
 	"github.com/phinze/belowdeck/internal/coordinator"
 	"github.com/phinze/belowdeck/internal/device"
-	"github.com/phinze/belowdeck/internal/module"
-	"github.com/phinze/belowdeck/internal/modules/github"
-	"github.com/phinze/belowdeck/internal/modules/homeassistant"
-	"github.com/phinze/belowdeck/internal/modules/nowplaying"
-	"github.com/phinze/belowdeck/internal/modules/weather"
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/layout"
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/power"
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/phinze/belowdeck/internal/replay"
+	"github.com/phinze/belowdeck/internal/state"
 	"github.com/phinze/belowdeck/internal/usbwatch"
-	"github.com/prashantgupta24/mac-sleep-notifier/notifier"
 	"github.com/spf13/cobra"
 	"rafaelmartins.com/p/streamdeck"
 )
 
 func runDaemon(cmd *cobra.Command, args []string) error {
-	log.Println("=== Stream Deck Daemon ===")
-	log.Println("Press Ctrl+C to exit")
-
 	// Load configuration
 	cfg, err := config.Load()
-	if err != nil {
-		log.Printf("Warning: config load: %v", err)
+
+	// Set up logging first so everything after lands in the configured target
+	var logCfg config.LoggingConfig
+	if cfg != nil {
+		logCfg = cfg.Logging
 	}
+	logCloser, logErr := logging.Setup(logCfg)
+	if logErr != nil {
+		return fmt.Errorf("logging: %w", logErr)
+	}
+	defer logCloser.Close()
+
+	// A panic here takes the daemon down; leave a bug report behind first
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Daemon panicked", "panic", r, "stack", string(debug.Stack()))
+			reportFailure(fmt.Sprintf("daemon panicked: %v", r), nil)
+			panic(r)
+		}
+	}()
 
-	// Check if media-control is available
-	if _, err := exec.LookPath("media-control"); err != nil {
-		log.Fatal("media-control not found. Install with: brew tap ungive/media-control && brew install media-control")
+	slog.Info("=== Stream Deck Daemon ===")
+	if err != nil {
+		slog.Warn("Config load failed", "err", err)
+	}
+	if cfg != nil && cfg.Profile != "" {
+		slog.Info("Using profile", "profile", cfg.Profile)
+	}
+	if cfg.Migrated() {
+		slog.Info("Config file is in an older format, upgraded as it loaded; 'belowdeck config migrate' saves the upgrade")
 	}
+	render.SetIconDir(cfg.IconPack())
 
 	// Setup signal handling
 	ctx, cancel := context.WithCancel(context.Background())
@@ -49,31 +76,56 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 
 	go func() {
 		<-sigChan
-		log.Println("\nReceived shutdown signal")
+		slog.Info("Received shutdown signal")
 		cancel()
 	}()
 
-	// Start sleep/wake notifier and run device loop
-	sleepCh := notifier.GetInstance().Start()
-	wakeCh := make(chan struct{}, 1)
-	go func() {
-		for activity := range sleepCh {
-			if activity.Type == notifier.Awake {
-				log.Println("System wake detected")
-				select {
-				case wakeCh <- struct{}{}:
-				default:
-				}
+	// Optional Prometheus endpoint, alive across device reconnects
+	if cfg != nil && cfg.Metrics.Listen != "" {
+		go func() {
+			if err := metrics.Serve(ctx, cfg.Metrics.Listen); err != nil {
+				slog.Error("Metrics endpoint failed", "err", err)
 			}
-		}
-	}()
+		}()
+	}
+
+	// Optional WebSocket event stream and privacy control, also alive
+	// across reconnects
+	if cfg != nil && cfg.Events.Listen != "" {
+		go func() {
+			if err := events.Serve(ctx, cfg.Events.Listen, map[string]http.Handler{"/privacy": coordinator.PrivacyHandler()}); err != nil {
+				slog.Error("Event stream failed", "err", err)
+			}
+		}()
+	}
+
+	// Optional input recording, to replay in the emulator
+	if path, _ := cmd.Flags().GetString("record"); path != "" {
+		go recordInput(ctx, path)
+	}
+
+	// Profiles chosen by where the computer is are checked as it moves
+	var profiles <-chan *config.Config
+	if cfg.AutoProfile() {
+		profiles = watchProfile(ctx, cfg.Profile)
+	}
+
+	// Start sleep/wake notifier and run device loop
+	wakes := power.WatchWakes(ctx, power.Watch(ctx))
 
 	// Start event-driven USB device watcher (fires callback on device arrival)
 	deviceArrivedCh := usbwatch.Watch(ctx, 0x0fd9)
 
-	// Main device loop - wait for device, run, repeat on disconnect
+	// Main device loop - wait for device, run, repeat on disconnect. The
+	// deck's modules are kept across reconnects.
+	var d *deck
+	defer func() {
+		if d != nil {
+			d.stop()
+		}
+	}()
 	for {
-		dev := waitForHardwareDevice(ctx, wakeCh, deviceArrivedCh)
+		dev := waitForHardwareDevice(ctx, wakes, deviceArrivedCh)
 		if dev == nil {
 			// Context cancelled
 			break
@@ -82,44 +134,68 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		// Check context before starting - avoid race where device connects after shutdown requested
 		select {
 		case <-ctx.Done():
-			log.Println("Exiting...")
+			slog.Info("Exiting")
 			dev.Close()
 			return nil
 		default:
 		}
 
-		// Drain any stale wake signals that accumulated while waiting for device.
-		// Without this, a wake signal from before device enumeration would
-		// immediately trigger a teardown in runWithDevice.
-	drainWake:
-		for {
-			select {
-			case <-wakeCh:
-				log.Println("Draining stale wake signal")
-			default:
-				break drainWake
-			}
+		// Drop any wake from before the device enumerated
+		if wakes.Drain() {
+			slog.Debug("Drained stale wake signal")
 		}
 
 		// Brief stabilization delay - USB device enumeration may not be complete
 		// even after GetDevice succeeds. Give the device a moment to fully initialize.
 		time.Sleep(500 * time.Millisecond)
+		logDevice(dev)
+
+		// Modules are laid out for one model, so a different deck gets new ones
+		if d != nil && d.model != dev.GetModelName() {
+			slog.Info("Different model connected, recreating modules", "was", d.model, "now", dev.GetModelName())
+			d.stop()
+			d = nil
+		}
+		if d == nil {
+			d = newDeck(cfg, dev)
+		} else {
+			d.dev.Swap(dev)
+		}
 
-		runWithDevice(ctx, cfg, dev, wakeCh)
+		if next := runWithDevice(ctx, cfg, d, dev, wakes, profiles); next != nil {
+			slog.Info("Profile changed, recreating modules", "was", cfg.Profile, "now", next.Profile)
+			d.stop()
+			d = nil
+			cfg = next
+		}
 
 		// Check if we should exit or wait for reconnect
 		select {
 		case <-ctx.Done():
-			log.Println("Exiting...")
+			slog.Info("Exiting")
 			return nil
 		default:
-			log.Println("Waiting for device reconnect...")
+			slog.Info("Waiting for device reconnect")
 		}
 	}
 
 	return nil
 }
 
+// logDevice logs which deck connected, warning if its firmware has known
+// problems.
+func logDevice(dev device.Device) {
+	model := dev.GetModelName()
+	fw, err := dev.GetFirmwareVersion()
+	if err != nil {
+		slog.Warn("Failed to read Stream Deck firmware version", "err", err)
+	}
+	slog.Info("Stream Deck", "model", model, "serial", dev.GetSerialNumber(), "firmware", fw)
+	if w := device.FirmwareWarning(model, fw); w != "" {
+		slog.Warn("Stream Deck firmware has known problems", "firmware", fw, "problem", w)
+	}
+}
+
 // enumInFlight tracks whether a device enumeration goroutine is currently running.
 // IOHIDManagerCopyDevices can block indefinitely in the kernel when the USB subsystem
 // is in a bad state. Without this guard, each timed-out poll spawns a new goroutine
@@ -163,12 +239,12 @@ func tryGetDeviceWithTimeout(timeout time.Duration) *streamdeck.Device {
 		}
 		return r.dev
 	case <-time.After(timeout):
-		log.Println("Device detection timed out (enumeration goroutine still in CGO)")
+		slog.Warn("Device detection timed out (enumeration goroutine still in CGO)")
 		// Goroutine is stuck in kernel - clean up if it ever returns.
 		go func() {
 			r := <-ch
 			if r.dev != nil {
-				log.Println("Late device arrival from timed-out enumeration, closing")
+				slog.Warn("Late device arrival from timed-out enumeration, closing")
 				r.dev.Close()
 			}
 		}()
@@ -180,7 +256,7 @@ func tryGetDeviceWithTimeout(timeout time.Duration) *streamdeck.Device {
 // detection. The deviceArrivedCh fires when IOKit detects a matching HID device,
 // eliminating the need for periodic polling. Wake signals are kept as a fallback
 // for sleep/wake edge cases.
-func waitForHardwareDevice(ctx context.Context, wakeCh <-chan struct{}, deviceArrivedCh <-chan struct{}) device.Device {
+func waitForHardwareDevice(ctx context.Context, wakes *power.Wakes, deviceArrivedCh <-chan struct{}) device.Device {
 	const deviceTimeout = 5 * time.Second
 
 	// First, try to get an already-connected device
@@ -188,74 +264,115 @@ func waitForHardwareDevice(ctx context.Context, wakeCh <-chan struct{}, deviceAr
 		return device.NewHardware(dev)
 	}
 
-	log.Println("Waiting for device...")
+	slog.Info("Waiting for device")
 
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		case <-deviceArrivedCh:
-			log.Println("USB device arrival detected, probing...")
-		case <-wakeCh:
-			// After wake, USB devices may take several seconds to enumerate.
-			// Retry multiple times with short delays instead of just checking once.
-			log.Println("Wake signal received, probing for device...")
-			for i := 0; i < 10; i++ {
-				if dev := tryGetDeviceWithTimeout(deviceTimeout); dev != nil {
-					log.Println("Device connected!")
-					return device.NewHardware(dev)
-				}
-				select {
-				case <-ctx.Done():
-					return nil
-				case <-time.After(500 * time.Millisecond):
-				}
+			slog.Info("USB device arrival detected, probing")
+		case <-wakes.C():
+			slog.Info("Wake signal received, probing for device")
+			var dev *streamdeck.Device
+			if power.ProbeAfterWake(ctx, func() bool {
+				dev = tryGetDeviceWithTimeout(deviceTimeout)
+				return dev != nil
+			}) {
+				slog.Info("Device connected")
+				return device.NewHardware(dev)
+			}
+			if ctx.Err() != nil {
+				return nil
 			}
-			log.Println("Device not found after wake, resuming wait...")
+			slog.Info("Device not found after wake, resuming wait")
 			continue
 		}
 
 		if dev := tryGetDeviceWithTimeout(deviceTimeout); dev != nil {
-			log.Println("Device connected!")
+			slog.Info("Device connected")
 			return device.NewHardware(dev)
 		}
 	}
 }
 
-// runWithDevice runs the coordinator with the given device until disconnect, wake, or context cancel.
-func runWithDevice(ctx context.Context, cfg *config.Config, dev device.Device, wakeCh <-chan struct{}) {
-	log.Printf("Connected to: %s", dev.GetModelName())
+// deck is the coordinator and modules for a connected model. It outlives
+// any one connection, so a reconnect after sleep or unplugging only rebinds
+// the device, keeping module state and cached data instead of refetching.
+type deck struct {
+	dev   *device.SwitchableDevice
+	coord *coordinator.Coordinator
+	model string
 
-	// Set brightness and clear keys
-	dev.SetBrightness(80)
-	dev.ForEachKey(func(key device.KeyID) error {
-		return dev.ClearKey(key)
+	stopOnce sync.Once
+}
+
+// newDeck creates the coordinator and registers the configured modules for dev.
+func newDeck(cfg *config.Config, dev device.Device) *deck {
+	d := &deck{dev: device.NewSwitchable(dev), model: dev.GetModelName()}
+	d.coord = coordinator.New(d.dev)
+	d.coord.SaveHealth()
+	d.coord.OnFailure(func(reason string) {
+		go reportFailure(reason, d.dev)
 	})
+	if err := layout.Register(d.coord, d.dev, cfg); err != nil {
+		for _, err := range layout.Errors(err) {
+			slog.Error("Module not registered, fix the layout in config.yaml", "err", err)
+		}
+	}
+	return d
+}
 
-	// Create coordinator and modules fresh for each connection
-	coord := coordinator.New(dev)
+// reportFailure writes a bug report for reason, describing dev unless it's
+// nil, and logs where it went.
+func reportFailure(reason string, dev device.Device) {
+	var devs []device.Device
+	if dev != nil {
+		devs = append(devs, dev)
+	}
+	if path := bugreport.Auto(reason, devs); path != "" {
+		slog.Error("Wrote bug report", "reason", reason, "path", path)
+	}
+}
 
-	np := nowplaying.New(dev)
-	coord.RegisterModule(np, module.Resources{
-		Keys:      []module.KeyID{module.Key5, module.Key6},
-		StripRect: image.Rect(0, 0, 400, 100),
-		Dials:     []module.DialID{module.Dial1, module.Dial2},
-	})
+// stop shuts down the deck's modules and saves their state. Only the first
+// call does anything.
+func (d *deck) stop() {
+	d.stopOnce.Do(func() {
+		done := make(chan struct{})
+		go func() {
+			d.coord.Stop()
+			close(done)
+		}()
 
-	w := weather.New(dev, cfg)
-	coord.RegisterModule(w, module.Resources{
-		StripRect: image.Rect(400, 0, 800, 100),
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			slog.Warn("Cleanup timed out")
+		}
+		if err := state.Flush(); err != nil {
+			slog.Warn("Failed to save state", "err", err)
+		}
 	})
+}
 
-	ha := homeassistant.New(dev, cfg)
-	coord.RegisterModule(ha, module.Resources{
-		Keys:  []module.KeyID{module.Key1, module.Key2},
-		Dials: []module.DialID{module.Dial4},
-	})
+// stallRespawnWindow is how soon after one render stall another makes the
+// daemon exit to be respawned instead of reconnecting again.
+const stallRespawnWindow = time.Minute
+
+// lastStall is when the render loop last stalled.
+var lastStall time.Time
 
-	gh := github.New(dev)
-	coord.RegisterModule(gh, module.Resources{
-		Keys: []module.KeyID{module.Key3, module.Key4},
+// runWithDevice runs the deck on dev, which it's bound to, until disconnect,
+// wake, a profile change, or context cancel. It returns the config for a
+// new profile, which the deck should be recreated with, or nil.
+func runWithDevice(ctx context.Context, cfg *config.Config, d *deck, dev device.Device, wakes *power.Wakes, profiles <-chan *config.Config) (next *config.Config) {
+	slog.Info("Connected", "model", dev.GetModelName())
+
+	// Set brightness and clear keys
+	dev.SetBrightness(cfg.EffectiveBrightness())
+	dev.ForEachKey(func(key device.KeyID) error {
+		return dev.ClearKey(key)
 	})
 
 	// Run coordinator with a child context so we can stop it independently
@@ -264,36 +381,48 @@ func runWithDevice(ctx context.Context, cfg *config.Config, dev device.Device, w
 
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- coord.Start(runCtx)
+		errChan <- d.coord.Start(runCtx)
 	}()
 
-	log.Println("Ready! Media on left, weather on right")
+	slog.Info("Ready")
 
 	// Wait for parent context cancel, device error, or system wake
 	select {
 	case <-ctx.Done():
-		log.Println("Shutting down...")
+		slog.Info("Shutting down")
 	case err := <-errChan:
-		if err != nil {
-			log.Printf("Device disconnected: %v", err)
+		if errors.Is(err, coordinator.ErrRenderStalled) {
+			// Closing the device below unsticks the write; a deck that
+			// stalls again soon after is past what a reconnect fixes
+			if time.Since(lastStall) < stallRespawnWindow {
+				slog.Error("Deck stalled again after reconnecting, exiting for clean respawn", "err", err)
+				reportFailure(fmt.Sprintf("deck stalled twice within %s: %v", stallRespawnWindow, err), nil)
+				d.stop()
+				os.Exit(1)
+			}
+			lastStall = time.Now()
+			slog.Warn("Deck stalled, reconnecting", "err", err)
+		} else if err != nil {
+			slog.Warn("Device disconnected", "err", err)
 		}
-	case <-wakeCh:
-		log.Println("Reconnecting device after wake...")
+	case <-wakes.C():
+		slog.Info("Reconnecting device after wake")
+	case next = <-profiles:
+		// Detached and reconnected as after a wake, with new modules
+		slog.Info("Reconnecting device for new profile")
 	}
 
-	// Stop coordinator with timeout
+	// Detach the coordinator from the device with timeout; its modules keep
+	// running for the next connection unless we're shutting down
 	runCancel()
 
-	done := make(chan struct{})
-	go func() {
-		coord.Stop()
-		close(done)
-	}()
-
 	select {
-	case <-done:
+	case <-errChan:
 	case <-time.After(2 * time.Second):
-		log.Println("Cleanup timed out")
+		slog.Warn("Detaching from device timed out")
+	}
+	if ctx.Err() != nil {
+		d.stop()
 	}
 
 	// Brief delay to let any pending USB I/O callbacks complete.
@@ -319,12 +448,71 @@ func runWithDevice(ctx context.Context, cfg *config.Config, dev device.Device, w
 	// Exit instead and let launchd respawn cleanly.
 	select {
 	case <-ctx.Done():
-		log.Println("Exiting...")
+		slog.Info("Exiting")
 		os.Exit(0)
 	case <-closeDone:
 		// Device closed cleanly
 	case <-time.After(3 * time.Second):
-		log.Println("Device close timed out, exiting for clean respawn")
+		slog.Error("Device close timed out, exiting for clean respawn")
+		reportFailure("device close timed out", nil)
 		os.Exit(1)
 	}
+	return next
+}
+
+// profileCheckInterval is how often watchProfile checks where the computer
+// is. Joining a network after wake takes a few seconds, so a check at
+// reconnect alone would often miss it.
+const profileCheckInterval = time.Minute
+
+// watchProfile checks which profile applies every profileCheckInterval
+// until ctx is done, sending the config when it isn't current anymore.
+func watchProfile(ctx context.Context, current string) <-chan *config.Config {
+	ch := make(chan *config.Config)
+	go func() {
+		ticker := time.NewTicker(profileCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if name, err := config.ChooseProfile(); err != nil || name == current {
+				continue
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				slog.Warn("Failed to load config for new profile", "err", err)
+			}
+			if cfg == nil {
+				continue
+			}
+			if cfg.Profile == current {
+				continue
+			}
+			select {
+			case ch <- cfg:
+				current = cfg.Profile
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// recordInput records the deck's input to path until ctx is done.
+func recordInput(ctx context.Context, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		slog.Error("Failed to record input", "err", err)
+		return
+	}
+	defer f.Close()
+
+	slog.Info("Recording input", "file", path)
+	if err := replay.Record(ctx, f); err != nil {
+		slog.Error("Failed to record input", "err", err)
+	}
 }
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/layout"
+	"github.com/phinze/belowdeck/internal/modules/homeassistant"
+	"github.com/phinze/belowdeck/internal/modules/nowplaying/mediaremote"
+	"github.com/phinze/belowdeck/internal/modules/weather"
+	"github.com/spf13/cobra"
+	"rafaelmartins.com/p/streamdeck"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Run deep diagnostics: binaries, credentials, permissions, and devices",
+	Long: "Goes beyond 'status': runs required binaries, makes real API calls with the\n" +
+		"configured credentials, checks Input Monitoring permission, and probes each\n" +
+		"connected Stream Deck. Every failure comes with a suggested fix.",
+	RunE:         runDoctor,
+	SilenceUsage: true,
+}
+
+// doctorReport prints check results and counts failures.
+type doctorReport struct {
+	failures int
+}
+
+func (r *doctorReport) section(name string) {
+	fmt.Println()
+	fmt.Printf("%s:\n", name)
+}
+
+func (r *doctorReport) ok(label, detail string) {
+	fmt.Printf("  ok    %s: %s\n", label, detail)
+}
+
+func (r *doctorReport) skip(label, reason string) {
+	fmt.Printf("  skip  %s: %s\n", label, reason)
+}
+
+func (r *doctorReport) warn(label, detail, fix string) {
+	fmt.Printf("  warn  %s: %s\n", label, detail)
+	if fix != "" {
+		fmt.Printf("        Fix: %s\n", fix)
+	}
+}
+
+func (r *doctorReport) fail(label string, err error, fix string) {
+	r.failures++
+	fmt.Printf("  FAIL  %s: %v\n", label, err)
+	if fix != "" {
+		fmt.Printf("        Fix: %s\n", fix)
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	fmt.Println("=== Belowdeck Doctor ===")
+
+	r := &doctorReport{}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg := doctorConfig(r)
+	enabled := func(id string) bool {
+		if _, ok := layout.Unsupported(id); ok {
+			return false
+		}
+		return slices.ContainsFunc(cfg.EffectiveLayout().AllModules(), func(ml config.ModuleLayout) bool {
+			return ml.ID == id
+		})
+	}
+
+	doctorBinaries(ctx, r, cfg, enabled)
+	doctorCredentials(ctx, r, cfg, enabled)
+	doctorPermissions(r)
+	doctorDevices(r)
+
+	fmt.Println()
+	if r.failures > 0 {
+		fmt.Printf("%d check(s) failed.\n", r.failures)
+		return errors.New("doctor found problems")
+	}
+	fmt.Println("All checks passed.")
+	return nil
+}
+
+// doctorConfig loads the config, reporting parse and layout errors.
+func doctorConfig(r *doctorReport) *config.Config {
+	r.section("Config")
+
+	path := config.DefaultConfigPath()
+	if _, err := os.Stat(path); err != nil {
+		r.warn("file", path+" not found", "Run 'belowdeck setup' to create it")
+	} else {
+		r.ok("file", path)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		r.fail("load", err, "Fix the error in "+path+" (see README for the format)")
+		if cfg == nil {
+			return &config.Config{}
+		}
+		return cfg
+	}
+	r.ok("load", "parsed, layout valid")
+	if cfg.Migrated() {
+		r.warn("format", "older than this belowdeck's (upgraded as it loads)", "Run 'belowdeck config migrate'")
+	}
+	return cfg
+}
+
+// doctorBinaries checks that external tools are installed and actually run.
+func doctorBinaries(ctx context.Context, r *doctorReport, cfg *config.Config, enabled func(string) bool) {
+	r.section("Binaries")
+
+	if enabled("nowplaying") {
+		mctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		info, err := mediaremote.NowPlaying(mctx)
+		cancel()
+		switch {
+		case err != nil:
+			r.fail("MediaRemote", err, "Now Playing needs macOS's MediaRemote framework; remove nowplaying from layout if it's unavailable")
+		case info.Title == "":
+			r.warn("MediaRemote", "nothing playing", "Start playback and rerun; if it still shows nothing, this macOS version may withhold now-playing info from belowdeck")
+		default:
+			r.ok("MediaRemote", info.Artist+" - "+info.Title)
+		}
+	} else {
+		r.skip("MediaRemote", "nowplaying not in layout")
+	}
+
+	if enabled("github") {
+		args := []string{"auth", "status"}
+		if cfg != nil && cfg.GitHub.Host != "" {
+			args = append(args, "--hostname", cfg.GitHub.Host)
+		}
+		if cfg != nil && cfg.GitHub.Token != "" {
+			r.ok("gh", "not needed, GitHub token configured")
+		} else if _, err := runTool(ctx, "gh", args...); err != nil {
+			r.fail("gh", err, "brew install gh && gh auth login, or set GITHUB_TOKEN")
+		} else {
+			r.ok("gh", "authenticated")
+		}
+	} else {
+		r.skip("gh", "github not in layout")
+	}
+
+	if enabled("yabai") {
+		if out, err := runTool(ctx, "yabai", "--version"); err != nil {
+			r.fail("yabai", err, "brew install koekeishiya/formulae/yabai && yabai --start-service")
+		} else {
+			r.ok("yabai", out)
+		}
+	}
+}
+
+// runTool runs a binary with a short timeout and returns its first output line.
+func runTool(ctx context.Context, name string, args ...string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", errors.New("not found in PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	first, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if err != nil {
+		if first != "" {
+			return "", fmt.Errorf("%v: %s", err, first)
+		}
+		return "", err
+	}
+	if len(first) > 60 {
+		first = first[:57] + "..."
+	}
+	if first == "" {
+		first = path
+	}
+	return first, nil
+}
+
+// doctorCredentials makes real API calls with the configured secrets.
+func doctorCredentials(ctx context.Context, r *doctorReport, cfg *config.Config, enabled func(string) bool) {
+	r.section("Credentials")
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	weatherName := weather.ProviderName(cfg)
+	if enabled("weather") {
+		if err := weather.Probe(ctx, cfg); err != nil {
+			fix := "Run 'belowdeck setup' to set the weather provider, location, and API key"
+			if strings.Contains(err.Error(), "401") {
+				fix = "Check the key at openweathermap.org; One Call 3.0 needs the separate \"One Call by Call\" subscription, or switch to weather.provider: open-meteo"
+			}
+			r.fail(weatherName, err, fix)
+		} else {
+			r.ok(weatherName, "API call succeeded")
+		}
+	} else {
+		r.skip(weatherName, "weather not in layout")
+	}
+
+	if !enabled("homeassistant") {
+		r.skip("Home Assistant", "homeassistant not in layout")
+		return
+	}
+	ha := cfg.HomeAssistant
+	if ha.Server == "" || ha.Token == "" {
+		r.fail("Home Assistant", errors.New("server or token not set"), "Run 'belowdeck setup'")
+		return
+	}
+
+	client := homeassistant.NewClient(ha.Server, ha.Token)
+	if err := client.Ping(ctx); err != nil {
+		fix := "Check that " + ha.Server + " is reachable from this Mac"
+		if strings.Contains(err.Error(), "401") {
+			fix = "Create a new long-lived access token (Profile > Security) and run 'belowdeck setup'"
+		}
+		r.fail("Home Assistant", err, fix)
+		return
+	}
+	r.ok("Home Assistant", ha.Server)
+
+	entities := append([]string{ha.RingLightEntity, ha.OfficeLightEntity}, ha.Entities...)
+	for _, id := range entities {
+		if id == "" {
+			continue
+		}
+		if _, err := client.GetState(ctx, id); err != nil {
+			r.fail(id, err, "Check the entity ID in Home Assistant (Settings > Entities)")
+		} else {
+			r.ok(id, "found")
+		}
+	}
+}
+
+// doctorDevices opens each connected Stream Deck and reports what it finds.
+func doctorDevices(r *doctorReport) {
+	r.section("Stream Deck")
+
+	devices, err := enumerateWithTimeout(5 * time.Second)
+	if err != nil {
+		r.fail("USB", err, "Unplug and replug the Stream Deck; if this persists, restart the Mac")
+		return
+	}
+	if len(devices) == 0 {
+		r.fail("USB", errors.New("no devices found"), "Connect the Stream Deck directly (not through an unpowered hub)")
+		return
+	}
+
+	for _, dev := range devices {
+		label := dev.GetModelName()
+		if err := dev.Open(); err != nil {
+			r.fail(label, err, "Quit the Elgato Stream Deck app; only one program can control the device")
+			continue
+		}
+
+		fw, err := dev.GetFirmwareVersion()
+		if err != nil {
+			fw = "unknown"
+		}
+		r.ok(label, fmt.Sprintf("serial %s, firmware %s, %d keys, %d dials, strip=%v",
+			dev.GetSerialNumber(), fw, dev.GetKeyCount(), dev.GetDialCount(), dev.GetTouchStripSupported()))
+		if w := device.FirmwareWarning(label, fw); w != "" {
+			r.warn(label+" firmware", w, "")
+		}
+		dev.Close()
+	}
+}
+
+// enumerateWithTimeout lists Stream Decks, giving up if IOKit enumeration
+// hangs (see tryGetDeviceWithTimeout).
+func enumerateWithTimeout(timeout time.Duration) ([]*streamdeck.Device, error) {
+	if !enumInFlight.CompareAndSwap(false, true) {
+		return nil, errors.New("a previous enumeration is still running")
+	}
+
+	type result struct {
+		devices []*streamdeck.Device
+		err     error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		defer enumInFlight.Store(false)
+		devices, err := streamdeck.Enumerate()
+		ch <- result{devices, err}
+	}()
+
+	select {
+	case res := <-ch:
+		return res.devices, res.err
+	case <-time.After(timeout):
+		return nil, errors.New("enumeration timed out (USB subsystem may be wedged)")
+	}
+}
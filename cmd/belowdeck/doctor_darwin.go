@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/phinze/belowdeck/internal/usbwatch"
+)
+
+// doctorPermissions checks the macOS privacy permissions the daemon needs.
+func doctorPermissions(r *doctorReport) {
+	r.section("Permissions")
+
+	exe, _ := os.Executable()
+	fix := "System Settings > Privacy & Security > Input Monitoring: enable " + exe
+
+	switch usbwatch.InputMonitoringAccess() {
+	case usbwatch.AccessGranted:
+		r.ok("Input Monitoring", "granted")
+	case usbwatch.AccessDenied:
+		r.fail("Input Monitoring", errors.New("denied"), fix)
+	default:
+		r.warn("Input Monitoring", "not yet requested; macOS will prompt on first run", "")
+	}
+}
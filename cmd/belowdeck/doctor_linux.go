@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// udevRule grants the logged-in user access to Stream Deck hidraw nodes.
+const udevRule = `SUBSYSTEM=="hidraw", ATTRS{idVendor}=="0fd9", TAG+="uaccess"`
+
+// doctorPermissions checks that this user can open the Stream Deck's hidraw
+// nodes, which udev leaves root-only without a rule.
+func doctorPermissions(r *doctorReport) {
+	r.section("Permissions")
+
+	fix := "echo '" + udevRule + "' | sudo tee /etc/udev/rules.d/70-streamdeck.rules && " +
+		"sudo udevadm control --reload && sudo udevadm trigger, then replug the Stream Deck"
+
+	nodes, _ := filepath.Glob("/sys/class/hidraw/hidraw*")
+	found := false
+	for _, node := range nodes {
+		// HID_ID is bus:vendor:product, e.g. 0003:00000FD9:00000084
+		uevent, err := os.ReadFile(filepath.Join(node, "device", "uevent"))
+		if err != nil || !strings.Contains(strings.ToUpper(string(uevent)), ":00000FD9:") {
+			continue
+		}
+		found = true
+
+		path := filepath.Join("/dev", filepath.Base(node))
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			r.fail(path, err, fix)
+			continue
+		}
+		f.Close()
+		r.ok(path, "readable and writable")
+	}
+	if !found {
+		r.skip("hidraw", "no Stream Deck connected")
+	}
+}
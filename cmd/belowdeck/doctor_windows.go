@@ -0,0 +1,8 @@
+package main
+
+// doctorPermissions checks the permissions the daemon needs; Windows lets
+// any user open HID devices.
+func doctorPermissions(r *doctorReport) {
+	r.section("Permissions")
+	r.skip("HID access", "no permission needed on Windows")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/modules/kiosk"
+	"github.com/spf13/cobra"
+)
+
+var kioskCmd = &cobra.Command{
+	Use:   "kiosk PLACE [TEXT...]",
+	Short: "Show text or an image on the kiosk module's keys or strip",
+	Long: "Shows TEXT, or stdin if there's none, in PLACE: a key of the kiosk module by\n" +
+		"number (1 is its first key) or \"strip\". PNG and JPEG data from stdin is shown\n" +
+		"as an image.\n\n" +
+		"  belowdeck kiosk 1 'Build OK'\n" +
+		"  make test 2>&1 | tail -3 | belowdeck kiosk strip\n" +
+		"  belowdeck kiosk 2 < graph.png",
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         runKiosk,
+	SilenceUsage: true,
+}
+
+func init() {
+	kioskCmd.Flags().BoolP("markdown", "m", false, "show the text as markdown")
+	kioskCmd.Flags().Bool("clear", false, "empty PLACE")
+}
+
+func runKiosk(cmd *cobra.Command, args []string) error {
+	cfg, _ := config.Load()
+	dir := kiosk.Dir(cfg)
+	place := args[0]
+
+	if clearPlace, _ := cmd.Flags().GetBool("clear"); clearPlace {
+		if !kiosk.ValidPlace(place) {
+			return fmt.Errorf("invalid place %q: want %s or a key number", place, kiosk.PlaceStrip)
+		}
+		return kiosk.Clear(dir, place)
+	}
+
+	var data []byte
+	if len(args) > 1 {
+		data = []byte(strings.Join(args[1:], " "))
+	} else {
+		var err error
+		if data, err = io.ReadAll(os.Stdin); err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+	}
+
+	ext := ""
+	if markdown, _ := cmd.Flags().GetBool("markdown"); markdown {
+		ext = ".md"
+	}
+	return kiosk.Put(dir, place, ext, data)
+}
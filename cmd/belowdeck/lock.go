@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/spf13/cobra"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Lock the running deck against input",
+	Long: "Locks the running deck, so its keys, dials, and strip ignore input until\n" +
+		"'belowdeck unlock', the layout's unlock keys, or its lock gesture unlocks it.\n" +
+		"The lock lasts through restarts. Fails if no belowdeck daemon is running.",
+	Args:         cobra.NoArgs,
+	RunE:         func(cmd *cobra.Command, args []string) error { return coordinator.RequestLock(true) },
+	SilenceUsage: true,
+}
+
+var unlockCmd = &cobra.Command{
+	Use:          "unlock",
+	Short:        "Unlock the running deck",
+	Long:         "Unlocks the running deck. Fails if no belowdeck daemon is running.",
+	Args:         cobra.NoArgs,
+	RunE:         func(cmd *cobra.Command, args []string) error { return coordinator.RequestLock(false) },
+	SilenceUsage: true,
+}
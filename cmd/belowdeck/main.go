@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/phinze/belowdeck/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -12,11 +13,27 @@ var rootCmd = &cobra.Command{
 	Short: "Stream Deck Plus daemon",
 	Long:  "A modular Stream Deck Plus application combining media controls, calendar, home automation, weather, and more.",
 	RunE:  runDaemon,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			config.UseProfile(profile)
+		}
+	},
 }
 
 func init() {
+	rootCmd.PersistentFlags().String("profile", "", "use the config profile `NAME` instead of choosing one by Wi-Fi network or monitor")
+	rootCmd.Flags().String("record", "", "record key, dial, and strip input to `FILE`, for replaying in the emulator")
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(kioskCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(modulesCmd)
+	rootCmd.AddCommand(renderCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(bugreportCmd)
 }
 
 func main() {
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/layout"
+	"github.com/phinze/belowdeck/internal/modules/welcome"
+	"github.com/spf13/cobra"
+)
+
+var modulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "List, enable, and disable modules",
+}
+
+var modulesListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List modules with their keys, dials, strip, and health",
+	Args:         cobra.NoArgs,
+	RunE:         runModulesList,
+	SilenceUsage: true,
+}
+
+var modulesEnableCmd = &cobra.Command{
+	Use:          "enable MODULE",
+	Short:        "Take a module off the config's disabled list",
+	Args:         cobra.ExactArgs(1),
+	RunE:         func(cmd *cobra.Command, args []string) error { return setModuleDisabled(args[0], false) },
+	SilenceUsage: true,
+}
+
+var modulesDisableCmd = &cobra.Command{
+	Use:   "disable MODULE",
+	Short: "Leave a module out of the layout, keeping its settings",
+	Long: "Adds MODULE to disabled_modules in the config file, so the daemon leaves it\n" +
+		"out of the layout. Its layout entry and settings stay as they are, and\n" +
+		"'belowdeck modules enable' brings it back.",
+	Args:         cobra.ExactArgs(1),
+	RunE:         func(cmd *cobra.Command, args []string) error { return setModuleDisabled(args[0], true) },
+	SilenceUsage: true,
+}
+
+func init() {
+	modulesCmd.AddCommand(modulesListCmd)
+	modulesCmd.AddCommand(modulesEnableCmd)
+	modulesCmd.AddCommand(modulesDisableCmd)
+}
+
+func runModulesList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("%w\n(a module's settings can be skipped with 'belowdeck modules disable MODULE')", err)
+	}
+	health := coordinator.LoadHealth()
+	if cfg.Profile != "" {
+		fmt.Printf("Profile: %s\n\n", cfg.Profile)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tPAGE\tKEYS\tDIALS\tSTRIP\tSTATUS")
+	var listed []string
+	var list func(page string, modules []config.ModuleLayout, folders []config.FolderLayout)
+	list = func(page string, modules []config.ModuleLayout, folders []config.FolderLayout) {
+		for _, ml := range modules {
+			listed = append(listed, ml.ID)
+			strip := "-"
+			if ml.Strip != nil {
+				strip = fmt.Sprintf("%d+%d", ml.Strip.X, ml.Strip.Width)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", ml.ID, page, joinInts(ml.AllKeys()), joinInts(ml.Dials), strip, moduleStatus(cfg, health, ml.ID))
+		}
+		for _, f := range folders {
+			list(f.Label, f.Modules, f.Folders)
+		}
+	}
+	l := cfg.EffectiveLayout()
+	list("root", l.Modules, l.Folders)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var unused []string
+	for _, id := range layout.ModuleIDs() {
+		if !slices.Contains(listed, id) {
+			unused = append(unused, id)
+		}
+	}
+	if len(unused) > 0 {
+		fmt.Printf("\nNot in the layout: %s\n", strings.Join(unused, ", "))
+	}
+	return nil
+}
+
+// moduleStatus describes module id: disabled, unable to run here, missing
+// settings, or its health as the daemon last saved it.
+func moduleStatus(cfg *config.Config, health map[string]coordinator.ModuleHealth, id string) string {
+	if cfg.ModuleDisabled(id) {
+		return "disabled"
+	}
+	if !layout.Known(id) {
+		return "unknown module"
+	}
+	if reason, ok := layout.Unsupported(id); ok {
+		return "unsupported: " + reason
+	}
+	if missing := welcome.Missing(id, cfg); missing != "" {
+		return "needs " + missing
+	}
+	h, ok := health[id]
+	if !ok {
+		return "not started"
+	}
+	status := h.State
+	if h.Reason != "" {
+		status += ": " + h.Reason
+	}
+	return fmt.Sprintf("%s (since %s)", status, h.Since.Format("Jan 2 15:04"))
+}
+
+// setModuleDisabled disables or enables module id in the config file.
+func setModuleDisabled(id string, disabled bool) error {
+	if !layout.Known(id) {
+		return fmt.Errorf("unknown module %q (want one of %s)", id, strings.Join(layout.ModuleIDs(), ", "))
+	}
+	if err := config.SetModuleDisabled(id, disabled); err != nil {
+		return err
+	}
+
+	if disabled {
+		fmt.Printf("Disabled %s.\n", id)
+	} else {
+		fmt.Printf("Enabled %s.\n", id)
+		// Only checked on enabling: a module that fails to load is what
+		// disabling is for
+		if cfg, err := config.Load(); err == nil && !slices.ContainsFunc(cfg.EffectiveLayout().AllModules(), func(ml config.ModuleLayout) bool { return ml.ID == id }) {
+			fmt.Printf("%s isn't in the layout; add it under layout.modules to place it.\n", id)
+		}
+	}
+	fmt.Println("Restart belowdeck for the change to take effect.")
+	return nil
+}
+
+// joinInts formats ns as a comma-separated list, or "-" if empty.
+func joinInts(ns []int) string {
+	if len(ns) == 0 {
+		return "-"
+	}
+	s := make([]string, len(ns))
+	for i, n := range ns {
+		s[i] = fmt.Sprint(n)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image/png"
+	"os"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/device/fake"
+	"github.com/phinze/belowdeck/internal/layout"
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render the deck without hardware",
+}
+
+var renderPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Render the configured layout to a PNG",
+	Long: "Starts the configured layout's modules against an offscreen Stream Deck Plus,\n" +
+		"gives them --wait to fetch their data, and writes one frame of the whole\n" +
+		"deck to --output. The daemon can keep running; the preview doesn't touch the\n" +
+		"hardware.",
+	Args:         cobra.NoArgs,
+	RunE:         runRenderPreview,
+	SilenceUsage: true,
+}
+
+func init() {
+	renderPreviewCmd.Flags().StringP("output", "o", "deck.png", "write the PNG to `FILE`")
+	renderPreviewCmd.Flags().Duration("wait", 3*time.Second, "how long modules get to load before the frame is taken")
+	renderCmd.AddCommand(renderPreviewCmd)
+}
+
+func runRenderPreview(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	wait, _ := cmd.Flags().GetDuration("wait")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	// Only warnings, so the modules starting up don't bury the result
+	logCloser, err := logging.Setup(config.LoggingConfig{Level: "warn"})
+	if err != nil {
+		return fmt.Errorf("logging: %w", err)
+	}
+	defer logCloser.Close()
+	render.SetIconDir(cfg.IconPack())
+
+	dev := fake.New()
+	if err := dev.Open(); err != nil {
+		return err
+	}
+	coord := coordinator.New(dev)
+	if err := layout.Register(coord, dev, cfg); err != nil {
+		for _, err := range layout.Errors(err) {
+			fmt.Fprintf(os.Stderr, "Module not registered: %v\n", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- coord.Start(ctx)
+	}()
+	select {
+	case <-time.After(wait):
+	case err := <-done:
+		cancel()
+		return fmt.Errorf("starting modules: %w", err)
+	}
+	img := dev.Snapshot()
+
+	cancel()
+	coord.Stop()
+	dev.Close()
+	<-done
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("encoding preview: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n", output)
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "List, set, and delete the secrets kept in Keychain",
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "Show which secrets are set, without their values",
+	Args:         cobra.NoArgs,
+	RunE:         runSecretsList,
+	SilenceUsage: true,
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set SECRET [VALUE]",
+	Short: "Store a secret, replacing any it had",
+	Long: "Stores VALUE, or stdin if there's none, as SECRET. Reading stdin keeps the\n" +
+		"value out of shell history and the process list.\n\n" +
+		"  belowdeck secrets set hass-token < token.txt\n" +
+		"  op read op://deck/owm/key | belowdeck secrets set openweathermap-api-key",
+	Args:         cobra.RangeArgs(1, 2),
+	RunE:         runSecretsSet,
+	SilenceUsage: true,
+}
+
+var secretsDeleteCmd = &cobra.Command{
+	Use:          "delete SECRET",
+	Short:        "Remove a secret",
+	Args:         cobra.ExactArgs(1),
+	RunE:         runSecretsDelete,
+	SilenceUsage: true,
+}
+
+func init() {
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsDeleteCmd)
+}
+
+// knownSecrets returns the secrets belowdeck reads, including one per
+// configured TOTP account.
+func knownSecrets() []config.Secret {
+	secrets := append([]config.Secret(nil), config.Secrets...)
+	if cfg, _ := config.Load(); cfg != nil {
+		for _, a := range cfg.TOTP.Accounts {
+			secrets = append(secrets, config.Secret{Key: config.TOTPSecretKey(a.Label), Name: "TOTP secret for " + a.Label})
+		}
+	}
+	return secrets
+}
+
+// checkSecret returns an error naming the secrets there are if key isn't
+// one belowdeck reads. TOTP secrets are allowed before their account is
+// configured.
+func checkSecret(key string) error {
+	if prefix := config.TOTPSecretKey(""); strings.HasPrefix(key, prefix) && key != prefix {
+		return nil
+	}
+	var keys []string
+	for _, s := range config.Secrets {
+		if s.Key == key {
+			return nil
+		}
+		keys = append(keys, s.Key)
+	}
+	return fmt.Errorf("unknown secret %q (want %s, or %s)", key, strings.Join(keys, ", "), config.TOTPSecretKey("LABEL"))
+}
+
+func runSecretsList(cmd *cobra.Command, args []string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SECRET\tSTATUS\tUSED FOR")
+	for _, s := range knownSecrets() {
+		status := "set"
+		if _, err := config.GetKeychainSecret(s.Key); errors.Is(err, config.ErrSecretNotFound) {
+			status = "not set"
+		} else if err != nil {
+			status = "error: " + err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, status, s.Name)
+	}
+	return w.Flush()
+}
+
+func runSecretsSet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if err := checkSecret(key); err != nil {
+		return err
+	}
+
+	var value string
+	if len(args) > 1 {
+		value = args[1]
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	if value == "" {
+		return fmt.Errorf("empty value for %s; use 'belowdeck secrets delete' to remove it", key)
+	}
+
+	if err := config.SetKeychainSecret(key, value); err != nil {
+		return fmt.Errorf("storing %s in Keychain: %w", key, err)
+	}
+	fmt.Printf("Stored %s in Keychain. Restart belowdeck to use it.\n", key)
+	return nil
+}
+
+func runSecretsDelete(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if err := checkSecret(key); err != nil {
+		return err
+	}
+
+	err := config.DeleteKeychainSecret(key)
+	if errors.Is(err, config.ErrSecretNotFound) {
+		// Nothing to do, which is fine for a provisioning script
+		fmt.Printf("%s was not set.\n", key)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deleting %s from Keychain: %w", key, err)
+	}
+	fmt.Printf("Deleted %s from Keychain.\n", key)
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var installServiceCmd = &cobra.Command{
+	Use:   "install-service",
+	Short: "Install and start a systemd user service running the daemon",
+	RunE:  runInstallService,
+}
+
+func init() {
+	rootCmd.AddCommand(installServiceCmd)
+}
+
+// serviceUnit is the systemd user unit; %s is the daemon's path.
+const serviceUnit = `[Unit]
+Description=Belowdeck Stream Deck daemon
+After=graphical-session.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=default.target
+`
+
+func runInstallService(cmd *cobra.Command, args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "systemd", "user", "belowdeck.service")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(serviceUnit, exe)), 0o644); err != nil {
+		return fmt.Errorf("writing unit: %w", err)
+	}
+	fmt.Printf("Unit written to %s\n", path)
+
+	for _, args := range [][]string{
+		{"--user", "daemon-reload"},
+		{"--user", "enable", "--now", "belowdeck.service"},
+	} {
+		c := exec.Command("systemctl", args...)
+		c.Stdout, c.Stderr = os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("systemctl %s: %w", strings.Join(args, " "), err)
+		}
+	}
+	fmt.Println("Service started. Logs: journalctl --user -u belowdeck -f")
+	return nil
+}
@@ -2,11 +2,19 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/modules/ci"
+	"github.com/phinze/belowdeck/internal/modules/hue"
+	"github.com/phinze/belowdeck/internal/modules/mail"
+	"github.com/phinze/belowdeck/internal/modules/totp"
+	"github.com/phinze/belowdeck/internal/modules/tracker"
+	"github.com/phinze/belowdeck/internal/modules/weather"
 	"github.com/spf13/cobra"
 )
 
@@ -22,26 +30,35 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Load existing config as defaults
-	existing, _ := config.Load()
+	existing, _ := config.LoadBase()
 	if existing == nil {
 		existing = &config.Config{}
 	}
 
-	cfg := &config.Config{}
+	// Start from the existing config so sections setup doesn't prompt for
+	// (mqtt, layout) are preserved when the file is rewritten.
+	cfg := existing
 
 	// Weather config
 	fmt.Println("-- Weather --")
+	provider := existing.Weather.Provider
+	if provider == "" {
+		provider = weather.ProviderOpenWeatherMap
+	}
+	cfg.Weather.Provider = prompt(reader, "Weather provider (openweathermap, open-meteo, nws)", provider)
 	cfg.Weather.Lat = prompt(reader, "Weather latitude", existing.Weather.Lat)
 	cfg.Weather.Lon = prompt(reader, "Weather longitude", existing.Weather.Lon)
 
-	apiKey := promptSecret(reader, "OpenWeatherMap API key", existing.Weather.APIKey != "")
-	if apiKey != "" {
-		if err := config.SetKeychainSecret(config.KeyOpenWeatherMapAPIKey, apiKey); err != nil {
-			return fmt.Errorf("storing API key in Keychain: %w", err)
+	if cfg.Weather.Provider == weather.ProviderOpenWeatherMap {
+		apiKey := promptSecret(reader, "OpenWeatherMap API key", existing.Weather.APIKey != "")
+		if apiKey != "" {
+			if err := config.SetKeychainSecret(config.KeyOpenWeatherMapAPIKey, apiKey); err != nil {
+				return fmt.Errorf("storing API key in Keychain: %w", err)
+			}
+			fmt.Println("  -> Stored in Keychain")
+		} else {
+			fmt.Println("  -> Kept existing")
 		}
-		fmt.Println("  -> Stored in Keychain")
-	} else {
-		fmt.Println("  -> Kept existing")
 	}
 
 	fmt.Println()
@@ -51,6 +68,8 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	cfg.HomeAssistant.Server = prompt(reader, "Home Assistant server URL", existing.HomeAssistant.Server)
 	cfg.HomeAssistant.RingLightEntity = prompt(reader, "Ring light entity ID", existing.HomeAssistant.RingLightEntity)
 	cfg.HomeAssistant.OfficeLightEntity = prompt(reader, "Office light entity ID", existing.HomeAssistant.OfficeLightEntity)
+	entities := prompt(reader, "Extra entities (comma-separated, e.g. climate.office,scene.movie_night)", strings.Join(existing.HomeAssistant.Entities, ","))
+	cfg.HomeAssistant.Entities = config.SplitList(entities)
 
 	hassToken := promptSecret(reader, "Home Assistant token", existing.HomeAssistant.Token != "")
 	if hassToken != "" {
@@ -64,6 +83,151 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 
+	// Philips Hue config
+	fmt.Println("-- Philips Hue --")
+	bridge := existing.Hue.Bridge
+	if bridge == "" {
+		if found, err := hue.Discover(cmd.Context()); err == nil && len(found) > 0 {
+			bridge = found[0]
+		}
+	}
+	cfg.Hue.Bridge = prompt(reader, "Hue bridge address (blank to skip)", bridge)
+	if cfg.Hue.Bridge != "" {
+		pair := existing.Hue.AppKey == "" || cfg.Hue.Bridge != existing.Hue.Bridge ||
+			strings.EqualFold(prompt(reader, "Pair again? (y/N)", ""), "y")
+		if pair {
+			if err := pairHue(cmd, reader, cfg.Hue.Bridge); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println("  -> Kept existing")
+		}
+
+		rooms := prompt(reader, "Rooms or zones (comma-separated; blank for every room)", strings.Join(existing.Hue.Rooms, ","))
+		cfg.Hue.Rooms = config.SplitList(rooms)
+		scenes := prompt(reader, "Scenes (comma-separated, e.g. Relax,Office/Concentrate)", strings.Join(existing.Hue.Scenes, ","))
+		cfg.Hue.Scenes = config.SplitList(scenes)
+	}
+
+	fmt.Println()
+
+	// GitHub config
+	fmt.Println("-- GitHub --")
+	cfg.GitHub.Host = prompt(reader, "GitHub Enterprise host (blank for github.com)", existing.GitHub.Host)
+
+	githubToken := promptSecret(reader, "GitHub token (blank to use gh CLI)", existing.GitHub.Token != "")
+	if githubToken != "" {
+		if err := config.SetKeychainSecret(config.KeyGitHubToken, githubToken); err != nil {
+			return fmt.Errorf("storing GitHub token in Keychain: %w", err)
+		}
+		fmt.Println("  -> Stored in Keychain")
+	} else if existing.GitHub.Token != "" {
+		fmt.Println("  -> Kept existing")
+	}
+
+	fmt.Println()
+
+	// Issue tracker config
+	fmt.Println("-- Issue tracker --")
+	cfg.Tracker.Provider = prompt(reader, "Tracker provider (jira, linear; blank to skip)", existing.Tracker.Provider)
+	if cfg.Tracker.Provider == tracker.ProviderJira {
+		cfg.Tracker.URL = prompt(reader, "Jira URL (e.g. https://example.atlassian.net)", existing.Tracker.URL)
+		cfg.Tracker.Email = prompt(reader, "Jira account email (blank for a Server/Data Center token)", existing.Tracker.Email)
+	}
+	if cfg.Tracker.Provider != "" {
+		cfg.Tracker.Project = prompt(reader, "Project or team key (blank for all)", existing.Tracker.Project)
+
+		trackerToken := promptSecret(reader, "Tracker API token", existing.Tracker.Token != "")
+		if trackerToken != "" {
+			if err := config.SetKeychainSecret(config.KeyTrackerToken, trackerToken); err != nil {
+				return fmt.Errorf("storing tracker token in Keychain: %w", err)
+			}
+			fmt.Println("  -> Stored in Keychain")
+		} else if existing.Tracker.Token != "" {
+			fmt.Println("  -> Kept existing")
+		}
+	}
+
+	fmt.Println()
+
+	// Mail config
+	fmt.Println("-- Mail --")
+	cfg.Mail.Provider = prompt(reader, "Mail provider (imap, gmail; blank to skip)", existing.Mail.Provider)
+	switch cfg.Mail.Provider {
+	case mail.ProviderIMAP:
+		cfg.Mail.Server = prompt(reader, "IMAP server (host or host:port)", existing.Mail.Server)
+		cfg.Mail.Username = prompt(reader, "IMAP username", existing.Mail.Username)
+	case mail.ProviderGmail:
+		cfg.Mail.ClientID = prompt(reader, "Google OAuth client ID", existing.Mail.ClientID)
+		cfg.Mail.ClientSecret = prompt(reader, "Google OAuth client secret", existing.Mail.ClientSecret)
+	}
+	if cfg.Mail.Provider != "" {
+		mailboxes := prompt(reader, "Mailboxes or Gmail queries (comma-separated)", strings.Join(existing.Mail.Mailboxes, ","))
+		cfg.Mail.Mailboxes = config.SplitList(mailboxes)
+
+		label := "IMAP password"
+		if cfg.Mail.Provider == mail.ProviderGmail {
+			label = "Gmail OAuth refresh token"
+		}
+		password := promptSecret(reader, label, existing.Mail.Password != "")
+		if password != "" {
+			if err := config.SetKeychainSecret(config.KeyMailPassword, password); err != nil {
+				return fmt.Errorf("storing mail password in Keychain: %w", err)
+			}
+			fmt.Println("  -> Stored in Keychain")
+		} else if existing.Mail.Password != "" {
+			fmt.Println("  -> Kept existing")
+		}
+	}
+
+	fmt.Println()
+
+	// CI config (pipelines are configured in config.yaml)
+	fmt.Println("-- CI --")
+	cfg.CI.Provider = prompt(reader, "CI provider (buildkite, circleci, jenkins; blank to skip)", existing.CI.Provider)
+	switch cfg.CI.Provider {
+	case ci.ProviderBuildkite:
+		cfg.CI.Org = prompt(reader, "Buildkite organization slug", existing.CI.Org)
+	case ci.ProviderJenkins:
+		cfg.CI.URL = prompt(reader, "Jenkins URL", existing.CI.URL)
+		cfg.CI.Username = prompt(reader, "Jenkins username", existing.CI.Username)
+	}
+	if cfg.CI.Provider != "" {
+		ciToken := promptSecret(reader, "CI API token", existing.CI.Token != "")
+		if ciToken != "" {
+			if err := config.SetKeychainSecret(config.KeyCIToken, ciToken); err != nil {
+				return fmt.Errorf("storing CI token in Keychain: %w", err)
+			}
+			fmt.Println("  -> Stored in Keychain")
+		} else if existing.CI.Token != "" {
+			fmt.Println("  -> Kept existing")
+		}
+	}
+
+	fmt.Println()
+
+	// TOTP config
+	fmt.Println("-- TOTP --")
+	var labels []string
+	for _, a := range existing.TOTP.Accounts {
+		labels = append(labels, a.Label)
+	}
+	labels = config.SplitList(prompt(reader, "TOTP accounts (comma-separated labels; blank to skip)", strings.Join(labels, ",")))
+	var accounts []config.TOTPAccount
+	for _, label := range labels {
+		account := config.TOTPAccount{Label: label}
+		if i := slices.IndexFunc(existing.TOTP.Accounts, func(a config.TOTPAccount) bool { return a.Label == label }); i >= 0 {
+			account = existing.TOTP.Accounts[i]
+		}
+		if err := promptTOTPSecret(reader, &account); err != nil {
+			return err
+		}
+		accounts = append(accounts, account)
+	}
+	cfg.TOTP.Accounts = accounts
+
+	fmt.Println()
+
 	// Write config file
 	if err := config.WriteConfigFile(cfg); err != nil {
 		return fmt.Errorf("writing config file: %w", err)
@@ -73,6 +237,66 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// pairHue asks the Hue bridge for an application key, waiting on the user
+// to press its link button, and stores the key in the Keychain.
+func pairHue(cmd *cobra.Command, reader *bufio.Reader, bridge string) error {
+	for {
+		fmt.Print("  Press the link button on the bridge, then press Enter: ")
+		if _, err := reader.ReadString('\n'); err != nil {
+			return fmt.Errorf("pairing with Hue bridge: %w", err)
+		}
+		key, err := hue.Pair(cmd.Context(), bridge)
+		if errors.Is(err, hue.ErrLinkButton) {
+			fmt.Println("  -> The bridge didn't see the button press; try again")
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("pairing with Hue bridge: %w", err)
+		}
+		if err := config.SetKeychainSecret(config.KeyHueAppKey, key); err != nil {
+			return fmt.Errorf("storing Hue app key in Keychain: %w", err)
+		}
+		fmt.Println("  -> Paired; stored in Keychain")
+		return nil
+	}
+}
+
+// promptTOTPSecret asks for an account's secret, or the otpauth:// URI from
+// its setup QR code, and stores the secret in the Keychain.
+func promptTOTPSecret(reader *bufio.Reader, account *config.TOTPAccount) error {
+	for {
+		secret := promptSecret(reader, account.Label+" secret or otpauth:// URI", account.Secret != "")
+		if secret == "" {
+			if account.Secret != "" {
+				fmt.Println("  -> Kept existing")
+				return nil
+			}
+			fmt.Println("  -> A secret is required")
+			continue
+		}
+
+		if strings.HasPrefix(secret, "otpauth://") {
+			uri, err := totp.ParseURI(secret)
+			if err != nil {
+				fmt.Printf("  -> %v; try again\n", err)
+				continue
+			}
+			secret = uri.Secret
+			account.Digits, account.Period, account.Algorithm = uri.Digits, uri.Period, uri.Algorithm
+		} else if _, err := totp.DecodeSecret(secret); err != nil {
+			fmt.Printf("  -> %v; try again\n", err)
+			continue
+		}
+
+		if err := config.SetKeychainSecret(config.TOTPSecretKey(account.Label), secret); err != nil {
+			return fmt.Errorf("storing TOTP secret in Keychain: %w", err)
+		}
+		account.Secret = secret
+		fmt.Println("  -> Stored in Keychain")
+		return nil
+	}
+}
+
 // prompt asks for a value with an optional default.
 func prompt(reader *bufio.Reader, label, defaultVal string) string {
 	if defaultVal != "" {
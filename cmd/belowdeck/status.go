@@ -3,8 +3,11 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/modules/weather"
 	"github.com/spf13/cobra"
 )
 
@@ -40,6 +43,11 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	// Weather
 	fmt.Println("Weather:")
+	provider := weather.ProviderOpenWeatherMap
+	if cfg != nil && cfg.Weather.Provider != "" {
+		provider = cfg.Weather.Provider
+	}
+	fmt.Printf("  Provider: %s\n", provider)
 	if cfg != nil && cfg.Weather.Lat != "" && cfg.Weather.Lon != "" {
 		fmt.Printf("  Location: %s, %s\n", cfg.Weather.Lat, cfg.Weather.Lon)
 	} else {
@@ -47,7 +55,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		allOK = false
 	}
 
-	if _, err := config.GetKeychainSecret(config.KeyOpenWeatherMapAPIKey); err == nil {
+	if provider != weather.ProviderOpenWeatherMap {
+		fmt.Println("  API Key: not needed")
+	} else if _, err := config.GetKeychainSecret(config.KeyOpenWeatherMapAPIKey); err == nil {
 		fmt.Println("  API Key (Keychain): set")
 	} else if cfg != nil && cfg.Weather.APIKey != "" {
 		fmt.Println("  API Key (env): set")
@@ -79,6 +89,12 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		fmt.Println("  Office light: NOT SET")
 	}
 
+	if cfg != nil {
+		for _, entity := range cfg.HomeAssistant.Entities {
+			fmt.Printf("  Entity: %s\n", entity)
+		}
+	}
+
 	if _, err := config.GetKeychainSecret(config.KeyHASSToken); err == nil {
 		fmt.Println("  Token (Keychain): set")
 	} else if cfg != nil && cfg.HomeAssistant.Token != "" {
@@ -89,15 +105,103 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	// MQTT (optional)
+	fmt.Println("MQTT:")
+	if cfg != nil && cfg.MQTT.Broker != "" {
+		fmt.Printf("  Broker: %s\n", cfg.MQTT.Broker)
+		fmt.Printf("  Tiles: %d\n", len(cfg.MQTT.Tiles))
+	} else {
+		fmt.Println("  Broker: not configured (module disabled)")
+	}
+	fmt.Println()
+
+	// Issue tracker (optional)
+	fmt.Println("Tracker:")
+	if cfg != nil && cfg.Tracker.Provider != "" {
+		fmt.Printf("  Provider: %s\n", cfg.Tracker.Provider)
+		if cfg.Tracker.URL != "" {
+			fmt.Printf("  URL: %s\n", cfg.Tracker.URL)
+		}
+		if cfg.Tracker.Token != "" {
+			fmt.Println("  Token: set")
+		} else {
+			fmt.Println("  Token: NOT SET")
+			allOK = false
+		}
+	} else {
+		fmt.Println("  Provider: not configured (module disabled)")
+	}
+	fmt.Println()
+
+	// Mail (optional)
+	fmt.Println("Mail:")
+	if cfg != nil && cfg.Mail.Provider != "" {
+		fmt.Printf("  Provider: %s\n", cfg.Mail.Provider)
+		if cfg.Mail.Server != "" {
+			fmt.Printf("  Server: %s\n", cfg.Mail.Server)
+		}
+		for _, mb := range cfg.Mail.Mailboxes {
+			fmt.Printf("  Mailbox: %s\n", mb)
+		}
+		if cfg.Mail.Password != "" {
+			fmt.Println("  Password: set")
+		} else {
+			fmt.Println("  Password: NOT SET")
+			allOK = false
+		}
+	} else {
+		fmt.Println("  Provider: not configured (module disabled)")
+	}
+	fmt.Println()
+
+	// CI (optional)
+	fmt.Println("CI:")
+	if cfg != nil && cfg.CI.Provider != "" {
+		fmt.Printf("  Provider: %s\n", cfg.CI.Provider)
+		fmt.Printf("  Pipelines: %d\n", len(cfg.CI.Pipelines))
+		if cfg.CI.Token != "" {
+			fmt.Println("  Token: set")
+		} else {
+			fmt.Println("  Token: NOT SET")
+			allOK = false
+		}
+	} else {
+		fmt.Println("  Provider: not configured (module disabled)")
+	}
+	fmt.Println()
+
+	// Layout
+	fmt.Println("Layout:")
+	if cfg != nil && len(cfg.Layout.Modules) > 0 {
+		fmt.Println("  Source: config")
+	} else {
+		fmt.Println("  Source: default")
+	}
+	l := cfg.EffectiveLayout()
+	for _, ml := range l.Modules {
+		fmt.Printf("  %s: keys=%v dials=%v", ml.ID, ml.Keys, ml.Dials)
+		if len(ml.Slots) > 0 {
+			fmt.Printf(" slots=%v", ml.Slots)
+		}
+		if ml.Strip != nil {
+			fmt.Printf(" strip=%d+%d", ml.Strip.X, ml.Strip.Width)
+		}
+		fmt.Println()
+	}
+	printFolders(l.Folders, "  ")
+	fmt.Println()
+
 	// Device check (quick USB probe)
 	fmt.Println("Stream Deck:")
-	dev := tryGetDeviceWithTimeout(2_000_000_000) // 2s
-	if dev != nil {
-		fmt.Println("  Device: CONNECTED")
-		dev.Close()
-	} else {
+	devices, err := enumerateWithTimeout(2 * time.Second)
+	if err != nil {
+		fmt.Printf("  Device: not detected (%v)\n", err)
+	} else if len(devices) == 0 {
 		fmt.Println("  Device: not detected")
 	}
+	for _, sd := range devices {
+		printDevice(device.NewHardware(sd))
+	}
 	fmt.Println()
 
 	if allOK {
@@ -108,3 +212,44 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printDevice reports a connected deck's serial number, firmware, and USB
+// path, warning about firmware with known problems. Firmware can only be
+// read while no other program, such as the daemon, has the deck open.
+func printDevice(dev device.Device) {
+	model := dev.GetModelName()
+	fmt.Printf("  %s: CONNECTED\n", model)
+	fmt.Printf("    Serial: %s\n", dev.GetSerialNumber())
+	if err := dev.Open(); err != nil {
+		fmt.Printf("    Firmware: unknown (%v)\n", err)
+	} else {
+		fw, err := dev.GetFirmwareVersion()
+		dev.Close()
+		if err != nil {
+			fmt.Printf("    Firmware: unknown (%v)\n", err)
+		} else {
+			fmt.Printf("    Firmware: %s\n", fw)
+			if w := device.FirmwareWarning(model, fw); w != "" {
+				fmt.Printf("    WARNING: firmware %s: %s\n", fw, w)
+			}
+		}
+	}
+	if path := dev.GetUSBPath(); path != "" {
+		fmt.Printf("    USB path: %s\n", path)
+	}
+}
+
+// printFolders lists folder keys and their modules, indented by nesting.
+func printFolders(folders []config.FolderLayout, indent string) {
+	for _, f := range folders {
+		fmt.Printf("%sfolder %q: key=%d back=%d\n", indent, f.Label, f.Key, f.BackKey())
+		for _, ml := range f.Modules {
+			fmt.Printf("%s  %s: keys=%v", indent, ml.ID, ml.Keys)
+			if len(ml.Slots) > 0 {
+				fmt.Printf(" slots=%v", ml.Slots)
+			}
+			fmt.Println()
+		}
+		printFolders(f.Folders, indent+"  ")
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,17 +15,19 @@ import (
 )
 
 func main() {
-	log.Println("=== Stream Deck Plus Demo ===")
-	log.Println("Press Ctrl+C to exit")
+	slog.Info("=== Stream Deck Plus Demo ===")
+	slog.Info("Press Ctrl+C to exit")
 
 	// Enumerate all connected devices
 	devices, err := streamdeck.Enumerate()
 	if err != nil {
-		log.Fatalf("Failed to enumerate devices: %v", err)
+		slog.Error("Failed to enumerate devices", "err", err)
+		os.Exit(1)
 	}
 
 	if len(devices) == 0 {
-		log.Fatal("No Stream Deck devices found!")
+		slog.Error("No Stream Deck devices found")
+		os.Exit(1)
 	}
 
 	fmt.Printf("\nFound %d device(s):\n", len(devices))
@@ -37,10 +39,11 @@ func main() {
 	device := devices[0]
 
 	if err := device.Open(); err != nil {
-		log.Fatalf("Failed to open device: %v", err)
+		slog.Error("Failed to open device", "err", err)
+		os.Exit(1)
 	}
 	defer func() {
-		log.Println("Closing device...")
+		slog.Info("Closing device")
 		device.Close()
 	}()
 
@@ -82,19 +85,19 @@ func main() {
 		}
 	}()
 
-	log.Println("Ready! Try pressing buttons, rotating dials, or touching the strip...")
+	slog.Info("Ready! Try pressing buttons, rotating dials, or touching the strip")
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-sigChan:
-			log.Println("\nReceived interrupt signal")
+			slog.Info("Received interrupt signal")
 			cancel()
 			return
 		case err := <-errChan:
 			if err != nil {
-				log.Printf("Error: %v", err)
+				slog.Error("Listener error", "err", err)
 			}
 		}
 	}
@@ -122,26 +125,26 @@ func setupKeys(device *streamdeck.Device) {
 		device.SetKeyColor(key, c)
 
 		return device.AddKeyHandler(key, func(d *streamdeck.Device, k *streamdeck.Key) error {
-			log.Printf("Key %s pressed!", k)
+			slog.Info("Key pressed", "key", k)
 
 			// Flash white
 			d.SetKeyColor(key, color.White)
 
 			// Wait for release and measure duration
 			duration := k.WaitForRelease()
-			log.Printf("Key %s released after %v", k, duration)
+			slog.Info("Key released", "key", k, "duration", duration)
 
 			// Restore color
 			return d.SetKeyColor(key, c)
 		})
 	})
 
-	log.Println("Keys configured with rainbow colors")
+	slog.Info("Keys configured with rainbow colors")
 }
 
 func setupDials(device *streamdeck.Device) {
 	if device.GetDialCount() == 0 {
-		log.Println("No dials on this device")
+		slog.Info("No dials on this device")
 		return
 	}
 
@@ -152,32 +155,32 @@ func setupDials(device *streamdeck.Device) {
 			if delta < 0 {
 				direction = "counter-clockwise"
 			}
-			log.Printf("Dial %s rotated %s (delta: %d)", di, direction, delta)
+			slog.Info("Dial rotated", "dial", di, "direction", direction, "delta", delta)
 			return nil
 		})
 
 		// Handle press
 		return device.AddDialSwitchHandler(dial, func(d *streamdeck.Device, di *streamdeck.Dial) error {
-			log.Printf("Dial %s pressed!", di)
+			slog.Info("Dial pressed", "dial", di)
 			duration := di.WaitForRelease()
-			log.Printf("Dial %s released after %v", di, duration)
+			slog.Info("Dial released", "dial", di, "duration", duration)
 			return nil
 		})
 	})
 
-	log.Println("Dials configured")
+	slog.Info("Dials configured")
 }
 
 func setupTouchStrip(device *streamdeck.Device) {
 	if !device.GetTouchStripSupported() {
-		log.Println("No touch strip on this device")
+		slog.Info("No touch strip on this device")
 		return
 	}
 
 	// Set a gradient on the touch strip
 	rect, err := device.GetTouchStripImageRectangle()
 	if err != nil {
-		log.Printf("Failed to get touch strip size: %v", err)
+		slog.Error("Failed to get touch strip size", "err", err)
 		return
 	}
 
@@ -190,7 +193,7 @@ func setupTouchStrip(device *streamdeck.Device) {
 		if typ == streamdeck.TOUCH_STRIP_TOUCH_TYPE_LONG {
 			touchType = "long"
 		}
-		log.Printf("Touch strip %s touch at (%d, %d)", touchType, p.X, p.Y)
+		slog.Info("Touch strip touch", "type", touchType, "point", p)
 		return nil
 	})
 
@@ -200,11 +203,11 @@ func setupTouchStrip(device *streamdeck.Device) {
 		if dest.X < origin.X {
 			direction = "left"
 		}
-		log.Printf("Touch strip swiped %s: (%d,%d) -> (%d,%d)", direction, origin.X, origin.Y, dest.X, dest.Y)
+		slog.Info("Touch strip swipe", "direction", direction, "from", origin, "to", dest)
 		return nil
 	})
 
-	log.Println("Touch strip configured with gradient")
+	slog.Info("Touch strip configured with gradient")
 }
 
 func createGradient(rect image.Rectangle, start, end color.RGBA) image.Image {
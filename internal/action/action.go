@@ -0,0 +1,223 @@
+// Package action provides the things a key can do—open an app or URL, run a
+// shell command, send a keystroke, type text or a macro, POST to a URL, or
+// call a Home Assistant service—so modules can bind configured behavior to keys without each
+// implementing it. It also wraps the macOS pasteboard for modules that copy
+// text.
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// Action is a configured key action.
+type Action interface {
+	// Run performs the action, returning when it has finished.
+	Run(ctx context.Context) error
+	// Label is a short description for a key without a configured label,
+	// e.g. the app name or URL host. It may be empty.
+	Label() string
+}
+
+// New builds the action described by cfg. Home Assistant service calls use
+// the server and token from appCfg.
+func New(cfg config.Action, appCfg *config.Config) (Action, error) {
+	switch {
+	case cfg.App != "":
+		return App(cfg.App), nil
+	case cfg.URL != "":
+		return OpenURL(cfg.URL), nil
+	case cfg.Command != "":
+		return Shell(cfg.Command), nil
+	case cfg.Keystroke != "":
+		script, err := keystrokeScript(cfg.Keystroke)
+		if err != nil {
+			return nil, err
+		}
+		return Keystroke{spec: cfg.Keystroke, script: script}, nil
+	case cfg.Type != "":
+		return Macro{{text: cfg.Type}}, nil
+	case len(cfg.Macro) > 0:
+		return newMacro(cfg.Macro)
+	case cfg.Post != nil:
+		if cfg.Post.URL == "" {
+			return nil, fmt.Errorf("post: url is required")
+		}
+		return Post(*cfg.Post), nil
+	case cfg.Service != "":
+		domain, service, ok := strings.Cut(cfg.Service, ".")
+		if !ok || domain == "" || service == "" {
+			return nil, fmt.Errorf("service %q: want domain.service, e.g. light.toggle", cfg.Service)
+		}
+		if appCfg == nil || appCfg.HomeAssistant.Server == "" || appCfg.HomeAssistant.Token == "" {
+			return nil, fmt.Errorf("service %q: Home Assistant server and token are not configured", cfg.Service)
+		}
+		return Service{
+			server:  strings.TrimSuffix(appCfg.HomeAssistant.Server, "/"),
+			token:   appCfg.HomeAssistant.Token,
+			domain:  domain,
+			service: service,
+			data:    cfg.Data,
+		}, nil
+	}
+	return nil, fmt.Errorf("no action configured")
+}
+
+// App opens an application by name.
+type App string
+
+// Run opens the app.
+func (a App) Run(ctx context.Context) error {
+	return runCommand(exec.CommandContext(ctx, "open", "-a", string(a)))
+}
+
+// Label returns the app name.
+func (a App) Label() string { return string(a) }
+
+// OpenURL opens a URL in its default handler.
+type OpenURL string
+
+// Run opens the URL.
+func (u OpenURL) Run(ctx context.Context) error {
+	return runCommand(exec.CommandContext(ctx, "open", string(u)))
+}
+
+// Label returns the URL's host.
+func (u OpenURL) Label() string { return urlHost(string(u)) }
+
+// Shell runs a command with /bin/sh -c.
+type Shell string
+
+// Run runs the command and waits for it to exit.
+func (s Shell) Run(ctx context.Context) error {
+	return runCommand(exec.CommandContext(ctx, "/bin/sh", "-c", string(s)))
+}
+
+// Label returns nothing; commands are too long to be useful labels.
+func (s Shell) Label() string { return "" }
+
+// Keystroke sends a key combination to the frontmost app.
+type Keystroke struct {
+	spec   string
+	script string
+}
+
+// Run sends the keystroke through System Events.
+func (k Keystroke) Run(ctx context.Context) error {
+	return runCommand(exec.CommandContext(ctx, "osascript", "-e", k.script))
+}
+
+// Label returns the keystroke spec, e.g. "cmd+shift+4".
+func (k Keystroke) Label() string { return k.spec }
+
+// postClient is shared by Post and Service actions; the action's context
+// bounds each request.
+var postClient = &http.Client{Timeout: 15 * time.Second}
+
+// Post sends an HTTP POST request.
+type Post config.Post
+
+// Run sends the request, failing on a non-2xx response.
+func (p Post) Run(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, strings.NewReader(p.Body))
+	if err != nil {
+		return err
+	}
+	contentType := p.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+	return doRequest(req)
+}
+
+// Label returns the URL's host.
+func (p Post) Label() string { return urlHost(p.URL) }
+
+// Service calls a Home Assistant service.
+type Service struct {
+	server  string
+	token   string
+	domain  string
+	service string
+	data    map[string]any
+}
+
+// Run calls the service.
+func (s Service) Run(ctx context.Context) error {
+	body, err := jsonBody(s.data)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/api/services/%s/%s", s.server, s.domain, s.service)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+	return doRequest(req)
+}
+
+// Label returns the service's entity ID if it targets one, otherwise the
+// service name, e.g. "light.toggle".
+func (s Service) Label() string {
+	if id, ok := s.data["entity_id"].(string); ok {
+		return id
+	}
+	return s.domain + "." + s.service
+}
+
+// runCommand runs cmd, including its output in the error if it fails.
+func runCommand(cmd *exec.Cmd) error {
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// doRequest sends req and discards the response, failing on a non-2xx status.
+func doRequest(req *http.Request) error {
+	resp, err := postClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+	return nil
+}
+
+// jsonBody marshals data for a request body; nil data means no body.
+func jsonBody(data map[string]any) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return body, nil
+}
+
+// urlHost returns the host part of a URL, e.g. "example.com" for
+// "https://example.com/path".
+func urlHost(u string) string {
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	}
+	return strings.SplitN(u, "/", 2)[0]
+}
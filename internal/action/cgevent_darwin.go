@@ -0,0 +1,107 @@
+package action
+
+import (
+	"time"
+	"unicode/utf16"
+
+	"github.com/ebitengine/purego"
+)
+
+// CoreGraphics type aliases.
+type (
+	cgEventRef       uintptr
+	cgEventSourceRef uintptr
+)
+
+const (
+	kCGEventSourceStateHIDSystemState = 1
+	kCGHIDEventTap                    = 0
+
+	// maxUnicodeChunk is how many UTF-16 units one keyboard event reliably
+	// carries.
+	maxUnicodeChunk = 20
+)
+
+// purego function bindings
+var (
+	cfRelease func(cf uintptr)
+
+	cgEventSourceCreate             func(stateID int32) cgEventSourceRef
+	cgEventCreateKeyboardEvent      func(source cgEventSourceRef, virtualKey uint16, keyDown bool) cgEventRef
+	cgEventKeyboardSetUnicodeString func(event cgEventRef, length uint64, unicodeString *uint16)
+	cgEventSetFlags                 func(event cgEventRef, flags uint64)
+	cgEventPost                     func(tap uint32, event cgEventRef)
+
+	axIsProcessTrusted func() bool
+)
+
+func init() {
+	cf, err := purego.Dlopen("/System/Library/Frameworks/CoreFoundation.framework/CoreFoundation", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		panic(err)
+	}
+	purego.RegisterLibFunc(&cfRelease, cf, "CFRelease")
+
+	cg, err := purego.Dlopen("/System/Library/Frameworks/CoreGraphics.framework/CoreGraphics", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		panic(err)
+	}
+	purego.RegisterLibFunc(&cgEventSourceCreate, cg, "CGEventSourceCreate")
+	purego.RegisterLibFunc(&cgEventCreateKeyboardEvent, cg, "CGEventCreateKeyboardEvent")
+	purego.RegisterLibFunc(&cgEventKeyboardSetUnicodeString, cg, "CGEventKeyboardSetUnicodeString")
+	purego.RegisterLibFunc(&cgEventSetFlags, cg, "CGEventSetFlags")
+	purego.RegisterLibFunc(&cgEventPost, cg, "CGEventPost")
+
+	as, err := purego.Dlopen("/System/Library/Frameworks/ApplicationServices.framework/ApplicationServices", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		panic(err)
+	}
+	purego.RegisterLibFunc(&axIsProcessTrusted, as, "AXIsProcessTrusted")
+}
+
+// accessibilityTrusted reports whether macOS lets this process post
+// keyboard events; without it they're silently dropped.
+func accessibilityTrusted() bool {
+	return axIsProcessTrusted()
+}
+
+// typeUnicode types runes as Unicode keyboard events, independent of the
+// keyboard layout.
+func typeUnicode(runes []rune) error {
+	units := utf16.Encode(runes)
+	for len(units) > 0 {
+		n := min(len(units), maxUnicodeChunk)
+		// Don't split a surrogate pair across events
+		if n < len(units) && utf16.IsSurrogate(rune(units[n-1])) {
+			n--
+		}
+		chunk := units[:n]
+		units = units[n:]
+
+		for _, down := range []bool{true, false} {
+			ev := cgEventCreateKeyboardEvent(0, 0, down)
+			cgEventKeyboardSetUnicodeString(ev, uint64(len(chunk)), &chunk[0])
+			cgEventPost(kCGHIDEventTap, ev)
+			cfRelease(uintptr(ev))
+		}
+		time.Sleep(keyInterval)
+	}
+	return nil
+}
+
+// pressChord presses and releases a key with the chord's modifiers held.
+func pressChord(c chord) error {
+	src := cgEventSourceCreate(kCGEventSourceStateHIDSystemState)
+	if src != 0 {
+		defer cfRelease(uintptr(src))
+	}
+
+	for _, down := range []bool{true, false} {
+		ev := cgEventCreateKeyboardEvent(src, c.code, down)
+		cgEventSetFlags(ev, c.flags)
+		cgEventPost(kCGHIDEventTap, ev)
+		cfRelease(uintptr(ev))
+	}
+	time.Sleep(keyInterval)
+	return nil
+}
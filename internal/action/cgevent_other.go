@@ -0,0 +1,11 @@
+//go:build !darwin
+
+package action
+
+import "errors"
+
+var errUnsupported = errors.New("typing requires macOS")
+
+func accessibilityTrusted() bool     { return true }
+func typeUnicode(runes []rune) error { return errUnsupported }
+func pressChord(c chord) error       { return errUnsupported }
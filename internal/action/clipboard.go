@@ -0,0 +1,19 @@
+package action
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// SetClipboard puts text on the macOS pasteboard.
+func SetClipboard(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// Clipboard returns the text on the macOS pasteboard.
+func Clipboard() (string, error) {
+	out, err := exec.Command("pbpaste").Output()
+	return string(out), err
+}
@@ -0,0 +1,117 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+)
+
+// modifierNames maps keystroke modifier spellings to AppleScript modifiers.
+var modifierNames = map[string]string{
+	"cmd":     "command down",
+	"command": "command down",
+	"shift":   "shift down",
+	"opt":     "option down",
+	"option":  "option down",
+	"alt":     "option down",
+	"ctrl":    "control down",
+	"control": "control down",
+}
+
+// modifierFlags maps keystroke modifier spellings to CGEvent flag masks.
+var modifierFlags = map[string]uint64{
+	"cmd":     1 << 20,
+	"command": 1 << 20,
+	"shift":   1 << 17,
+	"opt":     1 << 19,
+	"option":  1 << 19,
+	"alt":     1 << 19,
+	"ctrl":    1 << 18,
+	"control": 1 << 18,
+}
+
+// keyCodes maps named keys to macOS virtual key codes.
+var keyCodes = map[string]int{
+	"return": 36, "enter": 36, "tab": 48, "space": 49, "delete": 51,
+	"escape": 53, "esc": 53, "forwarddelete": 117,
+	"left": 123, "right": 124, "down": 125, "up": 126,
+	"home": 115, "end": 119, "pageup": 116, "pagedown": 121,
+	"f1": 122, "f2": 120, "f3": 99, "f4": 118, "f5": 96, "f6": 97,
+	"f7": 98, "f8": 100, "f9": 101, "f10": 109, "f11": 103, "f12": 111,
+}
+
+// charKeyCodes maps characters to their key codes on a US ANSI keyboard,
+// for chords; plain text is typed as Unicode instead.
+var charKeyCodes = map[rune]int{
+	'a': 0, 's': 1, 'd': 2, 'f': 3, 'h': 4, 'g': 5, 'z': 6, 'x': 7, 'c': 8,
+	'v': 9, 'b': 11, 'q': 12, 'w': 13, 'e': 14, 'r': 15, 'y': 16, 't': 17,
+	'1': 18, '2': 19, '3': 20, '4': 21, '6': 22, '5': 23, '=': 24, '9': 25,
+	'7': 26, '-': 27, '8': 28, '0': 29, ']': 30, 'o': 31, 'u': 32, '[': 33,
+	'i': 34, 'p': 35, 'l': 37, 'j': 38, '\'': 39, 'k': 40, ';': 41, '\\': 42,
+	',': 43, '/': 44, 'n': 45, 'm': 46, '.': 47, '`': 50,
+}
+
+// parseChord parses a key chord spec like "cmd+shift+4" or "ctrl+left" into
+// a key code and modifier flags.
+func parseChord(spec string) (chord, error) {
+	parts := strings.Split(spec, "+")
+	key := parts[len(parts)-1]
+	if key == "" {
+		return chord{}, fmt.Errorf("keys %q: missing key", spec)
+	}
+
+	var c chord
+	for _, p := range parts[:len(parts)-1] {
+		flag, ok := modifierFlags[strings.ToLower(strings.TrimSpace(p))]
+		if !ok {
+			return chord{}, fmt.Errorf("keys %q: unknown modifier %q", spec, p)
+		}
+		c.flags |= flag
+	}
+
+	if code, ok := keyCodes[strings.ToLower(key)]; ok {
+		c.code = uint16(code)
+	} else if r := []rune(strings.ToLower(key)); len(r) == 1 && hasCharKeyCode(r[0]) {
+		c.code = uint16(charKeyCodes[r[0]])
+	} else {
+		return chord{}, fmt.Errorf("keys %q: unknown key %q", spec, key)
+	}
+	return c, nil
+}
+
+func hasCharKeyCode(r rune) bool {
+	_, ok := charKeyCodes[r]
+	return ok
+}
+
+// keystrokeScript builds a System Events AppleScript for a keystroke spec
+// like "cmd+shift+4" or "ctrl+space".
+func keystrokeScript(spec string) (string, error) {
+	parts := strings.Split(spec, "+")
+	key := parts[len(parts)-1]
+	if key == "" {
+		return "", fmt.Errorf("keystroke %q: missing key", spec)
+	}
+
+	var mods []string
+	for _, p := range parts[:len(parts)-1] {
+		mod, ok := modifierNames[strings.ToLower(strings.TrimSpace(p))]
+		if !ok {
+			return "", fmt.Errorf("keystroke %q: unknown modifier %q", spec, p)
+		}
+		mods = append(mods, mod)
+	}
+
+	var action string
+	if code, ok := keyCodes[strings.ToLower(key)]; ok {
+		action = fmt.Sprintf("key code %d", code)
+	} else if len([]rune(key)) == 1 {
+		action = fmt.Sprintf("keystroke %q", key)
+	} else {
+		return "", fmt.Errorf("keystroke %q: unknown key %q", spec, key)
+	}
+
+	if len(mods) > 0 {
+		action += " using {" + strings.Join(mods, ", ") + "}"
+	}
+	return `tell application "System Events" to ` + action, nil
+}
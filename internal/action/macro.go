@@ -0,0 +1,112 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// keyInterval is the pause between synthesized key events; apps drop
+// keystrokes that arrive faster than they can process them.
+const keyInterval = 5 * time.Millisecond
+
+// Macro types text and presses key chords in the frontmost app by posting
+// keyboard events, which needs the Accessibility permission.
+type Macro []macroStep
+
+// macroStep is one parsed config.MacroStep.
+type macroStep struct {
+	text  string
+	chord *chord
+	delay time.Duration
+}
+
+// chord is a key press with modifiers held.
+type chord struct {
+	code  uint16
+	flags uint64
+}
+
+// newMacro parses macro steps, validating key chords up front.
+func newMacro(steps []config.MacroStep) (Macro, error) {
+	var m Macro
+	for i, s := range steps {
+		step := macroStep{text: s.Text, delay: time.Duration(s.Delay) * time.Millisecond}
+		if s.Keys != "" {
+			c, err := parseChord(s.Keys)
+			if err != nil {
+				return nil, fmt.Errorf("macro step %d: %w", i+1, err)
+			}
+			step.chord = &c
+		}
+		m = append(m, step)
+	}
+	return m, nil
+}
+
+// Run performs the macro's steps in order.
+func (m Macro) Run(ctx context.Context) error {
+	if !accessibilityTrusted() {
+		return fmt.Errorf("typing needs Accessibility permission (System Settings > Privacy & Security > Accessibility)")
+	}
+
+	for _, s := range m {
+		if s.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.delay):
+			}
+		}
+		if s.text != "" {
+			if err := typeText(ctx, expandText(s.text, time.Now())); err != nil {
+				return err
+			}
+		}
+		if s.chord != nil {
+			if err := pressChord(*s.chord); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Label returns the text of a macro that only types text.
+func (m Macro) Label() string {
+	if len(m) == 1 && m[0].chord == nil {
+		return m[0].text
+	}
+	return ""
+}
+
+// expandText replaces {date}, {time}, and {datetime} with now.
+func expandText(text string, now time.Time) string {
+	return strings.NewReplacer(
+		"{datetime}", now.Format("2006-01-02 15:04"),
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("15:04"),
+	).Replace(text)
+}
+
+// typeText types text a line at a time, pressing return for each newline
+// since apps handle a typed "\n" inconsistently.
+func typeText(ctx context.Context, text string) error {
+	for i, line := range strings.Split(text, "\n") {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if i > 0 {
+			if err := pressChord(chord{code: uint16(keyCodes["return"])}); err != nil {
+				return err
+			}
+		}
+		if err := typeUnicode([]rune(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
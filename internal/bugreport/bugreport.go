@@ -0,0 +1,254 @@
+// Package bugreport writes diagnostic bundles to attach to bug reports: the
+// recent log, every goroutine's stack, the config file with its secrets
+// stripped, and the connected decks. The daemon writes one when it panics
+// or the deck keeps failing, and 'belowdeck bugreport' writes one on demand.
+package bugreport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// logTail is how much of the end of the log file a bundle includes.
+	logTail = 1 << 20
+
+	// autoInterval is how soon after one automatic bundle another may be
+	// written, so a deck failing over and over doesn't fill the disk.
+	autoInterval = 10 * time.Minute
+)
+
+// redacted replaces secret values in the bundled config.
+const redacted = "REDACTED"
+
+// secretWords mark config keys whose values are stripped from a bundle.
+var secretWords = []string{"secret", "password", "passwd", "token", "api_key", "apikey", "credential", "authorization", "cookie", "bearer"}
+
+var (
+	autoMu   sync.Mutex
+	lastAuto time.Time
+)
+
+// Write writes a bundle explaining reason to a new temporary directory and
+// returns its path. devs are the decks to describe; firmware is only read
+// from ones that are open.
+func Write(reason string, devs []device.Device) (string, error) {
+	dir, err := os.MkdirTemp("", "belowdeck-bugreport-")
+	if err != nil {
+		return "", err
+	}
+	cfg, cfgErr := config.Load()
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"summary.txt", func(w io.Writer) error { return writeSummary(w, reason, cfgErr) }},
+		{"log.txt", func(w io.Writer) error { return writeLog(w, cfg) }},
+		{"goroutines.txt", func(w io.Writer) error { return pprof.Lookup("goroutine").WriteTo(w, 2) }},
+		{"config.yaml", writeConfig},
+		{"devices.txt", func(w io.Writer) error { return writeDevices(w, devs) }},
+	}
+	var errs []string
+	for _, f := range files {
+		if err := writeFile(filepath.Join(dir, f.name), f.write); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", f.name, err))
+		}
+	}
+	if len(errs) == len(files) {
+		return "", fmt.Errorf("writing bug report: %s", strings.Join(errs, "; "))
+	}
+	if len(errs) > 0 {
+		// A partial bundle still helps; note what's missing in it
+		os.WriteFile(filepath.Join(dir, "errors.txt"), []byte(strings.Join(errs, "\n")+"\n"), 0o644)
+	}
+	return dir, nil
+}
+
+// Auto writes a bundle as Write does, unless one was written automatically
+// in the last autoInterval, and prints its path to stderr. It returns the
+// path, or "" if none was written.
+func Auto(reason string, devs []device.Device) string {
+	autoMu.Lock()
+	if !lastAuto.IsZero() && time.Since(lastAuto) < autoInterval {
+		autoMu.Unlock()
+		return ""
+	}
+	lastAuto = time.Now()
+	autoMu.Unlock()
+
+	path, err := Write(reason, devs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "belowdeck: failed to write bug report: %v\n", err)
+		return ""
+	}
+	fmt.Fprintf(os.Stderr, "belowdeck: %s; bug report written to %s\n", reason, path)
+	return path
+}
+
+// writeFile creates path and fills it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeSummary writes why the bundle was made and what it was made on.
+func writeSummary(w io.Writer, reason string, cfgErr error) error {
+	fmt.Fprintf(w, "Reason: %s\n", reason)
+	fmt.Fprintf(w, "Time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(w, "Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(w, "Go: %s\n", runtime.Version())
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(w, "Version: %s\n", info.Main.Version)
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" || s.Key == "vcs.modified" {
+				fmt.Fprintf(w, "%s: %s\n", s.Key, s.Value)
+			}
+		}
+	}
+	fmt.Fprintf(w, "Config: %s\n", config.DefaultConfigPath())
+	if cfgErr != nil {
+		fmt.Fprintf(w, "Config error: %v\n", cfgErr)
+	}
+	return nil
+}
+
+// writeLog writes the lines this process has logged, then the end of the
+// log file if one is configured, since a daemon that crashed logged there.
+func writeLog(w io.Writer, cfg *config.Config) error {
+	if recent := logging.Recent(); len(recent) > 0 {
+		fmt.Fprintln(w, "=== This process ===")
+		w.Write(recent)
+	}
+	if cfg == nil {
+		return nil
+	}
+	path := logging.FilePath(cfg.Logging)
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err == nil && fi.Size() > logTail {
+		f.Seek(-logTail, io.SeekEnd)
+	}
+	fmt.Fprintf(w, "=== %s ===\n", path)
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// writeConfig writes the config file with the values of keys that look
+// like secrets replaced.
+func writeConfig(w io.Writer) error {
+	data, err := os.ReadFile(config.DefaultConfigPath())
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		// Unparseable, so there's no telling which values are secrets
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	stripSecrets(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// stripSecrets replaces the values of secret-looking keys under n, and every
+// value under a headers map, whose names say nothing about what they hold.
+func stripSecrets(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if key := n.Content[i].Value; strings.EqualFold(key, "headers") || isSecret(key) {
+				redactAll(n.Content[i+1])
+			}
+		}
+	}
+	for _, c := range n.Content {
+		stripSecrets(c)
+	}
+}
+
+// redactAll replaces every non-empty value under n, leaving map keys.
+func redactAll(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Value != "" {
+			n.Value, n.Tag, n.Style = redacted, "!!str", 0
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			redactAll(n.Content[i])
+		}
+	default:
+		for _, c := range n.Content {
+			redactAll(c)
+		}
+	}
+}
+
+// isSecret reports whether a config key's value looks like a secret.
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, w := range secretWords {
+		if strings.Contains(key, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDevices describes each deck in devs.
+func writeDevices(w io.Writer, devs []device.Device) error {
+	if len(devs) == 0 {
+		fmt.Fprintln(w, "No Stream Deck to describe")
+	}
+	for _, dev := range devs {
+		model := dev.GetModelName()
+		fmt.Fprintf(w, "%s\n", model)
+		fmt.Fprintf(w, "  Serial: %s\n", dev.GetSerialNumber())
+		if fw, err := dev.GetFirmwareVersion(); err != nil {
+			fmt.Fprintf(w, "  Firmware: unknown (%v)\n", err)
+		} else {
+			fmt.Fprintf(w, "  Firmware: %s\n", fw)
+			if warning := device.FirmwareWarning(model, fw); warning != "" {
+				fmt.Fprintf(w, "  WARNING: %s\n", warning)
+			}
+		}
+		if path := dev.GetUSBPath(); path != "" {
+			fmt.Fprintf(w, "  USB path: %s\n", path)
+		}
+		fmt.Fprintf(w, "  Keys: %d, dials: %d\n", dev.GetKeyCount(), dev.GetDialCount())
+	}
+	return nil
+}
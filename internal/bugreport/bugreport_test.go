@@ -0,0 +1,48 @@
+package bugreport
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStripSecrets(t *testing.T) {
+	const config = `
+github:
+  username: octocat
+  token: ghp_abc123
+launcher:
+  buttons:
+    - label: Deploy
+      post:
+        url: https://ci.example.com/hooks/deploy
+        headers:
+          Authorization: Bearer s3cr3t
+          X-Api-Session: abc.def
+    - label: Lights
+      service: light.toggle
+      auth_cookie: session=xyz
+`
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(config), &doc); err != nil {
+		t.Fatal(err)
+	}
+	stripSecrets(&doc)
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+
+	for _, secret := range []string{"ghp_abc123", "s3cr3t", "abc.def", "session=xyz"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("bundle config still holds %q:\n%s", secret, got)
+		}
+	}
+	for _, kept := range []string{"octocat", "Authorization:", "X-Api-Session:", "https://ci.example.com/hooks/deploy", "light.toggle"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("bundle config lost %q:\n%s", kept, got)
+		}
+	}
+}
@@ -3,10 +3,16 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/phinze/belowdeck/internal/units"
 	"github.com/zalando/go-keyring"
 	"gopkg.in/yaml.v3"
 )
@@ -15,22 +21,115 @@ const (
 	// KeychainService is the macOS Keychain service name for belowdeck secrets.
 	KeychainService = "belowdeck"
 
-	// Keychain account names for each secret.
+	// Keychain account names for each secret. New ones also go in Secrets.
 	KeyOpenWeatherMapAPIKey = "openweathermap-api-key"
 	KeyHASSToken            = "hass-token"
+	KeyMQTTPassword         = "mqtt-password"
+	KeyGitHubToken          = "github-token"
+	KeyTrackerToken         = "tracker-token"
+	KeyMailPassword         = "mail-password"
+	KeyCIToken              = "ci-token"
+	KeyHueAppKey            = "hue-app-key"
 )
 
+// Secret describes a secret kept in the Keychain.
+type Secret struct {
+	Key  string // Keychain account name
+	Name string // what it is, for people
+}
+
+// Secrets are the Keychain secrets belowdeck reads, besides the TOTP
+// secrets stored per account under TOTPSecretKey.
+var Secrets = []Secret{
+	{KeyOpenWeatherMapAPIKey, "OpenWeatherMap API key"},
+	{KeyHASSToken, "Home Assistant token"},
+	{KeyMQTTPassword, "MQTT password"},
+	{KeyGitHubToken, "GitHub token"},
+	{KeyTrackerToken, "Issue tracker token"},
+	{KeyMailPassword, "Mail password"},
+	{KeyCIToken, "CI token"},
+	{KeyHueAppKey, "Hue bridge app key"},
+}
+
+// TOTPSecretKey returns the Keychain account name for a TOTP account's
+// secret.
+func TOTPSecretKey(label string) string {
+	return "totp-" + label
+}
+
 // Config holds the full application configuration, assembled from YAML + Keychain + env.
 type Config struct {
-	Weather      WeatherConfig      `yaml:"weather"`
+	// Version is the file's format (see CurrentVersion). Older files are
+	// upgraded as they load.
+	Version int `yaml:"version,omitempty"`
+	// fileVersion is the Version the file had before that
+	fileVersion int
+
+	// Units is imperial (default) or metric, for every module that shows
+	// temperatures or speeds.
+	Units string `yaml:"units,omitempty"`
+	// Brightness is the deck's brightness percentage on connect; default 80.
+	// Turning the layout's brightness dial saves the new level here.
+	Brightness int `yaml:"brightness,omitempty"`
+	// IconDir holds an icon pack: SVG or image files that launcher, folder,
+	// and script icons name without a path, and that replace built-in icons
+	// of the same name. Empty means ~/.config/belowdeck/icons.
+	IconDir string `yaml:"icon_dir,omitempty"`
+	// DisabledModules are left out of the layout without removing their
+	// entries, so they can be turned back on as they were. `belowdeck
+	// modules enable` and `disable` edit this list.
+	DisabledModules []string `yaml:"disabled_modules,omitempty"`
+
+	Weather       WeatherConfig       `yaml:"weather"`
 	HomeAssistant HomeAssistantConfig `yaml:"homeassistant"`
+	GitHub        GitHubConfig        `yaml:"github,omitempty"`
+	Tracker       TrackerConfig       `yaml:"tracker,omitempty"`
+	Mail          MailConfig          `yaml:"mail,omitempty"`
+	CI            CIConfig            `yaml:"ci,omitempty"`
+	Network       NetworkConfig       `yaml:"network,omitempty"`
+	KeyLight      KeyLightConfig      `yaml:"keylight,omitempty"`
+	Hue           HueConfig           `yaml:"hue,omitempty"`
+	MQTT          MQTTConfig          `yaml:"mqtt,omitempty"`
+	NowPlaying    NowPlayingConfig    `yaml:"nowplaying,omitempty"`
+	Audio         AudioConfig         `yaml:"audio,omitempty"`
+	Focus         FocusConfig         `yaml:"focus,omitempty"`
+	Launcher      LauncherConfig      `yaml:"launcher,omitempty"`
+	Clock         ClockConfig         `yaml:"clock,omitempty"`
+	Countdown     CountdownConfig     `yaml:"countdown,omitempty"`
+	Script        ScriptConfig        `yaml:"script,omitempty"`
+	Kiosk         KioskConfig         `yaml:"kiosk,omitempty"`
+	TOTP          TOTPConfig          `yaml:"totp,omitempty"`
+	Mirror        MirrorConfig        `yaml:"mirror,omitempty"`
+	Prompter      PrompterConfig      `yaml:"prompter,omitempty"`
+	Layout        LayoutConfig        `yaml:"layout,omitempty"`
+	Dials         DialsConfig         `yaml:"dials,omitempty"`
+	Logging       LoggingConfig       `yaml:"logging,omitempty"`
+	Metrics       MetricsConfig       `yaml:"metrics,omitempty"`
+	Events        EventsConfig        `yaml:"events,omitempty"`
+
+	// Profiles are named sets of settings, each laid over the rest of the
+	// file when it's in use: a layout for work and another for home, say.
+	// A profile is chosen by name (see UseProfile) or by its when
+	// conditions (see ProfileWhen).
+	Profiles yaml.Node `yaml:"profiles,omitempty"`
+	// Profile is the name of the profile in use, or "" for none.
+	Profile     string `yaml:"-"`
+	autoProfile bool
+	// invalid are modules whose config block failed validation; they're
+	// left out of the layout as if disabled.
+	invalid []string
 }
 
 // WeatherConfig holds weather module configuration.
 type WeatherConfig struct {
-	Lat    string `yaml:"lat"`
-	Lon    string `yaml:"lon"`
-	APIKey string `yaml:"-"` // secret, not in YAML
+	// Provider is openweathermap (default), open-meteo, or nws. Only
+	// openweathermap needs an API key.
+	Provider string `yaml:"provider,omitempty"`
+	Lat      string `yaml:"lat"`
+	Lon      string `yaml:"lon"`
+	// Interval is seconds between fetches; default 600, at least 120.
+	Interval int    `yaml:"interval,omitempty"`
+	APIKey   string `yaml:"-"` // secret, not in YAML
 }
 
 // HomeAssistantConfig holds Home Assistant module configuration.
@@ -38,7 +137,413 @@ type HomeAssistantConfig struct {
 	Server            string `yaml:"server"`
 	RingLightEntity   string `yaml:"ring_light_entity"`
 	OfficeLightEntity string `yaml:"office_light_entity"`
-	Token             string `yaml:"-"` // secret, not in YAML
+	// Entities are extra entities shown on keys; the entity domain
+	// (climate, media_player, light, scene, script, automation) selects how
+	// each is rendered and controlled.
+	Entities []string `yaml:"entities,omitempty"`
+	// Doorbells show a camera snapshot when a sensor, such as a doorbell
+	// button or motion sensor, turns on.
+	Doorbells []HADoorbell `yaml:"doorbells,omitempty"`
+	Token     string       `yaml:"-"` // secret, not in YAML
+}
+
+// HADoorbell is a binary sensor whose triggering shows a camera snapshot.
+type HADoorbell struct {
+	Sensor string `yaml:"sensor"` // e.g. binary_sensor.front_door_ding
+	Camera string `yaml:"camera"` // e.g. camera.front_door
+	// Seconds is how long the snapshot shows; 0 means 10.
+	Seconds int `yaml:"seconds,omitempty"`
+	// Show is where: strip (the default) or keys, a 2x2 block at the left
+	// of the keys.
+	Show string `yaml:"show,omitempty"`
+}
+
+// GitHubConfig holds GitHub module configuration.
+type GitHubConfig struct {
+	// Host is a GitHub Enterprise Server hostname; empty means github.com.
+	Host string `yaml:"host,omitempty"`
+	// Interval is seconds between fetches; default 120, at least 60.
+	Interval int `yaml:"interval,omitempty"`
+	// Teams are teams, as org/slug, whose review requests are shown with
+	// mine. Empty means any team I'm on.
+	Teams []string `yaml:"teams,omitempty"`
+	// Token is used instead of the gh CLI's token when set.
+	Token string `yaml:"-"` // secret, not in YAML
+}
+
+// TrackerConfig holds issue tracker module configuration.
+type TrackerConfig struct {
+	// Provider is jira or linear.
+	Provider string `yaml:"provider,omitempty"`
+	// URL is the Jira site, e.g. https://example.atlassian.net. Unused for Linear.
+	URL string `yaml:"url,omitempty"`
+	// Email is the Jira Cloud account the API token belongs to. Leave it
+	// empty to send the token as a Jira Server/Data Center personal access token.
+	Email string `yaml:"email,omitempty"`
+	// Project limits issues to a Jira project key or Linear team key.
+	Project string `yaml:"project,omitempty"`
+	// Filter replaces the default "assigned to me and not done" query: JQL
+	// for Jira, or an IssueFilter as JSON for Linear. Project is ignored when
+	// it's set.
+	Filter string `yaml:"filter,omitempty"`
+	// Interval is seconds between fetches; default 120, at least 30.
+	Interval int    `yaml:"interval,omitempty"`
+	Token    string `yaml:"-"` // secret, not in YAML
+}
+
+// MailConfig holds mail module configuration.
+type MailConfig struct {
+	// Provider is imap or gmail.
+	Provider string `yaml:"provider,omitempty"`
+	// Server is the IMAP server as host:port; the port defaults to 993 (TLS).
+	Server   string `yaml:"server,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	// ClientID and ClientSecret identify the OAuth client the Gmail refresh
+	// token was issued to.
+	ClientID     string `yaml:"client_id,omitempty"`
+	ClientSecret string `yaml:"client_secret,omitempty"`
+	// Mailboxes are counted one per key: IMAP mailbox names (default INBOX)
+	// or Gmail search queries (default in:inbox).
+	Mailboxes []string `yaml:"mailboxes,omitempty"`
+	// Open is the URL or application opened by pressing a key. Empty means
+	// the mailbox in Gmail's web UI, or the Mail app for IMAP.
+	Open string `yaml:"open,omitempty"`
+	// Interval is seconds between checks; default 60, at least 15.
+	Interval int `yaml:"interval,omitempty"`
+	// Password is the IMAP password (an app password for Gmail over IMAP)
+	// or the Gmail API OAuth refresh token.
+	Password string `yaml:"-"` // secret, not in YAML
+}
+
+// CIConfig holds CI wallboard module configuration.
+type CIConfig struct {
+	// Provider is buildkite, circleci, or jenkins.
+	Provider string `yaml:"provider,omitempty"`
+	// Org is the Buildkite organization slug.
+	Org string `yaml:"org,omitempty"`
+	// URL is the Jenkins server, e.g. https://ci.example.com.
+	URL string `yaml:"url,omitempty"`
+	// Username is the Jenkins user the API token belongs to.
+	Username string `yaml:"username,omitempty"`
+	// Pipelines are shown one per key.
+	Pipelines []CIPipeline `yaml:"pipelines,omitempty"`
+	// Interval is seconds between checks; default 30, at least 10.
+	Interval int    `yaml:"interval,omitempty"`
+	Token    string `yaml:"-"` // secret, not in YAML
+}
+
+// CIPipeline is one pipeline on the wallboard.
+type CIPipeline struct {
+	Label string `yaml:"label,omitempty"`
+	// Name is the Buildkite pipeline slug, CircleCI project slug (e.g.
+	// gh/org/repo), or Jenkins job path (e.g. folder/job).
+	Name string `yaml:"name"`
+	// Branch limits builds to one branch; empty means any. Unused for Jenkins,
+	// where each branch is its own job.
+	Branch string `yaml:"branch,omitempty"`
+}
+
+// NetworkConfig holds network monitor module configuration.
+type NetworkConfig struct {
+	// Hosts are pinged in order. Empty means 1.1.1.1 alone.
+	Hosts []NetworkHost `yaml:"hosts,omitempty"`
+	// Interval is seconds between pings; default 5.
+	Interval int `yaml:"interval,omitempty"`
+}
+
+// NetworkHost is a labeled host to ping, e.g. {Label: "Router", Host: "192.168.1.1"}.
+type NetworkHost struct {
+	Label string `yaml:"label,omitempty"`
+	Host  string `yaml:"host"`
+}
+
+// KeyLightConfig holds Elgato Key Light module configuration.
+type KeyLightConfig struct {
+	// Lights are controlled one per key and dial, in order. Empty means
+	// those found on the network, by name.
+	Lights []KeyLight `yaml:"lights,omitempty"`
+}
+
+// KeyLight is a light at a fixed address, e.g. {Label: "Left", Host: "192.168.1.40"}.
+type KeyLight struct {
+	Label string `yaml:"label,omitempty"` // default: the name set in Control Center
+	Host  string `yaml:"host"`            // host or host:port; the port defaults to 9123
+}
+
+// HueConfig holds Philips Hue module configuration.
+type HueConfig struct {
+	Bridge string `yaml:"bridge,omitempty"` // bridge IP or hostname
+	// Rooms are rooms or zones, by name, shown one per key and dimmed one
+	// per dial, in order. Empty means every room.
+	Rooms []string `yaml:"rooms,omitempty"`
+	// Scenes are shown one per key after the rooms, by name; "Room/Scene"
+	// picks one where several rooms have a scene of that name.
+	Scenes []string `yaml:"scenes,omitempty"`
+	AppKey string   `yaml:"-"` // secret from pairing, not in YAML
+}
+
+// MQTTConfig holds MQTT module configuration.
+type MQTTConfig struct {
+	Broker   string     `yaml:"broker,omitempty"` // e.g. tcp://localhost:1883
+	ClientID string     `yaml:"client_id,omitempty"`
+	Username string     `yaml:"username,omitempty"`
+	Tiles    []MQTTTile `yaml:"tiles,omitempty"`
+	Password string     `yaml:"-"` // secret, not in YAML
+}
+
+// MQTTTile displays one subscribed topic and optionally publishes on input.
+type MQTTTile struct {
+	Label string `yaml:"label"`
+	Topic string `yaml:"topic"`
+
+	// Path extracts a value from a JSON payload (dot-separated, e.g. "sensor.temp"
+	// or "readings.0.value"). Empty means the raw payload is displayed.
+	Path string `yaml:"path,omitempty"`
+
+	// Format is a printf format applied to numeric values (e.g. "%.1f°").
+	Format string `yaml:"format,omitempty"`
+
+	// Strip places the tile on the module's strip segment instead of a key.
+	Strip bool `yaml:"strip,omitempty"`
+
+	// PressTopic/PressPayload are published when the tile's key is pressed.
+	PressTopic   string `yaml:"press_topic,omitempty"`
+	PressPayload string `yaml:"press_payload,omitempty"`
+
+	// DialTopic receives DialPayload when a dial bound to this tile is rotated.
+	// "{delta}" in the payload is replaced with the rotation amount.
+	DialTopic   string `yaml:"dial_topic,omitempty"`
+	DialPayload string `yaml:"dial_payload,omitempty"`
+}
+
+// NowPlayingConfig holds nowplaying module configuration.
+type NowPlayingConfig struct {
+	// ArtKeys spreads the album art across the module's keys after the
+	// first two (play/pause and info), e.g. keys: [5, 6, 7, 8].
+	ArtKeys bool `yaml:"art_keys,omitempty"`
+
+	// Marquee scrolls titles and artists too long for the strip instead of
+	// truncating them.
+	Marquee bool `yaml:"marquee,omitempty"`
+
+	// Interval is seconds between asking what's playing; default 1, at
+	// most 10.
+	Interval int `yaml:"interval,omitempty"`
+}
+
+// AudioConfig holds audio module configuration.
+type AudioConfig struct {
+	// Devices limits the output device key to these devices (by name), cycled
+	// in this order. Empty means every output device.
+	Devices []string `yaml:"devices,omitempty"`
+}
+
+// FocusConfig holds Focus module configuration. macOS has no public API for
+// changing Focus, so each mode is switched by running a user-created Shortcut.
+type FocusConfig struct {
+	// Modes are the Focus modes offered in the picker; the first is the one a
+	// short press turns on.
+	Modes []FocusMode `yaml:"modes,omitempty"`
+	// OffShortcut is the Shortcut that turns Focus off.
+	OffShortcut string `yaml:"off_shortcut,omitempty"`
+}
+
+// FocusMode pairs a Focus mode name (as shown in System Settings) with the
+// Shortcut that turns it on.
+type FocusMode struct {
+	Name     string `yaml:"name"`
+	Shortcut string `yaml:"shortcut"`
+}
+
+// LauncherConfig holds launcher module configuration.
+type LauncherConfig struct {
+	Buttons []LauncherButton `yaml:"buttons,omitempty"`
+}
+
+// LauncherButton is one launcher key and the action it runs.
+type LauncherButton struct {
+	Label string `yaml:"label,omitempty"`
+	// Icon is an image file path (.svg, .png, .jpg) or an SF Symbol name.
+	Icon string `yaml:"icon,omitempty"`
+
+	Action `yaml:",inline"`
+	// Shift is run instead while the layout's modifier key is held.
+	Shift *Action `yaml:"shift,omitempty"`
+}
+
+// Action is something a key can do, shared by the launcher and any module
+// that lets keys be bound in config. Exactly one of App, URL, Command,
+// Keystroke, Type, Macro, Post, or Service should be set.
+type Action struct {
+	App       string `yaml:"app,omitempty"`       // application name, e.g. "Slack"
+	URL       string `yaml:"url,omitempty"`       // opened in the default handler
+	Command   string `yaml:"command,omitempty"`   // run with /bin/sh -c
+	Keystroke string `yaml:"keystroke,omitempty"` // e.g. "cmd+shift+4"
+	Post      *Post  `yaml:"post,omitempty"`      // HTTP POST, e.g. to a webhook
+
+	// Type is text typed into the frontmost app. {date}, {time}, and
+	// {datetime} expand to the current local date and time.
+	Type  string      `yaml:"type,omitempty"`
+	Macro []MacroStep `yaml:"macro,omitempty"` // steps typed in order
+
+	// Service is a Home Assistant service such as "light.toggle", called
+	// with Data on the homeassistant server.
+	Service string         `yaml:"service,omitempty"`
+	Data    map[string]any `yaml:"data,omitempty"` // e.g. {entity_id: light.desk}
+}
+
+// MacroStep is one step of a keyboard macro: text to type, a key chord to
+// press, or a pause.
+type MacroStep struct {
+	Text  string `yaml:"text,omitempty"`  // expanded like Action.Type
+	Keys  string `yaml:"keys,omitempty"`  // e.g. "cmd+shift+4" or "return"
+	Delay int    `yaml:"delay,omitempty"` // milliseconds
+}
+
+// Post is an HTTP POST request made by an action.
+type Post struct {
+	URL         string            `yaml:"url"`
+	Body        string            `yaml:"body,omitempty"`
+	ContentType string            `yaml:"content_type,omitempty"` // default application/json
+	Headers     map[string]string `yaml:"headers,omitempty"`
+}
+
+// ClockConfig holds clock module configuration.
+type ClockConfig struct {
+	// Zones are shown on the strip, left to right. Empty means local time only.
+	Zones []ClockZone `yaml:"zones,omitempty"`
+}
+
+// ClockZone is a labeled IANA time zone, e.g. {Label: "Berlin", TZ: "Europe/Berlin"}.
+type ClockZone struct {
+	Label string `yaml:"label"`
+	TZ    string `yaml:"tz"`
+}
+
+// CountdownConfig holds countdown module configuration.
+type CountdownConfig struct {
+	// Events are shown one per key, in order.
+	Events []CountdownEvent `yaml:"events,omitempty"`
+}
+
+// CountdownEvent is a date counted down to, e.g. {Label: "Launch", Date: "2026-03-01"}.
+type CountdownEvent struct {
+	Label string `yaml:"label"`
+	// Date is YYYY-MM-DD, or YYYY-MM-DD HH:MM for a time of day, in local time.
+	Date string `yaml:"date"`
+}
+
+// ScriptConfig holds script module configuration.
+type ScriptConfig struct {
+	// Dir holds the *.lua scripts, one per key in filename order. Empty means
+	// ~/.config/belowdeck/scripts.
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// KioskConfig holds kiosk module configuration.
+type KioskConfig struct {
+	// Dir is watched for files named after the place they're shown: 1, 2,
+	// ... for the module's keys in order, or strip, each with a .txt, .md,
+	// .png, or .jpg extension. Empty means ~/.config/belowdeck/kiosk.
+	Dir string `yaml:"dir,omitempty"`
+	// Pipe, if set, is the path of a named pipe to create and read lines
+	// from, each a place and its text, e.g. "2: deploying".
+	Pipe string `yaml:"pipe,omitempty"`
+}
+
+// TOTPConfig holds TOTP module configuration.
+type TOTPConfig struct {
+	// Accounts are shown one per key, in order.
+	Accounts []TOTPAccount `yaml:"accounts,omitempty"`
+}
+
+// TOTPAccount is an account to show one-time codes for. Its secret is
+// stored in Keychain by belowdeck setup, under TOTPSecretKey(Label).
+type TOTPAccount struct {
+	Label     string `yaml:"label"`
+	Digits    int    `yaml:"digits,omitempty"`    // default 6
+	Period    int    `yaml:"period,omitempty"`    // seconds; default 30
+	Algorithm string `yaml:"algorithm,omitempty"` // sha1 (default), sha256, or sha512
+	Secret    string `yaml:"-"`                   // base32 secret, not in YAML
+}
+
+// MirrorConfig holds screen mirror module configuration.
+type MirrorConfig struct {
+	// X, Y, Width, and Height are the screen rectangle to mirror, in points
+	// from the top left of the main display, as the screenshot crosshair
+	// (Cmd-Shift-4) shows them.
+	X      int `yaml:"x"`
+	Y      int `yaml:"y"`
+	Width  int `yaml:"width"`
+	Height int `yaml:"height"`
+	// FPS is how many times a second to capture; default 2.
+	FPS float64 `yaml:"fps,omitempty"`
+}
+
+// PrompterConfig holds teleprompter module configuration.
+type PrompterConfig struct {
+	// File is the text or markdown (.md) file to scroll through. It's
+	// reloaded when it changes.
+	File string `yaml:"file"`
+	// FontSize is the starting text size in points; default 18. The dial
+	// changes it while pressed in.
+	FontSize int `yaml:"font_size,omitempty"`
+}
+
+// LoggingConfig controls daemon log output.
+type LoggingConfig struct {
+	Level  string `yaml:"level,omitempty"`  // debug, info (default), warn, or error
+	Format string `yaml:"format,omitempty"` // text (default) or json
+
+	// File is a log file rotated by size; empty means stderr.
+	File       string `yaml:"file,omitempty"`
+	MaxSizeMB  int    `yaml:"max_size_mb,omitempty"` // default 10
+	MaxBackups int    `yaml:"max_backups,omitempty"` // default 3
+
+	// Modules overrides the level per module ID, e.g. {homeassistant: debug}.
+	Modules map[string]string `yaml:"modules,omitempty"`
+}
+
+// MetricsConfig controls the Prometheus metrics endpoint.
+type MetricsConfig struct {
+	// Listen is the address for /metrics, e.g. "127.0.0.1:9464". Empty
+	// disables the endpoint.
+	Listen string `yaml:"listen,omitempty"`
+}
+
+// DialsConfig tunes how dial rotation is turned into steps before modules
+// see it.
+type DialsConfig struct {
+	DialTuning `yaml:",inline"` // applies to every dial
+
+	// Overrides replace the tuning for individual dials, keyed by dial number.
+	Overrides map[int]DialTuning `yaml:"overrides,omitempty"`
+}
+
+// DialTuning shapes one dial's rotation. Zero values leave it unchanged.
+type DialTuning struct {
+	// Acceleration is how many steps each tick counts for when the dial is
+	// spun fast; slow turns stay at one step per tick.
+	Acceleration float64 `yaml:"acceleration,omitempty"`
+	// Scale multiplies every step, e.g. 0.5 for finer control.
+	Scale float64 `yaml:"scale,omitempty"`
+	// Detents groups this many ticks into one step, for coarse choices.
+	Detents int `yaml:"detents,omitempty"`
+}
+
+// ForDial returns the tuning for dial, applying any override.
+func (d DialsConfig) ForDial(dial int) DialTuning {
+	if t, ok := d.Overrides[dial]; ok {
+		return t
+	}
+	return d.DialTuning
+}
+
+// EventsConfig controls the WebSocket event stream.
+type EventsConfig struct {
+	// Listen is the address for the /events WebSocket and the /privacy
+	// control, e.g. "127.0.0.1:9465". Empty disables both.
+	Listen string `yaml:"listen,omitempty"`
 }
 
 // DefaultConfigDir returns the default config directory path.
@@ -47,6 +552,17 @@ func DefaultConfigDir() string {
 	return filepath.Join(home, ".config", "belowdeck")
 }
 
+// ExpandHome expands a leading "~/" in path, as config file paths may use,
+// to the user's home directory.
+func ExpandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
 // DefaultConfigPath returns the default config file path.
 func DefaultConfigPath() string {
 	// Allow override via environment variable (used by nix-generated config)
@@ -56,18 +572,51 @@ func DefaultConfigPath() string {
 	return filepath.Join(DefaultConfigDir(), "config.yaml")
 }
 
+// Exists reports whether the config file exists.
+func Exists() bool {
+	_, err := os.Stat(DefaultConfigPath())
+	return err == nil
+}
+
 // Load assembles configuration from YAML file + Keychain + environment variables.
 // Environment variables always take precedence. Returns a usable Config even if
 // some sources are missing (modules handle their own "not configured" state).
+// The profile in use, if any, is laid over the file's settings. If module
+// blocks fail validation, the Config comes back with those modules disabled
+// along with the error naming them.
 func Load() (*Config, error) {
-	cfg := &Config{}
+	return load(true)
+}
+
+// LoadBase is Load without applying a profile, for rewriting the file with
+// WriteConfigFile.
+func LoadBase() (*Config, error) {
+	return load(false)
+}
 
-	// 1. Try to load YAML config file
+func load(applyProfiles bool) (*Config, error) {
+	cfg := &Config{fileVersion: CurrentVersion}
+
+	// 1. Try to load YAML config file, upgrading an older format
 	configPath := DefaultConfigPath()
 	if data, err := os.ReadFile(configPath); err == nil {
-		if err := yaml.Unmarshal(data, cfg); err != nil {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
 			return nil, fmt.Errorf("parsing %s: %w", configPath, err)
 		}
+		if doc.Kind != 0 && doc.Content[0].Tag != "!!null" {
+			if cfg.fileVersion, err = migrate(&doc); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+			}
+			if applyProfiles {
+				if cfg.Profile, cfg.autoProfile, err = applyProfile(doc.Content[0]); err != nil {
+					return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+				}
+			}
+			if err := doc.Decode(cfg); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+			}
+		}
 	}
 
 	// 2. Layer in Keychain secrets (ignore errors — Keychain may not be populated)
@@ -77,11 +626,73 @@ func Load() (*Config, error) {
 	if token, err := keyring.Get(KeychainService, KeyHASSToken); err == nil {
 		cfg.HomeAssistant.Token = token
 	}
+	if password, err := keyring.Get(KeychainService, KeyMQTTPassword); err == nil {
+		cfg.MQTT.Password = password
+	}
+	if token, err := keyring.Get(KeychainService, KeyGitHubToken); err == nil {
+		cfg.GitHub.Token = token
+	}
+	if token, err := keyring.Get(KeychainService, KeyTrackerToken); err == nil {
+		cfg.Tracker.Token = token
+	}
+	if password, err := keyring.Get(KeychainService, KeyMailPassword); err == nil {
+		cfg.Mail.Password = password
+	}
+	if token, err := keyring.Get(KeychainService, KeyCIToken); err == nil {
+		cfg.CI.Token = token
+	}
+	if key, err := keyring.Get(KeychainService, KeyHueAppKey); err == nil {
+		cfg.Hue.AppKey = key
+	}
+	for i, a := range cfg.TOTP.Accounts {
+		if secret, err := keyring.Get(KeychainService, TOTPSecretKey(a.Label)); err == nil {
+			cfg.TOTP.Accounts[i].Secret = secret
+		}
+	}
 
 	// 3. Environment variables override everything
 	if v := os.Getenv("OPENWEATHERMAP_API_KEY"); v != "" {
 		cfg.Weather.APIKey = v
 	}
+	if v := os.Getenv("GITHUB_TOKEN"); v != "" {
+		cfg.GitHub.Token = v
+	}
+	if v := os.Getenv("GITHUB_HOST"); v != "" {
+		cfg.GitHub.Host = v
+	}
+	if v := os.Getenv("TRACKER_PROVIDER"); v != "" {
+		cfg.Tracker.Provider = v
+	}
+	if v := os.Getenv("TRACKER_URL"); v != "" {
+		cfg.Tracker.URL = v
+	}
+	if v := os.Getenv("TRACKER_EMAIL"); v != "" {
+		cfg.Tracker.Email = v
+	}
+	if v := os.Getenv("TRACKER_TOKEN"); v != "" {
+		cfg.Tracker.Token = v
+	}
+	if v := os.Getenv("MAIL_PROVIDER"); v != "" {
+		cfg.Mail.Provider = v
+	}
+	if v := os.Getenv("MAIL_SERVER"); v != "" {
+		cfg.Mail.Server = v
+	}
+	if v := os.Getenv("MAIL_USERNAME"); v != "" {
+		cfg.Mail.Username = v
+	}
+	if v := os.Getenv("MAIL_PASSWORD"); v != "" {
+		cfg.Mail.Password = v
+	}
+	if v := os.Getenv("CI_PROVIDER"); v != "" {
+		cfg.CI.Provider = v
+	}
+	if v := os.Getenv("CI_TOKEN"); v != "" {
+		cfg.CI.Token = v
+	}
+	if v := os.Getenv("WEATHER_PROVIDER"); v != "" {
+		cfg.Weather.Provider = v
+	}
 	if v := os.Getenv("WEATHER_LAT"); v != "" {
 		cfg.Weather.Lat = v
 	}
@@ -100,10 +711,240 @@ func Load() (*Config, error) {
 	if v := os.Getenv("HASS_OFFICE_LIGHT_ENTITY"); v != "" {
 		cfg.HomeAssistant.OfficeLightEntity = v
 	}
+	if v := os.Getenv("HASS_ENTITIES"); v != "" {
+		cfg.HomeAssistant.Entities = SplitList(v)
+	}
+	if v := os.Getenv("HUE_BRIDGE"); v != "" {
+		cfg.Hue.Bridge = v
+	}
+	if v := os.Getenv("HUE_APP_KEY"); v != "" {
+		cfg.Hue.AppKey = v
+	}
+	if v := os.Getenv("MQTT_BROKER"); v != "" {
+		cfg.MQTT.Broker = v
+	}
+	if v := os.Getenv("MQTT_USERNAME"); v != "" {
+		cfg.MQTT.Username = v
+	}
+	if v := os.Getenv("MQTT_PASSWORD"); v != "" {
+		cfg.MQTT.Password = v
+	}
+
+	if v := os.Getenv("BELOWDECK_LOG_LEVEL"); v != "" {
+		cfg.Logging.Level = v
+	}
+	if v := os.Getenv("BELOWDECK_LOG_FORMAT"); v != "" {
+		cfg.Logging.Format = v
+	}
+	if v := os.Getenv("BELOWDECK_LOG_FILE"); v != "" {
+		cfg.Logging.File = v
+	}
+
+	if v := os.Getenv("BELOWDECK_METRICS_LISTEN"); v != "" {
+		cfg.Metrics.Listen = v
+	}
+	if v := os.Getenv("BELOWDECK_EVENTS_LISTEN"); v != "" {
+		cfg.Events.Listen = v
+	}
+	if v := os.Getenv("BELOWDECK_UNITS"); v != "" {
+		cfg.Units = v
+	}
+
+	if err := cfg.Layout.Validate(); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+	}
+	if _, err := units.Parse(cfg.Units); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+	}
+	// A bad module block disables that module, not the whole deck: the
+	// config comes back alongside the error
+	if err := cfg.validateModules(); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", configPath, err)
+	}
 
 	return cfg, nil
 }
 
+// UnitSystem returns the configured unit system, imperial if unset or invalid.
+func (c *Config) UnitSystem() units.System {
+	if c == nil {
+		return units.Imperial
+	}
+	s, err := units.Parse(c.Units)
+	if err != nil {
+		return units.Imperial
+	}
+	return s
+}
+
+// IconPack returns the icon pack directory.
+func (c *Config) IconPack() string {
+	if c == nil || c.IconDir == "" {
+		return filepath.Join(DefaultConfigDir(), "icons")
+	}
+	return c.IconDir
+}
+
+// DefaultBrightness is the deck brightness used when none is configured.
+const DefaultBrightness = 80
+
+// EffectiveBrightness returns the configured brightness clamped to 0-100,
+// or DefaultBrightness if unset. Safe to call on a nil Config.
+func (c *Config) EffectiveBrightness() byte {
+	if c == nil || c.Brightness == 0 {
+		return DefaultBrightness
+	}
+	return byte(max(0, min(c.Brightness, 100)))
+}
+
+// PollInterval returns how often module id fetches, as set by its block's
+// interval, or def if that's unset. Safe to call on a nil Config.
+func (c *Config) PollInterval(id string, def time.Duration) time.Duration {
+	if c == nil {
+		return def
+	}
+	if n := c.intervals()[id]; n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return def
+}
+
+// intervals returns the interval set in each module's block that has one.
+func (c *Config) intervals() map[string]int {
+	return map[string]int{
+		"weather":    c.Weather.Interval,
+		"github":     c.GitHub.Interval,
+		"tracker":    c.Tracker.Interval,
+		"mail":       c.Mail.Interval,
+		"ci":         c.CI.Interval,
+		"nowplaying": c.NowPlaying.Interval,
+	}
+}
+
+// ModuleDisabled reports whether module id is in DisabledModules or was
+// disabled for a bad config block. Safe to call on a nil Config.
+func (c *Config) ModuleDisabled(id string) bool {
+	return c != nil && (slices.Contains(c.DisabledModules, id) || slices.Contains(c.invalid, id))
+}
+
+// SaveBrightness sets brightness in the config file, leaving the rest of
+// the file, including comments, as it is.
+func SaveBrightness(perc int) error {
+	return saveTopLevel("brightness", func(*yaml.Node) (*yaml.Node, error) {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(perc)}, nil
+	})
+}
+
+// SetModuleDisabled adds module id to disabled_modules in the config file,
+// or takes it out, leaving the rest of the file as SaveBrightness does. It
+// works from the file alone, so a module whose settings keep the config
+// from loading can still be disabled.
+func SetModuleDisabled(id string, disabled bool) error {
+	return saveTopLevel("disabled_modules", func(old *yaml.Node) (*yaml.Node, error) {
+		var ids []string
+		if old != nil {
+			if err := old.Decode(&ids); err != nil {
+				return nil, fmt.Errorf("disabled_modules: %w", err)
+			}
+		}
+		ids = slices.DeleteFunc(ids, func(s string) bool { return s == id })
+		if disabled {
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, id := range ids {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: id})
+		}
+		return list, nil
+	})
+}
+
+// saveTopLevel replaces a top-level key of the config file with what update
+// returns for its current value (nil if unset), removing the key if that's
+// nil. The rest of the file, including comments, is left as it is.
+func saveTopLevel(key string, update func(old *yaml.Node) (*yaml.Node, error)) error {
+	path := DefaultConfigPath()
+	doc, err := readDoc(path)
+	if err != nil {
+		return err
+	}
+	root := doc.Content[0]
+
+	i := 0
+	for i < len(root.Content) && root.Content[i].Value != key {
+		i += 2
+	}
+	var old *yaml.Node
+	if i+1 < len(root.Content) {
+		old = root.Content[i+1]
+	}
+	value, err := update(old)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	switch {
+	case old == nil && value != nil:
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	case old != nil && value == nil:
+		root.Content = slices.Delete(root.Content, i, i+2)
+	case old != nil:
+		// Keep any comment on the old value
+		value.LineComment = old.LineComment
+		root.Content[i+1] = value
+	}
+	return writeDoc(path, doc)
+}
+
+// readDoc reads the config file at path as a YAML node tree, for editing
+// without losing comments. A missing or empty file reads as an empty
+// mapping.
+func readDoc(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if doc.Kind == 0 || doc.Content[0].Tag == "!!null" {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing %s: top level is not a mapping", path)
+	}
+	return &doc, nil
+}
+
+// writeDoc writes a node tree from readDoc back to path.
+func writeDoc(path string, doc *yaml.Node) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+	return os.WriteFile(path, out.Bytes(), 0o644)
+}
+
+// SplitList splits a comma-separated list, trimming whitespace and dropping empties.
+func SplitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 // WriteConfigFile writes the non-secret portion of config to the YAML file.
 func WriteConfigFile(cfg *Config) error {
 	dir := filepath.Dir(DefaultConfigPath())
@@ -111,6 +952,7 @@ func WriteConfigFile(cfg *Config) error {
 		return fmt.Errorf("creating config dir: %w", err)
 	}
 
+	cfg.Version = CurrentVersion
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("marshaling config: %w", err)
@@ -126,7 +968,16 @@ func SetKeychainSecret(account, value string) error {
 	return keyring.Set(KeychainService, account, value)
 }
 
+// ErrSecretNotFound is returned for a secret that isn't in the Keychain.
+var ErrSecretNotFound = keyring.ErrNotFound
+
 // GetKeychainSecret retrieves a secret from the macOS Keychain.
 func GetKeychainSecret(account string) (string, error) {
 	return keyring.Get(KeychainService, account)
 }
+
+// DeleteKeychainSecret removes a secret from the macOS Keychain, returning
+// ErrSecretNotFound if there was none.
+func DeleteKeychainSecret(account string) error {
+	return keyring.Delete(KeychainService, account)
+}
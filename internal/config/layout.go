@@ -0,0 +1,400 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// LayoutConfig assigns deck resources (keys, strip region, dials) to modules.
+// Modules not listed are not started.
+type LayoutConfig struct {
+	Modules []ModuleLayout `yaml:"modules"`
+	// Folders are keys that swap the deck's keys for a nested page.
+	Folders []FolderLayout `yaml:"folders,omitempty"`
+	// Modifier is a key that, while held, switches other keys and dials to
+	// their alternate actions. It's the same key on every page.
+	Modifier int `yaml:"modifier,omitempty"`
+	// Feedback is drawn on a key the moment it's pressed, before its module
+	// redraws: flash, invert, scale, or none (default).
+	Feedback string `yaml:"feedback,omitempty"`
+	// BrightnessDial adjusts the deck's brightness.
+	BrightnessDial *DialBinding `yaml:"brightness_dial,omitempty"`
+	// Gestures are deck-wide gestures, handled before any module sees them.
+	Gestures GestureBindings `yaml:"gestures,omitempty"`
+	// Lock sets when the deck locks itself and how it's unlocked.
+	Lock LockConfig `yaml:"lock,omitempty"`
+	// Privacy is a key that turns privacy mode on and off, hiding the
+	// text of modules with redact set. It's the same key on every page.
+	Privacy int `yaml:"privacy,omitempty"`
+	// Screensaver dims the deck while it's untouched.
+	Screensaver ScreensaverConfig `yaml:"screensaver,omitempty"`
+}
+
+// LockConfig sets when the deck locks against input, besides the lock
+// gesture and the lock command, and how it's unlocked.
+type LockConfig struct {
+	// Schedule locks the deck while the time is in any of its windows.
+	Schedule []Schedule `yaml:"schedule,omitempty"`
+	// Unlock is keys to press in order to unlock, e.g. [1, 4, 8]. Without
+	// it, the lock gesture unlocks the deck too.
+	Unlock []int `yaml:"unlock,omitempty"`
+}
+
+// Locked reports whether t falls in one of the lock schedule's windows.
+func (l LockConfig) Locked(t time.Time) bool {
+	return slices.ContainsFunc(l.Schedule, func(s Schedule) bool { return s.Active(t) })
+}
+
+// ScreensaverConfig dims the deck once it's gone untouched for a while.
+// The first touch after wakes it without doing anything else.
+type ScreensaverConfig struct {
+	// Minutes untouched before the screensaver starts; 0 disables it.
+	Minutes int `yaml:"minutes,omitempty"`
+	// Strip is the touch strip's brightness percentage while it runs; 0
+	// turns the strip off.
+	Strip int `yaml:"strip,omitempty"`
+	// Keys stay lit while it runs, such as a clock or a doorbell; the rest
+	// go dark.
+	Keys []int `yaml:"keys,omitempty"`
+}
+
+// GestureBindings binds deck-wide gestures.
+type GestureBindings struct {
+	// SwipePages switches pages when the strip is flicked: left for the
+	// next page, right for the previous one.
+	SwipePages bool `yaml:"swipe_pages,omitempty"`
+	// Lock is two keys that, pressed together, lock the deck against input
+	// until they're pressed together again, e.g. [1, 8].
+	Lock []int `yaml:"lock,omitempty"`
+}
+
+// DialBinding binds a dial to a deck-wide control.
+type DialBinding struct {
+	Dial int `yaml:"dial"`
+	// Shift binds the dial only while the modifier key is held, leaving it
+	// to its module otherwise.
+	Shift bool `yaml:"shift,omitempty"`
+}
+
+// FolderLayout is a key that opens a page of its own modules, Elgato-style.
+// Folder pages hold keys only; dials and the strip keep working as laid out
+// on the root page.
+type FolderLayout struct {
+	Key   int    `yaml:"key"`             // key on the parent page that opens the folder
+	Label string `yaml:"label,omitempty"` // shown on the folder key
+	// Icon is an image file path or an SF Symbol name; default folder.fill.
+	Icon string `yaml:"icon,omitempty"`
+	// Back is the key on the folder's page that returns to the parent; default 1.
+	Back int `yaml:"back,omitempty"`
+	// Apps open the folder while one of them is the frontmost app, by
+	// name or bundle ID, e.g. [OBS, us.zoom.xos]. macOS only.
+	Apps []string `yaml:"apps,omitempty"`
+	// Schedule opens the folder while the time is in any of its windows.
+	Schedule []Schedule     `yaml:"schedule,omitempty"`
+	Modules  []ModuleLayout `yaml:"modules,omitempty"`
+	Folders  []FolderLayout `yaml:"folders,omitempty"` // nested folders
+}
+
+// Scheduled reports whether t falls in one of the folder's schedule
+// windows.
+func (f FolderLayout) Scheduled(t time.Time) bool {
+	return slices.ContainsFunc(f.Schedule, func(s Schedule) bool { return s.Active(t) })
+}
+
+// BackKey returns the folder's back key, defaulting to key 1.
+func (f FolderLayout) BackKey() int {
+	if f.Back == 0 {
+		return 1
+	}
+	return f.Back
+}
+
+// ModuleLayout is the resource allocation for a single module.
+// Keys and dials are 1-indexed to match the physical labels on the deck.
+type ModuleLayout struct {
+	ID    string       `yaml:"id"`
+	Keys  []int        `yaml:"keys,omitempty"`
+	Strip *StripLayout `yaml:"strip,omitempty"`
+	Dials []int        `yaml:"dials,omitempty"`
+
+	// Slots binds the module's named key slots to keys, e.g. github's
+	// {stats: 3, inbox: 4}. Slots not listed take the module's other keys
+	// in order.
+	Slots map[string]int `yaml:"slots,omitempty"`
+
+	// Buttons replaces launcher.buttons for a launcher entry, so each page
+	// can have its own launcher keys.
+	Buttons []LauncherButton `yaml:"buttons,omitempty"`
+
+	// Redact is how the module hides sensitive text, such as pull request
+	// titles and email subjects, while privacy mode is on: blur or
+	// generic. Empty leaves it showing.
+	Redact string `yaml:"redact,omitempty"`
+}
+
+// AllKeys returns the module's keys followed by any slot keys not among
+// them, in key order.
+func (m ModuleLayout) AllKeys() []int {
+	keys := slices.Clone(m.Keys)
+	var extra []int
+	for _, k := range m.Slots {
+		if !slices.Contains(keys, k) && !slices.Contains(extra, k) {
+			extra = append(extra, k)
+		}
+	}
+	slices.Sort(extra)
+	return append(keys, extra...)
+}
+
+// StripLayout is a horizontal segment of the touch strip.
+type StripLayout struct {
+	X     int `yaml:"x"`
+	Width int `yaml:"width"`
+}
+
+// DefaultLayout returns the built-in layout used when config.yaml has none.
+func DefaultLayout() LayoutConfig {
+	return LayoutConfig{
+		Modules: []ModuleLayout{
+			{ID: "nowplaying", Keys: []int{5, 6}, Strip: &StripLayout{X: 0, Width: 400}, Dials: []int{1, 2}},
+			{ID: "weather", Strip: &StripLayout{X: 400, Width: 400}},
+			{ID: "homeassistant", Keys: []int{1, 2, 7, 8}, Dials: []int{4, 3}},
+			{ID: "github", Keys: []int{3, 4}},
+		},
+	}
+}
+
+// EffectiveLayout returns the configured layout, or the default layout if
+// none is configured. Safe to call on a nil Config.
+func (c *Config) EffectiveLayout() LayoutConfig {
+	if c == nil || (len(c.Layout.Modules) == 0 && len(c.Layout.Folders) == 0) {
+		return DefaultLayout()
+	}
+	return c.Layout
+}
+
+// AllModules returns the root page's modules followed by every folder's,
+// depth first.
+func (l LayoutConfig) AllModules() []ModuleLayout {
+	mods := append([]ModuleLayout(nil), l.Modules...)
+	var walk func([]FolderLayout)
+	walk = func(folders []FolderLayout) {
+		for _, f := range folders {
+			mods = append(mods, f.Modules...)
+			walk(f.Folders)
+		}
+	}
+	walk(l.Folders)
+	return mods
+}
+
+// Validate checks that key and dial numbers are in range for the largest
+// supported device (32 keys on an XL, 4 dials and an 800px strip on a Plus).
+// Resources the connected device lacks are dropped at registration time.
+func (l LayoutConfig) Validate() error {
+	if err := validateModules(l.Modules); err != nil {
+		return err
+	}
+	if err := validateFolders(l.Folders, ""); err != nil {
+		return err
+	}
+	if b := l.BrightnessDial; b != nil {
+		if b.Dial < 1 || b.Dial > 4 {
+			return fmt.Errorf("layout: brightness dial %d out of range 1-4", b.Dial)
+		}
+		if b.Shift && l.Modifier == 0 {
+			return fmt.Errorf("layout: brightness dial uses shift but no modifier key is set")
+		}
+		for _, m := range l.Modules {
+			if !b.Shift && slices.Contains(m.Dials, b.Dial) {
+				return fmt.Errorf("layout: module %s: dial %d is the brightness dial", m.ID, b.Dial)
+			}
+		}
+	}
+	switch l.Feedback {
+	case "", "none", "flash", "invert", "scale":
+	default:
+		return fmt.Errorf("layout: unknown feedback %q, want flash, invert, scale, or none", l.Feedback)
+	}
+	if err := l.validateLock(); err != nil {
+		return err
+	}
+	if err := l.validateLockConfig(); err != nil {
+		return err
+	}
+	if err := l.validateScreensaver(); err != nil {
+		return err
+	}
+	if err := l.validateDeckKey("modifier key", l.Modifier); err != nil {
+		return err
+	}
+	if l.Privacy != 0 && l.Privacy == l.Modifier {
+		return fmt.Errorf("layout: privacy key %d is the modifier key", l.Privacy)
+	}
+	return l.validateDeckKey("privacy key", l.Privacy)
+}
+
+// validateLock checks that the lock chord is two different keys in range,
+// neither of them the modifier key.
+func (l LayoutConfig) validateLock() error {
+	lock := l.Gestures.Lock
+	if len(lock) == 0 {
+		return nil
+	}
+	if len(lock) != 2 || lock[0] == lock[1] {
+		return fmt.Errorf("layout: lock gesture needs two different keys, got %v", lock)
+	}
+	for _, k := range lock {
+		if k < 1 || k > 32 {
+			return fmt.Errorf("layout: lock key %d out of range 1-32", k)
+		}
+		if k == l.Modifier {
+			return fmt.Errorf("layout: lock key %d is the modifier key", k)
+		}
+		if k == l.Privacy {
+			return fmt.Errorf("layout: lock key %d is the privacy key", k)
+		}
+	}
+	return nil
+}
+
+// validateLockConfig checks the lock schedule and that the unlock keys are
+// in range.
+func (l LayoutConfig) validateLockConfig() error {
+	for i, s := range l.Lock.Schedule {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("layout: lock: schedule %d: %w", i+1, err)
+		}
+	}
+	for _, k := range l.Lock.Unlock {
+		if k < 1 || k > 32 {
+			return fmt.Errorf("layout: unlock key %d out of range 1-32", k)
+		}
+	}
+	return nil
+}
+
+// validateScreensaver checks the screensaver's timeout, strip brightness,
+// and keys.
+func (l LayoutConfig) validateScreensaver() error {
+	s := l.Screensaver
+	if s.Minutes < 0 {
+		return fmt.Errorf("layout: screensaver: minutes %d is negative", s.Minutes)
+	}
+	if s.Strip < 0 || s.Strip > 100 {
+		return fmt.Errorf("layout: screensaver: strip brightness %d out of range 0-100", s.Strip)
+	}
+	for _, k := range s.Keys {
+		if k < 1 || k > 32 {
+			return fmt.Errorf("layout: screensaver: key %d out of range 1-32", k)
+		}
+	}
+	return nil
+}
+
+// validateDeckKey checks that key, one of the deck-wide keys such as the
+// modifier key, is in range and not given to a module or folder. what names
+// it for error messages.
+func (l LayoutConfig) validateDeckKey(what string, key int) error {
+	if key == 0 {
+		return nil
+	}
+	if key < 1 || key > 32 {
+		return fmt.Errorf("layout: %s %d out of range 1-32", what, key)
+	}
+	for _, m := range l.AllModules() {
+		if slices.Contains(m.AllKeys(), key) {
+			return fmt.Errorf("layout: module %s: key %d is the %s", m.ID, key, what)
+		}
+	}
+	var walk func([]FolderLayout) error
+	walk = func(folders []FolderLayout) error {
+		for _, f := range folders {
+			if f.Key == key || f.BackKey() == key {
+				return fmt.Errorf("layout: folder %q: key %d is the %s", f.Label, key, what)
+			}
+			if err := walk(f.Folders); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(l.Folders)
+}
+
+// validateFolders checks folder keys and contents; path names the enclosing
+// folders for error messages.
+func validateFolders(folders []FolderLayout, path string) error {
+	for _, f := range folders {
+		name := path + "folder " + f.Label
+		if f.Label == "" {
+			name = fmt.Sprintf("%sfolder on key %d", path, f.Key)
+		}
+		if f.Key < 1 || f.Key > 32 {
+			return fmt.Errorf("layout: %s: key %d out of range 1-32", name, f.Key)
+		}
+		if f.BackKey() < 1 || f.BackKey() > 32 {
+			return fmt.Errorf("layout: %s: back key %d out of range 1-32", name, f.BackKey())
+		}
+		for _, m := range f.Modules {
+			if len(m.Dials) > 0 || m.Strip != nil {
+				return fmt.Errorf("layout: %s: module %s: folders hold keys only, not dials or strip segments", name, m.ID)
+			}
+			if slices.Contains(m.AllKeys(), f.BackKey()) {
+				return fmt.Errorf("layout: %s: module %s: key %d is the folder's back key", name, m.ID, f.BackKey())
+			}
+		}
+		for i, s := range f.Schedule {
+			if err := s.Validate(); err != nil {
+				return fmt.Errorf("layout: %s: schedule %d: %w", name, i+1, err)
+			}
+		}
+		if err := validateModules(f.Modules); err != nil {
+			return err
+		}
+		if err := validateFolders(f.Folders, name+": "); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateModules(modules []ModuleLayout) error {
+	for _, m := range modules {
+		if m.ID == "" {
+			return fmt.Errorf("layout: module entry missing id")
+		}
+		for _, k := range m.AllKeys() {
+			if k < 1 || k > 32 {
+				return fmt.Errorf("layout: module %s: key %d out of range 1-32", m.ID, k)
+			}
+		}
+		bound := make(map[int]string)
+		for _, name := range slices.Sorted(maps.Keys(m.Slots)) {
+			if name == "" {
+				return fmt.Errorf("layout: module %s: slot missing name", m.ID)
+			}
+			k := m.Slots[name]
+			if other, ok := bound[k]; ok {
+				return fmt.Errorf("layout: module %s: slots %s and %s both bound to key %d", m.ID, other, name, k)
+			}
+			bound[k] = name
+		}
+		for _, d := range m.Dials {
+			if d < 1 || d > 4 {
+				return fmt.Errorf("layout: module %s: dial %d out of range 1-4", m.ID, d)
+			}
+		}
+		if m.Strip != nil && (m.Strip.X < 0 || m.Strip.Width <= 0 || m.Strip.X+m.Strip.Width > 800) {
+			return fmt.Errorf("layout: module %s: strip segment x=%d width=%d outside 0-800", m.ID, m.Strip.X, m.Strip.Width)
+		}
+		switch m.Redact {
+		case "", "blur", "generic":
+		default:
+			return fmt.Errorf("layout: module %s: unknown redact %q, want blur or generic", m.ID, m.Redact)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config file format this build reads and writes.
+// A change that would misread older files bumps it and adds a migration.
+const CurrentVersion = 1
+
+// migrations upgrade a config file one version at a time: migrations[i]
+// turns a version i file into a version i+1 one. They edit the top-level
+// YAML mapping rather than a Config, so they can read settings that no
+// longer have a field, and so `belowdeck config migrate` keeps comments.
+var migrations = []func(root *yaml.Node) error{
+	// 0 to 1: files from before versioning are already in the version 1
+	// format, and only gain the version field.
+	func(*yaml.Node) error { return nil },
+}
+
+// migrate upgrades doc, a config file's node tree, to CurrentVersion in
+// place, returning the version the file had. A file without a version is
+// version 0.
+func migrate(doc *yaml.Node) (int, error) {
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return 0, fmt.Errorf("top level is not a mapping")
+	}
+
+	version := 0
+	i := 0
+	for i < len(root.Content) && root.Content[i].Value != "version" {
+		i += 2
+	}
+	if i+1 < len(root.Content) {
+		if err := root.Content[i+1].Decode(&version); err != nil {
+			return 0, fmt.Errorf("version: %w", err)
+		}
+	}
+	switch {
+	case version > CurrentVersion:
+		return 0, fmt.Errorf("version %d is newer than this belowdeck reads (%d); upgrade belowdeck", version, CurrentVersion)
+	case version < 0:
+		return 0, fmt.Errorf("invalid version %d", version)
+	case version == CurrentVersion:
+		return version, nil
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v](root); err != nil {
+			return 0, fmt.Errorf("upgrading from version %d: %w", v, err)
+		}
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(CurrentVersion)}
+	if i+1 < len(root.Content) {
+		root.Content[i+1] = value
+	} else {
+		// At the top, where it's easy to find, under any comment that
+		// heads the file. One set off by a blank line is the document's;
+		// otherwise it's the first key's, and moves up to stay on top.
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: "version"}
+		if len(root.Content) > 0 && doc.HeadComment == "" && root.HeadComment == "" {
+			key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+		}
+		root.Content = append([]*yaml.Node{key, value}, root.Content...)
+	}
+	return version, nil
+}
+
+// MigrateFile upgrades the config file to CurrentVersion, keeping a copy of
+// the original alongside it with a .bak suffix. It returns the version the
+// file had, and leaves a file that's already current alone.
+func MigrateFile() (int, error) {
+	path := DefaultConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := readDoc(path)
+	if err != nil {
+		return 0, err
+	}
+	from, err := migrate(doc)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if from == CurrentVersion {
+		return from, nil
+	}
+
+	if err := os.WriteFile(path+".bak", data, 0o644); err != nil {
+		return 0, fmt.Errorf("backing up %s: %w", path, err)
+	}
+	return from, writeDoc(path, doc)
+}
+
+// Migrated reports whether the config file is in an older format, upgraded
+// in memory as it loaded. `belowdeck config migrate` saves the upgrade.
+func (c *Config) Migrated() bool {
+	return c != nil && c.fileVersion < CurrentVersion
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/environment"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileWhen chooses a profile by where the computer is. Every condition
+// given must hold; each matches if any of its entries does.
+type ProfileWhen struct {
+	// SSID are Wi-Fi network names, matched exactly.
+	SSID []string `yaml:"ssid,omitempty"`
+	// Monitor are monitor names, matched case-insensitively against part
+	// of an attached monitor's name, e.g. "U2720Q".
+	Monitor []string `yaml:"monitor,omitempty"`
+}
+
+// profileName is the profile UseProfile chose.
+var profileName string
+
+// UseProfile makes Load apply the named profile instead of choosing one by
+// its when conditions. The BELOWDECK_PROFILE environment variable does the
+// same when UseProfile hasn't been called.
+func UseProfile(name string) {
+	profileName = name
+}
+
+// AutoProfile reports whether the profile in use, or the lack of one, was
+// chosen by when conditions, so loading again elsewhere may choose another.
+func (c *Config) AutoProfile() bool {
+	return c != nil && c.autoProfile
+}
+
+// ChooseProfile returns the name of the profile Load would use now,
+// without loading the rest of the config.
+func ChooseProfile() (string, error) {
+	path := DefaultConfigPath()
+	doc, err := readDoc(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := migrate(doc); err != nil {
+		return "", fmt.Errorf("parsing %s: %w", path, err)
+	}
+	name, _, err := applyProfile(doc.Content[0])
+	if err != nil {
+		return "", fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return name, nil
+}
+
+// applyProfile chooses a profile from the profiles mapping in root and
+// lays its settings over the rest of root, returning its name ("" for none)
+// and whether it was chosen by when conditions.
+func applyProfile(root *yaml.Node) (string, bool, error) {
+	profiles := mappingValue(root, "profiles")
+	if profiles == nil {
+		if name := chosenProfile(); name != "" {
+			return "", false, fmt.Errorf("profile %q: no profiles are defined", name)
+		}
+		return "", false, nil
+	}
+	if profiles.Kind != yaml.MappingNode {
+		return "", false, fmt.Errorf("profiles: want a mapping of names to settings")
+	}
+
+	var names []string
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		names = append(names, profiles.Content[i].Value)
+		if profiles.Content[i+1].Kind != yaml.MappingNode {
+			return "", false, fmt.Errorf("profiles: %s: want a mapping of settings", profiles.Content[i].Value)
+		}
+	}
+
+	if name := chosenProfile(); name != "" {
+		profile := mappingValue(profiles, name)
+		if profile == nil {
+			return "", false, fmt.Errorf("unknown profile %q (have %s)", name, strings.Join(names, ", "))
+		}
+		layOver(root, profile)
+		return name, false, nil
+	}
+
+	// The first profile whose conditions hold; the environment is looked
+	// up only if some profile asks about it
+	var ssid string
+	var monitors []string
+	var haveSSID, haveMonitors, auto bool
+	for i, name := range names {
+		profile := profiles.Content[2*i+1]
+		var when ProfileWhen
+		if w := mappingValue(profile, "when"); w == nil {
+			continue
+		} else if err := w.Decode(&when); err != nil {
+			return "", false, fmt.Errorf("profiles: %s: when: %w", name, err)
+		}
+		if len(when.SSID) == 0 && len(when.Monitor) == 0 {
+			continue
+		}
+		auto = true
+
+		if len(when.SSID) > 0 {
+			if !haveSSID {
+				ssid, haveSSID = environment.WiFiSSID(), true
+			}
+			if ssid == "" || !slices.Contains(when.SSID, ssid) {
+				continue
+			}
+		}
+		if len(when.Monitor) > 0 {
+			if !haveMonitors {
+				monitors, haveMonitors = environment.Monitors(), true
+			}
+			if !slices.ContainsFunc(when.Monitor, func(want string) bool {
+				return want != "" && slices.ContainsFunc(monitors, func(m string) bool {
+					return strings.Contains(strings.ToLower(m), strings.ToLower(want))
+				})
+			}) {
+				continue
+			}
+		}
+		layOver(root, profile)
+		return name, true, nil
+	}
+	return "", auto, nil
+}
+
+// chosenProfile returns the profile named by UseProfile or the
+// environment, or "" to choose by when conditions.
+func chosenProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	return os.Getenv("BELOWDECK_PROFILE")
+}
+
+// layOver lays a profile's settings over root, the config file's top-level
+// mapping.
+func layOver(root, profile *yaml.Node) {
+	settings := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(profile.Content); i += 2 {
+		if profile.Content[i].Value != "when" {
+			settings.Content = append(settings.Content, profile.Content[i], profile.Content[i+1])
+		}
+	}
+	merge(root, settings)
+}
+
+// merge lays the mapping src over the mapping dst: mappings under the same
+// key are merged in turn, and anything else in src replaces what dst has.
+func merge(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		old := mappingValue(dst, key.Value)
+		switch {
+		case old == nil:
+			dst.Content = append(dst.Content, key, value)
+		case old.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			merge(old, value)
+		default:
+			*old = *value
+		}
+	}
+}
+
+// mappingValue returns the value under key in the mapping m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
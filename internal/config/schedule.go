@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schedule is a weekly window of time, such as weekday work hours.
+type Schedule struct {
+	// Days are the days the window starts on: mon through sun, weekdays,
+	// or weekends; default every day.
+	Days []string `yaml:"days,omitempty"`
+	// From and To are local times of day, e.g. "09:00" and "17:30". A
+	// window whose To isn't after its From ends the next day.
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// scheduleDays maps the names Schedule.Days accepts to the days they mean.
+var scheduleDays = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+}
+
+// Validate checks the days and times.
+func (s Schedule) Validate() error {
+	for _, d := range s.Days {
+		if _, ok := scheduleDays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("unknown day %q, want mon through sun, weekdays, or weekends", d)
+		}
+	}
+	if _, err := clockMinute(s.From); err != nil {
+		return fmt.Errorf("from: %w", err)
+	}
+	if _, err := clockMinute(s.To); err != nil {
+		return fmt.Errorf("to: %w", err)
+	}
+	return nil
+}
+
+// Active reports whether t falls in the window. A schedule that doesn't
+// validate is never active.
+func (s Schedule) Active(t time.Time) bool {
+	from, err := clockMinute(s.From)
+	if err != nil {
+		return false
+	}
+	to, err := clockMinute(s.To)
+	if err != nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	if from < to {
+		return s.startsOn(t.Weekday()) && now >= from && now < to
+	}
+	// Overnight: started today, or started yesterday and not yet over
+	yesterday := (t.Weekday() + 6) % 7
+	return (s.startsOn(t.Weekday()) && now >= from) || (s.startsOn(yesterday) && now < to)
+}
+
+// startsOn reports whether the window starts on day.
+func (s Schedule) startsOn(day time.Weekday) bool {
+	if len(s.Days) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.Days, func(d string) bool {
+		return slices.Contains(scheduleDays[strings.ToLower(d)], day)
+	})
+}
+
+// clockMinute parses a time of day such as "17:30" into minutes past
+// midnight.
+func clockMinute(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
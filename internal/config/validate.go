@@ -0,0 +1,460 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validateModules checks the config block of every module in the layout,
+// so a typo is reported at load with the offending field named instead of
+// the module quietly misbehaving. Modules that fail are disabled, leaving
+// the rest of the deck running. Blocks are only checked for values that are
+// set: a module missing settings or secrets is disabled when it starts, as
+// the default layout includes modules most people never configure.
+// Disabled modules aren't checked, so disabling one gets past a bad block.
+func (c *Config) validateModules() error {
+	validators := map[string]func() error{
+		"weather":       c.Weather.Validate,
+		"homeassistant": c.HomeAssistant.Validate,
+		"github":        c.GitHub.Validate,
+		"tracker":       c.Tracker.Validate,
+		"mail":          c.Mail.Validate,
+		"ci":            c.CI.Validate,
+		"network":       c.Network.Validate,
+		"keylight":      c.KeyLight.Validate,
+		"hue":           c.Hue.Validate,
+		"mqtt":          c.MQTT.Validate,
+		"launcher":      c.Launcher.Validate,
+		"clock":         c.Clock.Validate,
+		"countdown":     c.Countdown.Validate,
+		"totp":          c.TOTP.Validate,
+		"mirror":        c.Mirror.Validate,
+		"prompter":      c.Prompter.Validate,
+		"nowplaying":    c.NowPlaying.Validate,
+	}
+
+	var errs []error
+	var checked, invalid []string
+	for _, ml := range c.EffectiveLayout().AllModules() {
+		if c.ModuleDisabled(ml.ID) {
+			continue
+		}
+		if validate, ok := validators[ml.ID]; ok && !slices.Contains(checked, ml.ID) {
+			checked = append(checked, ml.ID)
+			if err := validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w (module disabled)", ml.ID, err))
+				invalid = append(invalid, ml.ID)
+			}
+		}
+		for i, b := range ml.Buttons {
+			if err := b.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("layout: module %s: button %d: %w (module disabled)", ml.ID, i+1, err))
+				invalid = append(invalid, ml.ID)
+			}
+		}
+	}
+	c.invalid = append(c.invalid, invalid...)
+	return errors.Join(errs...)
+}
+
+// oneOf checks that v, if set, is one of want.
+func oneOf(field, v string, want ...string) error {
+	if v == "" || slices.Contains(want, v) {
+		return nil
+	}
+	return fmt.Errorf("unknown %s %q (want %s)", field, v, strings.Join(want, ", "))
+}
+
+// intervalBounds are the shortest and longest intervals, in seconds, a
+// module's block may set. The shortest keep a module's fetches within its
+// providers' free rate limits: OpenWeatherMap's 1000 calls a day, GitHub's
+// 5000 requests an hour across the several a refresh makes, and so on.
+var intervalBounds = map[string][2]int{
+	"weather":    {120, 3 * 60 * 60},
+	"github":     {60, 60 * 60},
+	"tracker":    {30, 60 * 60},
+	"mail":       {15, 60 * 60},
+	"ci":         {10, 60 * 60},
+	"nowplaying": {1, 10},
+}
+
+// checkInterval checks that module id's interval, if set, is within its
+// intervalBounds.
+func checkInterval(id string, n int) error {
+	bounds := intervalBounds[id]
+	switch {
+	case n == 0:
+		return nil
+	case n < bounds[0]:
+		return fmt.Errorf("interval %d is below the minimum of %d seconds", n, bounds[0])
+	case n > bounds[1]:
+		return fmt.Errorf("interval %d is above the maximum of %d seconds", n, bounds[1])
+	}
+	return nil
+}
+
+// checkURL checks that v, if set, is an absolute URL with one of schemes.
+func checkURL(field, v string, schemes ...string) error {
+	if v == "" {
+		return nil
+	}
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" || !slices.Contains(schemes, u.Scheme) {
+		return fmt.Errorf("%s %q should be a URL starting with %s://", field, v, strings.Join(schemes, ":// or "))
+	}
+	return nil
+}
+
+// Validate checks the provider, interval, and coordinates.
+func (w WeatherConfig) Validate() error {
+	if err := oneOf("provider", w.Provider, "openweathermap", "open-meteo", "nws"); err != nil {
+		return err
+	}
+	if err := checkInterval("weather", w.Interval); err != nil {
+		return err
+	}
+	if w.Lat == "" && w.Lon == "" {
+		return nil
+	}
+	_, _, err := w.Coordinates()
+	return err
+}
+
+// Coordinates parses the configured latitude and longitude.
+func (w WeatherConfig) Coordinates() (lat, lon float64, err error) {
+	if w.Lat == "" || w.Lon == "" {
+		return 0, 0, fmt.Errorf("lat and lon must both be set")
+	}
+	lat, err = strconv.ParseFloat(w.Lat, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("lat %q is not a latitude between -90 and 90", w.Lat)
+	}
+	lon, err = strconv.ParseFloat(w.Lon, 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("lon %q is not a longitude between -180 and 180", w.Lon)
+	}
+	return lat, lon, nil
+}
+
+// Validate checks the server URL and entity IDs.
+func (h HomeAssistantConfig) Validate() error {
+	if err := checkURL("server", h.Server, "http", "https"); err != nil {
+		return err
+	}
+	for _, e := range []struct{ field, id string }{
+		{"ring_light_entity", h.RingLightEntity},
+		{"office_light_entity", h.OfficeLightEntity},
+	} {
+		if e.id != "" && !isEntityID(e.id) {
+			return fmt.Errorf("%s %q is not an entity ID like light.desk", e.field, e.id)
+		}
+	}
+	for _, id := range h.Entities {
+		if !isEntityID(id) {
+			return fmt.Errorf("entities: %q is not an entity ID like climate.office", id)
+		}
+	}
+	for i, d := range h.Doorbells {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("doorbell %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the doorbell's entities, time, and placement.
+func (d HADoorbell) Validate() error {
+	if !isEntityID(d.Sensor) {
+		return fmt.Errorf("sensor %q is not an entity ID like binary_sensor.front_door_ding", d.Sensor)
+	}
+	if !isEntityID(d.Camera) {
+		return fmt.Errorf("camera %q is not an entity ID like camera.front_door", d.Camera)
+	}
+	if d.Seconds < 0 {
+		return fmt.Errorf("seconds %d is negative", d.Seconds)
+	}
+	return oneOf("show", d.Show, "strip", "keys")
+}
+
+// isEntityID reports whether id looks like a Home Assistant entity ID.
+func isEntityID(id string) bool {
+	domain, object, ok := strings.Cut(id, ".")
+	return ok && domain != "" && object != ""
+}
+
+// Validate checks that Host is a bare hostname, the interval, and teams.
+func (g GitHubConfig) Validate() error {
+	if strings.Contains(g.Host, "/") {
+		return fmt.Errorf("host %q should be a hostname like github.example.com, without a scheme or path", g.Host)
+	}
+	for _, team := range g.Teams {
+		org, slug, ok := strings.Cut(team, "/")
+		if !ok || org == "" || slug == "" || strings.ContainsAny(team, " :") || strings.Contains(slug, "/") {
+			return fmt.Errorf("teams: %q should be a team like my-org/my-team", team)
+		}
+	}
+	return checkInterval("github", g.Interval)
+}
+
+// Validate checks the provider, interval, and Jira URL.
+func (t TrackerConfig) Validate() error {
+	if err := oneOf("provider", t.Provider, "jira", "linear"); err != nil {
+		return err
+	}
+	if err := checkInterval("tracker", t.Interval); err != nil {
+		return err
+	}
+	return checkURL("url", t.URL, "http", "https")
+}
+
+// Validate checks the provider and interval.
+func (m MailConfig) Validate() error {
+	if err := oneOf("provider", m.Provider, "imap", "gmail"); err != nil {
+		return err
+	}
+	return checkInterval("mail", m.Interval)
+}
+
+// Validate checks the interval.
+func (n NowPlayingConfig) Validate() error {
+	return checkInterval("nowplaying", n.Interval)
+}
+
+// Validate checks the provider, interval, Jenkins URL, and pipelines.
+func (c CIConfig) Validate() error {
+	if err := oneOf("provider", c.Provider, "buildkite", "circleci", "jenkins"); err != nil {
+		return err
+	}
+	if err := checkInterval("ci", c.Interval); err != nil {
+		return err
+	}
+	if err := checkURL("url", c.URL, "http", "https"); err != nil {
+		return err
+	}
+	for i, p := range c.Pipelines {
+		if p.Name == "" {
+			return fmt.Errorf("pipeline %d: name is required", i+1)
+		}
+	}
+	return nil
+}
+
+// Validate checks the ping interval and hosts.
+func (n NetworkConfig) Validate() error {
+	if n.Interval < 0 {
+		return fmt.Errorf("interval %d is negative", n.Interval)
+	}
+	for i, h := range n.Hosts {
+		if h.Host == "" {
+			return fmt.Errorf("host %d: host is required", i+1)
+		}
+	}
+	return nil
+}
+
+// Validate checks that every light has a host.
+func (k KeyLightConfig) Validate() error {
+	for i, l := range k.Lights {
+		if l.Host == "" {
+			return fmt.Errorf("light %d: host is required", i+1)
+		}
+		if strings.Contains(l.Host, "/") {
+			return fmt.Errorf("light %d: host %q should be a host or host:port, without a scheme or path", i+1, l.Host)
+		}
+	}
+	return nil
+}
+
+// Validate checks that Bridge is a bare host and no scene name is empty.
+func (h HueConfig) Validate() error {
+	if strings.Contains(h.Bridge, "/") {
+		return fmt.Errorf("bridge %q should be an IP address or hostname, without a scheme or path", h.Bridge)
+	}
+	for i, s := range h.Scenes {
+		if strings.TrimSpace(s) == "" || strings.HasSuffix(s, "/") {
+			return fmt.Errorf("scene %d: name is required", i+1)
+		}
+	}
+	return nil
+}
+
+// Validate checks the broker URL and tiles.
+func (m MQTTConfig) Validate() error {
+	if u, err := url.Parse(m.Broker); m.Broker != "" && (err != nil || u.Scheme == "") {
+		return fmt.Errorf("broker %q needs a scheme, e.g. tcp://localhost:1883", m.Broker)
+	}
+	for i, t := range m.Tiles {
+		if t.Topic == "" {
+			return fmt.Errorf("tile %d: topic is required", i+1)
+		}
+	}
+	return nil
+}
+
+// Validate checks every button.
+func (l LauncherConfig) Validate() error {
+	for i, b := range l.Buttons {
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("button %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the button's action and its shift action.
+func (b LauncherButton) Validate() error {
+	if err := b.Action.Validate(); err != nil {
+		return err
+	}
+	if b.Shift != nil {
+		if err := b.Shift.Validate(); err != nil {
+			return fmt.Errorf("shift: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly one action is set, and the form of those
+// whose format is known without running them.
+func (a Action) Validate() error {
+	var set []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"app", a.App != ""},
+		{"url", a.URL != ""},
+		{"command", a.Command != ""},
+		{"keystroke", a.Keystroke != ""},
+		{"type", a.Type != ""},
+		{"macro", len(a.Macro) > 0},
+		{"post", a.Post != nil},
+		{"service", a.Service != ""},
+	} {
+		if f.set {
+			set = append(set, f.name)
+		}
+	}
+	switch len(set) {
+	case 0:
+		return fmt.Errorf("no action set (want one of app, url, command, keystroke, type, macro, post, or service)")
+	case 1:
+	default:
+		return fmt.Errorf("more than one action set (%s); want exactly one", strings.Join(set, ", "))
+	}
+
+	if a.Post != nil {
+		if a.Post.URL == "" {
+			return fmt.Errorf("post: url is required")
+		}
+		if err := checkURL("post: url", a.Post.URL, "http", "https"); err != nil {
+			return err
+		}
+	}
+	if a.Service != "" && !isEntityID(a.Service) {
+		return fmt.Errorf("service %q: want domain.service, e.g. light.toggle", a.Service)
+	}
+	for i, s := range a.Macro {
+		if s.Delay < 0 {
+			return fmt.Errorf("macro step %d: delay %d is negative", i+1, s.Delay)
+		}
+	}
+	return nil
+}
+
+// Validate checks that every zone is a known time zone.
+func (c ClockConfig) Validate() error {
+	for _, z := range c.Zones {
+		if _, err := z.Location(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Location loads the zone's time zone.
+func (z ClockZone) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(z.TZ)
+	if err != nil {
+		return nil, fmt.Errorf("zone %q is not an IANA time zone like Europe/Berlin", z.TZ)
+	}
+	return loc, nil
+}
+
+// Validate checks every event's date.
+func (c CountdownConfig) Validate() error {
+	for _, ev := range c.Events {
+		if _, _, err := ev.Time(); err != nil {
+			return fmt.Errorf("event %q: %w", ev.Label, err)
+		}
+	}
+	return nil
+}
+
+// Date layouts accepted in CountdownEvent.Date.
+const (
+	countdownDate     = "2006-01-02"
+	countdownDateTime = "2006-01-02 15:04"
+)
+
+// Time parses the event's date in local time, reporting whether it
+// includes a time of day.
+func (e CountdownEvent) Time() (t time.Time, hasTime bool, err error) {
+	if t, err := time.ParseInLocation(countdownDateTime, e.Date, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err = time.ParseInLocation(countdownDate, e.Date, time.Local)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("date: want YYYY-MM-DD or YYYY-MM-DD HH:MM, got %q", e.Date)
+	}
+	return t, false, nil
+}
+
+// Validate checks that every account has a unique label and codes it can
+// compute.
+func (c TOTPConfig) Validate() error {
+	var labels []string
+	for i, a := range c.Accounts {
+		if a.Label == "" {
+			return fmt.Errorf("account %d: label is required", i+1)
+		}
+		if slices.Contains(labels, a.Label) {
+			return fmt.Errorf("account %q: label is used more than once", a.Label)
+		}
+		labels = append(labels, a.Label)
+		if a.Digits != 0 && (a.Digits < 6 || a.Digits > 8) {
+			return fmt.Errorf("account %q: digits %d should be 6 to 8", a.Label, a.Digits)
+		}
+		if a.Period < 0 {
+			return fmt.Errorf("account %q: period %d is negative", a.Label, a.Period)
+		}
+		if !slices.Contains([]string{"", "sha1", "sha256", "sha512"}, strings.ToLower(a.Algorithm)) {
+			return fmt.Errorf("account %q: algorithm %q should be sha1, sha256, or sha512", a.Label, a.Algorithm)
+		}
+	}
+	return nil
+}
+
+// Validate checks the rectangle's size and the capture rate.
+func (c MirrorConfig) Validate() error {
+	if c.Width < 0 || c.Height < 0 {
+		return fmt.Errorf("size %dx%d is negative", c.Width, c.Height)
+	}
+	if c.FPS < 0 || c.FPS > 10 {
+		return fmt.Errorf("fps %g should be between 0 and 10", c.FPS)
+	}
+	return nil
+}
+
+// Validate checks the font size.
+func (c PrompterConfig) Validate() error {
+	if c.FontSize != 0 && (c.FontSize < 10 || c.FontSize > 40) {
+		return fmt.Errorf("font_size %d should be between 10 and 40", c.FontSize)
+	}
+	return nil
+}
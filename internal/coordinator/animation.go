@@ -0,0 +1,45 @@
+package coordinator
+
+import (
+	"image"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// minFrameDelay caps animations at 20 frames a second. Each frame redraws
+// every key, and faster doesn't look any smoother over USB.
+const minFrameDelay = 50 * time.Millisecond
+
+// keyFrame returns what to draw for a module's key image: the frame due at
+// now if it's a render.Animation, noting when the next one is due. Render
+// loop only.
+func (c *Coordinator) keyFrame(img image.Image, now time.Time) image.Image {
+	anim, ok := img.(*render.Animation)
+	if !ok || len(anim.Frames) == 0 {
+		return img
+	}
+	frame, next := anim.Frame(now)
+	if c.nextFrame == 0 || next < c.nextFrame {
+		c.nextFrame = next
+	}
+	return frame
+}
+
+// scheduleFrame arms the frame timer for the soonest frame change seen in
+// the pass just rendered, or stops it if nothing is animating. Render loop
+// only.
+func (c *Coordinator) scheduleFrame() {
+	c.frameTimer.Stop()
+	if c.nextFrame > 0 {
+		c.frameTimer.Reset(max(c.nextFrame, minFrameDelay))
+	}
+	c.nextFrame = 0
+}
+
+// stoppedTimer returns a timer that won't fire until Reset.
+func stoppedTimer() *time.Timer {
+	t := time.NewTimer(time.Hour)
+	t.Stop()
+	return t
+}
@@ -0,0 +1,64 @@
+package coordinator
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/environment"
+)
+
+// appCheckInterval is how often the frontmost app is checked for
+// SetAppPage.
+const appCheckInterval = time.Second
+
+// appPage is a page shown while one of apps is frontmost.
+type appPage struct {
+	page PageID
+	apps []string
+}
+
+// SetAppPage shows page while one of apps, given by name or bundle ID, is
+// the frontmost app, and goes back to the page before it when the app
+// leaves the front, unless the deck was switched to another page meanwhile.
+// The first page set for an app wins. Must be called before Start.
+func (c *Coordinator) SetAppPage(page PageID, apps []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.appPages = append(c.appPages, appPage{page: page, apps: apps})
+}
+
+// pageForApp returns the page set for app, or RootPage if there's none.
+func (c *Coordinator) pageForApp(app environment.App) PageID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, ap := range c.appPages {
+		if slices.ContainsFunc(ap.apps, app.Is) {
+			return ap.page
+		}
+	}
+	return RootPage
+}
+
+// watchApps follows the frontmost app with the pages set by SetAppPage
+// until ctx is done.
+func (c *Coordinator) watchApps(ctx context.Context) {
+	defer c.wg.Done()
+
+	var follow pageFollower
+	var front environment.App
+	ticker := time.NewTicker(appCheckInterval)
+	defer ticker.Stop()
+	for {
+		if app := environment.FrontmostApp(); app != front {
+			front = app
+			c.logger.Debug("Frontmost app changed", "app", app.Name, "bundle", app.BundleID)
+			c.follow(&follow, c.pageForApp(app))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package coordinator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// Brightness dial behavior.
+const (
+	brightnessStep      = 5 // percent per tick
+	brightnessMin       = 5 // keep the deck visible
+	brightnessOSDShown  = 1500 * time.Millisecond
+	brightnessSaveDelay = 2 * time.Second // save once the dial comes to rest
+)
+
+// colorBrightnessBar is the filled part of the brightness OSD bar.
+var colorBrightnessBar = color.RGBA{230, 200, 90, 255}
+
+var (
+	brightnessFaceOnce sync.Once
+	brightnessFace     font.Face
+)
+
+// brightnessDial is a dial bound to the deck's brightness.
+type brightnessDial struct {
+	dial      module.DialID
+	shiftOnly bool
+	level     int
+	save      func(perc int)
+
+	osdUntil  time.Time
+	saveTimer *time.Timer
+}
+
+// SetBrightnessDial binds dial to the deck's brightness, starting from
+// level. With shiftOnly, the dial controls brightness only while the
+// modifier key is held. save is called with the new level once the dial
+// comes to rest. Must be called before Start.
+func (c *Coordinator) SetBrightnessDial(dial module.DialID, shiftOnly bool, level byte, save func(perc int)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.brightness = &brightnessDial{dial: dial, shiftOnly: shiftOnly, level: int(level), save: save}
+}
+
+// isBrightnessDial reports whether a rotation of dial on layer adjusts
+// brightness.
+func (c *Coordinator) isBrightnessDial(dial module.DialID, layer module.Layer) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	b := c.brightness
+	return b != nil && b.dial == dial && (!b.shiftOnly || layer == module.LayerShift)
+}
+
+// adjustBrightness changes the brightness by delta ticks and shows the OSD.
+func (c *Coordinator) adjustBrightness(delta int8) {
+	c.mu.Lock()
+	b := c.brightness
+	b.level = max(brightnessMin, min(b.level+int(delta)*brightnessStep, 100))
+	level := b.level
+	b.osdUntil = time.Now().Add(brightnessOSDShown)
+	if b.saveTimer != nil {
+		b.saveTimer.Stop()
+	}
+	if b.save != nil {
+		b.saveTimer = time.AfterFunc(brightnessSaveDelay, func() { b.save(level) })
+	}
+	c.mu.Unlock()
+
+	if err := c.device.SetBrightness(byte(level)); err != nil {
+		c.logger.Warn("Failed to set brightness", "level", level, "err", err)
+	}
+	c.requestRender()
+	time.AfterFunc(brightnessOSDShown, c.requestRender)
+}
+
+// brightnessOSD returns the brightness level bar while the dial is being
+// turned, or nil.
+func (c *Coordinator) brightnessOSD() image.Image {
+	c.mu.RLock()
+	b := c.brightness
+	if b == nil || time.Now().After(b.osdUntil) {
+		c.mu.RUnlock()
+		return nil
+	}
+	level := b.level
+	c.mu.RUnlock()
+
+	brightnessFaceOnce.Do(func() {
+		var err error
+		if brightnessFace, err = render.NewFace(render.Bold, 22); err != nil {
+			c.logger.Error("Brightness OSD font", "err", err)
+		}
+	})
+
+	rect := c.stripRect
+	img := image.NewRGBA(rect)
+	draw.Draw(img, rect, &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+
+	midY := rect.Min.Y + rect.Dy()/2
+	bar := image.Rect(rect.Min.X+170, midY-8, rect.Max.X-110, midY+8)
+	draw.Draw(img, bar, &image.Uniform{render.ColorKeyBg}, image.Point{}, draw.Src)
+	fill := bar
+	fill.Max.X = bar.Min.X + bar.Dx()*level/100
+	draw.Draw(img, fill, &image.Uniform{colorBrightnessBar}, image.Point{}, draw.Src)
+
+	if brightnessFace != nil {
+		render.DrawText(img, "Brightness", rect.Min.X+24, midY+8, brightnessFace, render.ColorWhite)
+		render.DrawText(img, fmt.Sprintf("%d%%", level), bar.Max.X+24, midY+8, brightnessFace, render.ColorWhite)
+	}
+	return img
+}
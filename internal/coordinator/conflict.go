@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// ConflictError reports resources a module claimed that are already taken
+// by another module or a deck-wide control. The module isn't registered.
+type ConflictError struct {
+	Module    string
+	Page      PageID
+	Conflicts []string
+}
+
+func (e *ConflictError) Error() string {
+	where := ""
+	if e.Page != RootPage {
+		where = fmt.Sprintf(" on page %d", e.Page)
+	}
+	return fmt.Sprintf("registering %s%s: %s", e.Module, where, strings.Join(e.Conflicts, "; "))
+}
+
+// conflicts lists the resources in res already claimed on page. Keys are
+// owned per page; dials and the strip are shared by every page. c.mu must
+// be held.
+func (c *Coordinator) conflicts(page PageID, res module.Resources) []string {
+	var out []string
+	for _, k := range res.Keys {
+		if c.modifierKey != 0 && k == c.modifierKey {
+			out = append(out, fmt.Sprintf("key %d is the modifier key", k))
+		} else if c.privacyKey != 0 && k == c.privacyKey {
+			out = append(out, fmt.Sprintf("key %d is the privacy key", k))
+		} else if owner, ok := c.keyOwners[page][k]; ok {
+			out = append(out, fmt.Sprintf("key %d is taken by %s", k, owner.ID()))
+		}
+	}
+	for _, d := range res.Dials {
+		if b := c.brightness; b != nil && !b.shiftOnly && d == b.dial {
+			out = append(out, fmt.Sprintf("dial %d is the brightness dial", d))
+		} else if owner, ok := c.dialOwners[d]; ok {
+			out = append(out, fmt.Sprintf("dial %d is taken by %s", d, owner.ID()))
+		}
+	}
+	if res.HasStrip() {
+		for _, m := range c.modules {
+			if r := c.moduleResources[m]; r.HasStrip() && r.StripRect.Overlaps(res.StripRect) {
+				out = append(out, fmt.Sprintf("strip x=%d-%d overlaps %s's x=%d-%d",
+					res.StripRect.Min.X, res.StripRect.Max.X, m.ID(), r.StripRect.Min.X, r.StripRect.Max.X))
+			}
+		}
+	}
+	return out
+}
@@ -3,16 +3,29 @@ package coordinator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"image"
 	"image/draw"
-	"log"
+	"log/slog"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/phinze/belowdeck/internal/metrics"
 	"github.com/phinze/belowdeck/internal/module"
 )
 
+// Backoff bounds for retrying failed module initialization.
+const (
+	initRetryMin = 5 * time.Second
+	initRetryMax = 5 * time.Minute
+)
+
 // Coordinator manages the lifecycle of modules and routes events to them.
 type Coordinator struct {
 	device  device.Device
@@ -21,15 +34,73 @@ type Coordinator struct {
 	// Resource tracking
 	moduleResources map[module.Module]module.Resources
 
-	// Ownership maps for event routing
-	keyOwners  map[module.KeyID]module.Module
+	// Ownership maps for event routing; keys are owned per page
+	keyOwners  map[PageID]map[module.KeyID]module.Module
 	dialOwners map[module.DialID]module.Module
 
+	// Rotation processing per dial (see SetDialTuning)
+	dialTuning map[module.DialID]*dialProcessor
+
+	// Pages (see ShowPage)
+	modulePages  map[module.Module]PageID
+	pageCount    int    // highest allocated page
+	page         PageID // page whose keys are showing
+	renderedPage PageID // page last drawn by renderKeys
+
+	// Pages that follow the frontmost app and the time (see SetAppPage
+	// and SetSchedulePage)
+	appPages      []appPage
+	schedulePages []schedulePage
+
+	// Modifier key (see SetModifierKey)
+	modifierKey  module.KeyID // 0 if none
+	modifierHeld bool
+
+	// Privacy key (see SetPrivacyKey)
+	privacyKey module.KeyID // 0 if none
+
+	// Flicking the strip changes pages (see SetPageSwipe)
+	pageSwipe bool
+
+	// Deck lock (see SetLockChord, SetUnlockSequence, and SetLockSchedule)
+	lock lockState
+
+	// Screensaver (see SetScreensaver)
+	saver screensaver
+
+	// Press feedback (see SetPressFeedback)
+	feedback      PressFeedback
+	pressed       map[module.KeyID]time.Time   // when each key's feedback ends
+	lastKeyImages map[module.KeyID]image.Image // owned by the render loop
+	pressedNow    map[module.KeyID]bool        // keys showing feedback this pass; render loop only
+	feedbackShown map[module.KeyID]bool        // keys showing feedback last pass; render loop only
+
+	// Brightness dial (see SetBrightnessDial); nil if none
+	brightness *brightnessDial
+
+	// Background work on keys (see KeyTask)
+	tasks          map[module.KeyID]keyTask
+	animatingTasks bool
+
 	// Track modules that failed to initialize
 	failedModules map[module.Module]bool
 
+	// Modules taken out of service after a panic, with the panic value
+	degradedModules map[module.Module]string
+
+	// Each module's latest state, by ID, as saved for LoadHealth; nil
+	// unless SaveHealth was called
+	health map[string]ModuleHealth
+
 	// Strip compositing
-	stripRect image.Rectangle
+	stripRect  image.Rectangle
+	stripOwned bool // a notification, OSD, or overlay drew the strip last pass; render loop only
+	gestures   gestures
+	offline    bool // enough modules' fetches are failing to show offline; render loop only
+
+	// Strip regions waiting to be redrawn (see RefreshStrip)
+	stripDirty   image.Rectangle
+	stripRefresh chan struct{}
 
 	// Lifecycle
 	ctx    context.Context
@@ -39,8 +110,39 @@ type Coordinator struct {
 	// State tracking
 	mu sync.RWMutex
 
-	// Overlay state tracking
-	overlayWasActive bool
+	// Overlay showing; render loop only (see overlayState)
+	overlay      overlayState
+	overlayInput chan struct{}
+
+	// Transient notifications (see Notify)
+	notes notifications
+
+	// renderNow triggers an immediate render outside the ticker
+	renderNow chan struct{}
+
+	// Images waiting to be written to the device (see writeLoop), and
+	// fingerprints of the images written to each key and the strip, which
+	// aren't written again while unchanged (see unchanged); faces is render
+	// loop only
+	writes       *writeQueue
+	faces        map[module.KeyID]uint64
+	rewriteFaces atomic.Bool  // set when a write fails, so faces can't be trusted
+	failedWrites atomic.Int32 // consecutive failed writes
+
+	// Called when a module panics or the deck keeps failing (see OnFailure)
+	onFailure func(reason string)
+
+	// Render pass and device write in progress, or nil, and the end of the
+	// render and write loops (see watchRender)
+	rendering  atomic.Pointer[activity]
+	writing    atomic.Pointer[activity]
+	renderDone chan struct{}
+
+	// Animated keys (see keyFrame); render loop only
+	frameTimer *time.Timer   // fires when the next frame is due
+	nextFrame  time.Duration // soonest frame change seen this pass
+
+	logger *slog.Logger
 }
 
 // New creates a new Coordinator for the given device.
@@ -49,40 +151,113 @@ func New(dev device.Device) *Coordinator {
 		device:          dev,
 		modules:         make([]module.Module, 0),
 		moduleResources: make(map[module.Module]module.Resources),
-		keyOwners:       make(map[module.KeyID]module.Module),
+		keyOwners:       make(map[PageID]map[module.KeyID]module.Module),
 		dialOwners:      make(map[module.DialID]module.Module),
+		dialTuning:      make(map[module.DialID]*dialProcessor),
+		pressed:         make(map[module.KeyID]time.Time),
+		tasks:           make(map[module.KeyID]keyTask),
+		lastKeyImages:   make(map[module.KeyID]image.Image),
+		modulePages:     make(map[module.Module]PageID),
 		failedModules:   make(map[module.Module]bool),
+		degradedModules: make(map[module.Module]string),
+		overlay:         newOverlayState(),
+		overlayInput:    make(chan struct{}, 1),
+		renderNow:       make(chan struct{}, 1),
+		stripRefresh:    make(chan struct{}, 1),
+		writes:          newWriteQueue(),
+		faces:           make(map[module.KeyID]uint64),
+		frameTimer:      stoppedTimer(),
+		logger:          logging.For("coordinator"),
 	}
 }
 
-// RegisterModule registers a module with its allocated resources.
-// Must be called before Start.
+// RegisterModule registers a module with its allocated resources on the
+// root page. Must be called before Start.
 func (c *Coordinator) RegisterModule(m module.Module, res module.Resources) error {
+	return c.RegisterPageModule(RootPage, m, res)
+}
+
+// RegisterPageModule registers a module whose keys are on page, which must
+// come from NewPage. Must be called before Start. It returns a
+// *ConflictError, leaving m unregistered, if res claims a key, dial, or strip
+// segment that's already taken.
+func (c *Coordinator) RegisterPageModule(page PageID, m module.Module, res module.Resources) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if page < 0 || int(page) > c.pageCount {
+		return fmt.Errorf("registering %s: no such page %d", m.ID(), page)
+	}
+	if conflicts := c.conflicts(page, res); len(conflicts) > 0 {
+		return &ConflictError{Module: m.ID(), Page: page, Conflicts: conflicts}
+	}
+
 	// Store resources for this module
+	res.Device = c.capabilities()
 	c.moduleResources[m] = res
+	c.modulePages[m] = page
 
 	// Build ownership maps
+	if c.keyOwners[page] == nil {
+		c.keyOwners[page] = make(map[module.KeyID]module.Module)
+	}
 	for _, key := range res.Keys {
-		c.keyOwners[key] = m
+		c.keyOwners[page][key] = m
 	}
 	for _, dial := range res.Dials {
 		c.dialOwners[dial] = m
 	}
 
+	// Let the module post notifications
+	if ns, ok := m.(module.NotifierSetter); ok {
+		ns.SetNotifier(c)
+	}
+
 	// Track module
 	c.modules = append(c.modules, m)
 
 	return nil
 }
 
-// Start initializes all modules and begins the event/render loop.
+// Start initializes all modules and runs the event and render loops until
+// ctx is canceled or the device disconnects.
+//
+// After a disconnect, Start may be called again once the device is back
+// (see device.SwitchableDevice). Modules are only initialized the first
+// time and keep running in between, so their state, overlays, and cached
+// data survive the reconnect. Stop shuts them down.
 func (c *Coordinator) Start(ctx context.Context) error {
-	c.ctx, c.cancel = context.WithCancel(ctx)
+	if c.ctx == nil {
+		// Modules outlive any one connection, so only Stop cancels their context
+		c.ctx, c.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		restorePrivacy()
+		c.initModules()
+		c.restoreLock()
+		c.wg.Add(1)
+		go c.watchLock(c.ctx)
+		if c.screensaverSet() {
+			c.wg.Add(1)
+			go c.watchScreensaver(c.ctx)
+		}
+		if len(c.appPages) > 0 {
+			c.wg.Add(1)
+			go c.watchApps(c.ctx)
+		}
+		if len(c.schedulePages) > 0 {
+			c.wg.Add(1)
+			go c.watchSchedule(c.ctx)
+		}
+	} else {
+		// A render loop stalled on the old device must be done with it
+		if err := c.awaitRenderLoop(); err != nil {
+			return err
+		}
+		c.logger.Info("Reattaching to device")
+		c.resetDisplay()
+	}
 
 	// Get full strip rectangle for compositing
+	c.stripRect = image.Rectangle{}
 	if c.device.GetTouchStripSupported() {
 		rect, err := c.device.GetTouchStripImageRectangle()
 		if err == nil {
@@ -90,14 +265,10 @@ func (c *Coordinator) Start(ctx context.Context) error {
 		}
 	}
 
-	// Initialize all modules (continue on error, just skip failed modules)
-	for _, m := range c.modules {
-		res := c.resourcesForModule(m)
-		if err := m.Init(c.ctx, res); err != nil {
-			log.Printf("Module %s failed to initialize: %v (skipping)", m.ID(), err)
-			c.failedModules[m] = true
-		}
-	}
+	// The loops below last for this connection, ending with ctx or Stop
+	runCtx, cancelRun := context.WithCancel(ctx)
+	stopRun := context.AfterFunc(c.ctx, cancelRun)
+	defer stopRun()
 
 	// Setup event handlers
 	c.setupEventHandlers()
@@ -112,18 +283,81 @@ func (c *Coordinator) Start(ctx context.Context) error {
 		close(listenErr)
 	}()
 
-	// Start render loop
+	// Start the render and write loops, and don't return until they're
+	// done with the device, unless a write is stuck
+	renderDone := make(chan struct{})
+	c.renderDone = renderDone
+	var loops sync.WaitGroup
 	c.wg.Add(1)
-	go c.renderLoop()
+	loops.Go(func() { c.renderLoop(runCtx) })
+	loops.Go(func() { c.writeLoop(runCtx) })
+	go func() {
+		loops.Wait()
+		close(renderDone)
+	}()
+	stalled := make(chan error, 1)
+	go func() {
+		stalled <- c.watchRender(runCtx)
+	}()
 
-	// Wait for context cancellation or device disconnect
+	// Wait for context cancellation, device disconnect, or a stall
+	var err error
 	select {
-	case <-c.ctx.Done():
-		return nil
-	case err := <-listenErr:
+	case <-runCtx.Done():
+	case err = <-listenErr:
 		// Device disconnected or listener error
+	case err = <-stalled:
+		cancelRun()
 		return err
 	}
+	cancelRun()
+	<-renderDone
+	return err
+}
+
+// initModules initializes all modules, continuing on error: failed modules
+// are skipped and retried in the background.
+func (c *Coordinator) initModules() {
+	for _, m := range c.modules {
+		res := c.resourcesForModule(m)
+		var err error
+		if !c.safeCall(m, "Init", func() { err = m.Init(c.ctx, res) }) {
+			continue
+		}
+		if err != nil {
+			c.logger.Warn("Module failed to initialize, will retry", "id", m.ID(), "err", err)
+			c.setFailed(m, true)
+			c.setHealth(m, "failed", err.Error())
+			c.wg.Add(1)
+			go c.retryInit(m)
+			continue
+		}
+		c.setHealth(m, "ready", "")
+	}
+}
+
+// resetDisplay forgets what was drawn on the device and any input in
+// progress, for a reconnected device that starts out blank.
+func (c *Coordinator) resetDisplay() {
+	c.mu.Lock()
+	c.modifierHeld = false
+	c.pressed = make(map[module.KeyID]time.Time)
+	c.mu.Unlock()
+
+	c.lock.mu.Lock()
+	c.lock.held = [2]bool{}
+	c.lock.mu.Unlock()
+
+	// The new connection starts at full brightness
+	c.saver.mu.Lock()
+	c.saver.on, c.saver.lastInput = false, time.Now()
+	c.saver.mu.Unlock()
+
+	c.lastKeyImages = make(map[module.KeyID]image.Image)
+	c.feedbackShown = nil
+	c.writes.reset()
+	clear(c.faces)
+	c.renderedPage = -1 // clears every key on the first pass
 }
 
 // Stop gracefully shuts down all modules.
@@ -134,7 +368,7 @@ func (c *Coordinator) Stop() error {
 
 	// Stop all modules
 	for _, m := range c.modules {
-		m.Stop()
+		c.safeCall(m, "Stop", func() { m.Stop() })
 	}
 
 	c.wg.Wait()
@@ -146,79 +380,225 @@ func (c *Coordinator) resourcesForModule(m module.Module) module.Resources {
 	return c.moduleResources[m]
 }
 
-// getActiveOverlay returns the active overlay provider, if any.
-func (c *Coordinator) getActiveOverlay() module.OverlayProvider {
+// isFailed reports whether a module is currently failed.
+func (c *Coordinator) isFailed(m module.Module) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.failedModules[m]
+}
+
+// setFailed marks a module as failed or active.
+func (c *Coordinator) setFailed(m module.Module, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if failed {
+		c.failedModules[m] = true
+	} else {
+		delete(c.failedModules, m)
+	}
+}
+
+// retryInit re-runs Init for a failed module with exponential backoff until
+// it succeeds or the coordinator stops. On success the module becomes active.
+func (c *Coordinator) retryInit(m module.Module) {
+	defer c.wg.Done()
+
+	delay := initRetryMin
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		// Clean up anything the failed Init left running before trying again
+		var err error
+		ok := c.safeCall(m, "Stop", func() { m.Stop() }) &&
+			c.safeCall(m, "Init", func() { err = m.Init(c.ctx, c.resourcesForModule(m)) })
+		if !ok {
+			return
+		}
+		if err == nil {
+			c.logger.Info("Module initialized after retry", "id", m.ID(), "attempts", attempt)
+			c.setFailed(m, false)
+			c.setHealth(m, "ready", "")
+			c.requestRender()
+			return
+		}
+
+		delay = min(delay*2, initRetryMax)
+		c.logger.Warn("Module init retry failed", "id", m.ID(), "attempt", attempt, "err", err, "next", delay)
+	}
+}
+
+// getActiveOverlay returns the module with an active overlay and its
+// overlay provider, if any.
+func (c *Coordinator) getActiveOverlay() (module.Module, module.OverlayProvider) {
 	for _, m := range c.modules {
-		if c.failedModules[m] {
+		if !c.isActive(m) {
+			continue
+		}
+		overlay, ok := m.(module.OverlayProvider)
+		if !ok {
 			continue
 		}
-		if overlay, ok := m.(module.OverlayProvider); ok && overlay.IsOverlayActive() {
-			return overlay
+		var active bool
+		if c.safeCall(m, "IsOverlayActive", func() { active = overlay.IsOverlayActive() }) && active {
+			return m, overlay
 		}
 	}
-	return nil
+	return nil, nil
+}
+
+// handleKey delivers a key event to m, or to its overlay handler when
+// overlay is non-nil. A panic degrades m instead of crashing the daemon.
+func (c *Coordinator) handleKey(m module.Module, overlay module.OverlayProvider, key module.KeyID, event module.KeyEvent) error {
+	var err error
+	if overlay != nil {
+		c.safeCall(m, "HandleOverlayKey", func() { err = overlay.HandleOverlayKey(key, event) })
+		c.noteOverlayInput()
+	} else {
+		c.safeCall(m, "HandleKey", func() { err = m.HandleKey(key, event) })
+	}
+	return err
+}
+
+// handleDial delivers a dial event to m, or to its overlay handler when
+// overlay is non-nil.
+func (c *Coordinator) handleDial(m module.Module, overlay module.OverlayProvider, dial module.DialID, event module.DialEvent) error {
+	var err error
+	if overlay != nil {
+		c.safeCall(m, "HandleOverlayDial", func() { err = overlay.HandleOverlayDial(dial, event) })
+		c.noteOverlayInput()
+	} else {
+		c.safeCall(m, "HandleDial", func() { err = m.HandleDial(dial, event) })
+	}
+	return err
+}
+
+// handleStripTouch delivers a strip event to m, or to its overlay handler
+// when overlay is non-nil.
+func (c *Coordinator) handleStripTouch(m module.Module, overlay module.OverlayProvider, event module.TouchStripEvent) error {
+	var err error
+	if overlay != nil {
+		c.safeCall(m, "HandleOverlayStripTouch", func() { err = overlay.HandleOverlayStripTouch(event) })
+		c.noteOverlayInput()
+	} else {
+		c.safeCall(m, "HandleStripTouch", func() { err = m.HandleStripTouch(event) })
+	}
+	return err
 }
 
 // setupEventHandlers registers device event handlers that route to modules.
 func (c *Coordinator) setupEventHandlers() {
 	// Key handlers - register for ALL keys, not just owned ones
-	allKeys := []module.KeyID{
-		module.Key1, module.Key2, module.Key3, module.Key4,
-		module.Key5, module.Key6, module.Key7, module.Key8,
-	}
-
-	for _, keyID := range allKeys {
+	for _, keyID := range c.deviceKeys() {
 		key := keyID
-		owner := c.keyOwners[key] // may be nil for unowned keys
 		c.device.AddKeyHandler(device.KeyID(key), func(d device.Device, k device.Key) error {
-			// Check for active overlay first
-			if overlay := c.getActiveOverlay(); overlay != nil {
-				// Route to overlay handler
-				event := module.KeyEvent{Pressed: true}
-				if err := overlay.HandleOverlayKey(key, event); err != nil {
-					return err
-				}
-				duration := k.WaitForRelease()
-				event = module.KeyEvent{Pressed: false, Duration: duration}
-				return overlay.HandleOverlayKey(key, event)
+			metrics.KeyPresses.WithLabelValues(strconv.Itoa(int(key))).Inc()
+
+			// A press that wakes the screensaver goes no further
+			if c.wake() {
+				k.WaitForRelease()
+				return nil
 			}
 
-			// No overlay - route to owner if exists
-			if owner == nil || c.failedModules[owner] {
+			// The lock chord comes before everything, and the lock before
+			// the rest
+			if c.pressLockKey(key) {
+				k.WaitForRelease()
+				c.releaseLockKey(key)
+				return nil
+			}
+			defer c.releaseLockKey(key)
+			if c.isLocked() {
+				c.unlockStep(key)
+				k.WaitForRelease()
 				return nil
 			}
+
+			// The modifier key is the coordinator's unless an overlay has
+			// taken over the keys
+			if c.isModifierKey(key) {
+				if _, overlay := c.getActiveOverlay(); overlay == nil {
+					c.holdModifier(key, k)
+					return nil
+				}
+			}
+			if c.isPrivacyKey(key) {
+				if _, overlay := c.getActiveOverlay(); overlay == nil {
+					c.pressPrivacyKey(key, k)
+					return nil
+				}
+			}
+
+			c.showPressFeedback(key)
+
+			// The owner depends on the page; the release goes to the same
+			// module as the press even if the press switched pages
+			owner := c.keyOwner(key) // may be nil for unowned keys
+			layer := c.layer()
+			events.Publish(events.Event{Type: events.TypeKey, Key: int(key), Pressed: events.Bool(true), Module: moduleID(owner), Layer: int(layer)})
+
+			// Check for active overlay first, then route to owner if exists.
+			// Unowned keys are still waited on so the release is published.
+			target, overlay := c.getActiveOverlay()
+			if overlay == nil && owner != nil && c.isActive(owner) {
+				target = owner
+			}
+
 			// Create press event
-			event := module.KeyEvent{Pressed: true}
-			if err := owner.HandleKey(key, event); err != nil {
-				return err
+			event := module.KeyEvent{Pressed: true, Layer: layer}
+			if target != nil {
+				if err := c.handleKey(target, overlay, key, event); err != nil {
+					return err
+				}
 			}
 
 			// Wait for release and create release event
 			duration := k.WaitForRelease()
-			event = module.KeyEvent{Pressed: false, Duration: duration}
-			return owner.HandleKey(key, event)
+			events.Publish(events.Event{Type: events.TypeKey, Key: int(key), Pressed: events.Bool(false), DurationMS: duration.Milliseconds(), Module: moduleID(owner), Layer: int(layer)})
+			if target == nil {
+				return nil
+			}
+			event = module.KeyEvent{Pressed: false, Duration: duration, Layer: layer}
+			return c.handleKey(target, overlay, key, event)
 		})
 	}
 
 	// Dial rotation handlers - register for ALL dials to support overlay
-	allDials := []module.DialID{module.Dial1, module.Dial2, module.Dial3, module.Dial4}
+	allDials := c.deviceDials()
 	for _, dialID := range allDials {
 		dial := dialID
 		owner := c.dialOwners[dial] // may be nil for unowned dials
 		c.device.AddDialRotateHandler(device.DialID(dial), func(d device.Device, di device.Dial, delta int8) error {
+			if c.wake() || c.isLocked() {
+				return nil
+			}
+			// Published raw; modules get the tuned delta
+			layer := c.layer()
+			events.Publish(events.Event{Type: events.TypeDialRotate, Dial: int(dial), Delta: int(delta), Module: moduleID(owner), Layer: int(layer)})
+			if delta = c.processDial(dial, delta); delta == 0 {
+				return nil
+			}
+			if c.isBrightnessDial(dial, layer) {
+				c.adjustBrightness(delta)
+				return nil
+			}
 			event := module.DialEvent{
 				Type:  module.DialRotate,
 				Delta: delta,
+				Layer: layer,
 			}
 			// Check for active overlay first
-			if overlay := c.getActiveOverlay(); overlay != nil {
-				return overlay.HandleOverlayDial(dial, event)
+			if m, overlay := c.getActiveOverlay(); overlay != nil {
+				return c.handleDial(m, overlay, dial, event)
 			}
 			// No overlay - route to owner if exists
-			if owner == nil || c.failedModules[owner] {
+			if owner == nil || !c.isActive(owner) {
 				return nil
 			}
-			return owner.HandleDial(dial, event)
+			return c.handleDial(owner, nil, dial, event)
 		})
 	}
 
@@ -227,132 +607,301 @@ func (c *Coordinator) setupEventHandlers() {
 		dial := dialID
 		owner := c.dialOwners[dial] // may be nil for unowned dials
 		c.device.AddDialSwitchHandler(device.DialID(dial), func(d device.Device, di device.Dial) error {
-			// Check for active overlay first
-			if overlay := c.getActiveOverlay(); overlay != nil {
-				// Create press event
-				event := module.DialEvent{Type: module.DialPress}
-				if err := overlay.HandleOverlayDial(dial, event); err != nil {
-					return err
-				}
-				// Wait for release and create release event
-				duration := di.WaitForRelease()
-				event = module.DialEvent{Type: module.DialRelease, Duration: duration}
-				return overlay.HandleOverlayDial(dial, event)
-			}
-			// No overlay - route to owner if exists
-			if owner == nil || c.failedModules[owner] {
+			if c.wake() || c.isLocked() {
+				di.WaitForRelease()
 				return nil
 			}
+			layer := c.layer()
+			events.Publish(events.Event{Type: events.TypeDialPress, Dial: int(dial), Pressed: events.Bool(true), Module: moduleID(owner), Layer: int(layer)})
+
+			// Check for active overlay first, then route to owner if exists
+			target, overlay := c.getActiveOverlay()
+			if overlay == nil && owner != nil && c.isActive(owner) {
+				target = owner
+			}
+
 			// Create press event
-			event := module.DialEvent{Type: module.DialPress}
-			if err := owner.HandleDial(dial, event); err != nil {
-				return err
+			event := module.DialEvent{Type: module.DialPress, Layer: layer}
+			if target != nil {
+				if err := c.handleDial(target, overlay, dial, event); err != nil {
+					return err
+				}
 			}
 			// Wait for release and create release event
 			duration := di.WaitForRelease()
-			event = module.DialEvent{Type: module.DialRelease, Duration: duration}
-			return owner.HandleDial(dial, event)
+			events.Publish(events.Event{Type: events.TypeDialPress, Dial: int(dial), Pressed: events.Bool(false), DurationMS: duration.Milliseconds(), Module: moduleID(owner), Layer: int(layer)})
+			if target == nil {
+				return nil
+			}
+			event = module.DialEvent{Type: module.DialRelease, Duration: duration, Layer: layer}
+			return c.handleDial(target, overlay, dial, event)
 		})
 	}
 
 	// Touch strip handler - route based on X coordinate
 	if c.device.GetTouchStripSupported() {
 		c.device.AddTouchStripTouchHandler(func(d device.Device, touchType device.TouchStripTouchType, point image.Point) error {
+			if c.wake() || c.isLocked() {
+				return nil
+			}
 			event := module.TouchStripEventFromDeviceTap(touchType, point)
-			// Check for active overlay first
-			if overlay := c.getActiveOverlay(); overlay != nil {
-				return overlay.HandleOverlayStripTouch(event)
+			touch := "short"
+			if touchType == device.TOUCH_STRIP_TOUCH_TYPE_LONG {
+				touch = "long"
+			}
+			events.Publish(events.Event{Type: events.TypeStripTouch, Touch: touch, X: point.X, Y: point.Y, Module: moduleID(c.stripOwner(point))})
+			err := c.dispatchStripEvent(event, point)
+			if event.Type != module.TouchTap {
+				c.gestures.reset()
+			} else if c.gestures.tap(point, time.Now()) {
+				err = errors.Join(err, c.dispatchStripEvent(module.TouchStripEvent{Type: module.TouchDoubleTap, Point: point}, point))
 			}
-			return c.routeStripEvent(event)
+			return err
 		})
 
 		c.device.AddTouchStripSwipeHandler(func(d device.Device, origin, dest image.Point) error {
+			if c.wake() || c.isLocked() {
+				return nil
+			}
 			event := module.TouchStripEventFromSwipe(origin, dest)
-			// Check for active overlay first
-			if overlay := c.getActiveOverlay(); overlay != nil {
-				return overlay.HandleOverlayStripTouch(event)
+			events.Publish(events.Event{Type: events.TypeStripSwipe, X: origin.X, Y: origin.Y, ToX: dest.X, ToY: dest.Y, Module: moduleID(c.stripOwner(origin))})
+			c.gestures.reset()
+			if c.swipePage(origin, dest) {
+				return nil
 			}
-			return c.routeStripEvent(event)
+			// The gesture's events all go where it started, even as a drag
+			// leaves that module's region
+			var errs []error
+			for _, e := range append([]module.TouchStripEvent{event}, swipeGestures(c.stripRect, origin, dest)...) {
+				errs = append(errs, c.dispatchStripEvent(e, origin))
+			}
+			return errors.Join(errs...)
 		})
 	}
 }
 
-// routeStripEvent finds the owning module for a strip event and dispatches it.
-func (c *Coordinator) routeStripEvent(event module.TouchStripEvent) error {
+// stripOwner returns the module whose strip region contains p, or nil.
+func (c *Coordinator) stripOwner(p image.Point) module.Module {
+	for _, m := range c.modules {
+		if res := c.resourcesForModule(m); res.HasStrip() && p.In(res.StripRect) {
+			return m
+		}
+	}
+	return nil
+}
+
+// moduleID returns m's ID, or "" for nil.
+func moduleID(m module.Module) string {
+	if m == nil {
+		return ""
+	}
+	return m.ID()
+}
+
+// dispatchStripEvent sends a strip event to the overlay showing, if any,
+// or else to the module whose region contains at.
+func (c *Coordinator) dispatchStripEvent(event module.TouchStripEvent, at image.Point) error {
+	if m, overlay := c.getActiveOverlay(); overlay != nil {
+		return c.handleStripTouch(m, overlay, event)
+	}
+	return c.routeStripEvent(event, at)
+}
+
+// routeStripEvent finds the module whose strip region contains at and
+// dispatches event to it.
+func (c *Coordinator) routeStripEvent(event module.TouchStripEvent, at image.Point) error {
 	for _, m := range c.modules {
-		if c.failedModules[m] {
+		if !c.isActive(m) {
 			continue
 		}
 		res := c.resourcesForModule(m)
-		if res.HasStrip() && event.Point.In(res.StripRect) {
-			return m.HandleStripTouch(event)
+		if res.HasStrip() && at.In(res.StripRect) {
+			return c.handleStripTouch(m, nil, event)
 		}
 	}
 	return nil
 }
 
-// renderLoop runs the periodic render cycle.
-func (c *Coordinator) renderLoop() {
+// renderLoop runs the periodic render cycle until ctx is done.
+func (c *Coordinator) renderLoop(ctx context.Context) {
 	defer c.wg.Done()
 
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
 
 	// Initial render
-	c.renderKeys()
-	c.renderStrip()
+	c.render()
 
 	for {
 		select {
-		case <-c.ctx.Done():
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			c.renderKeys()
-			c.renderStrip()
+			c.render()
+		case <-c.renderNow:
+			c.render()
+		case <-c.stripRefresh:
+			c.refreshStrip()
+		case <-c.overlayInput:
+			c.armOverlayExpiry()
+			c.render()
+		case <-c.overlay.expiry.C:
+			c.expireOverlay()
+			c.render()
+		case <-c.frameTimer.C:
+			c.render()
 		}
 	}
 }
 
+// render runs one pass of key and strip rendering.
+func (c *Coordinator) render() {
+	defer c.track(&c.rendering, "rendering")()
+	start := time.Now()
+	c.checkOffline()
+	c.renderKeys()
+	c.scheduleFrame()
+	c.renderStrip()
+	metrics.RenderDuration.Observe(time.Since(start).Seconds())
+}
+
+// setKeyImage writes a key image, counting failed writes. A key showing
+// press feedback keeps it until the feedback ends; img is still remembered
+// as what the key shows underneath.
+func (c *Coordinator) setKeyImage(key module.KeyID, img image.Image) {
+	c.lastKeyImages[key] = img
+	if c.pressedNow[key] {
+		return
+	}
+	c.writeKeyImage(key, img)
+}
+
+// writeKeyImage queues a key image for the device.
+func (c *Coordinator) writeKeyImage(key module.KeyID, img image.Image) {
+	img = c.screensaverKey(key, img)
+	if c.unchanged(key, img) {
+		return
+	}
+	c.writes.push(deviceWrite{key: key, img: img})
+}
+
+// clearKey queues clearing a key.
+func (c *Coordinator) clearKey(key module.KeyID) {
+	c.forgetFace(key)
+	c.writes.push(deviceWrite{key: key})
+}
+
+// setStripImage queues the strip image for the device.
+func (c *Coordinator) setStripImage(img image.Image) {
+	img = c.screensaverStrip(img)
+	if c.unchanged(stripFace, img) {
+		return
+	}
+	c.writes.push(deviceWrite{img: img})
+}
+
+// requestRender schedules a render as soon as possible without blocking.
+func (c *Coordinator) requestRender() {
+	select {
+	case c.renderNow <- struct{}{}:
+	default:
+	}
+}
+
 // renderKeys collects key images from all modules and applies them to the device.
+// Keys showing a notification are skipped and drawn with the notification instead.
+// Keys owned by a degraded module show an error tile, and those of a module
+// whose data is stale a badge.
 func (c *Coordinator) renderKeys() {
+	c.pressedNow = c.pressedKeys()
+	defer c.drawPressFeedback()
+
+	notes := c.keyNotifications()
+	for keyID, img := range notes {
+		c.setKeyImage(keyID, img)
+	}
+
+	now := time.Now()
+
 	// Check for active overlays first
-	overlayActive := false
-	for _, m := range c.modules {
-		if c.failedModules[m] {
-			continue
-		}
-		if overlay, ok := m.(module.OverlayProvider); ok && overlay.IsOverlayActive() {
-			overlayActive = true
-			// Overlay takes over all keys
-			keyImages := overlay.RenderOverlayKeys()
+	if m, overlay := c.getActiveOverlay(); overlay != nil {
+		// Overlay takes over all keys
+		var keyImages map[module.KeyID]image.Image
+		if c.safeCall(m, "RenderOverlayKeys", func() { keyImages = overlay.RenderOverlayKeys() }) {
 			for keyID, img := range keyImages {
+				if _, noted := notes[keyID]; noted {
+					continue
+				}
 				if img != nil {
-					c.device.SetKeyImage(device.KeyID(keyID), img)
+					c.setKeyImage(keyID, c.keyFrame(img, now))
 				}
 			}
-			c.overlayWasActive = true
+			c.overlayShown(m)
 			return
 		}
 	}
 
 	// If overlay just became inactive, clear all keys first
-	if c.overlayWasActive && !overlayActive {
+	if c.overlay.active {
+		c.clearAllKeys()
+		c.overlayHidden()
+	}
+
+	// Clear keys the new page leaves unowned
+	if page := c.CurrentPage(); page != c.renderedPage {
 		c.clearAllKeys()
-		c.overlayWasActive = false
+		c.renderedPage = page
 	}
 
 	// Normal rendering
+	tasks := c.activeTasks()
 	for _, m := range c.modules {
-		if c.failedModules[m] {
+		if c.isFailed(m) || !c.onCurrentPage(m) {
 			continue
 		}
-		keyImages := m.RenderKeys()
+		var keyImages map[module.KeyID]image.Image
+		if c.isActive(m) {
+			c.safeCall(m, "RenderKeys", func() { keyImages = m.RenderKeys() })
+		}
+		stale := c.isStale(m, now)
+		if c.isDegraded(m) {
+			keyImages = make(map[module.KeyID]image.Image)
+			errImg := renderDegradedKey(m.ID())
+			for _, keyID := range c.resourcesForModule(m).Keys {
+				keyImages[keyID] = errImg
+			}
+		}
 		for keyID, img := range keyImages {
+			if _, noted := notes[keyID]; noted || c.isModifierKey(keyID) || c.isPrivacyKey(keyID) {
+				continue
+			}
 			if img != nil {
-				c.device.SetKeyImage(device.KeyID(keyID), img)
+				img = c.keyFrame(img, now)
+			}
+			if stale && img != nil {
+				img = withStaleBadge(img)
+			}
+			if t, ok := tasks[keyID]; ok && img != nil {
+				img = renderTask(img, t, now)
+			}
+			if img != nil {
+				img = c.lockBadged(img)
+			}
+			if img != nil {
+				c.setKeyImage(keyID, img)
 			}
 		}
 	}
+
+	c.mu.RLock()
+	modKey, privKey := c.modifierKey, c.privacyKey
+	c.mu.RUnlock()
+	if _, noted := notes[modKey]; modKey != 0 && !noted {
+		c.setKeyImage(modKey, c.lockBadged(c.renderModifierKey()))
+	}
+	if _, noted := notes[privKey]; privKey != 0 && !noted {
+		c.setKeyImage(privKey, c.lockBadged(c.renderPrivacyKey()))
+	}
 }
 
 // renderStrip composites strip images from all modules and applies to the device.
@@ -361,35 +910,58 @@ func (c *Coordinator) renderStrip() {
 		return
 	}
 
+	// A notification takes over the whole strip while it's showing, as
+	// does the brightness level while it's being adjusted
+	c.stripOwned = true
+	if img := c.stripNotification(); img != nil {
+		c.setStripImage(img)
+		return
+	}
+	if img := c.brightnessOSD(); img != nil {
+		c.setStripImage(img)
+		return
+	}
+
 	// Check for active overlays first
-	for _, m := range c.modules {
-		if c.failedModules[m] {
-			continue
-		}
-		if overlay, ok := m.(module.OverlayProvider); ok && overlay.IsOverlayActive() {
-			// Overlay takes over the strip
-			stripImg := overlay.RenderOverlayStrip()
+	if m, overlay := c.getActiveOverlay(); overlay != nil {
+		// Overlay takes over the strip
+		var stripImg image.Image
+		if c.safeCall(m, "RenderOverlayStrip", func() { stripImg = overlay.RenderOverlayStrip() }) {
 			if stripImg != nil {
-				c.device.SetTouchStripImage(stripImg)
+				c.setStripImage(stripImg)
 			}
 			return
 		}
 	}
+	c.stripOwned = false
+
+	c.setStripImage(c.compositeStrip(c.stripRect))
+}
 
-	// Create composite strip image
-	composite := image.NewRGBA(c.stripRect)
+// compositeStrip draws the strip output of every module whose region
+// overlaps clip, returning an image covering just clip. Stale modules'
+// regions are badged, and the strip shows when the deck is offline.
+func (c *Coordinator) compositeStrip(clip image.Rectangle) *image.RGBA {
+	composite := image.NewRGBA(clip)
+	now := time.Now()
 
 	// Collect and composite each module's strip output
 	for _, m := range c.modules {
-		if c.failedModules[m] {
+		if c.isFailed(m) {
 			continue
 		}
 		res := c.resourcesForModule(m)
-		if !res.HasStrip() {
+		if !res.HasStrip() || !res.StripRect.Overlaps(clip) {
 			continue
 		}
 
-		stripImg := m.RenderStrip()
+		var stripImg image.Image
+		if c.isActive(m) {
+			c.safeCall(m, "RenderStrip", func() { stripImg = m.RenderStrip() })
+		}
+		if c.isDegraded(m) {
+			stripImg = renderDegradedStrip(c.stripRect, res.StripRect, m.ID())
+		}
 		if stripImg == nil {
 			continue
 		}
@@ -397,24 +969,102 @@ func (c *Coordinator) renderStrip() {
 		// Draw module's strip at its allocated region
 		// For now, we draw at 0,0 - in future, we'd use res.StripRect offset
 		draw.Draw(composite, stripImg.Bounds(), stripImg, image.Point{}, draw.Over)
+		if c.isStale(m, now) {
+			drawStaleBadge(composite, res.StripRect)
+		}
+	}
+	if c.offline {
+		drawOfflineIndicator(composite, c.stripRect)
+	}
+	if c.isLocked() {
+		drawLockBadge(composite, image.Pt(c.stripRect.Min.X+14, c.stripRect.Min.Y+14))
 	}
 
-	c.device.SetTouchStripImage(composite)
+	return composite
+}
+
+// RefreshStrip redraws region of the strip right away instead of on the
+// next render pass, writing only that part to the device. It's for strip
+// content that follows a dial, where waiting for the ticker would be
+// jerky. Safe to call from any goroutine; calls made before the redraw
+// runs are merged into one.
+func (c *Coordinator) RefreshStrip(region image.Rectangle) {
+	c.mu.Lock()
+	c.stripDirty = c.stripDirty.Union(region)
+	c.mu.Unlock()
+
+	select {
+	case c.stripRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// refreshStrip redraws the regions passed to RefreshStrip. If something
+// has taken over the whole strip, or is about to, the whole strip is
+// drawn instead.
+func (c *Coordinator) refreshStrip() {
+	defer c.track(&c.rendering, "refreshing the strip")()
+	c.mu.Lock()
+	region := c.stripDirty.Intersect(c.stripRect)
+	c.stripDirty = image.Rectangle{}
+	c.mu.Unlock()
+
+	if region.Empty() {
+		return
+	}
+	if c.stripOwned || c.stripNotification() != nil || c.brightnessOSD() != nil || c.screensaverOn() {
+		c.renderStrip()
+		return
+	}
+	if _, overlay := c.getActiveOverlay(); overlay != nil {
+		c.renderStrip()
+		return
+	}
+
+	c.forgetFace(stripFace)
+	c.writes.push(deviceWrite{img: c.compositeStrip(region), rect: region})
 }
 
 // Device returns the underlying device.
-// Modules can use this to query device capabilities like key size.
 func (c *Coordinator) Device() device.Device {
 	return c.device
 }
 
-// clearAllKeys sets all keys to black.
-func (c *Coordinator) clearAllKeys() {
-	allKeys := []module.KeyID{
-		module.Key1, module.Key2, module.Key3, module.Key4,
-		module.Key5, module.Key6, module.Key7, module.Key8,
+// capabilities describes the device for modules' resources. Stream Decks
+// take 24-bit color images on every model.
+func (c *Coordinator) capabilities() module.Capabilities {
+	caps := module.Capabilities{KeyCount: int(c.device.GetKeyCount()), ColorDepth: 24}
+	if rect, err := c.device.GetKeyImageRectangle(); err == nil {
+		caps.KeyRect = rect
+	}
+	if c.device.GetTouchStripSupported() {
+		if rect, err := c.device.GetTouchStripImageRectangle(); err == nil {
+			caps.StripRect = rect
+		}
 	}
+	return caps
+}
 
+// deviceKeys returns every key the device has.
+func (c *Coordinator) deviceKeys() []module.KeyID {
+	keys := make([]module.KeyID, 0, c.device.GetKeyCount())
+	for i := 1; i <= int(c.device.GetKeyCount()); i++ {
+		keys = append(keys, module.KeyID(i))
+	}
+	return keys
+}
+
+// deviceDials returns every dial the device has.
+func (c *Coordinator) deviceDials() []module.DialID {
+	dials := make([]module.DialID, 0, c.device.GetDialCount())
+	for i := 1; i <= int(c.device.GetDialCount()); i++ {
+		dials = append(dials, module.DialID(i))
+	}
+	return dials
+}
+
+// clearAllKeys sets all keys to black.
+func (c *Coordinator) clearAllKeys() {
 	// Create a black image for clearing
 	keyRect, err := c.device.GetKeyImageRectangle()
 	if err != nil {
@@ -422,7 +1072,7 @@ func (c *Coordinator) clearAllKeys() {
 	}
 	blackImg := image.NewRGBA(keyRect)
 
-	for _, keyID := range allKeys {
-		c.device.SetKeyImage(device.KeyID(keyID), blackImg)
+	for _, keyID := range c.deviceKeys() {
+		c.setKeyImage(keyID, blackImg)
 	}
 }
@@ -0,0 +1,115 @@
+package coordinator
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// Rotation speeds, in ticks per second, between which acceleration ramps
+// from none to full.
+const (
+	accelSlowRate = 5.0
+	accelFastRate = 30.0
+)
+
+// dialIdle is how long a dial must rest before a turn starts fresh, dropping
+// partial detents and fractional steps.
+const dialIdle = 500 * time.Millisecond
+
+// DialTuning shapes a dial's rotation before modules see it. Zero values
+// leave the raw deltas unchanged.
+type DialTuning struct {
+	// Acceleration is how many steps a tick counts for at full speed.
+	Acceleration float64
+	// Scale multiplies every step.
+	Scale float64
+	// Detents groups this many ticks into one step.
+	Detents int
+}
+
+// SetDialTuning sets how a dial's rotation is processed. Must be called
+// before Start.
+func (c *Coordinator) SetDialTuning(dial module.DialID, t DialTuning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dialTuning[dial] = &dialProcessor{tuning: t}
+}
+
+// processDial applies dial's tuning to a raw rotation delta. A zero result
+// means the turn hasn't yet added up to a step.
+func (c *Coordinator) processDial(dial module.DialID, delta int8) int8 {
+	c.mu.RLock()
+	p := c.dialTuning[dial]
+	c.mu.RUnlock()
+	if p == nil {
+		return delta
+	}
+	return p.process(delta, time.Now())
+}
+
+// dialProcessor carries one dial's state between rotation events.
+type dialProcessor struct {
+	mu     sync.Mutex
+	tuning DialTuning
+
+	last      time.Time
+	ticks     int     // ticks toward the next detent
+	remainder float64 // fractional steps carried to the next event
+}
+
+// process converts delta, received at now, into steps.
+func (p *dialProcessor) process(delta int8, now time.Time) int8 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	elapsed := now.Sub(p.last)
+	p.last = now
+
+	// Start fresh after a pause or a change of direction
+	if elapsed > dialIdle || (p.ticks != 0 && sign(p.ticks) != sign(int(delta))) || (p.remainder != 0 && math.Signbit(p.remainder) != (delta < 0)) {
+		p.ticks = 0
+		p.remainder = 0
+	}
+
+	steps := int(delta)
+	if n := p.tuning.Detents; n > 1 {
+		p.ticks += int(delta)
+		steps = p.ticks / n
+		p.ticks -= steps * n
+	}
+
+	out := float64(steps) * p.accelFactor(delta, elapsed)
+	if p.tuning.Scale > 0 {
+		out *= p.tuning.Scale
+	}
+	out += p.remainder
+
+	whole := math.Trunc(out)
+	p.remainder = out - whole
+	return int8(max(min(whole, math.MaxInt8), math.MinInt8))
+}
+
+// accelFactor returns the step multiplier for a turn of delta ticks that
+// arrived elapsed after the previous one.
+func (p *dialProcessor) accelFactor(delta int8, elapsed time.Duration) float64 {
+	if p.tuning.Acceleration <= 1 || elapsed > dialIdle || elapsed <= 0 {
+		return 1
+	}
+	rate := math.Abs(float64(delta)) / elapsed.Seconds()
+	ramp := (rate - accelSlowRate) / (accelFastRate - accelSlowRate)
+	ramp = max(0, min(ramp, 1))
+	return 1 + (p.tuning.Acceleration-1)*ramp
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/draw"
+)
+
+// PressFeedback is the effect drawn on a key the moment it's pressed.
+type PressFeedback string
+
+// Press feedback styles.
+const (
+	FeedbackNone   PressFeedback = ""
+	FeedbackFlash  PressFeedback = "flash"  // brighten toward white
+	FeedbackInvert PressFeedback = "invert" // invert colors
+	FeedbackScale  PressFeedback = "scale"  // shrink, like a button pushed in
+)
+
+// feedbackDuration is how long press feedback stays on a key before the
+// module's own image returns.
+const feedbackDuration = 150 * time.Millisecond
+
+// feedbackScale is how far FeedbackScale shrinks the key image.
+const feedbackScale = 0.85
+
+// SetPressFeedback sets the effect drawn on pressed keys. Must be called
+// before Start.
+func (c *Coordinator) SetPressFeedback(f PressFeedback) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f == "none" {
+		f = FeedbackNone
+	}
+	c.feedback = f
+}
+
+// showPressFeedback marks key for press feedback and renders right away,
+// so the effect appears even if the module is slow to respond.
+func (c *Coordinator) showPressFeedback(key module.KeyID) {
+	c.mu.Lock()
+	if c.feedback == FeedbackNone {
+		c.mu.Unlock()
+		return
+	}
+	c.pressed[key] = time.Now().Add(feedbackDuration)
+	c.mu.Unlock()
+
+	c.requestRender()
+	time.AfterFunc(feedbackDuration, c.requestRender)
+}
+
+// pressedKeys returns keys whose feedback is still showing, dropping
+// expired ones.
+func (c *Coordinator) pressedKeys() map[module.KeyID]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	keys := make(map[module.KeyID]bool)
+	for key, expires := range c.pressed {
+		if now.After(expires) {
+			delete(c.pressed, key)
+			continue
+		}
+		keys[key] = true
+	}
+	return keys
+}
+
+// renderPressFeedback applies the configured effect to the image last shown
+// on key. Must be called from the render loop, which owns lastKeyImages.
+func (c *Coordinator) renderPressFeedback(key module.KeyID) image.Image {
+	c.mu.RLock()
+	style := c.feedback
+	c.mu.RUnlock()
+
+	src := c.lastKeyImages[key]
+	if src == nil {
+		rect, err := c.device.GetKeyImageRectangle()
+		if err != nil {
+			return nil
+		}
+		src = image.NewRGBA(rect)
+	}
+	b := src.Bounds()
+	img := image.NewRGBA(b)
+
+	switch style {
+	case FeedbackScale:
+		w := int(float64(b.Dx()) * feedbackScale)
+		h := int(float64(b.Dy()) * feedbackScale)
+		x := b.Min.X + (b.Dx()-w)/2
+		y := b.Min.Y + (b.Dy()-h)/2
+		draw.ApproxBiLinear.Scale(img, image.Rect(x, y, x+w, y+h), src, b, draw.Src, nil)
+	default:
+		for py := b.Min.Y; py < b.Max.Y; py++ {
+			for px := b.Min.X; px < b.Max.X; px++ {
+				col := color.RGBAModel.Convert(src.At(px, py)).(color.RGBA)
+				if style == FeedbackInvert {
+					col = color.RGBA{255 - col.R, 255 - col.G, 255 - col.B, 255}
+				} else {
+					col = color.RGBA{col.R/2 + 128, col.G/2 + 128, col.B/2 + 128, 255}
+				}
+				img.SetRGBA(px, py, col)
+			}
+		}
+	}
+	return img
+}
+
+// drawPressFeedback draws feedback on the keys pressed this pass and
+// restores keys whose feedback just ended, which no module may redraw.
+func (c *Coordinator) drawPressFeedback() {
+	for key := range c.feedbackShown {
+		if c.pressedNow[key] {
+			continue
+		}
+		if img := c.lastKeyImages[key]; img != nil {
+			c.writeKeyImage(key, img)
+		} else {
+			c.clearKey(key)
+		}
+	}
+	for key := range c.pressedNow {
+		if img := c.renderPressFeedback(key); img != nil {
+			c.writeKeyImage(key, img)
+		}
+	}
+	c.feedbackShown = c.pressedNow
+}
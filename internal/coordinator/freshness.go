@@ -0,0 +1,106 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// offlineModules is how many modules' fetches have to be failing at once
+// for the deck to show it's offline. One failing is more likely trouble
+// with its service than with the network.
+const offlineModules = 2
+
+var (
+	colorStale      = color.RGBA{230, 160, 40, 255} // Amber
+	colorOfflineBg  = color.RGBA{70, 70, 70, 230}
+	offlineFaceOnce sync.Once
+	offlineFace     font.Face
+)
+
+// loadOfflineFace creates the offline indicator's font on first use.
+func loadOfflineFace() {
+	offlineFaceOnce.Do(func() {
+		var err error
+		if offlineFace, err = render.NewFace(render.Bold, 12); err != nil {
+			slog.Error("Offline indicator font", "err", err)
+		}
+	})
+}
+
+// isStale reports whether m shows fetched data that's gone stale at now.
+func (c *Coordinator) isStale(m module.Module, now time.Time) bool {
+	r, ok := m.(module.FreshnessReporter)
+	if !ok || !c.isActive(m) {
+		return false
+	}
+	var f module.DataFreshness
+	c.safeCall(m, "DataFreshness", func() { f = r.DataFreshness() })
+	return f.Stale(now)
+}
+
+// checkOffline works out whether enough modules' fetches are failing to
+// show the deck as offline, logging when that changes. Called once per
+// render pass.
+func (c *Coordinator) checkOffline() {
+	failing := make(map[string]bool)
+	for _, m := range c.modules {
+		r, ok := m.(module.FreshnessReporter)
+		if !ok || !c.isActive(m) {
+			continue
+		}
+		var f module.DataFreshness
+		if c.safeCall(m, "DataFreshness", func() { f = r.DataFreshness() }) && f.Failing {
+			failing[m.ID()] = true
+		}
+	}
+
+	offline := len(failing) >= offlineModules
+	if offline != c.offline {
+		if offline {
+			c.logger.Warn("Several modules can't reach their services, showing offline", "modules", len(failing))
+		} else {
+			c.logger.Info("Modules reaching their services again")
+		}
+		c.offline = offline
+	}
+}
+
+// withStaleBadge returns a copy of src with the stale badge in its top
+// right corner.
+func withStaleBadge(src image.Image) image.Image {
+	b := src.Bounds()
+	img := image.NewRGBA(b)
+	draw.Draw(img, b, src, b.Min, draw.Src)
+	drawStaleBadge(img, b)
+	return img
+}
+
+// drawStaleBadge draws a small amber dot, ringed in black to stand out
+// from whatever's under it, in the top right corner of region.
+func drawStaleBadge(img *image.RGBA, region image.Rectangle) {
+	x, y := region.Max.X-9, region.Min.Y+9
+	fillCircle(img, x, y, 6, color.Black)
+	fillCircle(img, x, y, 4, colorStale)
+}
+
+// drawOfflineIndicator draws an "offline" tag at the top centre of rect,
+// the whole strip, onto img, which may cover only part of it.
+func drawOfflineIndicator(img *image.RGBA, rect image.Rectangle) {
+	loadOfflineFace()
+	if offlineFace == nil {
+		return
+	}
+	const text = "offline"
+	w := font.MeasureString(offlineFace, text).Ceil() + 12
+	tag := image.Rect(0, 0, w, 18).Add(image.Pt(rect.Min.X+(rect.Dx()-w)/2, rect.Min.Y+2))
+	draw.Draw(img, tag, &image.Uniform{colorOfflineBg}, image.Point{}, draw.Over)
+	render.DrawTextCentered(img, text, tag.Min.X+w/2, tag.Min.Y+13, offlineFace, colorStale)
+}
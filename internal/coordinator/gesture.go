@@ -0,0 +1,88 @@
+package coordinator
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// Gesture thresholds, in strip pixels. The strip reports a swipe only once
+// the finger lifts, with where it started and ended but not how long it
+// took, so a flick is told from a swipe by how far and how straight it went,
+// and a drag's points are played back along its path.
+const (
+	flickDistance   = 200 // a quarter of the Plus's strip
+	edgeWidth       = 40
+	dragStep        = 20
+	doubleTapWindow = 350 * time.Millisecond
+	doubleTapSlop   = 40
+)
+
+// gestures remembers the last tap, to spot a double tap.
+type gestures struct {
+	mu      sync.Mutex
+	lastTap time.Time
+	tapAt   image.Point
+}
+
+// tap records a tap at p, reporting whether it's the second of a double
+// tap. The tap after a double tap starts afresh.
+func (g *gestures) tap(p image.Point, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	d := p.Sub(g.tapAt)
+	double := !g.lastTap.IsZero() && now.Sub(g.lastTap) <= doubleTapWindow &&
+		abs(d.X) <= doubleTapSlop && abs(d.Y) <= doubleTapSlop
+	if double {
+		g.lastTap = time.Time{}
+	} else {
+		g.lastTap, g.tapAt = now, p
+	}
+	return double
+}
+
+// reset forgets the last tap, as after a long tap or swipe.
+func (g *gestures) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lastTap = time.Time{}
+}
+
+// swipeGestures returns the events following a swipe from origin to dest
+// on strip: a flick or edge swipe if it was one, then the drag.
+func swipeGestures(strip image.Rectangle, origin, dest image.Point) []module.TouchStripEvent {
+	var out []module.TouchStripEvent
+	d := dest.Sub(origin)
+	if isFlick(origin, dest) {
+		out = append(out, module.TouchStripEvent{Type: module.TouchFlick, Point: origin, SwipeStart: origin, SwipeEnd: dest})
+	}
+	switch {
+	case !strip.Empty() && origin.X < strip.Min.X+edgeWidth && d.X > 0:
+		out = append(out, module.TouchStripEvent{Type: module.TouchEdgeSwipe, Point: origin, SwipeStart: origin, SwipeEnd: dest, Edge: module.EdgeLeft})
+	case !strip.Empty() && origin.X >= strip.Max.X-edgeWidth && d.X < 0:
+		out = append(out, module.TouchStripEvent{Type: module.TouchEdgeSwipe, Point: origin, SwipeStart: origin, SwipeEnd: dest, Edge: module.EdgeRight})
+	}
+	return append(out, dragPoints(origin, dest)...)
+}
+
+// isFlick reports whether a swipe from origin to dest went far and
+// straight enough to be a flick.
+func isFlick(origin, dest image.Point) bool {
+	d := dest.Sub(origin)
+	return abs(d.X) >= flickDistance && abs(d.X) >= 2*abs(d.Y)
+}
+
+// dragPoints returns a drag from origin to dest as a point every dragStep
+// pixels.
+func dragPoints(origin, dest image.Point) []module.TouchStripEvent {
+	d := dest.Sub(origin)
+	steps := max(abs(d.X), abs(d.Y)) / dragStep
+	out := []module.TouchStripEvent{{Type: module.TouchDrag, Point: origin, Phase: module.DragStart}}
+	for i := 1; i < steps; i++ {
+		p := origin.Add(d.Mul(i).Div(steps))
+		out = append(out, module.TouchStripEvent{Type: module.TouchDrag, Point: p, Phase: module.DragMove})
+	}
+	return append(out, module.TouchStripEvent{Type: module.TouchDrag, Point: dest, Phase: module.DragEnd})
+}
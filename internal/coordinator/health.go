@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"maps"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/state"
+)
+
+// healthStateKey is where module health is persisted, so `belowdeck modules
+// list` can show how the running daemon's modules are doing.
+const healthStateKey = "module_health"
+
+// ModuleHealth is a module's state as last reported by a coordinator.
+type ModuleHealth struct {
+	State  string    `json:"state"` // "ready", "failed", or "degraded"
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// LoadHealth returns the module health saved by the last coordinator to
+// start modules, by module ID. It's only current while that coordinator
+// runs; Since tells how old it is.
+func LoadHealth() map[string]ModuleHealth {
+	var health map[string]ModuleHealth
+	state.Load(healthStateKey, &health)
+	return health
+}
+
+// SaveHealth makes the coordinator save module health for LoadHealth. Only
+// the daemon calls it, so the emulator and previews leave its health alone.
+// Must be called before Start.
+func (c *Coordinator) SaveHealth() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.health = make(map[string]ModuleHealth)
+}
+
+// setHealth publishes a module's state change and, if SaveHealth was
+// called, saves it for LoadHealth. A module placed more than once is saved
+// under its ID by its latest change.
+func (c *Coordinator) setHealth(m module.Module, st, reason string) {
+	events.Publish(events.Event{Type: events.TypeModuleState, Module: m.ID(), State: st, Reason: reason})
+
+	c.mu.Lock()
+	if c.health == nil {
+		c.mu.Unlock()
+		return
+	}
+	c.health[m.ID()] = ModuleHealth{State: st, Reason: reason, Since: time.Now()}
+	health := maps.Clone(c.health)
+	c.mu.Unlock()
+	state.Save(healthStateKey, health)
+}
@@ -0,0 +1,356 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/phinze/belowdeck/internal/state"
+	"golang.org/x/image/draw"
+)
+
+// What locked or unlocked the deck, for the log.
+const (
+	lockByGesture  = "gesture"
+	lockByKeys     = "keys"
+	lockBySchedule = "schedule"
+	lockByCommand  = "command"
+	lockBySaved    = "saved"
+)
+
+const (
+	// unlockTimeout is how long the unlock sequence waits for its next key
+	// before starting over.
+	unlockTimeout = 3 * time.Second
+
+	// lockPollInterval is how often the lock schedule and lock requests
+	// are checked.
+	lockPollInterval = time.Second
+
+	// lockRequestWait is how long RequestLock waits for a running deck to
+	// take its request.
+	lockRequestWait = 3 * lockPollInterval
+
+	// lockStateKey is where a lock is persisted, so a restart or replugging
+	// the deck doesn't unlock it. Scheduled locks aren't; the schedule
+	// decides again.
+	lockStateKey = "locked"
+)
+
+// colorLocked is the lock badge's background.
+var colorLocked = color.RGBA{150, 40, 40, 255}
+
+// lockState is whether the deck is locked against input, and how it's
+// locked and unlocked.
+type lockState struct {
+	mu        sync.Mutex
+	chord     [2]module.KeyID // zero if none
+	held      [2]bool         // which of chord are down
+	unlock    []module.KeyID  // unlock sequence; empty to unlock with chord
+	progress  int             // unlock keys pressed so far
+	lastPress time.Time
+	schedule  func(time.Time) bool // nil if none
+	locked    bool
+	by        string
+}
+
+// LockRequestPath returns the file the lock and unlock commands write to
+// ask a running deck to lock or unlock.
+func LockRequestPath() string {
+	path := state.DefaultPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "lock-request")
+}
+
+// ErrNoDeck is returned by RequestLock when no running deck took the
+// request.
+var ErrNoDeck = errors.New("no running belowdeck took the request; is the daemon running?")
+
+// RequestLock asks a running deck to lock, or unlock if locked is false,
+// and waits for it to take the request. If none does within
+// lockRequestWait, the request is withdrawn, so a deck started later
+// doesn't act on it, and ErrNoDeck is returned.
+func RequestLock(locked bool) error {
+	path := LockRequestPath()
+	if path == "" {
+		return errors.New("no state directory")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	req := "unlock"
+	if locked {
+		req = "lock"
+	}
+	if err := os.WriteFile(path, []byte(req+"\n"), 0o644); err != nil {
+		return err
+	}
+	for deadline := time.Now().Add(lockRequestWait); time.Now().Before(deadline); {
+		time.Sleep(lockPollInterval / 10)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+	}
+	os.Remove(path)
+	return ErrNoDeck
+}
+
+// SetLockChord makes pressing keys a and b together lock the deck, so
+// every key, dial, and strip touch is ignored until it's unlocked: by the
+// unlock sequence if there is one (see SetUnlockSequence), or else by
+// pressing a and b together again. The key pressed first still reaches its
+// module; the second doesn't. Must be called before Start.
+func (c *Coordinator) SetLockChord(a, b module.KeyID) {
+	c.lock.mu.Lock()
+	defer c.lock.mu.Unlock()
+	c.lock.chord = [2]module.KeyID{a, b}
+}
+
+// SetUnlockSequence makes pressing keys in order, each within
+// unlockTimeout of the last, unlock the deck. Must be called before Start.
+func (c *Coordinator) SetUnlockSequence(keys []module.KeyID) {
+	c.lock.mu.Lock()
+	defer c.lock.mu.Unlock()
+	c.lock.unlock = keys
+}
+
+// SetLockSchedule locks the deck when locked starts returning true, and
+// unlocks it when it stops, unless it was unlocked in between. Must be
+// called before Start.
+func (c *Coordinator) SetLockSchedule(locked func(time.Time) bool) {
+	c.lock.mu.Lock()
+	defer c.lock.mu.Unlock()
+	c.lock.schedule = locked
+}
+
+// restoreLock locks the deck if it was locked when it last ran.
+func (c *Coordinator) restoreLock() {
+	var locked bool
+	if state.Load(lockStateKey, &locked) && locked {
+		c.setLocked(true, lockBySaved)
+	}
+}
+
+// setLocked locks or unlocks the deck, noting what did it.
+func (c *Coordinator) setLocked(locked bool, by string) {
+	c.lock.mu.Lock()
+	if c.lock.locked == locked {
+		c.lock.mu.Unlock()
+		return
+	}
+	c.lock.locked, c.lock.by, c.lock.progress = locked, by, 0
+	c.lock.mu.Unlock()
+	state.Save(lockStateKey, locked && by != lockBySchedule)
+
+	text := "Unlocked"
+	if locked {
+		c.logger.Info("Deck locked", "by", by)
+		text = "Locked"
+	} else {
+		c.logger.Info("Deck unlocked", "by", by)
+	}
+	if by != lockBySaved {
+		c.Notify(module.Notification{Text: text, Image: render.Icon("lock", 40, render.ColorWhite), Color: colorLocked})
+	}
+	c.requestRender()
+}
+
+// lockedBy returns what locked the deck, or "" if it isn't locked.
+func (c *Coordinator) lockedBy() string {
+	c.lock.mu.Lock()
+	defer c.lock.mu.Unlock()
+	if !c.lock.locked {
+		return ""
+	}
+	return c.lock.by
+}
+
+// isLocked reports whether the deck is locked.
+func (c *Coordinator) isLocked() bool {
+	c.lock.mu.Lock()
+	defer c.lock.mu.Unlock()
+	return c.lock.locked
+}
+
+// pressLockKey notes that key is down if it's in the lock chord, reporting
+// whether it completed the chord. Completing it locks the deck, or unlocks
+// it if there's no unlock sequence.
+func (c *Coordinator) pressLockKey(key module.KeyID) bool {
+	c.lock.mu.Lock()
+	i := c.lock.chordIndex(key)
+	if i < 0 {
+		c.lock.mu.Unlock()
+		return false
+	}
+	c.lock.held[i] = true
+	if !c.lock.held[1-i] {
+		c.lock.mu.Unlock()
+		return false
+	}
+	locked, toggles := c.lock.locked, len(c.lock.unlock) == 0
+	c.lock.mu.Unlock()
+
+	if !locked {
+		c.setLocked(true, lockByGesture)
+	} else if toggles {
+		c.setLocked(false, lockByGesture)
+	}
+	return true
+}
+
+// releaseLockKey notes that key is up if it's in the lock chord.
+func (c *Coordinator) releaseLockKey(key module.KeyID) {
+	c.lock.mu.Lock()
+	defer c.lock.mu.Unlock()
+	if i := c.lock.chordIndex(key); i >= 0 {
+		c.lock.held[i] = false
+	}
+}
+
+// chordIndex returns key's place in the lock chord, or -1. Callers hold
+// l.mu.
+func (l *lockState) chordIndex(key module.KeyID) int {
+	switch {
+	case l.chord[0] == 0:
+		return -1
+	case key == l.chord[0]:
+		return 0
+	case key == l.chord[1]:
+		return 1
+	}
+	return -1
+}
+
+// unlockStep follows key, pressed while locked, through the unlock
+// sequence, unlocking the deck once it's complete.
+func (c *Coordinator) unlockStep(key module.KeyID) {
+	c.lock.mu.Lock()
+	seq := c.lock.unlock
+	if len(seq) == 0 {
+		c.lock.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	if now.Sub(c.lock.lastPress) > unlockTimeout {
+		c.lock.progress = 0
+	}
+	c.lock.lastPress = now
+	switch {
+	case key == seq[c.lock.progress]:
+		c.lock.progress++
+	case key == seq[0]:
+		c.lock.progress = 1
+	default:
+		c.lock.progress = 0
+	}
+	done := c.lock.progress == len(seq)
+	c.lock.mu.Unlock()
+
+	if done {
+		c.setLocked(false, lockByKeys)
+	}
+}
+
+// lockScheduled reports whether the lock schedule locks the deck at t.
+func (c *Coordinator) lockScheduled(t time.Time) bool {
+	c.lock.mu.Lock()
+	schedule := c.lock.schedule
+	c.lock.mu.Unlock()
+	return schedule != nil && schedule(t)
+}
+
+// watchLock follows the lock schedule and the lock and unlock commands
+// until ctx is done.
+func (c *Coordinator) watchLock(ctx context.Context) {
+	defer c.wg.Done()
+
+	started := time.Now()
+	scheduled := false
+	for {
+		if s := c.lockScheduled(time.Now()); s != scheduled {
+			scheduled = s
+			if s {
+				c.setLocked(true, lockBySchedule)
+			} else if c.lockedBy() == lockBySchedule {
+				c.setLocked(false, lockBySchedule)
+			}
+		}
+		c.takeLockRequest(started)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// takeLockRequest carries out and removes a request left by the lock or
+// unlock command, if there is one. A request older than since, when this
+// deck started, by more than lockRequestWait was left while no deck was
+// running; it's removed unheeded, as the command already reported that it
+// failed.
+func (c *Coordinator) takeLockRequest(since time.Time) {
+	path := LockRequestPath()
+	if path == "" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			c.logger.Warn("Failed to read lock request", "err", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	os.Remove(path)
+	if err != nil {
+		c.logger.Warn("Failed to read lock request", "err", err)
+		return
+	}
+	if info.ModTime().Before(since.Add(-lockRequestWait)) {
+		c.logger.Info("Ignoring lock request left before the deck started", "request", strings.TrimSpace(string(data)))
+		return
+	}
+	switch req := strings.TrimSpace(string(data)); req {
+	case "lock":
+		c.setLocked(true, lockByCommand)
+	case "unlock":
+		c.setLocked(false, lockByCommand)
+	default:
+		c.logger.Warn("Ignoring lock request", "request", req)
+	}
+}
+
+// lockBadged returns img with the lock badge in its bottom left corner
+// while the deck is locked, or img itself otherwise.
+func (c *Coordinator) lockBadged(img image.Image) image.Image {
+	if !c.isLocked() {
+		return img
+	}
+	b := img.Bounds()
+	badged := image.NewRGBA(b)
+	draw.Draw(badged, b, img, b.Min, draw.Src)
+	drawLockBadge(badged, image.Pt(b.Min.X+12, b.Max.Y-12))
+	return badged
+}
+
+// drawLockBadge draws a small lock on a red disc centred on p.
+func drawLockBadge(img *image.RGBA, p image.Point) {
+	fillCircle(img, p.X, p.Y, 10, color.Black)
+	fillCircle(img, p.X, p.Y, 9, colorLocked)
+	icon := render.Icon("lock", 12, render.ColorWhite)
+	r := image.Rect(p.X-6, p.Y-6, p.X+6, p.Y+6)
+	draw.Draw(img, r, icon, icon.Bounds().Min, draw.Over)
+}
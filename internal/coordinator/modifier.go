@@ -0,0 +1,85 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// colorModifierHeld is the modifier key's background while held.
+var colorModifierHeld = color.RGBA{60, 110, 200, 255}
+
+var (
+	modifierFaceOnce sync.Once
+	modifierFace     font.Face
+)
+
+// SetModifierKey makes key a deck-wide modifier: while it's held, key and
+// dial events carry module.LayerShift. The key belongs to no module on any
+// page, except that an open overlay still receives it. Must be called
+// before Start.
+func (c *Coordinator) SetModifierKey(key module.KeyID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.modifierKey = key
+}
+
+// layer returns the current modifier layer.
+func (c *Coordinator) layer() module.Layer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.modifierHeld {
+		return module.LayerShift
+	}
+	return module.LayerBase
+}
+
+// isModifierKey reports whether key is the modifier key.
+func (c *Coordinator) isModifierKey(key module.KeyID) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.modifierKey != 0 && key == c.modifierKey
+}
+
+// holdModifier tracks the modifier key until it's released.
+func (c *Coordinator) holdModifier(key module.KeyID, k device.Key) {
+	c.setModifierHeld(true)
+	events.Publish(events.Event{Type: events.TypeKey, Key: int(key), Pressed: events.Bool(true)})
+
+	duration := k.WaitForRelease()
+	c.setModifierHeld(false)
+	events.Publish(events.Event{Type: events.TypeKey, Key: int(key), Pressed: events.Bool(false), DurationMS: duration.Milliseconds()})
+}
+
+func (c *Coordinator) setModifierHeld(held bool) {
+	c.mu.Lock()
+	c.modifierHeld = held
+	c.mu.Unlock()
+	c.requestRender()
+}
+
+// renderModifierKey draws the modifier key, highlighted while held.
+func (c *Coordinator) renderModifierKey() image.Image {
+	bg := render.ColorKeyBg
+	if c.layer() == module.LayerShift {
+		bg = colorModifierHeld
+	}
+	img := render.NewKey(bg)
+
+	modifierFaceOnce.Do(func() {
+		var err error
+		if modifierFace, err = render.NewFace(render.Bold, 18); err != nil {
+			c.logger.Error("Modifier key font", "err", err)
+		}
+	})
+	if modifierFace != nil {
+		render.DrawTextCentered(img, "Fn", render.KeySize/2, render.KeySize/2+7, modifierFace, render.ColorWhite)
+	}
+	return img
+}
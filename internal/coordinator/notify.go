@@ -0,0 +1,174 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// colorNotifyAccent is the default notification accent color.
+var colorNotifyAccent = color.RGBA{60, 140, 255, 255} // Blue
+
+// activeNotification is a notification with its rendered image and expiry.
+type activeNotification struct {
+	img     image.Image
+	expires time.Time
+}
+
+// notifications tracks the notifications currently on screen.
+type notifications struct {
+	mu    sync.Mutex
+	strip *activeNotification
+	keys  map[module.KeyID]*activeNotification
+}
+
+var (
+	notifyFacesOnce sync.Once
+	notifyTextFace  font.Face
+	notifyKeyFace   font.Face
+)
+
+// loadNotifyFaces creates the notification fonts on first use.
+func loadNotifyFaces() {
+	notifyFacesOnce.Do(func() {
+		var err error
+		if notifyTextFace, err = render.NewFace(render.Bold, 30); err != nil {
+			slog.Error("Notification font", "err", err)
+		}
+		if notifyKeyFace, err = render.NewFace(render.Bold, 13); err != nil {
+			slog.Error("Notification font", "err", err)
+		}
+	})
+}
+
+// Notify shows a transient notification on the strip or a key, replacing
+// normal (and overlay) rendering there until it expires. Safe to call from
+// any goroutine.
+func (c *Coordinator) Notify(n module.Notification) {
+	duration := n.Duration
+	if duration <= 0 {
+		duration = module.DefaultNotifyDuration
+	}
+	accent := n.Color
+	if accent == nil {
+		accent = colorNotifyAccent
+	}
+
+	loadNotifyFaces()
+	if notifyTextFace == nil || notifyKeyFace == nil {
+		return
+	}
+
+	var img image.Image
+	switch n.Target {
+	case module.NotifyStrip:
+		if c.stripRect.Empty() {
+			return
+		}
+		img = n.Strip
+		if img == nil {
+			img = renderStripNotification(c.stripRect, n.Text, n.Image, accent)
+		}
+	case module.NotifyKey:
+		img = renderKeyNotification(n.Text, n.Image, accent)
+	}
+
+	active := &activeNotification{img: img, expires: time.Now().Add(duration)}
+	c.notes.mu.Lock()
+	if n.Target == module.NotifyStrip {
+		c.notes.strip = active
+	} else {
+		if c.notes.keys == nil {
+			c.notes.keys = make(map[module.KeyID]*activeNotification)
+		}
+		c.notes.keys[n.Key] = active
+	}
+	c.notes.mu.Unlock()
+
+	c.logger.Debug("Notify", "text", n.Text)
+	c.requestRender()
+}
+
+// stripNotification returns the active strip notification image, or nil.
+func (c *Coordinator) stripNotification() image.Image {
+	c.notes.mu.Lock()
+	defer c.notes.mu.Unlock()
+
+	if c.notes.strip == nil {
+		return nil
+	}
+	if time.Now().After(c.notes.strip.expires) {
+		c.notes.strip = nil
+		return nil
+	}
+	return c.notes.strip.img
+}
+
+// keyNotifications returns the active key notification images. Keys whose
+// notification just expired and that no module owns are cleared, since no
+// module will redraw them.
+func (c *Coordinator) keyNotifications() map[module.KeyID]image.Image {
+	c.notes.mu.Lock()
+	defer c.notes.mu.Unlock()
+
+	now := time.Now()
+	active := make(map[module.KeyID]image.Image)
+	for key, n := range c.notes.keys {
+		if now.After(n.expires) {
+			delete(c.notes.keys, key)
+			if c.keyOwner(key) == nil {
+				c.clearKey(key)
+				delete(c.lastKeyImages, key)
+			}
+			continue
+		}
+		active[key] = n.img
+	}
+	return active
+}
+
+// renderStripNotification draws a full-strip banner: an accent bar, optional
+// icon, and the message.
+func renderStripNotification(rect image.Rectangle, text string, icon image.Image, accent color.Color) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+8, rect.Max.Y), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	textX := rect.Min.X + 30
+	if icon != nil {
+		size := rect.Dy() - 30
+		scaled := render.Scale(icon, size)
+		y := rect.Min.Y + (rect.Dy()-size)/2
+		draw.Draw(img, image.Rect(textX, y, textX+size, y+size), scaled, image.Point{}, draw.Over)
+		textX += size + 20
+	}
+
+	text = render.TruncateText(text, notifyTextFace, rect.Max.X-textX-20)
+	render.DrawText(img, text, textX, rect.Min.Y+rect.Dy()/2+10, notifyTextFace, render.ColorWhite)
+
+	return img
+}
+
+// renderKeyNotification draws a key-sized notification: accent background,
+// optional icon, and the message.
+func renderKeyNotification(text string, icon image.Image, accent color.Color) image.Image {
+	img := render.NewKey(accent)
+
+	if icon == nil {
+		text = render.TruncateText(text, notifyKeyFace, render.KeySize-6)
+		render.DrawTextCentered(img, text, render.KeySize/2, render.KeySize/2+5, notifyKeyFace, render.ColorWhite)
+		return img
+	}
+
+	render.DrawIcon(img, render.Scale(icon, 36), 8)
+	text = render.TruncateText(text, notifyKeyFace, render.KeySize-6)
+	render.DrawTextCentered(img, text, render.KeySize/2, 62, notifyKeyFace, render.ColorWhite)
+	return img
+}
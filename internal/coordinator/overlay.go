@@ -0,0 +1,88 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// overlayState is what the coordinator knows of the overlay showing. Only
+// the render loop touches it, so overlays open, stay up, and expire in one
+// goroutine; input handlers report overlay input through overlayInput.
+type overlayState struct {
+	active bool
+	owner  module.Module // module whose overlay was last shown
+
+	// expiry fires when an OverlayCloser's overlay has gone without input
+	// for its timeout. Stopped while no such overlay is showing.
+	expiry *time.Timer
+}
+
+func newOverlayState() overlayState {
+	return overlayState{expiry: stoppedTimer()}
+}
+
+// noteOverlayInput tells the render loop an overlay handled input, which
+// keeps it open for another timeout. Called from input handlers.
+func (c *Coordinator) noteOverlayInput() {
+	select {
+	case c.overlayInput <- struct{}{}:
+	default:
+	}
+}
+
+// overlayShown records that m's overlay is showing, starting its timeout
+// when it first appears. Render loop only.
+func (c *Coordinator) overlayShown(m module.Module) {
+	if c.overlay.active {
+		if c.overlay.owner == m {
+			return
+		}
+		c.overlayHidden()
+	}
+	c.overlay.active = true
+	c.overlay.owner = m
+	c.armOverlayExpiry()
+	metrics.OverlayActivations.WithLabelValues(m.ID()).Inc()
+	events.Publish(events.Event{Type: events.TypeOverlay, Module: m.ID(), State: "open"})
+}
+
+// overlayHidden records that the overlay closed. Render loop only.
+func (c *Coordinator) overlayHidden() {
+	c.overlay.active = false
+	c.overlay.expiry.Stop()
+	events.Publish(events.Event{Type: events.TypeOverlay, Module: c.overlay.owner.ID(), State: "closed"})
+}
+
+// armOverlayExpiry restarts the showing overlay's timeout, if it has one.
+// Render loop only.
+func (c *Coordinator) armOverlayExpiry() {
+	c.overlay.expiry.Stop()
+	if !c.overlay.active {
+		return
+	}
+	m := c.overlay.owner
+	closer, ok := m.(module.OverlayCloser)
+	if !ok {
+		return
+	}
+	var timeout time.Duration
+	if c.safeCall(m, "OverlayTimeout", func() { timeout = closer.OverlayTimeout() }) && timeout > 0 {
+		c.overlay.expiry.Reset(timeout)
+	}
+}
+
+// expireOverlay closes the showing overlay after its timeout. Render loop
+// only; the next render notices it closed.
+func (c *Coordinator) expireOverlay() {
+	if !c.overlay.active {
+		return
+	}
+	m := c.overlay.owner
+	if closer, ok := m.(module.OverlayCloser); ok {
+		c.logger.Debug("Overlay timed out", "id", m.ID())
+		c.safeCall(m, "CloseOverlay", closer.CloseOverlay)
+	}
+}
@@ -0,0 +1,147 @@
+package coordinator
+
+import (
+	"image"
+
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/state"
+)
+
+// PageID identifies a page of keys. Switching pages changes which modules
+// own the deck's keys; dials and the strip belong to the same modules on
+// every page.
+type PageID int
+
+// RootPage is the page shown at startup, which RegisterModule uses.
+const RootPage PageID = 0
+
+// pageStateKey is where the current page is persisted, so a restart or
+// reconnect returns to it.
+const pageStateKey = "page"
+
+// RestorePage shows the page saved by the last ShowPage, if it still exists.
+// Call after every page is allocated.
+func (c *Coordinator) RestorePage() {
+	var page PageID
+	if state.Load(pageStateKey, &page) {
+		c.ShowPage(page)
+	}
+}
+
+// NewPage allocates a page for RegisterPageModule. Must be called before Start.
+func (c *Coordinator) NewPage() PageID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pageCount++
+	return PageID(c.pageCount)
+}
+
+// ShowPage switches the deck's keys to page. Modules on other pages keep
+// running but aren't asked to render keys or sent key events.
+func (c *Coordinator) ShowPage(page PageID) {
+	c.showPage(page, true)
+}
+
+// showPage switches to page, saving it for RestorePage if save is set.
+func (c *Coordinator) showPage(page PageID, save bool) {
+	c.mu.Lock()
+	if page < 0 || int(page) > c.pageCount || page == c.page {
+		c.mu.Unlock()
+		return
+	}
+	c.page = page
+	c.mu.Unlock()
+	if save {
+		state.Save(pageStateKey, page)
+	}
+
+	c.logger.Debug("Showing page", "page", page)
+	events.Publish(events.Event{Type: events.TypePage, Page: int(page)})
+	c.requestRender()
+}
+
+// SetPageSwipe makes flicking the strip switch pages, left for the next
+// page and right for the previous one, wrapping around. Flicks that switch
+// pages aren't sent to modules; an open overlay still receives them. Must
+// be called before Start.
+func (c *Coordinator) SetPageSwipe(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pageSwipe = on
+}
+
+// swipePage switches pages for a flick from origin to dest, reporting
+// whether it did.
+func (c *Coordinator) swipePage(origin, dest image.Point) bool {
+	c.mu.RLock()
+	on, count, page := c.pageSwipe, c.pageCount, c.page
+	c.mu.RUnlock()
+	if !on || count == 0 || !isFlick(origin, dest) {
+		return false
+	}
+	if _, overlay := c.getActiveOverlay(); overlay != nil {
+		return false
+	}
+	step := 1
+	if dest.X > origin.X {
+		step = -1
+	}
+	c.ShowPage(PageID((int(page) + step + count + 1) % (count + 1)))
+	return true
+}
+
+// pageFollower shows pages chosen for the deck rather than by hand, such as
+// one for the frontmost app, and returns from them (see follow).
+type pageFollower struct {
+	shown PageID // page it last showed, or RootPage if it's showing none
+	back  PageID // page to return to
+}
+
+// follow shows page for f, or returns from f's page to the one before it if
+// page is RootPage. A deck moved off f's page by hand is left where it is.
+// Pages shown this way aren't saved for RestorePage.
+func (c *Coordinator) follow(f *pageFollower, page PageID) {
+	current := c.CurrentPage()
+	if page == RootPage {
+		if f.shown != RootPage && current == f.shown {
+			c.logger.Debug("Returning to page", "page", f.back)
+			c.showPage(f.back, false)
+		}
+		f.shown = RootPage
+		return
+	}
+	if page == current {
+		// Already there, by hand or by another follower
+		if f.shown != current {
+			f.shown = RootPage
+		}
+		return
+	}
+	if f.shown == RootPage || current != f.shown {
+		f.back = current
+	}
+	f.shown = page
+	c.showPage(page, false)
+}
+
+// CurrentPage returns the page whose keys are showing.
+func (c *Coordinator) CurrentPage() PageID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.page
+}
+
+// keyOwner returns the module that owns key on the current page, or nil.
+func (c *Coordinator) keyOwner(key module.KeyID) module.Module {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.keyOwners[c.page][key]
+}
+
+// onCurrentPage reports whether m's keys are on the current page.
+func (c *Coordinator) onCurrentPage(m module.Module) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.modulePages[m] == c.page
+}
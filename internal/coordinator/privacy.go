@@ -0,0 +1,149 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/phinze/belowdeck/internal/state"
+)
+
+// privacyStateKey is where privacy mode is persisted, so restarting
+// belowdeck in the middle of a screen share doesn't reveal anything.
+const privacyStateKey = "private"
+
+// colorPrivate is the privacy key's background while privacy mode is on.
+var colorPrivate = color.RGBA{110, 60, 150, 255}
+
+// SetPrivate turns privacy mode on or off (see module.SetPrivate),
+// remembering it across restarts. Safe to call from any goroutine.
+func SetPrivate(on bool) {
+	if module.Private() == on {
+		return
+	}
+	module.SetPrivate(on)
+	state.Save(privacyStateKey, on)
+
+	s := "off"
+	if on {
+		s = "on"
+	}
+	logging.For("coordinator").Info("Privacy mode changed", "private", on)
+	events.Publish(events.Event{Type: events.TypePrivacy, State: s})
+}
+
+// restorePrivacy turns privacy mode on if it was on when belowdeck last ran.
+func restorePrivacy() {
+	var on bool
+	if state.Load(privacyStateKey, &on) {
+		module.SetPrivate(on)
+	}
+}
+
+// PrivacyHandler serves privacy mode over HTTP: GET reports it as
+// {"private": true}, and POST sets it to the state in a JSON body, on or
+// off, or toggles it if state is empty or toggle, then reports it.
+//
+// A POST must be JSON and must not come from a web page on another origin,
+// so a page open in a browser can't switch privacy mode off: HTML forms
+// can't send JSON, and scripts need a CORS preflight this never answers.
+func PrivacyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			if !sameOrigin(r) {
+				http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+				return
+			}
+			if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+				http.Error(w, "body must be application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+			var body struct {
+				State string `json:"state"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+				http.Error(w, "invalid JSON body", http.StatusBadRequest)
+				return
+			}
+			switch body.State {
+			case "on":
+				SetPrivate(true)
+			case "off":
+				SetPrivate(false)
+			case "", "toggle":
+				SetPrivate(!module.Private())
+			default:
+				http.Error(w, "state must be on, off, or toggle", http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Private bool `json:"private"`
+		}{module.Private()})
+	})
+}
+
+// sameOrigin reports whether r has no Origin header, as from curl or a
+// script, or one naming the host it was sent to.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// SetPrivacyKey makes key turn privacy mode on and off. Like the modifier
+// key, it belongs to no module on any page, except that an open overlay
+// still receives it. Must be called before Start.
+func (c *Coordinator) SetPrivacyKey(key module.KeyID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.privacyKey = key
+}
+
+// isPrivacyKey reports whether key is the privacy key.
+func (c *Coordinator) isPrivacyKey(key module.KeyID) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.privacyKey != 0 && key == c.privacyKey
+}
+
+// pressPrivacyKey toggles privacy mode and waits for the key's release.
+func (c *Coordinator) pressPrivacyKey(key module.KeyID, k device.Key) {
+	events.Publish(events.Event{Type: events.TypeKey, Key: int(key), Pressed: events.Bool(true)})
+	SetPrivate(!module.Private())
+	c.requestRender()
+
+	duration := k.WaitForRelease()
+	events.Publish(events.Event{Type: events.TypeKey, Key: int(key), Pressed: events.Bool(false), DurationMS: duration.Milliseconds()})
+}
+
+// renderPrivacyKey draws the privacy key: an open eye, or a crossed-out one
+// on purple while privacy mode is on.
+func (c *Coordinator) renderPrivacyKey() image.Image {
+	bg, icon := render.ColorKeyBg, "eye"
+	if module.Private() {
+		bg, icon = colorPrivate, "eye-off"
+	}
+	img := render.NewKey(bg)
+	render.DrawIcon(img, render.Icon(icon, 40, render.ColorWhite), (render.KeySize-40)/2)
+	return img
+}
@@ -0,0 +1,119 @@
+package coordinator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// colorDegradedBg is the background of tiles for a module that panicked.
+var colorDegradedBg = color.RGBA{120, 30, 30, 255} // Dark red
+
+var (
+	degradedFacesOnce sync.Once
+	degradedTitleFace font.Face
+	degradedLabelFace font.Face
+)
+
+// loadDegradedFaces creates the error tile fonts on first use.
+func loadDegradedFaces() {
+	degradedFacesOnce.Do(func() {
+		var err error
+		if degradedTitleFace, err = render.NewFace(render.Bold, 28); err != nil {
+			slog.Error("Error tile font", "err", err)
+		}
+		if degradedLabelFace, err = render.NewFace(render.Regular, 12); err != nil {
+			slog.Error("Error tile font", "err", err)
+		}
+	})
+}
+
+// safeCall runs fn, which calls into module m. If fn panics, the panic is
+// logged with its stack, m is marked degraded, and safeCall returns false.
+func (c *Coordinator) safeCall(m module.Module, op string, fn func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.Error("Module panicked", "id", m.ID(), "op", op, "panic", r, "stack", string(debug.Stack()))
+			c.setDegraded(m, fmt.Sprint(r))
+			c.failed(fmt.Sprintf("module %s panicked in %s: %v", m.ID(), op, r))
+			ok = false
+		}
+	}()
+	fn()
+	return true
+}
+
+// OnFailure makes the coordinator call fn, with what went wrong, when a
+// module panics or failedWriteReport device writes in a row fail, as for
+// writing a bug report. Must be called before Start.
+func (c *Coordinator) OnFailure(fn func(reason string)) {
+	c.onFailure = fn
+}
+
+// failed calls the OnFailure func, if any.
+func (c *Coordinator) failed(reason string) {
+	if c.onFailure != nil {
+		c.onFailure(reason)
+	}
+}
+
+// isDegraded reports whether a module has panicked and been taken out of
+// service.
+func (c *Coordinator) isDegraded(m module.Module) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, degraded := c.degradedModules[m]
+	return degraded
+}
+
+// setDegraded takes a module out of service after a panic. It stays degraded
+// until the daemon restarts; its keys and strip region show an error tile.
+func (c *Coordinator) setDegraded(m module.Module, reason string) {
+	c.mu.Lock()
+	c.degradedModules[m] = reason
+	c.mu.Unlock()
+	c.setHealth(m, "degraded", reason)
+	c.requestRender()
+}
+
+// isActive reports whether a module should receive events and render calls.
+func (c *Coordinator) isActive(m module.Module) bool {
+	return !c.isFailed(m) && !c.isDegraded(m)
+}
+
+// renderDegradedKey draws the error tile shown on a degraded module's keys.
+func renderDegradedKey(id string) image.Image {
+	img := render.NewKey(colorDegradedBg)
+	loadDegradedFaces()
+	if degradedTitleFace == nil || degradedLabelFace == nil {
+		return img
+	}
+
+	render.DrawTextCentered(img, "!", render.KeySize/2, 36, degradedTitleFace, render.ColorWhite)
+	label := render.TruncateText(id, degradedLabelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 60, degradedLabelFace, render.ColorWhite)
+	return img
+}
+
+// renderDegradedStrip draws an error banner over a degraded module's strip
+// region. The returned image spans rect, matching module strip output.
+func renderDegradedStrip(rect, region image.Rectangle, id string) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{colorDegradedBg}, image.Point{}, draw.Src)
+	loadDegradedFaces()
+	if degradedLabelFace == nil {
+		return img
+	}
+
+	text := render.TruncateText(id+" stopped after an error", degradedLabelFace, region.Dx()-20)
+	render.DrawTextCentered(img, text, region.Min.X+region.Dx()/2, region.Min.Y+region.Dy()/2+4, degradedLabelFace, render.ColorWhite)
+	return img
+}
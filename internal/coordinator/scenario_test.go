@@ -0,0 +1,572 @@
+package coordinator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/device/fake"
+	"github.com/phinze/belowdeck/internal/events"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+var (
+	red     = color.RGBA{255, 0, 0, 255}
+	green   = color.RGBA{0, 255, 0, 255}
+	blue    = color.RGBA{0, 0, 255, 255}
+	magenta = color.RGBA{255, 0, 255, 255} // overlays
+	blank   = color.RGBA{}
+)
+
+// stripSize matches the fake device's touch strip.
+var stripSize = image.Rect(0, 0, 800, 100)
+
+// A scenario scripts one coordinator session: its modules are registered
+// on a fake device, the coordinator is started, and the steps run in order.
+type scenario struct {
+	name    string
+	modules []*testModule
+	steps   []step
+}
+
+// A step is one input or assertion in a scenario.
+type step struct {
+	desc string
+	run  func(t *testing.T, s *session)
+}
+
+// session is a running scenario.
+type session struct {
+	h       *fake.Harness
+	events  <-chan events.Event
+	modules map[string]*testModule
+}
+
+func TestScenarios(t *testing.T) {
+	for _, sc := range scenarios() {
+		t.Run(sc.name, func(t *testing.T) {
+			runScenario(t, sc)
+		})
+	}
+}
+
+func TestRegisterConflicts(t *testing.T) {
+	c := coordinator.New(fake.New())
+	c.SetModifierKey(module.Key8)
+	first := newTestModule("first", red, []module.KeyID{module.Key1, module.Key2}, []module.DialID{module.Dial1}, image.Rect(0, 0, 400, 100))
+	if err := c.RegisterModule(first, first.res); err != nil {
+		t.Fatalf("registering first: %v", err)
+	}
+
+	second := newTestModule("second", green, []module.KeyID{module.Key2, module.Key3, module.Key8}, []module.DialID{module.Dial1}, image.Rect(300, 0, 800, 100))
+	err := c.RegisterModule(second, second.res)
+	var conflict *coordinator.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("registering second: got %v, want a ConflictError", err)
+	}
+	want := []string{
+		"key 2 is taken by first",
+		"key 8 is the modifier key",
+		"dial 1 is taken by first",
+		"strip x=300-800 overlaps first's x=0-400",
+	}
+	if !slices.Equal(conflict.Conflicts, want) {
+		t.Errorf("conflicts = %q, want %q", conflict.Conflicts, want)
+	}
+
+	// Keys are per page, so another page may reuse them
+	third := newTestModule("third", blue, []module.KeyID{module.Key1}, nil, image.Rectangle{})
+	if err := c.RegisterPageModule(c.NewPage(), third, third.res); err != nil {
+		t.Errorf("registering on a folder page: %v", err)
+	}
+}
+
+func runScenario(t *testing.T, sc scenario) {
+	h := fake.NewHarness(t)
+	s := &session{h: h, modules: make(map[string]*testModule)}
+	for _, m := range sc.modules {
+		s.modules[m.ID()] = m
+		h.Register(m, m.res)
+	}
+
+	// Subscribe first so module state events from Start are seen
+	ch, unsubscribe := events.Subscribe()
+	t.Cleanup(unsubscribe)
+	s.events = ch
+
+	h.Start()
+	for i, st := range sc.steps {
+		t.Logf("step %d: %s", i+1, st.desc)
+		st.run(t, s)
+		if t.Failed() {
+			t.FailNow()
+		}
+	}
+}
+
+// scenarios returns the scenarios to run, with fresh modules each call.
+func scenarios() []scenario {
+	return []scenario{
+		{
+			name: "routes input to owners",
+			modules: []*testModule{
+				newTestModule("a", red, []module.KeyID{module.Key1}, []module.DialID{module.Dial1}, image.Rect(0, 0, 200, 100)),
+				newTestModule("b", green, []module.KeyID{module.Key2}, []module.DialID{module.Dial2}, image.Rect(200, 0, 400, 100)),
+			},
+			steps: []step{
+				pressKey(module.Key1),
+				rotateDial(module.Dial2, -2),
+				pressDial(module.Dial1),
+				tapStrip(250),
+				pressKey(module.Key5), // unowned
+				expectHandled("a", "key 1 press", "key 1 release", "dial 1 press", "dial 1 release"),
+				expectHandled("b", "dial 2 rotate -2", "strip tap 250"),
+				expectEvent(events.Event{Type: events.TypeKey, Key: 5, Pressed: events.Bool(true)}),
+			},
+		},
+		{
+			name: "overlay takes over input and display",
+			modules: []*testModule{
+				newTestModule("a", red, []module.KeyID{module.Key1}, []module.DialID{module.Dial1}, image.Rect(0, 0, 200, 100)),
+				newTestModule("b", green, []module.KeyID{module.Key2}, []module.DialID{module.Dial2}, image.Rect(200, 0, 400, 100)),
+			},
+			steps: []step{
+				expectKey(module.Key2, green),
+				openOverlay("a"),
+				expectEvent(events.Event{Type: events.TypeOverlay, Module: "a", State: "open"}),
+				expectKey(module.Key2, magenta),
+				expectKey(module.Key8, magenta),
+				expectStrip(300, magenta),
+				pressKey(module.Key2),
+				rotateDial(module.Dial2, 1),
+				tapStrip(300),
+				expectHandled("a", "overlay key 2 press", "overlay key 2 release", "overlay dial 2 rotate 1", "overlay strip tap 300"),
+				expectHandled("b"),
+				closeOverlay("a"),
+				expectEvent(events.Event{Type: events.TypeOverlay, Module: "a", State: "closed"}),
+				expectKey(module.Key2, green),
+				expectKey(module.Key8, blank),
+				pressKey(module.Key2),
+				expectHandled("b", "key 2 press", "key 2 release"),
+			},
+		},
+		{
+			name: "closes overlay after its timeout",
+			modules: []*testModule{
+				expiring(newTestModule("a", red, []module.KeyID{module.Key1}, nil, image.Rectangle{}), 300*time.Millisecond),
+				newTestModule("b", green, []module.KeyID{module.Key2}, nil, image.Rectangle{}),
+			},
+			steps: []step{
+				openOverlay("a"),
+				expectKey(module.Key2, magenta),
+				pressKey(module.Key2),
+				expectHandled("a", "overlay key 2 press", "overlay key 2 release"),
+				expectEvent(events.Event{Type: events.TypeOverlay, Module: "a", State: "closed"}),
+				expectKey(module.Key2, green),
+				expectOverlay("a", false),
+			},
+		},
+		{
+			name: "skips failed modules",
+			modules: []*testModule{
+				newTestModule("ok", green, []module.KeyID{module.Key1}, []module.DialID{module.Dial1}, image.Rect(0, 0, 200, 100)),
+				failing(newTestModule("broken", red, []module.KeyID{module.Key2}, []module.DialID{module.Dial2}, image.Rect(200, 0, 400, 100))),
+			},
+			steps: []step{
+				expectEvent(events.Event{Type: events.TypeModuleState, Module: "broken", State: "failed", Reason: "no backend"}),
+				expectKey(module.Key1, green),
+				expectStrip(100, green),
+				expectKeyNot(module.Key2, red),
+				expectStrip(300, blank),
+				pressKey(module.Key2),
+				rotateDial(module.Dial2, 1),
+				tapStrip(300),
+				expectHandled("broken"),
+				pressKey(module.Key1),
+				expectHandled("ok", "key 1 press", "key 1 release"),
+			},
+		},
+		{
+			name: "composites strip regions",
+			modules: []*testModule{
+				newTestModule("left", red, nil, nil, image.Rect(0, 0, 200, 100)),
+				newTestModule("middle", green, nil, nil, image.Rect(200, 0, 400, 100)),
+				newTestModule("keys", blue, []module.KeyID{module.Key3, module.Key4}, nil, image.Rectangle{}),
+			},
+			steps: []step{
+				expectStrip(10, red),
+				expectStrip(199, red),
+				expectStrip(200, green),
+				expectStrip(399, green),
+				expectStrip(600, blank),
+				expectKey(module.Key3, blue),
+				expectKey(module.Key4, blue),
+				recolor("middle", blue),
+				expectStrip(300, blue),
+				expectStrip(100, red),
+			},
+		},
+		{
+			name: "steps through animated keys",
+			modules: []*testModule{
+				animated(newTestModule("spinner", red, []module.KeyID{module.Key1}, nil, image.Rectangle{}), blue, 100*time.Millisecond),
+			},
+			steps: []step{
+				expectKey(module.Key1, red),
+				expectKeyWithin(module.Key1, blue, 250*time.Millisecond),
+				expectKeyWithin(module.Key1, red, 250*time.Millisecond),
+			},
+		},
+	}
+}
+
+// Input steps. Injection is synchronous, so modules have handled the input
+// by the time the step ends.
+
+func pressKey(key module.KeyID) step {
+	return step{fmt.Sprintf("press key %d", key), func(t *testing.T, s *session) {
+		if err := s.h.Device.InjectKeyPress(device.KeyID(key), 0); err != nil {
+			t.Errorf("InjectKeyPress: %v", err)
+		}
+	}}
+}
+
+func pressDial(dial module.DialID) step {
+	return step{fmt.Sprintf("press dial %d", dial), func(t *testing.T, s *session) {
+		if err := s.h.Device.InjectDialPress(device.DialID(dial), 0); err != nil {
+			t.Errorf("InjectDialPress: %v", err)
+		}
+	}}
+}
+
+func rotateDial(dial module.DialID, delta int8) step {
+	return step{fmt.Sprintf("rotate dial %d by %d", dial, delta), func(t *testing.T, s *session) {
+		if err := s.h.Device.InjectDialRotate(device.DialID(dial), delta); err != nil {
+			t.Errorf("InjectDialRotate: %v", err)
+		}
+	}}
+}
+
+func tapStrip(x int) step {
+	return step{fmt.Sprintf("tap strip at %d", x), func(t *testing.T, s *session) {
+		if err := s.h.Device.InjectStripTouch(device.TOUCH_STRIP_TOUCH_TYPE_SHORT, image.Pt(x, 50)); err != nil {
+			t.Errorf("InjectStripTouch: %v", err)
+		}
+	}}
+}
+
+// Module steps.
+
+func openOverlay(id string) step {
+	return step{"open overlay of " + id, func(t *testing.T, s *session) {
+		s.module(t, id).setOverlay(true)
+	}}
+}
+
+func closeOverlay(id string) step {
+	return step{"close overlay of " + id, func(t *testing.T, s *session) {
+		s.module(t, id).setOverlay(false)
+	}}
+}
+
+func recolor(id string, c color.RGBA) step {
+	return step{fmt.Sprintf("recolor %s to %v", id, c), func(t *testing.T, s *session) {
+		s.module(t, id).setColor(c)
+	}}
+}
+
+// Assertion steps. Rendering is asynchronous, so image assertions wait for
+// the render loop to catch up.
+
+func expectKey(key module.KeyID, want color.RGBA) step {
+	return step{fmt.Sprintf("key %d shows %v", key, want), func(t *testing.T, s *session) {
+		s.h.WaitForKey(key, func(img *image.RGBA) bool {
+			return img.RGBAAt(36, 36) == want
+		})
+	}}
+}
+
+// expectKeyWithin checks that key shows want within d, sooner than the
+// render loop's ticker would redraw it.
+func expectKeyWithin(key module.KeyID, want color.RGBA, d time.Duration) step {
+	return step{fmt.Sprintf("key %d shows %v within %v", key, want, d), func(t *testing.T, s *session) {
+		start := time.Now()
+		s.h.WaitForKey(key, func(img *image.RGBA) bool {
+			return img.RGBAAt(36, 36) == want
+		})
+		if took := time.Since(start); took > d {
+			t.Errorf("key %d took %v to show %v", key, took, want)
+		}
+	}}
+}
+
+// expectKeyNot checks key once others have rendered, so it should follow a
+// step that waits for the render loop.
+func expectKeyNot(key module.KeyID, unwanted color.RGBA) step {
+	return step{fmt.Sprintf("key %d doesn't show %v", key, unwanted), func(t *testing.T, s *session) {
+		if img := s.h.Device.KeyImage(device.KeyID(key)); img != nil && img.RGBAAt(36, 36) == unwanted {
+			t.Errorf("key %d shows %v", key, unwanted)
+		}
+	}}
+}
+
+func expectStrip(x int, want color.RGBA) step {
+	return step{fmt.Sprintf("strip at %d shows %v", x, want), func(t *testing.T, s *session) {
+		s.h.WaitFor(fmt.Sprintf("strip at %d to show %v", x, want), func() bool {
+			img := s.h.Device.StripImage()
+			return img != nil && img.RGBAAt(x, 50) == want
+		})
+	}}
+}
+
+// expectOverlay checks whether module id's overlay is open.
+func expectOverlay(id string, open bool) step {
+	return step{fmt.Sprintf("%s overlay open is %v", id, open), func(t *testing.T, s *session) {
+		if got := s.module(t, id).IsOverlayActive(); got != open {
+			t.Errorf("%s overlay open is %v, want %v", id, got, open)
+		}
+	}}
+}
+
+// expectHandled checks the inputs module id has handled since the last
+// check, in order.
+func expectHandled(id string, want ...string) step {
+	return step{fmt.Sprintf("%s handled %q", id, want), func(t *testing.T, s *session) {
+		if got := s.module(t, id).takeHandled(); !slices.Equal(got, want) {
+			t.Errorf("%s handled %q, want %q", id, got, want)
+		}
+	}}
+}
+
+// expectEvent waits for a published event matching want's non-zero fields,
+// skipping others.
+func expectEvent(want events.Event) step {
+	return step{fmt.Sprintf("event %s %s %s", want.Type, want.Module, want.State), func(t *testing.T, s *session) {
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case e := <-s.events:
+				if matches(e, want) {
+					return
+				}
+			case <-timeout:
+				t.Errorf("timed out waiting for %+v", want)
+				return
+			}
+		}
+	}}
+}
+
+func matches(e, want events.Event) bool {
+	if e.Type != want.Type || (want.Module != "" && e.Module != want.Module) {
+		return false
+	}
+	if want.State != "" && e.State != want.State || want.Reason != "" && e.Reason != want.Reason {
+		return false
+	}
+	if want.Key != 0 && e.Key != want.Key || want.Dial != 0 && e.Dial != want.Dial {
+		return false
+	}
+	return want.Pressed == nil || e.Pressed != nil && *e.Pressed == *want.Pressed
+}
+
+func (s *session) module(t *testing.T, id string) *testModule {
+	t.Helper()
+	m, ok := s.modules[id]
+	if !ok {
+		t.Fatalf("no module %q in scenario", id)
+	}
+	return m
+}
+
+// testModule fills its keys and strip region with a color, can show an
+// overlay, and records the input it handles.
+type testModule struct {
+	module.BaseModule
+	res            module.Resources
+	initErr        error
+	overlayTimeout time.Duration // 0 keeps the overlay open
+	altColor       color.RGBA    // second frame of animated keys
+	frameDelay     time.Duration // 0 draws still keys
+
+	mu      sync.Mutex
+	color   color.RGBA
+	overlay bool
+	handled []string
+}
+
+func newTestModule(id string, c color.RGBA, keys []module.KeyID, dials []module.DialID, strip image.Rectangle) *testModule {
+	return &testModule{
+		BaseModule: module.NewBaseModule(id),
+		res:        module.Resources{Keys: keys, Dials: dials, StripRect: strip},
+		color:      c,
+	}
+}
+
+// failing makes m fail every Init.
+func failing(m *testModule) *testModule {
+	m.initErr = errors.New("no backend")
+	return m
+}
+
+// expiring makes m's overlay close after timeout without input.
+func expiring(m *testModule, timeout time.Duration) *testModule {
+	m.overlayTimeout = timeout
+	return m
+}
+
+// animated makes m's keys alternate with alt, each color showing for delay.
+func animated(m *testModule, alt color.RGBA, delay time.Duration) *testModule {
+	m.altColor = alt
+	m.frameDelay = delay
+	return m
+}
+
+func (m *testModule) Init(ctx context.Context, res module.Resources) error {
+	if m.initErr != nil {
+		return m.initErr
+	}
+	return m.BaseModule.Init(ctx, res)
+}
+
+func (m *testModule) RenderKeys() map[module.KeyID]image.Image {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	imgs := make(map[module.KeyID]image.Image)
+	for _, key := range m.res.Keys {
+		imgs[key] = solid(image.Rect(0, 0, 72, 72), m.color)
+		if m.frameDelay > 0 {
+			frames := []image.Image{imgs[key], solid(image.Rect(0, 0, 72, 72), m.altColor)}
+			imgs[key] = render.NewAnimation(frames, m.frameDelay)
+		}
+	}
+	return imgs
+}
+
+func (m *testModule) RenderStrip() image.Image {
+	if !m.res.HasStrip() {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	img := image.NewRGBA(stripSize)
+	draw.Draw(img, m.res.StripRect, image.NewUniform(m.color), image.Point{}, draw.Src)
+	return img
+}
+
+func (m *testModule) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	m.record("key %d %s", id, pressOrRelease(event.Pressed))
+	return nil
+}
+
+func (m *testModule) HandleDial(id module.DialID, event module.DialEvent) error {
+	m.record("dial %d %s", id, dialAction(event))
+	return nil
+}
+
+func (m *testModule) HandleStripTouch(event module.TouchStripEvent) error {
+	m.record("strip tap %d", event.Point.X)
+	return nil
+}
+
+func (m *testModule) IsOverlayActive() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.overlay
+}
+
+func (m *testModule) RenderOverlayKeys() map[module.KeyID]image.Image {
+	imgs := make(map[module.KeyID]image.Image)
+	for key := module.Key1; key <= module.Key8; key++ {
+		imgs[key] = solid(image.Rect(0, 0, 72, 72), magenta)
+	}
+	return imgs
+}
+
+func (m *testModule) RenderOverlayStrip() image.Image {
+	return solid(stripSize, magenta)
+}
+
+func (m *testModule) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	m.record("overlay key %d %s", id, pressOrRelease(event.Pressed))
+	return nil
+}
+
+func (m *testModule) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
+	m.record("overlay dial %d %s", id, dialAction(event))
+	return nil
+}
+
+func (m *testModule) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	m.record("overlay strip tap %d", event.Point.X)
+	return nil
+}
+
+func (m *testModule) OverlayTimeout() time.Duration {
+	return m.overlayTimeout
+}
+
+func (m *testModule) CloseOverlay() {
+	m.setOverlay(false)
+}
+
+func (m *testModule) setOverlay(open bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.overlay = open
+}
+
+func (m *testModule) setColor(c color.RGBA) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.color = c
+}
+
+func (m *testModule) record(format string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handled = append(m.handled, fmt.Sprintf(format, args...))
+}
+
+// takeHandled returns and forgets the input handled so far.
+func (m *testModule) takeHandled() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	handled := m.handled
+	m.handled = nil
+	return handled
+}
+
+func pressOrRelease(pressed bool) string {
+	if pressed {
+		return "press"
+	}
+	return "release"
+}
+
+func dialAction(event module.DialEvent) string {
+	switch event.Type {
+	case module.DialRotate:
+		return fmt.Sprintf("rotate %d", event.Delta)
+	case module.DialPress:
+		return "press"
+	default:
+		return "release"
+	}
+}
+
+func solid(r image.Rectangle, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(r)
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
@@ -0,0 +1,58 @@
+package coordinator
+
+import (
+	"context"
+	"time"
+)
+
+// schedulePage is a page shown while active reports true.
+type schedulePage struct {
+	page   PageID
+	active func(time.Time) bool
+}
+
+// SetSchedulePage shows page while active reports that the time is in its
+// schedule, checked at the start of every minute, and goes back to the page
+// before it when the time is up, unless the deck was switched to another
+// page meanwhile. The first page set that's active wins. Must be called
+// before Start.
+func (c *Coordinator) SetSchedulePage(page PageID, active func(time.Time) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedulePages = append(c.schedulePages, schedulePage{page: page, active: active})
+}
+
+// scheduledPage returns the page scheduled for t, or RootPage if there's
+// none.
+func (c *Coordinator) scheduledPage(t time.Time) PageID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, sp := range c.schedulePages {
+		if sp.active(t) {
+			return sp.page
+		}
+	}
+	return RootPage
+}
+
+// watchSchedule follows the pages set by SetSchedulePage until ctx is
+// done.
+func (c *Coordinator) watchSchedule(ctx context.Context) {
+	defer c.wg.Done()
+
+	var follow pageFollower
+	scheduled := RootPage
+	for {
+		now := time.Now()
+		if page := c.scheduledPage(now); page != scheduled {
+			scheduled = page
+			c.logger.Debug("Scheduled page changed", "page", page)
+			c.follow(&follow, page)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package coordinator
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// screensaverPoll is how often the screensaver checks how long the deck
+// has gone untouched.
+const screensaverPoll = 5 * time.Second
+
+// screensaver dims the deck once it's gone untouched for a while.
+type screensaver struct {
+	mu        sync.Mutex
+	after     time.Duration // 0 if none
+	strip     byte          // strip brightness while on
+	keep      []module.KeyID
+	level     byte // brightness to return the strip to
+	lastInput time.Time
+	on        bool
+}
+
+// SetScreensaver starts the screensaver once the deck has gone untouched
+// for after: the strip dims to strip percent, and every key but keep goes
+// dark. Devices that light the strip separately dim it themselves, keeping
+// the keys in keep at full brightness; on the rest its image is darkened
+// instead. level is the deck's brightness, which the strip returns to
+// unless the brightness dial has changed it. The next input wakes the
+// deck and goes no further. Must be called before Start.
+func (c *Coordinator) SetScreensaver(after time.Duration, strip byte, keep []module.KeyID, level byte) {
+	c.saver.mu.Lock()
+	defer c.saver.mu.Unlock()
+	c.saver.after, c.saver.strip, c.saver.keep, c.saver.level = after, strip, keep, level
+}
+
+// screensaverSet reports whether there's a screensaver.
+func (c *Coordinator) screensaverSet() bool {
+	c.saver.mu.Lock()
+	defer c.saver.mu.Unlock()
+	return c.saver.after > 0
+}
+
+// screensaverOn reports whether the screensaver is running.
+func (c *Coordinator) screensaverOn() bool {
+	c.saver.mu.Lock()
+	defer c.saver.mu.Unlock()
+	return c.saver.on
+}
+
+// wake notes input, reporting whether it woke the deck from the
+// screensaver, in which case the input should go no further.
+func (c *Coordinator) wake() bool {
+	c.saver.mu.Lock()
+	c.saver.lastInput = time.Now()
+	on := c.saver.on
+	c.saver.mu.Unlock()
+
+	if on {
+		c.setScreensaver(false)
+	}
+	return on
+}
+
+// watchScreensaver starts the screensaver whenever the deck has gone
+// untouched long enough, until ctx is done.
+func (c *Coordinator) watchScreensaver(ctx context.Context) {
+	defer c.wg.Done()
+
+	c.saver.mu.Lock()
+	c.saver.lastInput = time.Now()
+	c.saver.mu.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(screensaverPoll):
+		}
+		c.saver.mu.Lock()
+		idle := !c.saver.on && time.Since(c.saver.lastInput) >= c.saver.after
+		c.saver.mu.Unlock()
+		if idle {
+			c.setScreensaver(true)
+		}
+	}
+}
+
+// setScreensaver starts or stops the screensaver.
+func (c *Coordinator) setScreensaver(on bool) {
+	c.saver.mu.Lock()
+	if c.saver.on == on {
+		c.saver.mu.Unlock()
+		return
+	}
+	c.saver.on = on
+	level := c.saver.strip
+	if !on {
+		level = c.saver.level
+	}
+	c.saver.mu.Unlock()
+
+	if !on {
+		c.mu.RLock()
+		if b := c.brightness; b != nil {
+			level = byte(b.level)
+		}
+		c.mu.RUnlock()
+	}
+	if c.device.GetRegionBrightnessSupported() {
+		if err := c.device.SetStripBrightness(level); err != nil {
+			c.logger.Warn("Failed to set strip brightness", "level", level, "err", err)
+		}
+	}
+	c.logger.Debug("Screensaver", "on", on)
+
+	// Every key and the strip are drawn again, dark or not
+	c.rewriteFaces.Store(true)
+	c.requestRender()
+}
+
+// screensaverKey returns what key shows for img: img itself, unless the
+// screensaver is running and key isn't one it keeps lit.
+func (c *Coordinator) screensaverKey(key module.KeyID, img image.Image) image.Image {
+	c.saver.mu.Lock()
+	dark := c.saver.on && !slices.Contains(c.saver.keep, key)
+	c.saver.mu.Unlock()
+	if !dark || img == nil {
+		return img
+	}
+	black := image.NewRGBA(img.Bounds())
+	draw.Draw(black, black.Rect, image.Black, image.Point{}, draw.Src)
+	return black
+}
+
+// screensaverStrip returns what the strip shows for img: img darkened to
+// the screensaver's strip brightness while it runs on a device that can't
+// dim the strip itself, or img.
+func (c *Coordinator) screensaverStrip(img image.Image) image.Image {
+	c.saver.mu.Lock()
+	on, strip := c.saver.on, c.saver.strip
+	c.saver.mu.Unlock()
+	if !on || img == nil || c.device.GetRegionBrightnessSupported() {
+		return img
+	}
+	b := img.Bounds()
+	dim := image.NewRGBA(b)
+	draw.Draw(dim, b, img, b.Min, draw.Src)
+	shade := &image.Uniform{color.Alpha{uint8(255 * (100 - int(strip)) / 100)}}
+	draw.DrawMask(dim, b, image.Black, image.Point{}, shade, image.Point{}, draw.Over)
+	return dim
+}
@@ -0,0 +1,161 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+)
+
+// Task display timing.
+const (
+	taskFrame      = 125 * time.Millisecond // spinner animation step
+	taskGlyphShown = time.Second            // how long the result glyph stays
+)
+
+// Task glyph colors.
+var (
+	colorTaskOK   = color.RGBA{60, 170, 90, 255}
+	colorTaskFail = color.RGBA{200, 60, 60, 255}
+)
+
+// keyTask is a task's state on a key.
+type keyTask struct {
+	state module.TaskState
+	since time.Time
+}
+
+// KeyTask implements module.Notifier, drawing a task's state over key.
+func (c *Coordinator) KeyTask(key module.KeyID, state module.TaskState) {
+	c.mu.Lock()
+	c.tasks[key] = keyTask{state: state, since: time.Now()}
+	start := !c.animatingTasks
+	c.animatingTasks = true
+	c.mu.Unlock()
+
+	c.requestRender()
+	if start {
+		go c.animateTasks()
+	}
+}
+
+// animateTasks renders every frame while any task is showing.
+func (c *Coordinator) animateTasks() {
+	ticker := time.NewTicker(taskFrame)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.done():
+			return
+		}
+
+		c.mu.Lock()
+		if len(c.tasks) == 0 {
+			c.animatingTasks = false
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+		c.requestRender()
+	}
+}
+
+// done returns a channel closed when the coordinator stops, or nil before Start.
+func (c *Coordinator) done() <-chan struct{} {
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Done()
+}
+
+// activeTasks returns the tasks to draw, dropping results shown long enough.
+func (c *Coordinator) activeTasks() map[module.KeyID]keyTask {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	active := make(map[module.KeyID]keyTask)
+	for key, t := range c.tasks {
+		if t.state != module.TaskRunning && now.Sub(t.since) > taskGlyphShown {
+			delete(c.tasks, key)
+			continue
+		}
+		active[key] = t
+	}
+	return active
+}
+
+// renderTask draws t over a module's key image: the image dimmed under a
+// spinner while running, or a check mark or cross badge when done.
+func renderTask(src image.Image, t keyTask, now time.Time) image.Image {
+	b := src.Bounds()
+	img := image.NewRGBA(b)
+	draw.Draw(img, b, src, b.Min, draw.Src)
+	cx := b.Min.X + b.Dx()/2
+	cy := b.Min.Y + b.Dy()/2
+
+	if t.state == module.TaskRunning {
+		draw.Draw(img, b, &image.Uniform{color.RGBA{0, 0, 0, 150}}, image.Point{}, draw.Over)
+
+		// A ring of dots whose bright head goes around once a second
+		const dots = 8
+		head := int(now.Sub(t.since)/taskFrame) % dots
+		for i := range dots {
+			age := (head - i + dots) % dots
+			v := uint8(255 - age*28)
+			a := 2 * math.Pi * float64(i) / dots
+			x := cx + int(16*math.Sin(a))
+			y := cy - int(16*math.Cos(a))
+			fillCircle(img, x, y, 3, color.RGBA{v, v, v, 255})
+		}
+		return img
+	}
+
+	bg, fg := colorTaskOK, render.ColorWhite
+	if t.state == module.TaskFailed {
+		bg = colorTaskFail
+	}
+	fillCircle(img, cx, cy, 18, bg)
+	if t.state == module.TaskFailed {
+		drawLine(img, cx-7, cy-7, cx+7, cy+7, 3, fg)
+		drawLine(img, cx-7, cy+7, cx+7, cy-7, 3, fg)
+	} else {
+		drawLine(img, cx-8, cy, cx-3, cy+6, 3, fg)
+		drawLine(img, cx-3, cy+6, cx+8, cy-6, 3, fg)
+	}
+	return img
+}
+
+// fillCircle fills a circle of radius r centered at (cx, cy).
+func fillCircle(img *image.RGBA, cx, cy, r int, col color.Color) {
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				img.Set(cx+x, cy+y, col)
+			}
+		}
+	}
+}
+
+// drawLine draws a line of the given width from (x0, y0) to (x1, y1).
+func drawLine(img *image.RGBA, x0, y0, x1, y1, width int, col color.Color) {
+	steps := max(abs(x1-x0), abs(y1-y0))
+	for i := 0; i <= steps; i++ {
+		x := x0 + (x1-x0)*i/max(steps, 1)
+		y := y0 + (y1-y0)*i/max(steps, 1)
+		fillCircle(img, x, y, width/2, col)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package coordinator
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"image"
+
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// stripFace is the key under which the whole strip's fingerprint is kept
+// in faces.
+const stripFace module.KeyID = 0
+
+// faceSeed seeds image fingerprints.
+var faceSeed = maphash.MakeSeed()
+
+// unchanged reports whether img is what key (or stripFace) last had
+// written, remembering it as written if not. Most keys look the same pass
+// after pass, and converting and sending an image the deck already shows
+// is most of what a render pass costs. Render loop only.
+func (c *Coordinator) unchanged(key module.KeyID, img image.Image) bool {
+	if c.rewriteFaces.Swap(false) {
+		clear(c.faces)
+	}
+	sum, ok := fingerprint(img)
+	if !ok {
+		delete(c.faces, key)
+		return false
+	}
+	if last, ok := c.faces[key]; ok && last == sum {
+		metrics.SkippedWrites.Inc()
+		return true
+	}
+	c.faces[key] = sum
+	return false
+}
+
+// forgetFace notes that key (or stripFace) no longer shows a known image.
+// Render loop only.
+func (c *Coordinator) forgetFace(key module.KeyID) {
+	delete(c.faces, key)
+}
+
+// fingerprint hashes img's size and pixels. It reports false for image
+// types whose pixels it can't read directly, which are always written.
+func fingerprint(img image.Image) (uint64, bool) {
+	var format byte
+	var pix []uint8
+	var stride int
+	var r image.Rectangle
+	switch im := img.(type) {
+	case *image.RGBA:
+		format, pix, stride, r = 'R', im.Pix, im.Stride, im.Rect
+	case *image.NRGBA:
+		format, pix, stride, r = 'N', im.Pix, im.Stride, im.Rect
+	default:
+		return 0, false
+	}
+
+	var h maphash.Hash
+	h.SetSeed(faceSeed)
+	h.WriteByte(format)
+	var size [8]byte
+	binary.LittleEndian.PutUint32(size[:4], uint32(r.Dx()))
+	binary.LittleEndian.PutUint32(size[4:], uint32(r.Dy()))
+	h.Write(size[:])
+	for y := 0; y < r.Dy(); y++ {
+		h.Write(pix[y*stride : y*stride+r.Dx()*4])
+	}
+	return h.Sum64(), true
+}
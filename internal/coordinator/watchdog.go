@@ -0,0 +1,79 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/metrics"
+)
+
+// Watchdog timing: a render pass or device write, which normally takes
+// milliseconds, is stalled once it has run for stallTimeout.
+const (
+	watchdogInterval = time.Second
+	stallTimeout     = 10 * time.Second
+)
+
+// ErrRenderStalled is returned by Start when a render pass or device write
+// stops making progress, most often a write that never returns. The
+// connection should be closed and reopened; closing it is what unblocks the
+// write.
+var ErrRenderStalled = errors.New("render loop stalled")
+
+// activity is work the watchdog times.
+type activity struct {
+	start time.Time
+	what  string // e.g. "writing key 3"
+}
+
+// track records in a that what has started, returning a func that records
+// its end.
+func (c *Coordinator) track(a *atomic.Pointer[activity], what string) func() {
+	a.Store(&activity{start: time.Now(), what: what})
+	return func() { a.Store(nil) }
+}
+
+// watchRender checks that render passes and device writes finish until ctx
+// is done. When one has run for stallTimeout it logs what it was and every
+// goroutine's stack, and returns an error wrapping ErrRenderStalled.
+func (c *Coordinator) watchRender(ctx context.Context) error {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		for _, a := range []*activity{c.rendering.Load(), c.writing.Load()} {
+			if a == nil || time.Since(a.start) < stallTimeout {
+				continue
+			}
+			metrics.RenderStalls.Inc()
+			c.logger.Error("Render loop stalled, resetting the device", "stage", a.what, "for", time.Since(a.start).Round(time.Second))
+			buf := make([]byte, 1<<20)
+			buf = buf[:runtime.Stack(buf, true)]
+			c.logger.Warn("Goroutines at stall", "stacks", string(buf))
+			return fmt.Errorf("%w while %s", ErrRenderStalled, a.what)
+		}
+	}
+}
+
+// awaitRenderLoop waits for the last connection's render and write loops to
+// finish, which one stuck in a device write does once the device is closed. It
+// returns an error wrapping ErrRenderStalled if it hasn't by stallTimeout.
+func (c *Coordinator) awaitRenderLoop() error {
+	if c.renderDone == nil {
+		return nil
+	}
+	select {
+	case <-c.renderDone:
+		return nil
+	case <-time.After(stallTimeout):
+		return fmt.Errorf("%w: the last connection's render loop never finished", ErrRenderStalled)
+	}
+}
@@ -0,0 +1,164 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// writeGap paces device writes, leaving the HID pipe room between them so a
+// burst of images doesn't hold up key and dial input.
+const writeGap = 2 * time.Millisecond
+
+// failedWriteReport is how many device writes in a row fail before the
+// deck counts as failing (see OnFailure).
+const failedWriteReport = 50
+
+// deviceWrite is an image waiting to be written to the device.
+type deviceWrite struct {
+	key  module.KeyID    // 0 for the strip
+	img  image.Image     // nil clears the key
+	rect image.Rectangle // part of the strip img covers; empty for all of it
+}
+
+// keyImage reports whether w sets a key's image, which can be batched.
+func (w deviceWrite) keyImage() bool {
+	return w.key != 0 && w.img != nil
+}
+
+// describe describes a batch of writes for the watchdog.
+func describe(batch []deviceWrite) string {
+	if batch[0].key == 0 {
+		return "writing the strip"
+	}
+	keys := make([]string, len(batch))
+	for i, w := range batch {
+		keys[i] = strconv.Itoa(int(w.key))
+	}
+	if len(keys) == 1 {
+		return "writing key " + keys[0]
+	}
+	return "writing keys " + strings.Join(keys, ", ")
+}
+
+// writeQueue holds device writes in the order they were queued, keeping
+// only the newest image for each key, so a burst such as an overlay opening
+// doesn't write a key more than once however often it's redrawn.
+type writeQueue struct {
+	mu      sync.Mutex
+	pending []deviceWrite
+	ready   chan struct{} // signalled when pending gains a write
+}
+
+func newWriteQueue() *writeQueue {
+	return &writeQueue{ready: make(chan struct{}, 1)}
+}
+
+// push queues w, replacing a pending write it supersedes: one to the same
+// key or strip region, or any strip write if w redraws the whole strip.
+func (q *writeQueue) push(w deviceWrite) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if w.key == 0 && w.rect.Empty() {
+		n := len(q.pending)
+		q.pending = slices.DeleteFunc(q.pending, func(p deviceWrite) bool { return p.key == 0 })
+		metrics.CoalescedWrites.Add(float64(n - len(q.pending)))
+	} else if i := slices.IndexFunc(q.pending, func(p deviceWrite) bool { return p.key == w.key && p.rect == w.rect }); i >= 0 {
+		q.pending[i] = w
+		metrics.CoalescedWrites.Inc()
+		return
+	}
+	q.pending = append(q.pending, w)
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pop takes the oldest pending write, along with the key images queued
+// right after it if it's a key image too, or returns nil if there's none.
+func (q *writeQueue) pop() []deviceWrite {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil
+	}
+	n := 1
+	for n < len(q.pending) && q.pending[0].keyImage() && q.pending[n].keyImage() {
+		n++
+	}
+	batch := slices.Clone(q.pending[:n])
+	q.pending = q.pending[n:]
+	return batch
+}
+
+// reset drops the pending writes, which a reconnected device gets redrawn
+// from scratch anyway.
+func (q *writeQueue) reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = nil
+}
+
+// writeLoop writes queued images to the device, paced by writeGap, until
+// ctx is done.
+func (c *Coordinator) writeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.writes.ready:
+		}
+		for batch := c.writes.pop(); batch != nil; batch = c.writes.pop() {
+			c.write(batch)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(writeGap):
+			}
+		}
+	}
+}
+
+// write writes a batch from pop to the device, counting failed writes.
+func (c *Coordinator) write(batch []deviceWrite) {
+	defer c.track(&c.writing, describe(batch))()
+
+	var err error
+	switch w := batch[0]; {
+	case len(batch) > 1:
+		images := make(map[device.KeyID]image.Image, len(batch))
+		for _, w := range batch {
+			images[device.KeyID(w.key)] = w.img
+		}
+		err = c.device.SetKeyImages(images)
+	case w.key == 0 && w.rect.Empty():
+		err = c.device.SetTouchStripImage(w.img)
+	case w.key == 0:
+		err = c.device.SetTouchStripImageRect(w.img, w.rect)
+	case w.img == nil:
+		err = c.device.ClearKey(device.KeyID(w.key))
+	default:
+		err = c.device.SetKeyImage(device.KeyID(w.key), w.img)
+	}
+	if err != nil {
+		metrics.USBWriteErrors.Inc()
+		c.rewriteFaces.Store(true)
+		if n := c.failedWrites.Add(1); n == failedWriteReport {
+			c.logger.Error("Device writes keep failing", "count", n, "err", err)
+			c.failed(fmt.Sprintf("%d device writes failed in a row: %v", n, err))
+		}
+	} else {
+		c.failedWrites.Store(0)
+	}
+}
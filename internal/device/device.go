@@ -2,10 +2,16 @@
 package device
 
 import (
+	"errors"
 	"image"
 	"time"
 )
 
+// ErrRegionBrightness is returned by SetKeyBrightness and
+// SetStripBrightness on devices whose keys and touch strip share one
+// backlight, as on the Stream Deck Plus.
+var ErrRegionBrightness = errors.New("device: keys and touch strip share one brightness")
+
 // Device is the interface that abstracts Stream Deck hardware.
 // Both the real hardware adapter and the emulator implement this interface.
 type Device interface {
@@ -21,11 +27,28 @@ type Device interface {
 	GetTouchStripSupported() bool
 	GetKeyImageRectangle() (image.Rectangle, error)
 	GetTouchStripImageRectangle() (image.Rectangle, error)
+	GetSerialNumber() string
+	// GetFirmwareVersion reads the firmware version from the open device.
+	GetFirmwareVersion() (string, error)
+	// GetUSBPath returns the device's USB HID path, or "" if it has none.
+	GetUSBPath() string
 
 	// Display
 	SetBrightness(perc byte) error
+	// GetRegionBrightnessSupported returns whether the keys and touch strip
+	// can be lit separately with SetKeyBrightness and SetStripBrightness,
+	// 0 turning them off. Without it those return ErrRegionBrightness.
+	GetRegionBrightnessSupported() bool
+	SetKeyBrightness(perc byte) error
+	SetStripBrightness(perc byte) error
 	SetKeyImage(key KeyID, img image.Image) error
+	// SetKeyImages sets several keys' images at once. A key that fails
+	// doesn't stop the rest; the errors are returned joined.
+	SetKeyImages(images map[KeyID]image.Image) error
 	SetTouchStripImage(img image.Image) error
+	// SetTouchStripImageRect redraws only rect of the strip, leaving the
+	// rest as it is; img covers rect in strip coordinates.
+	SetTouchStripImageRect(img image.Image, rect image.Rectangle) error
 	ClearKey(key KeyID) error
 
 	// Iteration
@@ -0,0 +1,255 @@
+package emulator
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	recordInterval  = 100 * time.Millisecond // GIF frame rate while recording
+	maxRecordFrames = 600                    // distinct frames kept before recording stops itself
+)
+
+// recorder accumulates GIF frames. Identical consecutive frames are merged
+// by extending the previous frame's delay, so an idle deck costs nothing.
+type recorder struct {
+	mu        sync.Mutex
+	anim      gif.GIF
+	last      []byte
+	lastFrame time.Time
+}
+
+// Snapshot composites the current deck state (keys, strip, and dials) into
+// an image laid out like the emulator window.
+func (e *Emulator) Snapshot() *image.RGBA {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	m, geo := e.model, e.geo
+	img := image.NewRGBA(image.Rect(0, 0, geo.windowWidth, geo.windowHeight))
+	fill(img, img.Bounds(), color.RGBA{30, 30, 30, 255})
+
+	border := color.RGBA{60, 60, 60, 255}
+	drawLabel(img, m.Name, geo.windowWidth/2-len(m.Name)*7/2, 20)
+
+	for i, keyImage := range e.keyImages {
+		x, y := geo.keyOrigin(m, i)
+		fill(img, image.Rect(x-2, y-2, x+m.KeyDisplaySize+2, y+m.KeyDisplaySize+2), border)
+		if keyImage != nil {
+			scaled := scaleImageNearest(keyImage, m.KeyDisplaySize, m.KeyDisplaySize)
+			draw.Draw(img, image.Rect(x, y, x+m.KeyDisplaySize, y+m.KeyDisplaySize), scaled, image.Point{}, draw.Src)
+		}
+	}
+
+	if m.Strip {
+		r := image.Rect(geo.stripStartX, geo.stripStartY, geo.stripStartX+stripWidth, geo.stripStartY+stripHeight)
+		fill(img, r.Inset(-2), border)
+		if e.stripImage != nil {
+			draw.Draw(img, r, e.stripImage, e.stripImage.Bounds().Min, draw.Src)
+		}
+	}
+
+	for i := 0; i < m.Dials; i++ {
+		cx, cy := geo.dialCenter(i)
+		radius := dialSize / 2
+		fillCircle(img, cx, cy, radius, color.RGBA{80, 80, 80, 255})
+		fillCircle(img, cx, cy, radius-8, color.RGBA{50, 50, 50, 255})
+		fillCircle(img, cx, cy, radius-12, color.RGBA{70, 70, 70, 255})
+		drawLabel(img, fmt.Sprintf("D%d", i+1), cx-7, cy+4)
+	}
+
+	return img
+}
+
+// Screenshot writes the current deck state to path as a PNG.
+func (e *Emulator) Screenshot(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, e.Snapshot()); err != nil {
+		f.Close()
+		return fmt.Errorf("encoding screenshot: %w", err)
+	}
+	return f.Close()
+}
+
+// StartRecording begins capturing frames for an animated GIF. Frames are
+// taken by the GUI loop, so recording only advances while RunGUI is running.
+func (e *Emulator) StartRecording() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.recording = &recorder{}
+}
+
+// IsRecording reports whether a recording is in progress.
+func (e *Emulator) IsRecording() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.recording != nil
+}
+
+// StopRecording ends the recording and writes it to path as an animated GIF.
+func (e *Emulator) StopRecording(path string) error {
+	e.mu.Lock()
+	rec := e.recording
+	e.recording = nil
+	e.mu.Unlock()
+
+	if rec == nil {
+		return fmt.Errorf("emulator: not recording")
+	}
+	return rec.write(path)
+}
+
+// write encodes the recorded frames to path as an animated GIF.
+func (r *recorder) write(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.anim.Image) == 0 {
+		return fmt.Errorf("emulator: recording has no frames")
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, &r.anim); err != nil {
+		f.Close()
+		return fmt.Errorf("encoding recording: %w", err)
+	}
+	return f.Close()
+}
+
+// captureFrame adds a frame to the active recording, if any, at most once
+// per recordInterval. It's called from the GUI loop.
+func (e *Emulator) captureFrame() {
+	e.mu.RLock()
+	rec := e.recording
+	e.mu.RUnlock()
+	if rec == nil {
+		return
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if time.Since(rec.lastFrame) < recordInterval {
+		return
+	}
+
+	now := time.Now()
+	delay := 0
+	if !rec.lastFrame.IsZero() {
+		delay = int(now.Sub(rec.lastFrame) / (10 * time.Millisecond)) // GIF delays are in 1/100s
+	}
+	rec.lastFrame = now
+
+	snap := e.Snapshot()
+	if n := len(rec.anim.Delay); n > 0 {
+		rec.anim.Delay[n-1] += delay
+		if bytes.Equal(snap.Pix, rec.last) {
+			return
+		}
+	}
+	if len(rec.anim.Image) >= maxRecordFrames {
+		slog.Warn("Recording frame limit reached, stopping")
+		go e.saveRecording()
+		return
+	}
+
+	frame := image.NewPaletted(snap.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(frame, frame.Bounds(), snap, image.Point{})
+	rec.anim.Image = append(rec.anim.Image, frame)
+	rec.anim.Delay = append(rec.anim.Delay, 0)
+	rec.last = snap.Pix
+}
+
+// SetCaptureDir sets where the F12 screenshot and F9 recording shortcuts
+// save their files. It defaults to the current directory.
+func (e *Emulator) SetCaptureDir(dir string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.captureDir = dir
+}
+
+// capturePath returns a timestamped file path in the capture directory.
+func (e *Emulator) capturePath(ext string) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	name := "belowdeck-" + time.Now().Format("20060102-150405") + ext
+	return filepath.Join(e.captureDir, name)
+}
+
+// saveScreenshot handles the screenshot shortcut.
+func (e *Emulator) saveScreenshot() {
+	path := e.capturePath(".png")
+	if err := e.Screenshot(path); err != nil {
+		slog.Error("Screenshot failed", "err", err)
+		return
+	}
+	slog.Info("Saved screenshot", "path", path)
+}
+
+// saveRecording stops the active recording and writes it in the background,
+// since encoding a long GIF would stall the GUI loop.
+func (e *Emulator) saveRecording() {
+	path := e.capturePath(".gif")
+	e.mu.Lock()
+	rec := e.recording
+	e.recording = nil
+	e.mu.Unlock()
+	if rec == nil {
+		return
+	}
+
+	go func() {
+		if err := rec.write(path); err != nil {
+			slog.Error("Recording failed", "err", err)
+			return
+		}
+		slog.Info("Saved recording", "path", path)
+	}()
+}
+
+// fill paints r with a solid color.
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// fillCircle paints a filled circle centered at (cx, cy).
+func fillCircle(img *image.RGBA, cx, cy, radius int, c color.Color) {
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				img.Set(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+// drawLabel draws text in the emulator's debug font with its baseline at y.
+func drawLabel(img *image.RGBA, text string, x, y int) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.White),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
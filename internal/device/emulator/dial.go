@@ -0,0 +1,103 @@
+package emulator
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+// dialStep is how far a dial is dragged around for one rotation step: 24
+// steps a turn, like the Plus's detents.
+const dialStep = math.Pi / 12
+
+// dialDrag is a dial being turned by dragging around it with the mouse.
+type dialDrag struct {
+	dial    int
+	angle   float64 // the pointer's angle around the dial when last seen
+	pending float64 // angle dragged through short of a whole step
+}
+
+// handleDialMouse turns a dial dragged around with the left button, one
+// step at a time, and holds a dial pressed for as long as the right button
+// is down on it.
+func (g *emulatorGame) handleDialMouse(mx, my int) {
+	e := g.emu
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if i := g.dialAt(mx, my); i >= 0 {
+			e.activeDial = i
+			e.dialDrag = &dialDrag{dial: i, angle: g.dialAngle(i, mx, my)}
+		}
+	}
+	if d := e.dialDrag; d != nil {
+		if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			e.dialDrag = nil
+		} else {
+			angle := g.dialAngle(d.dial, mx, my)
+			// The short way round, so crossing the angle's wrap isn't a
+			// whole turn
+			d.pending += math.Remainder(angle-d.angle, 2*math.Pi)
+			d.angle = angle
+			for ; d.pending >= dialStep; d.pending -= dialStep {
+				e.triggerDialRotate(device.DialID(d.dial+1), 1)
+			}
+			for ; d.pending <= -dialStep; d.pending += dialStep {
+				e.triggerDialRotate(device.DialID(d.dial+1), -1)
+			}
+		}
+	}
+
+	if e.dialRelease != nil && !ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		e.dialRelease()
+		e.dialRelease = nil
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) && e.dialRelease == nil {
+		if i := g.dialAt(mx, my); i >= 0 {
+			e.activeDial = i
+			e.dialRelease = e.triggerDialPress(device.DialID(i + 1))
+		}
+	}
+}
+
+// dialAngle returns the angle of (x, y) around dial i, clockwise from
+// pointing right.
+func (g *emulatorGame) dialAngle(i, x, y int) float64 {
+	cx, cy := g.emu.geo.dialCenter(i)
+	return math.Atan2(float64(y-cy), float64(x-cx))
+}
+
+// drawDial draws dial i with a marker turned as far as the dial has been,
+// lit while it's pressed and labeled with how long it's been held. The
+// caller holds g.emu.mu.
+func (g *emulatorGame) drawDial(screen *ebiten.Image, i int) {
+	v := g.view
+	cx, cy := g.emu.geo.dialCenter(i)
+	radius := dialSize / 2
+	held := g.emu.dialHeld[i]
+
+	// Draw dial as concentric circles (outer ring, inner dial)
+	face := color.RGBA{70, 70, 70, 255}
+	if !held.IsZero() {
+		face = color.RGBA{110, 110, 110, 255}
+	}
+	v.fillCircle(screen, cx, cy, radius, color.RGBA{80, 80, 80, 255})
+	v.fillCircle(screen, cx, cy, radius-8, color.RGBA{50, 50, 50, 255})
+	v.fillCircle(screen, cx, cy, radius-12, face)
+
+	// Marker, starting at the top
+	angle := -math.Pi/2 + float64(g.emu.dialTurns[i])*dialStep
+	mr := float64(radius - 22)
+	v.fillCircle(screen, cx+int(math.Round(mr*math.Cos(angle))), cy+int(math.Round(mr*math.Sin(angle))), 5, color.RGBA{220, 220, 220, 255})
+
+	// Draw dial label
+	label := fmt.Sprintf("D%d", i+1)
+	if !held.IsZero() {
+		label = fmt.Sprintf("%.1fs", time.Since(held).Seconds())
+	}
+	v.label(screen, label, cx-len(label)*3, cy-4)
+}
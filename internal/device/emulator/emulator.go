@@ -1,91 +1,222 @@
-// Package emulator provides a GUI-based Stream Deck Plus emulator.
+// Package emulator provides a GUI-based Stream Deck emulator. It defaults to
+// the Stream Deck Plus and can emulate other models' geometry.
 package emulator
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
+	"log/slog"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/power"
+	xdraw "golang.org/x/image/draw"
 )
 
-// Layout constants for Stream Deck Plus
+// Layout constants shared by all models
 const (
-	keySize        = 72  // Key image size (72x72) - native resolution
-	keyDisplaySize = 144 // Key display size - clean 2x scale for crisp rendering
-	keysPerRow     = 4   // Keys per row
-	keyRows        = 2   // Number of key rows
-	keyCount       = 8   // Total keys
-	dialCount      = 4   // Total dials
-	dialSize       = 120 // Visual dial size - similar to key size
-	marginX        = 20 // Left/right margin
-	marginY        = 20 // Top margin
-	headerHeight   = 30 // Title bar height
-	stripMarginY   = 72 // Space between keys and strip (~half key height)
-	dialMarginY    = 50 // Space between strip and dials
-	bottomMarginY  = 50 // Space below dials
+	dialSize      = 120 // Visual dial size - similar to key size
+	marginX       = 20  // Left/right margin
+	marginY       = 20  // Top margin
+	headerHeight  = 30  // Title bar height
+	stripMarginY  = 72  // Space between keys and strip (~half key height)
+	dialMarginY   = 50  // Space between strip and dials
+	bottomMarginY = 50  // Space below dials
+	keyGap        = 24  // Key spacing on models without a strip
 
 	// Strip dimensions (native resolution)
 	stripWidth  = 800
 	stripHeight = 100
 )
 
-// Calculate layout - strip-native width, keys 2x scaled with remaining space as padding
-const (
-	keyAreaWidth  = keysPerRow * keyDisplaySize                                   // 4*144 = 576
-	keySpacing    = (stripWidth - keyAreaWidth) / (keysPerRow + 1)                // Distribute remaining 224px as spacing = 44px each
-	keyAreaHeight = keyRows*keyDisplaySize + (keyRows-1)*keySpacing               // 2*144 + 44 = 332
-	dialSpacing   = (stripWidth - dialCount*dialSize) / (dialCount + 1)           // Even spacing for dials
-	windowWidth   = 2*marginX + stripWidth
-	windowHeight  = headerHeight + marginY + keyAreaHeight + stripMarginY + stripHeight + dialMarginY + dialSize + bottomMarginY
-)
+// Model describes the geometry of an emulated Stream Deck.
+type Model struct {
+	Name           string
+	Cols, Rows     int
+	KeySize        int // native key image resolution
+	KeyDisplaySize int // on-screen key size
+	Dials          int
+	Strip          bool
+}
+
+// Models are the emulated Stream Deck models, by flag name.
+var Models = map[string]Model{
+	"plus": {Name: "Stream Deck Plus", Cols: 4, Rows: 2, KeySize: 72, KeyDisplaySize: 144, Dials: 4, Strip: true},
+	"mk2":  {Name: "Stream Deck MK.2", Cols: 5, Rows: 3, KeySize: 72, KeyDisplaySize: 144},
+	"mini": {Name: "Stream Deck Mini", Cols: 3, Rows: 2, KeySize: 80, KeyDisplaySize: 160},
+	"xl":   {Name: "Stream Deck XL", Cols: 8, Rows: 4, KeySize: 96, KeyDisplaySize: 96},
+}
+
+// ParseModel looks up a model by flag name (plus, mk2, mini, xl).
+func ParseModel(name string) (Model, error) {
+	m, ok := Models[name]
+	if !ok {
+		return Model{}, fmt.Errorf("emulator: unknown model %q (want plus, mk2, mini, or xl)", name)
+	}
+	return m, nil
+}
+
+// keyCount returns the number of keys on the model.
+func (m Model) keyCount() int {
+	return m.Cols * m.Rows
+}
+
+// geometry is the window layout computed from a Model.
+type geometry struct {
+	keySpacing    int
+	keyAreaHeight int
+	contentWidth  int
+	dialSpacing   int
+	windowWidth   int
+	windowHeight  int
+
+	keysStartX, keysStartY int
+	stripStartX            int
+	stripStartY            int
+	dialStartY             int
+}
+
+// newGeometry lays out the window: with a strip, keys are spread across its
+// native width; without one, keys sit keyGap apart.
+func newGeometry(m Model) geometry {
+	var g geometry
+	keyAreaWidth := m.Cols * m.KeyDisplaySize
+	if m.Strip {
+		g.contentWidth = stripWidth
+		g.keySpacing = (stripWidth - keyAreaWidth) / (m.Cols + 1)
+	} else {
+		g.keySpacing = keyGap
+		g.contentWidth = keyAreaWidth + (m.Cols+1)*keyGap
+	}
+	g.keyAreaHeight = m.Rows*m.KeyDisplaySize + (m.Rows-1)*g.keySpacing
+	if m.Dials > 0 {
+		g.dialSpacing = (g.contentWidth - m.Dials*dialSize) / (m.Dials + 1)
+	}
+
+	g.keysStartX = marginX + g.keySpacing
+	g.keysStartY = headerHeight + marginY
+	g.stripStartX = marginX
+	g.stripStartY = g.keysStartY + g.keyAreaHeight
+	if m.Strip {
+		g.stripStartY += stripMarginY
+	}
+	g.dialStartY = g.stripStartY
+	if m.Strip {
+		g.dialStartY += stripHeight
+	}
+	g.dialStartY += dialMarginY
+
+	g.windowWidth = 2*marginX + g.contentWidth
+	g.windowHeight = g.keysStartY + g.keyAreaHeight + bottomMarginY
+	if m.Strip {
+		g.windowHeight += stripMarginY + stripHeight
+	}
+	if m.Dials > 0 {
+		g.windowHeight += dialMarginY + dialSize
+	}
+	return g
+}
+
+// keyOrigin returns the on-screen origin of key index i.
+func (g geometry) keyOrigin(m Model, i int) (int, int) {
+	row, col := i/m.Cols, i%m.Cols
+	return g.keysStartX + col*(m.KeyDisplaySize+g.keySpacing), g.keysStartY + row*(m.KeyDisplaySize+g.keySpacing)
+}
+
+// dialCenter returns the on-screen center of dial index i.
+func (g geometry) dialCenter(i int) (int, int) {
+	x := g.stripStartX + g.dialSpacing + i*(dialSize+g.dialSpacing)
+	return x + dialSize/2, g.dialStartY + dialSize/2
+}
 
 // Emulator implements the device.Device interface using Ebitengine for GUI rendering.
 type Emulator struct {
 	mu sync.RWMutex
 
+	model Model
+	geo   geometry
+
 	// State
-	open       bool
-	brightness byte
-	keyImages  [keyCount]*image.RGBA
-	stripImage *image.RGBA
+	open            bool
+	brightness      byte // keys
+	stripBrightness byte
+	keyImages       []*image.RGBA
+	stripImage      *image.RGBA
 
 	// Handlers
-	keyHandlers         [keyCount][]device.KeyHandler
-	dialRotateHandlers  [dialCount][]device.DialRotateHandler
-	dialSwitchHandlers  [dialCount][]device.DialSwitchHandler
-	stripTouchHandlers  []device.TouchStripTouchHandler
-	stripSwipeHandlers  []device.TouchStripSwipeHandler
+	keyHandlers        [][]device.KeyHandler
+	dialRotateHandlers [][]device.DialRotateHandler
+	dialSwitchHandlers [][]device.DialSwitchHandler
+	stripTouchHandlers []device.TouchStripTouchHandler
+	stripSwipeHandlers []device.TouchStripSwipeHandler
 
 	// Ebitengine state
 	game       *emulatorGame
 	stopCh     chan struct{}
 	errorCh    chan error
 	listenDone chan struct{}
+	detached   chan struct{} // closed by Detach to release Listen
+	listening  bool
 
 	// Input state (managed by game loop)
 	prevMousePressed bool
 	dragStart        image.Point
 	dragStartTime    time.Time
 	dragging         bool
+	mouseRelease     func()                // releases the key held by the mouse
+	keyboardRelease  map[ebiten.Key]func() // releases keys held via number keys
+	activeDial       int                   // dial index rotated by [ and ]
+	dialDrag         *dialDrag             // the dial being turned by dragging
+	dialRelease      func()                // releases the dial held by the right button
+
+	// Dial state shown in the window
+	dialTurns []int       // net steps each dial has been rotated
+	dialHeld  []time.Time // when each dial was pressed, zero if it isn't
+
+	// Capture state
+	captureDir string
+	recording  *recorder
+
+	// Simulated sleep and wake, sent with F8
+	power *power.Simulator
+
+	// Simulated unplugging, toggled with F7
+	unplugged bool
+	unplugCh  chan struct{} // closed by Unplug to fail Listen
+	arrivals  chan struct{}
 }
 
-// New creates a new emulator instance.
-func New() *Emulator {
+// ErrUnplugged is returned by Listen, and by writes, while the emulated
+// deck is unplugged.
+var ErrUnplugged = errors.New("emulator: device unplugged")
+
+// New creates a new emulator instance for the given model.
+func New(model Model) *Emulator {
 	e := &Emulator{
-		brightness: 80,
-		stopCh:     make(chan struct{}),
+		model:              model,
+		geo:                newGeometry(model),
+		brightness:         80,
+		stripBrightness:    80,
+		stopCh:             make(chan struct{}),
+		arrivals:           make(chan struct{}, 1),
+		keyImages:          make([]*image.RGBA, model.keyCount()),
+		keyHandlers:        make([][]device.KeyHandler, model.keyCount()),
+		dialRotateHandlers: make([][]device.DialRotateHandler, model.Dials),
+		dialSwitchHandlers: make([][]device.DialSwitchHandler, model.Dials),
+		dialTurns:          make([]int, model.Dials),
+		dialHeld:           make([]time.Time, model.Dials),
 	}
 
 	// Initialize key images to black
-	for i := 0; i < keyCount; i++ {
-		e.keyImages[i] = image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	for i := range e.keyImages {
+		e.keyImages[i] = image.NewRGBA(image.Rect(0, 0, model.KeySize, model.KeySize))
 	}
 
 	// Initialize strip image
@@ -102,13 +233,17 @@ func (e *Emulator) Open() error {
 	if e.open {
 		return fmt.Errorf("emulator: device is already open")
 	}
+	if e.unplugged {
+		return ErrUnplugged
+	}
 
 	e.open = true
 	e.stopCh = make(chan struct{})
 	return nil
 }
 
-// Close shuts down the emulator.
+// Close shuts down the emulator. While it's unplugged the window stays
+// up, so it can be plugged back in and opened again.
 func (e *Emulator) Close() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -118,11 +253,27 @@ func (e *Emulator) Close() error {
 	}
 
 	e.open = false
+	if !e.unplugged {
+		e.quit()
+	}
+	return nil
+}
 
-	// Signal the game loop to stop
-	close(e.stopCh)
+// Quit ends the GUI, as closing the window does, whether or not the
+// emulator is open.
+func (e *Emulator) Quit() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.quit()
+}
 
-	return nil
+// quit signals the game loop to stop. The caller holds e.mu.
+func (e *Emulator) quit() {
+	select {
+	case <-e.stopCh:
+	default:
+		close(e.stopCh)
+	}
 }
 
 // IsOpen returns whether the emulator is open.
@@ -134,64 +285,156 @@ func (e *Emulator) IsOpen() bool {
 
 // GetModelName returns the emulated model name.
 func (e *Emulator) GetModelName() string {
-	return "Stream Deck Plus (Emulator)"
+	return e.model.Name + " (Emulator)"
 }
 
 // GetKeyCount returns the number of keys.
 func (e *Emulator) GetKeyCount() byte {
-	return keyCount
+	return byte(e.model.keyCount())
 }
 
 // GetDialCount returns the number of dials.
 func (e *Emulator) GetDialCount() byte {
-	return dialCount
+	return byte(e.model.Dials)
 }
 
-// GetTouchStripSupported returns true as the emulated device supports touch strip.
+// GetTouchStripSupported reports whether the emulated model has a touch strip.
 func (e *Emulator) GetTouchStripSupported() bool {
-	return true
+	return e.model.Strip
 }
 
 // GetKeyImageRectangle returns the key image dimensions.
 func (e *Emulator) GetKeyImageRectangle() (image.Rectangle, error) {
-	return image.Rect(0, 0, keySize, keySize), nil
+	return image.Rect(0, 0, e.model.KeySize, e.model.KeySize), nil
 }
 
 // GetTouchStripImageRectangle returns the touch strip dimensions.
 func (e *Emulator) GetTouchStripImageRectangle() (image.Rectangle, error) {
+	if !e.model.Strip {
+		return image.Rectangle{}, fmt.Errorf("emulator: %s has no touch strip", e.model.Name)
+	}
 	return image.Rect(0, 0, stripWidth, stripHeight), nil
 }
 
-// SetBrightness sets the display brightness.
+// GetSerialNumber returns "EMULATOR".
+func (e *Emulator) GetSerialNumber() string {
+	return "EMULATOR"
+}
+
+// GetFirmwareVersion returns "emulator".
+func (e *Emulator) GetFirmwareVersion() (string, error) {
+	return "emulator", nil
+}
+
+// GetUSBPath returns "": the emulator isn't on USB.
+func (e *Emulator) GetUSBPath() string {
+	return ""
+}
+
+// SetBrightness sets the brightness of the keys and strip.
 func (e *Emulator) SetBrightness(perc byte) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
+	e.brightness, e.stripBrightness = perc, perc
+	return nil
+}
+
+// GetRegionBrightnessSupported returns whether the model has a touch strip
+// to light separately from the keys.
+func (e *Emulator) GetRegionBrightnessSupported() bool {
+	return e.model.Strip
+}
+
+// SetKeyBrightness sets the keys' brightness.
+func (e *Emulator) SetKeyBrightness(perc byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
+	if !e.model.Strip {
+		return device.ErrRegionBrightness
+	}
 	e.brightness = perc
 	return nil
 }
 
-// SetKeyImage sets the image for a key.
+// SetStripBrightness sets the touch strip's brightness.
+func (e *Emulator) SetStripBrightness(perc byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
+	if !e.model.Strip {
+		return device.ErrRegionBrightness
+	}
+	e.stripBrightness = perc
+	return nil
+}
+
+// SetKeyImage sets the image for a key, scaling it to the key resolution
+// like the hardware library does.
 func (e *Emulator) SetKeyImage(key device.KeyID, img image.Image) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
 
 	idx := int(key) - 1
-	if idx < 0 || idx >= keyCount {
+	if idx < 0 || idx >= len(e.keyImages) {
 		return fmt.Errorf("emulator: invalid key ID: %d", key)
 	}
 
-	// Create new RGBA image and draw the provided image onto it
-	rgba := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
-	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	rgba := image.NewRGBA(image.Rect(0, 0, e.model.KeySize, e.model.KeySize))
+	xdraw.BiLinear.Scale(rgba, rgba.Bounds(), img, img.Bounds(), draw.Src, nil)
 	e.keyImages[idx] = rgba
 
 	return nil
 }
 
+// SetKeyImages sets several keys' images, scaling them before taking the
+// lock once for all of them.
+func (e *Emulator) SetKeyImages(images map[device.KeyID]image.Image) error {
+	var errs []error
+	scaled := make(map[int]*image.RGBA, len(images))
+	for key, img := range images {
+		idx := int(key) - 1
+		if idx < 0 || idx >= len(e.keyImages) {
+			errs = append(errs, fmt.Errorf("emulator: invalid key ID: %d", key))
+			continue
+		}
+		rgba := image.NewRGBA(image.Rect(0, 0, e.model.KeySize, e.model.KeySize))
+		xdraw.BiLinear.Scale(rgba, rgba.Bounds(), img, img.Bounds(), draw.Src, nil)
+		scaled[idx] = rgba
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
+	for idx, rgba := range scaled {
+		e.keyImages[idx] = rgba
+	}
+	return errors.Join(errs...)
+}
+
 // SetTouchStripImage sets the touch strip image.
 func (e *Emulator) SetTouchStripImage(img image.Image) error {
+	if !e.model.Strip {
+		return fmt.Errorf("emulator: %s has no touch strip", e.model.Name)
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
 
 	// Create new RGBA image and draw the provided image onto it
 	rgba := image.NewRGBA(image.Rect(0, 0, stripWidth, stripHeight))
@@ -201,24 +444,47 @@ func (e *Emulator) SetTouchStripImage(img image.Image) error {
 	return nil
 }
 
+// SetTouchStripImageRect redraws rect of the touch strip image.
+func (e *Emulator) SetTouchStripImageRect(img image.Image, rect image.Rectangle) error {
+	if !e.model.Strip {
+		return fmt.Errorf("emulator: %s has no touch strip", e.model.Name)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
+
+	if e.stripImage == nil {
+		e.stripImage = image.NewRGBA(image.Rect(0, 0, stripWidth, stripHeight))
+	}
+	draw.Draw(e.stripImage, rect, img, img.Bounds().Min, draw.Src)
+
+	return nil
+}
+
 // ClearKey clears a key's image to black.
 func (e *Emulator) ClearKey(key device.KeyID) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.unplugged {
+		return ErrUnplugged
+	}
 
 	idx := int(key) - 1
-	if idx < 0 || idx >= keyCount {
+	if idx < 0 || idx >= len(e.keyImages) {
 		return fmt.Errorf("emulator: invalid key ID: %d", key)
 	}
 
-	e.keyImages[idx] = image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	e.keyImages[idx] = image.NewRGBA(image.Rect(0, 0, e.model.KeySize, e.model.KeySize))
 	return nil
 }
 
 // ForEachKey calls the callback for each key.
 func (e *Emulator) ForEachKey(cb func(device.KeyID) error) error {
-	for i := device.KEY_1; i <= device.KEY_8; i++ {
-		if err := cb(i); err != nil {
+	for i := 1; i <= e.model.keyCount(); i++ {
+		if err := cb(device.KeyID(i)); err != nil {
 			return err
 		}
 	}
@@ -227,8 +493,8 @@ func (e *Emulator) ForEachKey(cb func(device.KeyID) error) error {
 
 // ForEachDial calls the callback for each dial.
 func (e *Emulator) ForEachDial(cb func(device.DialID) error) error {
-	for i := device.DIAL_1; i <= device.DIAL_4; i++ {
-		if err := cb(i); err != nil {
+	for i := 1; i <= e.model.Dials; i++ {
+		if err := cb(device.DialID(i)); err != nil {
 			return err
 		}
 	}
@@ -241,7 +507,7 @@ func (e *Emulator) AddKeyHandler(key device.KeyID, fn device.KeyHandler) error {
 	defer e.mu.Unlock()
 
 	idx := int(key) - 1
-	if idx < 0 || idx >= keyCount {
+	if idx < 0 || idx >= len(e.keyHandlers) {
 		return fmt.Errorf("emulator: invalid key ID: %d", key)
 	}
 
@@ -255,7 +521,7 @@ func (e *Emulator) AddDialRotateHandler(dial device.DialID, fn device.DialRotate
 	defer e.mu.Unlock()
 
 	idx := int(dial) - 1
-	if idx < 0 || idx >= dialCount {
+	if idx < 0 || idx >= len(e.dialRotateHandlers) {
 		return fmt.Errorf("emulator: invalid dial ID: %d", dial)
 	}
 
@@ -269,7 +535,7 @@ func (e *Emulator) AddDialSwitchHandler(dial device.DialID, fn device.DialSwitch
 	defer e.mu.Unlock()
 
 	idx := int(dial) - 1
-	if idx < 0 || idx >= dialCount {
+	if idx < 0 || idx >= len(e.dialSwitchHandlers) {
 		return fmt.Errorf("emulator: invalid dial ID: %d", dial)
 	}
 
@@ -279,6 +545,10 @@ func (e *Emulator) AddDialSwitchHandler(dial device.DialID, fn device.DialSwitch
 
 // AddTouchStripTouchHandler registers a touch strip touch handler.
 func (e *Emulator) AddTouchStripTouchHandler(fn device.TouchStripTouchHandler) error {
+	if !e.model.Strip {
+		return fmt.Errorf("emulator: %s has no touch strip", e.model.Name)
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.stripTouchHandlers = append(e.stripTouchHandlers, fn)
@@ -287,13 +557,18 @@ func (e *Emulator) AddTouchStripTouchHandler(fn device.TouchStripTouchHandler) e
 
 // AddTouchStripSwipeHandler registers a touch strip swipe handler.
 func (e *Emulator) AddTouchStripSwipeHandler(fn device.TouchStripSwipeHandler) error {
+	if !e.model.Strip {
+		return fmt.Errorf("emulator: %s has no touch strip", e.model.Name)
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.stripSwipeHandlers = append(e.stripSwipeHandlers, fn)
 	return nil
 }
 
-// Listen blocks until the emulator is closed.
+// Listen blocks until the emulator is closed or detached, or returns
+// ErrUnplugged once it's unplugged.
 // For the emulator, the actual event loop runs via RunGUI() which must be called from main.
 func (e *Emulator) Listen(errCh chan error) error {
 	e.mu.Lock()
@@ -302,14 +577,111 @@ func (e *Emulator) Listen(errCh chan error) error {
 		return fmt.Errorf("emulator: device is not open")
 	}
 	e.errorCh = errCh
+	e.listening = true
 	if e.listenDone == nil {
 		e.listenDone = make(chan struct{})
 	}
+	if e.detached == nil {
+		e.detached = make(chan struct{})
+	}
+	if e.unplugCh == nil {
+		e.unplugCh = make(chan struct{})
+	}
+	listenDone, detached, unplugCh := e.listenDone, e.detached, e.unplugCh
 	e.mu.Unlock()
 
 	// Block until GUI is closed
-	<-e.listenDone
-	return nil
+	var err error
+	select {
+	case <-listenDone:
+	case <-detached:
+	case <-unplugCh:
+		err = ErrUnplugged
+	}
+
+	e.mu.Lock()
+	e.listening = false
+	e.mu.Unlock()
+	return err
+}
+
+// Detach drops all event handlers and releases Listen, as a real device's
+// disconnect does, so a coordinator can attach again as it would to a
+// reconnected device.
+func (e *Emulator) Detach() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.dropHandlers()
+	if e.detached != nil {
+		close(e.detached)
+		e.detached = nil
+	}
+}
+
+// dropHandlers forgets all event handlers. The caller holds e.mu.
+func (e *Emulator) dropHandlers() {
+	for i := range e.keyHandlers {
+		e.keyHandlers[i] = nil
+	}
+	for i := range e.dialRotateHandlers {
+		e.dialRotateHandlers[i] = nil
+		e.dialSwitchHandlers[i] = nil
+	}
+	e.stripTouchHandlers = nil
+	e.stripSwipeHandlers = nil
+}
+
+// Unplug simulates pulling the deck's cable: Listen returns ErrUnplugged,
+// the handlers are dropped, the keys go dark, and writes and Open fail
+// until Plug.
+func (e *Emulator) Unplug() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.unplugged {
+		return
+	}
+	e.unplugged = true
+	e.dropHandlers()
+	for i := range e.keyImages {
+		e.keyImages[i] = image.NewRGBA(image.Rect(0, 0, e.model.KeySize, e.model.KeySize))
+	}
+	e.stripImage = image.NewRGBA(image.Rect(0, 0, stripWidth, stripHeight))
+	if e.unplugCh != nil {
+		close(e.unplugCh)
+		e.unplugCh = nil
+	}
+}
+
+// Plug simulates plugging the deck back in, announcing its arrival on
+// Arrivals.
+func (e *Emulator) Plug() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.unplugged {
+		return
+	}
+	e.unplugged = false
+	select {
+	case e.arrivals <- struct{}{}:
+	default:
+	}
+}
+
+// Arrivals returns a channel signaled each time the emulated deck is
+// plugged back in, as usbwatch.Watch is for a real one.
+func (e *Emulator) Arrivals() <-chan struct{} {
+	return e.arrivals
+}
+
+// SimulatePower makes F8 send sim a sleep and then a wake, as if the system
+// had slept.
+func (e *Emulator) SimulatePower(sim *power.Simulator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.power = sim
 }
 
 // RunGUI starts the Ebitengine GUI loop. This MUST be called from the main goroutine
@@ -326,9 +698,17 @@ func (e *Emulator) RunGUI() error {
 	e.game = &emulatorGame{emu: e}
 	e.mu.Unlock()
 
-	ebiten.SetWindowSize(windowWidth, windowHeight)
-	ebiten.SetWindowTitle("Stream Deck Plus Emulator")
-	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeDisabled)
+	// Shrunk to fit a small screen, keeping its proportions; the window
+	// can then be resized either way
+	w, h := e.geo.windowWidth, e.geo.windowHeight
+	if mw, mh := ebiten.Monitor().Size(); mw > 0 && mh > 0 {
+		fit := min(1, 0.9*float64(mw)/float64(w), 0.9*float64(mh)/float64(h))
+		w, h = int(float64(w)*fit), int(float64(h)*fit)
+	}
+	ebiten.SetWindowSize(w, h)
+	ebiten.SetWindowSizeLimits(e.geo.windowWidth/4, e.geo.windowHeight/4, -1, -1)
+	ebiten.SetWindowTitle(e.model.Name + " Emulator")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
 	// Run the game loop (this blocks until the window is closed)
 	err := ebiten.RunGame(e.game)
@@ -340,7 +720,8 @@ func (e *Emulator) RunGUI() error {
 
 // emulatorGame implements ebiten.Game for the emulator.
 type emulatorGame struct {
-	emu *emulatorGame_emu
+	emu  *emulatorGame_emu
+	view view // set by Layout
 }
 
 // We need a separate reference to avoid import cycle
@@ -355,9 +736,62 @@ func (g *emulatorGame) Update() error {
 	}
 
 	g.handleInput()
+	g.handleCaptureKeys()
+	g.handlePowerKey()
+	g.handlePlugKey()
+	g.emu.captureFrame()
 	return nil
 }
 
+// handleCaptureKeys saves a PNG screenshot on F12 and toggles GIF recording
+// on F9.
+func (g *emulatorGame) handleCaptureKeys() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF12) {
+		go g.emu.saveScreenshot()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		if g.emu.IsRecording() {
+			g.emu.saveRecording()
+		} else {
+			g.emu.StartRecording()
+			slog.Info("Recording started, press F9 to stop")
+		}
+	}
+}
+
+// handlePowerKey simulates a sleep and wake on F8.
+func (g *emulatorGame) handlePowerKey() {
+	if !inpututil.IsKeyJustPressed(ebiten.KeyF8) {
+		return
+	}
+	g.emu.mu.RLock()
+	sim := g.emu.power
+	g.emu.mu.RUnlock()
+	if sim == nil {
+		return
+	}
+	slog.Info("Simulating sleep and wake")
+	sim.Sleep()
+	sim.Wake()
+}
+
+// handlePlugKey unplugs the deck on F7, and plugs it back in on the next.
+func (g *emulatorGame) handlePlugKey() {
+	if !inpututil.IsKeyJustPressed(ebiten.KeyF7) {
+		return
+	}
+	g.emu.mu.RLock()
+	unplugged := g.emu.unplugged
+	g.emu.mu.RUnlock()
+	if unplugged {
+		slog.Info("Simulating plugging the deck back in")
+		g.emu.Plug()
+	} else {
+		slog.Info("Simulating unplugging the deck, press F7 to plug it back in")
+		g.emu.Unplug()
+	}
+}
+
 func (g *emulatorGame) Draw(screen *ebiten.Image) {
 	// Background
 	screen.Fill(color.RGBA{30, 30, 30, 255})
@@ -365,156 +799,130 @@ func (g *emulatorGame) Draw(screen *ebiten.Image) {
 	g.emu.mu.RLock()
 	defer g.emu.mu.RUnlock()
 
-	// Layout: keys centered with even spacing, strip at native width below
-	keysStartX := marginX + keySpacing // First key offset by spacing
-	keysStartY := headerHeight + marginY
-	stripStartX := marginX
-	stripStartY := keysStartY + keyAreaHeight + stripMarginY
-	dialStartY := stripStartY + stripHeight + dialMarginY
+	m, geo, v := g.emu.model, g.emu.geo, g.view
+	brightness := float32(g.emu.brightness) / 100.0
+	stripBrightness := float32(g.emu.stripBrightness) / 100.0
 
 	// Draw title
-	ebitenutil.DebugPrintAt(screen, "Stream Deck Plus Emulator", windowWidth/2-100, 8)
+	title := m.Name + " Emulator"
+	v.label(screen, title, geo.windowWidth/2-len(title)*3, 8)
 
-	// Draw keys - clean 2x scale (72 -> 144) using nearest-neighbor
-	for i := 0; i < keyCount; i++ {
-		row := i / keysPerRow
-		col := i % keysPerRow
-
-		x := keysStartX + col*(keyDisplaySize+keySpacing)
-		y := keysStartY + row*(keyDisplaySize+keySpacing)
+	// Draw keys scaled up from their native resolution
+	for i, keyImage := range g.emu.keyImages {
+		x, y := geo.keyOrigin(m, i)
 
 		// Draw key background (border)
-		drawRect(screen, x-2, y-2, keyDisplaySize+4, keyDisplaySize+4, color.RGBA{60, 60, 60, 255})
+		v.fillRect(screen, x-2, y-2, m.KeyDisplaySize+4, m.KeyDisplaySize+4, color.RGBA{60, 60, 60, 255})
 
-		// Draw key image scaled up with nearest-neighbor filtering
-		if g.emu.keyImages[i] != nil {
-			// Scale up using nearest-neighbor for crisp 2x scaling
-			scaledImg := scaleImageNearest(g.emu.keyImages[i], keyDisplaySize, keyDisplaySize)
-			keyImg := ebiten.NewImageFromImage(scaledImg)
+		if keyImage != nil {
+			keyImg := ebiten.NewImageFromImage(keyImage)
 			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(x), float64(y))
-			// Apply brightness
-			brightness := float64(g.emu.brightness) / 100.0
-			op.ColorScale.Scale(float32(brightness), float32(brightness), float32(brightness), 1)
+			v.place(op, x, y, float64(m.KeyDisplaySize)/float64(m.KeySize))
+			op.ColorScale.Scale(brightness, brightness, brightness, 1)
 			screen.DrawImage(keyImg, op)
 		}
 	}
 
-	// Draw touch strip background
-	drawRect(screen, stripStartX-2, stripStartY-2, stripWidth+4, stripHeight+4, color.RGBA{60, 60, 60, 255})
+	if m.Strip {
+		// Draw touch strip background
+		v.fillRect(screen, geo.stripStartX-2, geo.stripStartY-2, stripWidth+4, stripHeight+4, color.RGBA{60, 60, 60, 255})
 
-	// Draw touch strip image at native resolution
-	if g.emu.stripImage != nil {
-		stripImg := ebiten.NewImageFromImage(g.emu.stripImage)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(stripStartX), float64(stripStartY))
-		brightness := float64(g.emu.brightness) / 100.0
-		op.ColorScale.Scale(float32(brightness), float32(brightness), float32(brightness), 1)
-		screen.DrawImage(stripImg, op)
+		// Draw touch strip image, one layout unit per native pixel
+		if g.emu.stripImage != nil {
+			stripImg := ebiten.NewImageFromImage(g.emu.stripImage)
+			op := &ebiten.DrawImageOptions{}
+			v.place(op, geo.stripStartX, geo.stripStartY, 1)
+			op.ColorScale.Scale(stripBrightness, stripBrightness, stripBrightness, 1)
+			screen.DrawImage(stripImg, op)
+		}
 	}
 
-	// Draw dials - evenly spaced across strip width
-	for i := 0; i < dialCount; i++ {
-		x := stripStartX + dialSpacing + i*(dialSize+dialSpacing)
-		y := dialStartY
-
-		// Calculate dial center
-		cx := x + dialSize/2
-		cy := y + dialSize/2
-		radius := dialSize / 2
-
-		// Draw dial as concentric circles (outer ring, inner dial)
-		drawCircle(screen, cx, cy, radius, color.RGBA{80, 80, 80, 255})
-		drawCircle(screen, cx, cy, radius-8, color.RGBA{50, 50, 50, 255})
-		drawCircle(screen, cx, cy, radius-12, color.RGBA{70, 70, 70, 255})
-
-		// Draw dial label
-		label := fmt.Sprintf("D%d", i+1)
-		ebitenutil.DebugPrintAt(screen, label, cx-8, cy-4)
+	// Draw dials - evenly spaced across the content width
+	for i := 0; i < m.Dials; i++ {
+		g.drawDial(screen, i)
 	}
 
 	// Draw instructions
-	instrY := windowHeight - 18
-	ebitenutil.DebugPrintAt(screen, "Click keys | Scroll over dials | Click/drag touch strip", 10, instrY)
+	instr := "Click or 1-8: keys"
+	if m.Dials > 0 {
+		instr += fmt.Sprintf(" | Drag, scroll or [ ]: turn dials (D%d), right-click: press", g.emu.activeDial+1)
+	}
+	if m.Strip {
+		instr += " | Click/drag touch strip"
+	}
+	if g.emu.recording != nil {
+		instr += " | REC (F9 to stop)"
+	} else {
+		instr += " | F12: screenshot, F9: record"
+	}
+	if g.emu.unplugged {
+		instr = "Unplugged | F7: plug back in"
+	}
+	v.label(screen, instr, 10, geo.windowHeight-18)
 }
 
+// Layout makes the screen the window's size in physical pixels, for the
+// monitor the window is on now, so the deck is drawn at that resolution
+// rather than scaled up from the layout's.
 func (g *emulatorGame) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return windowWidth, windowHeight
+	dsf := ebiten.Monitor().DeviceScaleFactor()
+	g.view = newView(g.emu.geo, float64(outsideWidth), float64(outsideHeight), dsf)
+	return int(math.Ceil(float64(outsideWidth) * dsf)), int(math.Ceil(float64(outsideHeight) * dsf))
+}
+
+// dialAt returns the index of the dial under (x, y), or -1.
+func (g *emulatorGame) dialAt(x, y int) int {
+	radius := dialSize / 2
+	for i := 0; i < g.emu.model.Dials; i++ {
+		cx, cy := g.emu.geo.dialCenter(i)
+		distX, distY := x-cx, y-cy
+		if distX*distX+distY*distY <= radius*radius {
+			return i
+		}
+	}
+	return -1
 }
 
 func (g *emulatorGame) handleInput() {
-	mx, my := ebiten.CursorPosition()
+	mx, my := g.view.layoutPt(ebiten.CursorPosition())
 	mousePressed := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
 
-	// Calculate positions (same layout as in Draw)
-	keysStartX := marginX + keySpacing // First key offset by spacing
-	keysStartY := headerHeight + marginY
-	stripStartX := marginX
-	stripStartY := keysStartY + keyAreaHeight + stripMarginY
-	dialStartY := stripStartY + stripHeight + dialMarginY
+	m, geo := g.emu.model, g.emu.geo
 
-	// Handle key clicks
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		// Check if click is on a key
-		for i := 0; i < keyCount; i++ {
-			row := i / keysPerRow
-			col := i % keysPerRow
+	g.handleKeyboard(mx, my)
+	g.handleDialMouse(mx, my)
 
-			kx := keysStartX + col*(keyDisplaySize+keySpacing)
-			ky := keysStartY + row*(keyDisplaySize+keySpacing)
-
-			if mx >= kx && mx < kx+keyDisplaySize && my >= ky && my < ky+keyDisplaySize {
-				g.triggerKeyPress(device.KeyID(i + 1))
-				return
-			}
-		}
+	// Release a key held by the mouse
+	if g.emu.mouseRelease != nil && !mousePressed {
+		g.emu.mouseRelease()
+		g.emu.mouseRelease = nil
+	}
 
-		// Check if click is on a dial (circular hit detection)
-		for i := 0; i < dialCount; i++ {
-			dx := stripStartX + dialSpacing + i*(dialSize+dialSpacing)
-			dy := dialStartY
-			// Dial center
-			cx := dx + dialSize/2
-			cy := dy + dialSize/2
-			radius := dialSize / 2
-
-			// Check if click is within circle
-			distX := mx - cx
-			distY := my - cy
-			if distX*distX+distY*distY <= radius*radius {
-				g.triggerDialPress(device.DialID(i + 1))
+	// Handle key clicks
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		// Check if click is on a key; it stays pressed until the button is released
+		for i := 0; i < m.keyCount(); i++ {
+			kx, ky := geo.keyOrigin(m, i)
+			if mx >= kx && mx < kx+m.KeyDisplaySize && my >= ky && my < ky+m.KeyDisplaySize {
+				g.emu.mouseRelease = g.emu.triggerKeyPress(device.KeyID(i + 1))
 				return
 			}
 		}
 
 		// Check if click is on touch strip - strip is at native resolution
-		if mx >= stripStartX && mx < stripStartX+stripWidth && my >= stripStartY && my < stripStartY+stripHeight {
+		if m.Strip && mx >= geo.stripStartX && mx < geo.stripStartX+stripWidth && my >= geo.stripStartY && my < geo.stripStartY+stripHeight {
 			g.emu.dragging = true
 			// Coordinates are already in strip space (native resolution)
-			g.emu.dragStart = image.Point{X: mx - stripStartX, Y: my - stripStartY}
+			g.emu.dragStart = image.Point{X: mx - geo.stripStartX, Y: my - geo.stripStartY}
 			g.emu.dragStartTime = time.Now()
 		}
 	}
 
 	// Handle touch strip drag/release
 	if g.emu.dragging && !mousePressed {
-		// Get end point in strip coordinates
-		endX := mx - stripStartX
-		endY := my - stripStartY
-
-		// Clamp to strip bounds
-		if endX < 0 {
-			endX = 0
-		}
-		if endX >= stripWidth {
-			endX = stripWidth - 1
-		}
-		if endY < 0 {
-			endY = 0
-		}
-		if endY >= stripHeight {
-			endY = stripHeight - 1
-		}
+		// Get end point in strip coordinates, clamped to strip bounds
+		endX := min(max(mx-geo.stripStartX, 0), stripWidth-1)
+		endY := min(max(my-geo.stripStartY, 0), stripHeight-1)
 
 		endPoint := image.Point{X: endX, Y: endY}
 		duration := time.Since(g.emu.dragStartTime)
@@ -530,10 +938,10 @@ func (g *emulatorGame) handleInput() {
 			if duration > 500*time.Millisecond {
 				touchType = device.TOUCH_STRIP_TOUCH_TYPE_LONG
 			}
-			g.triggerStripTouch(touchType, g.emu.dragStart)
+			g.emu.triggerStripTouch(touchType, g.emu.dragStart)
 		} else {
 			// It's a swipe
-			g.triggerStripSwipe(g.emu.dragStart, endPoint)
+			g.emu.triggerStripSwipe(g.emu.dragStart, endPoint)
 		}
 
 		g.emu.dragging = false
@@ -542,98 +950,145 @@ func (g *emulatorGame) handleInput() {
 	// Handle scroll wheel for dial rotation (circular hit detection)
 	_, wheelY := ebiten.Wheel()
 	if wheelY != 0 {
-		// Check which dial (if any) the cursor is over
-		for i := 0; i < dialCount; i++ {
-			dx := stripStartX + dialSpacing + i*(dialSize+dialSpacing)
-			dy := dialStartY
-			// Dial center
-			cx := dx + dialSize/2
-			cy := dy + dialSize/2
-			radius := dialSize / 2
-
-			// Check if cursor is within circle
-			distX := mx - cx
-			distY := my - cy
-			if distX*distX+distY*distY <= radius*radius {
-				delta := int8(wheelY)
-				if delta > 5 {
-					delta = 5
-				} else if delta < -5 {
-					delta = -5
-				}
-				g.triggerDialRotate(device.DialID(i+1), delta)
-				break
+		if i := g.dialAt(mx, my); i >= 0 {
+			delta := int8(wheelY)
+			if delta > 5 {
+				delta = 5
+			} else if delta < -5 {
+				delta = -5
 			}
+			g.emu.activeDial = i
+			g.emu.triggerDialRotate(device.DialID(i+1), delta)
 		}
 	}
 
 	g.emu.prevMousePressed = mousePressed
 }
 
-func (g *emulatorGame) triggerKeyPress(keyID device.KeyID) {
-	g.emu.mu.RLock()
-	handlers := g.emu.keyHandlers[int(keyID)-1]
-	g.emu.mu.RUnlock()
+// handleKeyboard maps number keys 1-8 to device keys, held for as long as
+// the number key is, and [ / ] to rotating the dial under the cursor (or the
+// last dial used).
+func (g *emulatorGame) handleKeyboard(mx, my int) {
+	if g.emu.keyboardRelease == nil {
+		g.emu.keyboardRelease = make(map[ebiten.Key]func())
+	}
 
+	for i := 0; i < 8 && i < g.emu.model.keyCount(); i++ {
+		k := ebiten.KeyDigit1 + ebiten.Key(i)
+		if inpututil.IsKeyJustPressed(k) {
+			g.emu.keyboardRelease[k] = g.emu.triggerKeyPress(device.KeyID(i + 1))
+		}
+		if inpututil.IsKeyJustReleased(k) {
+			if release, ok := g.emu.keyboardRelease[k]; ok {
+				release()
+				delete(g.emu.keyboardRelease, k)
+			}
+		}
+	}
+
+	if g.emu.model.Dials == 0 {
+		return
+	}
+	var delta int8
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+		delta = -1
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+		delta = 1
+	}
+	if delta == 0 {
+		return
+	}
+	if i := g.dialAt(mx, my); i >= 0 {
+		g.emu.activeDial = i
+	}
+	g.emu.triggerDialRotate(device.DialID(g.emu.activeDial+1), delta)
+}
+
+// triggerKeyPress fires the key's handlers and returns a function that
+// releases the key, ending their WaitForRelease.
+func (e *Emulator) triggerKeyPress(keyID device.KeyID) func() {
+	e.mu.RLock()
+	handlers := e.keyHandlers[int(keyID)-1]
+	e.mu.RUnlock()
+
+	var keys []*emulatorKey
 	for _, handler := range handlers {
 		key := &emulatorKey{
 			id:        keyID,
 			releaseCh: make(chan struct{}),
+			pressTime: time.Now(),
 		}
+		keys = append(keys, key)
 
 		// Fire handler in goroutine
 		go func(h device.KeyHandler, k *emulatorKey) {
-			if err := h(g.emu, k); err != nil {
-				if g.emu.errorCh != nil {
+			if err := h(e, k); err != nil {
+				if e.errorCh != nil {
 					select {
-					case g.emu.errorCh <- err:
+					case e.errorCh <- err:
 					default:
 					}
 				}
 			}
 		}(handler, key)
+	}
 
-		// Simulate immediate release for click (not hold)
-		go func(k *emulatorKey) {
-			time.Sleep(50 * time.Millisecond)
+	return func() {
+		for _, k := range keys {
 			k.release()
-		}(key)
+		}
 	}
 }
 
-func (g *emulatorGame) triggerDialPress(dialID device.DialID) {
-	g.emu.mu.RLock()
-	handlers := g.emu.dialSwitchHandlers[int(dialID)-1]
-	g.emu.mu.RUnlock()
+// triggerDialPress fires the dial's press handlers and returns a function
+// that releases the dial.
+func (e *Emulator) triggerDialPress(dialID device.DialID) func() {
+	e.mu.RLock()
+	handlers := e.dialSwitchHandlers[int(dialID)-1]
+	e.mu.RUnlock()
+
+	idx := int(dialID) - 1
+	e.mu.Lock()
+	e.dialHeld[idx] = time.Now()
+	e.mu.Unlock()
 
+	var dials []*emulatorDial
 	for _, handler := range handlers {
 		dial := &emulatorDial{
 			id:        dialID,
 			releaseCh: make(chan struct{}),
+			pressTime: time.Now(),
 		}
+		dials = append(dials, dial)
 
 		go func(h device.DialSwitchHandler, d *emulatorDial) {
-			if err := h(g.emu, d); err != nil {
-				if g.emu.errorCh != nil {
+			if err := h(e, d); err != nil {
+				if e.errorCh != nil {
 					select {
-					case g.emu.errorCh <- err:
+					case e.errorCh <- err:
 					default:
 					}
 				}
 			}
 		}(handler, dial)
+	}
 
-		go func(d *emulatorDial) {
-			time.Sleep(50 * time.Millisecond)
+	return func() {
+		e.mu.Lock()
+		e.dialHeld[idx] = time.Time{}
+		e.mu.Unlock()
+		for _, d := range dials {
 			d.release()
-		}(dial)
+		}
 	}
 }
 
-func (g *emulatorGame) triggerDialRotate(dialID device.DialID, delta int8) {
-	g.emu.mu.RLock()
-	handlers := g.emu.dialRotateHandlers[int(dialID)-1]
-	g.emu.mu.RUnlock()
+func (e *Emulator) triggerDialRotate(dialID device.DialID, delta int8) {
+	e.mu.Lock()
+	handlers := e.dialRotateHandlers[int(dialID)-1]
+	e.dialTurns[int(dialID)-1] += int(delta)
+	e.mu.Unlock()
 
 	for _, handler := range handlers {
 		dial := &emulatorDial{
@@ -642,10 +1097,10 @@ func (g *emulatorGame) triggerDialRotate(dialID device.DialID, delta int8) {
 		}
 
 		go func(h device.DialRotateHandler, d *emulatorDial, delta int8) {
-			if err := h(g.emu, d, delta); err != nil {
-				if g.emu.errorCh != nil {
+			if err := h(e, d, delta); err != nil {
+				if e.errorCh != nil {
 					select {
-					case g.emu.errorCh <- err:
+					case e.errorCh <- err:
 					default:
 					}
 				}
@@ -654,17 +1109,17 @@ func (g *emulatorGame) triggerDialRotate(dialID device.DialID, delta int8) {
 	}
 }
 
-func (g *emulatorGame) triggerStripTouch(touchType device.TouchStripTouchType, point image.Point) {
-	g.emu.mu.RLock()
-	handlers := g.emu.stripTouchHandlers
-	g.emu.mu.RUnlock()
+func (e *Emulator) triggerStripTouch(touchType device.TouchStripTouchType, point image.Point) {
+	e.mu.RLock()
+	handlers := e.stripTouchHandlers
+	e.mu.RUnlock()
 
 	for _, handler := range handlers {
 		go func(h device.TouchStripTouchHandler) {
-			if err := h(g.emu, touchType, point); err != nil {
-				if g.emu.errorCh != nil {
+			if err := h(e, touchType, point); err != nil {
+				if e.errorCh != nil {
 					select {
-					case g.emu.errorCh <- err:
+					case e.errorCh <- err:
 					default:
 					}
 				}
@@ -673,17 +1128,17 @@ func (g *emulatorGame) triggerStripTouch(touchType device.TouchStripTouchType, p
 	}
 }
 
-func (g *emulatorGame) triggerStripSwipe(origin, destination image.Point) {
-	g.emu.mu.RLock()
-	handlers := g.emu.stripSwipeHandlers
-	g.emu.mu.RUnlock()
+func (e *Emulator) triggerStripSwipe(origin, destination image.Point) {
+	e.mu.RLock()
+	handlers := e.stripSwipeHandlers
+	e.mu.RUnlock()
 
 	for _, handler := range handlers {
 		go func(h device.TouchStripSwipeHandler) {
-			if err := h(g.emu, origin, destination); err != nil {
-				if g.emu.errorCh != nil {
+			if err := h(e, origin, destination); err != nil {
+				if e.errorCh != nil {
 					select {
-					case g.emu.errorCh <- err:
+					case e.errorCh <- err:
 					default:
 					}
 				}
@@ -704,8 +1159,9 @@ func (k *emulatorKey) GetID() device.KeyID {
 	return k.id
 }
 
+// WaitForRelease blocks until the key is released and returns how long it
+// was held, measured from the press.
 func (k *emulatorKey) WaitForRelease() time.Duration {
-	k.pressTime = time.Now()
 	<-k.releaseCh
 	return time.Since(k.pressTime)
 }
@@ -728,8 +1184,9 @@ func (d *emulatorDial) GetID() device.DialID {
 	return d.id
 }
 
+// WaitForRelease blocks until the dial is released and returns how long it
+// was held, measured from the press.
 func (d *emulatorDial) WaitForRelease() time.Duration {
-	d.pressTime = time.Now()
 	<-d.releaseCh
 	return time.Since(d.pressTime)
 }
@@ -740,38 +1197,6 @@ func (d *emulatorDial) release() {
 	})
 }
 
-// Helper function to draw a filled rectangle
-func drawRect(screen *ebiten.Image, x, y, w, h int, c color.Color) {
-	rect := ebiten.NewImage(w, h)
-	rect.Fill(c)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	screen.DrawImage(rect, op)
-}
-
-// Helper function to draw a filled circle
-func drawCircle(screen *ebiten.Image, cx, cy, radius int, c color.Color) {
-	diameter := radius * 2
-	circle := ebiten.NewImage(diameter, diameter)
-
-	r, g, b, a := c.RGBA()
-	col := color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
-
-	for y := 0; y < diameter; y++ {
-		for x := 0; x < diameter; x++ {
-			dx := x - radius
-			dy := y - radius
-			if dx*dx+dy*dy <= radius*radius {
-				circle.Set(x, y, col)
-			}
-		}
-	}
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(cx-radius), float64(cy-radius))
-	screen.DrawImage(circle, op)
-}
-
 // scaleImageNearest scales an image using nearest-neighbor interpolation for crisp pixel scaling.
 func scaleImageNearest(src *image.RGBA, newWidth, newHeight int) *image.RGBA {
 	srcBounds := src.Bounds()
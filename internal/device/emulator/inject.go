@@ -0,0 +1,72 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+// Input injection, for replaying recorded input (see the replay package).
+// Handlers run as they do for clicks in the window: each in a goroutine of
+// its own, with holds that take real time.
+
+// Listening reports whether a coordinator is listening, having registered
+// its handlers, so injected input has somewhere to go.
+func (e *Emulator) Listening() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.listening
+}
+
+// InjectKeyPress presses a key and releases it after hold, returning once
+// it's released.
+func (e *Emulator) InjectKeyPress(key device.KeyID, hold time.Duration) error {
+	if int(key) < 1 || int(key) > e.model.keyCount() {
+		return fmt.Errorf("emulator: invalid key ID: %d", key)
+	}
+	release := e.triggerKeyPress(key)
+	time.Sleep(hold)
+	release()
+	return nil
+}
+
+// InjectDialPress presses a dial and releases it after hold, returning
+// once it's released.
+func (e *Emulator) InjectDialPress(dial device.DialID, hold time.Duration) error {
+	if int(dial) < 1 || int(dial) > e.model.Dials {
+		return fmt.Errorf("emulator: invalid dial ID: %d", dial)
+	}
+	release := e.triggerDialPress(dial)
+	time.Sleep(hold)
+	release()
+	return nil
+}
+
+// InjectDialRotate rotates a dial by delta steps.
+func (e *Emulator) InjectDialRotate(dial device.DialID, delta int8) error {
+	if int(dial) < 1 || int(dial) > e.model.Dials {
+		return fmt.Errorf("emulator: invalid dial ID: %d", dial)
+	}
+	e.triggerDialRotate(dial, delta)
+	return nil
+}
+
+// InjectStripTouch taps the touch strip at p.
+func (e *Emulator) InjectStripTouch(touchType device.TouchStripTouchType, p image.Point) error {
+	if !e.model.Strip {
+		return fmt.Errorf("emulator: %s has no touch strip", e.model.Name)
+	}
+	e.triggerStripTouch(touchType, p)
+	return nil
+}
+
+// InjectStripSwipe swipes the touch strip from origin to destination.
+func (e *Emulator) InjectStripSwipe(origin, destination image.Point) error {
+	if !e.model.Strip {
+		return fmt.Errorf("emulator: %s has no touch strip", e.model.Name)
+	}
+	e.triggerStripSwipe(origin, destination)
+	return nil
+}
@@ -0,0 +1,86 @@
+package emulator
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// view maps the window layout, in geometry's units, onto the screen, which
+// is the window in physical pixels. The layout is scaled to fit the window,
+// keeping its proportions, and centred.
+type view struct {
+	scale      float64 // screen pixels per layout unit
+	offX, offY float64 // screen position of the layout's origin
+}
+
+// newView fits geo into a window of w by h device-independent pixels on a
+// monitor with device scale factor dsf.
+func newView(geo geometry, w, h, dsf float64) view {
+	scale := min(w/float64(geo.windowWidth), h/float64(geo.windowHeight)) * dsf
+	return view{
+		scale: scale,
+		offX:  (w*dsf - float64(geo.windowWidth)*scale) / 2,
+		offY:  (h*dsf - float64(geo.windowHeight)*scale) / 2,
+	}
+}
+
+// pt returns the screen position of layout point (x, y).
+func (v view) pt(x, y int) (float32, float32) {
+	return float32(v.offX + float64(x)*v.scale), float32(v.offY + float64(y)*v.scale)
+}
+
+// layoutPt returns the layout point at screen position (x, y).
+func (v view) layoutPt(x, y int) (int, int) {
+	if v.scale == 0 {
+		return x, y // before the first Layout
+	}
+	return int(math.Floor((float64(x) - v.offX) / v.scale)), int(math.Floor((float64(y) - v.offY) / v.scale))
+}
+
+// place sets op to draw an image with its top left at layout point (x, y),
+// each of its pixels ratio layout units across.
+func (v view) place(op *ebiten.DrawImageOptions, x, y int, ratio float64) {
+	op.GeoM.Scale(ratio*v.scale, ratio*v.scale)
+	sx, sy := v.pt(x, y)
+	op.GeoM.Translate(float64(sx), float64(sy))
+	op.Filter = filterFor(ratio * v.scale)
+}
+
+// filterFor picks nearest-neighbour filtering for whole-number scales,
+// which keeps pixels sharp, and linear otherwise.
+func filterFor(scale float64) ebiten.Filter {
+	if scale == math.Trunc(scale) {
+		return ebiten.FilterNearest
+	}
+	return ebiten.FilterLinear
+}
+
+// fillRect fills a rectangle given in layout units.
+func (v view) fillRect(screen *ebiten.Image, x, y, w, h int, c color.Color) {
+	sx, sy := v.pt(x, y)
+	vector.FillRect(screen, sx, sy, float32(float64(w)*v.scale), float32(float64(h)*v.scale), c, false)
+}
+
+// fillCircle fills a circle given in layout units.
+func (v view) fillCircle(screen *ebiten.Image, cx, cy, radius int, c color.Color) {
+	sx, sy := v.pt(cx, cy)
+	vector.FillCircle(screen, sx, sy, float32(float64(radius)*v.scale), c, true)
+}
+
+// label prints text with its top left at layout point (x, y), in the debug
+// font scaled with the rest of the window.
+func (v view) label(screen *ebiten.Image, text string, x, y int) {
+	const charWidth, lineHeight = 6, 16
+	img := ebiten.NewImage(len(text)*charWidth+1, lineHeight)
+	ebitenutil.DebugPrint(img, text)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(v.scale, v.scale)
+	sx, sy := v.pt(x, y)
+	op.GeoM.Translate(float64(sx), float64(sy))
+	op.Filter = filterFor(v.scale)
+	screen.DrawImage(img, op)
+}
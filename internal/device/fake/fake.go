@@ -0,0 +1,471 @@
+// Package fake provides an in-memory Stream Deck Plus for tests and
+// offscreen rendering. It records the images written to it and lets tests
+// inject key, dial, and touch strip events without a GUI or hardware.
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+// Geometry of the emulated Stream Deck Plus.
+const (
+	keyCount    = 8
+	dialCount   = 4
+	keySize     = 72
+	stripWidth  = 800
+	stripHeight = 100
+)
+
+// Device is an in-memory device.Device. Event injection is synchronous:
+// Inject* methods return once every registered handler has returned.
+type Device struct {
+	mu sync.RWMutex
+
+	open        bool
+	listening   bool
+	closed      chan struct{}
+	brightness  byte // keys
+	stripBright byte
+	keyImages   map[device.KeyID]*image.RGBA
+	stripImage  *image.RGBA
+	keyWrites   int
+	stripWrites int
+
+	keyHandlers        map[device.KeyID][]device.KeyHandler
+	dialRotateHandlers map[device.DialID][]device.DialRotateHandler
+	dialSwitchHandlers map[device.DialID][]device.DialSwitchHandler
+	stripTouchHandlers []device.TouchStripTouchHandler
+	stripSwipeHandlers []device.TouchStripSwipeHandler
+}
+
+// New returns a closed fake device.
+func New() *Device {
+	return &Device{
+		keyImages:          make(map[device.KeyID]*image.RGBA),
+		keyHandlers:        make(map[device.KeyID][]device.KeyHandler),
+		dialRotateHandlers: make(map[device.DialID][]device.DialRotateHandler),
+		dialSwitchHandlers: make(map[device.DialID][]device.DialSwitchHandler),
+	}
+}
+
+// Open marks the device open.
+func (d *Device) Open() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.open {
+		return fmt.Errorf("fake: device is already open")
+	}
+	d.open = true
+	d.closed = make(chan struct{})
+	return nil
+}
+
+// Close marks the device closed, unblocking Listen.
+func (d *Device) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.open {
+		return fmt.Errorf("fake: device is not open")
+	}
+	d.open = false
+	d.listening = false
+	close(d.closed)
+	return nil
+}
+
+// IsOpen returns whether the device is open.
+func (d *Device) IsOpen() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.open
+}
+
+// GetModelName returns the fake model name.
+func (d *Device) GetModelName() string {
+	return "Stream Deck Plus (Fake)"
+}
+
+// GetKeyCount returns the number of keys.
+func (d *Device) GetKeyCount() byte {
+	return keyCount
+}
+
+// GetDialCount returns the number of dials.
+func (d *Device) GetDialCount() byte {
+	return dialCount
+}
+
+// GetTouchStripSupported returns true.
+func (d *Device) GetTouchStripSupported() bool {
+	return true
+}
+
+// GetKeyImageRectangle returns the key image dimensions.
+func (d *Device) GetKeyImageRectangle() (image.Rectangle, error) {
+	return image.Rect(0, 0, keySize, keySize), nil
+}
+
+// GetTouchStripImageRectangle returns the touch strip dimensions.
+func (d *Device) GetTouchStripImageRectangle() (image.Rectangle, error) {
+	return image.Rect(0, 0, stripWidth, stripHeight), nil
+}
+
+// GetSerialNumber returns a made-up serial number.
+func (d *Device) GetSerialNumber() string {
+	return "FAKE0000001"
+}
+
+// GetFirmwareVersion returns a made-up firmware version.
+func (d *Device) GetFirmwareVersion() (string, error) {
+	return "1.00.000", nil
+}
+
+// GetUSBPath returns "": the fake isn't on USB.
+func (d *Device) GetUSBPath() string {
+	return ""
+}
+
+// SetBrightness records the brightness of the keys and strip.
+func (d *Device) SetBrightness(perc byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.brightness, d.stripBright = perc, perc
+	return nil
+}
+
+// GetRegionBrightnessSupported returns true.
+func (d *Device) GetRegionBrightnessSupported() bool {
+	return true
+}
+
+// SetKeyBrightness records the keys' brightness.
+func (d *Device) SetKeyBrightness(perc byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.brightness = perc
+	return nil
+}
+
+// SetStripBrightness records the strip's brightness.
+func (d *Device) SetStripBrightness(perc byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stripBright = perc
+	return nil
+}
+
+// SetKeyImage records a copy of the image for a key.
+func (d *Device) SetKeyImage(key device.KeyID, img image.Image) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.keyImages[key] = clone(img)
+	d.keyWrites++
+	return nil
+}
+
+// SetKeyImages records copies of several keys' images, counting a write
+// for each.
+func (d *Device) SetKeyImages(images map[device.KeyID]image.Image) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var errs []error
+	for key, img := range images {
+		if err := checkKey(key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		d.keyImages[key] = clone(img)
+		d.keyWrites++
+	}
+	return errors.Join(errs...)
+}
+
+// SetTouchStripImage records a copy of the touch strip image.
+func (d *Device) SetTouchStripImage(img image.Image) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stripImage = clone(img)
+	d.stripWrites++
+	return nil
+}
+
+// SetTouchStripImageRect records img over rect of the touch strip image.
+func (d *Device) SetTouchStripImageRect(img image.Image, rect image.Rectangle) error {
+	if !rect.In(image.Rect(0, 0, stripWidth, stripHeight)) {
+		return fmt.Errorf("fake: strip rectangle %v out of bounds", rect)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stripImage == nil {
+		d.stripImage = image.NewRGBA(image.Rect(0, 0, stripWidth, stripHeight))
+	}
+	draw.Draw(d.stripImage, rect, img, img.Bounds().Min, draw.Src)
+	d.stripWrites++
+	return nil
+}
+
+// ClearKey sets a key to black.
+func (d *Device) ClearKey(key device.KeyID) error {
+	return d.SetKeyImage(key, image.NewRGBA(image.Rect(0, 0, keySize, keySize)))
+}
+
+// ForEachKey calls the callback for each key.
+func (d *Device) ForEachKey(cb func(device.KeyID) error) error {
+	for i := 1; i <= keyCount; i++ {
+		if err := cb(device.KeyID(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachDial calls the callback for each dial.
+func (d *Device) ForEachDial(cb func(device.DialID) error) error {
+	for i := 1; i <= dialCount; i++ {
+		if err := cb(device.DialID(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddKeyHandler registers a key press handler.
+func (d *Device) AddKeyHandler(key device.KeyID, fn device.KeyHandler) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.keyHandlers[key] = append(d.keyHandlers[key], fn)
+	return nil
+}
+
+// AddDialRotateHandler registers a dial rotation handler.
+func (d *Device) AddDialRotateHandler(dial device.DialID, fn device.DialRotateHandler) error {
+	if err := checkDial(dial); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dialRotateHandlers[dial] = append(d.dialRotateHandlers[dial], fn)
+	return nil
+}
+
+// AddDialSwitchHandler registers a dial press handler.
+func (d *Device) AddDialSwitchHandler(dial device.DialID, fn device.DialSwitchHandler) error {
+	if err := checkDial(dial); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dialSwitchHandlers[dial] = append(d.dialSwitchHandlers[dial], fn)
+	return nil
+}
+
+// AddTouchStripTouchHandler registers a touch strip touch handler.
+func (d *Device) AddTouchStripTouchHandler(fn device.TouchStripTouchHandler) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stripTouchHandlers = append(d.stripTouchHandlers, fn)
+	return nil
+}
+
+// AddTouchStripSwipeHandler registers a touch strip swipe handler.
+func (d *Device) AddTouchStripSwipeHandler(fn device.TouchStripSwipeHandler) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stripSwipeHandlers = append(d.stripSwipeHandlers, fn)
+	return nil
+}
+
+// Listen blocks until the device is closed.
+func (d *Device) Listen(errCh chan error) error {
+	d.mu.Lock()
+	if !d.open {
+		d.mu.Unlock()
+		return fmt.Errorf("fake: device is not open")
+	}
+	d.listening = true
+	closed := d.closed
+	d.mu.Unlock()
+
+	<-closed
+	return nil
+}
+
+// Listening reports whether Listen is running. The coordinator registers its
+// handlers before listening, so this tells tests when events can be injected.
+func (d *Device) Listening() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.listening
+}
+
+// InjectKeyPress presses a key and holds it for hold. The hold is simulated:
+// WaitForRelease returns hold immediately rather than sleeping.
+func (d *Device) InjectKeyPress(key device.KeyID, hold time.Duration) error {
+	d.mu.RLock()
+	handlers := d.keyHandlers[key]
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		errs = append(errs, h(d, &fakeKey{id: key, hold: hold}))
+	}
+	return errors.Join(errs...)
+}
+
+// InjectDialPress presses a dial and holds it for hold, simulated as in
+// InjectKeyPress.
+func (d *Device) InjectDialPress(dial device.DialID, hold time.Duration) error {
+	d.mu.RLock()
+	handlers := d.dialSwitchHandlers[dial]
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		errs = append(errs, h(d, &fakeDial{id: dial, hold: hold}))
+	}
+	return errors.Join(errs...)
+}
+
+// InjectDialRotate rotates a dial by delta steps.
+func (d *Device) InjectDialRotate(dial device.DialID, delta int8) error {
+	d.mu.RLock()
+	handlers := d.dialRotateHandlers[dial]
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		errs = append(errs, h(d, &fakeDial{id: dial}, delta))
+	}
+	return errors.Join(errs...)
+}
+
+// InjectStripTouch taps the touch strip at p.
+func (d *Device) InjectStripTouch(touchType device.TouchStripTouchType, p image.Point) error {
+	d.mu.RLock()
+	handlers := d.stripTouchHandlers
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		errs = append(errs, h(d, touchType, p))
+	}
+	return errors.Join(errs...)
+}
+
+// InjectStripSwipe swipes the touch strip from origin to destination.
+func (d *Device) InjectStripSwipe(origin, destination image.Point) error {
+	d.mu.RLock()
+	handlers := d.stripSwipeHandlers
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		errs = append(errs, h(d, origin, destination))
+	}
+	return errors.Join(errs...)
+}
+
+// KeyImage returns the last image set on a key, or nil if none was.
+func (d *Device) KeyImage(key device.KeyID) *image.RGBA {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.keyImages[key]
+}
+
+// StripImage returns the last touch strip image, or nil if none was set.
+func (d *Device) StripImage() *image.RGBA {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.stripImage
+}
+
+// Writes returns how many key and strip images have been set.
+func (d *Device) Writes() (keys, strip int) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.keyWrites, d.stripWrites
+}
+
+// Brightness returns the keys' last brightness set.
+func (d *Device) Brightness() byte {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.brightness
+}
+
+// StripBrightness returns the strip's last brightness set.
+func (d *Device) StripBrightness() byte {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.stripBright
+}
+
+func checkKey(key device.KeyID) error {
+	if key < 1 || key > keyCount {
+		return fmt.Errorf("fake: invalid key ID: %d", key)
+	}
+	return nil
+}
+
+func checkDial(dial device.DialID) error {
+	if dial < 1 || dial > dialCount {
+		return fmt.Errorf("fake: invalid dial ID: %d", dial)
+	}
+	return nil
+}
+
+// clone copies img so later changes by the caller don't leak into the record.
+func clone(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// fakeKey implements device.Key with a simulated hold.
+type fakeKey struct {
+	id   device.KeyID
+	hold time.Duration
+}
+
+func (k *fakeKey) GetID() device.KeyID {
+	return k.id
+}
+
+func (k *fakeKey) WaitForRelease() time.Duration {
+	return k.hold
+}
+
+// fakeDial implements device.Dial with a simulated hold.
+type fakeDial struct {
+	id   device.DialID
+	hold time.Duration
+}
+
+func (d *fakeDial) GetID() device.DialID {
+	return d.id
+}
+
+func (d *fakeDial) WaitForRelease() time.Duration {
+	return d.hold
+}
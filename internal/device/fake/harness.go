@@ -0,0 +1,89 @@
+package fake
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// waitTimeout bounds how long Harness waits for the render loop.
+const waitTimeout = 2 * time.Second
+
+// Harness runs a coordinator against a fake device for a test. Register
+// modules, call Start, inject events on Device, and wait for the render
+// loop with WaitFor or WaitForKey.
+type Harness struct {
+	t           testing.TB
+	Device      *Device
+	Coordinator *coordinator.Coordinator
+}
+
+// NewHarness opens a fake device and creates a coordinator for it.
+func NewHarness(t testing.TB) *Harness {
+	t.Helper()
+
+	dev := New()
+	if err := dev.Open(); err != nil {
+		t.Fatalf("opening fake device: %v", err)
+	}
+	return &Harness{t: t, Device: dev, Coordinator: coordinator.New(dev)}
+}
+
+// Register registers a module with the coordinator, failing the test on error.
+func (h *Harness) Register(m module.Module, res module.Resources) {
+	h.t.Helper()
+	if err := h.Coordinator.RegisterModule(m, res); err != nil {
+		h.t.Fatalf("registering %s: %v", m.ID(), err)
+	}
+}
+
+// Start runs the coordinator until the test ends and waits until it is
+// ready for injected events.
+func (h *Harness) Start() {
+	h.t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- h.Coordinator.Start(ctx)
+	}()
+
+	h.t.Cleanup(func() {
+		cancel()
+		h.Coordinator.Stop()
+		h.Device.Close()
+		<-done
+	})
+
+	h.WaitFor("coordinator to start listening", h.Device.Listening)
+}
+
+// WaitFor polls cond until it returns true, failing the test after a timeout.
+func (h *Harness) WaitFor(what string, cond func() bool) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// WaitForKey waits until the image on key satisfies match and returns it.
+func (h *Harness) WaitForKey(key module.KeyID, match func(*image.RGBA) bool) *image.RGBA {
+	h.t.Helper()
+
+	var img *image.RGBA
+	h.WaitFor("key image", func() bool {
+		img = h.Device.KeyImage(device.KeyID(key))
+		return img != nil && match(img)
+	})
+	return img
+}
@@ -0,0 +1,95 @@
+package fake_test
+
+import (
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device/fake"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// counter is a module that shows a color on its key and records key events.
+type counter struct {
+	module.BaseModule
+
+	mu     sync.Mutex
+	events []module.KeyEvent
+	color  color.RGBA
+}
+
+func newCounter() *counter {
+	return &counter{BaseModule: module.NewBaseModule("counter"), color: color.RGBA{255, 0, 0, 255}}
+}
+
+func (c *counter) RenderKeys() map[module.KeyID]image.Image {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	img := image.NewRGBA(image.Rect(0, 0, 72, 72))
+	img.Set(0, 0, c.color)
+	return map[module.KeyID]image.Image{module.Key1: img}
+}
+
+func (c *counter) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, event)
+	if !event.Pressed {
+		c.color = color.RGBA{0, 0, 255, 255}
+	}
+	return nil
+}
+
+func (c *counter) keyEvents() []module.KeyEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]module.KeyEvent(nil), c.events...)
+}
+
+func isColor(want color.RGBA) func(*image.RGBA) bool {
+	return func(img *image.RGBA) bool {
+		return img.RGBAAt(0, 0) == want
+	}
+}
+
+func TestHarnessRoutesKeyPressAndRenders(t *testing.T) {
+	h := fake.NewHarness(t)
+	m := newCounter()
+	h.Register(m, module.Resources{Keys: []module.KeyID{module.Key1}})
+	h.Start()
+
+	h.WaitForKey(module.Key1, isColor(color.RGBA{255, 0, 0, 255}))
+
+	if err := h.Device.InjectKeyPress(1, 700*time.Millisecond); err != nil {
+		t.Fatalf("InjectKeyPress: %v", err)
+	}
+
+	events := m.keyEvents()
+	if len(events) != 2 {
+		t.Fatalf("got %d key events, want 2", len(events))
+	}
+	if !events[0].Pressed || events[1].Pressed {
+		t.Errorf("got events %+v, want press then release", events)
+	}
+	if events[1].Duration != 700*time.Millisecond {
+		t.Errorf("release duration = %v, want 700ms", events[1].Duration)
+	}
+
+	h.WaitForKey(module.Key1, isColor(color.RGBA{0, 0, 255, 255}))
+}
+
+func TestInjectToUnownedKeyIsIgnored(t *testing.T) {
+	h := fake.NewHarness(t)
+	m := newCounter()
+	h.Register(m, module.Resources{Keys: []module.KeyID{module.Key1}})
+	h.Start()
+
+	if err := h.Device.InjectKeyPress(2, 0); err != nil {
+		t.Fatalf("InjectKeyPress: %v", err)
+	}
+	if n := len(m.keyEvents()); n != 0 {
+		t.Errorf("owner of key 1 got %d events for key 2", n)
+	}
+}
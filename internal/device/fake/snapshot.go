@@ -0,0 +1,46 @@
+package fake
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+// Snapshot layout: keys in rows of keyCols, each centered over a quarter of
+// the strip as on the deck, with the strip below.
+const (
+	keyCols        = 4
+	snapshotMargin = 16
+)
+
+// Snapshot composites the key and strip images into one picture of the
+// deck. Keys and strip that were never set, or are transparent, show black.
+func (d *Device) Snapshot() *image.RGBA {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	rows := (keyCount + keyCols - 1) / keyCols
+	stripY := snapshotMargin + rows*(keySize+snapshotMargin)
+	img := image.NewRGBA(image.Rect(0, 0, stripWidth+2*snapshotMargin, stripY+stripHeight+snapshotMargin))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{30, 30, 30, 255}), image.Point{}, draw.Src)
+
+	pitch := stripWidth / keyCols
+	for i := range keyCount {
+		x := snapshotMargin + (i%keyCols)*pitch + (pitch-keySize)/2
+		y := snapshotMargin + (i/keyCols)*(keySize+snapshotMargin)
+		r := image.Rect(x, y, x+keySize, y+keySize)
+		draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+		if key := d.keyImages[device.KeyID(i+1)]; key != nil {
+			draw.Draw(img, r, key, key.Bounds().Min, draw.Over)
+		}
+	}
+
+	r := image.Rect(snapshotMargin, stripY, snapshotMargin+stripWidth, stripY+stripHeight)
+	draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+	if d.stripImage != nil {
+		draw.Draw(img, r, d.stripImage, d.stripImage.Bounds().Min, draw.Over)
+	}
+	return img
+}
@@ -0,0 +1,12 @@
+package device
+
+// problemFirmware lists firmware versions known to misbehave with
+// belowdeck, by model name and then version, each with what goes wrong and
+// what to do about it. None are known yet; add them as they're reported.
+var problemFirmware = map[string]map[string]string{}
+
+// FirmwareWarning returns what's known to go wrong with firmware version fw
+// on model, or "" if nothing is.
+func FirmwareWarning(model, fw string) string {
+	return problemFirmware[model][fw]
+}
@@ -1,12 +1,19 @@
 package device
 
 import (
+	"errors"
 	"image"
+	"maps"
+	"slices"
 	"time"
 
 	"rafaelmartins.com/p/streamdeck"
+	"rafaelmartins.com/p/usbhid"
 )
 
+// elgatoVendorID is the USB vendor ID of every Stream Deck.
+const elgatoVendorID = 0x0fd9
+
 // HardwareDevice wraps the real streamdeck.Device to implement the Device interface.
 type HardwareDevice struct {
 	dev *streamdeck.Device
@@ -62,21 +69,78 @@ func (h *HardwareDevice) GetTouchStripImageRectangle() (image.Rectangle, error)
 	return h.dev.GetTouchStripImageRectangle()
 }
 
+// GetSerialNumber returns the device's serial number.
+func (h *HardwareDevice) GetSerialNumber() string {
+	return h.dev.GetSerialNumber()
+}
+
+// GetFirmwareVersion reads the device's firmware version.
+func (h *HardwareDevice) GetFirmwareVersion() (string, error) {
+	return h.dev.GetFirmwareVersion()
+}
+
+// GetUSBPath returns the device's USB HID path, found by enumerating USB
+// devices for its serial number, or "" if that fails.
+func (h *HardwareDevice) GetUSBPath() string {
+	serial := h.dev.GetSerialNumber()
+	devices, err := usbhid.Enumerate(func(d *usbhid.Device) bool {
+		return d.VendorId() == elgatoVendorID && d.SerialNumber() == serial
+	})
+	if err != nil || len(devices) == 0 {
+		return ""
+	}
+	return devices[0].Path()
+}
+
 // SetBrightness sets the device brightness.
 func (h *HardwareDevice) SetBrightness(perc byte) error {
 	return h.dev.SetBrightness(perc)
 }
 
+// GetRegionBrightnessSupported returns false: every Stream Deck has a
+// single backlight.
+func (h *HardwareDevice) GetRegionBrightnessSupported() bool {
+	return false
+}
+
+// SetKeyBrightness returns ErrRegionBrightness.
+func (h *HardwareDevice) SetKeyBrightness(perc byte) error {
+	return ErrRegionBrightness
+}
+
+// SetStripBrightness returns ErrRegionBrightness.
+func (h *HardwareDevice) SetStripBrightness(perc byte) error {
+	return ErrRegionBrightness
+}
+
 // SetKeyImage sets the image for a key.
 func (h *HardwareDevice) SetKeyImage(key KeyID, img image.Image) error {
 	return h.dev.SetKeyImage(streamdeck.KeyID(key), img)
 }
 
+// SetKeyImages sets several keys' images back to back, in key order. The
+// device takes one key per image report, so this saves only the
+// round trips through the interface.
+func (h *HardwareDevice) SetKeyImages(images map[KeyID]image.Image) error {
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(images)) {
+		if err := h.dev.SetKeyImage(streamdeck.KeyID(key), images[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // SetTouchStripImage sets the touch strip image.
 func (h *HardwareDevice) SetTouchStripImage(img image.Image) error {
 	return h.dev.SetTouchStripImage(img)
 }
 
+// SetTouchStripImageRect sets part of the touch strip image.
+func (h *HardwareDevice) SetTouchStripImageRect(img image.Image, rect image.Rectangle) error {
+	return h.dev.SetTouchStripImageWithRectangle(img, rect)
+}
+
 // ClearKey clears a key's image.
 func (h *HardwareDevice) ClearKey(key KeyID) error {
 	return h.dev.ClearKey(streamdeck.KeyID(key))
@@ -0,0 +1,180 @@
+package device
+
+import (
+	"image"
+	"sync"
+)
+
+// SwitchableDevice forwards to another Device that can be swapped out, so the
+// coordinator and modules can keep one Device across the hardware behind it
+// disconnecting and reconnecting.
+type SwitchableDevice struct {
+	mu  sync.RWMutex
+	dev Device
+}
+
+// NewSwitchable creates a switchable device forwarding to dev.
+func NewSwitchable(dev Device) *SwitchableDevice {
+	return &SwitchableDevice{dev: dev}
+}
+
+// Swap replaces the device calls are forwarded to. Event handlers aren't
+// carried over; register them again on the new device.
+func (s *SwitchableDevice) Swap(dev Device) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dev = dev
+}
+
+// current returns the device calls are forwarded to.
+func (s *SwitchableDevice) current() Device {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dev
+}
+
+// Open opens the current device.
+func (s *SwitchableDevice) Open() error {
+	return s.current().Open()
+}
+
+// Close closes the current device.
+func (s *SwitchableDevice) Close() error {
+	return s.current().Close()
+}
+
+// IsOpen returns whether the current device is open.
+func (s *SwitchableDevice) IsOpen() bool {
+	return s.current().IsOpen()
+}
+
+// GetModelName returns the current device's model name.
+func (s *SwitchableDevice) GetModelName() string {
+	return s.current().GetModelName()
+}
+
+// GetKeyCount returns the number of keys on the current device.
+func (s *SwitchableDevice) GetKeyCount() byte {
+	return s.current().GetKeyCount()
+}
+
+// GetDialCount returns the number of dials on the current device.
+func (s *SwitchableDevice) GetDialCount() byte {
+	return s.current().GetDialCount()
+}
+
+// GetTouchStripSupported returns whether the current device has a touch strip.
+func (s *SwitchableDevice) GetTouchStripSupported() bool {
+	return s.current().GetTouchStripSupported()
+}
+
+// GetKeyImageRectangle returns the dimensions for key images.
+func (s *SwitchableDevice) GetKeyImageRectangle() (image.Rectangle, error) {
+	return s.current().GetKeyImageRectangle()
+}
+
+// GetTouchStripImageRectangle returns the dimensions for the touch strip image.
+func (s *SwitchableDevice) GetTouchStripImageRectangle() (image.Rectangle, error) {
+	return s.current().GetTouchStripImageRectangle()
+}
+
+// GetSerialNumber returns the current device's serial number.
+func (s *SwitchableDevice) GetSerialNumber() string {
+	return s.current().GetSerialNumber()
+}
+
+// GetFirmwareVersion reads the current device's firmware version.
+func (s *SwitchableDevice) GetFirmwareVersion() (string, error) {
+	return s.current().GetFirmwareVersion()
+}
+
+// GetUSBPath returns the current device's USB HID path.
+func (s *SwitchableDevice) GetUSBPath() string {
+	return s.current().GetUSBPath()
+}
+
+// SetBrightness sets the current device's brightness.
+func (s *SwitchableDevice) SetBrightness(perc byte) error {
+	return s.current().SetBrightness(perc)
+}
+
+// GetRegionBrightnessSupported returns whether the current device lights
+// its keys and touch strip separately.
+func (s *SwitchableDevice) GetRegionBrightnessSupported() bool {
+	return s.current().GetRegionBrightnessSupported()
+}
+
+// SetKeyBrightness sets the current device's key brightness.
+func (s *SwitchableDevice) SetKeyBrightness(perc byte) error {
+	return s.current().SetKeyBrightness(perc)
+}
+
+// SetStripBrightness sets the current device's touch strip brightness.
+func (s *SwitchableDevice) SetStripBrightness(perc byte) error {
+	return s.current().SetStripBrightness(perc)
+}
+
+// SetKeyImage sets the image for a key.
+func (s *SwitchableDevice) SetKeyImage(key KeyID, img image.Image) error {
+	return s.current().SetKeyImage(key, img)
+}
+
+// SetKeyImages sets several keys' images.
+func (s *SwitchableDevice) SetKeyImages(images map[KeyID]image.Image) error {
+	return s.current().SetKeyImages(images)
+}
+
+// SetTouchStripImage sets the touch strip image.
+func (s *SwitchableDevice) SetTouchStripImage(img image.Image) error {
+	return s.current().SetTouchStripImage(img)
+}
+
+// SetTouchStripImageRect sets part of the touch strip image.
+func (s *SwitchableDevice) SetTouchStripImageRect(img image.Image, rect image.Rectangle) error {
+	return s.current().SetTouchStripImageRect(img, rect)
+}
+
+// ClearKey clears a key.
+func (s *SwitchableDevice) ClearKey(key KeyID) error {
+	return s.current().ClearKey(key)
+}
+
+// ForEachKey iterates over the current device's keys.
+func (s *SwitchableDevice) ForEachKey(cb func(KeyID) error) error {
+	return s.current().ForEachKey(cb)
+}
+
+// ForEachDial iterates over the current device's dials.
+func (s *SwitchableDevice) ForEachDial(cb func(DialID) error) error {
+	return s.current().ForEachDial(cb)
+}
+
+// AddKeyHandler adds a handler for a key press on the current device.
+func (s *SwitchableDevice) AddKeyHandler(key KeyID, fn KeyHandler) error {
+	return s.current().AddKeyHandler(key, fn)
+}
+
+// AddDialRotateHandler adds a handler for dial rotation on the current device.
+func (s *SwitchableDevice) AddDialRotateHandler(dial DialID, fn DialRotateHandler) error {
+	return s.current().AddDialRotateHandler(dial, fn)
+}
+
+// AddDialSwitchHandler adds a handler for dial press on the current device.
+func (s *SwitchableDevice) AddDialSwitchHandler(dial DialID, fn DialSwitchHandler) error {
+	return s.current().AddDialSwitchHandler(dial, fn)
+}
+
+// AddTouchStripTouchHandler adds a handler for touch strip touches on the current device.
+func (s *SwitchableDevice) AddTouchStripTouchHandler(fn TouchStripTouchHandler) error {
+	return s.current().AddTouchStripTouchHandler(fn)
+}
+
+// AddTouchStripSwipeHandler adds a handler for touch strip swipes on the current device.
+func (s *SwitchableDevice) AddTouchStripSwipeHandler(fn TouchStripSwipeHandler) error {
+	return s.current().AddTouchStripSwipeHandler(fn)
+}
+
+// Listen runs the current device's event loop.
+func (s *SwitchableDevice) Listen(errCh chan error) error {
+	return s.current().Listen(errCh)
+}
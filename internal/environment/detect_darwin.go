@@ -0,0 +1,48 @@
+package environment
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var (
+	wifiPortRE = regexp.MustCompile(`Hardware Port: Wi-Fi\nDevice: (\S+)`)
+	ssidRE     = regexp.MustCompile(`(?m)^\s+SSID : (.+)$`)
+)
+
+// wifiSSID asks ipconfig for the Wi-Fi interface's network. Recent macOS
+// versions redact it unless location access is granted, which reads as
+// no network.
+func wifiSSID() string {
+	dev := "en0"
+	if m := wifiPortRE.FindStringSubmatch(output("networksetup", "-listallhardwareports")); m != nil {
+		dev = m[1]
+	}
+	m := ssidRE.FindStringSubmatch(output("ipconfig", "getsummary", dev))
+	if m == nil || m[1] == "<redacted>" {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
+
+// monitors lists the displays system_profiler reports.
+func monitors() []string {
+	var report struct {
+		Displays []struct {
+			Screens []struct {
+				Name string `json:"_name"`
+			} `json:"spdisplays_ndrvs"`
+		} `json:"SPDisplaysDataType"`
+	}
+	if err := json.Unmarshal([]byte(output("system_profiler", "SPDisplaysDataType", "-json")), &report); err != nil {
+		return nil
+	}
+	var names []string
+	for _, gpu := range report.Displays {
+		for _, s := range gpu.Screens {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
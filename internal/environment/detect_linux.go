@@ -0,0 +1,51 @@
+package environment
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// wifiSSID asks iwgetid, or NetworkManager if that's missing, for the
+// network.
+func wifiSSID() string {
+	if ssid := strings.TrimSpace(output("iwgetid", "-r")); ssid != "" {
+		return ssid
+	}
+	for _, line := range strings.Split(output("nmcli", "-t", "-f", "active,ssid", "dev", "wifi"), "\n") {
+		if ssid, ok := strings.CutPrefix(line, "yes:"); ok {
+			return ssid
+		}
+	}
+	return ""
+}
+
+// monitors reads the names from the EDID of each connected display.
+func monitors() []string {
+	paths, _ := filepath.Glob("/sys/class/drm/card*-*/edid")
+	var names []string
+	for _, p := range paths {
+		edid, err := os.ReadFile(p)
+		if err != nil || len(edid) < 128 {
+			continue // not connected
+		}
+		if name := edidName(edid); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// edidName returns the monitor name descriptor of an EDID block, or "".
+func edidName(edid []byte) string {
+	// Four 18-byte descriptors; a name has tag 0xfc after three zero bytes
+	for off := 54; off+18 <= 126; off += 18 {
+		d := edid[off : off+18]
+		if d[0] == 0 && d[1] == 0 && d[2] == 0 && d[3] == 0xfc {
+			name, _, _ := bytes.Cut(d[5:], []byte("\n"))
+			return strings.TrimSpace(string(name))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package environment
+
+import (
+	"regexp"
+	"strings"
+)
+
+var ssidRE = regexp.MustCompile(`(?m)^\s+SSID\s+: (.+)$`)
+
+// wifiSSID asks netsh for the connected network.
+func wifiSSID() string {
+	m := ssidRE.FindStringSubmatch(output("netsh", "wlan", "show", "interfaces"))
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
+
+// monitorsScript prints each monitor's name from WMI, one per line.
+const monitorsScript = `Get-CimInstance -Namespace root\wmi -ClassName WmiMonitorID | ForEach-Object { -join [char[]]($_.UserFriendlyName -ne 0) }`
+
+// monitors asks WMI for the monitors' names.
+func monitors() []string {
+	var names []string
+	for _, line := range strings.Split(output("powershell", "-NoProfile", "-Command", monitorsScript), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names
+}
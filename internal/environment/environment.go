@@ -0,0 +1,38 @@
+// Package environment reports where the computer is and what it's doing:
+// the Wi-Fi network it's on and the monitors attached, so config profiles
+// can be chosen by place, and the app in front, so the deck can follow it.
+// Each is looked up by asking the OS, which can take a moment.
+package environment
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// lookupTimeout bounds each command run to find the network or monitors.
+const lookupTimeout = 5 * time.Second
+
+// WiFiSSID returns the name of the Wi-Fi network the computer is on, or ""
+// if it's on none or the OS won't say.
+func WiFiSSID() string {
+	return wifiSSID()
+}
+
+// Monitors returns the model names of the attached monitors, such as
+// "DELL U2720Q", including any built-in display the OS names. It returns
+// nil if they can't be listed.
+func Monitors() []string {
+	return monitors()
+}
+
+// output runs a command and returns its output, or "" if it fails.
+func output(name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
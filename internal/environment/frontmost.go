@@ -0,0 +1,22 @@
+package environment
+
+import "strings"
+
+// App identifies an application.
+type App struct {
+	Name     string // display name, e.g. "OBS"
+	BundleID string // e.g. "com.obsproject.obs-studio"; "" where the OS has none
+}
+
+// FrontmostApp returns the application in front, the one receiving
+// keystrokes. It returns the zero App if there's none or the OS won't say,
+// which is always the case outside macOS.
+func FrontmostApp() App {
+	return frontmostApp()
+}
+
+// Is reports whether the app is the one named, by display name or bundle
+// ID, ignoring case.
+func (a App) Is(name string) bool {
+	return name != "" && (strings.EqualFold(a.Name, name) || strings.EqualFold(a.BundleID, name))
+}
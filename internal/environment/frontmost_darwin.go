@@ -0,0 +1,27 @@
+package environment
+
+import (
+	"regexp"
+	"strings"
+)
+
+// appInfoRE matches the "key"="value" lines lsappinfo prints.
+var appInfoRE = regexp.MustCompile(`(?m)^"(\w+)"="(.*)"$`)
+
+// frontmostApp asks lsappinfo, which answers quickly enough to poll.
+func frontmostApp() App {
+	asn := strings.TrimSpace(output("lsappinfo", "front"))
+	if asn == "" || asn == "[ NULL ]" {
+		return App{}
+	}
+	var app App
+	for _, m := range appInfoRE.FindAllStringSubmatch(output("lsappinfo", "info", "-only", "name", "-only", "bundleid", asn), -1) {
+		switch m[1] {
+		case "LSDisplayName":
+			app.Name = m[2]
+		case "CFBundleIdentifier":
+			app.BundleID = m[2]
+		}
+	}
+	return app
+}
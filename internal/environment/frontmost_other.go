@@ -0,0 +1,7 @@
+//go:build !darwin
+
+package environment
+
+func frontmostApp() App {
+	return App{}
+}
@@ -0,0 +1,101 @@
+// Package events publishes deck input and module state changes to external
+// consumers over a local WebSocket.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	TypeKey         = "key"          // key pressed or released
+	TypeDialRotate  = "dial_rotate"  // dial turned
+	TypeDialPress   = "dial_press"   // dial pressed or released
+	TypeStripTouch  = "strip_touch"  // touch strip tapped
+	TypeStripSwipe  = "strip_swipe"  // touch strip swiped
+	TypeModuleState = "module_state" // module became ready, failed, or degraded
+	TypeOverlay     = "overlay"      // a module's overlay opened or closed
+	TypePage        = "page"         // the deck switched to another page of keys
+	TypePrivacy     = "privacy"      // privacy mode turned on or off
+)
+
+// Event is one published event. Fields that don't apply to Type are omitted
+// from the JSON.
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	Key   int `json:"key,omitempty"`
+	Dial  int `json:"dial,omitempty"`
+	Delta int `json:"delta,omitempty"`
+
+	// Pressed is set for key and dial press events; false means released.
+	Pressed    *bool `json:"pressed,omitempty"`
+	DurationMS int64 `json:"duration_ms,omitempty"` // hold time, on release
+
+	Touch string `json:"touch,omitempty"` // "short" or "long"
+	X     int    `json:"x,omitempty"`
+	Y     int    `json:"y,omitempty"`
+	ToX   int    `json:"to_x,omitempty"`
+	ToY   int    `json:"to_y,omitempty"`
+
+	// Module is the module that owns the input (empty if none) or whose state changed.
+	Module string `json:"module,omitempty"`
+	State  string `json:"state,omitempty"`  // module_state: ready, failed, degraded; overlay: open, closed; privacy: on, off
+	Reason string `json:"reason,omitempty"` // why a module failed or degraded
+
+	Page  int `json:"page,omitempty"`  // page now showing; 0 (omitted) is the root page
+	Layer int `json:"layer,omitempty"` // key and dial events: 1 while the modifier key is held
+}
+
+// subscriberBuffer is how many events a slow subscriber may fall behind
+// before events are dropped for it.
+const subscriberBuffer = 64
+
+var (
+	mu          sync.Mutex
+	subscribers = make(map[chan Event]struct{})
+)
+
+// Publish sends e to every subscriber without blocking; subscribers that
+// are full miss the event. Time is filled in if unset.
+func Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for ch := range subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of published events and a function that
+// unsubscribes and closes it.
+func Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	mu.Lock()
+	subscribers[ch] = struct{}{}
+	mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			mu.Lock()
+			delete(subscribers, ch)
+			mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Bool returns a pointer to b, for Event.Pressed.
+func Bool(b bool) *bool {
+	return &b
+}
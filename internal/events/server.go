@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/phinze/belowdeck/internal/logging"
+)
+
+// writeTimeout bounds a single WebSocket write to a client.
+const writeTimeout = 5 * time.Second
+
+// Serve streams events as JSON text messages to WebSocket clients connected
+// to /events on addr until ctx is canceled. Clients only receive; anything
+// they send is ignored. handlers are served on addr too, by path, for
+// controlling the deck.
+func Serve(ctx context.Context, addr string, handlers map[string]http.Handler) error {
+	logger := logging.For("events")
+
+	mux := http.NewServeMux()
+	for path, h := range handlers {
+		mux.Handle(path, h)
+	}
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			logger.Warn("WebSocket upgrade failed", "remote", r.RemoteAddr, "err", err)
+			return
+		}
+		defer conn.CloseNow()
+
+		logger.Debug("Client connected", "remote", r.RemoteAddr)
+		err = stream(conn.CloseRead(ctx), conn)
+		logger.Debug("Client disconnected", "remote", r.RemoteAddr, "err", err)
+	})
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Serving events", "addr", addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// stream writes events to conn until ctx is done, which CloseRead arranges
+// when the client goes away.
+func stream(ctx context.Context, conn *websocket.Conn) error {
+	ch, unsubscribe := Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close(websocket.StatusGoingAway, "")
+			return ctx.Err()
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			writeCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+			err = conn.Write(writeCtx, websocket.MessageText, data)
+			cancel()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package httpx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// Cache limits: bodies bigger than maxCachedBody aren't kept, and once
+// maxCacheEntries are, an arbitrary one makes room for the next.
+const (
+	maxCacheEntries = 128
+	maxCachedBody   = 1 << 20
+)
+
+// cache keeps GET responses that can be revalidated, keyed by URL and the
+// headers that pick what the response holds.
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is a cached 200 response and its validators.
+type cacheEntry struct {
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+func newCache() *cache {
+	return &cache{entries: make(map[string]*cacheEntry)}
+}
+
+// lookup returns req's cache key and what's cached under it. The key is ""
+// for a request that isn't cached: anything but a GET, and one that makes
+// its own conditions.
+func (c *cache) lookup(req *http.Request) (string, *cacheEntry) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return "", nil
+	}
+	key := fmt.Sprintf("%s\x00%s\x00%s", req.URL, req.Header.Get("Authorization"), req.Header.Get("Accept"))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return key, c.entries[key]
+}
+
+// store caches resp under key if it's a 200 with a validator and a body
+// small enough, returning a response to use in its place. Anything else
+// drops what was cached.
+func (c *cache) store(key string, resp *http.Response) *http.Response {
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") || resp.ContentLength > maxCachedBody {
+		c.forget(key)
+		return resp
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil || len(body) > maxCachedBody {
+		// Hand back what was read followed by the rest, or the error
+		c.forget(key)
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), errReader{err}, resp.Body), resp.Body}
+		return resp
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = &cacheEntry{etag: etag, lastModified: lastModified, header: resp.Header.Clone(), body: body}
+	return resp
+}
+
+// unmergedHeaders describe a 304's own message rather than the cached
+// body, so they aren't copied onto the stored response.
+var unmergedHeaders = []string{"Content-Length", "Content-Encoding", "Content-Range", "Transfer-Encoding", "Connection"}
+
+// refresh lays the header fields of a 304 for key over the stored response
+// e, as RFC 9111 section 4.3.4 has caches do, so a changed rate limit or
+// validator isn't served stale. It returns the updated entry; e itself is
+// left alone, as responses may still be reading it.
+func (c *cache) refresh(key string, e *cacheEntry, header http.Header) *cacheEntry {
+	merged := e.header.Clone()
+	for k, v := range header {
+		if !slices.Contains(unmergedHeaders, k) {
+			merged[k] = slices.Clone(v)
+		}
+	}
+	updated := &cacheEntry{etag: e.etag, lastModified: e.lastModified, header: merged, body: e.body}
+	if etag := header.Get("ETag"); etag != "" {
+		updated.etag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		updated.lastModified = lastModified
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == e {
+		c.entries[key] = updated
+	}
+	return updated
+}
+
+// forget drops what's cached under key.
+func (c *cache) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// revalidate returns a copy of req asking for the resource only if it has
+// changed since e was cached.
+func (e *cacheEntry) revalidate(req *http.Request) *http.Request {
+	req = req.Clone(req.Context())
+	if e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		req.Header.Set("If-Modified-Since", e.lastModified)
+	}
+	return req
+}
+
+// response returns the cached response as if req had just fetched it.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// readCloser reads from r and closes c.
+type readCloser struct {
+	io.Reader
+	c io.Closer
+}
+
+func (r readCloser) Close() error { return r.c.Close() }
+
+// errReader returns err, or io.EOF if it's nil.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
@@ -0,0 +1,66 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModifiedUpdatesHeaders(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("X-RateLimit-Remaining", "100")
+			w.Header().Set("Cache-Control", "max-age=60")
+			io.WriteString(w, "body")
+		default:
+			if got := r.Header.Get("If-None-Match"); got != `"v1"` {
+				t.Errorf("request %d: If-None-Match = %q, want %q", requests, got, `"v1"`)
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("X-RateLimit-Remaining", "99")
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer srv.Close()
+
+	client := New("test", 5*time.Second)
+	get := func() (*http.Response, string) {
+		t.Helper()
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(body)
+	}
+
+	get()
+	for i := 0; i < 2; i++ {
+		resp, body := get()
+		if resp.StatusCode != http.StatusOK || body != "body" {
+			t.Fatalf("revalidated response = %d %q, want 200 %q", resp.StatusCode, body, "body")
+		}
+		if got := resp.Header.Get("X-RateLimit-Remaining"); got != "99" {
+			t.Errorf("X-RateLimit-Remaining = %q, want the 304's %q", got, "99")
+		}
+		if got := resp.Header.Get("Cache-Control"); got != "max-age=60" {
+			t.Errorf("Cache-Control = %q, want the stored %q", got, "max-age=60")
+		}
+		if got := resp.Header.Get("Content-Length"); got != "4" {
+			t.Errorf("Content-Length = %q, want the stored %q", got, "4")
+		}
+	}
+	if requests != 3 {
+		t.Errorf("server saw %d requests, want 3", requests)
+	}
+}
@@ -0,0 +1,164 @@
+// Package httpx provides the HTTP client network modules share. Its
+// requests are logged under the module's name, idempotent ones are retried
+// with backoff when the server is failing or rate limiting, and GET
+// responses carrying an ETag or Last-Modified are cached and revalidated,
+// so an unchanged resource costs a 304 instead of a full body.
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/logging"
+)
+
+// Retry settings: waits double from retryBackoff, and a Retry-After from
+// the server is honored up to maxRetryWait.
+const (
+	maxAttempts  = 3
+	retryBackoff = 500 * time.Millisecond
+	maxRetryWait = 10 * time.Second
+)
+
+// New returns a client for the named module whose requests, retries and
+// all, give up after timeout.
+func New(name string, timeout time.Duration) *http.Client {
+	return NewWithTransport(name, timeout, http.DefaultTransport)
+}
+
+// NewWithTransport is New sending requests through base, e.g. one with its
+// own TLS settings.
+func NewWithTransport(name string, timeout time.Duration, base http.RoundTripper) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &transport{
+			name:  name,
+			base:  base,
+			cache: newCache(),
+		},
+	}
+}
+
+// transport adds logging, retries, and caching to base.
+type transport struct {
+	name  string
+	base  http.RoundTripper
+	cache *cache
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Loggers are looked up per request, since modules' clients are made
+	// before logging is set up
+	logger := logging.For(t.name)
+	start := time.Now()
+	url := logURL(req)
+
+	key, entry := t.cache.lookup(req)
+	if entry != nil {
+		req = entry.revalidate(req)
+	}
+
+	resp, err := t.send(req)
+	if err != nil {
+		logger.Debug("HTTP request failed", "method", req.Method, "url", url, "elapsed", time.Since(start), "err", err)
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		logger.Debug("HTTP request", "method", req.Method, "url", url, "status", resp.StatusCode, "cached", true, "elapsed", time.Since(start))
+		return t.cache.refresh(key, entry, resp.Header).response(req), nil
+	}
+	if key != "" {
+		resp = t.cache.store(key, resp)
+	}
+	logger.Debug("HTTP request", "method", req.Method, "url", url, "status", resp.StatusCode, "elapsed", time.Since(start))
+	return resp, nil
+}
+
+// send makes the request, retrying an idempotent one that fails to get a
+// response or gets a 429 or 5xx, as long as its body can be sent again.
+func (t *transport) send(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt == maxAttempts || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := retryBackoff << (attempt - 1)
+		if resp != nil {
+			if after := retryAfter(resp); after > wait {
+				wait = min(after, maxRetryWait)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		logging.For(t.name).Debug("Retrying HTTP request", "method", req.Method, "url", logURL(req), "attempt", attempt, "wait", wait, "status", status(resp), "err", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryable reports whether req is worth sending again after getting resp
+// or err.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+	default:
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter returns how long resp's Retry-After header, in seconds or as
+// a date, asks to wait, or 0.
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// logURL returns req's URL without its query, which may hold an API key.
+func logURL(req *http.Request) string {
+	return req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+}
+
+// status returns resp's status code, or 0 if there's no response.
+func status(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
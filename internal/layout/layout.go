@@ -0,0 +1,358 @@
+// Package layout builds modules from the configured layout and registers them
+// with a coordinator, so the daemon and emulator share one module table.
+package layout
+
+import (
+	"errors"
+	"image"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/modules/audio"
+	"github.com/phinze/belowdeck/internal/modules/ci"
+	"github.com/phinze/belowdeck/internal/modules/clock"
+	"github.com/phinze/belowdeck/internal/modules/countdown"
+	"github.com/phinze/belowdeck/internal/modules/focus"
+	"github.com/phinze/belowdeck/internal/modules/folder"
+	"github.com/phinze/belowdeck/internal/modules/github"
+	"github.com/phinze/belowdeck/internal/modules/homeassistant"
+	"github.com/phinze/belowdeck/internal/modules/hue"
+	"github.com/phinze/belowdeck/internal/modules/keylight"
+	"github.com/phinze/belowdeck/internal/modules/kiosk"
+	"github.com/phinze/belowdeck/internal/modules/launcher"
+	"github.com/phinze/belowdeck/internal/modules/mail"
+	"github.com/phinze/belowdeck/internal/modules/mirror"
+	"github.com/phinze/belowdeck/internal/modules/mqtt"
+	"github.com/phinze/belowdeck/internal/modules/network"
+	"github.com/phinze/belowdeck/internal/modules/nowplaying"
+	"github.com/phinze/belowdeck/internal/modules/prompter"
+	"github.com/phinze/belowdeck/internal/modules/script"
+	"github.com/phinze/belowdeck/internal/modules/sysstats"
+	"github.com/phinze/belowdeck/internal/modules/totp"
+	"github.com/phinze/belowdeck/internal/modules/tracker"
+	"github.com/phinze/belowdeck/internal/modules/weather"
+	"github.com/phinze/belowdeck/internal/modules/welcome"
+	"github.com/phinze/belowdeck/internal/modules/yabai"
+)
+
+// Factory constructs a module for the given app config.
+type Factory func(cfg *config.Config) module.Module
+
+// factories maps layout module IDs to their constructors.
+var factories = map[string]Factory{
+	"audio": func(cfg *config.Config) module.Module {
+		return audio.New(cfg)
+	},
+	"nowplaying": func(cfg *config.Config) module.Module {
+		return nowplaying.New(cfg)
+	},
+	"weather": func(cfg *config.Config) module.Module {
+		return weather.New(cfg)
+	},
+	"homeassistant": func(cfg *config.Config) module.Module {
+		return homeassistant.New(cfg)
+	},
+	"hue": func(cfg *config.Config) module.Module {
+		return hue.New(cfg)
+	},
+	"keylight": func(cfg *config.Config) module.Module {
+		return keylight.New(cfg)
+	},
+	"clock": func(cfg *config.Config) module.Module {
+		return clock.New(cfg)
+	},
+	"countdown": func(cfg *config.Config) module.Module {
+		return countdown.New(cfg)
+	},
+	"focus": func(cfg *config.Config) module.Module {
+		return focus.New(cfg)
+	},
+	"github": func(cfg *config.Config) module.Module {
+		return github.New(cfg)
+	},
+	"launcher": func(cfg *config.Config) module.Module {
+		return launcher.New(cfg)
+	},
+	"ci": func(cfg *config.Config) module.Module {
+		return ci.New(cfg)
+	},
+	"mail": func(cfg *config.Config) module.Module {
+		return mail.New(cfg)
+	},
+	"mqtt": func(cfg *config.Config) module.Module {
+		return mqtt.New(cfg)
+	},
+	"network": func(cfg *config.Config) module.Module {
+		return network.New(cfg)
+	},
+	"script": func(cfg *config.Config) module.Module {
+		return script.New(cfg)
+	},
+	"kiosk": func(cfg *config.Config) module.Module {
+		return kiosk.New(cfg)
+	},
+	"totp": func(cfg *config.Config) module.Module {
+		return totp.New(cfg)
+	},
+	"mirror": func(cfg *config.Config) module.Module {
+		return mirror.New(cfg)
+	},
+	"prompter": func(cfg *config.Config) module.Module {
+		return prompter.New(cfg)
+	},
+	"sysstats": func(cfg *config.Config) module.Module {
+		return sysstats.New()
+	},
+	"tracker": func(cfg *config.Config) module.Module {
+		return tracker.New(cfg)
+	},
+	"yabai": func(cfg *config.Config) module.Module {
+		return yabai.New()
+	},
+}
+
+// ModuleIDs returns the IDs of every module a layout can name, sorted.
+func ModuleIDs() []string {
+	return slices.Sorted(maps.Keys(factories))
+}
+
+// Known reports whether id names a module a layout can use.
+func Known(id string) bool {
+	_, ok := factories[id]
+	return ok
+}
+
+// Unsupported reports whether module id can't run on this OS, and why.
+func Unsupported(id string) (string, bool) {
+	reason, ok := unsupported[id]
+	return reason, ok
+}
+
+// Register constructs every module in the effective layout and registers it
+// with the coordinator, giving each folder a page of its own. Unknown and
+// disabled module IDs are logged and skipped. A module that can't be registered, such as one
+// claiming a key another module has, is left out; the rest are still
+// registered, and their errors are returned joined (see Errors).
+func Register(coord *coordinator.Coordinator, dev device.Device, cfg *config.Config) error {
+	l := cfg.EffectiveLayout()
+	coord.SetPressFeedback(coordinator.PressFeedback(l.Feedback))
+	if b := l.BrightnessDial; b != nil && b.Dial <= int(dev.GetDialCount()) {
+		coord.SetBrightnessDial(module.DialID(b.Dial), b.Shift, cfg.EffectiveBrightness(), func(perc int) {
+			cfg.Brightness = perc
+			if err := config.SaveBrightness(perc); err != nil {
+				slog.Warn("Failed to save brightness", "err", err)
+			}
+		})
+	}
+	if l.Modifier > 0 && l.Modifier <= int(dev.GetKeyCount()) {
+		coord.SetModifierKey(module.KeyID(l.Modifier))
+	}
+	if l.Privacy > 0 && l.Privacy <= int(dev.GetKeyCount()) {
+		coord.SetPrivacyKey(module.KeyID(l.Privacy))
+	}
+	coord.SetPageSwipe(l.Gestures.SwipePages)
+	if lock := l.Gestures.Lock; len(lock) == 2 && max(lock[0], lock[1]) <= int(dev.GetKeyCount()) {
+		coord.SetLockChord(module.KeyID(lock[0]), module.KeyID(lock[1]))
+	}
+	if len(l.Lock.Schedule) > 0 {
+		coord.SetLockSchedule(l.Lock.Locked)
+	}
+	var unlock []module.KeyID
+	for _, k := range l.Lock.Unlock {
+		if k > int(dev.GetKeyCount()) {
+			slog.Warn("Layout: device has no such unlock key, skipping unlock sequence", "key", k)
+			unlock = nil
+			break
+		}
+		unlock = append(unlock, module.KeyID(k))
+	}
+	coord.SetUnlockSequence(unlock)
+	if s := l.Screensaver; s.Minutes > 0 {
+		var keep []module.KeyID
+		for _, k := range s.Keys {
+			keep = append(keep, module.KeyID(k))
+		}
+		coord.SetScreensaver(time.Duration(s.Minutes)*time.Minute, byte(s.Strip), keep, cfg.EffectiveBrightness())
+	}
+	for i := 1; cfg != nil && i <= int(dev.GetDialCount()); i++ {
+		if t := cfg.Dials.ForDial(i); t != (config.DialTuning{}) {
+			coord.SetDialTuning(module.DialID(i), coordinator.DialTuning(t))
+		}
+	}
+	var errs []error
+	if !config.Exists() {
+		// Registered first so its overlay takes precedence
+		if err := coord.RegisterModule(welcome.New(cfg, moduleIDs(l)), module.Resources{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, registerModules(coord, dev, cfg, coordinator.RootPage, l.Modules)...)
+	errs = append(errs, registerFolders(coord, dev, cfg, coordinator.RootPage, l.Folders)...)
+	coord.RestorePage()
+	return errors.Join(errs...)
+}
+
+// moduleIDs returns the IDs of the modules in l, each once.
+func moduleIDs(l config.LayoutConfig) []string {
+	var ids []string
+	for _, ml := range l.AllModules() {
+		if !slices.Contains(ids, ml.ID) {
+			ids = append(ids, ml.ID)
+		}
+	}
+	return ids
+}
+
+// Errors splits an error returned by Register into one per module.
+func Errors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// registerModules registers a page's modules, returning the errors of those
+// it couldn't.
+func registerModules(coord *coordinator.Coordinator, dev device.Device, cfg *config.Config, page coordinator.PageID, modules []config.ModuleLayout) []error {
+	var errs []error
+	for _, ml := range modules {
+		factory, ok := factories[ml.ID]
+		if !ok {
+			slog.Warn("Layout: unknown module, skipping", "id", ml.ID)
+			continue
+		}
+		if reason, ok := Unsupported(ml.ID); ok {
+			slog.Warn("Layout: module not supported on this OS, skipping", "id", ml.ID, "os", runtime.GOOS, "reason", reason)
+			continue
+		}
+		if cfg.ModuleDisabled(ml.ID) {
+			slog.Info("Layout: module disabled, skipping", "id", ml.ID)
+			continue
+		}
+		m := factory(moduleConfig(cfg, ml))
+		res, err := bindSlots(m, ml, Resources(ml))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := coord.RegisterPageModule(page, m, fitDevice(dev, ml.ID, res)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// registerFolders registers a folder key on parent for each folder, and a
+// back key and the folder's modules on a new page. A folder whose key can't
+// be registered is skipped along with its page.
+func registerFolders(coord *coordinator.Coordinator, dev device.Device, cfg *config.Config, parent coordinator.PageID, folders []config.FolderLayout) []error {
+	var errs []error
+	for _, f := range folders {
+		page := coord.NewPage()
+
+		open := folder.New(f.Label, f.Icon, func() { coord.ShowPage(page) })
+		res := module.Resources{Keys: []module.KeyID{module.KeyID(f.Key)}}
+		if err := coord.RegisterPageModule(parent, open, fitDevice(dev, "folder", res)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if len(f.Apps) > 0 {
+			coord.SetAppPage(page, f.Apps)
+		}
+		if len(f.Schedule) > 0 {
+			coord.SetSchedulePage(page, f.Scheduled)
+		}
+
+		back := folder.NewBack(func() { coord.ShowPage(parent) })
+		res = module.Resources{Keys: []module.KeyID{module.KeyID(f.BackKey())}}
+		if err := coord.RegisterPageModule(page, back, fitDevice(dev, "folder", res)); err != nil {
+			errs = append(errs, err)
+		}
+
+		errs = append(errs, registerModules(coord, dev, cfg, page, f.Modules)...)
+		errs = append(errs, registerFolders(coord, dev, cfg, page, f.Folders)...)
+	}
+	return errs
+}
+
+// moduleConfig returns the config for one layout entry: cfg itself, or a
+// copy with the entry's overrides applied.
+func moduleConfig(cfg *config.Config, ml config.ModuleLayout) *config.Config {
+	if len(ml.Buttons) == 0 || cfg == nil {
+		return cfg
+	}
+	c := *cfg
+	c.Launcher.Buttons = ml.Buttons
+	return &c
+}
+
+// fitDevice drops keys, dials, and strip segments the device doesn't have,
+// so a layout written for one model still runs on a smaller one.
+func fitDevice(dev device.Device, id string, res module.Resources) module.Resources {
+	var keys []module.KeyID
+	for _, k := range res.Keys {
+		if int(k) > int(dev.GetKeyCount()) {
+			slog.Warn("Layout: device has no such key, skipping", "id", id, "key", k)
+			continue
+		}
+		keys = append(keys, k)
+	}
+	res.Keys = keys
+	maps.DeleteFunc(res.Slots, func(name string, k module.KeyID) bool {
+		return !slices.Contains(keys, k)
+	})
+
+	var dials []module.DialID
+	for _, d := range res.Dials {
+		if int(d) > int(dev.GetDialCount()) {
+			slog.Warn("Layout: device has no such dial, skipping", "id", id, "dial", d)
+			continue
+		}
+		dials = append(dials, d)
+	}
+	res.Dials = dials
+
+	if !res.StripRect.Empty() && !dev.GetTouchStripSupported() {
+		slog.Warn("Layout: device has no touch strip, skipping strip segment", "id", id)
+		res.StripRect = image.Rectangle{}
+	}
+	return res
+}
+
+// Resources converts a module's layout entry into coordinator resources.
+func Resources(ml config.ModuleLayout) module.Resources {
+	var res module.Resources
+	for _, k := range ml.AllKeys() {
+		res.Keys = append(res.Keys, module.KeyID(k))
+	}
+	for name, k := range ml.Slots {
+		if res.Slots == nil {
+			res.Slots = make(map[string]module.KeyID)
+		}
+		res.Slots[name] = module.KeyID(k)
+	}
+	for _, d := range ml.Dials {
+		res.Dials = append(res.Dials, module.DialID(d))
+	}
+	if ml.Strip != nil {
+		res.StripRect = image.Rect(ml.Strip.X, 0, ml.Strip.X+ml.Strip.Width, 100)
+	}
+	switch ml.Redact {
+	case "blur":
+		res.Redact = module.RedactBlur
+	case "generic":
+		res.Redact = module.RedactGeneric
+	}
+	return res
+}
@@ -0,0 +1,4 @@
+package layout
+
+// unsupported maps modules that can't run on this OS to the reason why.
+var unsupported = map[string]string{}
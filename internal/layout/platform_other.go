@@ -0,0 +1,11 @@
+//go:build !darwin
+
+package layout
+
+// unsupported maps modules that can't run on this OS to the reason why.
+var unsupported = map[string]string{
+	"audio":      "needs CoreAudio",
+	"focus":      "needs macOS Focus and Shortcuts",
+	"nowplaying": "needs MediaRemote",
+	"yabai":      "yabai is a macOS window manager",
+}
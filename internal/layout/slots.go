@@ -0,0 +1,63 @@
+package layout
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// bindSlots binds the key slots m declares. Slots the layout names keep
+// their keys; the rest take the module's unbound keys in order, so a layout
+// that lists keys positionally still works. It fails if the layout names a
+// slot m doesn't have or leaves a required slot unbound.
+func bindSlots(m module.Module, ml config.ModuleLayout, res module.Resources) (module.Resources, error) {
+	var slots []module.Slot
+	if sp, ok := m.(module.SlotProvider); ok {
+		slots = sp.Slots()
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(ml.Slots)) {
+		if !slices.ContainsFunc(slots, func(s module.Slot) bool { return s.Name == name }) {
+			return res, fmt.Errorf("layout: module %s: no slot named %q%s", ml.ID, name, slotNames(slots))
+		}
+	}
+	if len(slots) == 0 {
+		return res, nil
+	}
+
+	bound := maps.Clone(res.Slots)
+	if bound == nil {
+		bound = make(map[string]module.KeyID)
+	}
+	free := res.FreeKeys()
+	for _, s := range slots {
+		if _, ok := bound[s.Name]; ok {
+			continue
+		}
+		if len(free) > 0 {
+			bound[s.Name] = free[0]
+			free = free[1:]
+			continue
+		}
+		if s.Required {
+			return res, fmt.Errorf("layout: module %s: required slot %q not bound to a key", ml.ID, s.Name)
+		}
+	}
+	res.Slots = bound
+	return res, nil
+}
+
+// slotNames lists slots for an error message.
+func slotNames(slots []module.Slot) string {
+	if len(slots) == 0 {
+		return " (module has no slots)"
+	}
+	names := make([]string, len(slots))
+	for i, s := range slots {
+		names[i] = s.Name
+	}
+	return fmt.Sprintf(" (want one of %v)", names)
+}
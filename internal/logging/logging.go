@@ -0,0 +1,145 @@
+// Package logging configures structured logging for belowdeck: levels
+// (globally and per module), text or JSON output, and an optional rotating
+// log file for the launchd-run daemon.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// Defaults for the rotating log file.
+const (
+	defaultMaxSizeMB  = 10
+	defaultMaxBackups = 3
+)
+
+var (
+	mu           sync.RWMutex
+	root         slog.Handler = slog.Default().Handler()
+	defaultLevel              = slog.LevelInfo
+	moduleLevels              = map[string]slog.Level{}
+)
+
+// Setup installs the configured handler as the slog default (which also
+// redirects the standard log package). The returned Closer closes the log
+// file, if any. Call it before creating module loggers with For.
+func Setup(cfg config.LoggingConfig) (io.Closer, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]slog.Level, len(cfg.Modules))
+	for name, l := range cfg.Modules {
+		if levels[name], err = ParseLevel(l); err != nil {
+			return nil, fmt.Errorf("module %s: %w", name, err)
+		}
+	}
+
+	var w io.Writer = os.Stderr
+	var closer io.Closer = nopCloser{}
+	if cfg.File != "" {
+		maxSize := cfg.MaxSizeMB
+		if maxSize <= 0 {
+			maxSize = defaultMaxSizeMB
+		}
+		backups := cfg.MaxBackups
+		if backups <= 0 {
+			backups = defaultMaxBackups
+		}
+		f, err := openRotatingFile(cfg.File, int64(maxSize)<<20, backups)
+		if err != nil {
+			return nil, fmt.Errorf("opening log file: %w", err)
+		}
+		w, closer = f, f
+	}
+
+	// Lines are also kept in memory for bug reports (see Recent)
+	w = io.MultiWriter(w, recent)
+
+	// Filtering happens in levelHandler, so the inner handler passes everything
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var inner slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+		inner = slog.NewTextHandler(w, opts)
+	case "json":
+		inner = slog.NewJSONHandler(w, opts)
+	default:
+		closer.Close()
+		return nil, fmt.Errorf("unknown log format %q (want text or json)", cfg.Format)
+	}
+
+	mu.Lock()
+	root, defaultLevel, moduleLevels = inner, level, levels
+	mu.Unlock()
+
+	slog.SetDefault(slog.New(&levelHandler{level: level, inner: inner}))
+	return closer, nil
+}
+
+// FilePath returns the log file cfg writes to, with a leading "~/"
+// expanded, or "" if it logs to stderr.
+func FilePath(cfg config.LoggingConfig) string {
+	return config.ExpandHome(cfg.File)
+}
+
+// For returns a logger for the named module or component. Records carry a
+// "module" attribute and are filtered at the module's configured level.
+func For(name string) *slog.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	level, ok := moduleLevels[name]
+	if !ok {
+		level = defaultLevel
+	}
+	inner := root.WithAttrs([]slog.Attr{slog.String("module", name)})
+	return slog.New(&levelHandler{level: level, inner: inner})
+}
+
+// ParseLevel parses a level name (debug, info, warn, error). Empty means info.
+func ParseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (want debug, info, warn, or error)", s)
+	}
+	return level, nil
+}
+
+// levelHandler drops records below its level before they reach inner.
+type levelHandler struct {
+	level slog.Level
+	inner slog.Handler
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, inner: h.inner.WithGroup(name)}
+}
+
+// nopCloser is returned by Setup when logging to stderr.
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
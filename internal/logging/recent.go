@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+)
+
+// recentLines is how many log lines Recent keeps.
+const recentLines = 1000
+
+// recent holds the latest log lines written through Setup's handler, for
+// bug reports.
+var recent = &recentLog{}
+
+// recentLog is a ring of the last recentLines lines written to it.
+type recentLog struct {
+	mu    sync.Mutex
+	lines [recentLines][]byte
+	next  int
+	full  bool
+}
+
+// Write records p, which slog's handlers write a record at a time.
+func (r *recentLog) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		r.lines[r.next] = bytes.Clone(line)
+		r.next = (r.next + 1) % recentLines
+		r.full = r.full || r.next == 0
+	}
+	return len(p), nil
+}
+
+// Recent returns the last lines logged since Setup, oldest first.
+func Recent() []byte {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	var buf bytes.Buffer
+	if recent.full {
+		for _, line := range recent.lines[recent.next:] {
+			buf.Write(line)
+		}
+	}
+	for _, line := range recent.lines[:recent.next] {
+		buf.Write(line)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,125 @@
+// Package metrics defines the daemon's Prometheus metrics and serves them on
+// an optional localhost endpoint.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	// RenderDuration is the time taken by one pass of the render loop.
+	RenderDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "belowdeck_render_duration_seconds",
+		Help:    "Duration of one render loop pass (keys and strip).",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	})
+
+	// USBWriteErrors counts failed image writes to the device.
+	USBWriteErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "belowdeck_usb_write_errors_total",
+		Help: "Key and strip image writes that failed.",
+	})
+
+	// RenderStalls counts render passes the watchdog gave up on.
+	RenderStalls = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "belowdeck_render_stalls_total",
+		Help: "Render passes that stalled, resetting the device.",
+	})
+
+	// CoalescedWrites counts device writes dropped for a newer one.
+	CoalescedWrites = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "belowdeck_coalesced_writes_total",
+		Help: "Key and strip image writes replaced by a newer image before being sent.",
+	})
+
+	// SkippedWrites counts images not written because the key or strip
+	// already showed them.
+	SkippedWrites = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "belowdeck_unchanged_writes_skipped_total",
+		Help: "Key and strip images not written because the deck already showed them.",
+	})
+
+	// FetchDuration is the latency of module data fetches (API calls).
+	FetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "belowdeck_module_fetch_duration_seconds",
+		Help:    "Latency of module data fetches.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"module"})
+
+	// FetchFailures counts module data fetches that returned an error.
+	FetchFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "belowdeck_module_fetch_failures_total",
+		Help: "Module data fetches that failed.",
+	}, []string{"module"})
+
+	// OverlayActivations counts overlays taking over the deck.
+	OverlayActivations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "belowdeck_overlay_activations_total",
+		Help: "Times a module's overlay took over the deck.",
+	}, []string{"module"})
+
+	// KeyPresses counts key presses by key number.
+	KeyPresses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "belowdeck_key_presses_total",
+		Help: "Key presses by key.",
+	}, []string{"key"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RenderDuration,
+		USBWriteErrors,
+		RenderStalls,
+		CoalescedWrites,
+		SkippedWrites,
+		FetchDuration,
+		FetchFailures,
+		OverlayActivations,
+		KeyPresses,
+	)
+}
+
+// ObserveFetch records a module fetch that started at start and ended with err.
+func ObserveFetch(module string, start time.Time, err error) {
+	FetchDuration.WithLabelValues(module).Observe(time.Since(start).Seconds())
+	if err != nil {
+		FetchFailures.WithLabelValues(module).Inc()
+	}
+}
+
+// Serve exposes /metrics on addr until ctx is cancelled.
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	logging.For("metrics").Info("Serving metrics", "addr", addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,10 @@ package module
 import (
 	"context"
 	"image"
+	"log/slog"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/logging"
 )
 
 // BaseModule provides default no-op implementations of the Module interface.
@@ -12,11 +16,13 @@ type BaseModule struct {
 	resources Resources
 	ctx       context.Context
 	cancel    context.CancelFunc
+	notifier  Notifier
+	logger    *slog.Logger
 }
 
 // NewBaseModule creates a BaseModule with the given ID.
 func NewBaseModule(id string) BaseModule {
-	return BaseModule{id: id}
+	return BaseModule{id: id, logger: logging.For(id)}
 }
 
 // ID returns the module's identifier.
@@ -24,6 +30,14 @@ func (b *BaseModule) ID() string {
 	return b.id
 }
 
+// Log returns the module's logger, which tags records with the module ID.
+func (b *BaseModule) Log() *slog.Logger {
+	if b.logger == nil {
+		return slog.Default()
+	}
+	return b.logger
+}
+
 // Init stores the context and resources for the module.
 // Override this to perform module-specific initialization, but call the base
 // implementation to ensure resources and context are properly stored.
@@ -73,7 +87,69 @@ func (b *BaseModule) Resources() Resources {
 	return b.resources
 }
 
+// Redaction returns how sensitive text should be hidden right now:
+// Resources.Redact while privacy mode is on, RedactNone otherwise.
+func (b *BaseModule) Redaction() Redaction {
+	if !Private() {
+		return RedactNone
+	}
+	return b.resources.Redact
+}
+
 // Context returns the module's context.
 func (b *BaseModule) Context() context.Context {
 	return b.ctx
 }
+
+// SetNotifier stores the notifier used by Notify. Called by the coordinator
+// at registration.
+func (b *BaseModule) SetNotifier(n Notifier) {
+	b.notifier = n
+}
+
+// TaskTimeout bounds work started with RunOnKey.
+const TaskTimeout = 30 * time.Second
+
+// RunOnKey runs fn in the background so the event goroutine isn't held up,
+// showing a spinner on key while it runs and then whether it succeeded.
+// Failures are logged.
+func (b *BaseModule) RunOnKey(key KeyID, fn func(ctx context.Context) error) {
+	b.keyTask(key, TaskRunning)
+	go func() {
+		parent := b.ctx
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, TaskTimeout)
+		defer cancel()
+
+		if err := fn(ctx); err != nil {
+			b.Log().Warn("Key action failed", "key", key, "err", err)
+			b.keyTask(key, TaskFailed)
+			return
+		}
+		b.keyTask(key, TaskSucceeded)
+	}()
+}
+
+func (b *BaseModule) keyTask(key KeyID, state TaskState) {
+	if b.notifier != nil {
+		b.notifier.KeyTask(key, state)
+	}
+}
+
+// RefreshStrip redraws the module's strip region right away, for strip
+// content that should follow a dial smoothly. It's a no-op if no notifier
+// is set.
+func (b *BaseModule) RefreshStrip() {
+	if b.notifier != nil && b.resources.HasStrip() {
+		b.notifier.RefreshStrip(b.resources.StripRect)
+	}
+}
+
+// Notify posts a transient notification. It's a no-op if no notifier is set.
+func (b *BaseModule) Notify(n Notification) {
+	if b.notifier != nil {
+		b.notifier.Notify(n)
+	}
+}
@@ -5,6 +5,17 @@ import (
 	"time"
 )
 
+// Layer is the modifier layer an event happened on, so a key or dial can
+// offer an alternate action while the layout's modifier key is held.
+type Layer uint8
+
+const (
+	// LayerBase is the normal layer.
+	LayerBase Layer = iota
+	// LayerShift is active while the modifier key is held.
+	LayerShift
+)
+
 // DialEventType indicates the type of dial interaction.
 type DialEventType uint8
 
@@ -29,6 +40,9 @@ type DialEvent struct {
 	// Duration is how long the dial was held before release.
 	// Only meaningful for DialRelease events.
 	Duration time.Duration
+
+	// Layer is the modifier layer when the event happened.
+	Layer Layer
 }
 
 // KeyEvent represents an interaction with a physical key.
@@ -39,6 +53,10 @@ type KeyEvent struct {
 	// Duration is how long the key was held before release.
 	// Only meaningful when Pressed is false.
 	Duration time.Duration
+
+	// Layer is the modifier layer when the key was pressed; the release
+	// reports the same layer.
+	Layer Layer
 }
 
 // TouchStripEventType indicates the type of touch strip interaction.
@@ -51,6 +69,42 @@ const (
 	TouchLongTap
 	// TouchSwipe indicates a swipe gesture on the touch strip.
 	TouchSwipe
+	// TouchFlick indicates a swipe long and straight enough to be a flick,
+	// as for scrolling a page at a time. It follows the gesture's
+	// TouchSwipe.
+	TouchFlick
+	// TouchDrag is one point of a drag along the strip. A swipe is followed
+	// by a stream of them from DragStart to DragEnd, as for scrubbing or a
+	// slider.
+	TouchDrag
+	// TouchEdgeSwipe indicates a swipe in from either end of the strip. It
+	// follows the gesture's TouchSwipe.
+	TouchEdgeSwipe
+	// TouchDoubleTap indicates a second tap soon after and close to the
+	// first. It follows the second tap's TouchTap.
+	TouchDoubleTap
+)
+
+// DragPhase is where a TouchDrag event falls in its drag.
+type DragPhase uint8
+
+const (
+	// DragStart is the point where the drag began.
+	DragStart DragPhase = iota + 1
+	// DragMove is a point along the drag.
+	DragMove
+	// DragEnd is the point where the drag ended.
+	DragEnd
+)
+
+// Edge is the end of the strip a TouchEdgeSwipe came in from.
+type Edge uint8
+
+const (
+	// EdgeLeft is the left end of the strip.
+	EdgeLeft Edge = iota + 1
+	// EdgeRight is the right end of the strip.
+	EdgeRight
 )
 
 // TouchStripEvent represents an interaction with the touch strip.
@@ -58,15 +112,23 @@ type TouchStripEvent struct {
 	// Type indicates what kind of touch interaction occurred.
 	Type TouchStripEventType
 
-	// Point is the location of a tap or long tap.
+	// Point is the location of a tap or long tap, or a drag's point.
 	// For swipes, this is the same as SwipeStart.
 	Point image.Point
 
 	// SwipeStart is the starting point of a swipe gesture.
-	// Only meaningful for TouchSwipe events.
+	// Only meaningful for swipe, flick, and edge swipe events.
 	SwipeStart image.Point
 
 	// SwipeEnd is the ending point of a swipe gesture.
-	// Only meaningful for TouchSwipe events.
+	// Only meaningful for swipe, flick, and edge swipe events.
 	SwipeEnd image.Point
+
+	// Phase is where the point falls in its drag.
+	// Only meaningful for TouchDrag events.
+	Phase DragPhase
+
+	// Edge is the end of the strip the swipe came in from.
+	// Only meaningful for TouchEdgeSwipe events.
+	Edge Edge
 }
@@ -0,0 +1,64 @@
+package module
+
+import (
+	"sync"
+	"time"
+)
+
+// FreshnessReporter is implemented by modules showing data fetched from a
+// network service. The coordinator badges the keys and strip region of a
+// module whose data has gone stale, and shows the deck as offline when
+// several modules' fetches are failing at once.
+type FreshnessReporter interface {
+	// DataFreshness reports how current the module's data is. It's called
+	// from the render goroutine.
+	DataFreshness() DataFreshness
+}
+
+// DataFreshness is how current a module's fetched data is.
+type DataFreshness struct {
+	// Updated is when data was last fetched successfully, zero before the
+	// first time.
+	Updated time.Time
+
+	// StaleAfter is how old the data gets before it's shown as stale.
+	StaleAfter time.Duration
+
+	// Failing is whether the latest fetch failed.
+	Failing bool
+}
+
+// Stale reports whether the data is older than StaleAfter at now. Data not
+// yet fetched isn't stale, since the module shows it's still loading.
+func (f DataFreshness) Stale(now time.Time) bool {
+	return !f.Updated.IsZero() && f.StaleAfter > 0 && now.Sub(f.Updated) > f.StaleAfter
+}
+
+// Freshness keeps a module's DataFreshness. Embed it alongside BaseModule,
+// set StaleAfter in the constructor, and call Fetched after each fetch.
+type Freshness struct {
+	// StaleAfter is how old the data gets before it's shown as stale,
+	// typically a few poll intervals.
+	StaleAfter time.Duration
+
+	mu      sync.Mutex
+	updated time.Time
+	failing bool
+}
+
+// Fetched records a fetch that ended with err.
+func (f *Freshness) Fetched(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing = err != nil
+	if err == nil {
+		f.updated = time.Now()
+	}
+}
+
+// DataFreshness reports how current the data is.
+func (f *Freshness) DataFreshness() DataFreshness {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return DataFreshness{Updated: f.updated, StaleAfter: f.StaleAfter, Failing: f.failing}
+}
@@ -0,0 +1,76 @@
+package module
+
+import (
+	"image"
+	"image/color"
+	"time"
+)
+
+// DefaultNotifyDuration is how long a notification shows when Duration is zero.
+const DefaultNotifyDuration = 3 * time.Second
+
+// NotifyTarget selects where a notification is shown.
+type NotifyTarget uint8
+
+const (
+	// NotifyStrip shows the notification across the whole touch strip.
+	NotifyStrip NotifyTarget = iota
+	// NotifyKey shows the notification on a single key.
+	NotifyKey
+)
+
+// Notification is a transient message that temporarily replaces normal
+// rendering of the strip or a key, then disappears on its own.
+type Notification struct {
+	// Text is the message, e.g. "PR merged".
+	Text string
+
+	// Image is an optional icon drawn alongside the text.
+	Image image.Image
+
+	// Color is the accent color; zero means the default.
+	Color color.Color
+
+	// Target selects the strip or a key.
+	Target NotifyTarget
+
+	// Key is the key to show on. Only meaningful for NotifyKey.
+	Key KeyID
+
+	// Duration is how long to show it; zero means DefaultNotifyDuration.
+	Duration time.Duration
+
+	// Strip replaces the banner of a strip notification with a picture of
+	// the module's own, such as a slider showing a level being adjusted.
+	// It's drawn at the strip's origin; Text, Image, and Color are unused.
+	Strip image.Image
+}
+
+// TaskState is the progress of background work started from a key.
+type TaskState uint8
+
+const (
+	// TaskRunning shows a spinner on the key.
+	TaskRunning TaskState = iota + 1
+	// TaskSucceeded briefly shows a check mark.
+	TaskSucceeded
+	// TaskFailed briefly shows a cross.
+	TaskFailed
+)
+
+// Notifier shows transient notifications. The coordinator implements it.
+type Notifier interface {
+	Notify(n Notification)
+	// KeyTask draws a task's state over a key: a spinner while it runs,
+	// then a check mark or cross for a moment.
+	KeyTask(key KeyID, state TaskState)
+	// RefreshStrip redraws region of the strip right away, rather than
+	// on the next render pass.
+	RefreshStrip(region image.Rectangle)
+}
+
+// NotifierSetter is implemented by modules that want to post notifications.
+// BaseModule implements it, so embedding modules get Notify for free.
+type NotifierSetter interface {
+	SetNotifier(n Notifier)
+}
@@ -1,11 +1,17 @@
 package module
 
-import "image"
+import (
+	"image"
+	"time"
+)
 
 // OverlayProvider is an interface that modules can implement to provide
 // full-screen overlays that temporarily take over the entire display.
 type OverlayProvider interface {
 	// IsOverlayActive returns true if the module currently has an active overlay.
+	// It's called from both the input and render goroutines, so it should
+	// only read state; closing an expired overlay is up to the coordinator
+	// (see OverlayCloser).
 	IsOverlayActive() bool
 
 	// RenderOverlayKeys returns images for ALL keys when the overlay is active.
@@ -26,3 +32,19 @@ type OverlayProvider interface {
 	// This allows the overlay to respond to dial rotation and clicks.
 	HandleOverlayDial(id DialID, event DialEvent) error
 }
+
+// OverlayCloser is an OverlayProvider whose overlay closes by itself after
+// a while without input. The coordinator keeps the time: it calls
+// CloseOverlay once OverlayTimeout has passed since the overlay opened or
+// last handled input.
+type OverlayCloser interface {
+	OverlayProvider
+
+	// OverlayTimeout returns how long the overlay stays open without input.
+	// It's asked again after each input, so it can depend on what the
+	// overlay is showing.
+	OverlayTimeout() time.Duration
+
+	// CloseOverlay closes the overlay.
+	CloseOverlay()
+}
@@ -0,0 +1,30 @@
+package module
+
+import "sync/atomic"
+
+// Redaction is how a module hides sensitive text, such as pull request,
+// issue, and email titles, while privacy mode is on.
+type Redaction uint8
+
+const (
+	// RedactNone shows the text as usual.
+	RedactNone Redaction = iota
+	// RedactBlur blurs the text beyond reading.
+	RedactBlur
+	// RedactGeneric replaces the text with a generic stand-in, such as
+	// "Pull request".
+	RedactGeneric
+)
+
+var private atomic.Bool
+
+// SetPrivate turns privacy mode on or off for every module, as while
+// sharing the screen. Modules pick it up on their next render.
+func SetPrivate(on bool) {
+	private.Store(on)
+}
+
+// Private reports whether privacy mode is on.
+func Private() bool {
+	return private.Load()
+}
@@ -40,6 +40,67 @@ type Resources struct {
 
 	// Dials assigned to this module (may be empty).
 	Dials []DialID
+
+	// Slots maps the names of the module's key slots to the keys bound to
+	// them. Every bound key is also in Keys.
+	Slots map[string]KeyID
+
+	// Device describes the hardware the module renders for. The
+	// coordinator fills it in when the module is registered.
+	Device Capabilities
+
+	// Redact is how the module hides sensitive text while privacy mode is
+	// on (see SetPrivate).
+	Redact Redaction
+}
+
+// Capabilities describes what the device can display, so modules can size
+// their images without holding the device.
+type Capabilities struct {
+	// KeyCount is how many keys the device has.
+	KeyCount int
+
+	// KeyRect is the size of a key image.
+	KeyRect image.Rectangle
+
+	// StripRect is the size of the whole touch strip image. A zero rect
+	// means the device has no touch strip.
+	StripRect image.Rectangle
+
+	// ColorDepth is the bits per pixel of key and strip images.
+	ColorDepth int
+}
+
+// HasStrip returns true if the device has a touch strip.
+func (c Capabilities) HasStrip() bool {
+	return !c.StripRect.Empty()
+}
+
+// Slot returns the key bound to the named slot, if any.
+func (r Resources) Slot(name string) (KeyID, bool) {
+	k, ok := r.Slots[name]
+	return k, ok
+}
+
+// FreeKeys returns the module's keys not bound to a slot, in layout order.
+func (r Resources) FreeKeys() []KeyID {
+	var keys []KeyID
+	for _, k := range r.Keys {
+		if !r.slotted(k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// slotted reports whether key is bound to a slot.
+func (r Resources) slotted(key KeyID) bool {
+	for _, k := range r.Slots {
+		if k == key {
+			return true
+		}
+	}
+	return false
 }
 
 // HasKeys returns true if this module has any keys allocated.
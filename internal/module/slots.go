@@ -0,0 +1,17 @@
+package module
+
+// Slot is a named key role a module declares, such as GitHub's "inbox" key.
+// Layouts bind slots to physical keys by name instead of by position.
+type Slot struct {
+	Name string
+
+	// Required slots must be bound; the layout is rejected otherwise.
+	Required bool
+}
+
+// SlotProvider is implemented by modules whose keys have distinct roles.
+// The layout binds each declared slot before Init, and the module finds its
+// keys with Resources.Slot.
+type SlotProvider interface {
+	Slots() []Slot
+}
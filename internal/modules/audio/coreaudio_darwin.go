@@ -0,0 +1,237 @@
+package audio
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+)
+
+// CoreAudio type aliases.
+type (
+	audioObjectID uint32
+	osStatus      int32
+
+	cfIndex          int64
+	cfStringRef      uintptr
+	cfTypeRef        uintptr
+	cfStringEncoding uint32
+)
+
+// audioObjectPropertyAddress mirrors AudioObjectPropertyAddress.
+type audioObjectPropertyAddress struct {
+	selector uint32
+	scope    uint32
+	element  uint32
+}
+
+// fourCC packs a four-character code the way CoreAudio headers do.
+func fourCC(s string) uint32 {
+	return uint32(s[0])<<24 | uint32(s[1])<<16 | uint32(s[2])<<8 | uint32(s[3])
+}
+
+var (
+	kAudioObjectSystemObject audioObjectID = 1
+
+	kAudioHardwarePropertyDevices             = fourCC("dev#")
+	kAudioHardwarePropertyDefaultOutputDevice = fourCC("dOut")
+	kAudioObjectPropertyName                  = fourCC("lnam")
+	kAudioDevicePropertyStreams               = fourCC("stm#")
+	kAudioDevicePropertyMute                  = fourCC("mute")
+	kAudioDevicePropertyVirtualMainVolume     = fourCC("vmvc")
+
+	kAudioObjectPropertyScopeGlobal = fourCC("glob")
+	kAudioDevicePropertyScopeOutput = fourCC("outp")
+)
+
+const (
+	kAudioObjectPropertyElementMain uint32 = 0
+
+	kCFStringEncodingUTF8 cfStringEncoding = 0x08000100
+)
+
+// purego function bindings
+var (
+	audioObjectGetPropertyDataSize func(id audioObjectID, addr *audioObjectPropertyAddress, qualifierSize uint32, qualifier unsafe.Pointer, outSize *uint32) osStatus
+	audioObjectGetPropertyData     func(id audioObjectID, addr *audioObjectPropertyAddress, qualifierSize uint32, qualifier unsafe.Pointer, ioSize *uint32, out unsafe.Pointer) osStatus
+	audioObjectSetPropertyData     func(id audioObjectID, addr *audioObjectPropertyAddress, qualifierSize uint32, qualifier unsafe.Pointer, size uint32, data unsafe.Pointer) osStatus
+
+	// The virtual main volume lives in AudioHardwareService, which handles
+	// devices that only expose per-channel volume controls.
+	audioHardwareServiceGetPropertyData func(id audioObjectID, addr *audioObjectPropertyAddress, qualifierSize uint32, qualifier unsafe.Pointer, ioSize *uint32, out unsafe.Pointer) osStatus
+	audioHardwareServiceSetPropertyData func(id audioObjectID, addr *audioObjectPropertyAddress, qualifierSize uint32, qualifier unsafe.Pointer, size uint32, data unsafe.Pointer) osStatus
+
+	cfRelease          func(cf cfTypeRef)
+	cfStringGetLength  func(str cfStringRef) cfIndex
+	cfStringGetCString func(str cfStringRef, buffer []byte, bufferSize cfIndex, encoding cfStringEncoding) bool
+	cfStringGetMaxSize func(length cfIndex, encoding cfStringEncoding) cfIndex
+)
+
+func init() {
+	cf, err := purego.Dlopen("/System/Library/Frameworks/CoreFoundation.framework/CoreFoundation", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		panic(err)
+	}
+
+	purego.RegisterLibFunc(&cfRelease, cf, "CFRelease")
+	purego.RegisterLibFunc(&cfStringGetLength, cf, "CFStringGetLength")
+	purego.RegisterLibFunc(&cfStringGetCString, cf, "CFStringGetCString")
+	purego.RegisterLibFunc(&cfStringGetMaxSize, cf, "CFStringGetMaximumSizeForEncoding")
+
+	ca, err := purego.Dlopen("/System/Library/Frameworks/CoreAudio.framework/CoreAudio", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		panic(err)
+	}
+
+	purego.RegisterLibFunc(&audioObjectGetPropertyDataSize, ca, "AudioObjectGetPropertyDataSize")
+	purego.RegisterLibFunc(&audioObjectGetPropertyData, ca, "AudioObjectGetPropertyData")
+	purego.RegisterLibFunc(&audioObjectSetPropertyData, ca, "AudioObjectSetPropertyData")
+
+	toolbox, err := purego.Dlopen("/System/Library/Frameworks/AudioToolbox.framework/AudioToolbox", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		panic(err)
+	}
+
+	purego.RegisterLibFunc(&audioHardwareServiceGetPropertyData, toolbox, "AudioHardwareServiceGetPropertyData")
+	purego.RegisterLibFunc(&audioHardwareServiceSetPropertyData, toolbox, "AudioHardwareServiceSetPropertyData")
+}
+
+// statusError converts a non-zero OSStatus into an error.
+func statusError(op string, status osStatus) error {
+	if status == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: OSStatus %d", op, status)
+}
+
+// outputDevices lists audio devices that have at least one output stream.
+func outputDevices() ([]outputDevice, error) {
+	addr := audioObjectPropertyAddress{kAudioHardwarePropertyDevices, kAudioObjectPropertyScopeGlobal, kAudioObjectPropertyElementMain}
+
+	var size uint32
+	if err := statusError("list devices", audioObjectGetPropertyDataSize(kAudioObjectSystemObject, &addr, 0, nil, &size)); err != nil {
+		return nil, err
+	}
+
+	ids := make([]audioObjectID, size/uint32(unsafe.Sizeof(audioObjectID(0))))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if err := statusError("list devices", audioObjectGetPropertyData(kAudioObjectSystemObject, &addr, 0, nil, &size, unsafe.Pointer(&ids[0]))); err != nil {
+		return nil, err
+	}
+
+	var devices []outputDevice
+	for _, id := range ids {
+		if !hasOutputStreams(id) {
+			continue
+		}
+		name, err := deviceName(id)
+		if err != nil {
+			continue
+		}
+		devices = append(devices, outputDevice{id: uint32(id), name: name})
+	}
+	return devices, nil
+}
+
+// hasOutputStreams reports whether the device can play audio.
+func hasOutputStreams(id audioObjectID) bool {
+	addr := audioObjectPropertyAddress{kAudioDevicePropertyStreams, kAudioDevicePropertyScopeOutput, kAudioObjectPropertyElementMain}
+
+	var size uint32
+	if audioObjectGetPropertyDataSize(id, &addr, 0, nil, &size) != 0 {
+		return false
+	}
+	return size > 0
+}
+
+// deviceName returns a device's human-readable name.
+func deviceName(id audioObjectID) (string, error) {
+	addr := audioObjectPropertyAddress{kAudioObjectPropertyName, kAudioObjectPropertyScopeGlobal, kAudioObjectPropertyElementMain}
+
+	var str cfStringRef
+	size := uint32(unsafe.Sizeof(str))
+	if err := statusError("device name", audioObjectGetPropertyData(id, &addr, 0, nil, &size, unsafe.Pointer(&str))); err != nil {
+		return "", err
+	}
+	if str == 0 {
+		return "", fmt.Errorf("device name: empty")
+	}
+	defer cfRelease(cfTypeRef(str))
+
+	bufSize := cfStringGetMaxSize(cfStringGetLength(str), kCFStringEncodingUTF8) + 1
+	buf := make([]byte, bufSize)
+	if !cfStringGetCString(str, buf, bufSize, kCFStringEncodingUTF8) {
+		return "", fmt.Errorf("device name: conversion failed")
+	}
+	for i, b := range buf {
+		if b == 0 {
+			return string(buf[:i]), nil
+		}
+	}
+	return string(buf), nil
+}
+
+// defaultOutputDevice returns the current system output device.
+func defaultOutputDevice() (uint32, error) {
+	addr := audioObjectPropertyAddress{kAudioHardwarePropertyDefaultOutputDevice, kAudioObjectPropertyScopeGlobal, kAudioObjectPropertyElementMain}
+
+	var id audioObjectID
+	size := uint32(unsafe.Sizeof(id))
+	if err := statusError("default output", audioObjectGetPropertyData(kAudioObjectSystemObject, &addr, 0, nil, &size, unsafe.Pointer(&id))); err != nil {
+		return 0, err
+	}
+	return uint32(id), nil
+}
+
+// setDefaultOutputDevice switches system output to the given device.
+func setDefaultOutputDevice(id uint32) error {
+	addr := audioObjectPropertyAddress{kAudioHardwarePropertyDefaultOutputDevice, kAudioObjectPropertyScopeGlobal, kAudioObjectPropertyElementMain}
+
+	dev := audioObjectID(id)
+	return statusError("set default output", audioObjectSetPropertyData(kAudioObjectSystemObject, &addr, 0, nil, uint32(unsafe.Sizeof(dev)), unsafe.Pointer(&dev)))
+}
+
+// getVolume returns a device's output volume in [0, 1].
+func getVolume(id uint32) (float64, error) {
+	addr := audioObjectPropertyAddress{kAudioDevicePropertyVirtualMainVolume, kAudioDevicePropertyScopeOutput, kAudioObjectPropertyElementMain}
+
+	var vol float32
+	size := uint32(unsafe.Sizeof(vol))
+	if err := statusError("get volume", audioHardwareServiceGetPropertyData(audioObjectID(id), &addr, 0, nil, &size, unsafe.Pointer(&vol))); err != nil {
+		return 0, err
+	}
+	return float64(vol), nil
+}
+
+// setVolume sets a device's output volume in [0, 1].
+func setVolume(id uint32, volume float64) error {
+	addr := audioObjectPropertyAddress{kAudioDevicePropertyVirtualMainVolume, kAudioDevicePropertyScopeOutput, kAudioObjectPropertyElementMain}
+
+	vol := float32(volume)
+	return statusError("set volume", audioHardwareServiceSetPropertyData(audioObjectID(id), &addr, 0, nil, uint32(unsafe.Sizeof(vol)), unsafe.Pointer(&vol)))
+}
+
+// getMute reports whether a device's output is muted.
+func getMute(id uint32) (bool, error) {
+	addr := audioObjectPropertyAddress{kAudioDevicePropertyMute, kAudioDevicePropertyScopeOutput, kAudioObjectPropertyElementMain}
+
+	var muted uint32
+	size := uint32(unsafe.Sizeof(muted))
+	if err := statusError("get mute", audioObjectGetPropertyData(audioObjectID(id), &addr, 0, nil, &size, unsafe.Pointer(&muted))); err != nil {
+		return false, err
+	}
+	return muted != 0, nil
+}
+
+// setMute mutes or unmutes a device's output.
+func setMute(id uint32, muted bool) error {
+	addr := audioObjectPropertyAddress{kAudioDevicePropertyMute, kAudioDevicePropertyScopeOutput, kAudioObjectPropertyElementMain}
+
+	var v uint32
+	if muted {
+		v = 1
+	}
+	return statusError("set mute", audioObjectSetPropertyData(audioObjectID(id), &addr, 0, nil, uint32(unsafe.Sizeof(v)), unsafe.Pointer(&v)))
+}
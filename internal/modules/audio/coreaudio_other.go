@@ -0,0 +1,15 @@
+//go:build !darwin
+
+package audio
+
+import "errors"
+
+var errUnsupported = errors.New("audio control requires macOS")
+
+func outputDevices() ([]outputDevice, error)    { return nil, errUnsupported }
+func defaultOutputDevice() (uint32, error)      { return 0, errUnsupported }
+func setDefaultOutputDevice(id uint32) error    { return errUnsupported }
+func getVolume(id uint32) (float64, error)      { return 0, errUnsupported }
+func setVolume(id uint32, volume float64) error { return errUnsupported }
+func getMute(id uint32) (bool, error)           { return false, errUnsupported }
+func setMute(id uint32, muted bool) error       { return errUnsupported }
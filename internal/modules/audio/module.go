@@ -0,0 +1,287 @@
+// Package audio provides a Stream Deck module for system output volume and device switching.
+package audio
+
+import (
+	"context"
+	"image"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// outputDevice is a CoreAudio device that can play audio.
+type outputDevice struct {
+	id   uint32
+	name string
+}
+
+// audioState is a snapshot of the current output device.
+type audioState struct {
+	deviceID   uint32
+	deviceName string
+	volume     float64
+	muted      bool
+	ok         bool // false until the first successful refresh
+}
+
+// Module implements the audio output module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+
+	// State
+	mu      sync.RWMutex
+	state   audioState
+	devices []outputDevice
+
+	// Fonts
+	nameFace  font.Face
+	valueFace font.Face
+
+	// Resources
+	resources module.Resources
+
+	// Cancel function for polling
+	pollCancel context.CancelFunc
+}
+
+// New creates a new audio module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("audio"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "audio"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	m.pollCancel = cancel
+	go m.pollAudio(pollCtx)
+
+	m.Log().Info("Module initialized")
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	if m.pollCancel != nil {
+		m.pollCancel()
+	}
+	return m.BaseModule.Stop()
+}
+
+// pollAudio picks up volume and device changes made outside the deck.
+func (m *Module) pollAudio(ctx context.Context) {
+	m.refresh()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refresh()
+		}
+	}
+}
+
+// refresh reads the device list and the default device's volume and mute state.
+func (m *Module) refresh() {
+	devices, err := outputDevices()
+	if err != nil {
+		m.Log().Warn("Failed to list devices", "err", err)
+		return
+	}
+
+	id, err := defaultOutputDevice()
+	if err != nil {
+		m.Log().Warn("Failed to get default output", "err", err)
+		return
+	}
+
+	state := audioState{deviceID: id, ok: true}
+	for _, d := range devices {
+		if d.id == id {
+			state.deviceName = d.name
+		}
+	}
+	// Some devices (e.g. HDMI) have no software volume or mute; leave them at zero values
+	state.volume, _ = getVolume(id)
+	state.muted, _ = getMute(id)
+
+	m.mu.Lock()
+	m.state = state
+	m.devices = m.filterDevices(devices)
+	m.mu.Unlock()
+}
+
+// filterDevices restricts cycling to the configured devices, if any, in config order.
+func (m *Module) filterDevices(devices []outputDevice) []outputDevice {
+	if m.appCfg == nil || len(m.appCfg.Audio.Devices) == 0 {
+		return devices
+	}
+
+	var filtered []outputDevice
+	for _, name := range m.appCfg.Audio.Devices {
+		for _, d := range devices {
+			if strings.EqualFold(d.name, name) {
+				filtered = append(filtered, d)
+			}
+		}
+	}
+	return filtered
+}
+
+// getState returns the current audio state.
+func (m *Module) getState() audioState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// adjustVolume changes output volume by delta dial ticks (2% each).
+func (m *Module) adjustVolume(delta int8) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.state.ok {
+		return
+	}
+
+	volume := math.Max(0, math.Min(1, m.state.volume+float64(delta)*0.02))
+	if err := setVolume(m.state.deviceID, volume); err != nil {
+		m.Log().Warn("Failed to set volume", "err", err)
+		return
+	}
+	m.state.volume = volume
+
+	// Turning the volume up is a clear signal to unmute
+	if m.state.muted && delta > 0 {
+		if err := setMute(m.state.deviceID, false); err == nil {
+			m.state.muted = false
+		}
+	}
+}
+
+// toggleMute mutes or unmutes the current output device.
+func (m *Module) toggleMute() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.state.ok {
+		return
+	}
+
+	muted := !m.state.muted
+	if err := setMute(m.state.deviceID, muted); err != nil {
+		m.Log().Warn("Failed to set mute", "err", err)
+		return
+	}
+	m.state.muted = muted
+	m.Log().Info("Mute changed", "muted", muted)
+}
+
+// nextDevice switches output to the device after the current one.
+func (m *Module) nextDevice() {
+	m.mu.RLock()
+	devices := m.devices
+	current := m.state.deviceID
+	m.mu.RUnlock()
+
+	if len(devices) == 0 {
+		return
+	}
+
+	next := devices[0]
+	for i, d := range devices {
+		if d.id == current {
+			next = devices[(i+1)%len(devices)]
+			break
+		}
+	}
+	if next.id == current {
+		return
+	}
+
+	if err := setDefaultOutputDevice(next.id); err != nil {
+		m.Log().Warn("Failed to switch output", "device", next.name, "err", err)
+		return
+	}
+	m.Log().Info("Switched output", "device", next.name)
+	m.refresh()
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderDeviceKey(m.getState()),
+	}
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	if !m.resources.HasStrip() || !m.resources.Device.HasStrip() {
+		return nil
+	}
+
+	rect := m.resources.Device.StripRect
+
+	return m.renderStrip(rect, m.resources.StripRect, m.getState())
+}
+
+// HandleKey cycles the output device.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	m.nextDevice()
+	return nil
+}
+
+// HandleDial adjusts volume on rotate and toggles mute on press.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	switch event.Type {
+	case module.DialRotate:
+		m.adjustVolume(event.Delta)
+	case module.DialPress:
+		m.toggleMute()
+	}
+	return nil
+}
+
+// HandleStripTouch toggles mute on tap.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	if event.Type == module.TouchTap {
+		m.toggleMute()
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package audio
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// Common colors
+var (
+	colorBackground = color.RGBA{25, 25, 25, 255}
+	colorKeyBg      = color.RGBA{40, 40, 40, 255}
+	colorWhite      = color.RGBA{255, 255, 255, 255}
+	colorGray       = color.RGBA{160, 160, 160, 255}
+	colorBarBg      = color.RGBA{60, 60, 60, 255}
+	colorLevel      = color.RGBA{80, 200, 120, 255} // Green
+	colorLevelHigh  = color.RGBA{255, 200, 50, 255} // Yellow above 80%
+	colorMuted      = color.RGBA{230, 80, 80, 255}  // Red
+)
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.valueFace, err = render.NewFace(render.Bold, 22); err != nil {
+		return err
+	}
+	if m.nameFace, err = render.NewFace(render.Regular, 12); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deviceIcon picks an icon for an output device based on its name.
+func deviceIcon(name string) string {
+	lower := strings.ToLower(name)
+	for _, hint := range []string{"headphone", "airpods", "buds", "headset"} {
+		if strings.Contains(lower, hint) {
+			return "headphones"
+		}
+	}
+	return "speaker"
+}
+
+// renderDeviceKey renders the current output device's icon and name.
+func (m *Module) renderDeviceKey(state audioState) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	name := state.deviceName
+	if !state.ok {
+		name = "No output"
+	}
+
+	iconSize := 34
+	icon := render.Icon(deviceIcon(name), iconSize, colorWhite)
+	iconX := (keySize - iconSize) / 2
+	draw.Draw(img, image.Rect(iconX, 8, iconX+iconSize, 8+iconSize), icon, image.Point{}, draw.Over)
+
+	render.DrawTextCentered(img, render.TruncateText(name, m.nameFace, keySize-6), keySize/2, 60, m.nameFace, colorGray)
+
+	return img
+}
+
+// renderStrip renders a volume level bar within the module's strip region.
+func (m *Module) renderStrip(rect, region image.Rectangle, state audioState) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	// Volume icon on the left
+	iconName, iconColor := "volume-2", colorWhite
+	if state.muted {
+		iconName, iconColor = "volume-x", colorMuted
+	}
+	iconSize := 40
+	iconX := region.Min.X + 15
+	iconY := region.Min.Y + (region.Dy()-iconSize)/2
+	icon := render.Icon(iconName, iconSize, iconColor)
+	draw.Draw(img, image.Rect(iconX, iconY, iconX+iconSize, iconY+iconSize), icon, image.Point{}, draw.Over)
+
+	// Percentage on the right
+	pct := fmt.Sprintf("%.0f%%", state.volume*100)
+	pctW := font.MeasureString(m.valueFace, "100%").Ceil()
+	pctX := region.Max.X - 15 - pctW
+	render.DrawText(img, pct, pctX, region.Min.Y+62, m.valueFace, colorWhite)
+
+	// Device name above the level bar
+	barX0 := iconX + iconSize + 15
+	barX1 := pctX - 15
+	if barX1 <= barX0 {
+		return img
+	}
+	render.DrawText(img, render.TruncateText(state.deviceName, m.nameFace, barX1-barX0), barX0, region.Min.Y+38, m.nameFace, colorGray)
+
+	// Segmented level bar
+	barTop, barBottom := region.Min.Y+50, region.Min.Y+66
+	const segments = 20
+	segW := (barX1 - barX0) / segments
+	lit := int(state.volume*segments + 0.5)
+	for i := 0; i < segments; i++ {
+		x := barX0 + i*segW
+		col := colorBarBg
+		if i < lit {
+			switch {
+			case state.muted:
+				col = colorGray
+			case i >= segments*4/5:
+				col = colorLevelHigh
+			default:
+				col = colorLevel
+			}
+		}
+		draw.Draw(img, image.Rect(x, barTop, x+segW-2, barBottom), &image.Uniform{col}, image.Point{}, draw.Src)
+	}
+
+	return img
+}
@@ -0,0 +1,110 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/httpx"
+)
+
+// Provider names accepted in ci.provider.
+const (
+	ProviderBuildkite = "buildkite"
+	ProviderCircleCI  = "circleci"
+	ProviderJenkins   = "jenkins"
+)
+
+// State is a build's outcome, or that it hasn't finished.
+type State int
+
+const (
+	StateUnknown State = iota
+	StatePassed
+	StateFailed
+	StateRunning
+	StateQueued
+	StateCanceled
+)
+
+// Build is the latest build of a pipeline.
+type Build struct {
+	Number   int
+	State    State
+	URL      string
+	Started  time.Time // zero if not started
+	Finished time.Time // zero if not finished
+
+	// Progress is the fraction done while running, or -1 if unknown.
+	Progress float64
+}
+
+// Provider fetches the latest build of a pipeline.
+type Provider interface {
+	Name() string
+	Latest(ctx context.Context, p config.CIPipeline) (Build, error)
+}
+
+// newProvider returns the provider selected in cfg.
+func newProvider(cfg config.CIConfig) (Provider, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("no CI token configured (set CI_TOKEN or run 'belowdeck setup')")
+	}
+
+	switch cfg.Provider {
+	case ProviderBuildkite:
+		if cfg.Org == "" {
+			return nil, fmt.Errorf("ci.org is required for Buildkite")
+		}
+		return &buildkite{org: cfg.Org, token: cfg.Token}, nil
+	case ProviderCircleCI:
+		return &circleCI{token: cfg.Token}, nil
+	case ProviderJenkins:
+		if cfg.URL == "" || cfg.Username == "" {
+			return nil, fmt.Errorf("ci.url and ci.username are required for Jenkins")
+		}
+		return newJenkins(cfg), nil
+	case "":
+		return nil, fmt.Errorf("ci.provider is not set (buildkite, circleci, or jenkins)")
+	default:
+		return nil, fmt.Errorf("unknown CI provider %q (want buildkite, circleci, or jenkins)", cfg.Provider)
+	}
+}
+
+// jobProgress returns the fraction of total jobs that are done, or -1 if
+// there are none.
+func jobProgress(done, total int) float64 {
+	if total == 0 {
+		return -1
+	}
+	return float64(done) / float64(total)
+}
+
+// httpClient is shared by the providers; callers' contexts bound each request.
+var httpClient = httpx.New("ci", 15*time.Second)
+
+// getJSON fetches url with headers set and decodes the JSON response into v.
+func getJSON(ctx context.Context, url string, headers map[string]string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
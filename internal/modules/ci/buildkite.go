@@ -0,0 +1,93 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// buildkite fetches builds from the Buildkite REST API.
+type buildkite struct {
+	org   string
+	token string
+}
+
+func (b *buildkite) Name() string { return "Buildkite" }
+
+func (b *buildkite) Latest(ctx context.Context, p config.CIPipeline) (Build, error) {
+	q := url.Values{}
+	q.Set("per_page", "1")
+	if p.Branch != "" {
+		q.Set("branch", p.Branch)
+	}
+	u := fmt.Sprintf("https://api.buildkite.com/v2/organizations/%s/pipelines/%s/builds?%s",
+		url.PathEscape(b.org), url.PathEscape(p.Name), q.Encode())
+
+	var builds []struct {
+		Number     int        `json:"number"`
+		State      string     `json:"state"`
+		WebURL     string     `json:"web_url"`
+		StartedAt  *time.Time `json:"started_at"`
+		FinishedAt *time.Time `json:"finished_at"`
+		Jobs       []struct {
+			Type  string `json:"type"`
+			State string `json:"state"`
+		} `json:"jobs"`
+	}
+	if err := getJSON(ctx, u, map[string]string{"Authorization": "Bearer " + b.token}, &builds); err != nil {
+		return Build{}, err
+	}
+	if len(builds) == 0 {
+		return Build{}, fmt.Errorf("no builds")
+	}
+
+	bk := builds[0]
+	build := Build{
+		Number:   bk.Number,
+		State:    buildkiteState(bk.State),
+		URL:      bk.WebURL,
+		Progress: -1,
+	}
+	if bk.StartedAt != nil {
+		build.Started = *bk.StartedAt
+	}
+	if bk.FinishedAt != nil {
+		build.Finished = *bk.FinishedAt
+	}
+
+	if build.State == StateRunning {
+		var done, total int
+		for _, j := range bk.Jobs {
+			if j.Type != "script" {
+				continue
+			}
+			total++
+			switch j.State {
+			case "passed", "failed", "canceled", "skipped", "broken", "timed_out":
+				done++
+			}
+		}
+		build.Progress = jobProgress(done, total)
+	}
+	return build, nil
+}
+
+// buildkiteState maps a Buildkite build state.
+func buildkiteState(s string) State {
+	switch s {
+	case "passed":
+		return StatePassed
+	case "failed", "failing":
+		return StateFailed
+	case "running", "canceling":
+		return StateRunning
+	case "scheduled", "blocked", "creating":
+		return StateQueued
+	case "canceled", "skipped", "not_run":
+		return StateCanceled
+	}
+	return StateUnknown
+}
@@ -0,0 +1,129 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+const circleAPI = "https://circleci.com/api/v2"
+
+// circleCI fetches pipelines from the CircleCI v2 API. A pipeline's state
+// is the worst of its workflows'.
+type circleCI struct {
+	token string
+}
+
+func (c *circleCI) Name() string { return "CircleCI" }
+
+func (c *circleCI) Latest(ctx context.Context, p config.CIPipeline) (Build, error) {
+	headers := map[string]string{"Circle-Token": c.token}
+
+	u := fmt.Sprintf("%s/project/%s/pipeline", circleAPI, p.Name)
+	if p.Branch != "" {
+		u += "?branch=" + url.QueryEscape(p.Branch)
+	}
+	var pipelines struct {
+		Items []struct {
+			ID     string `json:"id"`
+			Number int    `json:"number"`
+		} `json:"items"`
+	}
+	if err := getJSON(ctx, u, headers, &pipelines); err != nil {
+		return Build{}, err
+	}
+	if len(pipelines.Items) == 0 {
+		return Build{}, fmt.Errorf("no pipelines")
+	}
+	pl := pipelines.Items[0]
+
+	var workflows struct {
+		Items []struct {
+			ID        string     `json:"id"`
+			Status    string     `json:"status"`
+			CreatedAt time.Time  `json:"created_at"`
+			StoppedAt *time.Time `json:"stopped_at"`
+		} `json:"items"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/pipeline/%s/workflow", circleAPI, pl.ID), headers, &workflows); err != nil {
+		return Build{}, err
+	}
+
+	build := Build{
+		Number:   pl.Number,
+		URL:      fmt.Sprintf("https://app.circleci.com/pipelines/%s/%d", p.Name, pl.Number),
+		Progress: -1,
+	}
+	var running []string
+	finished := true
+	for i, wf := range workflows.Items {
+		state := circleState(wf.Status)
+		if i == 0 || worse(state, build.State) {
+			build.State = state
+		}
+		if build.Started.IsZero() || wf.CreatedAt.Before(build.Started) {
+			build.Started = wf.CreatedAt
+		}
+		if wf.StoppedAt == nil {
+			finished = false
+		} else if wf.StoppedAt.After(build.Finished) {
+			build.Finished = *wf.StoppedAt
+		}
+		if state == StateRunning {
+			running = append(running, wf.ID)
+		}
+	}
+	if !finished {
+		build.Finished = time.Time{}
+	}
+
+	if len(running) > 0 {
+		var done, total int
+		for _, id := range running {
+			var jobs struct {
+				Items []struct {
+					Status string `json:"status"`
+				} `json:"items"`
+			}
+			if err := getJSON(ctx, fmt.Sprintf("%s/workflow/%s/job", circleAPI, id), headers, &jobs); err != nil {
+				return build, nil // progress is optional
+			}
+			for _, j := range jobs.Items {
+				total++
+				switch j.Status {
+				case "success", "failed", "canceled", "not_run", "infrastructure_fail", "timedout", "terminated-unknown":
+					done++
+				}
+			}
+		}
+		build.Progress = jobProgress(done, total)
+	}
+	return build, nil
+}
+
+// circleState maps a CircleCI workflow status.
+func circleState(s string) State {
+	switch s {
+	case "success":
+		return StatePassed
+	case "failed", "error", "failing", "unauthorized":
+		return StateFailed
+	case "running":
+		return StateRunning
+	case "on_hold", "not_run":
+		return StateQueued
+	case "canceled":
+		return StateCanceled
+	}
+	return StateUnknown
+}
+
+// worse reports whether a should stand for a pipeline over b: failures
+// first, then running work.
+func worse(a, b State) bool {
+	rank := map[State]int{StateFailed: 4, StateRunning: 3, StateQueued: 2, StatePassed: 1}
+	return rank[a] > rank[b]
+}
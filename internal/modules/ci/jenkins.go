@@ -0,0 +1,87 @@
+package ci
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// jenkins fetches job builds from a Jenkins server's JSON API.
+type jenkins struct {
+	base string
+	auth string
+}
+
+func newJenkins(cfg config.CIConfig) *jenkins {
+	creds := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Token))
+	return &jenkins{
+		base: strings.TrimSuffix(cfg.URL, "/"),
+		auth: "Basic " + creds,
+	}
+}
+
+func (j *jenkins) Name() string { return "Jenkins" }
+
+// jobPath turns "folder/job" into "/job/folder/job/job".
+func jobPath(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.Trim(name, "/"), "/") {
+		b.WriteString("/job/")
+		b.WriteString(url.PathEscape(part))
+	}
+	return b.String()
+}
+
+func (j *jenkins) Latest(ctx context.Context, p config.CIPipeline) (Build, error) {
+	u := j.base + jobPath(p.Name) + "/lastBuild/api/json?tree=number,result,building,timestamp,duration,estimatedDuration,url"
+
+	var jb struct {
+		Number            int     `json:"number"`
+		Result            *string `json:"result"`
+		Building          bool    `json:"building"`
+		Timestamp         int64   `json:"timestamp"` // start, epoch millis
+		Duration          int64   `json:"duration"`
+		EstimatedDuration int64   `json:"estimatedDuration"`
+		URL               string  `json:"url"`
+	}
+	if err := getJSON(ctx, u, map[string]string{"Authorization": j.auth}, &jb); err != nil {
+		return Build{}, err
+	}
+
+	build := Build{
+		Number:   jb.Number,
+		URL:      jb.URL,
+		Started:  time.UnixMilli(jb.Timestamp),
+		Progress: -1,
+	}
+	switch {
+	case jb.Building:
+		build.State = StateRunning
+		// Jenkins estimates from recent builds; cap short of done since it's a guess
+		if jb.EstimatedDuration > 0 {
+			elapsed := time.Since(build.Started).Milliseconds()
+			build.Progress = min(float64(elapsed)/float64(jb.EstimatedDuration), 0.99)
+		}
+	case jb.Result != nil:
+		build.State = jenkinsState(*jb.Result)
+		build.Finished = build.Started.Add(time.Duration(jb.Duration) * time.Millisecond)
+	}
+	return build, nil
+}
+
+// jenkinsState maps a Jenkins build result.
+func jenkinsState(result string) State {
+	switch result {
+	case "SUCCESS":
+		return StatePassed
+	case "FAILURE", "UNSTABLE":
+		return StateFailed
+	case "ABORTED", "NOT_BUILT":
+		return StateCanceled
+	}
+	return StateUnknown
+}
@@ -0,0 +1,240 @@
+// Package ci provides a Stream Deck module showing the latest build status
+// of configured Buildkite, CircleCI, or Jenkins pipelines.
+package ci
+
+import (
+	"context"
+	"image"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// pollInterval is how often pipelines are checked unless configured.
+const pollInterval = 30 * time.Second
+
+// pipelineState is what's known about one configured pipeline.
+type pipelineState struct {
+	build  Build
+	loaded bool
+	err    bool // last fetch failed
+}
+
+// Module implements the CI wallboard module.
+type Module struct {
+	module.BaseModule
+	module.Freshness
+
+	appCfg    *config.Config
+	interval  time.Duration // between fetches
+	provider  Provider
+	pipelines []config.CIPipeline
+
+	mu     sync.RWMutex
+	states []pipelineState // parallel to pipelines
+
+	// Fonts
+	labelFace      font.Face
+	numberFace     font.Face
+	smallFace      font.Face
+	stripTitleFace font.Face
+	stripLabelFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new CI module.
+func New(appCfg *config.Config) *Module {
+	interval := appCfg.PollInterval("ci", pollInterval)
+	return &Module{
+		BaseModule: module.NewBaseModule("ci"),
+		Freshness:  module.Freshness{StaleAfter: 3 * interval},
+		appCfg:     appCfg,
+		interval:   interval,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "ci"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var cfg config.CIConfig
+	if m.appCfg != nil {
+		cfg = m.appCfg.CI
+	}
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return err
+	}
+	m.provider = provider
+
+	m.pipelines = cfg.Pipelines
+	if len(m.pipelines) > len(res.Keys) {
+		m.Log().Warn("More pipelines than keys, ignoring the rest", "pipelines", len(m.pipelines), "keys", len(res.Keys))
+		m.pipelines = m.pipelines[:len(res.Keys)]
+	}
+	m.states = make([]pipelineState, len(m.pipelines))
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	go m.pollBuilds(ctx)
+
+	m.Log().Info("Module initialized", "provider", provider.Name(), "pipelines", len(m.pipelines))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// pollBuilds checks every pipeline now and every interval.
+func (m *Module) pollBuilds(ctx context.Context) {
+	m.fetchBuilds(ctx)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.fetchBuilds(ctx)
+		}
+	}
+}
+
+// fetchBuilds fetches the latest build of each pipeline concurrently. A
+// pipeline that fails to fetch keeps its last known build.
+func (m *Module) fetchBuilds(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i, p := range m.pipelines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			build, err := m.provider.Latest(ctx, p)
+			metrics.ObserveFetch(m.ID(), start, err)
+			m.Fetched(err)
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if err != nil {
+				m.Log().Warn("Failed to fetch build", "pipeline", p.Name, "err", err)
+				m.states[i].err = true
+				return
+			}
+			m.states[i] = pipelineState{build: build, loaded: true}
+		}()
+	}
+	wg.Wait()
+}
+
+// snapshot returns a copy of every pipeline's state.
+func (m *Module) snapshot() []pipelineState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]pipelineState(nil), m.states...)
+}
+
+// label returns the display name for pipeline i.
+func (m *Module) label(i int) string {
+	if m.pipelines[i].Label != "" {
+		return m.pipelines[i].Label
+	}
+	return m.pipelines[i].Name
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	states := m.snapshot()
+	keys := make(map[module.KeyID]image.Image)
+	for i := range m.pipelines {
+		keys[m.resources.Keys[i]] = m.renderPipelineKey(m.label(i), states[i])
+	}
+	return keys
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	if !m.resources.HasStrip() || !m.resources.Device.HasStrip() {
+		return nil
+	}
+
+	rect := m.resources.Device.StripRect
+
+	return m.renderStrip(rect, m.resources.StripRect, m.snapshot(), time.Now())
+}
+
+// HandleKey opens the pipeline's latest build in the browser.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	states := m.snapshot()
+	for i := range m.pipelines {
+		if m.resources.Keys[i] == id && states[i].build.URL != "" {
+			m.openURL(states[i].build.URL)
+		}
+	}
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch opens the running build shown on the strip.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	if event.Type != module.TouchTap {
+		return nil
+	}
+	states := m.snapshot()
+	if i := runningIndex(states); i >= 0 && states[i].build.URL != "" {
+		m.openURL(states[i].build.URL)
+	}
+	return nil
+}
+
+// runningIndex returns the index of the most recently started running
+// build, or -1 if none is running.
+func runningIndex(states []pipelineState) int {
+	best := -1
+	for i, s := range states {
+		if s.build.State != StateRunning {
+			continue
+		}
+		if best < 0 || s.build.Started.After(states[best].build.Started) {
+			best = i
+		}
+	}
+	return best
+}
+
+// openURL opens a URL in the default browser.
+func (m *Module) openURL(url string) {
+	if err := exec.Command("open", url).Start(); err != nil {
+		m.Log().Warn("Failed to open URL", "url", url, "err", err)
+	}
+}
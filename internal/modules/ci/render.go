@@ -0,0 +1,206 @@
+package ci
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// Colors
+var (
+	colorPassed   = color.RGBA{40, 120, 60, 255}
+	colorFailed   = color.RGBA{170, 45, 45, 255}
+	colorRunning  = color.RGBA{190, 140, 30, 255}
+	colorQueued   = color.RGBA{70, 70, 90, 255}
+	colorBarTrack = color.RGBA{60, 60, 60, 255}
+	colorStale    = color.RGBA{200, 200, 200, 255}
+	colorFailText = color.RGBA{240, 90, 80, 255}
+)
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Bold, 12); err != nil {
+		return err
+	}
+	if m.numberFace, err = render.NewFace(render.Bold, 16); err != nil {
+		return err
+	}
+	if m.smallFace, err = render.NewFace(render.Regular, 11); err != nil {
+		return err
+	}
+	if m.stripTitleFace, err = render.NewFace(render.Bold, 18); err != nil {
+		return err
+	}
+	if m.stripLabelFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+	return nil
+}
+
+// stateColor returns the background color for a build state.
+func stateColor(s State) color.Color {
+	switch s {
+	case StatePassed:
+		return colorPassed
+	case StateFailed:
+		return colorFailed
+	case StateRunning:
+		return colorRunning
+	case StateQueued:
+		return colorQueued
+	}
+	return render.ColorKeyBg
+}
+
+// stateText returns a short word for a build state.
+func stateText(s State) string {
+	switch s {
+	case StatePassed:
+		return "passed"
+	case StateFailed:
+		return "failed"
+	case StateRunning:
+		return "running"
+	case StateQueued:
+		return "queued"
+	case StateCanceled:
+		return "canceled"
+	}
+	return "unknown"
+}
+
+// renderPipelineKey renders a pipeline's name, build number, and state,
+// colored by the state. A progress bar runs along the bottom while building.
+func (m *Module) renderPipelineKey(label string, s pipelineState) image.Image {
+	if !s.loaded {
+		img := render.NewKey(render.ColorKeyBg)
+		render.DrawTextCentered(img, render.TruncateText(label, m.labelFace, render.KeySize-6), render.KeySize/2, 30, m.labelFace, render.ColorWhite)
+		render.DrawTextCentered(img, "...", render.KeySize/2, 52, m.labelFace, render.ColorGray)
+		return img
+	}
+
+	b := s.build
+	img := render.NewKey(stateColor(b.State))
+	render.DrawTextCentered(img, render.TruncateText(label, m.labelFace, render.KeySize-6), render.KeySize/2, 18, m.labelFace, render.ColorWhite)
+	render.DrawTextCentered(img, fmt.Sprintf("#%d", b.Number), render.KeySize/2, 40, m.numberFace, render.ColorWhite)
+
+	detail := stateText(b.State)
+	switch {
+	case b.State == StateRunning && !b.Started.IsZero():
+		detail = duration(time.Since(b.Started))
+	case !b.Finished.IsZero():
+		detail = render.FormatAge(time.Since(b.Finished)) + " ago"
+	}
+	textColor := render.ColorWhite
+	if s.err {
+		// Last fetch failed, so this may be out of date
+		detail += "?"
+		textColor = colorStale
+	}
+	render.DrawTextCentered(img, detail, render.KeySize/2, 58, m.smallFace, textColor)
+
+	if b.State == StateRunning && b.Progress >= 0 {
+		drawProgress(img, image.Rect(4, render.KeySize-6, render.KeySize-4, render.KeySize-3), b.Progress)
+	}
+	return img
+}
+
+// renderStrip shows the running build's progress, or a summary when
+// nothing is running.
+func (m *Module) renderStrip(rect, region image.Rectangle, states []pipelineState, now time.Time) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+
+	x := region.Min.X + 12
+	width := region.Dx() - 24
+
+	i := runningIndex(states)
+	if i < 0 {
+		m.drawSummary(img, region, states)
+		return img
+	}
+
+	b := states[i].build
+	title := fmt.Sprintf("%s #%d", m.label(i), b.Number)
+	render.DrawText(img, render.TruncateText(title, m.stripTitleFace, width), x, region.Min.Y+32, m.stripTitleFace, render.ColorWhite)
+
+	status := "running " + duration(now.Sub(b.Started))
+	if b.Progress >= 0 {
+		status = fmt.Sprintf("%d%%, %s", int(b.Progress*100), status)
+	}
+	if others := countState(states, StateRunning) - 1; others > 0 {
+		status += fmt.Sprintf(" (+%d more)", others)
+	}
+	render.DrawText(img, status, x, region.Min.Y+56, m.stripLabelFace, render.ColorGray)
+
+	bar := image.Rect(x, region.Min.Y+70, x+width, region.Min.Y+78)
+	if b.Progress >= 0 {
+		drawProgress(img, bar, b.Progress)
+	} else if bar.Dx() > 5 {
+		// No estimate: a sliding block shows it's alive
+		draw.Draw(img, bar, &image.Uniform{colorBarTrack}, image.Point{}, draw.Src)
+		block := bar.Dx() / 5
+		offset := int(now.UnixMilli()/20) % (bar.Dx() - block)
+		draw.Draw(img, image.Rect(bar.Min.X+offset, bar.Min.Y, bar.Min.X+offset+block, bar.Max.Y), &image.Uniform{colorRunning}, image.Point{}, draw.Src)
+	}
+	return img
+}
+
+// drawSummary draws counts of failing and passing pipelines.
+func (m *Module) drawSummary(img *image.RGBA, region image.Rectangle, states []pipelineState) {
+	centerX := region.Min.X + region.Dx()/2
+	failed := countState(states, StateFailed)
+
+	switch {
+	case len(states) == 0:
+		render.DrawTextCentered(img, "No pipelines", centerX, region.Min.Y+56, m.stripTitleFace, render.ColorGray)
+	case failed > 0:
+		var names string
+		for i, s := range states {
+			if s.build.State == StateFailed {
+				if names != "" {
+					names += ", "
+				}
+				names += m.label(i)
+			}
+		}
+		render.DrawTextCentered(img, fmt.Sprintf("%d failing", failed), centerX, region.Min.Y+42, m.stripTitleFace, colorFailText)
+		render.DrawTextCentered(img, render.TruncateText(names, m.stripLabelFace, region.Dx()-24), centerX, region.Min.Y+68, m.stripLabelFace, render.ColorGray)
+	default:
+		passed := countState(states, StatePassed)
+		render.DrawTextCentered(img, fmt.Sprintf("%d/%d passing", passed, len(states)), centerX, region.Min.Y+56, m.stripTitleFace, render.ColorWhite)
+	}
+}
+
+// drawProgress draws a progress bar filled to fraction.
+func drawProgress(img *image.RGBA, bar image.Rectangle, fraction float64) {
+	draw.Draw(img, bar, &image.Uniform{colorBarTrack}, image.Point{}, draw.Src)
+	fill := bar
+	fill.Max.X = bar.Min.X + int(float64(bar.Dx())*min(max(fraction, 0), 1))
+	draw.Draw(img, fill, &image.Uniform{render.ColorWhite}, image.Point{}, draw.Src)
+}
+
+// countState returns how many pipelines' latest builds are in state s.
+func countState(states []pipelineState, s State) int {
+	n := 0
+	for _, st := range states {
+		if st.loaded && st.build.State == s {
+			n++
+		}
+	}
+	return n
+}
+
+// duration formats an elapsed time as "45s" or "3m12s".
+func duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,219 @@
+// Package clock provides a Stream Deck module with a stopwatch, countdown timer, and world clock.
+package clock
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// longPressDuration is how long the stopwatch key must be held to reset it.
+const longPressDuration = 500 * time.Millisecond
+
+// zone is a configured time zone ready for display.
+type zone struct {
+	label string
+	loc   *time.Location
+}
+
+// Module implements the clock module. Its first key is the stopwatch, its
+// second key shows the countdown, and its first dial sets and runs the countdown.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+	zones  []zone
+
+	// State
+	mu        sync.Mutex
+	stopwatch stopwatch
+	countdown countdown
+
+	// Fonts
+	labelFace      font.Face
+	valueFace      font.Face
+	stripLabelFace font.Face
+	stripTimeFace  font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new clock module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("clock"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "clock"
+}
+
+// Key slots.
+const (
+	slotStopwatch = "stopwatch"
+	slotCountdown = "countdown"
+)
+
+// Slots declares the module's keys.
+func (m *Module) Slots() []module.Slot {
+	return []module.Slot{{Name: slotStopwatch}, {Name: slotCountdown}}
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	faces := []struct {
+		dst    *font.Face
+		weight render.Weight
+		size   float64
+	}{
+		{&m.labelFace, render.Regular, 11},
+		{&m.valueFace, render.Bold, 20},
+		{&m.stripLabelFace, render.Regular, 14},
+		{&m.stripTimeFace, render.Bold, 28},
+	}
+	for _, f := range faces {
+		face, err := render.NewFace(f.weight, f.size)
+		if err != nil {
+			return err
+		}
+		*f.dst = face
+	}
+
+	var zones []config.ClockZone
+	if m.appCfg != nil {
+		zones = m.appCfg.Clock.Zones
+	}
+	m.zones = m.loadZones(zones)
+	m.restoreTimers()
+
+	m.Log().Info("Module initialized", "zones", len(m.zones))
+	return nil
+}
+
+// loadZones resolves configured time zones, skipping invalid ones. With none
+// configured, local time is shown.
+func (m *Module) loadZones(cfgs []config.ClockZone) []zone {
+	var zones []zone
+	for _, c := range cfgs {
+		loc, err := c.Location()
+		if err != nil {
+			m.Log().Warn("Invalid time zone", "tz", c.TZ, "err", err)
+			continue
+		}
+		label := c.Label
+		if label == "" {
+			label = c.TZ
+		}
+		zones = append(zones, zone{label: label, loc: loc})
+	}
+	if len(zones) == 0 {
+		zones = append(zones, zone{label: "Local", loc: time.Local})
+	}
+	return zones
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	now := time.Now()
+
+	m.mu.Lock()
+	elapsed, swRunning := m.stopwatch.value(now), m.stopwatch.running
+	left, cdRunning, cdFinished := m.countdown.left(now), m.countdown.running, m.countdown.finished(now)
+	notify := cdFinished && !m.countdown.notified
+	if notify {
+		m.countdown.notified = true
+		m.saveTimers()
+	}
+	m.mu.Unlock()
+
+	if notify {
+		m.Notify(module.Notification{Text: "Timer done", Color: colorAlarmBg})
+	}
+
+	keys := make(map[module.KeyID]image.Image)
+	if k, ok := m.resources.Slot(slotStopwatch); ok {
+		keys[k] = m.renderStopwatchKey(elapsed, swRunning)
+	}
+	if k, ok := m.resources.Slot(slotCountdown); ok {
+		keys[k] = m.renderCountdownKey(left, cdRunning, cdFinished, now)
+	}
+	return keys
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	if !m.resources.HasStrip() || !m.resources.Device.HasStrip() {
+		return nil
+	}
+
+	rect := m.resources.Device.StripRect
+
+	return m.renderStrip(rect, m.resources.StripRect, time.Now())
+}
+
+// HandleKey starts/stops the stopwatch on press, resets on long press, and
+// starts/pauses the countdown from its key.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	// Act on release so press duration is known
+	if event.Pressed {
+		return nil
+	}
+
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stopwatchKey, hasStopwatch := m.resources.Slot(slotStopwatch)
+	countdownKey, hasCountdown := m.resources.Slot(slotCountdown)
+	switch {
+	case hasStopwatch && id == stopwatchKey:
+		if event.Duration >= longPressDuration {
+			m.Log().Info("Stopwatch reset")
+			m.stopwatch.reset()
+		} else {
+			m.stopwatch.toggle(now)
+		}
+	case hasCountdown && id == countdownKey:
+		m.countdown.toggle(now)
+	}
+	m.saveTimers()
+	return nil
+}
+
+// HandleDial sets the countdown length on rotate and starts/pauses it on press.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch event.Type {
+	case module.DialRotate:
+		m.countdown.adjust(int(event.Delta), now)
+	case module.DialPress:
+		m.countdown.toggle(now)
+		m.Log().Info("Countdown toggled", "running", m.countdown.running)
+	}
+	m.saveTimers()
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
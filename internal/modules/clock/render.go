@@ -0,0 +1,103 @@
+package clock
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+)
+
+var (
+	colorRunning  = color.RGBA{80, 200, 120, 255} // Green
+	colorAlarmBg  = color.RGBA{180, 40, 40, 255}  // Red
+	colorDivider  = color.RGBA{60, 60, 60, 255}
+	colorNightDim = color.RGBA{110, 110, 140, 255} // Muted blue for off-hours zones
+)
+
+// renderStopwatchKey renders the stopwatch's elapsed time.
+func (m *Module) renderStopwatchKey(elapsed time.Duration, running bool) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	valueColor := render.ColorWhite
+	if running {
+		valueColor = colorRunning
+	}
+
+	render.DrawTextCentered(img, "Stopwatch", render.KeySize/2, 18, m.labelFace, render.ColorGray)
+	render.DrawTextCentered(img, formatDuration(elapsed), render.KeySize/2, 48, m.valueFace, valueColor)
+
+	return img
+}
+
+// renderCountdownKey renders the countdown's remaining time. A finished
+// countdown flashes until acknowledged.
+func (m *Module) renderCountdownKey(left time.Duration, running, finished bool, now time.Time) image.Image {
+	bg := render.ColorKeyBg
+	if finished && now.UnixMilli()/500%2 == 0 {
+		bg = colorAlarmBg
+	}
+	img := render.NewKey(bg)
+
+	valueColor := render.ColorWhite
+	switch {
+	case finished:
+	case running:
+		valueColor = colorRunning
+	case left == 0:
+		valueColor = render.ColorDimGray
+	}
+
+	label := "Timer"
+	if finished {
+		label = "Done!"
+	}
+	render.DrawTextCentered(img, label, render.KeySize/2, 18, m.labelFace, render.ColorGray)
+	render.DrawTextCentered(img, formatDuration(left), render.KeySize/2, 48, m.valueFace, valueColor)
+
+	return img
+}
+
+// renderStrip renders one column per time zone within the module's strip region.
+func (m *Module) renderStrip(rect, region image.Rectangle, now time.Time) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+
+	colW := region.Dx() / len(m.zones)
+	localDay := dayNumber(now)
+	for i, z := range m.zones {
+		x0 := region.Min.X + i*colW
+		centerX := x0 + colW/2
+
+		if i > 0 {
+			draw.Draw(img, image.Rect(x0, region.Min.Y+15, x0+1, region.Max.Y-15), &image.Uniform{colorDivider}, image.Point{}, draw.Src)
+		}
+
+		t := now.In(z.loc)
+		label := z.label
+		switch d := dayNumber(t) - localDay; {
+		case d > 0:
+			label += " +1d"
+		case d < 0:
+			label += " -1d"
+		}
+
+		// Dim zones outside roughly working hours
+		timeColor := render.ColorWhite
+		if t.Hour() < 8 || t.Hour() >= 19 {
+			timeColor = colorNightDim
+		}
+
+		render.DrawTextCentered(img, render.TruncateText(label, m.stripLabelFace, colW-10), centerX, region.Min.Y+32, m.stripLabelFace, render.ColorGray)
+		render.DrawTextCentered(img, t.Format("3:04 PM"), centerX, region.Min.Y+72, m.stripTimeFace, timeColor)
+	}
+
+	return img
+}
+
+// dayNumber returns a comparable calendar-day number for t in its own zone.
+func dayNumber(t time.Time) int {
+	y, mo, d := t.Date()
+	return int(time.Date(y, mo, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
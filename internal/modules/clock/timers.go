@@ -0,0 +1,167 @@
+package clock
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/state"
+)
+
+// stopwatch counts up while running.
+type stopwatch struct {
+	running bool
+	started time.Time     // when the current run began
+	elapsed time.Duration // accumulated from previous runs
+}
+
+// toggle starts or stops the stopwatch.
+func (s *stopwatch) toggle(now time.Time) {
+	if s.running {
+		s.elapsed += now.Sub(s.started)
+		s.running = false
+		return
+	}
+	s.started = now
+	s.running = true
+}
+
+// reset stops the stopwatch and clears it.
+func (s *stopwatch) reset() {
+	*s = stopwatch{}
+}
+
+// value returns the total elapsed time.
+func (s *stopwatch) value(now time.Time) time.Duration {
+	if s.running {
+		return s.elapsed + now.Sub(s.started)
+	}
+	return s.elapsed
+}
+
+// countdownStep is how much one dial tick changes the countdown length.
+const countdownStep = time.Minute
+
+// maxCountdown caps the countdown length.
+const maxCountdown = 24 * time.Hour
+
+// countdown counts down from a dial-set length.
+type countdown struct {
+	length    time.Duration // configured length
+	remaining time.Duration // time left while paused
+	deadline  time.Time     // when it ends, while running
+	running   bool
+	notified  bool // whether the finish notification was posted for this run
+}
+
+// adjust changes the remaining time by delta dial ticks. While running the
+// deadline moves; while stopped the length is edited.
+func (c *countdown) adjust(delta int, now time.Time) {
+	if c.running {
+		c.deadline = c.deadline.Add(time.Duration(delta) * countdownStep)
+		if c.deadline.Before(now) {
+			c.deadline = now
+		}
+		return
+	}
+
+	c.remaining = min(max(c.remaining+time.Duration(delta)*countdownStep, 0), maxCountdown)
+	c.length = c.remaining
+}
+
+// toggle starts or pauses the countdown. Once finished it resets to its length.
+func (c *countdown) toggle(now time.Time) {
+	switch {
+	case c.running && c.finished(now):
+		c.running = false
+		c.remaining = c.length
+	case c.running:
+		c.remaining = c.deadline.Sub(now)
+		c.running = false
+	case c.remaining > 0:
+		c.deadline = now.Add(c.remaining)
+		c.running = true
+		c.notified = false
+	}
+}
+
+// left returns the time remaining.
+func (c *countdown) left(now time.Time) time.Duration {
+	if !c.running {
+		return c.remaining
+	}
+	return max(c.deadline.Sub(now), 0)
+}
+
+// finished reports whether a running countdown has reached zero.
+func (c *countdown) finished(now time.Time) bool {
+	return c.running && !now.Before(c.deadline)
+}
+
+// formatDuration formats d as M:SS, or H:MM:SS past an hour.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	mins := int(d/time.Minute) % 60
+	s := int(d/time.Second) % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, mins, s)
+	}
+	return fmt.Sprintf("%d:%02d", mins, s)
+}
+
+// stateKey is where the timers are persisted across restarts.
+const stateKey = "clock"
+
+// savedTimers is the persisted form of the stopwatch and countdown. Times are
+// absolute, so a timer left running keeps counting while the daemon is down.
+type savedTimers struct {
+	Stopwatch struct {
+		Running bool          `json:"running"`
+		Started time.Time     `json:"started"`
+		Elapsed time.Duration `json:"elapsed"`
+	} `json:"stopwatch"`
+	Countdown struct {
+		Length    time.Duration `json:"length"`
+		Remaining time.Duration `json:"remaining"`
+		Deadline  time.Time     `json:"deadline"`
+		Running   bool          `json:"running"`
+		Notified  bool          `json:"notified"`
+	} `json:"countdown"`
+}
+
+// saveTimers records the timers' state. Must be called with m.mu held.
+func (m *Module) saveTimers() {
+	var s savedTimers
+	s.Stopwatch.Running = m.stopwatch.running
+	s.Stopwatch.Started = m.stopwatch.started
+	s.Stopwatch.Elapsed = m.stopwatch.elapsed
+	s.Countdown.Length = m.countdown.length
+	s.Countdown.Remaining = m.countdown.remaining
+	s.Countdown.Deadline = m.countdown.deadline
+	s.Countdown.Running = m.countdown.running
+	s.Countdown.Notified = m.countdown.notified
+	state.Save(stateKey, s)
+}
+
+// restoreTimers loads the timers' saved state, if any.
+func (m *Module) restoreTimers() {
+	var s savedTimers
+	if !state.Load(stateKey, &s) {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopwatch = stopwatch{
+		running: s.Stopwatch.Running,
+		started: s.Stopwatch.Started,
+		elapsed: s.Stopwatch.Elapsed,
+	}
+	m.countdown = countdown{
+		length:    s.Countdown.Length,
+		remaining: s.Countdown.Remaining,
+		deadline:  s.Countdown.Deadline,
+		running:   s.Countdown.Running,
+		notified:  s.Countdown.Notified,
+	}
+}
@@ -0,0 +1,191 @@
+// Package countdown provides a Stream Deck module counting down the days to
+// configured dates.
+package countdown
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// detailDuration is how long an event's details stay on the strip.
+const detailDuration = 5 * time.Second
+
+// event is a parsed countdown event bound to a key.
+type event struct {
+	label   string
+	at      time.Time
+	hasTime bool // at includes a time of day
+	key     module.KeyID
+}
+
+// Module implements the countdown module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+
+	events []event
+
+	// Fonts
+	labelFace  font.Face
+	numberFace font.Face
+	midFace    font.Face
+	unitFace   font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new countdown module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("countdown"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "countdown"
+}
+
+// Init parses the configured events and assigns them to keys in order.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	var events []config.CountdownEvent
+	if m.appCfg != nil {
+		events = m.appCfg.Countdown.Events
+	}
+	for _, ev := range events {
+		if len(m.events) >= len(res.Keys) {
+			m.Log().Warn("No key available, skipping event", "event", ev.Label)
+			continue
+		}
+		at, hasTime, err := ev.Time()
+		if err != nil {
+			m.Log().Warn("Skipping event with invalid date", "event", ev.Label, "err", err)
+			continue
+		}
+		m.events = append(m.events, event{
+			label:   ev.Label,
+			at:      at,
+			hasTime: hasTime,
+			key:     res.Keys[len(m.events)],
+		})
+	}
+
+	m.Log().Info("Module initialized", "events", len(m.events))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	now := time.Now()
+	keys := make(map[module.KeyID]image.Image)
+	for _, ev := range m.events {
+		keys[ev.key] = m.renderEventKey(ev, now)
+	}
+	return keys
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey shows the pressed event's date and time remaining on the strip.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+	for _, ev := range m.events {
+		if ev.key == id {
+			now := time.Now()
+			m.Notify(module.Notification{
+				Text:     details(ev, now),
+				Color:    rampColor(daysUntil(ev.at, now)),
+				Duration: detailDuration,
+			})
+		}
+	}
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
+
+// daysUntil returns the number of calendar days from now until t: 0 on the
+// day itself, negative once it has passed.
+func daysUntil(t, now time.Time) int {
+	y1, m1, d1 := now.Date()
+	y2, m2, d2 := t.Date()
+	from := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// details describes an event for the strip, e.g.
+// "Vacation: Fri, Dec 24 2026 - in 69 days".
+func details(ev event, now time.Time) string {
+	layout := "Mon, Jan 2 2006"
+	if ev.hasTime {
+		layout += " 3:04 PM"
+	}
+	date := ev.at.Format(layout)
+
+	var when string
+	switch days := daysUntil(ev.at, now); {
+	case ev.hasTime && days == 0 && ev.at.After(now):
+		when = "in " + hoursMinutes(ev.at.Sub(now))
+	case days == 0:
+		when = "today"
+	case days == 1:
+		when = "tomorrow"
+	case days > 0 && days < 14:
+		when = fmt.Sprintf("in %d days", days)
+	case days >= 14:
+		when = fmt.Sprintf("in %d days (%d weeks)", days, days/7)
+	case days == -1:
+		when = "yesterday"
+	default:
+		when = fmt.Sprintf("%d days ago", -days)
+	}
+	return fmt.Sprintf("%s: %s - %s", ev.label, date, when)
+}
+
+// hoursMinutes formats a duration under a day as "3h 20m" or "20m".
+func hoursMinutes(d time.Duration) string {
+	h := int(d.Hours())
+	mins := int(d.Minutes()) % 60
+	if h == 0 {
+		return fmt.Sprintf("%dm", mins)
+	}
+	return fmt.Sprintf("%dh %dm", h, mins)
+}
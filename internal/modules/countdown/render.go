@@ -0,0 +1,105 @@
+package countdown
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// Color ramp stops: far off, a week out, and on the day.
+var (
+	colorFar  = color.RGBA{80, 150, 240, 255}
+	colorWeek = color.RGBA{230, 180, 40, 255}
+	colorNear = color.RGBA{235, 75, 60, 255}
+	colorPast = color.RGBA{110, 110, 110, 255}
+)
+
+// Days at which the ramp starts warming and reaches each stop.
+const (
+	rampStartDays = 30
+	rampWeekDays  = 7
+)
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Bold, 12); err != nil {
+		return err
+	}
+	if m.numberFace, err = render.NewFace(render.Bold, 26); err != nil {
+		return err
+	}
+	if m.midFace, err = render.NewFace(render.Bold, 18); err != nil {
+		return err
+	}
+	if m.unitFace, err = render.NewFace(render.Regular, 11); err != nil {
+		return err
+	}
+	return nil
+}
+
+// rampColor returns the accent for an event days away: blue beyond a month,
+// warming to yellow a week out and red on the day. Past events are gray.
+func rampColor(days int) color.Color {
+	switch {
+	case days < 0:
+		return colorPast
+	case days >= rampStartDays:
+		return colorFar
+	case days >= rampWeekDays:
+		return lerp(colorWeek, colorFar, float64(days-rampWeekDays)/(rampStartDays-rampWeekDays))
+	default:
+		return lerp(colorNear, colorWeek, float64(days)/rampWeekDays)
+	}
+}
+
+// lerp blends from a to b by t in [0, 1].
+func lerp(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// renderEventKey renders an event's label and the days remaining, colored
+// along the ramp. On the day, events with a time count down the hours.
+func (m *Module) renderEventKey(ev event, now time.Time) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	days := daysUntil(ev.at, now)
+	accent := rampColor(days)
+	draw.Draw(img, image.Rect(0, 0, render.KeySize, 4), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	label := render.TruncateText(ev.label, m.labelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 19, m.labelFace, render.ColorGray)
+
+	number, unit := fmt.Sprint(days), "days"
+	switch {
+	case days == 0 && ev.hasTime && ev.at.After(now):
+		number, unit = hoursMinutes(ev.at.Sub(now)), "to go"
+	case days == 0:
+		number, unit = "Today", ""
+	case days == 1:
+		unit = "day"
+	case days == -1:
+		number, unit = "1", "day ago"
+	case days < 0:
+		number, unit = fmt.Sprint(-days), "days ago"
+	}
+
+	// Step down the size for longer text like "Today" or "3h 20m"
+	face := m.numberFace
+	if font.MeasureString(face, number).Ceil() > render.KeySize-8 {
+		face = m.midFace
+	}
+	render.DrawTextCentered(img, number, render.KeySize/2, 48, face, accent)
+	if unit != "" {
+		render.DrawTextCentered(img, unit, render.KeySize/2, 64, m.unitFace, render.ColorGray)
+	}
+	return img
+}
@@ -0,0 +1,102 @@
+package focus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// The Focus daemon persists its state as JSON under ~/Library/DoNotDisturb.
+// Reading it requires Full Disk Access for the process running belowdeck.
+const (
+	assertionsFile = "Library/DoNotDisturb/DB/Assertions.json"
+	modesFile      = "Library/DoNotDisturb/DB/ModeConfigurations.json"
+)
+
+// assertions mirrors the parts of Assertions.json we need: one record per
+// manually enabled Focus.
+type assertions struct {
+	Data []struct {
+		StoreAssertionRecords []struct {
+			AssertionDetails struct {
+				ModeIdentifier string `json:"assertionDetailsModeIdentifier"`
+			} `json:"assertionDetails"`
+		} `json:"storeAssertionRecords"`
+	} `json:"data"`
+}
+
+// modeConfigurations mirrors the parts of ModeConfigurations.json we need to
+// map mode identifiers to display names.
+type modeConfigurations struct {
+	Data []struct {
+		ModeConfigurations map[string]struct {
+			Mode struct {
+				Name string `json:"name"`
+			} `json:"mode"`
+		} `json:"modeConfigurations"`
+	} `json:"data"`
+}
+
+// activeFocus returns the name of the manually enabled Focus mode, or "" if
+// Focus is off. Scheduled and automation-triggered Focus isn't recorded as an
+// assertion, so it reads as off.
+func activeFocus() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	var a assertions
+	if err := readJSON(filepath.Join(home, assertionsFile), &a); err != nil {
+		return "", err
+	}
+
+	var modeID string
+	for _, d := range a.Data {
+		for _, r := range d.StoreAssertionRecords {
+			if id := r.AssertionDetails.ModeIdentifier; id != "" {
+				modeID = id
+			}
+		}
+	}
+	if modeID == "" {
+		return "", nil
+	}
+
+	var mc modeConfigurations
+	if err := readJSON(filepath.Join(home, modesFile), &mc); err != nil {
+		return "", err
+	}
+	for _, d := range mc.Data {
+		if cfg, ok := d.ModeConfigurations[modeID]; ok && cfg.Mode.Name != "" {
+			return cfg.Mode.Name, nil
+		}
+	}
+
+	// Unknown mode; still report that something is on
+	return modeID, nil
+}
+
+// readJSON decodes a JSON file into v.
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return nil
+}
+
+// runShortcut runs a macOS Shortcut by name.
+func runShortcut(ctx context.Context, name string) error {
+	out, err := exec.CommandContext(ctx, "shortcuts", "run", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("shortcuts run %q: %w: %s", name, err, out)
+	}
+	return nil
+}
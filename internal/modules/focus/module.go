@@ -0,0 +1,311 @@
+// Package focus provides a Stream Deck module for macOS Focus (Do Not Disturb) modes.
+package focus
+
+import (
+	"context"
+	"image"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// longPressDuration is how long the key must be held to open the mode picker.
+const longPressDuration = 500 * time.Millisecond
+
+// pickerTimeout is how long the mode picker stays up without input.
+const pickerTimeout = 5 * time.Second
+
+// maxPickerModes is the number of modes shown in the picker; the last key is "Off".
+const maxPickerModes = 7
+
+// Module implements the Focus mode module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+	config config.FocusConfig
+
+	// State
+	mu     sync.RWMutex
+	active string // active Focus name, "" when off
+
+	// Overlay state
+	pickerOpen bool
+
+	// Fonts
+	labelFace font.Face
+	titleFace font.Face
+
+	// Resources
+	resources module.Resources
+
+	// Cancel function for polling
+	pollCancel context.CancelFunc
+}
+
+// New creates a new Focus module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("focus"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "focus"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+	if m.appCfg != nil {
+		m.config = m.appCfg.Focus
+	}
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	m.pollCancel = cancel
+	go m.pollFocus(pollCtx)
+
+	m.Log().Info("Module initialized", "modes", len(m.config.Modes))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	if m.pollCancel != nil {
+		m.pollCancel()
+	}
+	return m.BaseModule.Stop()
+}
+
+// pollFocus picks up Focus changes made from Control Center or other devices.
+func (m *Module) pollFocus(ctx context.Context) {
+	m.refresh()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refresh()
+		}
+	}
+}
+
+// refresh reads the active Focus mode.
+func (m *Module) refresh() {
+	active, err := activeFocus()
+	if err != nil {
+		m.Log().Warn("Failed to read state", "err", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.active = active
+	m.mu.Unlock()
+}
+
+// getActive returns the active Focus name, or "" when off.
+func (m *Module) getActive() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.active
+}
+
+// setFocus switches to the named mode (or off when mode is nil) in the
+// background, updating displayed state optimistically.
+func (m *Module) setFocus(mode *config.FocusMode) {
+	shortcut, name := m.config.OffShortcut, ""
+	if mode != nil {
+		shortcut, name = mode.Shortcut, mode.Name
+	}
+	if shortcut == "" {
+		m.Log().Warn("No shortcut configured", "mode", name)
+		return
+	}
+
+	m.mu.Lock()
+	m.active = name
+	m.mu.Unlock()
+
+	m.Log().Info("Running shortcut", "shortcut", shortcut)
+	go func() {
+		if err := runShortcut(m.Context(), shortcut); err != nil {
+			m.Log().Warn("Shortcut failed", "err", err)
+		}
+		m.refresh()
+	}()
+}
+
+// toggle turns Focus off if it's on, or turns on the first configured mode.
+func (m *Module) toggle() {
+	if m.getActive() != "" {
+		m.setFocus(nil)
+		return
+	}
+	if len(m.config.Modes) == 0 {
+		m.Log().Warn("No modes configured")
+		return
+	}
+	m.setFocus(&m.config.Modes[0])
+}
+
+// pickerModes returns the modes shown in the picker.
+func (m *Module) pickerModes() []config.FocusMode {
+	modes := m.config.Modes
+	if len(modes) > maxPickerModes {
+		modes = modes[:maxPickerModes]
+	}
+	return modes
+}
+
+// openPicker shows the mode picker overlay.
+func (m *Module) openPicker() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pickerOpen = true
+}
+
+// closePicker dismisses the mode picker overlay.
+func (m *Module) closePicker() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pickerOpen = false
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderFocusKey(m.getActive()),
+	}
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey toggles Focus on short press and opens the picker on long press.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	// Act on release so press duration is known
+	if event.Pressed {
+		return nil
+	}
+
+	if event.Duration >= longPressDuration {
+		m.openPicker()
+		return nil
+	}
+	m.toggle()
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
+
+// IsOverlayActive returns true if the mode picker is visible.
+func (m *Module) IsOverlayActive() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pickerOpen
+}
+
+// OverlayTimeout returns how long the picker stays up without input.
+func (m *Module) OverlayTimeout() time.Duration {
+	return pickerTimeout
+}
+
+// CloseOverlay dismisses the picker.
+func (m *Module) CloseOverlay() {
+	m.closePicker()
+}
+
+// RenderOverlayKeys returns images for all 8 keys: one per mode, then "Off".
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	active := m.getActive()
+	modes := m.pickerModes()
+
+	keys := make(map[module.KeyID]image.Image)
+	for i := 0; i < 8; i++ {
+		keyID := module.KeyID(i + 1)
+		switch {
+		case i < len(modes):
+			keys[keyID] = m.renderPickerKey(modes[i].Name, strings.EqualFold(modes[i].Name, active))
+		case i == len(modes):
+			keys[keyID] = m.renderPickerKey("Off", active == "")
+		default:
+			keys[keyID] = m.renderEmptyKey()
+		}
+	}
+	return keys
+}
+
+// RenderOverlayStrip returns the touch strip image for the picker.
+func (m *Module) RenderOverlayStrip() image.Image {
+	rect := m.resources.Device.StripRect
+	return m.renderPickerStrip(rect)
+}
+
+// HandleOverlayKey picks a mode.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	modes := m.pickerModes()
+	i := int(id) - 1
+	switch {
+	case i < len(modes):
+		m.setFocus(&modes[i])
+	case i == len(modes):
+		m.setFocus(nil)
+	default:
+		return nil
+	}
+
+	m.closePicker()
+	return nil
+}
+
+// HandleOverlayDial dismisses the picker on dial press.
+func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
+	if event.Type == module.DialRelease {
+		m.closePicker()
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch dismisses the picker on tap.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	if event.Type == module.TouchTap {
+		m.closePicker()
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package focus
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+)
+
+// Common colors
+var (
+	colorBackground = color.RGBA{25, 25, 25, 255}
+	colorKeyBg      = color.RGBA{40, 40, 40, 255}
+	colorActiveBg   = color.RGBA{70, 50, 130, 255}   // Focus purple
+	colorActive     = color.RGBA{180, 150, 255, 255} // Light purple
+	colorWhite      = color.RGBA{255, 255, 255, 255}
+	colorGray       = color.RGBA{160, 160, 160, 255}
+	colorDimGray    = color.RGBA{80, 80, 80, 255}
+)
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.titleFace, err = render.NewFace(render.Bold, 22); err != nil {
+		return err
+	}
+	if m.labelFace, err = render.NewFace(render.Regular, 12); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderFocusKey renders the Focus key: purple with the mode name when active.
+func (m *Module) renderFocusKey(active string) image.Image {
+	bg, iconColor, label, labelColor := colorKeyBg, colorDimGray, "Focus Off", colorGray
+	if active != "" {
+		bg, iconColor, label, labelColor = colorActiveBg, colorActive, active, colorWhite
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+
+	iconSize := 34
+	icon := render.Icon("moon", iconSize, iconColor)
+	iconX := (keySize - iconSize) / 2
+	draw.Draw(img, image.Rect(iconX, 8, iconX+iconSize, 8+iconSize), icon, image.Point{}, draw.Over)
+
+	render.DrawTextCentered(img, render.TruncateText(label, m.labelFace, keySize-6), keySize/2, 60, m.labelFace, labelColor)
+
+	return img
+}
+
+// renderPickerKey renders one choice in the mode picker.
+func (m *Module) renderPickerKey(name string, selected bool) image.Image {
+	bg, textColor := colorKeyBg, colorWhite
+	if selected {
+		bg = colorActiveBg
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+
+	render.DrawTextCentered(img, render.TruncateText(name, m.labelFace, keySize-6), keySize/2, keySize/2+4, m.labelFace, textColor)
+
+	return img
+}
+
+// renderEmptyKey renders an unused picker key.
+func (m *Module) renderEmptyKey() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	return img
+}
+
+// renderPickerStrip renders the picker's strip prompt.
+func (m *Module) renderPickerStrip(rect image.Rectangle) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	render.DrawTextCentered(img, "Choose a Focus", rect.Dx()/2, rect.Dy()/2+8, m.titleFace, colorWhite)
+	render.DrawText(img, "Tap or press a dial to cancel", 15, rect.Dy()-12, m.labelFace, colorGray)
+
+	return img
+}
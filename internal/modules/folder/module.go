@@ -0,0 +1,115 @@
+// Package folder provides the keys that open and close folder pages: a
+// folder key on the parent page and a back key on the folder's own page.
+package folder
+
+import (
+	"context"
+	"image"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// iconSize is the edge length of the key icon.
+const iconSize = 36
+
+// Module implements a folder or back key.
+type Module struct {
+	module.BaseModule
+
+	label string
+	spec  string // icon file path or SF Symbol name
+	open  func()
+
+	icon      image.Image // nil if it failed to load
+	labelFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a folder key that calls open when pressed. icon is an image
+// file path or SF Symbol name; empty means folder.fill.
+func New(label, icon string, open func()) *Module {
+	if icon == "" {
+		icon = "folder.fill"
+	}
+	return &Module{
+		BaseModule: module.NewBaseModule("folder"),
+		label:      label,
+		spec:       icon,
+		open:       open,
+	}
+}
+
+// NewBack creates a back key that calls open, which should show the parent
+// page, when pressed.
+func NewBack(open func()) *Module {
+	return New("Back", "chevron.left", open)
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "folder"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var err error
+	if m.labelFace, err = render.NewFace(render.Regular, 12); err != nil {
+		return err
+	}
+	if m.icon, err = render.FindIcon(m.spec, iconSize, render.ColorWhite); err != nil {
+		m.Log().Warn("Failed to load icon", "icon", m.spec, "err", err)
+	}
+	return nil
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	img := render.NewKey(render.ColorKeyBg)
+	if m.icon != nil {
+		render.DrawIcon(img, m.icon, 10)
+	}
+	label := render.TruncateText(m.label, m.labelFace, render.KeySize-6)
+	y := 62
+	if m.icon == nil {
+		y = render.KeySize/2 + 5
+	}
+	render.DrawTextCentered(img, label, render.KeySize/2, y, m.labelFace, render.ColorGray)
+	return map[module.KeyID]image.Image{m.resources.Keys[0]: img}
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey switches pages on press.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if event.Pressed {
+		m.open()
+	}
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
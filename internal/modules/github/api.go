@@ -1,15 +1,20 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os/exec"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/phinze/belowdeck/internal/httpx"
 )
 
 // PRStats holds counts of PRs in different states (for authored PRs).
@@ -24,6 +29,7 @@ type PRStats struct {
 // ReviewStats holds the count of PRs awaiting my review.
 type ReviewStats struct {
 	Total int
+	Team  int // of Total, those requested of a configured team and not me
 }
 
 // PRStatus represents the review status of a PR.
@@ -53,36 +59,198 @@ type PRInfo struct {
 	CI      CIStatus
 	URL     string
 	HeadSHA string // For fetching CI status
+	Branch  string // head branch name
+	NodeID  string // GraphQL ID, for enabling auto-merge
 	IsDraft bool
+	Created time.Time
+
+	// Reviewers and teams whose review is still requested
+	RequestedReviewers []string
+	RequestedTeams     []string
+
+	// For PRs awaiting my review: when it was requested, and whether I'm
+	// the only reviewer requested
+	Requested    time.Time
+	SoleReviewer bool
+	Team         string // configured team it was requested of, if not me directly
 }
 
 // Client is a GitHub API client.
 type Client struct {
-	token      string
+	apiBase    string // REST API root, e.g. https://api.github.com
+	graphqlURL string
+	tokens     *tokenSource
 	httpClient *http.Client
 	username   string // cached username
+	limits     rateLimits
+}
+
+// NewClient creates a new GitHub API client for host (github.com if
+// empty). token is used if set; otherwise the token comes from the gh CLI
+// and is fetched again if the API rejects it.
+func NewClient(host, token string) (*Client, error) {
+	tokens := &tokenSource{host: host, static: token}
+	if _, err := tokens.get(); err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		apiBase:    apiBase(host),
+		graphqlURL: graphqlURL(host),
+		tokens:     tokens,
+		httpClient: httpx.New("github", 10*time.Second),
+	}, nil
 }
 
-// NewClient creates a new GitHub API client using the gh CLI token.
-func NewClient() (*Client, error) {
-	// Get token from gh CLI
-	cmd := exec.Command("gh", "auth", "token")
-	output, err := cmd.Output()
+// apiBase returns the REST API root for host. GitHub Enterprise Server
+// serves the API under /api/v3 on its own host.
+func apiBase(host string) string {
+	if host == "" || host == "github.com" {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// graphqlURL returns the GraphQL endpoint for host.
+func graphqlURL(host string) string {
+	if host == "" || host == "github.com" {
+		return "https://api.github.com/graphql"
+	}
+	return "https://" + host + "/api/graphql"
+}
+
+// tokenSource supplies the API token: a configured one, or the gh CLI's,
+// which is cached and refreshed when the API rejects it.
+type tokenSource struct {
+	host   string
+	static string
+
+	mu     sync.Mutex
+	cached string
+}
+
+// get returns the current token, asking gh for one if none is cached.
+func (t *tokenSource) get() (string, error) {
+	if t.static != "" {
+		return t.static, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cached == "" {
+		token, err := ghAuthToken(t.host)
+		if err != nil {
+			return "", err
+		}
+		t.cached = token
+	}
+	return t.cached, nil
+}
+
+// refresh drops rejected if it's still the cached token and fetches a new
+// one, reporting whether the token changed. A configured token can't be
+// refreshed.
+func (t *tokenSource) refresh(rejected string) (bool, error) {
+	if t.static != "" {
+		return false, nil
+	}
+
+	t.mu.Lock()
+	if t.cached == rejected {
+		t.cached = ""
+	}
+	t.mu.Unlock()
+
+	token, err := t.get()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gh auth token: %w", err)
+		return false, err
+	}
+	return token != rejected, nil
+}
+
+// ghAuthToken gets a token from the gh CLI.
+func ghAuthToken(host string) (string, error) {
+	args := []string{"auth", "token"}
+	if host != "" {
+		args = append(args, "--hostname", host)
+	}
+	output, err := exec.Command("gh", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get gh auth token: %w", err)
 	}
 
 	token := strings.TrimSpace(string(output))
 	if token == "" {
-		return nil, fmt.Errorf("gh auth token is empty")
+		return "", fmt.Errorf("gh auth token is empty")
 	}
+	return token, nil
+}
 
-	return &Client{
-		token: token,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}, nil
+// get performs an authenticated GET of path under the API root.
+func (c *Client) get(ctx context.Context, path string) (*http.Response, error) {
+	return c.do(ctx, "GET", c.apiBase+path, nil)
+}
+
+// do performs an authenticated request, sending body as JSON if it's
+// non-nil. If the token is rejected with 401, it's refreshed and the request
+// retried once. It returns a *RateLimitError, without sending the request if
+// it already knows, when the request's rate limit is exhausted.
+func (c *Client) do(ctx context.Context, method, endpoint string, body any) (*http.Response, error) {
+	if err := c.limits.check(endpoint); err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := c.tokens.get()
+		if err != nil {
+			return nil, err
+		}
+
+		var reader io.Reader
+		if payload != nil {
+			reader = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.limits.note(endpoint, resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+
+		changed, err := c.tokens.refresh(token)
+		if err != nil || !changed {
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+}
+
+// RateLimits returns the API rate limits as of the latest responses.
+func (c *Client) RateLimits() []RateLimit {
+	return c.limits.all()
 }
 
 // GetMyPRStats fetches stats about the authenticated user's PRs.
@@ -149,15 +317,7 @@ func (c *Client) getAuthenticatedUser(ctx context.Context) (string, error) {
 		return c.username, nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.get(ctx, "/user")
 	if err != nil {
 		return "", err
 	}
@@ -181,17 +341,9 @@ func (c *Client) getAuthenticatedUser(ctx context.Context) (string, error) {
 
 // searchPRCount searches for PRs matching a query and returns the count.
 func (c *Client) searchPRCount(ctx context.Context, query string) (int, error) {
-	apiURL := "https://api.github.com/search/issues?per_page=1&q=" + url.QueryEscape(query)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
+	path := "/search/issues?per_page=1&q=" + url.QueryEscape(query)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.get(ctx, path)
 	if err != nil {
 		return 0, err
 	}
@@ -341,17 +493,9 @@ func (c *Client) getCIStatus(ctx context.Context, repo, sha string) CIStatus {
 	}
 
 	// Use the combined status endpoint
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/commits/%s/status", repo, sha)
+	path := fmt.Sprintf("/repos/%s/commits/%s/status", repo, sha)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return CIStatusPending
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.get(ctx, path)
 	if err != nil {
 		return CIStatusPending
 	}
@@ -380,17 +524,9 @@ func (c *Client) getCIStatus(ctx context.Context, repo, sha string) CIStatus {
 
 // searchPRs searches for PRs matching a query and returns details including head SHA.
 func (c *Client) searchPRs(ctx context.Context, query string, status PRStatus) ([]PRInfo, error) {
-	apiURL := "https://api.github.com/search/issues?per_page=10&q=" + url.QueryEscape(query)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, err
-	}
+	path := "/search/issues?per_page=10&q=" + url.QueryEscape(query)
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.get(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -458,29 +594,31 @@ func (c *Client) fetchPRDetails(ctx context.Context, prs []PRInfo) {
 	for range len(prs) {
 		r := <-results
 		prs[r.index].HeadSHA = r.details.HeadSHA
+		prs[r.index].Branch = r.details.HeadRef
+		prs[r.index].NodeID = r.details.NodeID
 		prs[r.index].IsDraft = r.details.IsDraft
+		prs[r.index].Created = r.details.Created
+		prs[r.index].RequestedReviewers = r.details.Reviewers
+		prs[r.index].RequestedTeams = r.details.Teams
 	}
 }
 
 // prDetails holds extra details fetched from the PR API.
 type prDetails struct {
-	HeadSHA string
-	IsDraft bool
+	HeadSHA   string
+	HeadRef   string
+	NodeID    string
+	IsDraft   bool
+	Created   time.Time
+	Reviewers []string // logins
+	Teams     []string // slugs
 }
 
-// getPRDetails fetches the head SHA and draft status for a specific PR.
+// getPRDetails fetches the head SHA, branch, and draft status for a specific PR.
 func (c *Client) getPRDetails(ctx context.Context, repo string, number int) prDetails {
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d", repo, number)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return prDetails{}
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
+	path := fmt.Sprintf("/repos/%s/pulls/%d", repo, number)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.get(ctx, path)
 	if err != nil {
 		return prDetails{}
 	}
@@ -491,23 +629,69 @@ func (c *Client) getPRDetails(ctx context.Context, repo string, number int) prDe
 	}
 
 	var pr struct {
-		Draft bool `json:"draft"`
-		Head  struct {
+		NodeID    string    `json:"node_id"`
+		Draft     bool      `json:"draft"`
+		CreatedAt time.Time `json:"created_at"`
+		Head      struct {
 			SHA string `json:"sha"`
+			Ref string `json:"ref"`
 		} `json:"head"`
+		RequestedReviewers []struct {
+			Login string `json:"login"`
+		} `json:"requested_reviewers"`
+		RequestedTeams []struct {
+			Slug string `json:"slug"`
+		} `json:"requested_teams"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
 		return prDetails{}
 	}
 
-	return prDetails{
+	details := prDetails{
 		HeadSHA: pr.Head.SHA,
+		HeadRef: pr.Head.Ref,
+		NodeID:  pr.NodeID,
 		IsDraft: pr.Draft,
+		Created: pr.CreatedAt,
+	}
+	for _, r := range pr.RequestedReviewers {
+		details.Reviewers = append(details.Reviewers, r.Login)
 	}
+	for _, t := range pr.RequestedTeams {
+		details.Teams = append(details.Teams, t.Slug)
+	}
+	return details
+}
+
+// reviewQuery is a search for PRs awaiting my review, requested of me or
+// of team.
+type reviewQuery struct {
+	team  string // org/slug, empty for requests to me
+	query string
+}
+
+// reviewQueries returns the searches for PRs awaiting username's review.
+// Without teams, one search covers requests to username and to any team
+// they're on. With teams, requests to username directly come first, then
+// each team's not already covered by an earlier search, so no PR turns up
+// twice; requests to other teams aren't included.
+func reviewQueries(username string, teams []string) []reviewQuery {
+	const base = "is:open is:pr archived:false "
+	if len(teams) == 0 {
+		return []reviewQuery{{query: base + "review-requested:" + username}}
+	}
+	exclude := "-user-review-requested:" + username
+	queries := []reviewQuery{{query: base + "user-review-requested:" + username}}
+	for _, team := range teams {
+		queries = append(queries, reviewQuery{team: team, query: base + "team-review-requested:" + team + " " + exclude})
+		exclude += " -team-review-requested:" + team
+	}
+	return queries
 }
 
-// GetReviewRequestedStats fetches the count of PRs awaiting my review.
-func (c *Client) GetReviewRequestedStats(ctx context.Context) (ReviewStats, error) {
+// GetReviewRequestedStats fetches the count of PRs awaiting my review,
+// including those requested of teams (see reviewQueries).
+func (c *Client) GetReviewRequestedStats(ctx context.Context, teams []string) (ReviewStats, error) {
 	var stats ReviewStats
 
 	username, err := c.getAuthenticatedUser(ctx)
@@ -515,34 +699,167 @@ func (c *Client) GetReviewRequestedStats(ctx context.Context) (ReviewStats, erro
 		return stats, fmt.Errorf("failed to get username: %w", err)
 	}
 
-	// Query: is:open is:pr review-requested:{user} archived:false
-	query := fmt.Sprintf("is:open is:pr review-requested:%s archived:false", username)
-	count, err := c.searchPRCount(ctx, query)
-	if err != nil {
-		return stats, err
+	for _, q := range reviewQueries(username, teams) {
+		count, err := c.searchPRCount(ctx, q.query)
+		if err != nil {
+			return stats, err
+		}
+		stats.Total += count
+		if q.team != "" {
+			stats.Team += count
+		}
 	}
-
-	stats.Total = count
 	return stats, nil
 }
 
-// GetReviewRequestedPRList fetches PRs awaiting my review with details.
-func (c *Client) GetReviewRequestedPRList(ctx context.Context) ([]PRInfo, error) {
+// GetReviewRequestedPRList fetches PRs awaiting my review with details,
+// including those requested of teams (see reviewQueries).
+func (c *Client) GetReviewRequestedPRList(ctx context.Context, teams []string) ([]PRInfo, error) {
 	username, err := c.getAuthenticatedUser(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get username: %w", err)
 	}
 
-	// Query: is:open is:pr review-requested:{user} archived:false
-	query := fmt.Sprintf("is:open is:pr review-requested:%s archived:false", username)
-	prs, err := c.searchPRs(ctx, query, PRStatusWaiting)
-	if err != nil {
-		return nil, err
+	var prs []PRInfo
+	for _, q := range reviewQueries(username, teams) {
+		found, err := c.searchPRs(ctx, q.query, PRStatusWaiting)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].Team = q.team
+		}
+		prs = append(prs, found...)
 	}
 
 	// For review-requested PRs, the status is always "waiting" (for my review)
 	// Fetch CI statuses
 	c.fetchCIStatuses(ctx, prs)
 
+	// Most urgent first: see sortByUrgency
+	c.fetchReviewRequests(ctx, prs, username)
+	sortByUrgency(prs)
+
 	return prs, nil
 }
+
+// ApprovePR submits an approving review on a PR.
+func (c *Client) ApprovePR(ctx context.Context, repo string, number int) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d/reviews", repo, number)
+	return c.send(ctx, "POST", path, map[string]string{"event": "APPROVE"})
+}
+
+// MergePR merges a PR with the repository's default merge method.
+func (c *Client) MergePR(ctx context.Context, repo string, number int) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d/merge", repo, number)
+	return c.send(ctx, "PUT", path, struct{}{})
+}
+
+// EnableAutoMerge arranges for a PR to merge once its required checks pass.
+// There's no REST endpoint for this, so it goes through GraphQL.
+func (c *Client) EnableAutoMerge(ctx context.Context, nodeID string) error {
+	if nodeID == "" {
+		return fmt.Errorf("PR node ID unknown")
+	}
+	query := `mutation($id: ID!) {
+		enablePullRequestAutoMerge(input: {pullRequestId: $id}) { clientMutationId }
+	}`
+	resp, err := c.do(ctx, "POST", c.graphqlURL, map[string]any{
+		"query":     query,
+		"variables": map[string]string{"id": nodeID},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	// GraphQL reports failures in the body with a 200
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%s", result.Errors[0].Message)
+	}
+	return nil
+}
+
+// RerequestReview asks everyone who has already reviewed a PR, other than
+// the authenticated user, to review it again. It returns how many reviewers
+// were asked.
+func (c *Client) RerequestReview(ctx context.Context, repo string, number int) (int, error) {
+	username, err := c.getAuthenticatedUser(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get username: %w", err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d/reviews?per_page=100", repo, number)
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reviews); err != nil {
+		return 0, err
+	}
+
+	seen := make(map[string]bool)
+	var reviewers []string
+	for _, r := range reviews {
+		login := r.User.Login
+		if login == "" || login == username || seen[login] {
+			continue
+		}
+		seen[login] = true
+		reviewers = append(reviewers, login)
+	}
+	if len(reviewers) == 0 {
+		return 0, nil
+	}
+
+	path = fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", repo, number)
+	if err := c.send(ctx, "POST", path, map[string][]string{"reviewers": reviewers}); err != nil {
+		return 0, err
+	}
+	return len(reviewers), nil
+}
+
+// send performs a write request under the API root and returns an error
+// carrying GitHub's message if it doesn't succeed.
+func (c *Client) send(ctx context.Context, method, path string, body any) error {
+	resp, err := c.do(ctx, method, c.apiBase+path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var result struct {
+		Message string `json:"message"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&result) == nil && result.Message != "" {
+		return fmt.Errorf("%s", result.Message)
+	}
+	return fmt.Errorf("API error: %s", resp.Status)
+}
@@ -3,14 +3,18 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"image"
-	"log"
 	"os/exec"
 	"sync"
 	"time"
 
-	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/action"
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/metrics"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/reachability"
 	"golang.org/x/image/font"
 )
 
@@ -23,13 +27,49 @@ const (
 	OverlayReviewRequested
 )
 
+const (
+	// longPressDuration is how long a PR key must be held to open its action menu.
+	longPressDuration = 500 * time.Millisecond
+
+	// overlayTimeout is how long the overlay stays up without input.
+	overlayTimeout = 5 * time.Second
+
+	// actionMenuTimeout is how long the action menu stays up without input.
+	actionMenuTimeout = 15 * time.Second
+
+	// actionTimeout bounds a PR action's API calls.
+	actionTimeout = 30 * time.Second
+)
+
+// Action menu keys.
+const (
+	actionKeyApprove   = module.Key1
+	actionKeyMerge     = module.Key2
+	actionKeyRerequest = module.Key3
+	actionKeyCopy      = module.Key4
+	actionKeyBack      = module.Key8
+)
+
+// pollInterval is how often PR stats are fetched unless configured; not
+// much more often, to stay clear of GitHub's rate limits.
+const pollInterval = 2 * time.Minute
+
 // Module implements the GitHub PR stats module.
 type Module struct {
 	module.BaseModule
+	module.Freshness
+
+	appCfg   *config.Config
+	client   *Client
+	enabled  bool
+	interval time.Duration // between fetches, unless backing off
+
+	// When the next fetch may go, later than the next poll while backing
+	// off as the rate limit quota runs low; poll goroutine only
+	resumeAt time.Time
 
-	device  device.Device
-	client  *Client
-	enabled bool
+	// When the exhausted rate limit resets, zero if none is; guarded by mu
+	limitedUntil time.Time
 
 	// State for my PRs (Key3)
 	mu     sync.RWMutex
@@ -41,9 +81,15 @@ type Module struct {
 	reviewPRList []PRInfo
 
 	// Overlay state
-	overlayType   OverlayType
-	overlayExpiry time.Time
-	currentPage   int // Current page in pagination (0-indexed)
+	overlayType OverlayType
+	currentPage int // Current page in pagination (0-indexed)
+
+	// actionPR is the PR whose action menu is open, nil when showing the list
+	actionPR *PRInfo
+
+	// overlayPressed tracks keys pressed while the overlay was up, so the
+	// release of the key that opened it isn't taken as a selection
+	overlayPressed map[module.KeyID]bool
 
 	// Fonts
 	labelFace      font.Face
@@ -60,10 +106,13 @@ type Module struct {
 }
 
 // New creates a new GitHub module.
-func New(dev device.Device) *Module {
+func New(appCfg *config.Config) *Module {
+	interval := appCfg.PollInterval("github", pollInterval)
 	return &Module{
 		BaseModule: module.NewBaseModule("github"),
-		device:     dev,
+		Freshness:  module.Freshness{StaleAfter: 3 * interval},
+		appCfg:     appCfg,
+		interval:   interval,
 	}
 }
 
@@ -72,6 +121,20 @@ func (m *Module) ID() string {
 	return "github"
 }
 
+// Key slots.
+const (
+	slotStats = "stats" // my PR stats (outbox)
+	slotInbox = "inbox" // review-requested PRs
+)
+
+// Slots declares the module's keys.
+func (m *Module) Slots() []module.Slot {
+	return []module.Slot{
+		{Name: slotStats, Required: true},
+		{Name: slotInbox},
+	}
+}
+
 // Init initializes the module.
 func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	if err := m.BaseModule.Init(ctx, res); err != nil {
@@ -81,12 +144,16 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	m.resources = res
 	m.ctx = ctx
 
-	// Create API client (uses gh CLI token)
-	client, err := NewClient()
+	// Create API client (uses the configured token, else gh CLI's).
+	// Returning the error lets the coordinator retry once gh is authenticated.
+	var host, token string
+	if m.appCfg != nil {
+		host, token = m.appCfg.GitHub.Host, m.appCfg.GitHub.Token
+	}
+	client, err := NewClient(host, token)
 	if err != nil {
-		log.Printf("GitHub module disabled: %v", err)
 		m.enabled = false
-		return nil
+		return fmt.Errorf("GitHub client: %w", err)
 	}
 	m.client = client
 	m.enabled = true
@@ -99,7 +166,7 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Start polling
 	go m.pollStats(ctx)
 
-	log.Println("GitHub module initialized")
+	m.Log().Info("Module initialized")
 	return nil
 }
 
@@ -108,38 +175,42 @@ func (m *Module) Stop() error {
 	return m.BaseModule.Stop()
 }
 
-// pollStats periodically fetches PR stats from GitHub.
+// pollStats fetches PR stats from GitHub now and every interval while the
+// network is reachable.
 func (m *Module) pollStats(ctx context.Context) {
-	// Initial fetch
-	m.fetchStats(ctx)
-
-	// Poll every 2 minutes (to avoid rate limits)
-	ticker := time.NewTicker(2 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			m.fetchStats(ctx)
-		}
-	}
+	reachability.Poll(ctx, m.interval, m.fetchStats)
 }
 
-// fetchStats fetches the current PR stats for both my PRs and review-requested PRs.
+// fetchStats fetches the current PR stats for both my PRs and review-requested
+// PRs, unless it's backing off to save rate limit quota.
 func (m *Module) fetchStats(ctx context.Context) {
+	// Polls come every interval; allow for ticks arriving a little early
+	if time.Until(m.resumeAt) > time.Second {
+		return
+	}
+	defer m.backOff(time.Now())
+
 	// Fetch my PR stats
+	start := time.Now()
 	stats, err := m.client.GetMyPRStats(ctx)
+	metrics.ObserveFetch(m.ID(), start, err)
+	// Running out of quota isn't a failure to reach GitHub, so it doesn't
+	// count toward showing the deck offline; the data goes stale instead
+	var limited *RateLimitError
+	if !errors.As(err, &limited) {
+		m.Fetched(err)
+	}
 	if err != nil {
-		log.Printf("Failed to fetch GitHub PR stats: %v", err)
+		m.Log().Warn("Failed to fetch PR stats", "err", err)
 		return
 	}
 
 	// Also fetch PR list for overlay (includes CI status)
+	start = time.Now()
 	prList, err := m.client.GetMyPRList(ctx)
+	metrics.ObserveFetch(m.ID(), start, err)
 	if err != nil {
-		log.Printf("Failed to fetch GitHub PR list: %v", err)
+		m.Log().Warn("Failed to fetch PR list", "err", err)
 		// Continue with stats even if list fails
 	}
 
@@ -154,16 +225,20 @@ func (m *Module) fetchStats(ctx context.Context) {
 	}
 
 	// Fetch review-requested stats
-	reviewStats, err := m.client.GetReviewRequestedStats(ctx)
+	start = time.Now()
+	reviewStats, err := m.client.GetReviewRequestedStats(ctx, m.teams())
+	metrics.ObserveFetch(m.ID(), start, err)
 	if err != nil {
-		log.Printf("Failed to fetch review-requested stats: %v", err)
+		m.Log().Warn("Failed to fetch review-requested stats", "err", err)
 		// Continue with partial data
 	}
 
 	// Fetch review-requested PR list
-	reviewPRList, err := m.client.GetReviewRequestedPRList(ctx)
+	start = time.Now()
+	reviewPRList, err := m.client.GetReviewRequestedPRList(ctx, m.teams())
+	metrics.ObserveFetch(m.ID(), start, err)
 	if err != nil {
-		log.Printf("Failed to fetch review-requested PR list: %v", err)
+		m.Log().Warn("Failed to fetch review-requested PR list", "err", err)
 		// Continue with partial data
 	}
 
@@ -179,6 +254,52 @@ func (m *Module) fetchStats(ctx context.Context) {
 	m.mu.Unlock()
 }
 
+// backOff sets when the next fetch may go, from the rate limit quota left
+// after a fetch that began at start, and notes whether a limit is
+// exhausted.
+func (m *Module) backOff(start time.Time) {
+	now := time.Now()
+	limits := m.client.RateLimits()
+	delay := pollDelay(limits, m.interval, now)
+	m.resumeAt = start.Add(delay)
+	if delay > m.interval {
+		m.Log().Debug("Backing off to save rate limit quota", "next", m.resumeAt.Format(time.TimeOnly))
+	}
+
+	var until time.Time
+	for _, l := range limits {
+		if l.Exhausted(now) && l.Reset.After(until) {
+			until = l.Reset
+		}
+	}
+	m.mu.Lock()
+	was := m.limitedUntil
+	m.limitedUntil = until
+	m.mu.Unlock()
+	switch {
+	case !until.IsZero() && !now.Before(was):
+		m.Log().Warn("GitHub rate limit exhausted, pausing fetches", "until", until.Format(time.TimeOnly))
+	case until.IsZero() && !was.IsZero():
+		m.Log().Info("GitHub rate limit available again")
+	}
+}
+
+// teams returns the configured teams whose review requests count as mine.
+func (m *Module) teams() []string {
+	if m.appCfg == nil {
+		return nil
+	}
+	return m.appCfg.GitHub.Teams
+}
+
+// rateLimited reports whether a rate limit is exhausted, so the keys show
+// it instead of silently going stale.
+func (m *Module) rateLimited() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Now().Before(m.limitedUntil)
+}
+
 // getStats returns the current PR stats.
 func (m *Module) getStats() PRStats {
 	m.mu.RLock()
@@ -215,14 +336,20 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 
 	keys := make(map[module.KeyID]image.Image)
 
-	// Key 0 (Key3): My PR stats overview (outbox)
-	if len(m.resources.Keys) > 0 {
-		keys[m.resources.Keys[0]] = m.renderPRStatsButton()
+	// My PR stats overview (outbox)
+	if k, ok := m.resources.Slot(slotStats); ok {
+		keys[k] = m.renderPRStatsButton()
+	}
+
+	// Review-requested PRs (inbox)
+	if k, ok := m.resources.Slot(slotInbox); ok {
+		keys[k] = m.renderReviewRequestedButton()
 	}
 
-	// Key 1 (Key4): Review-requested PRs (inbox)
-	if len(m.resources.Keys) > 1 {
-		keys[m.resources.Keys[1]] = m.renderReviewRequestedButton()
+	if m.rateLimited() {
+		for k, img := range keys {
+			keys[k] = withRateLimitBadge(img)
+		}
 	}
 
 	return keys
@@ -242,15 +369,16 @@ func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
 
 	// Determine which overlay to show based on which key was pressed
 	m.mu.Lock()
-	if len(m.resources.Keys) > 1 && id == m.resources.Keys[1] {
-		// Key4 pressed - show review-requested overlay
+	if k, ok := m.resources.Slot(slotInbox); ok && id == k {
+		// Inbox pressed - show review-requested overlay
 		m.overlayType = OverlayReviewRequested
 	} else {
-		// Key3 pressed - show my PRs overlay
+		// Stats pressed - show my PRs overlay
 		m.overlayType = OverlayMyPRs
 	}
-	m.overlayExpiry = time.Now().Add(5 * time.Second)
 	m.currentPage = 0 // Reset to first page
+	m.actionPR = nil
+	m.overlayPressed = make(map[module.KeyID]bool)
 	m.mu.Unlock()
 
 	return nil
@@ -262,7 +390,8 @@ func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
 }
 
 // HandleOverlayDial processes dial events when the overlay is active.
-// Dial4 (right knob) controls pagination: rotate to change page, click to dismiss overlay.
+// Dial4 (right knob) controls pagination: rotate to change page, click to
+// dismiss overlay. In the action menu, click goes back to the list.
 func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
 	// Only handle Dial4 (right knob)
 	if id != module.Dial4 {
@@ -272,8 +401,16 @@ func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) err
 	// Get the appropriate PR list based on overlay type
 	m.mu.RLock()
 	overlayType := m.overlayType
+	inMenu := m.actionPR != nil
 	m.mu.RUnlock()
 
+	if inMenu {
+		if event.Type == module.DialRelease {
+			m.closeActionMenu()
+		}
+		return nil
+	}
+
 	var prList []PRInfo
 	if overlayType == OverlayReviewRequested {
 		prList = m.getReviewPRList()
@@ -302,15 +439,11 @@ func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) err
 				m.currentPage = 0
 			}
 		}
-		// Reset the 5s timer on page change
-		m.overlayExpiry = time.Now().Add(5 * time.Second)
 		m.mu.Unlock()
 
 	case module.DialRelease:
 		// Click dismisses the overlay
-		m.mu.Lock()
-		m.overlayType = OverlayNone
-		m.mu.Unlock()
+		m.CloseOverlay()
 	}
 
 	return nil
@@ -321,18 +454,31 @@ func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
 	return nil
 }
 
-// HandleOverlayKey processes key events when the overlay is active.
+// HandleOverlayKey processes key events when the overlay is active. Keys act
+// on release: a short press opens the PR in the browser and a long press
+// opens its action menu.
 func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
-	// Only trigger on press (not release)
-	if !event.Pressed {
+	m.mu.Lock()
+	if event.Pressed {
+		m.overlayPressed[id] = true
+		m.mu.Unlock()
 		return nil
 	}
-
-	// Get the appropriate PR list based on overlay type
-	m.mu.RLock()
+	if !m.overlayPressed[id] {
+		// Release of the key that opened the overlay
+		m.mu.Unlock()
+		return nil
+	}
+	delete(m.overlayPressed, id)
+	actionPR := m.actionPR
 	overlayType := m.overlayType
 	currentPage := m.currentPage
-	m.mu.RUnlock()
+	m.mu.Unlock()
+
+	if actionPR != nil {
+		m.handleActionKey(id, *actionPR)
+		return nil
+	}
 
 	var prList []PRInfo
 	if overlayType == OverlayReviewRequested {
@@ -346,16 +492,111 @@ func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error
 	const itemsPerPage = 8
 	keyIndex := int(id) - 1 // Key1=1, so subtract 1 for 0-indexed
 	prIndex := currentPage*itemsPerPage + keyIndex
-	if prIndex >= 0 && prIndex < len(prList) {
-		pr := prList[prIndex]
-		if pr.URL != "" {
-			m.openURL(pr.URL)
-		}
+	if prIndex < 0 || prIndex >= len(prList) {
+		return nil
+	}
+	pr := prList[prIndex]
+
+	if event.Duration >= longPressDuration {
+		m.mu.Lock()
+		m.actionPR = &pr
+		m.mu.Unlock()
+		return nil
 	}
 
+	if pr.URL != "" {
+		m.openURL(pr.URL)
+	}
 	return nil
 }
 
+// handleActionKey runs the action menu entry on key id for pr.
+func (m *Module) handleActionKey(id module.KeyID, pr PRInfo) {
+	switch id {
+	case actionKeyApprove:
+		m.runAction("Approve", pr, func(ctx context.Context) (string, error) {
+			return fmt.Sprintf("Approved #%d", pr.Number), m.client.ApprovePR(ctx, pr.Repo, pr.Number)
+		})
+
+	case actionKeyMerge:
+		if pr.CI == CIStatusPassed {
+			m.runAction("Merge", pr, func(ctx context.Context) (string, error) {
+				return fmt.Sprintf("Merged #%d", pr.Number), m.client.MergePR(ctx, pr.Repo, pr.Number)
+			})
+		} else {
+			m.runAction("Auto-merge", pr, func(ctx context.Context) (string, error) {
+				return fmt.Sprintf("#%d merges when green", pr.Number), m.client.EnableAutoMerge(ctx, pr.NodeID)
+			})
+		}
+
+	case actionKeyRerequest:
+		m.runAction("Re-request", pr, func(ctx context.Context) (string, error) {
+			n, err := m.client.RerequestReview(ctx, pr.Repo, pr.Number)
+			if err == nil && n == 0 {
+				return "", fmt.Errorf("no previous reviewers")
+			}
+			return fmt.Sprintf("Re-requested %d review(s)", n), err
+		})
+
+	case actionKeyCopy:
+		if pr.Branch == "" {
+			m.Notify(module.Notification{Text: "Branch unknown", Color: colorRed})
+			break
+		}
+		m.CloseOverlay()
+		if err := action.SetClipboard(pr.Branch); err != nil {
+			m.Log().Warn("Failed to copy branch", "branch", pr.Branch, "err", err)
+			m.Notify(module.Notification{Text: "Copy failed", Color: colorRed})
+			break
+		}
+		if m.Redaction() == module.RedactNone {
+			m.Notify(module.Notification{Text: "Copied " + pr.Branch})
+		} else {
+			m.Notify(module.Notification{Text: "Copied branch"})
+		}
+
+	case actionKeyBack:
+		m.closeActionMenu()
+	}
+}
+
+// runAction closes the overlay and runs an API action in the background,
+// reporting its result as a notification and refreshing the PR lists once
+// it succeeds. fn returns the success message.
+func (m *Module) runAction(name string, pr PRInfo, fn func(ctx context.Context) (string, error)) {
+	m.CloseOverlay()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(m.ctx, actionTimeout)
+		defer cancel()
+
+		msg, err := fn(ctx)
+		if err != nil {
+			m.Log().Warn("PR action failed", "action", name, "repo", pr.Repo, "number", pr.Number, "err", err)
+			m.Notify(module.Notification{Text: name + " failed: " + err.Error(), Color: colorRed})
+			return
+		}
+		m.Log().Info("PR action succeeded", "action", name, "repo", pr.Repo, "number", pr.Number)
+		m.Notify(module.Notification{Text: msg, Color: colorGreen})
+		m.fetchStats(m.ctx)
+	}()
+}
+
+// closeActionMenu returns from the action menu to the PR list.
+func (m *Module) closeActionMenu() {
+	m.mu.Lock()
+	m.actionPR = nil
+	m.mu.Unlock()
+}
+
+// CloseOverlay dismisses the overlay, including any open action menu.
+func (m *Module) CloseOverlay() {
+	m.mu.Lock()
+	m.overlayType = OverlayNone
+	m.actionPR = nil
+	m.mu.Unlock()
+}
+
 // HandleOverlayStripTouch processes touch strip events when the overlay is active.
 func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
 	// Strip now shows repo summary (left) and pagination affordance (right)
@@ -367,7 +608,7 @@ func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
 // openURL opens a URL in the default browser.
 func (m *Module) openURL(url string) {
 	if err := exec.Command("open", url).Start(); err != nil {
-		log.Printf("Failed to open URL %s: %v", url, err)
+		m.Log().Warn("Failed to open URL", "url", url, "err", err)
 	}
 }
 
@@ -375,23 +616,18 @@ func (m *Module) openURL(url string) {
 func (m *Module) IsOverlayActive() bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	return m.overlayType != OverlayNone
+}
 
-	if m.overlayType == OverlayNone {
-		return false
-	}
-
-	// Check if overlay has expired
-	if time.Now().After(m.overlayExpiry) {
-		// Need to acquire write lock to update
-		m.mu.RUnlock()
-		m.mu.Lock()
-		m.overlayType = OverlayNone
-		m.mu.Unlock()
-		m.mu.RLock()
-		return false
+// OverlayTimeout returns how long the overlay stays up without input, which
+// is longer in the action menu.
+func (m *Module) OverlayTimeout() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.actionPR != nil {
+		return actionMenuTimeout
 	}
-
-	return true
+	return overlayTimeout
 }
 
 // RenderOverlayKeys returns images for all 8 keys showing PR list with pagination.
@@ -402,8 +638,13 @@ func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
 	m.mu.RLock()
 	overlayType := m.overlayType
 	currentPage := m.currentPage
+	actionPR := m.actionPR
 	m.mu.RUnlock()
 
+	if actionPR != nil {
+		return m.renderActionKeys(*actionPR)
+	}
+
 	var prList []PRInfo
 	if overlayType == OverlayReviewRequested {
 		prList = m.getReviewPRList()
@@ -437,8 +678,13 @@ func (m *Module) RenderOverlayStrip() image.Image {
 	m.mu.RLock()
 	overlayType := m.overlayType
 	currentPage := m.currentPage
+	actionPR := m.actionPR
 	m.mu.RUnlock()
 
+	if actionPR != nil {
+		return m.renderActionStrip(*actionPR)
+	}
+
 	var prList []PRInfo
 	if overlayType == OverlayReviewRequested {
 		prList = m.getReviewPRList()
@@ -0,0 +1,150 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Quota left, as a fraction of the limit, below which polling slows: to
+// twice the interval under half, four times under a quarter, and until the
+// reset under a tenth.
+const (
+	quotaLow      = 0.5
+	quotaVeryLow  = 0.25
+	quotaReserved = 0.1
+)
+
+// RateLimit is one of GitHub's API rate limits as of the latest response
+// counted against it.
+type RateLimit struct {
+	Resource  string // core, search, graphql, ...
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Exhausted reports whether the limit has no requests left at now.
+func (l RateLimit) Exhausted(now time.Time) bool {
+	return l.Remaining == 0 && now.Before(l.Reset)
+}
+
+// RateLimitError is returned for a request refused, or not sent, because
+// its rate limit is exhausted.
+type RateLimitError struct {
+	Resource string
+	Reset    time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub %s rate limit exhausted until %s", e.Resource, e.Reset.Format(time.Kitchen))
+}
+
+// rateLimits tracks the rate limits reported in response headers.
+type rateLimits struct {
+	mu     sync.Mutex
+	limits map[string]RateLimit
+}
+
+// check returns a RateLimitError if endpoint's limit is known to be
+// exhausted, so the request isn't sent only to be refused.
+func (r *rateLimits) check(endpoint string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.limits[resourceFor(endpoint)]
+	if ok && l.Exhausted(time.Now()) {
+		return &RateLimitError{Resource: l.Resource, Reset: l.Reset}
+	}
+	return nil
+}
+
+// note records the limit reported by resp, a response from endpoint,
+// returning a RateLimitError if resp refused the request over it.
+func (r *rateLimits) note(endpoint string, resp *http.Response) error {
+	h := resp.Header
+	resource := h.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = resourceFor(endpoint)
+	}
+	limit, err1 := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	remaining, err2 := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, err3 := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err1 == nil && err2 == nil && err3 == nil {
+		r.mu.Lock()
+		if r.limits == nil {
+			r.limits = make(map[string]RateLimit)
+		}
+		r.limits[resource] = RateLimit{Resource: resource, Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+		r.mu.Unlock()
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	switch {
+	case err2 == nil && err3 == nil && remaining == 0:
+		return &RateLimitError{Resource: resource, Reset: time.Unix(reset, 0)}
+	case h.Get("Retry-After") != "":
+		// A secondary limit, on bursts rather than totals, which holds off
+		// the resource like an exhausted one until it's over
+		secs, _ := strconv.Atoi(h.Get("Retry-After"))
+		reset := time.Now().Add(time.Duration(secs) * time.Second)
+		r.mu.Lock()
+		l := r.limits[resource]
+		l.Resource, l.Remaining, l.Reset = resource, 0, reset
+		l.Limit = max(l.Limit, 1)
+		if r.limits == nil {
+			r.limits = make(map[string]RateLimit)
+		}
+		r.limits[resource] = l
+		r.mu.Unlock()
+		return &RateLimitError{Resource: resource, Reset: reset}
+	}
+	return nil
+}
+
+// all returns the limits seen so far.
+func (r *rateLimits) all() []RateLimit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RateLimit, 0, len(r.limits))
+	for _, l := range r.limits {
+		out = append(out, l)
+	}
+	return out
+}
+
+// resourceFor returns the rate limit a request to endpoint counts against.
+func resourceFor(endpoint string) string {
+	switch {
+	case strings.Contains(endpoint, "/search/"):
+		return "search"
+	case strings.HasSuffix(endpoint, "/graphql"):
+		return "graphql"
+	}
+	return "core"
+}
+
+// pollDelay returns how long after a fetch the next should wait given
+// limits: interval while quota is plentiful, longer as any limit's
+// remaining quota shrinks, and until its reset once it's nearly gone.
+func pollDelay(limits []RateLimit, interval time.Duration, now time.Time) time.Duration {
+	delay := interval
+	for _, l := range limits {
+		if l.Limit <= 0 || !now.Before(l.Reset) {
+			continue
+		}
+		switch left := float64(l.Remaining) / float64(l.Limit); {
+		case left < quotaReserved:
+			delay = max(delay, l.Reset.Sub(now))
+		case left < quotaVeryLow:
+			delay = max(delay, 4*interval)
+		case left < quotaLow:
+			delay = max(delay, 2*interval)
+		}
+	}
+	return delay
+}
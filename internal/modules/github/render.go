@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"log"
 	"strings"
+	"time"
 
-	"github.com/srwiley/oksvg"
-	"github.com/srwiley/rasterx"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
@@ -19,15 +19,6 @@ import (
 //go:embed fonts/PublicSans-Bold.ttf
 var fontBold []byte
 
-//go:embed icons/github.svg
-var iconGitHubSVG string
-
-//go:embed icons/send.svg
-var iconSendSVG string
-
-//go:embed icons/inbox.svg
-var iconInboxSVG string
-
 // Common colors
 var (
 	colorKeyBg   = color.RGBA{40, 40, 40, 255}
@@ -37,6 +28,7 @@ var (
 	colorOrange  = color.RGBA{219, 109, 40, 255} // GitHub orange
 	colorRed     = color.RGBA{248, 81, 73, 255}  // GitHub red for CI failures
 	colorDimGray = color.RGBA{110, 110, 110, 255}
+	colorBlue    = color.RGBA{88, 166, 255, 255} // GitHub blue for team requests
 )
 
 const keySize = 72
@@ -123,7 +115,7 @@ func (m *Module) renderPRStatsButton() image.Image {
 		rowY = 28
 	} else {
 		// Draw send icon (outbox) at top
-		iconImg := renderSVGIcon(iconSendSVG, 20, colorWhite)
+		iconImg := render.Icon("send", 20, colorWhite)
 		iconX := (keySize - 20) / 2
 		draw.Draw(img, image.Rect(iconX, 4, iconX+20, 24), iconImg, image.Point{}, draw.Over)
 		rowY = 28
@@ -154,20 +146,81 @@ func (m *Module) renderReviewRequestedButton() image.Image {
 	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
 
 	// Draw inbox icon at top
-	iconImg := renderSVGIcon(iconInboxSVG, 24, colorWhite)
+	iconImg := render.Icon("inbox", 24, colorWhite)
 	iconX := (keySize - 24) / 2
 	draw.Draw(img, image.Rect(iconX, 8, iconX+24, 32), iconImg, image.Point{}, draw.Over)
 
-	// Draw "Review" label
-	m.drawTextCentered(img, "Review", keySize/2, 48, m.labelFace, colorDimGray)
+	// Draw "Review" label, with how long the oldest request has waited,
+	// and the count colored by it
+	label, countColor := "Review", color.Color(colorYellow)
+	oldest, sole := oldestReview(m.getReviewPRList())
+	if !oldest.IsZero() {
+		now := time.Now()
+		label += " " + render.FormatAge(now.Sub(oldest))
+		countColor = reviewAgeColor(oldest, now)
+	}
+	m.drawTextCentered(img, label, keySize/2, 48, m.labelFace, colorDimGray)
 
 	// Draw count
 	countStr := fmt.Sprintf("%d", stats.Total)
-	m.drawTextCentered(img, countStr, keySize/2, 64, m.numberFace, colorYellow)
+	m.drawTextCentered(img, countStr, keySize/2, 64, m.numberFace, countColor)
+
+	// Badge the icon with how many only I was asked to review, and how
+	// many were asked of my teams
+	if sole > 0 {
+		m.drawCountBadge(img, iconX+24, 30, sole, colorRed)
+	}
+	if stats.Team > 0 {
+		m.drawCountBadge(img, iconX, 30, stats.Team, colorBlue)
+	}
 
 	return img
 }
 
+// drawCountBadge draws n in white on a disc of col centred on x, y.
+func (m *Module) drawCountBadge(img *image.RGBA, x, y, n int, col color.Color) {
+	const r = 7
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			switch d := dx*dx + dy*dy; {
+			case d <= (r-1)*(r-1):
+				img.Set(x+dx, y+dy, col)
+			case d <= r*r:
+				img.Set(x+dx, y+dy, colorKeyBg)
+			}
+		}
+	}
+	text := fmt.Sprint(n)
+	if n > 9 {
+		text = "+"
+	}
+	m.drawTextCentered(img, text, x, y+3, m.labelFace, colorWhite)
+}
+
+// withRateLimitBadge returns img, a key, with an hourglass on an orange
+// disc in its top left corner, showing fetches are paused until the rate
+// limit resets.
+func withRateLimitBadge(img image.Image) image.Image {
+	b := img.Bounds()
+	badged := image.NewRGBA(b)
+	draw.Draw(badged, b, img, b.Min, draw.Src)
+	const r = 9
+	cx, cy := b.Min.X+r+1, b.Min.Y+r+1
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			switch d := x*x + y*y; {
+			case d <= (r-2)*(r-2):
+				badged.Set(cx+x, cy+y, colorOrange)
+			case d <= r*r:
+				badged.Set(cx+x, cy+y, color.Black)
+			}
+		}
+	}
+	icon := render.Icon("hourglass", 10, colorWhite)
+	draw.Draw(badged, image.Rect(cx-5, cy-5, cx+5, cy+5), icon, icon.Bounds().Min, draw.Over)
+	return badged
+}
+
 // drawStatRow draws a stat row with label and count.
 func (m *Module) drawStatRow(img *image.RGBA, y int, label string, count int, col color.Color) {
 	// Draw colored indicator dot
@@ -213,28 +266,6 @@ func (m *Module) drawTextRight(img *image.RGBA, text string, rightX, y int, face
 	d.DrawString(text)
 }
 
-// renderSVGIcon renders an SVG string to an image with the given size and color.
-func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
-	r, g, b, _ := iconColor.RGBA()
-	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
-	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
-
-	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
-	if err != nil {
-		log.Printf("Failed to parse SVG: %v", err)
-		return image.NewRGBA(image.Rect(0, 0, size, size))
-	}
-
-	img := image.NewRGBA(image.Rect(0, 0, size, size))
-	icon.SetTarget(0, 0, float64(size), float64(size))
-
-	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
-	raster := rasterx.NewDasher(size, size, scanner)
-	icon.Draw(raster, 1.0)
-
-	return img
-}
-
 // renderPRKey renders a single PR on a key.
 func (m *Module) renderPRKey(pr PRInfo) image.Image {
 	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
@@ -287,19 +318,39 @@ func (m *Module) renderPRKey(pr PRInfo) image.Image {
 		m.drawText(img, "+", 40, 16, m.labelFace, colorGreen)
 	}
 
+	// For a PR awaiting my review, how long it's waited, and whether
+	// anyone else was asked
+	if !pr.Requested.IsZero() {
+		now := time.Now()
+		m.drawTextRight(img, render.FormatAge(now.Sub(pr.Requested)), keySize-4, 16, m.labelFace, reviewAgeColor(pr.Requested, now))
+	}
+	maxRepo := 10
+	switch {
+	case pr.SoleReviewer:
+		m.drawTextRight(img, "solo", keySize-4, 28, m.labelFace, colorWhite)
+		maxRepo = 7
+	case pr.Team != "":
+		m.drawTextRight(img, "team", keySize-4, 28, m.labelFace, colorBlue)
+		maxRepo = 7
+	}
+
 	// Draw repo name (truncated)
 	repo := pr.Repo
 	// Get just the repo part (after /)
 	if idx := strings.LastIndex(repo, "/"); idx != -1 {
 		repo = repo[idx+1:]
 	}
-	if len(repo) > 10 {
-		repo = repo[:9] + "."
+	if len(repo) > maxRepo {
+		repo = repo[:maxRepo-1] + "."
 	}
 	m.drawText(img, repo, 4, 28, m.labelFace, colorDimGray)
 
 	// Draw title (wrapped across multiple lines)
 	title := pr.Title
+	redact := m.Redaction()
+	if redact == module.RedactGeneric {
+		title = "Pull request"
+	}
 	lines := wrapText(title, 11) // ~11 chars per line at this font size
 	y := 42
 	for i, line := range lines {
@@ -309,6 +360,9 @@ func (m *Module) renderPRKey(pr PRInfo) image.Image {
 		m.drawText(img, line, 4, y, m.overlayFace, colorWhite)
 		y += 11
 	}
+	if redact == module.RedactBlur {
+		render.Blur(img, image.Rect(0, 32, keySize, keySize))
+	}
 
 	return img
 }
@@ -546,10 +600,18 @@ func (m *Module) drawStripPR(img *image.RGBA, pr PRInfo, x int) {
 
 	// Draw title (18px, truncated)
 	title := pr.Title
+	redact := m.Redaction()
+	if redact == module.RedactGeneric {
+		title = "Pull request"
+	}
 	if len(title) > 18 {
 		title = title[:17] + "..."
 	}
 	m.drawText(img, title, x+16, 60, m.stripTitleFace, colorWhite)
+	if redact == module.RedactBlur {
+		w := font.MeasureString(m.stripTitleFace, title).Ceil()
+		render.Blur(img, image.Rect(x+16, 44, x+16+w, 66))
+	}
 }
 
 // drawTextCentered draws text horizontally centered at the given position.
@@ -602,3 +664,59 @@ func wrapText(text string, maxChars int) []string {
 
 	return lines
 }
+
+// renderActionKeys renders the action menu for a PR.
+func (m *Module) renderActionKeys(pr PRInfo) map[module.KeyID]image.Image {
+	merge := "Merge"
+	if pr.CI != CIStatusPassed {
+		merge = "Auto-merge"
+	}
+
+	keys := map[module.KeyID]image.Image{
+		actionKeyApprove:   m.renderActionKey("Approve", colorGreen),
+		actionKeyMerge:     m.renderActionKey(merge, colorGreen),
+		actionKeyRerequest: m.renderActionKey("Re-request", colorYellow),
+		actionKeyCopy:      m.renderActionKey("Copy branch", colorDimGray),
+		actionKeyBack:      m.renderActionKey("Back", colorDimGray),
+	}
+	for id := module.Key1; id <= module.Key8; id++ {
+		if _, ok := keys[id]; !ok {
+			keys[id] = m.renderEmptyKey()
+		}
+	}
+	return keys
+}
+
+// renderActionKey renders one action menu entry with an accent bar on top.
+func (m *Module) renderActionKey(label string, accent color.Color) image.Image {
+	img := m.renderEmptyKey().(*image.RGBA)
+	draw.Draw(img, image.Rect(0, 0, keySize, 4), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	lines := wrapText(label, 10)
+	y := keySize/2 + 5 - (len(lines)-1)*8
+	for _, line := range lines {
+		m.drawTextCentered(img, line, keySize/2, y, m.labelFace, colorWhite)
+		y += 16
+	}
+	return img
+}
+
+// renderActionStrip renders the touch strip for a PR's action menu: the PR
+// on the left and how to get back on the right.
+func (m *Module) renderActionStrip(pr PRInfo) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 100))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{30, 30, 30, 255}}, image.Point{}, draw.Src)
+
+	m.drawStripPR(img, pr, 0)
+	if redact := m.Redaction(); pr.Branch != "" && redact != module.RedactGeneric {
+		branch := render.TruncateText(pr.Branch, m.stripLabelFace, 560)
+		m.drawText(img, branch, 16, 85, m.stripLabelFace, colorDimGray)
+		if redact == module.RedactBlur {
+			w := font.MeasureString(m.stripLabelFace, branch).Ceil()
+			render.Blur(img, image.Rect(16, 72, 16+w, 90))
+		}
+	}
+
+	m.drawTextCentered(img, "click=back", 700, 55, m.stripLabelFace, colorDimGray)
+	return img
+}
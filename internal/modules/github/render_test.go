@@ -0,0 +1,58 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/render/rendertest"
+)
+
+// fixturePRs are a fixed PR list covering each review and CI status.
+var fixturePRs = []PRInfo{
+	{Title: "Add dial acceleration", Repo: "phinze/belowdeck", Number: 142, Status: PRStatusApproved, CI: CIStatusPassed},
+	{Title: "Fix flaky reconnect after sleep", Repo: "phinze/belowdeck", Number: 139, Status: PRStatusWaiting, CI: CIStatusPending},
+	{Title: "Bump dependencies", Repo: "phinze/dotfiles", Number: 58, Status: PRStatusChanges, CI: CIStatusFailed},
+	{Title: "WIP: new theme", Repo: "phinze/homelab-infrastructure", Number: 7, Status: PRStatusWaiting, IsDraft: true},
+}
+
+func newTestModule(t *testing.T) *Module {
+	t.Helper()
+	m := &Module{
+		stats:       PRStats{WaitingForReview: 2, Approved: 1, ChangesRequested: 1, CIFailed: 1, Draft: 1},
+		reviewStats: ReviewStats{Total: 3},
+	}
+	if err := m.initFonts(); err != nil {
+		t.Fatalf("initFonts: %v", err)
+	}
+	return m
+}
+
+func TestRenderButtonsGolden(t *testing.T) {
+	m := newTestModule(t)
+	rendertest.Golden(t, "stats_button", m.renderPRStatsButton())
+	rendertest.Golden(t, "review_button", m.renderReviewRequestedButton())
+
+	m.stats = PRStats{WaitingForReview: 3, Approved: 2}
+	rendertest.Golden(t, "stats_button_clean", m.renderPRStatsButton())
+}
+
+func TestRenderPRKeysGolden(t *testing.T) {
+	m := newTestModule(t)
+	for _, tc := range []struct {
+		name string
+		pr   PRInfo
+	}{
+		{"pr_approved", fixturePRs[0]},
+		{"pr_waiting", fixturePRs[1]},
+		{"pr_ci_failed", fixturePRs[2]},
+		{"pr_draft", fixturePRs[3]},
+	} {
+		rendertest.Golden(t, tc.name, m.renderPRKey(tc.pr))
+	}
+}
+
+func TestRenderOverlayStripGolden(t *testing.T) {
+	m := newTestModule(t)
+	rendertest.Golden(t, "overlay_strip", m.renderOverlayStripWithPRs(fixturePRs, 0))
+	rendertest.Golden(t, "overlay_strip_empty", m.renderOverlayStripWithPRs(nil, 0))
+	rendertest.Golden(t, "action_strip", m.renderActionStrip(fixturePRs[1]))
+}
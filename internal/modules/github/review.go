@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// How long a review can wait before it's shown as getting old, then as
+// overdue.
+const (
+	reviewAging   = 24 * time.Hour
+	reviewOverdue = 3 * 24 * time.Hour
+)
+
+// fetchReviewRequests sets when each of prs, awaiting username's review,
+// had it requested, and whether username is the only reviewer requested.
+func (c *Client) fetchReviewRequests(ctx context.Context, prs []PRInfo, username string) {
+	if len(prs) == 0 {
+		return
+	}
+
+	type requestResult struct {
+		index int
+		at    time.Time
+	}
+	results := make(chan requestResult, len(prs))
+
+	for i, pr := range prs {
+		go func(idx int, pr PRInfo) {
+			results <- requestResult{idx, c.getReviewRequested(ctx, pr.Repo, pr.Number, username)}
+		}(i, pr)
+	}
+
+	for range len(prs) {
+		r := <-results
+		pr := &prs[r.index]
+		pr.Requested = r.at
+		if pr.Requested.IsZero() {
+			pr.Requested = pr.Created
+		}
+		pr.SoleReviewer = len(pr.RequestedTeams) == 0 && len(pr.RequestedReviewers) == 1 &&
+			strings.EqualFold(pr.RequestedReviewers[0], username)
+	}
+}
+
+// getReviewRequested returns when username's review of a PR was last
+// requested, or when a team's was if it wasn't requested of them by name.
+// It returns the zero time if neither is found.
+func (c *Client) getReviewRequested(ctx context.Context, repo string, number int, username string) time.Time {
+	path := fmt.Sprintf("/repos/%s/issues/%d/events?per_page=100", repo, number)
+
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return time.Time{}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}
+	}
+
+	var events []struct {
+		Event             string    `json:"event"`
+		CreatedAt         time.Time `json:"created_at"`
+		RequestedReviewer *struct {
+			Login string `json:"login"`
+		} `json:"requested_reviewer"`
+		RequestedTeam *struct {
+			Slug string `json:"slug"`
+		} `json:"requested_team"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return time.Time{}
+	}
+
+	var mine, team time.Time
+	for _, e := range events {
+		switch {
+		case e.Event != "review_requested":
+		case e.RequestedReviewer != nil && strings.EqualFold(e.RequestedReviewer.Login, username):
+			mine = e.CreatedAt
+		case e.RequestedTeam != nil:
+			team = e.CreatedAt
+		}
+	}
+	if !mine.IsZero() {
+		return mine
+	}
+	return team
+}
+
+// sortByUrgency sorts PRs awaiting review with those only I was asked to
+// review first, since nobody else will, then those asked of me rather than
+// a team, then by how long they've waited, longest first.
+func sortByUrgency(prs []PRInfo) {
+	sort.SliceStable(prs, func(i, j int) bool {
+		if prs[i].SoleReviewer != prs[j].SoleReviewer {
+			return prs[i].SoleReviewer
+		}
+		if (prs[i].Team == "") != (prs[j].Team == "") {
+			return prs[i].Team == ""
+		}
+		return prs[i].Requested.Before(prs[j].Requested)
+	})
+}
+
+// reviewAgeColor returns the color for a review requested at requested:
+// yellow while it's fresh, orange once it's aging, red once it's overdue.
+func reviewAgeColor(requested, now time.Time) color.Color {
+	switch age := now.Sub(requested); {
+	case age >= reviewOverdue:
+		return colorRed
+	case age >= reviewAging:
+		return colorOrange
+	}
+	return colorYellow
+}
+
+// oldestReview returns the longest-waiting of prs that are awaiting my
+// review, and how many of them only I was asked to review.
+func oldestReview(prs []PRInfo) (oldest time.Time, sole int) {
+	for _, pr := range prs {
+		if pr.Requested.IsZero() {
+			continue
+		}
+		if oldest.IsZero() || pr.Requested.Before(oldest) {
+			oldest = pr.Requested
+		}
+		if pr.SoleReviewer {
+			sole++
+		}
+	}
+	return oldest, sole
+}
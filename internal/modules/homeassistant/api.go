@@ -5,9 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"image"
+	_ "image/jpeg" // camera snapshots
+	_ "image/png"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/phinze/belowdeck/internal/httpx"
 )
 
 // LightState represents the state of a light entity.
@@ -29,14 +34,34 @@ func NewClient(baseURL, token string) *Client {
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
 	return &Client{
-		baseURL: baseURL,
-		token:   token,
-		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: httpx.New("homeassistant", 5*time.Second),
 	}
 }
 
+// Ping checks that the server is reachable and accepts the token.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	return nil
+}
+
 // CallService calls a Home Assistant service.
 func (c *Client) CallService(ctx context.Context, domain, service string, data map[string]any) error {
 	url := fmt.Sprintf("%s/api/services/%s/%s", c.baseURL, domain, service)
@@ -73,44 +98,109 @@ func (c *Client) CallService(ctx context.Context, domain, service string, data m
 
 // GetLightState fetches the current state of a light entity.
 func (c *Client) GetLightState(ctx context.Context, entityID string) (LightState, error) {
+	state, err := c.GetState(ctx, entityID)
+	if err != nil {
+		return LightState{}, err
+	}
+	return state.Light(), nil
+}
+
+// EntityState is the generic state of any Home Assistant entity.
+type EntityState struct {
+	State      string
+	Attributes map[string]any
+}
+
+// Domain returns the entity domain (e.g. "climate") from an entity ID.
+func Domain(entityID string) string {
+	domain, _, _ := strings.Cut(entityID, ".")
+	return domain
+}
+
+// GetState fetches the raw state and attributes of any entity.
+func (c *Client) GetState(ctx context.Context, entityID string) (EntityState, error) {
 	url := fmt.Sprintf("%s/api/states/%s", c.baseURL, entityID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return LightState{}, fmt.Errorf("failed to create request: %w", err)
+		return EntityState{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return LightState{}, fmt.Errorf("request failed: %w", err)
+		return EntityState{}, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return LightState{}, fmt.Errorf("API error: %s", resp.Status)
+		return EntityState{}, fmt.Errorf("API error: %s", resp.Status)
 	}
 
 	var data struct {
-		State      string `json:"state"`
-		Attributes struct {
-			Brightness *int `json:"brightness"`
-		} `json:"attributes"`
+		State      string         `json:"state"`
+		Attributes map[string]any `json:"attributes"`
 	}
-
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return LightState{}, fmt.Errorf("failed to decode response: %w", err)
+		return EntityState{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return EntityState{State: data.State, Attributes: data.Attributes}, nil
+}
+
+// GetCameraSnapshot fetches a camera entity's current image.
+func (c *Client) GetCameraSnapshot(ctx context.Context, entityID string) (image.Image, error) {
+	url := fmt.Sprintf("%s/api/camera_proxy/%s", c.baseURL, entityID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	state := LightState{
-		On: data.State == "on",
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API error: %s", resp.Status)
 	}
 
-	if data.Attributes.Brightness != nil {
-		b := uint8(*data.Attributes.Brightness)
-		state.Brightness = &b
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
 	}
+	return img, nil
+}
+
+// Float returns a numeric attribute, or false if it's missing or not a number.
+func (s EntityState) Float(name string) (float64, bool) {
+	v, ok := s.Attributes[name].(float64)
+	return v, ok
+}
+
+// String returns a string attribute, or "" if it's missing.
+func (s EntityState) String(name string) string {
+	v, _ := s.Attributes[name].(string)
+	return v
+}
+
+// Light returns the state as a light's on/off state and brightness.
+func (s EntityState) Light() LightState {
+	state := LightState{On: s.State == "on"}
+	if b, ok := s.Float("brightness"); ok {
+		v := uint8(b)
+		state.Brightness = &v
+	}
+	return state
+}
 
-	return state, nil
+// Bool returns a boolean attribute, or false if it's missing.
+func (s EntityState) Bool(name string) bool {
+	v, _ := s.Attributes[name].(bool)
+	return v
 }
@@ -0,0 +1,182 @@
+package homeassistant
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// Color dial behavior. A light's dial adjusts its color while held.
+const (
+	kelvinStep    = 100 // color temperature per dial tick
+	hueStep       = 10  // degrees of hue per dial tick
+	colorBarShown = 1500 * time.Millisecond
+)
+
+// Color temperature range assumed when a light doesn't report its own.
+const (
+	defaultMinKelvin = 2000
+	defaultMaxKelvin = 6500
+)
+
+// colorModes are the color modes in which a light shows a hue rather than
+// a shade of white.
+var colorModes = []string{"hs", "xy", "rgb", "rgbw", "rgbww"}
+
+// lightColor is what a light's attributes say about its color.
+type lightColor struct {
+	temp    bool // supports color temperature
+	hue     bool // supports colors
+	inColor bool // showing a color rather than a white
+
+	kelvin, minKelvin, maxKelvin float64
+	h, s                         float64 // hue in degrees, saturation in percent
+}
+
+// colorOf reads a light's color support and current color from its state.
+func colorOf(state EntityState) lightColor {
+	c := lightColor{minKelvin: defaultMinKelvin, maxKelvin: defaultMaxKelvin}
+	modes, _ := state.Attributes["supported_color_modes"].([]any)
+	for _, mode := range modes {
+		switch mode {
+		case "color_temp":
+			c.temp = true
+		case "hs", "xy", "rgb", "rgbw", "rgbww":
+			c.hue = true
+		}
+	}
+	c.inColor = slices.Contains(colorModes, state.String("color_mode"))
+
+	if v, ok := state.Float("min_color_temp_kelvin"); ok {
+		c.minKelvin = v
+	}
+	if v, ok := state.Float("max_color_temp_kelvin"); ok {
+		c.maxKelvin = v
+	}
+	c.kelvin = (c.minKelvin + c.maxKelvin) / 2
+	if v, ok := state.Float("color_temp_kelvin"); ok {
+		c.kelvin = v
+	}
+	if hs, ok := state.Attributes["hs_color"].([]any); ok && len(hs) == 2 {
+		c.h, _ = hs[0].(float64)
+		c.s, _ = hs[1].(float64)
+	}
+	return c
+}
+
+// adjustsHue reports whether turning the color dial changes the light's
+// hue rather than its color temperature.
+func (c lightColor) adjustsHue() bool {
+	return c.hue && (c.inColor || !c.temp)
+}
+
+// colorDial is the press state of a dial bound to a light.
+type colorDial struct {
+	held   bool
+	turned bool // turned since it was pressed
+}
+
+// pressColorDial notes a press or release of a light's dial. A release
+// without turning switches the light between white and color.
+func (m *Module) pressColorDial(id module.DialID, entityID string, event module.DialEvent) {
+	m.mu.Lock()
+	d := m.colorDials[id]
+	m.colorDials[id] = colorDial{held: event.Type == module.DialPress}
+	m.mu.Unlock()
+
+	if event.Type == module.DialRelease && d.held && !d.turned {
+		m.toggleLightColor(entityID)
+	}
+}
+
+// turnColorDial adjusts a light's color if its dial is held, reporting
+// whether it did.
+func (m *Module) turnColorDial(id module.DialID, entityID string, delta int8) bool {
+	m.mu.Lock()
+	d := m.colorDials[id]
+	if d.held {
+		d.turned = true
+		m.colorDials[id] = d
+	}
+	m.mu.Unlock()
+
+	if !d.held {
+		return false
+	}
+	m.adjustLightColor(entityID, delta)
+	return true
+}
+
+// adjustLightColor steps a light's hue, if it's showing a color, or its
+// color temperature, and shows where it is on the strip.
+func (m *Module) adjustLightColor(entityID string, delta int8) {
+	state := m.getEntityState(entityID)
+	c := colorOf(state)
+
+	switch {
+	case c.adjustsHue():
+		h := math.Mod(c.h+float64(delta)*hueStep+360, 360)
+		s := c.s
+		if s == 0 {
+			s = 100
+		}
+		m.setLightColor(entityID, "hs", "hs_color", []any{h, s})
+		m.showColorBar(state, entityID, colorBarHue, h/360)
+	case c.temp:
+		k := math.Max(c.minKelvin, math.Min(c.maxKelvin, c.kelvin+float64(delta)*kelvinStep))
+		if k == c.kelvin {
+			return
+		}
+		m.setLightColor(entityID, "color_temp", "color_temp_kelvin", k)
+		m.showColorBar(state, entityID, colorBarTemp, (k-c.minKelvin)/(c.maxKelvin-c.minKelvin))
+	}
+}
+
+// toggleLightColor switches a light that can show both between its last
+// white and its last color.
+func (m *Module) toggleLightColor(entityID string) {
+	state := m.getEntityState(entityID)
+	c := colorOf(state)
+	if !c.temp || !c.hue {
+		return
+	}
+
+	if c.inColor {
+		m.setLightColor(entityID, "color_temp", "color_temp_kelvin", c.kelvin)
+		m.showColorBar(state, entityID, colorBarTemp, (c.kelvin-c.minKelvin)/(c.maxKelvin-c.minKelvin))
+		return
+	}
+	s := c.s
+	if s == 0 {
+		s = 100
+	}
+	m.setLightColor(entityID, "hs", "hs_color", []any{c.h, s})
+	m.showColorBar(state, entityID, colorBarHue, c.h/360)
+}
+
+// setLightColor turns a light on in a color mode, caching the new color so
+// rapid ticks chain off it.
+func (m *Module) setLightColor(entityID, mode, attr string, value any) {
+	m.setEntityAttribute(entityID, "color_mode", mode)
+	m.setEntityAttribute(entityID, attr, value)
+	m.Log().Info("Light color", "entity", entityID, attr, value)
+	m.callService("light", "turn_on", map[string]any{
+		"entity_id": entityID,
+		attr:        value,
+	})
+}
+
+// showColorBar shows the light's new color on the strip for a moment.
+func (m *Module) showColorBar(state EntityState, entityID string, kind colorBarKind, pos float64) {
+	if !m.resources.Device.HasStrip() {
+		return
+	}
+	rect := m.resources.Device.StripRect
+	m.Notify(module.Notification{
+		Target:   module.NotifyStrip,
+		Duration: colorBarShown,
+		Strip:    m.renderColorBar(rect, entityName(entityID, state), kind, colorOf(state), pos),
+	})
+}
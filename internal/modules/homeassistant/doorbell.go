@@ -0,0 +1,175 @@
+package homeassistant
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+)
+
+// defaultSnapshotShown is how long a doorbell's snapshot shows unless
+// configured otherwise.
+const defaultSnapshotShown = 10 * time.Second
+
+// snapshotKeys are the 2x2 block of keys a snapshot shows on with
+// show: keys, left to right and top to bottom.
+var snapshotKeys = []module.KeyID{module.Key1, module.Key2, module.Key5, module.Key6}
+
+// snapshot is a camera image shown as an overlay after a doorbell rang.
+type snapshot struct {
+	img    image.Image
+	name   string // the sensor's friendly name
+	onKeys bool
+	shown  time.Duration
+}
+
+// fetchDoorbells checks each doorbell sensor, showing its camera when the
+// sensor turns on. A sensor already on at startup doesn't count.
+func (m *Module) fetchDoorbells(ctx context.Context) {
+	for _, d := range m.config.Doorbells {
+		start := time.Now()
+		state, err := m.client.GetState(ctx, d.Sensor)
+		metrics.ObserveFetch(m.ID(), start, err)
+		if err != nil {
+			m.Log().Warn("Failed to fetch doorbell state", "entity", d.Sensor, "err", err)
+			continue
+		}
+
+		m.mu.Lock()
+		prev, seen := m.doorbellStates[d.Sensor]
+		m.doorbellStates[d.Sensor] = state.State
+		m.mu.Unlock()
+
+		if seen && prev != "on" && state.State == "on" {
+			m.showSnapshot(ctx, d, entityName(d.Sensor, state))
+		}
+	}
+}
+
+// showSnapshot fetches a doorbell's camera image and opens the overlay.
+func (m *Module) showSnapshot(ctx context.Context, d config.HADoorbell, name string) {
+	m.Log().Info("Doorbell", "entity", d.Sensor, "camera", d.Camera)
+	img, err := m.client.GetCameraSnapshot(ctx, d.Camera)
+	if err != nil {
+		m.Log().Warn("Failed to fetch camera snapshot", "entity", d.Camera, "err", err)
+		return
+	}
+
+	shown := defaultSnapshotShown
+	if d.Seconds > 0 {
+		shown = time.Duration(d.Seconds) * time.Second
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot = &snapshot{
+		img:    img,
+		name:   name,
+		onKeys: d.Show == "keys" || !m.resources.Device.HasStrip(),
+		shown:  shown,
+	}
+}
+
+// getSnapshot returns the snapshot showing, or nil.
+func (m *Module) getSnapshot() *snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshot
+}
+
+// IsOverlayActive returns true while a doorbell snapshot is showing.
+func (m *Module) IsOverlayActive() bool {
+	return m.getSnapshot() != nil
+}
+
+// OverlayTimeout returns how long the snapshot shows.
+func (m *Module) OverlayTimeout() time.Duration {
+	if s := m.getSnapshot(); s != nil {
+		return s.shown
+	}
+	return defaultSnapshotShown
+}
+
+// CloseOverlay dismisses the snapshot.
+func (m *Module) CloseOverlay() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot = nil
+}
+
+// RenderOverlayKeys returns the snapshot across a 2x2 block of keys, with
+// the rest dark, when it shows on the keys. Otherwise the keys are left as
+// they were.
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	s := m.getSnapshot()
+	if s == nil || !s.onKeys {
+		return nil
+	}
+
+	block := render.Scale(s.img, 2*keySize)
+	keys := make(map[module.KeyID]image.Image)
+	for id := module.Key1; id <= module.Key8; id++ {
+		keys[id] = render.NewKey(color.Black)
+	}
+	for i, id := range snapshotKeys {
+		tile := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+		draw.Draw(tile, tile.Bounds(), block, image.Pt(i%2*keySize, i/2*keySize), draw.Src)
+		keys[id] = tile
+	}
+	return keys
+}
+
+// RenderOverlayStrip returns the snapshot, fit to the strip's height beside
+// the sensor's name, when it shows on the strip.
+func (m *Module) RenderOverlayStrip() image.Image {
+	s := m.getSnapshot()
+	if s == nil || s.onKeys {
+		return nil
+	}
+	rect := m.resources.Device.StripRect
+
+	img := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+
+	b := s.img.Bounds()
+	h := rect.Dy()
+	w := min(rect.Dx(), b.Dx()*h/b.Dy())
+	x := (rect.Dx() - w) / 2
+	draw.CatmullRom.Scale(img, image.Rect(x, 0, x+w, h), s.img, b, draw.Src, nil)
+
+	if x > 24 {
+		name := render.TruncateText(s.name, m.valueFace, x-24)
+		render.DrawText(img, name, 12, h/2+8, m.valueFace, colorWhite)
+	}
+	return img
+}
+
+// HandleOverlayKey dismisses the snapshot on any key press.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		m.CloseOverlay()
+	}
+	return nil
+}
+
+// HandleOverlayDial dismisses the snapshot on dial press.
+func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
+	if event.Type == module.DialRelease {
+		m.CloseOverlay()
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch dismisses the snapshot on tap.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	if event.Type == module.TouchTap {
+		m.CloseOverlay()
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package homeassistant
+
+import (
+	"context"
+	"image"
+	"math"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// entityBinding ties a configured entity to the key (and optional dial) that
+// controls it. The entity's domain decides how it's rendered and what input does.
+type entityBinding struct {
+	entityID string
+	control  entityControl
+	key      module.KeyID
+	dial     module.DialID // 0 if no dial is bound
+}
+
+// entityControl implements rendering and input handling for one entity domain.
+// Input methods run on the device listener and must not block; they update
+// cached state optimistically and fire service calls via callService.
+type entityControl interface {
+	// usesDial reports whether this domain wants a dial bound to it.
+	usesDial() bool
+	render(m *Module, b *entityBinding, state EntityState) image.Image
+	press(m *Module, b *entityBinding, state EntityState)
+	rotate(m *Module, b *entityBinding, state EntityState, delta int8)
+	dialPress(m *Module, b *entityBinding, state EntityState)
+}
+
+// domainControls maps HA entity domains to their controls.
+var domainControls = map[string]entityControl{
+	"climate":      climateControl{},
+	"media_player": mediaPlayerControl{},
+	"light":        lightControl{},
+	"scene":        actionControl{service: "turn_on", icon: "clapperboard"},
+	"script":       actionControl{service: "turn_on", icon: "scroll-text"},
+	"automation":   actionControl{service: "trigger", icon: "zap"},
+}
+
+// bindEntities assigns configured entities to the keys not bound to a slot,
+// and dials after the ring light dial, in order.
+func (m *Module) bindEntities() {
+	m.entities = nil
+	keys := m.resources.FreeKeys()
+	dials := m.resources.Dials
+
+	nextKey, nextDial := 0, 1
+	for _, id := range m.config.Entities {
+		control, ok := domainControls[Domain(id)]
+		if !ok {
+			m.Log().Warn("Unsupported entity domain, skipping", "entity", id)
+			continue
+		}
+		if nextKey >= len(keys) {
+			m.Log().Warn("No key available, skipping", "entity", id)
+			continue
+		}
+
+		b := &entityBinding{entityID: id, control: control, key: keys[nextKey]}
+		nextKey++
+		if control.usesDial() && nextDial < len(dials) {
+			b.dial = dials[nextDial]
+			nextDial++
+		}
+		m.entities = append(m.entities, b)
+	}
+}
+
+// fetchEntityStates refreshes the state of every bound entity.
+func (m *Module) fetchEntityStates(ctx context.Context) {
+	for _, b := range m.entities {
+		start := time.Now()
+		state, err := m.client.GetState(ctx, b.entityID)
+		metrics.ObserveFetch(m.ID(), start, err)
+		if err != nil {
+			m.Log().Warn("Failed to fetch state", "entity", b.entityID, "err", err)
+			continue
+		}
+
+		m.mu.Lock()
+		m.entityStates[b.entityID] = state
+		m.mu.Unlock()
+	}
+}
+
+// getEntityState returns the last known state for an entity.
+func (m *Module) getEntityState(entityID string) EntityState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.entityStates[entityID]
+}
+
+// setEntityAttribute optimistically updates a cached attribute so rapid dial
+// ticks chain off the new value rather than the last polled one.
+func (m *Module) setEntityAttribute(entityID, name string, value any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.entityStates[entityID]
+	attrs := make(map[string]any, len(state.Attributes)+1)
+	for k, v := range state.Attributes {
+		attrs[k] = v
+	}
+	attrs[name] = value
+	state.Attributes = attrs
+	m.entityStates[entityID] = state
+}
+
+// entityForKey returns the binding for a key, or nil.
+func (m *Module) entityForKey(id module.KeyID) *entityBinding {
+	for _, b := range m.entities {
+		if b.key == id {
+			return b
+		}
+	}
+	return nil
+}
+
+// entityForDial returns the binding for a dial, or nil.
+func (m *Module) entityForDial(id module.DialID) *entityBinding {
+	for _, b := range m.entities {
+		if b.dial != 0 && b.dial == id {
+			return b
+		}
+	}
+	return nil
+}
+
+// callService fires an HA service call in the background so input handling
+// never blocks the device listener. Failures are logged.
+func (m *Module) callService(domain, service string, data map[string]any) {
+	go func() {
+		if err := m.client.CallService(m.Context(), domain, service, data); err != nil {
+			m.Log().Warn("Service call failed", "service", domain+"."+service, "err", err)
+		}
+	}()
+}
+
+// climateControl shows current temperature and setpoint; the dial adjusts the setpoint.
+type climateControl struct{}
+
+func (climateControl) usesDial() bool { return true }
+
+func (climateControl) render(m *Module, b *entityBinding, state EntityState) image.Image {
+	return m.renderClimateButton(state)
+}
+
+// press toggles the thermostat between off and its last active mode.
+func (climateControl) press(m *Module, b *entityBinding, state EntityState) {
+	service := "turn_off"
+	if state.State == "off" {
+		service = "turn_on"
+	}
+	m.Log().Info("Climate", "entity", b.entityID, "service", service)
+	m.callService("climate", service, map[string]any{"entity_id": b.entityID})
+}
+
+func (climateControl) rotate(m *Module, b *entityBinding, state EntityState, delta int8) {
+	target, ok := state.Float("temperature")
+	if !ok {
+		return
+	}
+
+	step, ok := state.Float("target_temp_step")
+	if !ok || step <= 0 {
+		step = 0.5
+	}
+
+	newTarget := target + float64(delta)*step
+	if lo, ok := state.Float("min_temp"); ok && newTarget < lo {
+		newTarget = lo
+	}
+	if hi, ok := state.Float("max_temp"); ok && newTarget > hi {
+		newTarget = hi
+	}
+	newTarget = math.Round(newTarget/step) * step
+	if newTarget == target {
+		return
+	}
+
+	m.setEntityAttribute(b.entityID, "temperature", newTarget)
+	m.Log().Info("Climate setpoint", "entity", b.entityID, "target", newTarget)
+	m.callService("climate", "set_temperature", map[string]any{
+		"entity_id":   b.entityID,
+		"temperature": newTarget,
+	})
+}
+
+func (climateControl) dialPress(m *Module, b *entityBinding, state EntityState) {}
+
+// mediaPlayerControl shows the current media; the dial controls volume.
+type mediaPlayerControl struct{}
+
+func (mediaPlayerControl) usesDial() bool { return true }
+
+func (mediaPlayerControl) render(m *Module, b *entityBinding, state EntityState) image.Image {
+	return m.renderMediaPlayerButton(state)
+}
+
+func (mediaPlayerControl) press(m *Module, b *entityBinding, state EntityState) {
+	m.Log().Info("Media player play/pause", "entity", b.entityID)
+	m.callService("media_player", "media_play_pause", map[string]any{"entity_id": b.entityID})
+}
+
+func (mediaPlayerControl) rotate(m *Module, b *entityBinding, state EntityState, delta int8) {
+	volume, ok := state.Float("volume_level")
+	if !ok {
+		return
+	}
+
+	// Each dial tick adjusts volume by 2%
+	newVolume := math.Max(0, math.Min(1, volume+float64(delta)*0.02))
+	if newVolume == volume {
+		return
+	}
+
+	m.setEntityAttribute(b.entityID, "volume_level", newVolume)
+	m.callService("media_player", "volume_set", map[string]any{
+		"entity_id":    b.entityID,
+		"volume_level": newVolume,
+	})
+}
+
+// dialPress toggles mute.
+func (mediaPlayerControl) dialPress(m *Module, b *entityBinding, state EntityState) {
+	muted := !state.Bool("is_volume_muted")
+	m.setEntityAttribute(b.entityID, "is_volume_muted", muted)
+	m.callService("media_player", "volume_mute", map[string]any{
+		"entity_id":       b.entityID,
+		"is_volume_muted": muted,
+	})
+}
+
+// lightControl toggles a light; the dial adjusts brightness.
+type lightControl struct{}
+
+func (lightControl) usesDial() bool { return true }
+
+func (lightControl) render(m *Module, b *entityBinding, state EntityState) image.Image {
+	return m.renderLightButton(b.entityID, state)
+}
+
+func (lightControl) press(m *Module, b *entityBinding, state EntityState) {
+	m.callService("light", "toggle", map[string]any{"entity_id": b.entityID})
+}
+
+func (lightControl) rotate(m *Module, b *entityBinding, state EntityState, delta int8) {
+	m.callService("light", "turn_on", map[string]any{
+		"entity_id":       b.entityID,
+		"brightness_step": int(delta) * 25,
+	})
+}
+
+func (lightControl) dialPress(m *Module, b *entityBinding, state EntityState) {}
+
+// actionControl runs a scene, script, or automation on press, showing on
+// the key whether Home Assistant accepted it.
+type actionControl struct {
+	service string // activates the entity, in its own domain
+	icon    string // built-in icon when the entity has none the icon pack knows
+}
+
+func (actionControl) usesDial() bool { return false }
+
+func (c actionControl) render(m *Module, b *entityBinding, state EntityState) image.Image {
+	return m.renderActionButton(b.entityID, c.icon, state)
+}
+
+func (c actionControl) press(m *Module, b *entityBinding, state EntityState) {
+	domain := Domain(b.entityID)
+	m.Log().Info("Running", "entity", b.entityID, "service", domain+"."+c.service)
+	m.RunOnKey(b.key, func(ctx context.Context) error {
+		return m.client.CallService(ctx, domain, c.service, map[string]any{"entity_id": b.entityID})
+	})
+}
+
+func (actionControl) rotate(m *Module, b *entityBinding, state EntityState, delta int8) {}
+
+func (actionControl) dialPress(m *Module, b *entityBinding, state EntityState) {}
@@ -5,13 +5,13 @@ import (
 	"context"
 	"fmt"
 	"image"
-	"log"
 	"sync"
 	"time"
 
 	"github.com/phinze/belowdeck/internal/config"
-	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/metrics"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/reachability"
 	"golang.org/x/image/font"
 )
 
@@ -21,13 +21,14 @@ type Config struct {
 	Token             string
 	RingLightEntity   string
 	OfficeLightEntity string
+	Entities          []string
+	Doorbells         []config.HADoorbell
 }
 
 // Module implements the Home Assistant control module.
 type Module struct {
 	module.BaseModule
 
-	device  device.Device
 	appCfg  *config.Config
 	config  Config
 	client  *Client
@@ -37,19 +38,26 @@ type Module struct {
 	mu               sync.RWMutex
 	ringLightState   LightState
 	officeLightState LightState
+	entityStates     map[string]EntityState
+	colorDials       map[module.DialID]colorDial
+	doorbellStates   map[string]string // last state of each doorbell sensor
+	snapshot         *snapshot         // doorbell overlay, nil when closed
+
+	// Additional entities bound to keys/dials, controlled by domain
+	entities []*entityBinding
 
 	// Fonts
 	labelFace font.Face
+	valueFace font.Face
 
 	// Resources
 	resources module.Resources
 }
 
 // New creates a new Home Assistant module.
-func New(dev device.Device, appCfg *config.Config) *Module {
+func New(appCfg *config.Config) *Module {
 	return &Module{
 		BaseModule: module.NewBaseModule("homeassistant"),
-		device:     dev,
 		appCfg:     appCfg,
 	}
 }
@@ -59,6 +67,17 @@ func (m *Module) ID() string {
 	return "homeassistant"
 }
 
+// Key slots. Configured entities take the module's other keys.
+const (
+	slotOffice     = "office"      // office time / quittin time
+	slotToggleRing = "toggle-ring" // ring light toggle
+)
+
+// Slots declares the module's fixed keys.
+func (m *Module) Slots() []module.Slot {
+	return []module.Slot{{Name: slotOffice}, {Name: slotToggleRing}}
+}
+
 // Init initializes the module.
 func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Call base init
@@ -71,7 +90,7 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Load config (optional - module disabled if not configured)
 	config, err := loadConfig(m.appCfg)
 	if err != nil {
-		log.Printf("Home Assistant module disabled: %v", err)
+		m.Log().Warn("Module disabled", "err", err)
 		m.enabled = false
 		return nil
 	}
@@ -81,6 +100,11 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Create API client
 	m.client = NewClient(m.config.URL, m.config.Token)
 
+	m.entityStates = make(map[string]EntityState)
+	m.colorDials = make(map[module.DialID]colorDial)
+	m.doorbellStates = make(map[string]string)
+	m.bindEntities()
+
 	// Initialize fonts
 	if err := m.initFonts(); err != nil {
 		return err
@@ -89,40 +113,35 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Start state polling
 	go m.pollState(ctx)
 
-	log.Printf("Home Assistant module initialized (url=%s)", m.config.URL)
+	m.Log().Info("Module initialized", "url", m.config.URL)
 	return nil
 }
 
-// pollState periodically fetches entity states from Home Assistant.
+// pollState fetches entity states from Home Assistant now and every 2
+// seconds while the network is reachable.
 func (m *Module) pollState(ctx context.Context) {
-	// Initial fetch
-	m.fetchRingLightState(ctx)
-	m.fetchOfficeLightState(ctx)
-
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			m.fetchRingLightState(ctx)
-			m.fetchOfficeLightState(ctx)
-		}
-	}
+	reachability.Poll(ctx, 2*time.Second, func(ctx context.Context) {
+		m.fetchRingLightState(ctx)
+		m.fetchOfficeLightState(ctx)
+		m.fetchEntityStates(ctx)
+		m.fetchDoorbells(ctx)
+	})
 }
 
 // fetchRingLightState fetches the current ring light state.
 func (m *Module) fetchRingLightState(ctx context.Context) {
-	state, err := m.client.GetLightState(ctx, m.config.RingLightEntity)
+	start := time.Now()
+	state, err := m.client.GetState(ctx, m.config.RingLightEntity)
+	metrics.ObserveFetch(m.ID(), start, err)
 	if err != nil {
-		log.Printf("Failed to fetch ring light state: %v", err)
+		m.Log().Warn("Failed to fetch ring light state", "err", err)
 		return
 	}
 
+	// Kept whole too, for its color attributes
 	m.mu.Lock()
-	m.ringLightState = state
+	m.ringLightState = state.Light()
+	m.entityStates[m.config.RingLightEntity] = state
 	m.mu.Unlock()
 }
 
@@ -135,9 +154,11 @@ func (m *Module) getRingLightState() LightState {
 
 // fetchOfficeLightState fetches the current office light state.
 func (m *Module) fetchOfficeLightState(ctx context.Context) {
+	start := time.Now()
 	state, err := m.client.GetLightState(ctx, m.config.OfficeLightEntity)
+	metrics.ObserveFetch(m.ID(), start, err)
 	if err != nil {
-		log.Printf("Failed to fetch office light state: %v", err)
+		m.Log().Warn("Failed to fetch office light state", "err", err)
 		return
 	}
 
@@ -189,6 +210,8 @@ func loadConfig(appCfg *config.Config) (Config, error) {
 		Token:             token,
 		RingLightEntity:   ringLightEntity,
 		OfficeLightEntity: officeLightEntity,
+		Entities:          appCfg.HomeAssistant.Entities,
+		Doorbells:         appCfg.HomeAssistant.Doorbells,
 	}, nil
 }
 
@@ -200,14 +223,19 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 
 	keys := make(map[module.KeyID]image.Image)
 
-	// Key 0: Office Time button
-	if len(m.resources.Keys) > 0 {
-		keys[m.resources.Keys[0]] = m.renderOfficeTimeButton()
+	// Office Time button
+	if k, ok := m.resources.Slot(slotOffice); ok {
+		keys[k] = m.renderOfficeTimeButton()
+	}
+
+	// Ring Light toggle
+	if k, ok := m.resources.Slot(slotToggleRing); ok {
+		keys[k] = m.renderRingLightButton()
 	}
 
-	// Key 1: Ring Light toggle
-	if len(m.resources.Keys) > 1 {
-		keys[m.resources.Keys[1]] = m.renderRingLightButton()
+	// Other keys: configured entities, rendered by domain
+	for _, b := range m.entities {
+		keys[b.key] = b.control.render(m, b, m.getEntityState(b.entityID))
 	}
 
 	return keys
@@ -230,18 +258,22 @@ func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
 	}
 
 	// Fire-and-forget: run HA calls in a goroutine so we never block the device listener.
-	// Key 0: Office toggle button
-	if len(m.resources.Keys) > 0 && id == m.resources.Keys[0] {
+	// Office toggle button
+	if k, ok := m.resources.Slot(slotOffice); ok && id == k {
 		go m.toggleOfficeMode()
 		return nil
 	}
 
-	// Key 1: Ring Light toggle
-	if len(m.resources.Keys) > 1 && id == m.resources.Keys[1] {
+	// Ring Light toggle
+	if k, ok := m.resources.Slot(slotToggleRing); ok && id == k {
 		go m.toggleRingLight()
 		return nil
 	}
 
+	if b := m.entityForKey(id); b != nil {
+		b.control.press(m, b, m.getEntityState(b.entityID))
+	}
+
 	return nil
 }
 
@@ -250,41 +282,41 @@ func (m *Module) toggleOfficeMode() {
 	state := m.getOfficeLightState()
 
 	if state.On {
-		log.Println("Executing Quittin Time script...")
+		m.Log().Info("Executing Quittin Time script")
 		err := m.client.CallService(m.Context(), "script", "turn_on", map[string]any{
 			"entity_id": "script.quittin_time",
 		})
 		if err != nil {
-			log.Printf("Failed to execute Quittin Time: %v", err)
+			m.Log().Warn("Failed to execute Quittin Time", "err", err)
 			return
 		}
-		log.Println("Quittin Time script executed successfully")
+		m.Log().Info("Quittin Time script executed")
 	} else {
-		log.Println("Executing Office Time script...")
+		m.Log().Info("Executing Office Time script")
 		err := m.client.CallService(m.Context(), "script", "turn_on", map[string]any{
 			"entity_id": "script.office_time",
 		})
 		if err != nil {
-			log.Printf("Failed to execute Office Time: %v", err)
+			m.Log().Warn("Failed to execute Office Time", "err", err)
 			return
 		}
-		log.Println("Office Time script executed successfully")
+		m.Log().Info("Office Time script executed")
 	}
 }
 
 // toggleRingLight toggles the ring light on/off.
 func (m *Module) toggleRingLight() {
-	log.Println("Toggling ring light...")
+	m.Log().Info("Toggling ring light")
 
 	err := m.client.CallService(m.Context(), "light", "toggle", map[string]any{
 		"entity_id": m.config.RingLightEntity,
 	})
 	if err != nil {
-		log.Printf("Failed to toggle ring light: %v", err)
+		m.Log().Warn("Failed to toggle ring light", "err", err)
 		return
 	}
 
-	log.Println("Ring light toggled")
+	m.Log().Info("Ring light toggled")
 }
 
 // adjustRingLightBrightness adjusts the ring light brightness by a delta.
@@ -303,12 +335,12 @@ func (m *Module) adjustRingLightBrightness(delta int8) {
 		m.ringLightState.Brightness = nil
 		m.mu.Unlock()
 
-		log.Printf("Brightness would reach 0, turning off ring light")
+		m.Log().Info("Brightness would reach 0, turning off ring light")
 		err := m.client.CallService(m.Context(), "light", "turn_off", map[string]any{
 			"entity_id": m.config.RingLightEntity,
 		})
 		if err != nil {
-			log.Printf("Failed to turn off ring light: %v", err)
+			m.Log().Warn("Failed to turn off ring light", "err", err)
 		}
 		return
 	}
@@ -326,14 +358,14 @@ func (m *Module) adjustRingLightBrightness(delta int8) {
 	}
 	m.mu.Unlock()
 
-	log.Printf("Adjusting ring light brightness by %d", step)
+	m.Log().Info("Adjusting ring light brightness", "step", step)
 
 	err := m.client.CallService(m.Context(), "light", "turn_on", map[string]any{
 		"entity_id":       m.config.RingLightEntity,
 		"brightness_step": step,
 	})
 	if err != nil {
-		log.Printf("Failed to adjust ring light brightness: %v", err)
+		m.Log().Warn("Failed to adjust ring light brightness", "err", err)
 	}
 }
 
@@ -343,14 +375,42 @@ func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
 		return nil
 	}
 
-	// Only handle rotation events
-	if event.Type != module.DialRotate {
+	// Entity dials handle rotation and press. A light's dial turned while
+	// held adjusts its color instead.
+	if b := m.entityForDial(id); b != nil {
+		state := m.getEntityState(b.entityID)
+		isLight := Domain(b.entityID) == "light"
+		switch event.Type {
+		case module.DialRotate:
+			if isLight && m.turnColorDial(id, b.entityID, event.Delta) {
+				return nil
+			}
+			b.control.rotate(m, b, state, event.Delta)
+		case module.DialPress:
+			if isLight {
+				m.pressColorDial(id, b.entityID, event)
+				return nil
+			}
+			b.control.dialPress(m, b, state)
+		case module.DialRelease:
+			if isLight {
+				m.pressColorDial(id, b.entityID, event)
+			}
+		}
 		return nil
 	}
 
-	// Dial 0: Ring Light brightness (fire-and-forget)
+	// Dial 0: Ring Light brightness (fire-and-forget), or color while held
 	if len(m.resources.Dials) > 0 && id == m.resources.Dials[0] {
-		go m.adjustRingLightBrightness(event.Delta)
+		entityID := m.config.RingLightEntity
+		switch event.Type {
+		case module.DialRotate:
+			if !m.turnColorDial(id, entityID, event.Delta) {
+				go m.adjustRingLightBrightness(event.Delta)
+			}
+		case module.DialPress, module.DialRelease:
+			m.pressColorDial(id, entityID, event)
+		}
 		return nil
 	}
 
@@ -1,30 +1,17 @@
 package homeassistant
 
 import (
-	_ "embed"
 	"fmt"
 	"image"
 	"image/color"
-	"log"
+	"math"
 	"strings"
 
-	"github.com/srwiley/oksvg"
-	"github.com/srwiley/rasterx"
+	"github.com/phinze/belowdeck/internal/render"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
-	"golang.org/x/image/math/fixed"
 )
 
-//go:embed fonts/PublicSans-Bold.ttf
-var fontBold []byte
-
-//go:embed icons/lamp-desk.svg
-var iconLampDeskSVG string
-
-//go:embed icons/circle.svg
-var iconCircleSVG string
-
 // Common colors
 var (
 	colorKeyBg    = color.RGBA{40, 40, 40, 255}
@@ -32,26 +19,23 @@ var (
 	colorAmber    = color.RGBA{255, 191, 0, 255}
 	colorLightRay = color.RGBA{255, 245, 180, 255}
 	colorDimGray  = color.RGBA{80, 80, 80, 255}
+	colorHeat     = color.RGBA{255, 120, 40, 255}
+	colorCool     = color.RGBA{80, 160, 255, 255}
+	colorGreen    = color.RGBA{50, 205, 50, 255}
+	colorBarBg    = color.RGBA{70, 70, 70, 255}
 )
 
 const keySize = 72
 
 // initFonts initializes the font faces for rendering.
 func (m *Module) initFonts() error {
-	ttBold, err := opentype.Parse(fontBold)
-	if err != nil {
-		return fmt.Errorf("failed to parse bold font: %w", err)
+	var err error
+	if m.labelFace, err = render.NewFace(render.Bold, 11); err != nil {
+		return err
 	}
-
-	m.labelFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    11,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create label face: %w", err)
+	if m.valueFace, err = render.NewFace(render.Bold, 22); err != nil {
+		return err
 	}
-
 	return nil
 }
 
@@ -77,7 +61,7 @@ func (m *Module) renderOfficeTimeButton() image.Image {
 	}
 
 	// Draw icon in upper portion
-	iconImg := renderSVGIcon(iconLampDeskSVG, 40, iconColor)
+	iconImg := render.Icon("lamp-desk", 40, iconColor)
 	iconX := (keySize - 40) / 2
 	iconY := 8
 	draw.Draw(img, image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg, image.Point{}, draw.Over)
@@ -88,7 +72,7 @@ func (m *Module) renderOfficeTimeButton() image.Image {
 	}
 
 	// Draw label at bottom
-	m.drawTextCentered(img, labelText, keySize/2, 62, m.labelFace, colorWhite)
+	render.DrawTextCentered(img, labelText, keySize/2, 62, m.labelFace, colorWhite)
 
 	return img
 }
@@ -106,9 +90,9 @@ func drawLightRays(img *image.RGBA, col color.Color) {
 	rays := []struct {
 		x1, y1, x2, y2 int
 	}{
-		{43, 33, 48, 38},  // closest to lamp
-		{48, 28, 53, 33},  // middle ray
-		{53, 23, 58, 28},  // furthest ray
+		{43, 33, 48, 38}, // closest to lamp
+		{48, 28, 53, 33}, // middle ray
+		{53, 23, 58, 28}, // furthest ray
 	}
 
 	for _, r := range rays {
@@ -189,55 +173,267 @@ func (m *Module) renderRingLightButton() image.Image {
 	}
 
 	// Draw icon in upper portion
-	iconImg := renderSVGIcon(iconCircleSVG, 40, iconColor)
+	iconImg := render.Icon("circle", 40, iconColor)
 	iconX := (keySize - 40) / 2
 	iconY := 8
 	draw.Draw(img, image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg, image.Point{}, draw.Over)
 
 	// Draw label at bottom
-	m.drawTextCentered(img, labelText, keySize/2, 62, m.labelFace, colorWhite)
+	render.DrawTextCentered(img, labelText, keySize/2, 62, m.labelFace, colorWhite)
 
 	return img
 }
 
-// renderSVGIcon renders an SVG string to an image with the given size and color.
-func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
-	// Replace currentColor with the actual color
-	r, g, b, _ := iconColor.RGBA()
-	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
-	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
+// hvacColor returns the accent color for a thermostat's HVAC mode.
+func hvacColor(mode string) color.Color {
+	switch mode {
+	case "heat":
+		return colorHeat
+	case "cool":
+		return colorCool
+	case "heat_cool", "auto":
+		return colorGreen
+	case "off", "":
+		return colorDimGray
+	default:
+		return colorWhite
+	}
+}
+
+// renderClimateButton renders a thermostat: current temperature large, setpoint below.
+func (m *Module) renderClimateButton(state EntityState) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	accent := hvacColor(state.State)
 
-	// Parse SVG
-	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
-	if err != nil {
-		log.Printf("Failed to parse SVG: %v", err)
-		return image.NewRGBA(image.Rect(0, 0, size, size))
+	// Mode indicator bar at top
+	draw.Draw(img, image.Rect(0, 0, keySize, 4), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	iconImg := render.Icon("thermometer", 16, accent)
+	draw.Draw(img, image.Rect(4, 8, 20, 24), iconImg, image.Point{}, draw.Over)
+
+	// Current temperature
+	current := "--"
+	if t, ok := state.Float("current_temperature"); ok {
+		current = fmt.Sprintf("%.0f°", t)
+	}
+	render.DrawTextCentered(img, current, keySize/2, 44, m.valueFace, colorWhite)
+
+	// Setpoint
+	label := strings.ToUpper(state.State)
+	if t, ok := state.Float("temperature"); ok && state.State != "off" {
+		label = "Set " + formatTemp(t)
+	}
+	render.DrawTextCentered(img, label, keySize/2, 62, m.labelFace, accent)
+
+	return img
+}
+
+// formatTemp formats a setpoint, keeping a decimal only for half-degree steps.
+func formatTemp(t float64) string {
+	if t == float64(int(t)) {
+		return fmt.Sprintf("%.0f°", t)
 	}
+	return fmt.Sprintf("%.1f°", t)
+}
 
-	// Create output image with transparent background
-	img := image.NewRGBA(image.Rect(0, 0, size, size))
+// renderMediaPlayerButton renders a media player card with title and volume bar.
+func (m *Module) renderMediaPlayerButton(state EntityState) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
 
-	// Set target size
-	icon.SetTarget(0, 0, float64(size), float64(size))
+	playing := state.State == "playing"
+	iconColor := colorDimGray
+	if playing {
+		iconColor = colorGreen
+	}
+	iconImg := render.Icon("music", 24, iconColor)
+	iconX := (keySize - 24) / 2
+	draw.Draw(img, image.Rect(iconX, 6, iconX+24, 30), iconImg, image.Point{}, draw.Over)
+
+	// Title (or player state when idle)
+	title := state.String("media_title")
+	if title == "" {
+		title = state.String("friendly_name")
+	}
+	if title == "" {
+		title = state.State
+	}
+	render.DrawTextCentered(img, render.TruncateText(title, m.labelFace, keySize-8), keySize/2, 44, m.labelFace, colorWhite)
 
-	// Render to image
-	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
-	raster := rasterx.NewDasher(size, size, scanner)
-	icon.Draw(raster, 1.0)
+	// Artist
+	if artist := state.String("media_artist"); artist != "" {
+		render.DrawTextCentered(img, render.TruncateText(artist, m.labelFace, keySize-8), keySize/2, 56, m.labelFace, colorDimGray)
+	}
+
+	// Volume bar along the bottom
+	if volume, ok := state.Float("volume_level"); ok {
+		barColor := colorAmber
+		if state.Bool("is_volume_muted") {
+			barColor = colorDimGray
+		}
+		bar := image.Rect(6, keySize-8, keySize-6, keySize-4)
+		draw.Draw(img, bar, &image.Uniform{colorBarBg}, image.Point{}, draw.Src)
+		fill := bar
+		fill.Max.X = bar.Min.X + int(float64(bar.Dx())*volume)
+		draw.Draw(img, fill, &image.Uniform{barColor}, image.Point{}, draw.Src)
+	}
 
 	return img
 }
 
-// drawTextCentered draws text centered horizontally at the given position.
-func (m *Module) drawTextCentered(img *image.RGBA, text string, centerX, y int, face font.Face, col color.Color) {
-	width := font.MeasureString(face, text).Ceil()
-	x := centerX - width/2
+// renderLightButton renders a generic light toggle labelled with its friendly name.
+func (m *Module) renderLightButton(entityID string, state EntityState) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	iconColor := color.Color(colorDimGray)
+	if state.State == "on" {
+		iconColor = colorAmber
+		if b, ok := state.Float("brightness"); ok {
+			v := uint8(b)
+			iconColor = color.RGBA{v, v, uint8(float64(v) * 0.9), 255}
+		}
+	}
+
+	iconImg := render.Icon("circle", 40, iconColor)
+	iconX := (keySize - 40) / 2
+	draw.Draw(img, image.Rect(iconX, 8, iconX+40, 48), iconImg, image.Point{}, draw.Over)
+
+	label := entityName(entityID, state)
+	render.DrawTextCentered(img, render.TruncateText(label, m.labelFace, keySize-4), keySize/2, 62, m.labelFace, colorWhite)
+
+	return img
+}
 
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(col),
-		Face: face,
-		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
+// entityName returns an entity's friendly name, or its entity ID without
+// the domain if it has none.
+func entityName(entityID string, state EntityState) string {
+	if name := state.String("friendly_name"); name != "" {
+		return name
+	}
+	return strings.TrimPrefix(entityID, Domain(entityID)+".")
+}
+
+// renderActionButton renders a scene, script, or automation key: the
+// entity's own icon if the icon pack has it, else the domain's, over its
+// friendly name. A running script is lit; a disabled automation is dimmed.
+func (m *Module) renderActionButton(entityID, fallbackIcon string, state EntityState) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	iconColor := color.Color(colorWhite)
+	switch {
+	case Domain(entityID) == "script" && state.State == "on":
+		iconColor = colorAmber
+	case Domain(entityID) == "automation" && state.State == "off":
+		iconColor = colorDimGray
+	}
+
+	// HA icons are named like mdi:movie-open
+	var iconImg image.Image
+	if _, name, ok := strings.Cut(state.String("icon"), ":"); ok {
+		iconImg, _ = render.FindIcon(name, 40, iconColor)
+	}
+	if iconImg == nil {
+		iconImg = render.Icon(fallbackIcon, 40, iconColor)
+	}
+	iconX := (keySize - 40) / 2
+	draw.Draw(img, image.Rect(iconX, 8, iconX+40, 48), iconImg, image.Point{}, draw.Over)
+
+	label := entityName(entityID, state)
+	render.DrawTextCentered(img, render.TruncateText(label, m.labelFace, keySize-4), keySize/2, 62, m.labelFace, colorWhite)
+
+	return img
+}
+
+// colorBarKind is which of a light's colors the strip bar shows.
+type colorBarKind int
+
+const (
+	colorBarTemp colorBarKind = iota // white, warm to cool
+	colorBarHue                      // hue around the color wheel
+)
+
+// renderColorBar renders the strip shown while a light's color is being
+// adjusted: its name and value over a gradient of the range, marked at pos
+// (0 to 1).
+func (m *Module) renderColorBar(rect image.Rectangle, name string, kind colorBarKind, c lightColor, pos float64) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{20, 20, 20, 255}}, image.Point{}, draw.Src)
+
+	// Bar color at f, from 0 at the left to 1 at the right
+	colorAt := func(f float64) color.RGBA {
+		return hueColor(f * 360)
+	}
+	value := fmt.Sprintf("%.0f°", pos*360)
+	if kind == colorBarTemp {
+		colorAt = func(f float64) color.RGBA {
+			return kelvinColor(c.minKelvin + f*(c.maxKelvin-c.minKelvin))
+		}
+		value = fmt.Sprintf("%.0f K", c.minKelvin+pos*(c.maxKelvin-c.minKelvin))
+	}
+
+	const margin = 16
+	width := img.Bounds().Dx()
+	valueWidth := font.MeasureString(m.valueFace, value).Ceil()
+	render.DrawText(img, render.TruncateText(name, m.valueFace, width-valueWidth-3*margin), margin, 34, m.valueFace, colorWhite)
+	render.DrawText(img, value, width-margin-valueWidth, 34, m.valueFace, colorWhite)
+
+	bar := image.Rect(margin, 52, width-margin, img.Bounds().Dy()-16)
+	for x := bar.Min.X; x < bar.Max.X; x++ {
+		f := float64(x-bar.Min.X) / float64(bar.Dx()-1)
+		draw.Draw(img, image.Rect(x, bar.Min.Y, x+1, bar.Max.Y), &image.Uniform{colorAt(f)}, image.Point{}, draw.Src)
+	}
+
+	// Marker at the current value, outlined so it shows against white
+	mx := bar.Min.X + int(pos*float64(bar.Dx()-1))
+	draw.Draw(img, image.Rect(mx-3, bar.Min.Y-4, mx+4, bar.Max.Y+4), &image.Uniform{color.RGBA{0, 0, 0, 255}}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(mx-1, bar.Min.Y-2, mx+2, bar.Max.Y+2), &image.Uniform{colorWhite}, image.Point{}, draw.Src)
+
+	return img
+}
+
+// hueColor returns the fully saturated color at hue h degrees.
+func hueColor(h float64) color.RGBA {
+	h = math.Mod(h, 360) / 60
+	x := uint8(255 * (1 - math.Abs(math.Mod(h, 2)-1)))
+	switch int(h) {
+	case 0:
+		return color.RGBA{255, x, 0, 255}
+	case 1:
+		return color.RGBA{x, 255, 0, 255}
+	case 2:
+		return color.RGBA{0, 255, x, 255}
+	case 3:
+		return color.RGBA{0, x, 255, 255}
+	case 4:
+		return color.RGBA{x, 0, 255, 255}
+	default:
+		return color.RGBA{255, 0, x, 255}
+	}
+}
+
+// kelvinColor approximates the color of white light at a color
+// temperature, after Tanner Helland's fit of blackbody data.
+func kelvinColor(k float64) color.RGBA {
+	t := k / 100
+	clamp := func(v float64) uint8 {
+		return uint8(math.Max(0, math.Min(255, v)))
+	}
+
+	r, g, b := 255.0, 0.0, 255.0
+	if t <= 66 {
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+		if t <= 19 {
+			b = 0
+		} else {
+			b = 138.5177312231*math.Log(t-10) - 305.0447927307
+		}
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
 	}
-	d.DrawString(text)
+	return color.RGBA{clamp(r), clamp(g), clamp(b), 255}
 }
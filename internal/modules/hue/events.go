@@ -0,0 +1,77 @@
+package hue
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// resourceUpdate is a change to a resource, with only what changed set.
+type resourceUpdate struct {
+	ID      string   `json:"id"`
+	Type    string   `json:"type"` // grouped_light, scene, ...
+	On      *onState `json:"on"`
+	Dimming *dimming `json:"dimming"`
+	Status  *struct {
+		Active string `json:"active"`
+	} `json:"status"`
+}
+
+// Events follows the bridge's event stream, calling fn with each resource
+// update, until ctx is done or the stream breaks. Only updates are passed
+// on; resources added or deleted are picked up when the module next loads
+// them.
+func (c *Client) Events(ctx context.Context, fn func(resourceUpdate)) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/eventstream/clip/v2", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("hue-application-key", c.appKey)
+	req.Header.Set("Accept", "text/event-stream")
+
+	// No timeout: the stream stays open for as long as the bridge lets it
+	client := &http.Client{Transport: insecureTransport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	// Each message is a data line holding a JSON list of events; the
+	// bridge also sends comment lines to keep the connection open
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var events []struct {
+			Type string           `json:"type"` // update, add, delete, or error
+			Data []resourceUpdate `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(data), &events); err != nil {
+			continue
+		}
+		for _, e := range events {
+			if e.Type != "update" {
+				continue
+			}
+			for _, u := range e.Data {
+				fn(u)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("event stream closed")
+}
@@ -0,0 +1,473 @@
+// Package hue provides a Stream Deck module that controls Philips Hue rooms
+// and scenes through the bridge, without Home Assistant in between.
+package hue
+
+import (
+	"context"
+	"image"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	// retryInterval is how long to wait before reconnecting when the
+	// bridge can't be reached or its event stream drops.
+	retryInterval = 10 * time.Second
+
+	// commandGap is the least time between commands to one room, so a
+	// fast dial turn doesn't flood the bridge, which queues or drops group
+	// commands sent faster than it can pass them on.
+	commandGap = 300 * time.Millisecond
+
+	// localHold is how long after an input a room ignores updates from the
+	// bridge, so the echoes of the commands it sent don't pull it back.
+	localHold = time.Second
+
+	dimStep = 2 // percent per dial tick
+)
+
+// room is a room or zone bound to a key, and a dial if there's one for it.
+type room struct {
+	name    string
+	lightID string // its grouped_light
+	key     module.KeyID
+	dial    module.DialID // 0 if no dial is bound
+
+	on         bool
+	brightness float64 // percent
+	ok         bool    // false until its state is first loaded
+	changed    time.Time
+
+	send chan struct{} // wakes its sender with the state to send
+}
+
+// sceneKey is a scene bound to a key.
+type sceneKey struct {
+	name   string
+	id     string
+	key    module.KeyID
+	active bool
+}
+
+// Module implements the Hue module.
+type Module struct {
+	module.BaseModule
+
+	appCfg  *config.Config
+	client  *Client
+	enabled bool
+
+	// State
+	mu     sync.RWMutex
+	rooms  []*room
+	scenes []*sceneKey
+	bound  bool // rooms and scenes are bound to keys
+
+	// Fonts
+	labelFace font.Face
+	valueFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new Hue module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("hue"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "hue"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var cfg config.HueConfig
+	if m.appCfg != nil {
+		cfg = m.appCfg.Hue
+	}
+	if cfg.Bridge == "" || cfg.AppKey == "" {
+		m.Log().Warn("Module disabled", "err", "Hue bridge not paired; run belowdeck setup")
+		m.enabled = false
+		return nil
+	}
+	m.enabled = true
+	m.client = NewClient(cfg.Bridge, cfg.AppKey)
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	go m.run(ctx)
+
+	m.Log().Info("Module initialized", "bridge", cfg.Bridge)
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// run loads the bridge's rooms and scenes, then follows its event stream,
+// starting over after a pause whenever either fails.
+func (m *Module) run(ctx context.Context) {
+	for {
+		if err := m.load(ctx); err != nil {
+			m.Log().Warn("Failed to load from bridge", "err", err)
+		} else if err := m.client.Events(ctx, m.apply); ctx.Err() == nil {
+			m.Log().Warn("Event stream dropped", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// load fetches the rooms, their lights, and the scenes, binding them to
+// keys the first time.
+func (m *Module) load(ctx context.Context) error {
+	start := time.Now()
+	groups, err := m.client.Groups(ctx)
+	var lights []groupedLight
+	if err == nil {
+		lights, err = m.client.GroupedLights(ctx)
+	}
+	var scenes []scene
+	if err == nil {
+		scenes, err = m.client.Scenes(ctx)
+	}
+	metrics.ObserveFetch(m.ID(), start, err)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.bound {
+		m.bind(ctx, groups, scenes)
+		m.bound = true
+	}
+	for _, l := range lights {
+		if r := m.roomForLight(l.ID); r != nil {
+			if l.On != nil {
+				r.on = l.On.On
+			}
+			if l.Dimming != nil {
+				r.brightness = l.Dimming.Brightness
+			}
+			r.ok = true
+		}
+	}
+	for _, s := range scenes {
+		for _, k := range m.scenes {
+			if k.id == s.ID && s.Status != nil {
+				k.active = s.Status.Active != "inactive"
+			}
+		}
+	}
+	return nil
+}
+
+// bind assigns the configured rooms, or every room by name, to keys and
+// dials in order, then the configured scenes to the keys after them.
+func (m *Module) bind(ctx context.Context, groups []group, scenes []scene) {
+	cfg := m.appCfg.Hue
+
+	var rooms []group
+	if len(cfg.Rooms) == 0 {
+		for _, g := range groups {
+			if g.Type == "room" {
+				rooms = append(rooms, g)
+			}
+		}
+		slices.SortFunc(rooms, func(a, b group) int {
+			return strings.Compare(a.Metadata.Name, b.Metadata.Name)
+		})
+	}
+	for _, name := range cfg.Rooms {
+		i := slices.IndexFunc(groups, func(g group) bool { return strings.EqualFold(g.Metadata.Name, name) })
+		if i < 0 {
+			m.Log().Warn("No room or zone by that name, skipping", "room", name)
+			continue
+		}
+		rooms = append(rooms, groups[i])
+	}
+
+	keys := m.resources.Keys
+	dials := m.resources.Dials
+	for i, g := range rooms {
+		if i >= len(keys) {
+			m.Log().Warn("No key available, skipping", "room", g.Metadata.Name)
+			continue
+		}
+		r := &room{
+			name:    g.Metadata.Name,
+			lightID: g.groupedLight(),
+			key:     keys[i],
+			send:    make(chan struct{}, 1),
+		}
+		if i < len(dials) {
+			r.dial = dials[i]
+		}
+		m.rooms = append(m.rooms, r)
+		go m.sendRoom(ctx, r)
+	}
+
+	for _, spec := range cfg.Scenes {
+		next := len(m.rooms) + len(m.scenes)
+		if next >= len(keys) {
+			m.Log().Warn("No key available, skipping", "scene", spec)
+			continue
+		}
+		s, ok := findScene(spec, groups, scenes)
+		if !ok {
+			m.Log().Warn("No scene by that name, skipping", "scene", spec)
+			continue
+		}
+		m.scenes = append(m.scenes, &sceneKey{name: s.Metadata.Name, id: s.ID, key: keys[next]})
+	}
+
+	m.Log().Info("Bound to bridge", "rooms", len(m.rooms), "scenes", len(m.scenes))
+}
+
+// findScene finds a scene by name, or by "Room/Scene".
+func findScene(spec string, groups []group, scenes []scene) (scene, bool) {
+	roomName, name, inRoom := strings.Cut(spec, "/")
+	if !inRoom {
+		name = spec
+	}
+	for _, s := range scenes {
+		if !strings.EqualFold(s.Metadata.Name, name) {
+			continue
+		}
+		if !inRoom || slices.ContainsFunc(groups, func(g group) bool {
+			return g.ID == s.Group.RID && strings.EqualFold(g.Metadata.Name, roomName)
+		}) {
+			return s, true
+		}
+	}
+	return scene{}, false
+}
+
+// roomForLight returns the room whose grouped_light is id, or nil. The
+// caller holds mu.
+func (m *Module) roomForLight(id string) *room {
+	for _, r := range m.rooms {
+		if r.lightID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// apply records an update from the event stream.
+func (m *Module) apply(u resourceUpdate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch u.Type {
+	case "grouped_light":
+		r := m.roomForLight(u.ID)
+		if r == nil || time.Since(r.changed) < localHold {
+			return
+		}
+		if u.On != nil {
+			r.on = u.On.On
+		}
+		if u.Dimming != nil {
+			r.brightness = u.Dimming.Brightness
+		}
+	case "scene":
+		for _, k := range m.scenes {
+			if k.id == u.ID && u.Status != nil {
+				k.active = u.Status.Active != "inactive"
+			}
+		}
+	}
+}
+
+// change applies fn to a room's state and wakes its sender.
+func (m *Module) change(r *room, fn func(r *room)) {
+	m.mu.Lock()
+	if !r.ok {
+		m.mu.Unlock()
+		return
+	}
+	fn(r)
+	r.changed = time.Now()
+	m.mu.Unlock()
+
+	select {
+	case r.send <- struct{}{}:
+	default: // already pending; it sends the latest state
+	}
+}
+
+// sendRoom sends a room's state to the bridge whenever it changes, at most
+// once per commandGap, so a run of dial ticks becomes a few commands.
+func (m *Module) sendRoom(ctx context.Context, r *room) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.send:
+		}
+
+		m.mu.RLock()
+		state := groupedLight{On: &onState{On: r.on}}
+		if r.on {
+			state.Dimming = &dimming{Brightness: r.brightness}
+		}
+		m.mu.RUnlock()
+
+		if err := m.client.SetGroupedLight(ctx, r.lightID, state); err != nil {
+			m.Log().Warn("Failed to set room", "room", r.name, "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(commandGap):
+		}
+	}
+}
+
+// roomView is a copy of a room's state for rendering.
+type roomView struct {
+	name       string
+	on         bool
+	brightness float64
+	ok         bool
+}
+
+// RenderKeys returns a key for each room and scene.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled {
+		return nil
+	}
+
+	m.mu.RLock()
+	rooms := make(map[module.KeyID]roomView, len(m.rooms))
+	for _, r := range m.rooms {
+		rooms[r.key] = roomView{name: r.name, on: r.on, brightness: r.brightness, ok: r.ok}
+	}
+	scenes := make(map[module.KeyID]sceneKey, len(m.scenes))
+	for _, s := range m.scenes {
+		scenes[s.key] = *s
+	}
+	m.mu.RUnlock()
+
+	keys := make(map[module.KeyID]image.Image)
+	for k, v := range rooms {
+		keys[k] = m.renderRoomKey(v)
+	}
+	for k, s := range scenes {
+		keys[k] = m.renderSceneKey(s)
+	}
+	return keys
+}
+
+// RenderStrip returns nil; the module doesn't use the strip.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// roomFor returns the first room match accepts, or nil.
+func (m *Module) roomFor(match func(r *room) bool) *room {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, r := range m.rooms {
+		if match(r) {
+			return r
+		}
+	}
+	return nil
+}
+
+// toggle switches a room's lights on or off.
+func (m *Module) toggle(r *room) {
+	m.change(r, func(r *room) { r.on = !r.on })
+	m.Log().Info("Toggled room", "room", r.name)
+}
+
+// HandleKey toggles a room or recalls a scene.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	if r := m.roomFor(func(r *room) bool { return r.key == id }); r != nil {
+		m.toggle(r)
+		return nil
+	}
+
+	m.mu.RLock()
+	i := slices.IndexFunc(m.scenes, func(s *sceneKey) bool { return s.key == id })
+	var s sceneKey
+	if i >= 0 {
+		s = *m.scenes[i]
+	}
+	m.mu.RUnlock()
+	if i < 0 {
+		return nil
+	}
+
+	m.Log().Info("Recalling scene", "scene", s.name)
+	m.RunOnKey(id, func(ctx context.Context) error {
+		return m.client.RecallScene(ctx, s.id)
+	})
+	return nil
+}
+
+// HandleDial dims a room on rotate and toggles it on press.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	if !m.enabled {
+		return nil
+	}
+	r := m.roomFor(func(r *room) bool { return r.dial != 0 && r.dial == id })
+	if r == nil {
+		return nil
+	}
+
+	switch event.Type {
+	case module.DialRotate:
+		delta := float64(event.Delta) * dimStep
+		m.change(r, func(r *room) {
+			r.brightness = min(100, max(1, r.brightness+delta))
+			// Turning it up is a clear signal to turn it on
+			if delta > 0 {
+				r.on = true
+			}
+		})
+	case module.DialPress:
+		m.toggle(r)
+	}
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package hue
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// Colors
+var (
+	colorLit    = color.RGBA{255, 200, 110, 255}
+	colorActive = color.RGBA{255, 191, 0, 255}
+)
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Regular, 11); err != nil {
+		return err
+	}
+	if m.valueFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderRoomKey renders a room: a bulb lit with its brightness, the
+// brightness, and its name.
+func (m *Module) renderRoomKey(r roomView) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	iconColor := color.Color(render.ColorDimGray)
+	value := "..."
+	switch {
+	case !r.ok:
+	case r.on:
+		// Dimmed, but never so far it looks off
+		level := 0.4 + 0.6*r.brightness/100
+		iconColor = color.RGBA{uint8(float64(colorLit.R) * level), uint8(float64(colorLit.G) * level), uint8(float64(colorLit.B) * level), 255}
+		value = fmt.Sprintf("%.0f%%", r.brightness)
+	default:
+		value = "Off"
+	}
+
+	render.DrawIcon(img, render.Icon("lightbulb", 34, iconColor), 6)
+	render.DrawTextCentered(img, value, render.KeySize/2, 54, m.valueFace, render.ColorWhite)
+	label := render.TruncateText(r.name, m.labelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 67, m.labelFace, render.ColorGray)
+	return img
+}
+
+// renderSceneKey renders a scene, lit while it's active.
+func (m *Module) renderSceneKey(s sceneKey) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	iconColor := color.Color(render.ColorWhite)
+	if s.active {
+		iconColor = colorActive
+	}
+	render.DrawIcon(img, render.Icon("sparkles", 40, iconColor), 8)
+	label := render.TruncateText(s.name, m.labelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 64, m.labelFace, render.ColorWhite)
+	return img
+}
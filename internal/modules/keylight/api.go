@@ -0,0 +1,140 @@
+package keylight
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/httpx"
+)
+
+// defaultPort is where Key Lights serve their HTTP API.
+const defaultPort = "9123"
+
+// Limits of the Key Light API. Temperature is in mireds: 143 is 7000 K,
+// 344 is 2900 K.
+const (
+	minBrightness  = 3
+	maxBrightness  = 100
+	minTemperature = 143
+	maxTemperature = 344
+)
+
+// lightState is a light's settings as the API reports and takes them.
+type lightState struct {
+	On          int `json:"on"` // 0 or 1
+	Brightness  int `json:"brightness"`
+	Temperature int `json:"temperature"`
+}
+
+// kelvin returns the light's color temperature in kelvin, to the nearest 50.
+func (s lightState) kelvin() int {
+	if s.Temperature <= 0 {
+		return 0
+	}
+	return int(math.Round(1e6/float64(s.Temperature)/50)) * 50
+}
+
+// lightsBody wraps light states in the API's envelope.
+type lightsBody struct {
+	NumberOfLights int          `json:"numberOfLights"`
+	Lights         []lightState `json:"lights"`
+}
+
+// Client talks to one Key Light over its local HTTP API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the light at host, with or without a port.
+func NewClient(host string) *Client {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, defaultPort)
+	}
+	return &Client{
+		baseURL:    "http://" + host,
+		httpClient: httpx.New("keylight", 3*time.Second),
+	}
+}
+
+// GetState fetches the light's settings.
+func (c *Client) GetState(ctx context.Context) (lightState, error) {
+	var body lightsBody
+	if err := c.get(ctx, "/elgato/lights", &body); err != nil {
+		return lightState{}, err
+	}
+	if len(body.Lights) == 0 {
+		return lightState{}, fmt.Errorf("no lights reported")
+	}
+	return body.Lights[0], nil
+}
+
+// SetState sends the light's settings.
+func (c *Client) SetState(ctx context.Context, state lightState) error {
+	data, err := json.Marshal(lightsBody{NumberOfLights: 1, Lights: []lightState{state}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+"/elgato/lights", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+	return nil
+}
+
+// GetName fetches the name the light was given in Control Center, or its
+// product name if it has none.
+func (c *Client) GetName(ctx context.Context) (string, error) {
+	var info struct {
+		DisplayName string `json:"displayName"`
+		ProductName string `json:"productName"`
+	}
+	if err := c.get(ctx, "/elgato/accessory-info", &info); err != nil {
+		return "", err
+	}
+	if info.DisplayName != "" {
+		return info.DisplayName, nil
+	}
+	return info.ProductName, nil
+}
+
+// get fetches path and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
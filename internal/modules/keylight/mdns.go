@@ -0,0 +1,133 @@
+package keylight
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// elgatoService is the DNS-SD service Key Lights advertise.
+const elgatoService = "_elg._tcp.local."
+
+// mdnsAddr is the multicast DNS group.
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// found is a light answering a discovery query.
+type found struct {
+	instance string // service instance name, e.g. "Elgato Key Light 1A2B"
+	addr     string // host:port
+}
+
+// discover asks the local network for Key Lights, collecting answers for
+// wait. The query goes from an ephemeral port, so responders reply to it
+// directly rather than to the multicast group (RFC 6762 section 6.7).
+func discover(ctx context.Context, wait time.Duration) ([]found, error) {
+	query, err := (&dnsmessage.Message{
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(elgatoService),
+			Type:  dnsmessage.TypePTR,
+			Class: dnsmessage.ClassINET,
+		}},
+	}).Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetReadDeadline(deadline)
+
+	if _, err := conn.WriteTo(query, mdnsAddr); err != nil {
+		return nil, err
+	}
+
+	var answers answerSet
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break // deadline
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil {
+			continue
+		}
+		answers.add(msg, from.IP)
+	}
+	return answers.lights(), nil
+}
+
+// answerSet gathers the records of discovery responses, which may split a
+// light's PTR, SRV, and A records across messages. Maps are keyed by
+// lowercased name, as DNS names compare without case.
+type answerSet struct {
+	instances []string                          // PTR targets, in order of arrival
+	services  map[string]dnsmessage.SRVResource // by instance
+	addrs     map[string]net.IP                 // by host name
+	sources   map[string]net.IP                 // responder of each instance
+}
+
+func (a *answerSet) add(msg dnsmessage.Message, from net.IP) {
+	if a.services == nil {
+		a.services = make(map[string]dnsmessage.SRVResource)
+		a.addrs = make(map[string]net.IP)
+		a.sources = make(map[string]net.IP)
+	}
+
+	for _, r := range append(msg.Answers, msg.Additionals...) {
+		name := strings.ToLower(r.Header.Name.String())
+		switch body := r.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if name != elgatoService {
+				continue
+			}
+			instance := body.PTR.String()
+			key := strings.ToLower(instance)
+			if _, ok := a.sources[key]; !ok {
+				a.instances = append(a.instances, instance)
+			}
+			a.sources[key] = from
+		case *dnsmessage.SRVResource:
+			a.services[name] = *body
+		case *dnsmessage.AResource:
+			a.addrs[name] = net.IP(body.A[:])
+		}
+	}
+}
+
+// lights resolves each instance to an address: the SRV record's host and
+// port where they were answered, else the responder on the default port.
+func (a *answerSet) lights() []found {
+	var lights []found
+	for _, instance := range a.instances {
+		key := strings.ToLower(instance)
+		ip, port := a.sources[key], defaultPort
+		if srv, ok := a.services[key]; ok {
+			port = strconv.Itoa(int(srv.Port))
+			if addr, ok := a.addrs[strings.ToLower(srv.Target.String())]; ok {
+				ip = addr
+			}
+		}
+		name := instance
+		if i := strings.LastIndex(key, "."+elgatoService); i > 0 {
+			name = instance[:i]
+		}
+		lights = append(lights, found{
+			instance: name,
+			addr:     net.JoinHostPort(ip.String(), port),
+		})
+	}
+	return lights
+}
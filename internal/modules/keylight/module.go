@@ -0,0 +1,315 @@
+// Package keylight provides a Stream Deck module that controls Elgato Key
+// Lights over their local HTTP API, without Home Assistant in between.
+package keylight
+
+import (
+	"context"
+	"image"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	// pollInterval is how often the lights are read, to pick up changes
+	// made from Control Center or the light's own buttons.
+	pollInterval = 2 * time.Second
+
+	// discoverWait is how long discovery listens for answers, and
+	// rediscoverInterval how long until it asks again while it has found
+	// no lights.
+	discoverWait       = 2 * time.Second
+	rediscoverInterval = 30 * time.Second
+
+	brightnessStep  = 2 // percent per dial tick
+	temperatureStep = 5 // mireds per dial tick, about 100 K
+)
+
+// light is one Key Light and its last known settings.
+type light struct {
+	label  string
+	client *Client
+	state  lightState
+	ok     bool // false until the first successful fetch
+}
+
+// lightView is a copy of a light's state for rendering.
+type lightView struct {
+	label string
+	state lightState
+	ok    bool
+	temp  bool // its dial adjusts temperature
+}
+
+// Module implements the Key Light module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+
+	// State
+	mu        sync.RWMutex
+	lights    []*light
+	tempDials map[module.DialID]bool // dials pressed into adjusting temperature
+
+	// Fonts
+	labelFace font.Face
+	valueFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new Key Light module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("keylight"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "keylight"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+	m.tempDials = make(map[module.DialID]bool)
+
+	if m.appCfg != nil {
+		for _, l := range m.appCfg.KeyLight.Lights {
+			m.lights = append(m.lights, &light{label: l.Label, client: NewClient(l.Host)})
+		}
+	}
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	go m.pollLights(ctx)
+
+	m.Log().Info("Module initialized", "lights", len(m.lights))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// pollLights finds the lights, if none are configured, then reads them
+// every pollInterval.
+func (m *Module) pollLights(ctx context.Context) {
+	for len(m.getLights()) == 0 {
+		m.discoverLights(ctx)
+		if len(m.getLights()) > 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(rediscoverInterval):
+		}
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		m.fetchAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// discoverLights looks for Key Lights on the network, ordering them by name.
+func (m *Module) discoverLights(ctx context.Context) {
+	answers, err := discover(ctx, discoverWait)
+	if err != nil {
+		m.Log().Warn("Discovery failed", "err", err)
+		return
+	}
+
+	var lights []*light
+	for _, f := range answers {
+		l := &light{label: f.instance, client: NewClient(f.addr)}
+		if name, err := l.client.GetName(ctx); err == nil && name != "" {
+			l.label = name
+		}
+		m.Log().Info("Found light", "name", l.label, "addr", f.addr)
+		lights = append(lights, l)
+	}
+	slices.SortFunc(lights, func(a, b *light) int {
+		return strings.Compare(a.label, b.label)
+	})
+
+	m.mu.Lock()
+	m.lights = lights
+	m.mu.Unlock()
+}
+
+// fetchAll reads every light's settings, and the name of any configured
+// without a label.
+func (m *Module) fetchAll(ctx context.Context) {
+	for _, l := range m.getLights() {
+		start := time.Now()
+		state, err := l.client.GetState(ctx)
+		metrics.ObserveFetch(m.ID(), start, err)
+		if err != nil {
+			m.Log().Warn("Failed to fetch light", "light", l.client.baseURL, "err", err)
+			continue
+		}
+
+		m.mu.Lock()
+		l.state, l.ok = state, true
+		needsName := l.label == ""
+		m.mu.Unlock()
+
+		if needsName {
+			if name, err := l.client.GetName(ctx); err == nil {
+				m.mu.Lock()
+				l.label = name
+				m.mu.Unlock()
+			}
+		}
+	}
+}
+
+// getLights returns the lights found or configured.
+func (m *Module) getLights() []*light {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lights
+}
+
+// lightAt returns the i'th light, or nil.
+func (m *Module) lightAt(i int) *light {
+	lights := m.getLights()
+	if i < 0 || i >= len(lights) {
+		return nil
+	}
+	return lights[i]
+}
+
+// update applies fn to a light's settings and sends the result, in the
+// background so input handling never blocks on the network.
+func (m *Module) update(l *light, fn func(s *lightState)) {
+	m.mu.Lock()
+	if !l.ok {
+		m.mu.Unlock()
+		return
+	}
+	fn(&l.state)
+	state := l.state
+	m.mu.Unlock()
+
+	go func() {
+		if err := l.client.SetState(m.Context(), state); err != nil {
+			m.Log().Warn("Failed to set light", "light", l.label, "err", err)
+		}
+	}()
+}
+
+// RenderKeys returns one key per light, in order.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	keys := make(map[module.KeyID]image.Image)
+	for i, k := range m.resources.Keys {
+		if v, ok := m.view(i); ok {
+			keys[k] = m.renderLightKey(v)
+		}
+	}
+	return keys
+}
+
+// view returns a copy of the i'th light's state.
+func (m *Module) view(i int) (lightView, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if i >= len(m.lights) {
+		return lightView{}, false
+	}
+	l := m.lights[i]
+	v := lightView{label: l.label, state: l.state, ok: l.ok}
+	if i < len(m.resources.Dials) {
+		v.temp = m.tempDials[m.resources.Dials[i]]
+	}
+	return v, true
+}
+
+// RenderStrip returns nil; the module doesn't use the strip.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey toggles the key's light.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+	l := m.lightAt(slices.Index(m.resources.Keys, id))
+	if l == nil {
+		return nil
+	}
+
+	m.update(l, func(s *lightState) { s.On = 1 - s.On })
+	m.Log().Info("Toggled light", "light", l.label)
+	return nil
+}
+
+// HandleDial adjusts the dial's light: brightness on rotate, or color
+// temperature after a press switches it; another press switches back.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	l := m.lightAt(slices.Index(m.resources.Dials, id))
+	if l == nil {
+		return nil
+	}
+
+	switch event.Type {
+	case module.DialPress:
+		m.mu.Lock()
+		m.tempDials[id] = !m.tempDials[id]
+		m.mu.Unlock()
+	case module.DialRotate:
+		m.mu.RLock()
+		temp := m.tempDials[id]
+		m.mu.RUnlock()
+
+		delta := int(event.Delta)
+		if temp {
+			// Clockwise is cooler, so fewer mireds
+			m.update(l, func(s *lightState) {
+				s.Temperature = min(maxTemperature, max(minTemperature, s.Temperature-delta*temperatureStep))
+			})
+			return nil
+		}
+		m.update(l, func(s *lightState) {
+			s.Brightness = min(maxBrightness, max(minBrightness, s.Brightness+delta*brightnessStep))
+			// Turning it up is a clear signal to turn it on
+			if delta > 0 {
+				s.On = 1
+			}
+		})
+	}
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package keylight
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// Colors of a lit icon at either end of the temperature range
+var (
+	colorWarm = color.RGBA{255, 170, 80, 255}
+	colorCool = color.RGBA{200, 225, 255, 255}
+)
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Regular, 11); err != nil {
+		return err
+	}
+	if m.valueFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+	return nil
+}
+
+// lightColor returns the icon color for a light: its shade of white,
+// dimmed with its brightness, or gray when it's off.
+func lightColor(s lightState) color.Color {
+	if s.On == 0 {
+		return render.ColorDimGray
+	}
+	// 0 at the warmest, 1 at the coolest
+	f := float64(maxTemperature-s.Temperature) / (maxTemperature - minTemperature)
+	f = min(1, max(0, f))
+	// Dimmed, but never so far it looks off
+	level := 0.4 + 0.6*float64(s.Brightness)/maxBrightness
+	mix := func(a, b uint8) uint8 {
+		return uint8((float64(a) + f*(float64(b)-float64(a))) * level)
+	}
+	return color.RGBA{mix(colorWarm.R, colorCool.R), mix(colorWarm.G, colorCool.G), mix(colorWarm.B, colorCool.B), 255}
+}
+
+// renderLightKey renders a light's key: its icon in the light's color, the
+// value its dial adjusts, and its name.
+func (m *Module) renderLightKey(v lightView) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	icon := render.Icon("lamp-desk", 34, lightColor(v.state))
+	render.DrawIcon(img, icon, 6)
+
+	value := "..."
+	switch {
+	case !v.ok:
+	case v.temp:
+		value = fmt.Sprintf("%d K", v.state.kelvin())
+	case v.state.On == 0:
+		value = "Off"
+	default:
+		value = fmt.Sprintf("%d%%", v.state.Brightness)
+	}
+	render.DrawTextCentered(img, value, render.KeySize/2, 54, m.valueFace, render.ColorWhite)
+
+	label := render.TruncateText(v.label, m.labelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 67, m.labelFace, render.ColorGray)
+
+	return img
+}
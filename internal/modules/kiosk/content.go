@@ -0,0 +1,162 @@
+package kiosk
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register decoders for image files
+	_ "image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// PlaceStrip names the module's strip region; keys are named 1, 2, ... in
+// the order they're assigned.
+const PlaceStrip = "strip"
+
+// Extensions are the file types the module shows, by extension.
+var Extensions = []string{".txt", ".md", ".png", ".jpg"}
+
+// line is a line of text to show; headings are drawn bold.
+type line struct {
+	text    string
+	heading bool
+}
+
+// content is what's shown in one place: lines of text, or an image.
+type content struct {
+	lines []line
+	img   image.Image
+}
+
+// Dir returns the directory the module watches.
+func Dir(cfg *config.Config) string {
+	if cfg != nil && cfg.Kiosk.Dir != "" {
+		return config.ExpandHome(cfg.Kiosk.Dir)
+	}
+	return filepath.Join(config.DefaultConfigDir(), "kiosk")
+}
+
+// ValidPlace reports whether place names the strip or a key.
+func ValidPlace(place string) bool {
+	if place == PlaceStrip {
+		return true
+	}
+	n, err := strconv.Atoi(place)
+	return err == nil && n >= 1
+}
+
+// Put shows data in place by writing it to dir, replacing whatever was
+// there. ext is one of Extensions, or "" to pick .png or .jpg for an image
+// and .txt otherwise. The file is written aside and renamed into place, so
+// the module never reads half of it.
+func Put(dir, place, ext string, data []byte) error {
+	if !ValidPlace(place) {
+		return fmt.Errorf("invalid place %q: want %s or a key number", place, PlaceStrip)
+	}
+	if ext == "" {
+		switch http.DetectContentType(data) {
+		case "image/png":
+			ext = ".png"
+		case "image/jpeg":
+			ext = ".jpg"
+		default:
+			ext = ".txt"
+		}
+	}
+	if !slices.Contains(Extensions, ext) {
+		return fmt.Errorf("unsupported extension %q", ext)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+place+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := Clear(dir, place); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(dir, place+ext))
+}
+
+// Clear empties place by removing its files from dir.
+func Clear(dir, place string) error {
+	for _, ext := range Extensions {
+		if err := os.Remove(filepath.Join(dir, place+ext)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// load reads a file into content by its extension.
+func load(path string) (content, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return content{}, err
+	}
+	switch filepath.Ext(path) {
+	case ".png", ".jpg":
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return content{}, fmt.Errorf("failed to decode image: %w", err)
+		}
+		return content{img: img}, nil
+	case ".md":
+		return content{lines: parseMarkdown(string(data))}, nil
+	default:
+		return content{lines: parseText(string(data))}, nil
+	}
+}
+
+// parseText splits text into its non-blank lines.
+func parseText(text string) []line {
+	var lines []line
+	for _, s := range strings.Split(text, "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			lines = append(lines, line{text: s})
+		}
+	}
+	return lines
+}
+
+// parseMarkdown reduces markdown to what fits on a key: headings are kept
+// as bold lines, list items get bullets, and inline markup and code fences
+// are dropped.
+func parseMarkdown(text string) []line {
+	emphasis := strings.NewReplacer("**", "", "__", "", "`", "")
+	var lines []line
+	for _, s := range strings.Split(text, "\n") {
+		s = strings.TrimSpace(s)
+		if s == "" || strings.HasPrefix(s, "```") {
+			continue
+		}
+		l := line{}
+		switch {
+		case strings.HasPrefix(s, "#"):
+			l.heading = true
+			s = strings.TrimSpace(strings.TrimLeft(s, "#"))
+		case strings.HasPrefix(s, "- "), strings.HasPrefix(s, "* "), strings.HasPrefix(s, "+ "):
+			s = "• " + strings.TrimSpace(s[2:])
+		}
+		l.text = emphasis.Replace(s)
+		lines = append(lines, l)
+	}
+	return lines
+}
@@ -0,0 +1,276 @@
+// Package kiosk provides a Stream Deck module that shows whatever text or
+// images shell scripts leave for it.
+//
+// The module watches a directory (default ~/.config/belowdeck/kiosk) for
+// files named after where they're shown, 1.txt for its first key or
+// strip.png for its strip, and redraws when one changes. Markdown is shown
+// with its headings in bold. A named pipe, if configured, takes lines like
+// "2: deploying" for quick updates, and `belowdeck kiosk` writes the files
+// from arguments or stdin.
+package kiosk
+
+import (
+	"bufio"
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// pollInterval is how often the directory is checked for changes.
+const pollInterval = time.Second
+
+// stamp identifies a version of a file, to tell when it changes.
+type stamp struct {
+	path string
+	mod  time.Time
+	size int64
+}
+
+// Module implements the kiosk module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+	dir    string
+
+	// State
+	mu     sync.RWMutex
+	images map[string]image.Image // rendered, by place
+	piped  map[string]bool        // places showing a line from the pipe
+	files  map[string]stamp       // the version of each place's file last seen; scan's alone
+
+	// Fonts
+	textFace      font.Face
+	headFace      font.Face
+	bigFace       font.Face
+	stripFace     font.Face
+	stripHeadFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new kiosk module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("kiosk"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "kiosk"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+	m.dir = Dir(m.appCfg)
+	m.images = make(map[string]image.Image)
+	m.piped = make(map[string]bool)
+	m.files = make(map[string]stamp)
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		m.Log().Warn("Failed to create directory", "dir", m.dir, "err", err)
+	}
+	go m.watch(ctx)
+	if m.appCfg != nil && m.appCfg.Kiosk.Pipe != "" {
+		go m.readPipe(ctx, config.ExpandHome(m.appCfg.Kiosk.Pipe))
+	}
+
+	m.Log().Info("Module initialized", "dir", m.dir)
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// watch scans the directory every pollInterval.
+func (m *Module) watch(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		m.scan()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan shows each place's file if it changed since the last scan, and
+// clears places whose file was removed, unless a line from the pipe has
+// replaced it. Where a place has files of more than one type, the newest
+// wins.
+func (m *Module) scan() {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return
+	}
+
+	current := make(map[string]stamp)
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		place := strings.TrimSuffix(e.Name(), ext)
+		if e.IsDir() || !ValidPlace(place) || !slices.Contains(Extensions, ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		s := stamp{path: filepath.Join(m.dir, e.Name()), mod: info.ModTime(), size: info.Size()}
+		if prev, ok := current[place]; !ok || s.mod.After(prev.mod) {
+			current[place] = s
+		}
+	}
+
+	for place, s := range current {
+		if m.files[place] == s {
+			continue
+		}
+		c, err := load(s.path)
+		if err != nil {
+			m.Log().Warn("Failed to load file", "file", s.path, "err", err)
+		}
+		m.show(place, c, false)
+	}
+	for place := range m.files {
+		if _, ok := current[place]; ok {
+			continue
+		}
+		m.mu.RLock()
+		piped := m.piped[place]
+		m.mu.RUnlock()
+		if !piped {
+			m.show(place, content{}, false)
+		}
+	}
+	m.files = current
+}
+
+// readPipe shows each line written to the named pipe at path.
+func (m *Module) readPipe(ctx context.Context, path string) {
+	f, err := openPipe(path)
+	if err != nil {
+		m.Log().Warn("Failed to open pipe", "pipe", path, "err", err)
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		place, text, ok := strings.Cut(scanner.Text(), ":")
+		place = strings.TrimSpace(place)
+		if !ok || !ValidPlace(place) {
+			m.Log().Debug("Ignoring pipe line without a place", "line", scanner.Text())
+			continue
+		}
+		m.show(place, content{lines: parseText(text)}, true)
+	}
+}
+
+// show renders c in place, or clears it if c is empty.
+func (m *Module) show(place string, c content, piped bool) {
+	var img image.Image
+	if c.img != nil || len(c.lines) > 0 {
+		img = m.render(place, c)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if img != nil {
+		m.images[place] = img
+	} else {
+		delete(m.images, place)
+	}
+	m.piped[place] = piped
+}
+
+// render draws c for place, or returns nil if the module has no such key,
+// or no strip.
+func (m *Module) render(place string, c content) image.Image {
+	if place == PlaceStrip {
+		if !m.resources.HasStrip() || !m.resources.Device.HasStrip() {
+			return nil
+		}
+		rect := m.resources.Device.StripRect
+		return m.renderStrip(rect, m.resources.StripRect, c)
+	}
+
+	if _, ok := m.key(place); !ok {
+		return nil
+	}
+	return m.renderKey(c)
+}
+
+// key returns the key a place names.
+func (m *Module) key(place string) (module.KeyID, bool) {
+	n, err := strconv.Atoi(place)
+	if err != nil || n < 1 || n > len(m.resources.Keys) {
+		return 0, false
+	}
+	return m.resources.Keys[n-1], true
+}
+
+// RenderKeys returns the images shown on the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make(map[module.KeyID]image.Image)
+	for place, img := range m.images {
+		if k, ok := m.key(place); ok {
+			keys[k] = img
+		}
+	}
+	return keys
+}
+
+// RenderStrip returns the image shown on the strip, if any.
+func (m *Module) RenderStrip() image.Image {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.images[PlaceStrip]
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
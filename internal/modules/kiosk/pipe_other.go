@@ -0,0 +1,28 @@
+//go:build !windows
+
+package kiosk
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// openPipe creates a named pipe at path, unless one is there already, and
+// opens it. It's opened for writing too, so reads wait for the next writer
+// instead of ending when the last one closes.
+func openPipe(path string) (*os.File, error) {
+	if err := syscall.Mkfifo(path, 0o600); err != nil && !errors.Is(err, fs.ErrExist) {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode()&fs.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("%s exists and is not a named pipe", path)
+	}
+	return os.OpenFile(path, os.O_RDWR, 0)
+}
@@ -0,0 +1,11 @@
+package kiosk
+
+import (
+	"errors"
+	"os"
+)
+
+// openPipe fails: Windows named pipes aren't files a shell can write to.
+func openPipe(path string) (*os.File, error) {
+	return nil, errors.New("named pipes are not supported on Windows")
+}
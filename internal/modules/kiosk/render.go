@@ -0,0 +1,116 @@
+package kiosk
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// Line heights and the most lines a key shows
+const (
+	keyLineHeight   = 14
+	keyLines        = 5
+	stripLineHeight = 20
+)
+
+// row is a wrapped line ready to draw.
+type row struct {
+	text string
+	face font.Face
+	col  color.Color
+}
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.textFace, err = render.NewFace(render.Regular, 12); err != nil {
+		return err
+	}
+	if m.headFace, err = render.NewFace(render.Bold, 13); err != nil {
+		return err
+	}
+	if m.bigFace, err = render.NewFace(render.Bold, 20); err != nil {
+		return err
+	}
+	if m.stripFace, err = render.NewFace(render.Regular, 14); err != nil {
+		return err
+	}
+	if m.stripHeadFace, err = render.NewFace(render.Bold, 16); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderKey draws c on a key: an image scaled to fit, or lines of text
+// centered, with a single short line drawn large.
+func (m *Module) renderKey(c content) image.Image {
+	if c.img != nil {
+		return render.Scale(c.img, render.KeySize)
+	}
+
+	img := render.NewKey(render.ColorKeyBg)
+	maxWidth := render.KeySize - 8
+	if len(c.lines) == 1 && !c.lines[0].heading && font.MeasureString(m.bigFace, c.lines[0].text).Ceil() <= maxWidth {
+		render.DrawTextCentered(img, c.lines[0].text, render.KeySize/2, render.KeySize/2+7, m.bigFace, render.ColorWhite)
+		return img
+	}
+
+	rows := m.wrap(c.lines, m.textFace, m.headFace, maxWidth, keyLines)
+	y := (render.KeySize-len(rows)*keyLineHeight)/2 + 11
+	for _, r := range rows {
+		render.DrawTextCentered(img, r.text, render.KeySize/2, y, r.face, r.col)
+		y += keyLineHeight
+	}
+	return img
+}
+
+// renderStrip draws c in the module's strip region: an image fit to the
+// region and centered, or lines of text from the left.
+func (m *Module) renderStrip(rect, region image.Rectangle, c content) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+
+	if c.img != nil {
+		b := c.img.Bounds()
+		w, h := region.Dx(), region.Dx()*b.Dy()/b.Dx()
+		if h > region.Dy() {
+			w, h = region.Dy()*b.Dx()/b.Dy(), region.Dy()
+		}
+		x, y := region.Min.X+(region.Dx()-w)/2, region.Min.Y+(region.Dy()-h)/2
+		draw.CatmullRom.Scale(img, image.Rect(x, y, x+w, y+h), c.img, b, draw.Over, nil)
+		return img
+	}
+
+	rows := m.wrap(c.lines, m.stripFace, m.stripHeadFace, region.Dx()-24, (region.Dy()-8)/stripLineHeight)
+	y := region.Min.Y + (region.Dy()-len(rows)*stripLineHeight)/2 + 15
+	for _, r := range rows {
+		render.DrawText(img, r.text, region.Min.X+12, y, r.face, r.col)
+		y += stripLineHeight
+	}
+	return img
+}
+
+// wrap breaks lines into at most maxRows rows no wider than maxWidth,
+// headings in headFace and white, the rest in face and gray.
+func (m *Module) wrap(lines []line, face, headFace font.Face, maxWidth, maxRows int) []row {
+	var rows []row
+	for _, l := range lines {
+		f, col := face, color.Color(render.ColorGray)
+		if l.heading || len(lines) == 1 {
+			col = render.ColorWhite
+		}
+		if l.heading {
+			f = headFace
+		}
+		for _, text := range render.WrapText(l.text, f, maxWidth, maxRows-len(rows)) {
+			rows = append(rows, row{text: text, face: f, col: col})
+		}
+		if len(rows) >= maxRows {
+			break
+		}
+	}
+	return rows[:min(len(rows), maxRows)]
+}
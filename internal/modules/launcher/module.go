@@ -0,0 +1,207 @@
+// Package launcher provides a Stream Deck module of config-driven launcher keys.
+package launcher
+
+import (
+	"context"
+	"image"
+
+	"github.com/phinze/belowdeck/internal/action"
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// iconSize is the edge length of a button icon.
+const iconSize = 40
+
+// button is a configured launcher button bound to a key.
+type button struct {
+	action action.Action // nil if misconfigured
+	shift  action.Action // run while the modifier key is held; nil if none
+	key    module.KeyID
+	label  string
+	icon   image.Image // nil if none configured or it failed to load
+
+	// animated is the key drawn with each frame of an animated icon, drawn
+	// once up front; nil if the icon is still
+	animated *render.Animation
+}
+
+// Module implements the launcher module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+
+	buttons []*button
+
+	// Fonts
+	labelFace     font.Face
+	labelOnlyFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new launcher module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("launcher"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "launcher"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var err error
+	if m.labelFace, err = render.NewFace(render.Regular, 12); err != nil {
+		return err
+	}
+	if m.labelOnlyFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+
+	var buttons []config.LauncherButton
+	if m.appCfg != nil {
+		buttons = m.appCfg.Launcher.Buttons
+	}
+	m.bindButtons(buttons)
+
+	m.Log().Info("Module initialized", "buttons", len(m.buttons))
+	return nil
+}
+
+// bindButtons assigns configured buttons to the module's keys in order and
+// loads their icons once up front.
+func (m *Module) bindButtons(buttons []config.LauncherButton) {
+	m.buttons = nil
+	for i, cfg := range buttons {
+		if i >= len(m.resources.Keys) {
+			m.Log().Warn("No key available, skipping", "button", i+1)
+			continue
+		}
+
+		b := &button{key: m.resources.Keys[i], label: cfg.Label}
+		a, err := action.New(cfg.Action, m.appCfg)
+		if err != nil {
+			m.Log().Warn("Invalid action", "button", i+1, "err", err)
+		} else {
+			b.action = a
+			if b.label == "" {
+				b.label = a.Label()
+			}
+		}
+		if cfg.Shift != nil {
+			if b.shift, err = action.New(*cfg.Shift, m.appCfg); err != nil {
+				m.Log().Warn("Invalid shift action", "button", i+1, "err", err)
+			}
+		}
+		if cfg.Icon != "" {
+			icon, err := render.FindIcon(cfg.Icon, iconSize, render.ColorWhite)
+			if err != nil {
+				m.Log().Warn("Failed to load icon", "icon", cfg.Icon, "err", err)
+			}
+			b.icon = icon
+			if anim, ok := icon.(*render.Animation); ok {
+				b.animated = anim.Map(func(frame image.Image) image.Image {
+					return m.drawButton(b.label, frame)
+				})
+			}
+		}
+		m.buttons = append(m.buttons, b)
+	}
+}
+
+// buttonForKey returns the button bound to a key, or nil.
+func (m *Module) buttonForKey(id module.KeyID) *button {
+	for _, b := range m.buttons {
+		if b.key == id {
+			return b
+		}
+	}
+	return nil
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	keys := make(map[module.KeyID]image.Image)
+	for _, b := range m.buttons {
+		keys[b.key] = m.renderButton(b)
+	}
+	return keys
+}
+
+// renderButton draws a button's key.
+func (m *Module) renderButton(b *button) image.Image {
+	if b.animated != nil {
+		return b.animated
+	}
+	return m.drawButton(b.label, b.icon)
+}
+
+// drawButton draws an icon with its label below, or just the label.
+func (m *Module) drawButton(label string, icon image.Image) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	if icon == nil {
+		label = render.TruncateText(label, m.labelOnlyFace, render.KeySize-6)
+		render.DrawTextCentered(img, label, render.KeySize/2, render.KeySize/2+5, m.labelOnlyFace, render.ColorWhite)
+		return img
+	}
+
+	render.DrawIcon(img, icon, 8)
+	label = render.TruncateText(label, m.labelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 64, m.labelFace, render.ColorGray)
+	return img
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey runs the button's action, or its shift action while the modifier
+// key is held, in the background with a spinner on the key.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	b := m.buttonForKey(id)
+	if b == nil {
+		return nil
+	}
+	a := b.action
+	if event.Layer == module.LayerShift && b.shift != nil {
+		a = b.shift
+	}
+	if a == nil {
+		return nil
+	}
+
+	m.Log().Info("Launching", "label", b.label, "layer", event.Layer)
+	m.RunOnKey(id, a.Run)
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// Provider names accepted in mail.provider.
+const (
+	ProviderIMAP  = "imap"
+	ProviderGmail = "gmail"
+)
+
+// latestCount is how many of the newest unread messages are kept per
+// mailbox, one per overlay key.
+const latestCount = 8
+
+// Mailbox is the unread state of one configured mailbox.
+type Mailbox struct {
+	Name   string
+	Unread int
+	Latest []Message // newest first, at most latestCount
+}
+
+// Message is the header summary of one unread message.
+type Message struct {
+	From    string
+	Subject string
+	Date    time.Time
+}
+
+// Provider checks unread mail in the named mailboxes.
+type Provider interface {
+	Name() string
+	Check(ctx context.Context, mailboxes []string) ([]Mailbox, error)
+	// OpenURL returns what pressing a mailbox's key opens: a URL, or an
+	// application name.
+	OpenURL(mailbox string) string
+}
+
+// newProvider returns the provider selected in cfg.
+func newProvider(cfg config.MailConfig) (Provider, error) {
+	if cfg.Password == "" {
+		return nil, fmt.Errorf("no mail password configured (set MAIL_PASSWORD or run 'belowdeck setup')")
+	}
+
+	switch cfg.Provider {
+	case ProviderIMAP:
+		if cfg.Server == "" || cfg.Username == "" {
+			return nil, fmt.Errorf("mail.server and mail.username are required for IMAP")
+		}
+		return newIMAP(cfg), nil
+	case ProviderGmail:
+		if cfg.ClientID == "" || cfg.ClientSecret == "" {
+			return nil, fmt.Errorf("mail.client_id and mail.client_secret are required for Gmail")
+		}
+		return newGmail(cfg), nil
+	case "":
+		return nil, fmt.Errorf("mail.provider is not set (imap or gmail)")
+	default:
+		return nil, fmt.Errorf("unknown mail provider %q (want imap or gmail)", cfg.Provider)
+	}
+}
+
+// defaultMailboxes returns what's checked when mail.mailboxes is empty.
+func defaultMailboxes(provider string) []string {
+	if provider == ProviderGmail {
+		return []string{"in:inbox"}
+	}
+	return []string{"INBOX"}
+}
+
+// headerDecoder decodes RFC 2047 encoded words, e.g. "=?UTF-8?B?...?=".
+var headerDecoder = mime.WordDecoder{}
+
+// decodeHeader decodes an encoded header value, returning it unchanged if
+// it can't be decoded.
+func decodeHeader(s string) string {
+	if decoded, err := headerDecoder.DecodeHeader(s); err == nil {
+		s = decoded
+	}
+	return strings.TrimSpace(s)
+}
+
+// senderName returns the display name from a From header, or the address
+// if there is none.
+func senderName(from string) string {
+	from = decodeHeader(from)
+	if i := strings.Index(from, "<"); i > 0 {
+		if name := strings.Trim(strings.TrimSpace(from[:i]), `"`); name != "" {
+			return name
+		}
+	}
+	return strings.Trim(from, "<>")
+}
@@ -0,0 +1,202 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/httpx"
+)
+
+const (
+	gmailAPI      = "https://gmail.googleapis.com/gmail/v1/users/me"
+	googleToken   = "https://oauth2.googleapis.com/token"
+	gmailInboxURL = "https://mail.google.com/mail/u/0/#search/"
+)
+
+// httpClient is shared by the Gmail provider; callers' contexts bound each request.
+var httpClient = httpx.New("mail", 15*time.Second)
+
+// gmailProvider checks Gmail search queries through the Gmail API,
+// exchanging a refresh token for short-lived access tokens.
+type gmailProvider struct {
+	clientID     string
+	clientSecret string
+	refreshToken string
+	open         string
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+func newGmail(cfg config.MailConfig) *gmailProvider {
+	return &gmailProvider{
+		clientID:     cfg.ClientID,
+		clientSecret: cfg.ClientSecret,
+		refreshToken: cfg.Password,
+		open:         cfg.Open,
+	}
+}
+
+func (p *gmailProvider) Name() string { return "Gmail" }
+
+func (p *gmailProvider) OpenURL(query string) string {
+	if p.open != "" {
+		return p.open
+	}
+	return gmailInboxURL + url.PathEscape(unreadQuery(query))
+}
+
+// unreadQuery narrows a configured query to unread messages.
+func unreadQuery(query string) string {
+	return strings.TrimSpace(query + " is:unread")
+}
+
+func (p *gmailProvider) Check(ctx context.Context, queries []string) ([]Mailbox, error) {
+	token, err := p.token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("refreshing access token: %w", err)
+	}
+
+	result := make([]Mailbox, 0, len(queries))
+	for _, query := range queries {
+		mb, err := p.check(ctx, token, query)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", query, err)
+		}
+		result = append(result, mb)
+	}
+	return result, nil
+}
+
+// check counts unread messages matching query and fetches the newest ones' headers.
+func (p *gmailProvider) check(ctx context.Context, token, query string) (Mailbox, error) {
+	mb := Mailbox{Name: query}
+
+	q := url.Values{}
+	q.Set("q", unreadQuery(query))
+	q.Set("maxResults", "500")
+	var list struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+		ResultSizeEstimate int `json:"resultSizeEstimate"`
+	}
+	if err := p.get(ctx, token, "/messages?"+q.Encode(), &list); err != nil {
+		return mb, err
+	}
+
+	// The estimate can be well off; the listed page is exact when it's complete
+	mb.Unread = max(list.ResultSizeEstimate, len(list.Messages))
+
+	for i, m := range list.Messages {
+		if i >= latestCount {
+			break
+		}
+		path := "/messages/" + m.ID + "?format=metadata&metadataHeaders=From&metadataHeaders=Subject"
+		var msg struct {
+			InternalDate string `json:"internalDate"` // epoch millis
+			Payload      struct {
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+			} `json:"payload"`
+		}
+		if err := p.get(ctx, token, path, &msg); err != nil {
+			return mb, err
+		}
+
+		var summary Message
+		for _, h := range msg.Payload.Headers {
+			switch strings.ToLower(h.Name) {
+			case "from":
+				summary.From = senderName(h.Value)
+			case "subject":
+				summary.Subject = decodeHeader(h.Value)
+			}
+		}
+		var millis int64
+		if _, err := fmt.Sscan(msg.InternalDate, &millis); err == nil {
+			summary.Date = time.UnixMilli(millis)
+		}
+		mb.Latest = append(mb.Latest, summary)
+	}
+	return mb, nil
+}
+
+// get fetches path under the Gmail API and decodes the JSON response into v.
+func (p *gmailProvider) get(ctx context.Context, token, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", gmailAPI+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusUnauthorized {
+			p.mu.Lock()
+			p.accessToken = ""
+			p.mu.Unlock()
+		}
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// token returns a valid access token, refreshing it when it's about to expire.
+func (p *gmailProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Until(p.expiry) > time.Minute {
+		return p.accessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", p.refreshToken)
+	form.Set("client_id", p.clientID)
+	form.Set("client_secret", p.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", googleToken, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("token response: %s", resp.Status)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("token refresh failed: %s", result.Error)
+	}
+
+	p.accessToken = result.AccessToken
+	p.expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
@@ -0,0 +1,246 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// imapTimeout bounds a whole check, connect to logout.
+const imapTimeout = 30 * time.Second
+
+// imapProvider checks mailboxes over IMAP with TLS. It speaks just enough
+// of the protocol for LOGIN, STATUS, EXAMINE, SEARCH, and header FETCHes, and
+// connects fresh for each check rather than holding an idle connection.
+type imapProvider struct {
+	server   string
+	username string
+	password string
+	open     string
+}
+
+func newIMAP(cfg config.MailConfig) *imapProvider {
+	server := cfg.Server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "993")
+	}
+	return &imapProvider{
+		server:   server,
+		username: cfg.Username,
+		password: cfg.Password,
+		open:     cfg.Open,
+	}
+}
+
+func (p *imapProvider) Name() string { return "IMAP" }
+
+func (p *imapProvider) OpenURL(mailbox string) string {
+	if p.open != "" {
+		return p.open
+	}
+	return "Mail"
+}
+
+func (p *imapProvider) Check(ctx context.Context, mailboxes []string) ([]Mailbox, error) {
+	ctx, cancel := context.WithTimeout(ctx, imapTimeout)
+	defer cancel()
+
+	c, err := dialIMAP(ctx, p.server)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	if _, err := c.cmd("LOGIN %s %s", quote(p.username), quote(p.password)); err != nil {
+		return nil, fmt.Errorf("login: %w", err)
+	}
+
+	result := make([]Mailbox, 0, len(mailboxes))
+	for _, name := range mailboxes {
+		mb, err := c.check(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result = append(result, mb)
+	}
+
+	c.cmd("LOGOUT")
+	return result, nil
+}
+
+// imapConn is one IMAP session.
+type imapConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+func dialIMAP(ctx context.Context, server string) (*imapConn, error) {
+	host, _, _ := net.SplitHostPort(server)
+	d := &tls.Dialer{Config: &tls.Config{ServerName: host}}
+	conn, err := d.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c := &imapConn{conn: conn, r: bufio.NewReader(conn)}
+	greeting, err := c.readLine()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected greeting: %s", greeting)
+	}
+	return c, nil
+}
+
+func (c *imapConn) close() error {
+	return c.conn.Close()
+}
+
+// cmd sends a command and returns its untagged responses, with any literals
+// inlined. A NO or BAD completion is returned as an error.
+func (c *imapConn) cmd(format string, args ...any) ([]string, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+
+	var untagged []string
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if rest, ok := strings.CutPrefix(line, tag+" "); ok {
+			if strings.HasPrefix(rest, "OK") {
+				return untagged, nil
+			}
+			return nil, fmt.Errorf("%s", rest)
+		}
+		if strings.HasPrefix(line, "* ") {
+			untagged = append(untagged, line[2:])
+		}
+	}
+}
+
+// literalSuffix matches the "{n}" that announces an n-byte literal.
+var literalSuffix = regexp.MustCompile(`\{(\d+)\}$`)
+
+// readLine reads one response line, reading any literals it announces into
+// the line along with the rest of the response that follows them.
+func (c *imapConn) readLine() (string, error) {
+	var b strings.Builder
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		b.WriteString(line)
+
+		m := literalSuffix.FindStringSubmatch(line)
+		if m == nil {
+			return b.String(), nil
+		}
+		n, _ := strconv.Atoi(m[1])
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return "", err
+		}
+		b.WriteString("\n")
+		b.Write(literal)
+	}
+}
+
+// unseenCount matches the UNSEEN count in a STATUS response.
+var unseenCount = regexp.MustCompile(`UNSEEN (\d+)`)
+
+// check counts a mailbox's unread messages and fetches the newest ones' headers.
+func (c *imapConn) check(name string) (Mailbox, error) {
+	mb := Mailbox{Name: name}
+
+	lines, err := c.cmd("STATUS %s (UNSEEN)", quote(name))
+	if err != nil {
+		return mb, err
+	}
+	for _, line := range lines {
+		if m := unseenCount.FindStringSubmatch(line); m != nil {
+			mb.Unread, _ = strconv.Atoi(m[1])
+		}
+	}
+	if mb.Unread == 0 {
+		return mb, nil
+	}
+
+	if _, err := c.cmd("EXAMINE %s", quote(name)); err != nil {
+		return mb, err
+	}
+	lines, err = c.cmd("SEARCH UNSEEN")
+	if err != nil {
+		return mb, err
+	}
+	var ids []string
+	for _, line := range lines {
+		if rest, ok := strings.CutPrefix(line, "SEARCH"); ok {
+			ids = append(ids, strings.Fields(rest)...)
+		}
+	}
+	if len(ids) > latestCount {
+		ids = ids[len(ids)-latestCount:]
+	}
+	if len(ids) == 0 {
+		return mb, nil
+	}
+
+	lines, err = c.cmd("FETCH %s (BODY.PEEK[HEADER.FIELDS (FROM SUBJECT DATE)])", strings.Join(ids, ","))
+	if err != nil {
+		return mb, err
+	}
+	for _, line := range lines {
+		// The headers are the literal after the first line break
+		_, headers, ok := strings.Cut(line, "\n")
+		if !ok {
+			continue
+		}
+		msg, err := mail.ReadMessage(strings.NewReader(headers + "\r\n"))
+		if err != nil {
+			continue
+		}
+		date, _ := msg.Header.Date()
+		mb.Latest = append(mb.Latest, Message{
+			From:    senderName(msg.Header.Get("From")),
+			Subject: decodeHeader(msg.Header.Get("Subject")),
+			Date:    date,
+		})
+	}
+
+	// Sequence numbers ascend with arrival, so reverse for newest first
+	for i, j := 0, len(mb.Latest)-1; i < j; i, j = i+1, j-1 {
+		mb.Latest[i], mb.Latest[j] = mb.Latest[j], mb.Latest[i]
+	}
+	return mb, nil
+}
+
+// quote returns s as an IMAP quoted string.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
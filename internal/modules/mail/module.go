@@ -0,0 +1,334 @@
+// Package mail provides a Stream Deck module for unread mail counts from an
+// IMAP server or Gmail.
+package mail
+
+import (
+	"context"
+	"image"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	// pollInterval is how often mailboxes are checked unless configured.
+	pollInterval = time.Minute
+
+	// longPressDuration is how long a key must be held to show its latest messages.
+	longPressDuration = 500 * time.Millisecond
+
+	// overlayTimeout is how long the overlay stays up without input.
+	overlayTimeout = 8 * time.Second
+)
+
+// Module implements the mail module.
+type Module struct {
+	module.BaseModule
+	module.Freshness
+
+	appCfg    *config.Config
+	interval  time.Duration // between fetches
+	provider  Provider
+	mailboxes []string
+
+	mu     sync.RWMutex
+	state  []Mailbox // parallel to mailboxes once loaded
+	loaded bool
+
+	// Overlay state: index of the mailbox shown, -1 when closed
+	overlayIndex int
+
+	// Fonts
+	labelFace  font.Face
+	fromFace   font.Face
+	textFace   font.Face
+	stripFace  font.Face
+	stripLabel font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new mail module.
+func New(appCfg *config.Config) *Module {
+	interval := appCfg.PollInterval("mail", pollInterval)
+	return &Module{
+		BaseModule:   module.NewBaseModule("mail"),
+		Freshness:    module.Freshness{StaleAfter: 3 * interval},
+		appCfg:       appCfg,
+		interval:     interval,
+		overlayIndex: -1,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "mail"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var cfg config.MailConfig
+	if m.appCfg != nil {
+		cfg = m.appCfg.Mail
+	}
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return err
+	}
+	m.provider = provider
+
+	m.mailboxes = cfg.Mailboxes
+	if len(m.mailboxes) == 0 {
+		m.mailboxes = defaultMailboxes(cfg.Provider)
+	}
+	if len(m.mailboxes) > len(res.Keys) {
+		m.Log().Warn("More mailboxes than keys, ignoring the rest", "mailboxes", len(m.mailboxes), "keys", len(res.Keys))
+		m.mailboxes = m.mailboxes[:len(res.Keys)]
+	}
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	go m.pollMail(ctx)
+
+	m.Log().Info("Module initialized", "provider", provider.Name(), "mailboxes", len(m.mailboxes))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// pollMail checks the mailboxes now and every interval.
+func (m *Module) pollMail(ctx context.Context) {
+	m.checkMail(ctx)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkMail(ctx)
+		}
+	}
+}
+
+// checkMail refreshes unread state, keeping the previous state on failure.
+func (m *Module) checkMail(ctx context.Context) {
+	start := time.Now()
+	state, err := m.provider.Check(ctx, m.mailboxes)
+	metrics.ObserveFetch(m.ID(), start, err)
+	m.Fetched(err)
+	if err != nil {
+		m.Log().Warn("Failed to check mail", "err", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.state = state
+	m.loaded = true
+	m.mu.Unlock()
+}
+
+// mailbox returns the state of mailbox i and whether it has been checked.
+func (m *Module) mailbox(i int) (Mailbox, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.loaded || i >= len(m.state) {
+		return Mailbox{Name: m.mailboxes[i]}, false
+	}
+	return m.state[i], true
+}
+
+// keyIndex returns the mailbox index for key id, or -1.
+func (m *Module) keyIndex(id module.KeyID) int {
+	for i := range m.mailboxes {
+		if m.resources.Keys[i] == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	keys := make(map[module.KeyID]image.Image)
+	for i := range m.mailboxes {
+		mb, loaded := m.mailbox(i)
+		keys[m.resources.Keys[i]] = m.renderMailboxKey(mb, loaded)
+	}
+	return keys
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey opens the mailbox on a short press and shows its latest unread
+// messages on a long press.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if event.Pressed {
+		return nil
+	}
+	i := m.keyIndex(id)
+	if i < 0 {
+		return nil
+	}
+
+	if event.Duration >= longPressDuration {
+		m.mu.Lock()
+		m.overlayIndex = i
+		m.mu.Unlock()
+		return nil
+	}
+
+	m.open(m.mailboxes[i])
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
+
+// IsOverlayActive returns true while the latest messages are shown.
+func (m *Module) IsOverlayActive() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.overlayIndex >= 0
+}
+
+// OverlayTimeout returns how long the overlay stays up without input.
+func (m *Module) OverlayTimeout() time.Duration {
+	return overlayTimeout
+}
+
+// CloseOverlay dismisses the latest messages.
+func (m *Module) CloseOverlay() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.overlayIndex = -1
+}
+
+// HandleOverlayKey opens the mailbox from any message key and closes the overlay.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	m.mu.Lock()
+	i := m.overlayIndex
+	m.overlayIndex = -1
+	m.mu.Unlock()
+
+	if i >= 0 {
+		m.open(m.mailboxes[i])
+	}
+	return nil
+}
+
+// HandleOverlayDial closes the overlay on any dial press.
+func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
+	if event.Type == module.DialRelease {
+		m.mu.Lock()
+		m.overlayIndex = -1
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch closes the overlay on a tap.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	if event.Type == module.TouchTap {
+		m.mu.Lock()
+		m.overlayIndex = -1
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// RenderOverlayKeys shows the overlay mailbox's latest unread messages, one per key.
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	m.mu.RLock()
+	i := m.overlayIndex
+	m.mu.RUnlock()
+	if i < 0 {
+		return nil
+	}
+
+	mb, _ := m.mailbox(i)
+	keys := make(map[module.KeyID]image.Image)
+	for k := range latestCount {
+		keyID := module.Key1 + module.KeyID(k)
+		if k < len(mb.Latest) {
+			keys[keyID] = m.renderMessageKey(mb.Latest[k])
+		} else {
+			keys[keyID] = m.renderEmptyKey()
+		}
+	}
+	return keys
+}
+
+// RenderOverlayStrip shows which mailbox the overlay is listing.
+func (m *Module) RenderOverlayStrip() image.Image {
+	m.mu.RLock()
+	i := m.overlayIndex
+	m.mu.RUnlock()
+	if i < 0 {
+		return nil
+	}
+
+	mb, _ := m.mailbox(i)
+	return m.renderOverlayStrip(mb)
+}
+
+// open opens a mailbox in the browser or mail app.
+func (m *Module) open(mailbox string) {
+	target := m.provider.OpenURL(mailbox)
+	args := []string{target}
+	if !strings.Contains(target, "://") {
+		args = []string{"-a", target}
+	}
+	if err := exec.Command("open", args...).Start(); err != nil {
+		m.Log().Warn("Failed to open mailbox", "target", target, "err", err)
+	}
+}
+
+// displayName returns a short label for a mailbox name or query, e.g.
+// "INBOX" -> "Inbox", "label:work" -> "work".
+func displayName(mailbox string) string {
+	if _, value, ok := strings.Cut(mailbox, ":"); ok && !strings.Contains(mailbox, " ") {
+		mailbox = value
+	}
+	if strings.EqualFold(mailbox, "inbox") {
+		return "Inbox"
+	}
+	if i := strings.LastIndex(mailbox, "/"); i >= 0 {
+		mailbox = mailbox[i+1:]
+	}
+	return mailbox
+}
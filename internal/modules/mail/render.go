@@ -0,0 +1,145 @@
+package mail
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// Colors
+var (
+	colorStripBg = color.RGBA{30, 30, 30, 255}
+	colorDimGray = color.RGBA{110, 110, 110, 255}
+	colorBadge   = color.RGBA{230, 60, 50, 255}
+	colorUnread  = color.RGBA{80, 150, 240, 255}
+)
+
+const iconSize = 32
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Bold, 12); err != nil {
+		return err
+	}
+	if m.fromFace, err = render.NewFace(render.Bold, 11); err != nil {
+		return err
+	}
+	if m.textFace, err = render.NewFace(render.Regular, 10); err != nil {
+		return err
+	}
+	if m.stripFace, err = render.NewFace(render.Bold, 18); err != nil {
+		return err
+	}
+	if m.stripLabel, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderMailboxKey renders a mailbox's envelope icon with an unread badge.
+func (m *Module) renderMailboxKey(mb Mailbox, loaded bool) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	iconColor := render.ColorWhite
+	if loaded && mb.Unread == 0 {
+		iconColor = render.ColorGray
+	}
+	render.DrawIcon(img, render.Icon("mail", iconSize, iconColor), 10)
+
+	label := render.TruncateText(displayName(mb.Name), m.labelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 62, m.labelFace, colorDimGray)
+
+	if loaded && mb.Unread > 0 {
+		m.drawBadge(img, mb.Unread)
+	}
+	return img
+}
+
+// drawBadge draws the unread count in a red pill over the icon's top-right corner.
+func (m *Module) drawBadge(img *image.RGBA, count int) {
+	text := fmt.Sprintf("%d", count)
+	if count > 999 {
+		text = "999+"
+	}
+	width := max(font.MeasureString(m.labelFace, text).Ceil()+10, 18)
+	const height = 18
+	right := render.KeySize - 6
+	rect := image.Rect(right-width, 4, right, 4+height)
+
+	// Pill: a rectangle with round ends
+	r := height / 2
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			dx := 0
+			if x < rect.Min.X+r {
+				dx = rect.Min.X + r - x
+			} else if x >= rect.Max.X-r {
+				dx = x - (rect.Max.X - r - 1)
+			}
+			dy := y - (rect.Min.Y + r)
+			if dx*dx+dy*dy <= r*r {
+				img.Set(x, y, colorBadge)
+			}
+		}
+	}
+	render.DrawTextCentered(img, text, (rect.Min.X+rect.Max.X)/2, rect.Max.Y-5, m.labelFace, render.ColorWhite)
+}
+
+// renderMessageKey renders one unread message's sender, age, and subject.
+func (m *Module) renderMessageKey(msg Message) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+	draw.Draw(img, image.Rect(0, 0, render.KeySize, 4), &image.Uniform{colorUnread}, image.Point{}, draw.Src)
+
+	from, subject := msg.From, msg.Subject
+	redact := m.Redaction()
+	if redact == module.RedactGeneric {
+		from, subject = "Sender", "Message"
+	}
+
+	const maxWidth = render.KeySize - 8
+	render.DrawText(img, render.TruncateText(from, m.fromFace, maxWidth), 4, 17, m.fromFace, render.ColorWhite)
+	if !msg.Date.IsZero() {
+		render.DrawText(img, render.FormatAge(time.Since(msg.Date)), 4, 29, m.textFace, colorDimGray)
+	}
+
+	y := 42
+	for _, line := range render.WrapText(subject, m.textFace, maxWidth, 3) {
+		render.DrawText(img, line, 4, y, m.textFace, render.ColorGray)
+		y += 11
+	}
+	if redact == module.RedactBlur {
+		render.Blur(img, image.Rect(0, 6, render.KeySize, 20))
+		render.Blur(img, image.Rect(0, 33, render.KeySize, render.KeySize))
+	}
+	return img
+}
+
+// renderEmptyKey renders an empty key for the overlay.
+func (m *Module) renderEmptyKey() image.Image {
+	return render.NewKey(render.ColorKeyBg)
+}
+
+// renderOverlayStrip names the mailbox being listed and how to dismiss it.
+func (m *Module) renderOverlayStrip(mb Mailbox) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 100))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorStripBg}, image.Point{}, draw.Src)
+
+	render.DrawText(img, displayName(mb.Name), 20, 45, m.stripFace, render.ColorWhite)
+	summary := fmt.Sprintf("%d unread", mb.Unread)
+	if mb.Unread > len(mb.Latest) && len(mb.Latest) > 0 {
+		summary += fmt.Sprintf(", newest %d shown", len(mb.Latest))
+	}
+	render.DrawText(img, summary, 20, 72, m.stripLabel, colorDimGray)
+
+	render.DrawTextCentered(img, "press=open", 700, 45, m.stripLabel, colorDimGray)
+	render.DrawTextCentered(img, "click=back", 700, 72, m.stripLabel, colorDimGray)
+	return img
+}
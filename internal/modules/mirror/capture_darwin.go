@@ -0,0 +1,250 @@
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+	"github.com/ebitengine/purego/objc"
+)
+
+// cgRect mirrors CoreGraphics' CGRect.
+type cgRect struct {
+	X, Y, Width, Height float64
+}
+
+// contains reports whether the point (x, y) is inside r.
+func (r cgRect) contains(x, y float64) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// kCGImageAlphaPremultipliedLast lays out bitmap pixels as RGBA bytes, as
+// image.RGBA holds them.
+const kCGImageAlphaPremultipliedLast = 1
+
+// purego function bindings
+var (
+	cfRelease func(cf uintptr)
+
+	cgImageGetWidth                func(img uintptr) uintptr
+	cgImageGetHeight               func(img uintptr) uintptr
+	cgColorSpaceCreateDeviceRGB    func() uintptr
+	cgBitmapContextCreate          func(data unsafe.Pointer, width, height, bitsPerComponent, bytesPerRow, space uintptr, bitmapInfo uint32) uintptr
+	cgContextDrawImage             func(ctx uintptr, rect cgRect, img uintptr)
+	cgPreflightScreenCaptureAccess func() bool
+	cgRequestScreenCaptureAccess   func() bool
+)
+
+var (
+	loadOnce sync.Once
+	loadErr  error
+
+	selAlloc           = objc.RegisterName("alloc")
+	selNew             = objc.RegisterName("new")
+	selRelease         = objc.RegisterName("release")
+	selRetain          = objc.RegisterName("retain")
+	selArray           = objc.RegisterName("array")
+	selCount           = objc.RegisterName("count")
+	selObjectAtIndex   = objc.RegisterName("objectAtIndex:")
+	selDisplays        = objc.RegisterName("displays")
+	selFrame           = objc.RegisterName("frame")
+	selInitWithDisplay = objc.RegisterName("initWithDisplay:excludingWindows:")
+	selSetSourceRect   = objc.RegisterName("setSourceRect:")
+	selSetWidth        = objc.RegisterName("setWidth:")
+	selSetHeight       = objc.RegisterName("setHeight:")
+	selSetShowsCursor  = objc.RegisterName("setShowsCursor:")
+	selGetContent      = objc.RegisterName("getShareableContentWithCompletionHandler:")
+	selCaptureImage    = objc.RegisterName("captureImageWithFilter:configuration:completionHandler:")
+	selDescription     = objc.RegisterName("localizedDescription")
+	selUTF8String      = objc.RegisterName("UTF8String")
+)
+
+// load loads ScreenCaptureKit and the CoreGraphics functions that turn its
+// images into Go ones.
+func load() error {
+	loadOnce.Do(func() {
+		if _, err := purego.Dlopen("/System/Library/Frameworks/ScreenCaptureKit.framework/ScreenCaptureKit", purego.RTLD_LAZY|purego.RTLD_GLOBAL); err != nil {
+			loadErr = fmt.Errorf("loading ScreenCaptureKit: %w", err)
+			return
+		}
+		// SCScreenshotManager arrived in macOS 14
+		if objc.GetClass("SCScreenshotManager") == 0 {
+			loadErr = errors.New("screen capture requires macOS 14 or later")
+			return
+		}
+
+		cf, err := purego.Dlopen("/System/Library/Frameworks/CoreFoundation.framework/CoreFoundation", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+		if err != nil {
+			loadErr = fmt.Errorf("loading CoreFoundation: %w", err)
+			return
+		}
+		purego.RegisterLibFunc(&cfRelease, cf, "CFRelease")
+
+		cg, err := purego.Dlopen("/System/Library/Frameworks/CoreGraphics.framework/CoreGraphics", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+		if err != nil {
+			loadErr = fmt.Errorf("loading CoreGraphics: %w", err)
+			return
+		}
+		purego.RegisterLibFunc(&cgImageGetWidth, cg, "CGImageGetWidth")
+		purego.RegisterLibFunc(&cgImageGetHeight, cg, "CGImageGetHeight")
+		purego.RegisterLibFunc(&cgColorSpaceCreateDeviceRGB, cg, "CGColorSpaceCreateDeviceRGB")
+		purego.RegisterLibFunc(&cgBitmapContextCreate, cg, "CGBitmapContextCreate")
+		purego.RegisterLibFunc(&cgContextDrawImage, cg, "CGContextDrawImage")
+		purego.RegisterLibFunc(&cgPreflightScreenCaptureAccess, cg, "CGPreflightScreenCaptureAccess")
+		purego.RegisterLibFunc(&cgRequestScreenCaptureAccess, cg, "CGRequestScreenCaptureAccess")
+	})
+	return loadErr
+}
+
+// capturer captures one rectangle of the screen with ScreenCaptureKit.
+type capturer struct {
+	filter objc.ID // SCContentFilter for the display holding the rectangle
+	config objc.ID // SCStreamConfiguration cropping to it
+}
+
+// newCapturer prepares to capture rect, in points from the top left of the
+// main display. The first call asks for Screen Recording permission if the
+// process doesn't have it.
+func newCapturer(ctx context.Context, rect image.Rectangle) (*capturer, error) {
+	if err := load(); err != nil {
+		return nil, err
+	}
+	if !cgPreflightScreenCaptureAccess() {
+		cgRequestScreenCaptureAccess()
+		return nil, errors.New("needs Screen Recording permission (System Settings > Privacy & Security)")
+	}
+
+	// Completions run on a dispatch queue; ScreenCaptureKit keeps its own
+	// copy of each block, so releasing ours early on a timeout is safe
+	type result struct {
+		display objc.ID
+		frame   cgRect
+		err     error
+	}
+	ch := make(chan result, 1)
+	block := objc.NewBlock(func(_ objc.Block, content, nsErr objc.ID) {
+		if content == 0 {
+			ch <- result{err: nsError("listing displays", nsErr)}
+			return
+		}
+		displays := content.Send(selDisplays)
+		for i := range objc.Send[uint64](displays, selCount) {
+			d := displays.Send(selObjectAtIndex, i)
+			frame := objc.Send[cgRect](d, selFrame)
+			if frame.contains(float64(rect.Min.X), float64(rect.Min.Y)) {
+				ch <- result{display: d.Send(selRetain), frame: frame}
+				return
+			}
+		}
+		ch <- result{err: fmt.Errorf("no display contains %d,%d", rect.Min.X, rect.Min.Y)}
+	})
+	defer block.Release()
+	objc.ID(objc.GetClass("SCShareableContent")).Send(selGetContent, block)
+
+	var r result
+	select {
+	case r = <-ch:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	defer r.display.Send(selRelease)
+
+	noWindows := objc.ID(objc.GetClass("NSArray")).Send(selArray)
+	filter := objc.ID(objc.GetClass("SCContentFilter")).Send(selAlloc).Send(selInitWithDisplay, r.display, noWindows)
+
+	// sourceRect is relative to the display; output is one pixel per point
+	config := objc.ID(objc.GetClass("SCStreamConfiguration")).Send(selNew)
+	config.Send(selSetSourceRect, cgRect{
+		X:      float64(rect.Min.X) - r.frame.X,
+		Y:      float64(rect.Min.Y) - r.frame.Y,
+		Width:  float64(rect.Dx()),
+		Height: float64(rect.Dy()),
+	})
+	config.Send(selSetWidth, uint64(rect.Dx()))
+	config.Send(selSetHeight, uint64(rect.Dy()))
+	config.Send(selSetShowsCursor, false)
+
+	return &capturer{filter: filter, config: config}, nil
+}
+
+// capture takes a picture of the rectangle.
+func (c *capturer) capture(ctx context.Context) (image.Image, error) {
+	type result struct {
+		img *image.RGBA
+		err error
+	}
+	ch := make(chan result, 1)
+	// The image is only valid during the completion, so copy it there
+	block := objc.NewBlock(func(_ objc.Block, cgImage uintptr, nsErr objc.ID) {
+		if cgImage == 0 {
+			ch <- result{err: nsError("capturing", nsErr)}
+			return
+		}
+		ch <- result{img: toRGBA(cgImage)}
+	})
+	defer block.Release()
+	objc.ID(objc.GetClass("SCScreenshotManager")).Send(selCaptureImage, c.filter, c.config, block)
+
+	select {
+	case r := <-ch:
+		return r.img, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// close releases the capturer's ScreenCaptureKit objects.
+func (c *capturer) close() {
+	c.filter.Send(selRelease)
+	c.config.Send(selRelease)
+}
+
+// toRGBA draws a CGImage into a new image.RGBA.
+func toRGBA(cgImage uintptr) *image.RGBA {
+	w, h := cgImageGetWidth(cgImage), cgImageGetHeight(cgImage)
+	img := image.NewRGBA(image.Rect(0, 0, int(w), int(h)))
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	space := cgColorSpaceCreateDeviceRGB()
+	defer cfRelease(space)
+	ctx := cgBitmapContextCreate(unsafe.Pointer(&img.Pix[0]), w, h, 8, uintptr(img.Stride), space, kCGImageAlphaPremultipliedLast)
+	if ctx == 0 {
+		return img
+	}
+	defer cfRelease(ctx)
+	cgContextDrawImage(ctx, cgRect{Width: float64(w), Height: float64(h)}, cgImage)
+	return img
+}
+
+// nsError describes an NSError from a completion, which may be nil.
+func nsError(doing string, nsErr objc.ID) error {
+	if nsErr == 0 {
+		return fmt.Errorf("%s failed", doing)
+	}
+	return fmt.Errorf("%s: %s", doing, goString(nsErr.Send(selDescription)))
+}
+
+// goString copies an NSString.
+func goString(s objc.ID) string {
+	if s == 0 {
+		return ""
+	}
+	p := objc.Send[*byte](s, selUTF8String)
+	if p == nil {
+		return ""
+	}
+	var n int
+	for *(*byte)(unsafe.Add(unsafe.Pointer(p), n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice(p, n))
+}
@@ -0,0 +1,21 @@
+//go:build !darwin
+
+package mirror
+
+import (
+	"context"
+	"errors"
+	"image"
+)
+
+var errUnsupported = errors.New("screen capture requires macOS")
+
+// capturer is a stand-in; screen capture needs ScreenCaptureKit.
+type capturer struct{}
+
+func newCapturer(ctx context.Context, rect image.Rectangle) (*capturer, error) {
+	return nil, errUnsupported
+}
+
+func (c *capturer) capture(ctx context.Context) (image.Image, error) { return nil, errUnsupported }
+func (c *capturer) close()                                           {}
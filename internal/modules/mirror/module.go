@@ -0,0 +1,196 @@
+// Package mirror provides a Stream Deck module that mirrors a rectangle of
+// the screen onto the touch strip, for status that apps show but don't
+// offer an API for, like a build bar or a chat badge.
+package mirror
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	defaultFPS = 2
+
+	// captureTimeout bounds a single capture, and retryInterval is how long
+	// to wait before setting up capture again after it fails.
+	captureTimeout = 5 * time.Second
+	retryInterval  = 10 * time.Second
+)
+
+// Module implements the screen mirror module.
+type Module struct {
+	module.BaseModule
+
+	appCfg  *config.Config
+	rect    image.Rectangle
+	enabled bool
+
+	// State
+	mu      sync.RWMutex
+	frame   image.Image
+	lastErr string // last capture error, shown while there's no frame
+
+	// Fonts
+	messageFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new screen mirror module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("mirror"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "mirror"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var cfg config.MirrorConfig
+	if m.appCfg != nil {
+		cfg = m.appCfg.Mirror
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		m.Log().Warn("Module disabled", "err", "mirror.width and mirror.height not set")
+		m.enabled = false
+		return nil
+	}
+	if !res.HasStrip() {
+		m.Log().Warn("Module disabled", "err", "no strip region assigned")
+		m.enabled = false
+		return nil
+	}
+	m.enabled = true
+	m.rect = image.Rect(cfg.X, cfg.Y, cfg.X+cfg.Width, cfg.Y+cfg.Height)
+
+	fps := cfg.FPS
+	if fps == 0 {
+		fps = defaultFPS
+	}
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	go m.run(ctx, time.Duration(float64(time.Second)/fps))
+
+	m.Log().Info("Module initialized", "rect", m.rect, "fps", fps)
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// run captures the rectangle every interval, setting capture up again
+// after a pause whenever it fails, as it does when displays change.
+func (m *Module) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var c *capturer
+	defer func() {
+		if c != nil {
+			c.close()
+		}
+	}()
+
+	for {
+		wait := ticker.C
+		if c == nil {
+			setupCtx, cancel := context.WithTimeout(ctx, captureTimeout)
+			var err error
+			c, err = newCapturer(setupCtx, m.rect)
+			cancel()
+			if err != nil {
+				m.setError(err)
+				wait = time.After(retryInterval)
+			}
+		}
+
+		if c != nil {
+			captureCtx, cancel := context.WithTimeout(ctx, captureTimeout)
+			frame, err := c.capture(captureCtx)
+			cancel()
+			if err != nil {
+				m.setError(err)
+				c.close()
+				c = nil
+				wait = time.After(retryInterval)
+			} else {
+				m.mu.Lock()
+				m.frame, m.lastErr = frame, ""
+				m.mu.Unlock()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wait:
+		}
+	}
+}
+
+// setError records a capture failure, logging it when it's new.
+func (m *Module) setError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err.Error() != m.lastErr {
+		m.Log().Warn("Screen capture failed", "err", err)
+		m.lastErr = err.Error()
+	}
+}
+
+// RenderKeys returns nil; the module only uses the strip.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	return nil
+}
+
+// RenderStrip returns the latest capture, or why there isn't one.
+func (m *Module) RenderStrip() image.Image {
+	if !m.enabled || !m.resources.Device.HasStrip() {
+		return nil
+	}
+
+	rect := m.resources.Device.StripRect
+
+	m.mu.RLock()
+	frame, lastErr := m.frame, m.lastErr
+	m.mu.RUnlock()
+	return m.renderStrip(rect, m.resources.StripRect, frame, lastErr)
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
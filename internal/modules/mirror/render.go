@@ -0,0 +1,41 @@
+package mirror
+
+import (
+	"image"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+)
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	m.messageFace, err = render.NewFace(render.Regular, 13)
+	return err
+}
+
+// renderStrip draws the frame fit to the module's strip region and
+// centered, or, before the first frame, the last error.
+func (m *Module) renderStrip(rect, region image.Rectangle, frame image.Image, lastErr string) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+
+	if frame == nil || frame.Bounds().Empty() {
+		msg := "Capturing..."
+		if lastErr != "" {
+			msg = lastErr
+		}
+		msg = render.TruncateText(msg, m.messageFace, region.Dx()-20)
+		render.DrawTextCentered(img, msg, region.Min.X+region.Dx()/2, region.Min.Y+region.Dy()/2+5, m.messageFace, render.ColorGray)
+		return img
+	}
+
+	b := frame.Bounds()
+	w, h := region.Dx(), region.Dx()*b.Dy()/b.Dx()
+	if h > region.Dy() {
+		w, h = region.Dy()*b.Dx()/b.Dy(), region.Dy()
+	}
+	x, y := region.Min.X+(region.Dx()-w)/2, region.Min.Y+(region.Dy()-h)/2
+	draw.ApproxBiLinear.Scale(img, image.Rect(x, y, x+w, y+h), frame, b, draw.Src, nil)
+	return img
+}
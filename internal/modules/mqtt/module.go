@@ -0,0 +1,271 @@
+// Package mqtt provides a Stream Deck module for generic IoT state and commands over MQTT.
+package mqtt
+
+import (
+	"context"
+	"image"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// tileBinding ties a configured tile to the key or dial that displays/controls it.
+type tileBinding struct {
+	tile config.MQTTTile
+	key  module.KeyID  // 0 for strip tiles
+	dial module.DialID // 0 if no dial is bound
+}
+
+// Module implements the MQTT module.
+type Module struct {
+	module.BaseModule
+
+	appCfg  *config.Config
+	client  paho.Client
+	enabled bool
+
+	tiles []*tileBinding
+
+	// State: latest display text per topic
+	mu     sync.RWMutex
+	values map[string]string
+
+	// Fonts
+	labelFace      font.Face
+	valueFace      font.Face
+	stripLabelFace font.Face
+	stripValueFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new MQTT module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("mqtt"),
+		appCfg:     appCfg,
+		values:     make(map[string]string),
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "mqtt"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	if m.appCfg == nil || m.appCfg.MQTT.Broker == "" {
+		m.Log().Info("Module disabled: broker not configured")
+		m.enabled = false
+		return nil
+	}
+	cfg := m.appCfg.MQTT
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	m.bindTiles(cfg.Tiles)
+
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = "belowdeck"
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(clientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetOnConnectHandler(m.onConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			m.Log().Warn("Connection lost", "err", err)
+		})
+
+	m.client = paho.NewClient(opts)
+	// With connect retry enabled, Connect returns immediately and keeps trying
+	// in the background; subscriptions are (re)established in onConnect.
+	m.client.Connect()
+	m.enabled = true
+
+	m.Log().Info("Module initialized", "broker", cfg.Broker, "tiles", len(m.tiles))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	if m.client != nil {
+		m.client.Disconnect(250)
+	}
+	return m.BaseModule.Stop()
+}
+
+// bindTiles assigns key tiles to the module's keys and dial-publishing tiles to
+// its dials, in order. Strip tiles share the module's strip segment.
+func (m *Module) bindTiles(tiles []config.MQTTTile) {
+	m.tiles = nil
+	nextKey, nextDial := 0, 0
+
+	for _, t := range tiles {
+		if t.Topic == "" {
+			m.Log().Warn("Tile has no topic, skipping", "tile", t.Label)
+			continue
+		}
+
+		b := &tileBinding{tile: t}
+		if !t.Strip {
+			if nextKey >= len(m.resources.Keys) {
+				m.Log().Warn("No key available, skipping", "tile", t.Label)
+				continue
+			}
+			b.key = m.resources.Keys[nextKey]
+			nextKey++
+		}
+		if t.DialTopic != "" && nextDial < len(m.resources.Dials) {
+			b.dial = m.resources.Dials[nextDial]
+			nextDial++
+		}
+		m.tiles = append(m.tiles, b)
+	}
+}
+
+// onConnect subscribes to every tile topic. Called on each (re)connect.
+func (m *Module) onConnect(c paho.Client) {
+	m.Log().Info("Connected")
+
+	for _, b := range m.tiles {
+		tile := b.tile
+		token := c.Subscribe(tile.Topic, 0, func(_ paho.Client, msg paho.Message) {
+			m.handleMessage(tile, msg.Payload())
+		})
+		go func() {
+			if token.WaitTimeout(5*time.Second) && token.Error() != nil {
+				m.Log().Warn("Subscribe failed", "topic", tile.Topic, "err", token.Error())
+			}
+		}()
+	}
+}
+
+// handleMessage extracts and formats a tile's value from an incoming payload.
+func (m *Module) handleMessage(tile config.MQTTTile, payload []byte) {
+	v, err := extractValue(payload, tile.Path)
+	if err != nil {
+		m.Log().Warn("Bad payload", "topic", tile.Topic, "err", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.values[tile.Topic] = formatValue(v, tile.Format)
+	m.mu.Unlock()
+}
+
+// getValue returns the latest display text for a topic.
+func (m *Module) getValue(topic string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.values[topic]
+	return v, ok
+}
+
+// publish sends a payload without blocking the caller.
+func (m *Module) publish(topic, payload string) {
+	token := m.client.Publish(topic, 0, false, payload)
+	go func() {
+		if token.WaitTimeout(5*time.Second) && token.Error() != nil {
+			m.Log().Warn("Publish failed", "topic", topic, "err", token.Error())
+		}
+	}()
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled {
+		return nil
+	}
+
+	keys := make(map[module.KeyID]image.Image)
+	for _, b := range m.tiles {
+		if b.key == 0 {
+			continue
+		}
+		value, ok := m.getValue(b.tile.Topic)
+		keys[b.key] = m.renderTileKey(b.tile.Label, value, ok)
+	}
+	return keys
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	if !m.enabled || !m.resources.HasStrip() {
+		return nil
+	}
+
+	rect := m.resources.Device.StripRect
+
+	var labels, values []string
+	for _, b := range m.tiles {
+		if !b.tile.Strip {
+			continue
+		}
+		value, ok := m.getValue(b.tile.Topic)
+		if !ok {
+			value = "-"
+		}
+		labels = append(labels, b.tile.Label)
+		values = append(values, value)
+	}
+
+	return m.renderStrip(rect, m.resources.StripRect, labels, values)
+}
+
+// HandleKey publishes the tile's press payload.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	for _, b := range m.tiles {
+		if b.key == id && b.tile.PressTopic != "" {
+			m.Log().Info("Publish", "topic", b.tile.PressTopic)
+			m.publish(b.tile.PressTopic, b.tile.PressPayload)
+		}
+	}
+	return nil
+}
+
+// HandleDial publishes the tile's dial payload with the rotation amount substituted.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	if !m.enabled || event.Type != module.DialRotate {
+		return nil
+	}
+
+	for _, b := range m.tiles {
+		if b.dial != id {
+			continue
+		}
+		payload := b.tile.DialPayload
+		if payload == "" {
+			payload = "{delta}"
+		}
+		payload = strings.ReplaceAll(payload, "{delta}", strconv.Itoa(int(event.Delta)))
+		m.publish(b.tile.DialTopic, payload)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// extractValue pulls the displayed value out of a payload. With an empty path
+// the raw payload is returned; otherwise the payload is parsed as JSON and the
+// dot-separated path is followed through objects and arrays.
+func extractValue(payload []byte, path string) (any, error) {
+	if path == "" {
+		text := strings.TrimSpace(string(payload))
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f, nil
+		}
+		return text, nil
+	}
+
+	var v any
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return nil, fmt.Errorf("decode JSON payload: %w", err)
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return nil, fmt.Errorf("path %q: key %q not found", path, part)
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("path %q: invalid index %q", path, part)
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("path %q: cannot descend into %T", path, v)
+		}
+	}
+
+	return v, nil
+}
+
+// formatValue renders an extracted value as display text. Numbers use the
+// tile's printf format when one is configured.
+func formatValue(v any, format string) string {
+	switch val := v.(type) {
+	case float64:
+		if format != "" {
+			return fmt.Sprintf(format, val)
+		}
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		if val {
+			return "On"
+		}
+		return "Off"
+	case string:
+		return val
+	case nil:
+		return "-"
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
@@ -0,0 +1,85 @@
+package mqtt
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// Common colors
+var (
+	colorBackground = color.RGBA{25, 25, 25, 255}
+	colorKeyBg      = color.RGBA{40, 40, 40, 255}
+	colorWhite      = color.RGBA{255, 255, 255, 255}
+	colorGray       = color.RGBA{160, 160, 160, 255}
+	colorDimGray    = color.RGBA{80, 80, 80, 255}
+	colorDivider    = color.RGBA{60, 60, 60, 255}
+)
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	faces := []struct {
+		dst    *font.Face
+		weight render.Weight
+		size   float64
+	}{
+		{&m.labelFace, render.Regular, 11},
+		{&m.valueFace, render.Bold, 20},
+		{&m.stripLabelFace, render.Regular, 14},
+		{&m.stripValueFace, render.Bold, 26},
+	}
+	for _, f := range faces {
+		var err error
+		if *f.dst, err = render.NewFace(f.weight, f.size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderTileKey renders a tile's label and current value on a key.
+func (m *Module) renderTileKey(label, value string, hasValue bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	render.DrawTextCentered(img, render.TruncateText(label, m.labelFace, keySize-6), keySize/2, 18, m.labelFace, colorGray)
+
+	valueColor := colorWhite
+	if !hasValue {
+		value = "-"
+		valueColor = colorDimGray
+	}
+	render.DrawTextCentered(img, render.TruncateText(value, m.valueFace, keySize-6), keySize/2, 48, m.valueFace, valueColor)
+
+	return img
+}
+
+// renderStrip renders strip tiles as evenly divided columns within the module's region.
+func (m *Module) renderStrip(rect, region image.Rectangle, labels, values []string) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	if len(labels) == 0 {
+		return img
+	}
+
+	colW := region.Dx() / len(labels)
+	for i := range labels {
+		x0 := region.Min.X + i*colW
+		centerX := x0 + colW/2
+
+		if i > 0 {
+			draw.Draw(img, image.Rect(x0, region.Min.Y+15, x0+1, region.Max.Y-15), &image.Uniform{colorDivider}, image.Point{}, draw.Src)
+		}
+
+		render.DrawTextCentered(img, render.TruncateText(labels[i], m.stripLabelFace, colW-10), centerX, region.Min.Y+32, m.stripLabelFace, colorGray)
+		render.DrawTextCentered(img, render.TruncateText(values[i], m.stripValueFace, colW-10), centerX, region.Min.Y+72, m.stripValueFace, colorWhite)
+	}
+
+	return img
+}
@@ -0,0 +1,266 @@
+// Package network provides a Stream Deck module that pings configured hosts
+// and shows their reachability and latency.
+package network
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	// defaultInterval is the time between pings when network.interval is unset.
+	defaultInterval = 5 * time.Second
+
+	// historyLen is how many samples each host's sparkline keeps.
+	historyLen = 60
+
+	// downAfter is how many pings in a row must fail before a host is
+	// considered down, so a single dropped packet doesn't alert.
+	downAfter = 2
+
+	// alertDuration is how long down/up notifications stay on the strip.
+	alertDuration = 5 * time.Second
+)
+
+// lost marks a failed ping in a host's history.
+const lost = -1
+
+// host is one monitored host and its recent results.
+type host struct {
+	label string
+	addr  string
+
+	history  []float64 // round-trip ms, or lost; oldest first
+	failures int       // consecutive failed pings
+	down     bool
+}
+
+// latest returns the most recent round-trip time in ms, or lost.
+func (h *host) latest() float64 {
+	if len(h.history) == 0 {
+		return lost
+	}
+	return h.history[len(h.history)-1]
+}
+
+// Module implements the network monitor module.
+type Module struct {
+	module.BaseModule
+
+	appCfg   *config.Config
+	interval time.Duration
+
+	mu    sync.RWMutex
+	hosts []*host
+
+	// Fonts
+	labelFace      font.Face
+	valueFace      font.Face
+	stripLabelFace font.Face
+	stripValueFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new network module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("network"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "network"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var cfg config.NetworkConfig
+	if m.appCfg != nil {
+		cfg = m.appCfg.Network
+	}
+	hosts := cfg.Hosts
+	if len(hosts) == 0 {
+		hosts = []config.NetworkHost{{Label: "Internet", Host: "1.1.1.1"}}
+	}
+	for _, h := range hosts {
+		label := h.Label
+		if label == "" {
+			label = h.Host
+		}
+		m.hosts = append(m.hosts, &host{label: label, addr: h.Host})
+	}
+
+	m.interval = defaultInterval
+	if cfg.Interval > 0 {
+		m.interval = time.Duration(cfg.Interval) * time.Second
+	}
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	go m.pollHosts(ctx)
+
+	m.Log().Info("Module initialized", "hosts", len(m.hosts), "interval", m.interval)
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// pollHosts pings every host now and every interval.
+func (m *Module) pollHosts(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.pingAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pingAll pings the hosts concurrently and records the results.
+func (m *Module) pingAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, h := range m.hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rtt, err := ping(ctx, h.addr)
+			if ctx.Err() != nil {
+				return
+			}
+			m.record(h, rtt, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// record appends a ping result to h's history and notifies when h goes
+// down or comes back.
+func (m *Module) record(h *host, rtt time.Duration, err error) {
+	m.mu.Lock()
+	sample := float64(lost)
+	if err == nil {
+		sample = float64(rtt) / float64(time.Millisecond)
+	}
+	h.history = append(h.history, sample)
+	if len(h.history) > historyLen {
+		h.history = h.history[len(h.history)-historyLen:]
+	}
+
+	var note *module.Notification
+	if err != nil {
+		h.failures++
+		if h.failures == downAfter {
+			h.down = true
+			m.Log().Warn("Host unreachable", "host", h.addr, "err", err)
+			note = &module.Notification{Text: h.label + " unreachable", Color: colorDown}
+		}
+	} else {
+		if h.down {
+			m.Log().Info("Host reachable again", "host", h.addr, "rtt", rtt)
+			note = &module.Notification{Text: fmt.Sprintf("%s back (%s)", h.label, formatRTT(sample)), Color: colorUp}
+		}
+		h.failures = 0
+		h.down = false
+	}
+	m.mu.Unlock()
+
+	if note != nil {
+		note.Duration = alertDuration
+		m.Notify(*note)
+	}
+}
+
+// hostState is a copy of a host's state for rendering.
+type hostState struct {
+	label   string
+	latest  float64
+	down    bool
+	history []float64
+}
+
+// snapshot returns a copy of every host's state.
+func (m *Module) snapshot() []hostState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	states := make([]hostState, len(m.hosts))
+	for i, h := range m.hosts {
+		states[i] = hostState{
+			label:   h.label,
+			latest:  h.latest(),
+			down:    h.down,
+			history: append([]float64(nil), h.history...),
+		}
+	}
+	return states
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if len(m.resources.Keys) == 0 {
+		return nil
+	}
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderStatusKey(m.snapshot()),
+	}
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	if !m.resources.HasStrip() || !m.resources.Device.HasStrip() {
+		return nil
+	}
+
+	rect := m.resources.Device.StripRect
+
+	return m.renderStrip(rect, m.resources.StripRect, m.snapshot())
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
+
+// formatRTT formats a round-trip time in ms, e.g. "12 ms" or "0.8 ms".
+func formatRTT(ms float64) string {
+	if ms < 10 {
+		return fmt.Sprintf("%.1f ms", ms)
+	}
+	return fmt.Sprintf("%.0f ms", ms)
+}
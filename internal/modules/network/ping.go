@@ -0,0 +1,50 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// pingTimeout is how long to wait for a reply before counting the host down.
+const pingTimeout = 2 * time.Second
+
+// pingTime matches the round-trip time in ping's reply line.
+var pingTime = regexp.MustCompile(`time[=<]([\d.]+) ?ms`)
+
+// ping sends one echo request to host with the system ping, which can send
+// ICMP without root, and returns the round-trip time.
+func ping(ctx context.Context, host string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	args := []string{"-c", "1", "-n", host}
+	if runtime.GOOS == "windows" {
+		args = []string{"-n", "1", host}
+	}
+	out, err := exec.CommandContext(ctx, "ping", args...).Output()
+	if ctx.Err() != nil {
+		return 0, fmt.Errorf("timed out")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("no reply")
+	}
+	return parsePing(string(out))
+}
+
+// parsePing extracts the round-trip time from ping's output.
+func parsePing(out string) (time.Duration, error) {
+	m := pingTime.FindStringSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("no reply")
+	}
+	ms, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
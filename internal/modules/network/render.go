@@ -0,0 +1,174 @@
+package network
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// Colors
+var (
+	colorUp       = color.RGBA{80, 200, 120, 255}
+	colorSlow     = color.RGBA{230, 180, 40, 255}
+	colorDown     = color.RGBA{235, 75, 60, 255}
+	colorUnknown  = color.RGBA{90, 90, 90, 255}
+	colorGridLine = color.RGBA{50, 50, 50, 255}
+	colorDivider  = color.RGBA{55, 55, 55, 255}
+)
+
+const (
+	// slowMS is the round-trip time above which a host shows as slow.
+	slowMS = 150
+
+	// maxKeyRows is how many hosts fit on the status key.
+	maxKeyRows = 4
+
+	// minScaleMS is the smallest full-scale value for the sparkline, so a
+	// quiet link doesn't magnify jitter.
+	minScaleMS = 50
+)
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Bold, 11); err != nil {
+		return err
+	}
+	if m.valueFace, err = render.NewFace(render.Regular, 11); err != nil {
+		return err
+	}
+	if m.stripLabelFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+	if m.stripValueFace, err = render.NewFace(render.Bold, 18); err != nil {
+		return err
+	}
+	return nil
+}
+
+// statusColor returns the dot color for a host.
+func statusColor(h hostState) color.Color {
+	switch {
+	case h.down:
+		return colorDown
+	case h.latest == lost && len(h.history) == 0:
+		return colorUnknown
+	case h.latest == lost:
+		return colorSlow // dropped a packet but not yet down
+	case h.latest > slowMS:
+		return colorSlow
+	}
+	return colorUp
+}
+
+// statusText returns the latest result for a host.
+func statusText(h hostState) string {
+	switch {
+	case h.down:
+		return "down"
+	case len(h.history) == 0:
+		return "..."
+	case h.latest == lost:
+		return "lost"
+	}
+	return formatRTT(h.latest)
+}
+
+// renderStatusKey renders a row per host with a status dot and latency.
+func (m *Module) renderStatusKey(hosts []hostState) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+	if len(hosts) > maxKeyRows {
+		hosts = hosts[:maxKeyRows]
+	}
+
+	rowH := 16
+	y := (render.KeySize-len(hosts)*rowH)/2 + 12
+	for _, h := range hosts {
+		draw.Draw(img, image.Rect(5, y-8, 11, y-2), &image.Uniform{statusColor(h)}, image.Point{}, draw.Src)
+
+		// No room for units; the dot color says whether a host is down
+		value := "--"
+		if !h.down && h.latest != lost {
+			value = fmt.Sprintf("%.0f", h.latest)
+		} else if len(h.history) == 0 {
+			value = "..."
+		}
+		valueW := font.MeasureString(m.valueFace, value).Ceil()
+		render.DrawText(img, value, render.KeySize-4-valueW, y, m.valueFace, render.ColorGray)
+
+		label := render.TruncateText(h.label, m.labelFace, render.KeySize-4-valueW-4-14)
+		render.DrawText(img, label, 14, y, m.labelFace, render.ColorWhite)
+		y += rowH
+	}
+	return img
+}
+
+// renderStrip renders a column per host with its latest result and a
+// latency sparkline, leaving the rest of the image transparent.
+func (m *Module) renderStrip(rect, region image.Rectangle, hosts []hostState) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+	if len(hosts) == 0 {
+		return img
+	}
+
+	colW := region.Dx() / len(hosts)
+	for i, h := range hosts {
+		x0 := region.Min.X + i*colW
+		if i > 0 {
+			draw.Draw(img, image.Rect(x0, region.Min.Y+12, x0+1, region.Max.Y-12), &image.Uniform{colorDivider}, image.Point{}, draw.Src)
+		}
+
+		value := statusText(h)
+		valueW := font.MeasureString(m.stripValueFace, value).Ceil()
+		render.DrawText(img, value, x0+colW-10-valueW, region.Min.Y+30, m.stripValueFace, statusColor(h))
+		label := render.TruncateText(h.label, m.stripLabelFace, colW-valueW-30)
+		render.DrawText(img, label, x0+10, region.Min.Y+29, m.stripLabelFace, render.ColorGray)
+
+		graph := image.Rect(x0+10, region.Min.Y+42, x0+colW-10, region.Max.Y-10)
+		if graph.Dx() > 0 {
+			drawSparkline(img, graph, h.history)
+		}
+	}
+	return img
+}
+
+// drawSparkline draws latency samples right-aligned in dst, scaled to the
+// slowest sample shown. Lost pings are full-height red bars.
+func drawSparkline(img *image.RGBA, dst image.Rectangle, values []float64) {
+	draw.Draw(img, image.Rect(dst.Min.X, dst.Max.Y-1, dst.Max.X, dst.Max.Y), &image.Uniform{colorGridLine}, image.Point{}, draw.Src)
+	if len(values) == 0 {
+		return
+	}
+
+	colW := max(dst.Dx()/historyLen, 1)
+	if len(values)*colW > dst.Dx() {
+		values = values[len(values)-dst.Dx()/colW:]
+	}
+
+	scale := float64(minScaleMS)
+	for _, v := range values {
+		scale = max(scale, v)
+	}
+
+	x := dst.Max.X - len(values)*colW
+	for _, v := range values {
+		if v == lost {
+			draw.Draw(img, image.Rect(x, dst.Min.Y, x+colW, dst.Max.Y), &image.Uniform{color.RGBA{110, 40, 35, 255}}, image.Point{}, draw.Src)
+		} else if h := int(float64(dst.Dy()) * v / scale); h > 0 {
+			col := colorUp
+			if v > slowMS {
+				col = colorSlow
+			}
+			top := dst.Max.Y - h
+			fill := color.RGBA{col.R / 3, col.G / 3, col.B / 3, 255}
+			draw.Draw(img, image.Rect(x, top, x+colW, dst.Max.Y), &image.Uniform{fill}, image.Point{}, draw.Src)
+			draw.Draw(img, image.Rect(x, top, x+colW, top+2), &image.Uniform{col}, image.Point{}, draw.Src)
+		}
+		x += colW
+	}
+}
@@ -1,27 +1,33 @@
 package nowplaying
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
-	"log"
-	"os/exec"
+	"encoding/base64"
 	"sync"
 	"time"
+
+	"github.com/phinze/belowdeck/internal/modules/nowplaying/mediaremote"
 )
 
-// NowPlaying represents the media-control JSON output (with --micros flag)
+// pollInterval is how often MediaRemote is asked what's playing unless
+// configured.
+const pollInterval = time.Second
+
+// queryTimeout bounds one MediaRemote query.
+const queryTimeout = 2 * time.Second
+
+// NowPlaying is the playing media, as shown by the module.
 type NowPlaying struct {
-	Title                string `json:"title"`
-	Artist               string `json:"artist"`
-	Album                string `json:"album"`
-	DurationMicros       int64  `json:"durationMicros"`
-	ElapsedTimeMicros    int64  `json:"elapsedTimeMicros"`
-	TimestampEpochMicros int64  `json:"timestampEpochMicros"`
-	Playing              bool   `json:"playing"`
-	ArtworkData          string `json:"artworkData"`
-	ArtworkMime          string `json:"artworkMimeType"`
-	BundleIdentifier     string `json:"bundleIdentifier"`
+	Title                string
+	Artist               string
+	Album                string
+	DurationMicros       int64
+	ElapsedTimeMicros    int64 // position at TimestampEpochMicros
+	TimestampEpochMicros int64
+	Playing              bool
+	ArtworkData          string // base64-encoded image
+	ArtworkMime          string
+	BundleIdentifier     string
 }
 
 // liveState wraps NowPlaying with thread-safe access.
@@ -42,123 +48,69 @@ func (s *liveState) get() NowPlaying {
 	return s.NowPlaying
 }
 
-// StreamPayload wraps the stream JSON structure with raw payload for proper merging.
-type StreamPayload struct {
-	Diff    bool            `json:"diff"`
-	Payload json.RawMessage `json:"payload"`
+// set replaces the current state.
+func (s *liveState) set(np NowPlaying) {
+	s.Lock()
+	defer s.Unlock()
+	s.NowPlaying = np
 }
 
-// startMediaStream runs the media-control stream with automatic reconnection.
-func (m *Module) startMediaStream(ctx context.Context) {
+// pollMedia keeps the live state current from MediaRemote until ctx is done.
+func (m *Module) pollMedia(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	var lastErr string // last error logged, to avoid repeating it every poll
 	for {
-		m.runMediaStream(ctx)
+		qctx, cancel := context.WithTimeout(ctx, queryTimeout)
+		info, err := mediaremote.NowPlaying(qctx)
+		cancel()
 
-		// Don't reconnect if context is cancelled
-		if ctx.Err() != nil {
+		switch {
+		case ctx.Err() != nil:
 			return
+		case err != nil:
+			if err.Error() != lastErr {
+				m.Log().Warn("Failed to get now playing", "err", err)
+				lastErr = err.Error()
+			}
+		default:
+			lastErr = ""
+			m.liveState.set(fromInfo(info))
 		}
 
-		log.Println("media-control stream exited, reconnecting in 2s...")
 		select {
-		case <-time.After(2 * time.Second):
 		case <-ctx.Done():
 			return
+		case <-ticker.C:
 		}
 	}
 }
 
-// runMediaStream runs a single media-control stream session.
-func (m *Module) runMediaStream(ctx context.Context) {
-	cmd := exec.CommandContext(ctx, "media-control", "stream", "--micros")
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		log.Printf("Failed to get stdout pipe: %v", err)
-		return
-	}
-
-	if err := cmd.Start(); err != nil {
-		log.Printf("Failed to start media-control stream: %v", err)
-		return
-	}
-
-	log.Println("Started media-control stream")
-
-	scanner := bufio.NewScanner(stdout)
-	// Increase buffer size for large artwork payloads
-	buf := make([]byte, 0, 1024*1024) // 1MB buffer
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		var envelope StreamPayload
-		if err := json.Unmarshal(line, &envelope); err != nil {
-			continue
-		}
-
-		// Parse payload as a map to see which fields are present
-		var payloadMap map[string]interface{}
-		if err := json.Unmarshal(envelope.Payload, &payloadMap); err != nil {
-			continue
-		}
-
-		m.liveState.Lock()
-		if !envelope.Diff && len(payloadMap) == 0 {
-			// Reset to defaults
-			m.liveState.NowPlaying = NowPlaying{
-				Title:                "?",
-				Artist:               "?",
-				TimestampEpochMicros: time.Now().UnixMicro(),
-			}
-		} else {
-			// Merge only fields that are present in the payload
-			mergePayloadMap(&m.liveState.NowPlaying, payloadMap)
+// fromInfo converts MediaRemote's now-playing info for display.
+func fromInfo(info mediaremote.Info) NowPlaying {
+	if info.Title == "" {
+		return NowPlaying{
+			Title:                "?",
+			Artist:               "?",
+			TimestampEpochMicros: time.Now().UnixMicro(),
 		}
-		m.liveState.Unlock()
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Printf("Scanner error: %v", err)
-	}
-
-	cmd.Wait()
-}
-
-// mergePayloadMap merges a map of fields into a NowPlaying struct.
-func mergePayloadMap(dst *NowPlaying, src map[string]interface{}) {
-	if v, ok := src["title"].(string); ok {
-		dst.Title = v
-	}
-	if v, ok := src["artist"].(string); ok {
-		dst.Artist = v
-	}
-	if v, ok := src["album"].(string); ok {
-		dst.Album = v
-	}
-	if v, ok := src["durationMicros"].(float64); ok {
-		dst.DurationMicros = int64(v)
-	}
-	if v, ok := src["elapsedTimeMicros"].(float64); ok {
-		dst.ElapsedTimeMicros = int64(v)
-	}
-	if v, ok := src["timestampEpochMicros"].(float64); ok {
-		dst.TimestampEpochMicros = int64(v)
-	}
-	// Only update playing if it's actually present in the payload
-	if v, ok := src["playing"].(bool); ok {
-		dst.Playing = v
-	}
-	if v, ok := src["artworkData"].(string); ok {
-		dst.ArtworkData = v
-	}
-	if v, ok := src["artworkMimeType"].(string); ok {
-		dst.ArtworkMime = v
-	}
-	if v, ok := src["bundleIdentifier"].(string); ok {
-		dst.BundleIdentifier = v
-	}
-	if v, ok := src["parentApplicationBundleIdentifier"].(string); ok {
-		dst.BundleIdentifier = v
 	}
+	np := NowPlaying{
+		Title:                info.Title,
+		Artist:               info.Artist,
+		Album:                info.Album,
+		DurationMicros:       info.Duration.Microseconds(),
+		ElapsedTimeMicros:    info.Elapsed.Microseconds(),
+		TimestampEpochMicros: info.Timestamp.UnixMicro(),
+		Playing:              info.Playing,
+		ArtworkMime:          info.ArtworkMIME,
+		BundleIdentifier:     info.BundleID,
+	}
+	if len(info.Artwork) > 0 {
+		np.ArtworkData = base64.StdEncoding.EncodeToString(info.Artwork)
+	}
+	return np
 }
 
 // getLiveElapsedMicros calculates the live elapsed time based on timestamp and playing state.
@@ -0,0 +1,45 @@
+// Package mediaremote reports and controls what the system is playing
+// through macOS's private MediaRemote framework, bound with purego.
+package mediaremote
+
+import (
+	"errors"
+	"time"
+)
+
+// Info describes what is playing. The zero Info means nothing is.
+type Info struct {
+	Title  string
+	Artist string
+	Album  string
+
+	Duration time.Duration
+	Elapsed  time.Duration // playback position at Timestamp
+	// Timestamp is when Elapsed was measured; while playing the position
+	// keeps advancing from it
+	Timestamp time.Time
+	Playing   bool
+
+	Artwork     []byte // encoded image, empty if none
+	ArtworkMIME string
+
+	// BundleID identifies the app playing, or the app hosting it for
+	// players embedded in another app (such as a browser tab).
+	BundleID string
+}
+
+// Command is a playback command for Send.
+type Command uint32
+
+// Commands, numbered as MRMediaRemoteCommand.
+const (
+	Play            Command = 0
+	Pause           Command = 1
+	TogglePlayPause Command = 2
+	Stop            Command = 3
+	NextTrack       Command = 4
+	PreviousTrack   Command = 5
+)
+
+// ErrUnsupported is returned where MediaRemote isn't available.
+var ErrUnsupported = errors.New("MediaRemote requires macOS")
@@ -0,0 +1,257 @@
+package mediaremote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+	"github.com/ebitengine/purego/objc"
+)
+
+// purego function bindings
+var (
+	mrGetNowPlayingInfo                  func(queue uintptr, completion objc.Block)
+	mrGetNowPlayingClient                func(queue uintptr, completion objc.Block)
+	mrClientGetBundleIdentifier          func(client uintptr) objc.ID
+	mrClientGetParentAppBundleIdentifier func(client uintptr) objc.ID
+	mrSendCommand                        func(cmd uint32, options uintptr) bool
+	mrSetElapsedTime                     func(seconds float64)
+
+	dispatchGetGlobalQueue func(identifier int64, flags uint64) uintptr
+)
+
+var (
+	loadOnce sync.Once
+	loadErr  error
+
+	// infoKeys are the now-playing dictionary keys by short name, retained
+	// for the life of the process
+	infoKeys = make(map[string]objc.ID)
+
+	selObjectForKey     = objc.RegisterName("objectForKey:")
+	selIsKindOfClass    = objc.RegisterName("isKindOfClass:")
+	selUTF8String       = objc.RegisterName("UTF8String")
+	selDoubleValue      = objc.RegisterName("doubleValue")
+	selBytes            = objc.RegisterName("bytes")
+	selLength           = objc.RegisterName("length")
+	selTimeSince1970    = objc.RegisterName("timeIntervalSince1970")
+	selStringWithString = objc.RegisterName("stringWithUTF8String:")
+	selRetain           = objc.RegisterName("retain")
+)
+
+// Load loads MediaRemote, reporting why it can't be used. Other functions
+// call it as needed.
+func Load() error {
+	loadOnce.Do(func() {
+		loadErr = load()
+	})
+	return loadErr
+}
+
+func load() error {
+	if _, err := purego.Dlopen("/System/Library/Frameworks/Foundation.framework/Foundation", purego.RTLD_LAZY|purego.RTLD_GLOBAL); err != nil {
+		return fmt.Errorf("loading Foundation: %w", err)
+	}
+	sys, err := purego.Dlopen("/usr/lib/libSystem.B.dylib", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		return fmt.Errorf("loading libSystem: %w", err)
+	}
+	if err := bind(&dispatchGetGlobalQueue, sys, "dispatch_get_global_queue"); err != nil {
+		return err
+	}
+
+	mr, err := purego.Dlopen("/System/Library/PrivateFrameworks/MediaRemote.framework/MediaRemote", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		return fmt.Errorf("loading MediaRemote: %w", err)
+	}
+	// MediaRemote is private, so symbols may disappear in a macOS update;
+	// report that instead of panicking
+	for _, b := range []struct {
+		fptr any
+		name string
+	}{
+		{&mrGetNowPlayingInfo, "MRMediaRemoteGetNowPlayingInfo"},
+		{&mrGetNowPlayingClient, "MRMediaRemoteGetNowPlayingClient"},
+		{&mrClientGetBundleIdentifier, "MRNowPlayingClientGetBundleIdentifier"},
+		{&mrClientGetParentAppBundleIdentifier, "MRNowPlayingClientGetParentAppBundleIdentifier"},
+		{&mrSendCommand, "MRMediaRemoteSendCommand"},
+		{&mrSetElapsedTime, "MRMediaRemoteSetElapsedTime"},
+	} {
+		if err := bind(b.fptr, mr, b.name); err != nil {
+			return err
+		}
+	}
+
+	nsString := objc.ID(objc.GetClass("NSString"))
+	for _, name := range []string{"Title", "Artist", "Album", "Duration", "ElapsedTime", "Timestamp", "PlaybackRate", "ArtworkData", "ArtworkMIMEType"} {
+		key := nsString.Send(selStringWithString, "kMRMediaRemoteNowPlayingInfo"+name)
+		infoKeys[name] = key.Send(selRetain)
+	}
+	return nil
+}
+
+// bind registers the C function name from lib into fptr.
+func bind(fptr any, lib uintptr, name string) error {
+	sym, err := purego.Dlsym(lib, name)
+	if err != nil {
+		return fmt.Errorf("MediaRemote: %s not found: %w", name, err)
+	}
+	purego.RegisterFunc(fptr, sym)
+	return nil
+}
+
+// NowPlaying returns what the system is playing. Recent macOS versions
+// withhold this from some processes, which then always see nothing playing.
+func NowPlaying(ctx context.Context) (Info, error) {
+	if err := Load(); err != nil {
+		return Info{}, err
+	}
+	queue := dispatchGetGlobalQueue(0, 0)
+
+	// Completions run on a dispatch queue; MediaRemote keeps its own copy of
+	// each block, so releasing ours early on a timeout is safe
+	infoCh := make(chan Info, 1)
+	infoBlock := objc.NewBlock(func(_ objc.Block, dict objc.ID) {
+		infoCh <- parseInfo(dict)
+	})
+	defer infoBlock.Release()
+	mrGetNowPlayingInfo(queue, infoBlock)
+
+	var info Info
+	select {
+	case info = <-infoCh:
+	case <-ctx.Done():
+		return Info{}, ctx.Err()
+	}
+	if info.Title == "" {
+		return Info{}, nil
+	}
+
+	bundleCh := make(chan string, 1)
+	clientBlock := objc.NewBlock(func(_ objc.Block, client uintptr) {
+		bundleCh <- clientBundleID(client)
+	})
+	defer clientBlock.Release()
+	mrGetNowPlayingClient(queue, clientBlock)
+
+	select {
+	case info.BundleID = <-bundleCh:
+	case <-ctx.Done():
+		return Info{}, ctx.Err()
+	}
+	return info, nil
+}
+
+// Send sends a playback command to the app playing.
+func Send(cmd Command) error {
+	if err := Load(); err != nil {
+		return err
+	}
+	if !mrSendCommand(uint32(cmd), 0) {
+		return errors.New("command not accepted")
+	}
+	return nil
+}
+
+// Seek moves playback to pos.
+func Seek(pos time.Duration) error {
+	if err := Load(); err != nil {
+		return err
+	}
+	mrSetElapsedTime(pos.Seconds())
+	return nil
+}
+
+// parseInfo copies the fields of a now-playing dictionary, which is only
+// valid during the completion.
+func parseInfo(dict objc.ID) Info {
+	if dict == 0 {
+		return Info{}
+	}
+	info := Info{
+		Title:       stringValue(dict, "Title"),
+		Artist:      stringValue(dict, "Artist"),
+		Album:       stringValue(dict, "Album"),
+		Duration:    seconds(numberValue(dict, "Duration")),
+		Elapsed:     seconds(numberValue(dict, "ElapsedTime")),
+		Playing:     numberValue(dict, "PlaybackRate") > 0,
+		Artwork:     dataValue(dict, "ArtworkData"),
+		ArtworkMIME: stringValue(dict, "ArtworkMIMEType"),
+		Timestamp:   time.Now(),
+	}
+	if v := value(dict, "Timestamp", "NSDate"); v != 0 {
+		info.Timestamp = time.Unix(0, 0).Add(seconds(objc.Send[float64](v, selTimeSince1970)))
+	}
+	return info
+}
+
+// clientBundleID returns the bundle ID of a now-playing client, preferring
+// its parent app's.
+func clientBundleID(client uintptr) string {
+	if client == 0 {
+		return ""
+	}
+	if id := goString(mrClientGetParentAppBundleIdentifier(client)); id != "" {
+		return id
+	}
+	return goString(mrClientGetBundleIdentifier(client))
+}
+
+// value returns dict's value for key if it's an instance of class, else 0.
+func value(dict objc.ID, key, class string) objc.ID {
+	v := dict.Send(selObjectForKey, infoKeys[key])
+	if v == 0 || !objc.Send[bool](v, selIsKindOfClass, objc.GetClass(class)) {
+		return 0
+	}
+	return v
+}
+
+func stringValue(dict objc.ID, key string) string {
+	return goString(value(dict, key, "NSString"))
+}
+
+func numberValue(dict objc.ID, key string) float64 {
+	v := value(dict, key, "NSNumber")
+	if v == 0 {
+		return 0
+	}
+	return objc.Send[float64](v, selDoubleValue)
+}
+
+func dataValue(dict objc.ID, key string) []byte {
+	v := value(dict, key, "NSData")
+	if v == 0 {
+		return nil
+	}
+	length := objc.Send[uint64](v, selLength)
+	ptr := objc.Send[unsafe.Pointer](v, selBytes)
+	if ptr == nil || length == 0 {
+		return nil
+	}
+	return bytes.Clone(unsafe.Slice((*byte)(ptr), length))
+}
+
+// goString copies an NSString.
+func goString(s objc.ID) string {
+	if s == 0 {
+		return ""
+	}
+	p := objc.Send[*byte](s, selUTF8String)
+	if p == nil {
+		return ""
+	}
+	var n int
+	for *(*byte)(unsafe.Add(unsafe.Pointer(p), n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice(p, n))
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,20 @@
+//go:build !darwin
+
+package mediaremote
+
+import (
+	"context"
+	"time"
+)
+
+// Load reports whether MediaRemote could be loaded.
+func Load() error { return ErrUnsupported }
+
+// NowPlaying returns what the system is playing.
+func NowPlaying(ctx context.Context) (Info, error) { return Info{}, ErrUnsupported }
+
+// Send sends a playback command to the app playing.
+func Send(cmd Command) error { return ErrUnsupported }
+
+// Seek moves playback to pos.
+func Seek(pos time.Duration) error { return ErrUnsupported }
@@ -4,12 +4,14 @@ package nowplaying
 import (
 	"context"
 	"image"
-	"log"
 	"os/exec"
 	"sync"
+	"time"
 
-	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/config"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/modules/nowplaying/mediaremote"
+	"github.com/phinze/belowdeck/internal/render"
 	"golang.org/x/image/font"
 )
 
@@ -17,29 +19,61 @@ import (
 type Module struct {
 	module.BaseModule
 
-	device device.Device
+	appCfg   *config.Config
+	interval time.Duration // between asking what's playing
+
+	// Key roles, assigned from resources in order: play/pause, info, then
+	// album art tiles when art_keys is enabled
+	playKey module.KeyID
+	infoKey module.KeyID
+	artKeys []module.KeyID
+
+	// Dial roles, assigned from resources in order: seek, track, then
+	// volume when the module has a third dial
+	seekDial   module.DialID
+	trackDial  module.DialID
+	volumeDial module.DialID
 
 	// State
-	liveState     *liveState
-	cachedArtwork image.Image
-	artworkHash   string
-	lastPlaying   bool
-	mu            sync.RWMutex
+	liveState   *liveState
+	artwork     artworkCache
+	volume      volumeState
+	lastPlaying bool
+	mu          sync.RWMutex
 
 	// Fonts
 	titleFace  font.Face
 	artistFace font.Face
 
-	// Cancel function for media stream
+	// Scrolling for long titles and artists, when enabled
+	marquee       bool
+	titleMarquee  render.Marquee
+	artistMarquee render.Marquee
+
+	// Cancel function for media polling
 	streamCancel context.CancelFunc
+
+	// volumeWake signals the volume worker that changes are queued
+	volumeWake chan struct{}
+}
+
+// artworkCache holds the current artwork decoded and scaled for each place
+// it's drawn, rebuilt only when the artwork changes.
+type artworkCache struct {
+	data     string        // artwork data the images were built from
+	thumb    image.Image   // square, strip height
+	backdrop image.Image   // darkened, covering the strip region
+	tiles    []image.Image // one per art key, left to right
 }
 
 // New creates a new NowPlaying module.
-func New(dev device.Device) *Module {
+func New(appCfg *config.Config) *Module {
 	return &Module{
 		BaseModule: module.NewBaseModule("nowplaying"),
-		device:     dev,
+		appCfg:     appCfg,
+		interval:   appCfg.PollInterval("nowplaying", pollInterval),
 		liveState:  newLiveState(),
+		volumeWake: make(chan struct{}, 1),
 	}
 }
 
@@ -48,6 +82,17 @@ func (m *Module) ID() string {
 	return "nowplaying"
 }
 
+// Key slots. With art_keys, the artwork tiles across the module's other keys.
+const (
+	slotPlay = "play" // play/pause
+	slotInfo = "info" // track info
+)
+
+// Slots declares the module's fixed keys.
+func (m *Module) Slots() []module.Slot {
+	return []module.Slot{{Name: slotPlay}, {Name: slotInfo}}
+}
+
 // Init initializes the module.
 func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Call base init
@@ -60,12 +105,32 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 		return err
 	}
 
-	// Start media stream in background
+	m.playKey, _ = res.Slot(slotPlay)
+	m.infoKey, _ = res.Slot(slotInfo)
+	m.artKeys = nil
+	if m.appCfg != nil && m.appCfg.NowPlaying.ArtKeys {
+		m.artKeys = res.FreeKeys()
+	}
+	m.marquee = m.appCfg != nil && m.appCfg.NowPlaying.Marquee
+
+	m.seekDial, m.trackDial, m.volumeDial = 0, 0, 0
+	if len(res.Dials) > 0 {
+		m.seekDial = res.Dials[0]
+	}
+	if len(res.Dials) > 1 {
+		m.trackDial = res.Dials[1]
+	}
+	if len(res.Dials) > 2 {
+		m.volumeDial = res.Dials[2]
+		go m.volumeWorker()
+	}
+
+	// Start polling MediaRemote in background
 	streamCtx, cancel := context.WithCancel(ctx)
 	m.streamCancel = cancel
-	go m.startMediaStream(streamCtx)
+	go m.pollMedia(streamCtx)
 
-	log.Println("NowPlaying module initialized")
+	m.Log().Info("Module initialized")
 	return nil
 }
 
@@ -79,7 +144,7 @@ func (m *Module) Stop() error {
 
 // RenderKeys returns images for the module's keys.
 func (m *Module) RenderKeys() map[module.KeyID]image.Image {
-	keyRect, _ := m.device.GetKeyImageRectangle()
+	keyRect := m.Resources().Device.KeyRect
 	size := keyRect.Dx()
 
 	keys := make(map[module.KeyID]image.Image)
@@ -87,52 +152,84 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 	// Get current state
 	np := m.liveState.get()
 
-	// Key 5: Play/Pause icon (changes based on state)
+	// Play/Pause icon (changes based on state)
 	m.mu.Lock()
 	if np.Playing != m.lastPlaying {
 		m.lastPlaying = np.Playing
 	}
 	playing := m.lastPlaying
+	m.updateArtwork(&np)
+	tiles := m.artwork.tiles
 	m.mu.Unlock()
 
-	if playing {
-		keys[module.Key5] = renderSVGIcon(iconPauseSVG, size, colorOrange)
-	} else {
-		keys[module.Key5] = renderSVGIcon(iconPlaySVG, size, colorLimeGreen)
+	if m.playKey != 0 {
+		if playing {
+			keys[m.playKey] = render.Icon("pause", size, colorOrange)
+		} else {
+			keys[m.playKey] = render.Icon("play", size, colorLimeGreen)
+		}
+	}
+
+	// Info icon (static)
+	if m.infoKey != 0 {
+		keys[m.infoKey] = render.Icon("info", size, colorDeepSkyBlue)
 	}
 
-	// Key 6: Info icon (static)
-	keys[module.Key6] = renderSVGIcon(iconInfoSVG, size, colorDeepSkyBlue)
+	// Album art spread across the art keys, blank when there's no artwork
+	for i, key := range m.artKeys {
+		if i < len(tiles) {
+			keys[key] = tiles[i]
+		} else {
+			keys[key] = renderBlankKey(size)
+		}
+	}
 
 	return keys
 }
 
 // RenderStrip returns the touch strip image.
 func (m *Module) RenderStrip() image.Image {
-	if !m.device.GetTouchStripSupported() {
+	if !m.Resources().Device.HasStrip() {
 		return nil
 	}
 
-	rect, err := m.device.GetTouchStripImageRectangle()
-	if err != nil {
-		return nil
-	}
+	rect := m.Resources().Device.StripRect
 
 	np := m.liveState.get()
 
-	// Update artwork cache if changed
 	m.mu.Lock()
-	if np.ArtworkData != "" && np.ArtworkData != m.artworkHash {
-		if img := decodeArtwork(np.ArtworkData); img != nil {
-			m.cachedArtwork = img
-			m.artworkHash = np.ArtworkData
-			log.Printf("Track: %s - %s", np.Artist, np.Title)
-		}
-	}
-	artwork := m.cachedArtwork
+	m.updateArtwork(&np)
+	art := m.artwork
+	vol := m.volumeOverlayLocked()
 	m.mu.Unlock()
 
-	return m.renderStrip(rect, &np, artwork)
+	return m.renderStrip(rect, m.Resources().StripRect, &np, art.thumb, art.backdrop, vol)
+}
+
+// updateArtwork rebuilds the artwork cache if the artwork changed. Artwork
+// only changes with the track, so decode and scale once per change rather
+// than per frame, and remember undecodable artwork too so it
+// isn't retried every render. Callers must hold m.mu.
+func (m *Module) updateArtwork(np *NowPlaying) {
+	if np.ArtworkData == "" || np.ArtworkData == m.artwork.data {
+		return
+	}
+
+	m.artwork = artworkCache{data: np.ArtworkData}
+	m.Log().Info("Track changed", "artist", np.Artist, "title", np.Title)
+
+	img := decodeArtwork(np.ArtworkData)
+	if img == nil {
+		return
+	}
+	if region := m.Resources().StripRect; !region.Empty() {
+		m.artwork.thumb = scaleImageSquare(img, region.Dy())
+		m.artwork.backdrop = renderBackdrop(img, region.Dx(), region.Dy())
+	}
+	if len(m.artKeys) > 0 {
+		keyRect := m.Resources().Device.KeyRect
+		m.artwork.tiles = splitAcrossKeys(img, len(m.artKeys), keyRect.Dx())
+	}
 }
 
 // HandleKey processes key events.
@@ -143,12 +240,18 @@ func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
 	}
 
 	switch id {
-	case module.Key5:
-		log.Println("Key: Toggle play/pause")
-		go exec.Command("media-control", "toggle-play-pause").Run()
-	case module.Key6:
+	case m.playKey:
+		if event.Layer == module.LayerShift {
+			m.openPlayer()
+			return nil
+		}
+		m.Log().Debug("Key: toggle play/pause")
+		m.RunOnKey(id, func(ctx context.Context) error {
+			return mediaremote.Send(mediaremote.TogglePlayPause)
+		})
+	case m.infoKey:
 		np := m.liveState.get()
-		log.Printf("Info: %s - %s (%s)", np.Artist, np.Title, np.Album)
+		m.Log().Info("Now playing", "artist", np.Artist, "title", np.Title, "album", np.Album)
 	}
 
 	return nil
@@ -156,60 +259,114 @@ func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
 
 // HandleDial processes dial events.
 func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	if id == 0 {
+		return nil
+	}
+
 	switch id {
-	case module.Dial1:
+	case m.seekDial:
 		switch event.Type {
 		case module.DialRotate:
 			// Seek 5 seconds per tick
 			seekAmount := int64(event.Delta) * 5 * 1000000 // 5 seconds in micros
-			log.Printf("Dial: Seeking %+d seconds", event.Delta*5)
+			m.Log().Debug("Dial: seek", "seconds", int(event.Delta)*5)
 
 			np := m.liveState.get()
-			currentPos := getLiveElapsedMicros(&np)
-
-			newPos := currentPos + seekAmount
-			if newPos < 0 {
-				newPos = 0
-			}
-			if newPos > np.DurationMicros {
-				newPos = np.DurationMicros
-			}
-
-			// media-control seek takes seconds
-			go exec.Command("media-control", "seek", formatSeekPosition(newPos)).Run()
+			m.seekTo(&np, getLiveElapsedMicros(&np)+seekAmount)
 
 		case module.DialPress:
-			log.Println("Dial: Toggle play/pause")
-			go exec.Command("media-control", "toggle-play-pause").Run()
+			m.Log().Debug("Dial: toggle play/pause")
+			go m.send(mediaremote.TogglePlayPause)
 		}
 
-	case module.Dial2:
+	case m.trackDial:
 		if event.Type == module.DialRotate {
 			if event.Delta < 0 {
-				log.Println("Dial: Previous track")
-				go exec.Command("media-control", "previous-track").Run()
+				m.Log().Debug("Dial: previous track")
+				go m.send(mediaremote.PreviousTrack)
 			} else {
-				log.Println("Dial: Next track")
-				go exec.Command("media-control", "next-track").Run()
+				m.Log().Debug("Dial: next track")
+				go m.send(mediaremote.NextTrack)
 			}
 		}
+
+	case m.volumeDial:
+		switch event.Type {
+		case module.DialRotate:
+			m.Log().Debug("Dial: volume", "delta", event.Delta)
+			m.adjustVolume(event.Delta)
+		case module.DialPress:
+			m.Log().Debug("Dial: toggle mute")
+			m.toggleMute()
+		}
 	}
 
 	return nil
 }
 
-// HandleStripTouch processes touch strip events.
+// HandleStripTouch processes touch strip events. Taps right of the artwork
+// seek to that point in the track and swipes scrub by the distance covered,
+// both measured against the progress bar; tapping the artwork opens the
+// playing app.
 func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
-	if event.Type != module.TouchTap {
+	np := m.liveState.get()
+	region := m.Resources().StripRect
+	x0, x1 := textSpan(region)
+
+	switch event.Type {
+	case module.TouchTap:
+		if event.Point.X >= region.Min.X+region.Dy() {
+			m.seekToFraction(&np, float64(event.Point.X-x0)/float64(x1-x0))
+			return nil
+		}
+	case module.TouchSwipe:
+		fraction := float64(event.SwipeEnd.X-event.SwipeStart.X) / float64(x1-x0)
+		if np.DurationMicros > 0 {
+			m.seekTo(&np, getLiveElapsedMicros(&np)+int64(fraction*float64(np.DurationMicros)))
+		}
+		return nil
+	default:
 		return nil
 	}
 
+	m.openPlayer()
+	return nil
+}
+
+// openPlayer brings the app that's playing to the front.
+func (m *Module) openPlayer() {
 	np := m.liveState.get()
 	if np.BundleIdentifier == "" {
-		return nil
+		return
 	}
 
-	log.Printf("Strip tap: opening %s", np.BundleIdentifier)
+	m.Log().Debug("Opening player", "bundle", np.BundleIdentifier)
 	go exec.Command("open", "-b", np.BundleIdentifier).Run()
-	return nil
+}
+
+// seekToFraction seeks to fraction (clamped to [0, 1]) of the track.
+func (m *Module) seekToFraction(np *NowPlaying, fraction float64) {
+	if np.DurationMicros <= 0 {
+		return
+	}
+	fraction = max(0, min(1, fraction))
+	m.seekTo(np, int64(fraction*float64(np.DurationMicros)))
+}
+
+// seekTo seeks to pos, clamped to the track.
+func (m *Module) seekTo(np *NowPlaying, pos int64) {
+	pos = max(0, min(np.DurationMicros, pos))
+	m.Log().Debug("Seek", "position", formatDurationMicros(pos))
+	go func() {
+		if err := mediaremote.Seek(time.Duration(pos) * time.Microsecond); err != nil {
+			m.Log().Warn("Seek failed", "err", err)
+		}
+	}()
+}
+
+// send sends a playback command, logging failures.
+func (m *Module) send(cmd mediaremote.Command) {
+	if err := mediaremote.Send(cmd); err != nil {
+		m.Log().Warn("Playback command failed", "cmd", cmd, "err", err)
+	}
 }
@@ -2,39 +2,19 @@ package nowplaying
 
 import (
 	"bytes"
-	_ "embed"
 	"encoding/base64"
 	"fmt"
 	"image"
 	"image/color"
 	_ "image/jpeg"
 	_ "image/png"
-	"log"
-	"strings"
 
-	"github.com/srwiley/oksvg"
-	"github.com/srwiley/rasterx"
+	"github.com/phinze/belowdeck/internal/render"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/math/fixed"
 )
 
-//go:embed fonts/PublicSans-Bold.ttf
-var fontBold []byte
-
-//go:embed fonts/PublicSans-Regular.ttf
-var fontRegular []byte
-
-//go:embed icons/play.svg
-var iconPlaySVG string
-
-//go:embed icons/pause.svg
-var iconPauseSVG string
-
-//go:embed icons/info.svg
-var iconInfoSVG string
-
 // Common colors
 var (
 	colorLimeGreen   = color.RGBA{50, 205, 50, 255}
@@ -45,76 +25,71 @@ var (
 	colorProgressBg  = color.RGBA{60, 60, 60, 255}
 	colorArtist      = color.RGBA{180, 180, 180, 255}
 	colorTime        = color.RGBA{120, 120, 120, 255}
+
+	// colorBackdropShade darkens the artwork spread behind the strip text
+	colorBackdropShade = color.RGBA{0, 0, 0, 190}
 )
 
 // initFonts initializes the font faces for rendering.
 func (m *Module) initFonts() error {
-	// Parse bold font for title
-	ttBold, err := opentype.Parse(fontBold)
-	if err != nil {
-		return fmt.Errorf("failed to parse bold font: %w", err)
-	}
-
-	m.titleFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    24,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create title face: %w", err)
-	}
-
-	// Parse regular font for artist
-	ttRegular, err := opentype.Parse(fontRegular)
-	if err != nil {
-		return fmt.Errorf("failed to parse regular font: %w", err)
+	var err error
+	if m.titleFace, err = render.NewFace(render.Bold, 24); err != nil {
+		return err
 	}
-
-	m.artistFace, err = opentype.NewFace(ttRegular, &opentype.FaceOptions{
-		Size:    18,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create artist face: %w", err)
+	if m.artistFace, err = render.NewFace(render.Regular, 18); err != nil {
+		return err
 	}
-
 	return nil
 }
 
-// renderStrip renders the touch strip with album art, text, and progress bar.
-func (m *Module) renderStrip(rect image.Rectangle, np *NowPlaying, artwork image.Image) image.Image {
+// renderStrip renders the module's strip region: the album art spread
+// behind everything, a sharp thumbnail on the left, and the track text above
+// the progress bar to its right. While the volume dial is in use, a volume
+// bar replaces the track text.
+func (m *Module) renderStrip(rect, region image.Rectangle, np *NowPlaying, thumb, backdrop image.Image, vol *volumeState) image.Image {
 	img := image.NewRGBA(rect)
-	fullW := rect.Dx()
-	h := rect.Dy()
+	x0 := region.Min.X
+	h := region.Dy()
 
-	// Only use left half of the strip
-	w := fullW / 2
-
-	// Background - dark (full strip to clear any previous content)
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	// Background: darkened artwork across the region, or plain dark
+	draw.Draw(img, region, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	if backdrop != nil {
+		draw.Draw(img, region, backdrop, backdrop.Bounds().Min, draw.Src)
+	}
 
-	// Layout for left half: [Art full height] [gap] [Text + progress]
+	// Layout: [Art full height] [gap] [Text + progress]
 	artSize := h // Full height bleed
-	textX := artSize + 8
+	textX, textRight := textSpan(region)
 	progressH := 5
 	progressMargin := 8
 
-	// Draw album art thumbnail on left, full bleed
-	if artwork != nil {
-		artRect := image.Rect(0, 0, artSize, artSize)
-		thumb := scaleImageSquare(artwork, artSize)
-		draw.Draw(img, artRect, thumb, image.Point{}, draw.Over)
+	// Draw album art thumbnail on left, full bleed (already scaled to strip height)
+	if thumb != nil {
+		artRect := image.Rect(x0, 0, x0+artSize, artSize)
+		draw.Draw(img, artRect, thumb, thumb.Bounds().Min, draw.Over)
+	}
+
+	if vol != nil {
+		m.drawVolume(img, textX, textRight, h, vol)
+		return img
 	}
 
 	// Draw title (bold)
 	if np.Title != "" {
-		m.drawText(img, np.Title, textX, 30, m.titleFace, color.White, w-textX-10)
+		if m.marquee {
+			m.titleMarquee.Draw(img, np.Title, textX, 30, textRight-textX, m.titleFace, color.White)
+		} else {
+			m.drawText(img, np.Title, textX, 30, m.titleFace, color.White, textRight-textX)
+		}
 	}
 
 	// Draw artist (regular, smaller, gray)
 	if np.Artist != "" {
-		m.drawText(img, np.Artist, textX, 54, m.artistFace, colorArtist, w-textX-10)
+		if m.marquee {
+			m.artistMarquee.Draw(img, np.Artist, textX, 54, textRight-textX, m.artistFace, colorArtist)
+		} else {
+			m.drawText(img, np.Artist, textX, 54, m.artistFace, colorArtist, textRight-textX)
+		}
 	}
 
 	// Calculate live elapsed time
@@ -131,7 +106,7 @@ func (m *Module) renderStrip(rect image.Rectangle, np *NowPlaying, artwork image
 	}
 
 	// Progress bar background
-	progressRect := image.Rect(textX, h-progressMargin-progressH, w-10, h-progressMargin)
+	progressRect := image.Rect(textX, h-progressMargin-progressH, textRight, h-progressMargin)
 	draw.Draw(img, progressRect, &image.Uniform{colorProgressBg}, image.Point{}, draw.Src)
 
 	// Progress bar fill
@@ -148,48 +123,43 @@ func (m *Module) renderStrip(rect image.Rectangle, np *NowPlaying, artwork image
 		elapsed := formatDurationMicros(elapsedMicros)
 		total := formatDurationMicros(durationMicros)
 		timeStr := fmt.Sprintf("%s / %s", elapsed, total)
-		m.drawTextRightAligned(img, timeStr, w-10, h-progressMargin-progressH-6, m.artistFace, colorTime)
+		m.drawTextRightAligned(img, timeStr, textRight, h-progressMargin-progressH-6, m.artistFace, colorTime)
 	}
 
 	return img
 }
 
-// renderSVGIcon renders an SVG string to an image with the given size and color.
-func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
-	// Replace currentColor with the actual color
-	r, g, b, _ := iconColor.RGBA()
-	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
-	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
+// textSpan returns the horizontal extent of the track text and progress bar
+// within region, to the right of the full-height thumbnail.
+func textSpan(region image.Rectangle) (x0, x1 int) {
+	return region.Min.X + region.Dy() + 8, region.Max.X - 10
+}
 
-	// Parse SVG
-	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
-	if err != nil {
-		log.Printf("Failed to parse SVG: %v", err)
-		return image.NewRGBA(image.Rect(0, 0, size, size))
+// drawVolume draws the volume level as a labeled bar spanning x0 to x1.
+func (m *Module) drawVolume(img *image.RGBA, x0, x1, h int, vol *volumeState) {
+	label, value, fillColor := "Volume", "...", colorLimeGreen
+	if vol.known {
+		value = fmt.Sprintf("%d%%", vol.level)
+	}
+	if vol.muted {
+		label, fillColor = "Muted", colorOrange
+	}
+	m.drawText(img, label, x0, 30, m.titleFace, color.White, x1-x0)
+	m.drawTextRightAligned(img, value, x1, 30, m.artistFace, colorArtist)
+
+	barH := 8
+	barY := h/2 + 8
+	draw.Draw(img, image.Rect(x0, barY, x1, barY+barH), &image.Uniform{colorProgressBg}, image.Point{}, draw.Src)
+	if vol.known {
+		fillW := (x1 - x0) * vol.level / 100
+		draw.Draw(img, image.Rect(x0, barY, x0+fillW, barY+barH), &image.Uniform{fillColor}, image.Point{}, draw.Src)
 	}
-
-	// Create output image with dark background
-	img := image.NewRGBA(image.Rect(0, 0, size, size))
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
-
-	// Calculate scaling and centering
-	iconSize := float64(size) * 0.6 // Icon takes 60% of button
-	padding := (float64(size) - iconSize) / 2
-
-	icon.SetTarget(padding, padding, iconSize, iconSize)
-
-	// Render to image
-	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
-	raster := rasterx.NewDasher(size, size, scanner)
-	icon.Draw(raster, 1.0)
-
-	return img
 }
 
 // drawText draws text with automatic truncation if it exceeds maxWidth.
 func (m *Module) drawText(img *image.RGBA, text string, x, y int, face font.Face, col color.Color, maxWidth int) {
 	// Truncate text if too long
-	truncated := truncateText(text, face, maxWidth)
+	truncated := render.TruncateText(text, face, maxWidth)
 
 	d := &font.Drawer{
 		Dst:  img,
@@ -215,32 +185,6 @@ func (m *Module) drawTextRightAligned(img *image.RGBA, text string, rightX, y in
 	d.DrawString(text)
 }
 
-// truncateText truncates text to fit within maxWidth, adding ellipsis if needed.
-func truncateText(text string, face font.Face, maxWidth int) string {
-	if maxWidth <= 0 {
-		return text
-	}
-
-	ellipsis := "..."
-
-	width := font.MeasureString(face, text).Ceil()
-	if width <= maxWidth {
-		return text
-	}
-
-	// Binary search for the right length
-	runes := []rune(text)
-	for i := len(runes); i > 0; i-- {
-		truncated := string(runes[:i]) + ellipsis
-		w := font.MeasureString(face, truncated).Ceil()
-		if w <= maxWidth {
-			return truncated
-		}
-	}
-
-	return ellipsis
-}
-
 // scaleImageSquare scales and crops an image to a square of the given size.
 func scaleImageSquare(src image.Image, size int) image.Image {
 	srcBounds := src.Bounds()
@@ -261,6 +205,54 @@ func scaleImageSquare(src image.Image, size int) image.Image {
 	return dst
 }
 
+// scaleImageCover scales src to fill a w x h rectangle, cropping the excess
+// from the center.
+func scaleImageCover(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	crop := b
+	if b.Dx()*h > b.Dy()*w {
+		cw := b.Dy() * w / h
+		crop.Min.X = b.Min.X + (b.Dx()-cw)/2
+		crop.Max.X = crop.Min.X + cw
+	} else {
+		ch := b.Dx() * h / w
+		crop.Min.Y = b.Min.Y + (b.Dy()-ch)/2
+		crop.Max.Y = crop.Min.Y + ch
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// renderBackdrop spreads the artwork across a w x h region, darkened so text
+// stays readable over it.
+func renderBackdrop(src image.Image, w, h int) image.Image {
+	img := scaleImageCover(src, w, h)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackdropShade}, image.Point{}, draw.Over)
+	return img
+}
+
+// splitAcrossKeys scales the artwork to a row of n keys and cuts it into one
+// tile per key.
+func splitAcrossKeys(src image.Image, n, size int) []image.Image {
+	row := scaleImageCover(src, n*size, size)
+	tiles := make([]image.Image, n)
+	for i := range tiles {
+		tile := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(tile, tile.Bounds(), row, image.Pt(i*size, 0), draw.Src)
+		tiles[i] = tile
+	}
+	return tiles
+}
+
+// renderBlankKey returns an empty key, shown on art keys with no artwork.
+func renderBlankKey(size int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+	return img
+}
+
 // decodeArtwork decodes base64 artwork data to an image.
 func decodeArtwork(artworkBase64 string) image.Image {
 	imgData, err := base64.StdEncoding.DecodeString(artworkBase64)
@@ -281,8 +273,3 @@ func formatDurationMicros(micros int64) string {
 	s := totalSeconds % 60
 	return fmt.Sprintf("%d:%02d", m, s)
 }
-
-// formatSeekPosition formats microseconds as seconds for the seek command.
-func formatSeekPosition(micros int64) string {
-	return fmt.Sprintf("%.1f", float64(micros)/1000000)
-}
@@ -0,0 +1,168 @@
+package nowplaying
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// volumeStep is the volume change per dial tick, in percent.
+const volumeStep = 2
+
+// volumeOverlayDuration is how long the volume bar stays on the strip after
+// the last turn or press of the volume dial.
+const volumeOverlayDuration = 1500 * time.Millisecond
+
+// volumeState tracks system output volume for the volume dial. Dial events
+// only record what they want; the volume worker talks to osascript so slow
+// calls never hold up input. Guarded by Module.mu.
+type volumeState struct {
+	level int // 0-100
+	muted bool
+	known bool // level and muted reflect the system
+
+	pendingDelta  int  // percent to add, accumulated from dial ticks
+	pendingToggle bool // toggle mute
+
+	shownUntil time.Time // volume bar is drawn until then
+}
+
+// adjustVolume queues a volume change of delta dial ticks and shows the
+// volume bar.
+func (m *Module) adjustVolume(delta int8) {
+	m.mu.Lock()
+	m.showVolumeLocked()
+	m.volume.pendingDelta += int(delta) * volumeStep
+	m.mu.Unlock()
+	m.wakeVolumeWorker()
+}
+
+// toggleMute queues a mute toggle and shows the volume bar.
+func (m *Module) toggleMute() {
+	m.mu.Lock()
+	m.showVolumeLocked()
+	m.volume.pendingToggle = !m.volume.pendingToggle
+	m.mu.Unlock()
+	m.wakeVolumeWorker()
+}
+
+// showVolumeLocked extends the volume bar's display. Volume may have
+// changed elsewhere while the bar was hidden, so a fresh showing rereads it.
+// Callers must hold m.mu.
+func (m *Module) showVolumeLocked() {
+	now := time.Now()
+	if now.After(m.volume.shownUntil) {
+		m.volume.known = false
+	}
+	m.volume.shownUntil = now.Add(volumeOverlayDuration)
+}
+
+func (m *Module) wakeVolumeWorker() {
+	select {
+	case m.volumeWake <- struct{}{}:
+	default:
+	}
+}
+
+// volumeWorker applies queued volume changes until the module stops.
+// Changes that arrive while one is being applied are merged into the next.
+func (m *Module) volumeWorker() {
+	for {
+		select {
+		case <-m.Context().Done():
+			return
+		case <-m.volumeWake:
+		}
+
+		m.mu.Lock()
+		delta, toggle, known := m.volume.pendingDelta, m.volume.pendingToggle, m.volume.known
+		level, muted := m.volume.level, m.volume.muted
+		m.volume.pendingDelta, m.volume.pendingToggle = 0, false
+		m.mu.Unlock()
+
+		if !known {
+			var err error
+			if level, muted, err = getVolume(); err != nil {
+				m.Log().Warn("Failed to read volume", "err", err)
+				continue
+			}
+		}
+
+		if delta != 0 {
+			level = max(0, min(100, level+delta))
+			if err := setVolume(level); err != nil {
+				m.Log().Warn("Failed to set volume", "err", err)
+			}
+			// Turning the volume up is a clear signal to unmute
+			if delta > 0 && muted && !toggle {
+				toggle = true
+			}
+		}
+		if toggle {
+			muted = !muted
+			if err := setMuted(muted); err != nil {
+				m.Log().Warn("Failed to set mute", "err", err)
+			}
+		}
+
+		m.mu.Lock()
+		m.volume.level, m.volume.muted, m.volume.known = level, muted, true
+		m.mu.Unlock()
+	}
+}
+
+// volumeOverlayLocked returns the volume to draw on the strip, or nil if the
+// volume bar isn't showing. Callers must hold m.mu.
+func (m *Module) volumeOverlayLocked() *volumeState {
+	if time.Now().After(m.volume.shownUntil) {
+		return nil
+	}
+	v := m.volume
+	return &v
+}
+
+// getVolume reads the system output volume (0-100) and mute state.
+func getVolume() (int, bool, error) {
+	out, err := exec.Command("osascript", "-e", "get volume settings").Output()
+	if err != nil {
+		return 0, false, fmt.Errorf("osascript: %w", err)
+	}
+	return parseVolumeSettings(string(out))
+}
+
+// parseVolumeSettings parses osascript's volume settings record, e.g.
+// "output volume:45, input volume:50, alert volume:100, output muted:false".
+func parseVolumeSettings(s string) (int, bool, error) {
+	level, muted, found := 0, false, false
+	for _, field := range strings.Split(strings.TrimSpace(s), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), ":")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "output volume":
+			// "missing value" for devices without software volume
+			if n, err := strconv.Atoi(value); err == nil {
+				level, found = n, true
+			}
+		case "output muted":
+			muted = value == "true"
+		}
+	}
+	if !found {
+		return 0, false, fmt.Errorf("no output volume in %q", s)
+	}
+	return level, muted, nil
+}
+
+// setVolume sets the system output volume (0-100).
+func setVolume(level int) error {
+	return exec.Command("osascript", "-e", fmt.Sprintf("set volume output volume %d", level)).Run()
+}
+
+// setMuted sets the system output mute state.
+func setMuted(muted bool) error {
+	return exec.Command("osascript", "-e", fmt.Sprintf("set volume output muted %t", muted)).Run()
+}
@@ -0,0 +1,265 @@
+// Package prompter provides a Stream Deck module that scrolls a text file
+// across the touch strip, for presentation notes or interview questions.
+//
+// Turning the module's dial scrolls the text a few pixels at a time;
+// turning it while pressed in changes the text size, and pressing it
+// without turning goes back to the top. The strip region is redrawn on its
+// own as the dial turns, so scrolling keeps up with the hand.
+package prompter
+
+import (
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// pollInterval is how often the file is checked for changes.
+const pollInterval = time.Second
+
+// Text sizes in points
+const (
+	defaultSize = 18
+	minSize     = 10
+	maxSize     = 40
+)
+
+// scrollStep is how far one dial detent scrolls, in pixels.
+const scrollStep = 6
+
+// stamp identifies a version of the file, to tell when it changes.
+type stamp struct {
+	mod  time.Time
+	size int64
+}
+
+// Module implements the teleprompter module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+	path   string
+
+	// State
+	mu      sync.Mutex
+	lines   []line
+	loaded  stamp
+	loadErr error
+	size    int  // text size in points
+	offset  int  // pixels scrolled from the top
+	held    bool // dial pressed in
+	resized bool // dial turned since it was pressed in
+
+	// Wrapped text for the current size, rebuilt when it's nil
+	rows   []row
+	height int // of all rows, in pixels
+
+	// Fonts
+	faces       map[int]faces // by size
+	messageFace font.Face
+
+	// Resources
+	resources module.Resources
+	enabled   bool
+}
+
+// New creates a new teleprompter module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("prompter"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "prompter"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+	m.faces = make(map[int]faces)
+	m.size = defaultSize
+	if m.appCfg != nil && m.appCfg.Prompter.FontSize != 0 {
+		m.size = m.appCfg.Prompter.FontSize
+	}
+
+	if m.appCfg == nil || m.appCfg.Prompter.File == "" {
+		m.Log().Warn("Module disabled", "err", "prompter.file is not set")
+		return nil
+	}
+	if !res.HasStrip() || !m.resources.Device.HasStrip() {
+		m.Log().Warn("Module disabled", "err", "no strip region")
+		return nil
+	}
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	m.path = config.ExpandHome(m.appCfg.Prompter.File)
+	m.enabled = true
+	go m.watch(ctx)
+
+	m.Log().Info("Module initialized", "file", m.path)
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// watch reloads the file every pollInterval if it changed.
+func (m *Module) watch(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		m.reload()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reload reads the file if it changed since it was last read, keeping the
+// scroll position.
+func (m *Module) reload() {
+	var s stamp
+	info, err := os.Stat(m.path)
+	if err == nil {
+		s = stamp{mod: info.ModTime(), size: info.Size()}
+	}
+
+	m.mu.Lock()
+	unchanged := err == nil && m.loadErr == nil && s == m.loaded
+	m.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	var lines []line
+	if err == nil {
+		var data []byte
+		if data, err = os.ReadFile(m.path); err == nil {
+			lines = parse(string(data), strings.EqualFold(filepath.Ext(m.path), ".md"))
+		}
+	}
+
+	m.mu.Lock()
+	if err != nil && (m.loadErr == nil || m.loadErr.Error() != err.Error()) {
+		m.Log().Warn("Failed to load file", "file", m.path, "err", err)
+	}
+	m.lines, m.loaded, m.loadErr = lines, s, err
+	m.rows = nil
+	m.mu.Unlock()
+	m.RefreshStrip()
+}
+
+// RenderStrip draws the visible part of the text.
+func (m *Module) RenderStrip() image.Image {
+	if !m.enabled {
+		return nil
+	}
+	rect := m.resources.Device.StripRect
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.layout()
+	return m.renderStrip(rect, m.resources.StripRect)
+}
+
+// HandleDial scrolls on rotation, or resizes the text while the dial is
+// pressed in. Pressing without turning goes back to the top.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	if !m.enabled {
+		return nil
+	}
+
+	m.mu.Lock()
+	switch event.Type {
+	case module.DialPress:
+		m.held, m.resized = true, false
+		m.mu.Unlock()
+		return nil
+	case module.DialRelease:
+		if !m.resized {
+			m.offset = 0
+		}
+		m.held, m.resized = false, false
+	case module.DialRotate:
+		if m.held {
+			m.resize(m.size + int(event.Delta))
+			m.resized = true
+		} else {
+			m.scroll(int(event.Delta) * scrollStep)
+		}
+	}
+	m.mu.Unlock()
+
+	m.RefreshStrip()
+	return nil
+}
+
+// HandleStripTouch goes back to the top on a tap.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	if !m.enabled || event.Type != module.TouchTap {
+		return nil
+	}
+
+	m.mu.Lock()
+	m.offset = 0
+	m.mu.Unlock()
+
+	m.RefreshStrip()
+	return nil
+}
+
+// scroll moves the text by delta pixels, no further than its ends. Must
+// be called with mu held.
+func (m *Module) scroll(delta int) {
+	m.layout()
+	m.offset = max(0, min(m.offset+delta, m.maxOffset()))
+}
+
+// resize changes the text size, keeping the reading position about where
+// it was. Must be called with mu held.
+func (m *Module) resize(size int) {
+	size = max(minSize, min(size, maxSize))
+	if size == m.size {
+		return
+	}
+	if _, err := m.facesFor(size); err != nil {
+		m.Log().Warn("Failed to create font", "size", size, "err", err)
+		return
+	}
+
+	m.layout()
+	oldHeight, oldOffset := m.height, m.offset
+	m.size, m.rows = size, nil
+	m.layout()
+	if oldHeight > 0 {
+		m.offset = oldOffset * m.height / oldHeight
+	}
+	m.offset = max(0, min(m.offset, m.maxOffset()))
+}
+
+// maxOffset is how far the text can scroll before its end comes into
+// view. Must be called with mu held, after layout.
+func (m *Module) maxOffset() int {
+	return max(0, m.height-(m.resources.StripRect.Dy()-2*padY))
+}
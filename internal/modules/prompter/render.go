@@ -0,0 +1,150 @@
+package prompter
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// Margins around the text, in pixels
+const (
+	padX = 14
+	padY = 8
+)
+
+var colorScrollbar = color.RGBA{120, 120, 120, 255}
+
+// faces are the fonts for one text size.
+type faces struct {
+	text font.Face
+	head font.Face
+}
+
+// row is a wrapped line ready to draw, y pixels below the top of the
+// text. A row without text is the gap between paragraphs.
+type row struct {
+	text string
+	face font.Face
+	y    int
+}
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.messageFace, err = render.NewFace(render.Regular, 13); err != nil {
+		return err
+	}
+	_, err = m.facesFor(m.size)
+	return err
+}
+
+// facesFor returns the fonts for size, creating them the first time.
+func (m *Module) facesFor(size int) (faces, error) {
+	if f, ok := m.faces[size]; ok {
+		return f, nil
+	}
+	text, err := render.NewFace(render.Regular, float64(size))
+	if err != nil {
+		return faces{}, err
+	}
+	head, err := render.NewFace(render.Bold, float64(size))
+	if err != nil {
+		return faces{}, err
+	}
+	m.faces[size] = faces{text: text, head: head}
+	return m.faces[size], nil
+}
+
+// lineHeight is the distance between rows of text at size.
+func lineHeight(size int) int {
+	return size * 13 / 10
+}
+
+// layout wraps the text to the strip region at the current size, if it
+// isn't already, and keeps the scroll position within it. Must be called
+// with mu held.
+func (m *Module) layout() {
+	if m.rows != nil {
+		return
+	}
+
+	f := m.faces[m.size]
+	lh := lineHeight(m.size)
+	width := m.resources.StripRect.Dx() - 2*padX
+	rows := []row{} // not nil, even when empty, as nil means stale
+	y := 0
+	for _, l := range m.lines {
+		if l.text == "" {
+			rows = append(rows, row{y: y})
+			y += lh / 2
+			continue
+		}
+		face := f.text
+		if l.heading {
+			face = f.head
+		}
+		for _, text := range render.WrapText(l.text, face, width, 0) {
+			rows = append(rows, row{text: text, face: face, y: y})
+			y += lh
+		}
+	}
+	m.rows, m.height = rows, y
+	m.offset = max(0, min(m.offset, m.maxOffset()))
+}
+
+// renderStrip draws the rows in view in the module's strip region, with a
+// scrollbar when there's more than fits, and the text size while the dial
+// is pressed in. Must be called with mu held, after layout.
+func (m *Module) renderStrip(rect, region image.Rectangle) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+
+	if m.loadErr != nil || len(m.rows) == 0 {
+		msg := "Nothing to show"
+		if m.loadErr != nil {
+			msg = m.loadErr.Error()
+		}
+		msg = render.TruncateText(msg, m.messageFace, region.Dx()-20)
+		render.DrawTextCentered(img, msg, region.Min.X+region.Dx()/2, region.Min.Y+region.Dy()/2+5, m.messageFace, render.ColorGray)
+		return img
+	}
+
+	// Draw into the region alone so rows partly scrolled out are cut off
+	// at its edges rather than spilling onto the rest of the strip
+	canvas := img.SubImage(region).(*image.RGBA)
+	lh := lineHeight(m.size)
+	ascent := m.faces[m.size].text.Metrics().Ascent.Ceil()
+	top := region.Min.Y + padY - m.offset
+	for _, r := range m.rows {
+		y := top + r.y
+		if y+lh < region.Min.Y || r.text == "" {
+			continue
+		}
+		if y > region.Max.Y {
+			break
+		}
+		render.DrawText(canvas, r.text, region.Min.X+padX, y+ascent, r.face, render.ColorWhite)
+	}
+
+	// Scrollbar: a thumb as tall as the share of the text in view
+	if view := region.Dy() - 2*padY; m.height > view {
+		track := region.Dy() - 4
+		thumb := max(8, track*view/m.height)
+		y := region.Min.Y + 2 + (track-thumb)*m.offset/m.maxOffset()
+		bar := image.Rect(region.Max.X-4, y, region.Max.X-2, y+thumb)
+		draw.Draw(img, bar, &image.Uniform{colorScrollbar}, image.Point{}, draw.Src)
+	}
+
+	if m.held {
+		label := fmt.Sprintf("%d pt", m.size)
+		w := font.MeasureString(m.messageFace, label).Ceil() + 12
+		badge := image.Rect(region.Max.X-w-8, region.Min.Y+4, region.Max.X-8, region.Min.Y+22)
+		draw.Draw(img, badge, &image.Uniform{render.ColorDimGray}, image.Point{}, draw.Src)
+		render.DrawTextCentered(img, label, badge.Min.X+w/2, badge.Max.Y-5, m.messageFace, render.ColorWhite)
+	}
+	return img
+}
@@ -0,0 +1,46 @@
+package prompter
+
+import "strings"
+
+// line is a paragraph of the file; headings are drawn bold. An empty line
+// stands for the blank lines between paragraphs.
+type line struct {
+	text    string
+	heading bool
+}
+
+// parse splits text into lines, collapsing runs of blank lines into one.
+// For markdown, headings are kept as bold lines, list items get bullets,
+// and inline markup and code fences are dropped.
+func parse(text string, markdown bool) []line {
+	emphasis := strings.NewReplacer("**", "", "__", "", "`", "")
+	var lines []line
+	for _, s := range strings.Split(text, "\n") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			if len(lines) > 0 && lines[len(lines)-1].text != "" {
+				lines = append(lines, line{})
+			}
+			continue
+		}
+		l := line{text: s}
+		if markdown {
+			if strings.HasPrefix(s, "```") {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(s, "#"):
+				l.heading = true
+				s = strings.TrimSpace(strings.TrimLeft(s, "#"))
+			case strings.HasPrefix(s, "- "), strings.HasPrefix(s, "* "), strings.HasPrefix(s, "+ "):
+				s = "• " + strings.TrimSpace(s[2:])
+			}
+			l.text = emphasis.Replace(s)
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) > 0 && lines[len(lines)-1].text == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
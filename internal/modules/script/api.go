@@ -0,0 +1,248 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/httpx"
+	"github.com/phinze/belowdeck/internal/render"
+	lua "github.com/yuin/gopher-lua"
+	"golang.org/x/image/font"
+)
+
+// maxBodySize caps how much of an HTTP response a script can read.
+const maxBodySize = 1 << 20
+
+// httpClient is shared by all scripts; the Lua call's context bounds each request.
+var httpClient = httpx.New("script", 15*time.Second)
+
+// registerAPI installs the draw, http, and shell tables and the log function
+// into a script's Lua state.
+func (s *tile) registerAPI(L *lua.LState) {
+	L.SetGlobal("draw", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"fill": s.luaFill,
+		"text": s.luaText,
+		"icon": s.luaIcon,
+	}))
+	L.SetGlobal("http", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"get":  luaHTTPGet,
+		"post": luaHTTPPost,
+	}))
+	L.SetGlobal("shell", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"exec": luaShellExec,
+	}))
+	L.SetGlobal("log", L.NewFunction(s.luaLog))
+}
+
+// canvasOrRaise returns the key image being drawn, raising a Lua error when
+// draw.* is called outside render().
+func (s *tile) canvasOrRaise(L *lua.LState) *image.RGBA {
+	if s.canvas == nil {
+		L.RaiseError("draw functions can only be called from render()")
+	}
+	return s.canvas
+}
+
+// luaFill implements draw.fill(color).
+func (s *tile) luaFill(L *lua.LState) int {
+	canvas := s.canvasOrRaise(L)
+	c, err := parseColor(L.CheckString(1))
+	if err != nil {
+		L.ArgError(1, err.Error())
+	}
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{c}, image.Point{}, draw.Src)
+	return 0
+}
+
+// luaText implements draw.text(str, {x, y, size, bold, color}). Text is
+// centered on x (default: the key's center) with its baseline at y.
+func (s *tile) luaText(L *lua.LState) int {
+	canvas := s.canvasOrRaise(L)
+	text := L.CheckString(1)
+	opts := L.OptTable(2, L.NewTable())
+
+	size := optNumber(opts, "size", 14)
+	weight := render.Regular
+	if lua.LVAsBool(opts.RawGetString("bold")) {
+		weight = render.Bold
+	}
+	col, err := parseColor(optString(opts, "color", "#ffffff"))
+	if err != nil {
+		L.ArgError(2, err.Error())
+	}
+
+	face, err := s.face(weight, size)
+	if err != nil {
+		L.RaiseError("font: %v", err)
+	}
+	x := int(optNumber(opts, "x", render.KeySize/2))
+	y := int(optNumber(opts, "y", render.KeySize/2+5))
+	text = render.TruncateText(text, face, render.KeySize-6)
+	render.DrawTextCentered(canvas, text, x, y, face, col)
+	return 0
+}
+
+// luaIcon implements draw.icon(spec, {y, size, color}). spec is an image file
+// path (containing a slash) or an icon name, as in the launcher.
+func (s *tile) luaIcon(L *lua.LState) int {
+	canvas := s.canvasOrRaise(L)
+	spec := L.CheckString(1)
+	opts := L.OptTable(2, L.NewTable())
+
+	size := int(optNumber(opts, "size", 32))
+	y := int(optNumber(opts, "y", 8))
+	colSpec := optString(opts, "color", "#ffffff")
+	col, err := parseColor(colSpec)
+	if err != nil {
+		L.ArgError(2, err.Error())
+	}
+
+	key := fmt.Sprintf("%s|%d|%s", spec, size, colSpec)
+	icon, ok := s.icons[key]
+	if !ok {
+		if icon, err = render.FindIcon(spec, size, col); err != nil {
+			s.log.Warn("Failed to load icon", "icon", spec, "err", err)
+		}
+		s.icons[key] = icon // cache failures too, so a bad spec isn't retried every frame
+	}
+	if icon != nil {
+		render.DrawIcon(canvas, icon, y)
+	}
+	return 0
+}
+
+// luaLog implements log(...), writing the arguments to the module log.
+func (s *tile) luaLog(L *lua.LState) int {
+	parts := make([]string, L.GetTop())
+	for i := range parts {
+		parts[i] = L.ToStringMeta(L.Get(i + 1)).String()
+	}
+	s.log.Info(strings.Join(parts, " "))
+	return 0
+}
+
+// luaHTTPGet implements http.get(url) -> body, status | nil, err.
+func luaHTTPGet(L *lua.LState) int {
+	req, err := http.NewRequestWithContext(luaContext(L), http.MethodGet, L.CheckString(1), nil)
+	if err != nil {
+		return pushError(L, err)
+	}
+	return doRequest(L, req)
+}
+
+// luaHTTPPost implements http.post(url, body, content_type) -> body, status | nil, err.
+func luaHTTPPost(L *lua.LState) int {
+	req, err := http.NewRequestWithContext(luaContext(L), http.MethodPost, L.CheckString(1), strings.NewReader(L.OptString(2, "")))
+	if err != nil {
+		return pushError(L, err)
+	}
+	req.Header.Set("Content-Type", L.OptString(3, "application/json"))
+	return doRequest(L, req)
+}
+
+func doRequest(L *lua.LState, req *http.Request) int {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LString(body))
+	L.Push(lua.LNumber(resp.StatusCode))
+	return 2
+}
+
+// luaShellExec implements shell.exec(cmd) -> output, exit_code, running cmd
+// with /bin/sh -c and returning combined stdout and stderr.
+func luaShellExec(L *lua.LState) int {
+	cmd := exec.CommandContext(luaContext(L), "/bin/sh", "-c", L.CheckString(1))
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	code := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return pushError(L, err)
+		}
+		code = exitErr.ExitCode()
+	}
+	L.Push(lua.LString(strings.TrimRight(out.String(), "\n")))
+	L.Push(lua.LNumber(code))
+	return 2
+}
+
+// luaContext returns the deadline set for the current call, so HTTP and
+// shell calls give up when the script's time budget runs out.
+func luaContext(L *lua.LState) context.Context {
+	if ctx := L.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func pushError(L *lua.LState, err error) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+	return 2
+}
+
+func optNumber(t *lua.LTable, key string, def float64) float64 {
+	if v, ok := t.RawGetString(key).(lua.LNumber); ok {
+		return float64(v)
+	}
+	return def
+}
+
+func optString(t *lua.LTable, key, def string) string {
+	if v, ok := t.RawGetString(key).(lua.LString); ok {
+		return string(v)
+	}
+	return def
+}
+
+// face returns a cached font face for the weight and size.
+func (s *tile) face(w render.Weight, size float64) (font.Face, error) {
+	key := fmt.Sprintf("%d|%g", w, size)
+	if f, ok := s.faces[key]; ok {
+		return f, nil
+	}
+	f, err := render.NewFace(w, size)
+	if err != nil {
+		return nil, err
+	}
+	s.faces[key] = f
+	return f, nil
+}
+
+// parseColor parses "#rgb" or "#rrggbb".
+func parseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, want #rrggbb", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, want #rrggbb", s)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
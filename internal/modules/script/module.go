@@ -0,0 +1,319 @@
+// Package script provides a Stream Deck module of user-scripted Lua tiles.
+//
+// Each *.lua file in the scripts directory (default
+// ~/.config/belowdeck/scripts) drives one key, in filename order. A script
+// may define these globals:
+//
+//	interval = 60            -- seconds between update() calls
+//	function update() end    -- fetch state; may call http.* and shell.exec
+//	function render() end    -- draw the key with draw.fill/text/icon
+//	function press(ms) end   -- called on key release with the hold time
+//
+// render runs on every frame with a short time budget, so slow work belongs
+// in update. While update or press is running, the key keeps showing its
+// last frame, so a slow script never holds up the rest of the deck.
+package script
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	lua "github.com/yuin/gopher-lua"
+	"golang.org/x/image/font"
+)
+
+// Time budgets for script calls.
+const (
+	renderTimeout = 250 * time.Millisecond
+	actionTimeout = 30 * time.Second
+)
+
+// defaultInterval is used when a script doesn't set interval.
+const defaultInterval = 60 * time.Second
+
+// colorError marks the top edge of a tile whose script failed.
+var colorError = color.RGBA{200, 60, 60, 255}
+
+// tile is one loaded script bound to a key. Lua states aren't safe for
+// concurrent use, so every call into L holds mu.
+type tile struct {
+	mu   sync.Mutex
+	name string
+	key  module.KeyID
+	L    *lua.LState
+	log  *slog.Logger
+
+	canvas *image.RGBA  // set only while render() runs
+	last   atomic.Value // image.Image: last frame rendered, shown while mu is busy
+	faces  map[string]font.Face
+	icons  map[string]image.Image
+
+	lastErr string // last error logged, to avoid repeating it every frame
+}
+
+// Module implements the script module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+
+	tiles []*tile
+
+	nameFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new script module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("script"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "script"
+}
+
+// Init loads the scripts and starts their update loops.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var err error
+	if m.nameFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(config.DefaultConfigDir(), "scripts")
+	if m.appCfg != nil && m.appCfg.Script.Dir != "" {
+		dir = m.appCfg.Script.Dir
+	}
+	paths, err := filepath.Glob(filepath.Join(config.ExpandHome(dir), "*.lua"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	for i, path := range paths {
+		if i >= len(res.Keys) {
+			m.Log().Warn("No key available, skipping script", "script", path)
+			continue
+		}
+		t, err := m.load(path, res.Keys[i])
+		if err != nil {
+			// Keep the tile so its key shows the failure
+			m.Log().Warn("Failed to load script", "script", path, "err", err)
+		}
+		m.tiles = append(m.tiles, t)
+		if t.L != nil {
+			go m.updateLoop(t)
+		}
+	}
+
+	m.Log().Info("Module initialized", "dir", dir, "scripts", len(m.tiles))
+	return nil
+}
+
+// Stop closes every script's Lua state.
+func (m *Module) Stop() error {
+	m.BaseModule.Stop()
+	for _, t := range m.tiles {
+		t.mu.Lock()
+		if t.L != nil {
+			t.L.Close()
+			t.L = nil
+		}
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+// load compiles and runs a script's top level.
+func (m *Module) load(path string, key module.KeyID) (*tile, error) {
+	name := strings.TrimSuffix(filepath.Base(path), ".lua")
+	t := &tile{
+		name:  name,
+		key:   key,
+		log:   m.Log().With("script", name),
+		faces: make(map[string]font.Face),
+		icons: make(map[string]image.Image),
+	}
+
+	L := lua.NewState()
+	t.registerAPI(L)
+	if err := L.DoFile(path); err != nil {
+		L.Close()
+		t.lastErr = err.Error()
+		return t, err
+	}
+	t.L = L
+	return t, nil
+}
+
+// updateLoop calls the script's update function now and every interval.
+func (m *Module) updateLoop(t *tile) {
+	t.mu.Lock()
+	interval := defaultInterval
+	if n, ok := t.L.GetGlobal("interval").(lua.LNumber); ok && n > 0 {
+		interval = time.Duration(float64(n) * float64(time.Second))
+	}
+	_, hasUpdate := t.L.GetGlobal("update").(*lua.LFunction)
+	t.mu.Unlock()
+	if !hasUpdate {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.call(t, "update", actionTimeout)
+		select {
+		case <-m.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// call runs a global function in the script, if defined, within timeout.
+// The caller must not hold t.mu.
+func (m *Module) call(t *tile, fn string, timeout time.Duration, args ...lua.LValue) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.callLocked(m.Context(), fn, timeout, args...)
+}
+
+// callLocked runs fn with t.mu held, logging errors when they change.
+func (t *tile) callLocked(ctx context.Context, fn string, timeout time.Duration, args ...lua.LValue) bool {
+	if t.L == nil {
+		return false
+	}
+	f, ok := t.L.GetGlobal(fn).(*lua.LFunction)
+	if !ok {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	t.L.SetContext(ctx)
+	defer t.L.RemoveContext()
+
+	if err := t.L.CallByParam(lua.P{Fn: f, NRet: 0, Protect: true}, args...); err != nil {
+		if msg := err.Error(); msg != t.lastErr {
+			t.log.Warn("Script error", "func", fn, "err", err)
+			t.lastErr = msg
+		}
+		return false
+	}
+	if fn == "render" {
+		t.lastErr = ""
+	}
+	return true
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	keys := make(map[module.KeyID]image.Image)
+	for _, t := range m.tiles {
+		keys[t.key] = m.renderTile(t)
+	}
+	return keys
+}
+
+// renderTile runs the script's render function onto a fresh key image. A
+// script without one shows its name; a failed script shows an error tile.
+// If update or press is running, which can take up to actionTimeout, the
+// tile's last frame is returned instead of waiting: the render pass would
+// otherwise freeze every key and trip the coordinator's stall watchdog.
+func (m *Module) renderTile(t *tile) image.Image {
+	if !t.mu.TryLock() {
+		if last, ok := t.last.Load().(image.Image); ok {
+			return last
+		}
+		return m.renderName(t.name, false)
+	}
+	defer t.mu.Unlock()
+	img := m.renderTileLocked(t)
+	t.last.Store(img)
+	return img
+}
+
+// renderTileLocked is renderTile with t.mu held.
+func (m *Module) renderTileLocked(t *tile) image.Image {
+	if t.L == nil {
+		return m.renderName(t.name, true)
+	}
+	if _, ok := t.L.GetGlobal("render").(*lua.LFunction); !ok {
+		return m.renderName(t.name, false)
+	}
+
+	t.canvas = render.NewKey(render.ColorKeyBg)
+	defer func() { t.canvas = nil }()
+	if !t.callLocked(m.Context(), "render", renderTimeout) {
+		return m.renderName(t.name, true)
+	}
+	return t.canvas
+}
+
+// renderName draws a tile labeled with the script name, marked as failed if
+// failed is set.
+func (m *Module) renderName(name string, failed bool) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+	label := render.TruncateText(name, m.nameFace, render.KeySize-6)
+	if !failed {
+		render.DrawTextCentered(img, label, render.KeySize/2, render.KeySize/2+5, m.nameFace, render.ColorWhite)
+		return img
+	}
+	draw.Draw(img, image.Rect(0, 0, render.KeySize, 4), &image.Uniform{colorError}, image.Point{}, draw.Src)
+	render.DrawTextCentered(img, label, render.KeySize/2, 34, m.nameFace, render.ColorWhite)
+	render.DrawTextCentered(img, "error", render.KeySize/2, 54, m.nameFace, render.ColorGray)
+	return img
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey calls the script's press function on release, in the background
+// so slow scripts don't hold up input.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if event.Pressed {
+		return nil
+	}
+	for _, t := range m.tiles {
+		if t.key == id {
+			go m.call(t, "press", actionTimeout, lua.LNumber(event.Duration.Milliseconds()))
+		}
+	}
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
@@ -0,0 +1,58 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// A script whose update is slow mustn't hold up rendering: the render pass
+// is watched for stalls, and every key waits on it.
+func TestSlowUpdateDoesNotBlockRender(t *testing.T) {
+	dir := t.TempDir()
+	script := `
+function update()
+  local start = os.clock()
+  while os.clock() - start < 2 do end
+end
+
+function render()
+  draw.fill("#336699")
+end
+`
+	if err := os.WriteFile(filepath.Join(dir, "slow.lua"), []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Script.Dir = dir
+	m := New(cfg)
+	if err := m.Init(context.Background(), module.Resources{Keys: []module.KeyID{1}}); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	// Let the update loop take the tile's lock
+	deadline := time.Now().Add(time.Second)
+	for m.tiles[0].mu.TryLock() {
+		m.tiles[0].mu.Unlock()
+		if time.Now().After(deadline) {
+			t.Fatal("update never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	keys := m.RenderKeys()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("RenderKeys took %v while update ran, want it to return promptly", elapsed)
+	}
+	if keys[1] == nil {
+		t.Error("RenderKeys returned no image for the script's key")
+	}
+}
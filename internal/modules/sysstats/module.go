@@ -0,0 +1,186 @@
+// Package sysstats provides a Stream Deck module for live CPU, memory, and network usage.
+package sysstats
+
+import (
+	"context"
+	"image"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// Module implements the system stats module.
+type Module struct {
+	module.BaseModule
+
+	// State
+	mu       sync.RWMutex
+	latest   sample
+	history  [numMetrics][]float64
+	selected metric
+
+	// Fonts
+	labelFace font.Face
+	valueFace font.Face
+	keyFace   font.Face
+
+	// Resources
+	resources module.Resources
+
+	// Cancel function for sampling
+	pollCancel context.CancelFunc
+}
+
+// New creates a new system stats module.
+func New() *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("sysstats"),
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "sysstats"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	m.pollCancel = cancel
+	go m.pollStats(pollCtx)
+
+	m.Log().Info("Module initialized")
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	if m.pollCancel != nil {
+		m.pollCancel()
+	}
+	return m.BaseModule.Stop()
+}
+
+// pollStats samples system stats once per second.
+func (m *Module) pollStats(ctx context.Context) {
+	var s sampler
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		smp, err := s.collect(ctx)
+		if err != nil {
+			m.Log().Warn("Sample error", "err", err)
+		} else {
+			m.record(smp)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record stores a sample and appends it to each metric's history.
+func (m *Module) record(smp sample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.latest = smp
+	for mt := metric(0); mt < numMetrics; mt++ {
+		h := append(m.history[mt], smp.value(mt))
+		if len(h) > historyLen {
+			h = h[len(h)-historyLen:]
+		}
+		m.history[mt] = h
+	}
+}
+
+// getState returns the latest sample, a copy of the selected metric's history,
+// and the selected metric.
+func (m *Module) getState() (sample, []float64, metric) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h := append([]float64(nil), m.history[m.selected]...)
+	return m.latest, h, m.selected
+}
+
+// cycleMetric moves the strip graph to the next (or previous) metric.
+func (m *Module) cycleMetric(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.selected = metric((int(m.selected) + delta%int(numMetrics) + int(numMetrics)) % int(numMetrics))
+	m.Log().Debug("Showing metric", "metric", m.selected)
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	latest, _, _ := m.getState()
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderCoresKey(latest),
+	}
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	if !m.resources.HasStrip() || !m.resources.Device.HasStrip() {
+		return nil
+	}
+
+	rect := m.resources.Device.StripRect
+
+	latest, history, selected := m.getState()
+	return m.renderStrip(rect, m.resources.StripRect, latest, history, selected)
+}
+
+// HandleKey opens Activity Monitor.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	m.Log().Debug("Opening Activity Monitor")
+	go exec.Command("open", "-a", "Activity Monitor").Run()
+	return nil
+}
+
+// HandleDial switches the metric shown on the strip graph.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	switch event.Type {
+	case module.DialRotate:
+		if event.Delta != 0 {
+			m.cycleMetric(int(event.Delta))
+		}
+	case module.DialPress:
+		m.cycleMetric(1)
+	}
+	return nil
+}
+
+// HandleStripTouch cycles the strip graph on tap.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	if event.Type == module.TouchTap {
+		m.cycleMetric(1)
+	}
+	return nil
+}
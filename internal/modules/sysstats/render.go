@@ -0,0 +1,174 @@
+package sysstats
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+)
+
+// Common colors
+var (
+	colorBackground = color.RGBA{25, 25, 25, 255}
+	colorKeyBg      = color.RGBA{40, 40, 40, 255}
+	colorWhite      = color.RGBA{255, 255, 255, 255}
+	colorGray       = color.RGBA{160, 160, 160, 255}
+	colorDimGray    = color.RGBA{80, 80, 80, 255}
+	colorGridLine   = color.RGBA{50, 50, 50, 255}
+)
+
+// metricColors gives each metric's graph its own color.
+var metricColors = [numMetrics]color.RGBA{
+	metricCPU:     {80, 200, 120, 255},  // Green
+	metricMemory:  {100, 160, 255, 255}, // Blue
+	metricNetwork: {255, 170, 60, 255},  // Orange
+}
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.valueFace, err = render.NewFace(render.Bold, 26); err != nil {
+		return err
+	}
+	if m.keyFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+	if m.labelFace, err = render.NewFace(render.Regular, 14); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderCoresKey renders per-core CPU load as a row of vertical bars.
+func (m *Module) renderCoresKey(latest sample) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	render.DrawText(img, fmt.Sprintf("CPU %.0f%%", latest.cpu), 6, 18, m.keyFace, colorWhite)
+
+	n := len(latest.cores)
+	if n == 0 {
+		return img
+	}
+
+	// Bars fill the area below the label, with a 1px gap when there's room
+	area := image.Rect(6, 26, keySize-6, keySize-6)
+	barW := area.Dx() / n
+	gap := 1
+	if barW < 3 {
+		gap = 0
+	}
+	if barW < 1 {
+		barW = 1
+	}
+
+	col := metricColors[metricCPU]
+	for i, load := range latest.cores {
+		x := area.Min.X + i*barW
+		if x+barW-gap > area.Max.X {
+			break
+		}
+		draw.Draw(img, image.Rect(x, area.Min.Y, x+barW-gap, area.Max.Y), &image.Uniform{colorGridLine}, image.Point{}, draw.Src)
+
+		h := int(float64(area.Dy()) * clamp(load/100, 0, 1))
+		if h > 0 {
+			draw.Draw(img, image.Rect(x, area.Max.Y-h, x+barW-gap, area.Max.Y), &image.Uniform{col}, image.Point{}, draw.Src)
+		}
+	}
+
+	return img
+}
+
+// renderStrip renders the selected metric's reading and sparkline within the
+// module's strip region, leaving the rest of the image transparent.
+func (m *Module) renderStrip(rect, region image.Rectangle, latest sample, history []float64, selected metric) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	col := metricColors[selected]
+
+	// Left column: metric label, current value, and which metric is selected
+	textX := region.Min.X + 15
+	render.DrawText(img, selected.String(), textX, region.Min.Y+30, m.labelFace, colorGray)
+	render.DrawText(img, formatValue(selected, latest.value(selected)), textX, region.Min.Y+62, m.valueFace, colorWhite)
+
+	for mt := metric(0); mt < numMetrics; mt++ {
+		dot := colorDimGray
+		if mt == selected {
+			dot = col
+		}
+		x := textX + int(mt)*14
+		draw.Draw(img, image.Rect(x, region.Min.Y+78, x+8, region.Min.Y+82), &image.Uniform{dot}, image.Point{}, draw.Src)
+	}
+
+	// Right side: sparkline
+	graphX := region.Min.X + 140
+	if region.Dx() < 260 {
+		graphX = region.Min.X + region.Dx()/2
+	}
+	graph := image.Rect(graphX, region.Min.Y+12, region.Max.X-12, region.Max.Y-12)
+	if graph.Dx() <= 0 {
+		return img
+	}
+
+	maxVal := 100.0
+	if selected == metricNetwork {
+		// Auto-scale throughput to the busiest sample on screen
+		maxVal = 0
+		for _, v := range history {
+			if v > maxVal {
+				maxVal = v
+			}
+		}
+	}
+	drawSparkline(img, graph, history, maxVal, col)
+
+	return img
+}
+
+// drawSparkline draws the most recent samples right-aligned in dst as a filled
+// area graph with a brighter top line. One pixel column per sample.
+func drawSparkline(img *image.RGBA, dst image.Rectangle, values []float64, maxVal float64, col color.RGBA) {
+	// Baseline
+	draw.Draw(img, image.Rect(dst.Min.X, dst.Max.Y-1, dst.Max.X, dst.Max.Y), &image.Uniform{colorGridLine}, image.Point{}, draw.Src)
+
+	if len(values) == 0 || maxVal <= 0 {
+		return
+	}
+
+	// Stretch samples so the graph spans the whole area once history fills up
+	colW := dst.Dx() / historyLen
+	if colW < 1 {
+		colW = 1
+	}
+	if len(values)*colW > dst.Dx() {
+		values = values[len(values)-dst.Dx()/colW:]
+	}
+
+	fill := color.RGBA{col.R / 3, col.G / 3, col.B / 3, 255}
+	x := dst.Max.X - len(values)*colW
+	for _, v := range values {
+		h := int(float64(dst.Dy()) * clamp(v/maxVal, 0, 1))
+		if h > 0 {
+			top := dst.Max.Y - h
+			draw.Draw(img, image.Rect(x, top, x+colW, dst.Max.Y), &image.Uniform{fill}, image.Point{}, draw.Src)
+			draw.Draw(img, image.Rect(x, top, x+colW, top+2), &image.Uniform{col}, image.Point{}, draw.Src)
+		}
+		x += colW
+	}
+}
+
+// clamp limits v to [lo, hi].
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
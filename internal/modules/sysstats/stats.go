@@ -0,0 +1,129 @@
+package sysstats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+// metric identifies which series the strip graph shows.
+type metric int
+
+const (
+	metricCPU metric = iota
+	metricMemory
+	metricNetwork
+	numMetrics
+)
+
+// String returns the short label shown on the strip.
+func (mt metric) String() string {
+	switch mt {
+	case metricCPU:
+		return "CPU"
+	case metricMemory:
+		return "RAM"
+	case metricNetwork:
+		return "NET"
+	}
+	return "?"
+}
+
+// historyLen is the number of samples kept per metric (one per second).
+const historyLen = 120
+
+// sample is a single reading of all metrics.
+type sample struct {
+	cores   []float64 // per-core CPU load, percent
+	cpu     float64   // overall CPU load, percent
+	memory  float64   // used memory, percent
+	network float64   // combined rx+tx throughput, bytes/sec
+}
+
+// sampler collects system stats, tracking network counters between calls.
+type sampler struct {
+	lastNetBytes uint64
+	lastNetTime  time.Time
+}
+
+// collect reads current CPU, memory, and network usage. CPU load is measured
+// since the previous call, so the first sample reflects load since boot.
+func (s *sampler) collect(ctx context.Context) (sample, error) {
+	var smp sample
+
+	cores, err := cpu.PercentWithContext(ctx, 0, true)
+	if err != nil {
+		return smp, fmt.Errorf("cpu: %w", err)
+	}
+	smp.cores = cores
+	for _, c := range cores {
+		smp.cpu += c
+	}
+	if len(cores) > 0 {
+		smp.cpu /= float64(len(cores))
+	}
+
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return smp, fmt.Errorf("memory: %w", err)
+	}
+	smp.memory = vm.UsedPercent
+
+	counters, err := net.IOCountersWithContext(ctx, false)
+	if err != nil {
+		return smp, fmt.Errorf("network: %w", err)
+	}
+	if len(counters) > 0 {
+		now := time.Now()
+		total := counters[0].BytesRecv + counters[0].BytesSent
+		if !s.lastNetTime.IsZero() && total >= s.lastNetBytes {
+			elapsed := now.Sub(s.lastNetTime).Seconds()
+			if elapsed > 0 {
+				smp.network = float64(total-s.lastNetBytes) / elapsed
+			}
+		}
+		s.lastNetBytes = total
+		s.lastNetTime = now
+	}
+
+	return smp, nil
+}
+
+// value returns the reading for a metric.
+func (smp sample) value(mt metric) float64 {
+	switch mt {
+	case metricCPU:
+		return smp.cpu
+	case metricMemory:
+		return smp.memory
+	case metricNetwork:
+		return smp.network
+	}
+	return 0
+}
+
+// formatValue formats a metric reading for display.
+func formatValue(mt metric, v float64) string {
+	if mt == metricNetwork {
+		return formatRate(v)
+	}
+	return fmt.Sprintf("%.0f%%", v)
+}
+
+// formatRate formats a byte rate with a binary unit suffix.
+func formatRate(bytesPerSec float64) string {
+	switch {
+	case bytesPerSec >= 1<<30:
+		return fmt.Sprintf("%.1f GB/s", bytesPerSec/(1<<30))
+	case bytesPerSec >= 1<<20:
+		return fmt.Sprintf("%.1f MB/s", bytesPerSec/(1<<20))
+	case bytesPerSec >= 1<<10:
+		return fmt.Sprintf("%.0f KB/s", bytesPerSec/(1<<10))
+	default:
+		return fmt.Sprintf("%.0f B/s", bytesPerSec)
+	}
+}
@@ -0,0 +1,208 @@
+// Package totp provides a Stream Deck module that shows live one-time codes
+// for accounts whose TOTP secrets are stored in Keychain.
+//
+// Codes are never logged: a press copies the key's code to the clipboard
+// and says so by the account's label, and the clipboard is cleared again
+// after clearAfter unless something else has been copied since.
+package totp
+
+import (
+	"context"
+	"image"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/action"
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// clearAfter is how long a copied code stays on the clipboard.
+const clearAfter = 15 * time.Second
+
+// account is a TOTP account bound to a key.
+type account struct {
+	label string
+	key   module.KeyID
+	gen   generator
+	err   string // why there are no codes, if there aren't, for the key
+}
+
+// Module implements the TOTP module.
+type Module struct {
+	module.BaseModule
+
+	appCfg *config.Config
+
+	accounts []*account
+
+	// State
+	mu         sync.Mutex
+	clearTimer *time.Timer // clears the last code copied
+	copied     string      // the last code copied, until it's cleared
+
+	// Fonts
+	labelFace     font.Face
+	codeFace      font.Face
+	smallCodeFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new TOTP module.
+func New(appCfg *config.Config) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("totp"),
+		appCfg:     appCfg,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "totp"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var accounts []config.TOTPAccount
+	if m.appCfg != nil {
+		accounts = m.appCfg.TOTP.Accounts
+	}
+	for i, a := range accounts {
+		if i >= len(res.Keys) {
+			m.Log().Warn("No key available, skipping account", "account", a.Label)
+			continue
+		}
+		acct := &account{label: a.Label, key: res.Keys[i]}
+		if err := acct.setup(a); err != nil {
+			m.Log().Warn("Account has no codes", "account", a.Label, "err", err)
+			acct.err = "Bad secret"
+		}
+		m.accounts = append(m.accounts, acct)
+	}
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	m.Log().Info("Module initialized", "accounts", len(m.accounts))
+	return nil
+}
+
+// setup prepares the account's code generator from its settings.
+func (a *account) setup(cfg config.TOTPAccount) error {
+	if cfg.Secret == "" {
+		a.err = "No secret"
+		return nil
+	}
+	key, err := DecodeSecret(cfg.Secret)
+	if err != nil {
+		return err
+	}
+	a.gen = generator{key: key, digits: cfg.Digits, period: time.Duration(cfg.Period) * time.Second, hash: hashFor(cfg.Algorithm)}
+	if a.gen.digits == 0 {
+		a.gen.digits = DefaultDigits
+	}
+	if a.gen.period == 0 {
+		a.gen.period = DefaultPeriod
+	}
+	if a.gen.hash == nil {
+		a.gen.hash = hashFor("")
+	}
+	return nil
+}
+
+// Stop clears a code still on the clipboard, then shuts down the module.
+func (m *Module) Stop() error {
+	m.mu.Lock()
+	pending := m.clearTimer != nil && m.clearTimer.Stop()
+	m.mu.Unlock()
+	if pending {
+		m.clearClipboard()
+	}
+	return m.BaseModule.Stop()
+}
+
+// RenderKeys returns each account's current code.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	now := time.Now()
+	keys := make(map[module.KeyID]image.Image)
+	for _, a := range m.accounts {
+		keys[a.key] = m.renderAccountKey(a, now)
+	}
+	return keys
+}
+
+// RenderStrip returns nil; the module doesn't use the strip.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey copies the account's current code to the clipboard.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+	i := slices.IndexFunc(m.accounts, func(a *account) bool { return a.key == id })
+	if i < 0 || m.accounts[i].err != "" {
+		return nil
+	}
+	a := m.accounts[i]
+
+	code := a.gen.code(time.Now())
+	if err := action.SetClipboard(code); err != nil {
+		m.Log().Warn("Failed to copy code", "account", a.label, "err", err)
+		m.Notify(module.Notification{Text: "Copy failed", Color: colorExpiring})
+		return nil
+	}
+
+	m.mu.Lock()
+	if m.clearTimer != nil {
+		m.clearTimer.Stop()
+	}
+	m.copied = code
+	m.clearTimer = time.AfterFunc(clearAfter, m.clearClipboard)
+	m.mu.Unlock()
+
+	m.Log().Info("Copied code", "account", a.label)
+	m.Notify(module.Notification{Text: "Copied " + a.label + " code"})
+	return nil
+}
+
+// clearClipboard empties the clipboard if it still holds the last code
+// copied.
+func (m *Module) clearClipboard() {
+	m.mu.Lock()
+	code := m.copied
+	m.copied = ""
+	m.mu.Unlock()
+
+	if code == "" {
+		return
+	}
+	if current, err := action.Clipboard(); err != nil || current != code {
+		return
+	}
+	if err := action.SetClipboard(""); err != nil {
+		m.Log().Warn("Failed to clear clipboard", "err", err)
+	}
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
@@ -0,0 +1,102 @@
+package totp
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+var (
+	colorArc      = color.RGBA{90, 170, 255, 255}
+	colorExpiring = color.RGBA{230, 70, 60, 255}
+)
+
+// expiringSoon is when a code's countdown turns red.
+const expiringSoon = 5 * time.Second
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Regular, 11); err != nil {
+		return err
+	}
+	if m.codeFace, err = render.NewFace(render.Bold, 16); err != nil {
+		return err
+	}
+	if m.smallCodeFace, err = render.NewFace(render.Bold, 12); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderAccountKey renders an account's label, its current code split in
+// half for reading, and an arc counting down the time the code has left.
+func (m *Module) renderAccountKey(a *account, now time.Time) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	label := render.TruncateText(a.label, m.labelFace, render.KeySize-6)
+	render.DrawTextCentered(img, label, render.KeySize/2, 16, m.labelFace, render.ColorGray)
+
+	if a.err != "" {
+		render.DrawTextCentered(img, a.err, render.KeySize/2, 44, m.labelFace, render.ColorDimGray)
+		return img
+	}
+
+	code := a.gen.code(now)
+	code = code[:len(code)/2] + " " + code[len(code)/2:]
+	face := m.codeFace
+	if font.MeasureString(face, code).Ceil() > render.KeySize-6 {
+		face = m.smallCodeFace
+	}
+	render.DrawTextCentered(img, code, render.KeySize/2, 43, face, render.ColorWhite)
+
+	left := a.gen.remaining(now)
+	arcColor := colorArc
+	if time.Duration(left*float64(a.gen.period)) <= expiringSoon {
+		arcColor = colorExpiring
+	}
+	drawArc(img, render.KeySize/2, 59, 7, 2.5, 1, render.ColorDimGray)
+	drawArc(img, render.KeySize/2, 59, 7, 2.5, left, arcColor)
+	return img
+}
+
+// drawArc draws a ring of radius r and the given width around (cx, cy),
+// clockwise from the top for frac of the way around, with antialiased
+// edges.
+func drawArc(img *image.RGBA, cx, cy int, r, width, frac float64, col color.RGBA) {
+	reach := int(math.Ceil(r + width))
+	for y := cy - reach; y <= cy+reach; y++ {
+		for x := cx - reach; x <= cx+reach; x++ {
+			dx, dy := float64(x-cx)+0.5, float64(y-cy)+0.5
+			coverage := width/2 + 0.5 - math.Abs(math.Hypot(dx, dy)-r)
+			if coverage <= 0 {
+				continue
+			}
+			// Angle clockwise from the top, as a fraction of a turn
+			angle := math.Atan2(dx, -dy) / (2 * math.Pi)
+			if angle < 0 {
+				angle++
+			}
+			if angle > frac {
+				continue
+			}
+			blend(img, x, y, col, min(1, coverage))
+		}
+	}
+}
+
+// blend mixes col into the pixel at (x, y) by alpha.
+func blend(img *image.RGBA, x, y int, col color.RGBA, alpha float64) {
+	if !(image.Point{x, y}.In(img.Rect)) {
+		return
+	}
+	bg := img.RGBAAt(x, y)
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + alpha*(float64(b)-float64(a)))
+	}
+	img.SetRGBA(x, y, color.RGBA{mix(bg.R, col.R), mix(bg.G, col.G), mix(bg.B, col.B), 255})
+}
@@ -0,0 +1,122 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for accounts that don't set their own.
+const (
+	DefaultDigits = 6
+	DefaultPeriod = 30 * time.Second
+)
+
+// generator computes an account's codes (RFC 6238).
+type generator struct {
+	key    []byte
+	digits int
+	period time.Duration
+	hash   func() hash.Hash
+}
+
+// code returns the code for the period containing t.
+func (g generator) code(t time.Time) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(g.period/time.Second)))
+
+	mac := hmac.New(g.hash, g.key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226, section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	mod := uint32(1)
+	for range g.digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", g.digits, value%mod)
+}
+
+// remaining returns how long the code for t has left, as a fraction of the
+// period.
+func (g generator) remaining(t time.Time) float64 {
+	into := time.Duration(t.UnixNano()) % g.period
+	return 1 - float64(into)/float64(g.period)
+}
+
+// hashFor returns the hash for an algorithm name, or nil if unknown.
+func hashFor(algorithm string) func() hash.Hash {
+	switch strings.ToLower(algorithm) {
+	case "", "sha1":
+		return sha1.New
+	case "sha256":
+		return sha256.New
+	case "sha512":
+		return sha512.New
+	}
+	return nil
+}
+
+// DecodeSecret decodes a base32 secret as authenticator apps show them:
+// in any case, with or without spaces and padding.
+func DecodeSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(secret))
+	s = strings.TrimRight(s, "=")
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base32 secret")
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("empty secret")
+	}
+	return key, nil
+}
+
+// URI is what an otpauth:// URI, as encoded in a setup QR code, says about
+// an account. Digits and Period are zero, and Algorithm empty, where the
+// URI leaves them out.
+type URI struct {
+	Secret    string
+	Digits    int
+	Period    int // seconds
+	Algorithm string
+}
+
+// ParseURI parses an otpauth://totp/... URI.
+func ParseURI(s string) (URI, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return URI{}, err
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		return URI{}, fmt.Errorf("not an otpauth://totp URI")
+	}
+
+	q := u.Query()
+	uri := URI{Secret: q.Get("secret"), Algorithm: strings.ToLower(q.Get("algorithm"))}
+	if _, err := DecodeSecret(uri.Secret); err != nil {
+		return URI{}, err
+	}
+	if d := q.Get("digits"); d != "" {
+		if uri.Digits, err = strconv.Atoi(d); err != nil {
+			return URI{}, fmt.Errorf("invalid digits %q", d)
+		}
+	}
+	if p := q.Get("period"); p != "" {
+		if uri.Period, err = strconv.Atoi(p); err != nil {
+			return URI{}, fmt.Errorf("invalid period %q", p)
+		}
+	}
+	return uri, nil
+}
@@ -0,0 +1,137 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/httpx"
+)
+
+// Provider names accepted in tracker.provider.
+const (
+	ProviderJira   = "jira"
+	ProviderLinear = "linear"
+)
+
+// maxIssues caps how many issues are fetched; counts beyond it aren't shown.
+const maxIssues = 100
+
+// Category groups workflow states across trackers.
+type Category int
+
+const (
+	CategoryTodo Category = iota
+	CategoryInProgress
+)
+
+// Issue is one open issue assigned to the user.
+type Issue struct {
+	Key      string // e.g. "ENG-123"
+	Title    string
+	Status   string // workflow state name, e.g. "In Review"
+	Category Category
+	URL      string
+}
+
+// Provider fetches the user's open assigned issues, most recently updated first.
+type Provider interface {
+	Name() string
+	Issues(ctx context.Context) ([]Issue, error)
+}
+
+// newProvider returns the provider selected in cfg.
+func newProvider(cfg config.TrackerConfig) (Provider, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("no tracker token configured (set TRACKER_TOKEN or run 'belowdeck setup')")
+	}
+
+	switch cfg.Provider {
+	case ProviderJira:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("tracker.url is required for Jira")
+		}
+		return newJira(cfg), nil
+	case ProviderLinear:
+		return newLinear(cfg)
+	case "":
+		return nil, fmt.Errorf("tracker.provider is not set (jira or linear)")
+	default:
+		return nil, fmt.Errorf("unknown tracker provider %q (want jira or linear)", cfg.Provider)
+	}
+}
+
+// StatusCount is how many issues are in one workflow state.
+type StatusCount struct {
+	Status   string
+	Category Category
+	Count    int
+}
+
+// countByStatus groups issues by status, in-progress states first and then
+// by count.
+func countByStatus(issues []Issue) []StatusCount {
+	index := make(map[string]int)
+	var counts []StatusCount
+	for _, is := range issues {
+		i, ok := index[is.Status]
+		if !ok {
+			i = len(counts)
+			index[is.Status] = i
+			counts = append(counts, StatusCount{Status: is.Status, Category: is.Category})
+		}
+		counts[i].Count++
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Category != counts[j].Category {
+			return counts[i].Category > counts[j].Category
+		}
+		return counts[i].Count > counts[j].Count
+	})
+	return counts
+}
+
+// httpClient is shared by the providers; callers' contexts bound each request.
+var httpClient = httpx.New("tracker", 15*time.Second)
+
+// doJSON sends body (if non-nil) as JSON and decodes a JSON response into v.
+func doJSON(ctx context.Context, method, url string, headers map[string]string, body, v any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,97 @@
+package tracker
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// jira fetches issues from Jira Cloud or, without an account email, from
+// Jira Server/Data Center using a personal access token.
+type jira struct {
+	base    string
+	jql     string
+	headers map[string]string
+	cloud   bool
+}
+
+func newJira(cfg config.TrackerConfig) *jira {
+	j := &jira{
+		base:    strings.TrimSuffix(cfg.URL, "/"),
+		jql:     jiraJQL(cfg),
+		headers: make(map[string]string),
+		cloud:   cfg.Email != "",
+	}
+	if j.cloud {
+		creds := base64.StdEncoding.EncodeToString([]byte(cfg.Email + ":" + cfg.Token))
+		j.headers["Authorization"] = "Basic " + creds
+	} else {
+		j.headers["Authorization"] = "Bearer " + cfg.Token
+	}
+	return j
+}
+
+// jiraJQL returns the configured filter, or open issues assigned to the
+// user in the configured project.
+func jiraJQL(cfg config.TrackerConfig) string {
+	if cfg.Filter != "" {
+		return cfg.Filter
+	}
+	jql := "assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC"
+	if cfg.Project != "" {
+		jql = fmt.Sprintf("project = %q AND %s", cfg.Project, jql)
+	}
+	return jql
+}
+
+func (j *jira) Name() string { return "Jira" }
+
+func (j *jira) Issues(ctx context.Context) ([]Issue, error) {
+	// Cloud retired /search in favor of /search/jql; Server only has /search.
+	endpoint := "/rest/api/2/search"
+	if j.cloud {
+		endpoint = "/rest/api/3/search/jql"
+	}
+	q := url.Values{}
+	q.Set("jql", j.jql)
+	q.Set("fields", "summary,status")
+	q.Set("maxResults", fmt.Sprint(maxIssues))
+
+	var result struct {
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+				Status  struct {
+					Name           string `json:"name"`
+					StatusCategory struct {
+						Key string `json:"key"` // new, indeterminate, done
+					} `json:"statusCategory"`
+				} `json:"status"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := doJSON(ctx, "GET", j.base+endpoint+"?"+q.Encode(), j.headers, nil, &result); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(result.Issues))
+	for _, is := range result.Issues {
+		category := CategoryTodo
+		if is.Fields.Status.StatusCategory.Key == "indeterminate" {
+			category = CategoryInProgress
+		}
+		issues = append(issues, Issue{
+			Key:      is.Key,
+			Title:    is.Fields.Summary,
+			Status:   is.Fields.Status.Name,
+			Category: category,
+			URL:      j.base + "/browse/" + is.Key,
+		})
+	}
+	return issues, nil
+}
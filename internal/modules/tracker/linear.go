@@ -0,0 +1,108 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+const linearURL = "https://api.linear.app/graphql"
+
+const linearQuery = `query($filter: IssueFilter, $first: Int) {
+	viewer {
+		assignedIssues(filter: $filter, first: $first, orderBy: updatedAt) {
+			nodes { identifier title url state { name type } }
+		}
+	}
+}`
+
+// linear fetches issues assigned to the API key's user from Linear.
+type linear struct {
+	filter  map[string]any
+	headers map[string]string
+}
+
+func newLinear(cfg config.TrackerConfig) (*linear, error) {
+	filter, err := linearFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &linear{
+		filter: filter,
+		// Personal API keys are sent bare, without "Bearer"
+		headers: map[string]string{"Authorization": cfg.Token},
+	}, nil
+}
+
+// linearFilter returns the configured filter, or issues not yet completed or
+// canceled in the configured team.
+func linearFilter(cfg config.TrackerConfig) (map[string]any, error) {
+	if cfg.Filter != "" {
+		var filter map[string]any
+		if err := json.Unmarshal([]byte(cfg.Filter), &filter); err != nil {
+			return nil, fmt.Errorf("tracker.filter is not a JSON IssueFilter: %w", err)
+		}
+		return filter, nil
+	}
+	filter := map[string]any{
+		"state": map[string]any{"type": map[string]any{"nin": []string{"completed", "canceled"}}},
+	}
+	if cfg.Project != "" {
+		filter["team"] = map[string]any{"key": map[string]any{"eq": cfg.Project}}
+	}
+	return filter, nil
+}
+
+func (l *linear) Name() string { return "Linear" }
+
+func (l *linear) Issues(ctx context.Context) ([]Issue, error) {
+	body := map[string]any{
+		"query":     linearQuery,
+		"variables": map[string]any{"filter": l.filter, "first": maxIssues},
+	}
+	var result struct {
+		Data struct {
+			Viewer struct {
+				AssignedIssues struct {
+					Nodes []struct {
+						Identifier string `json:"identifier"`
+						Title      string `json:"title"`
+						URL        string `json:"url"`
+						State      struct {
+							Name string `json:"name"`
+							Type string `json:"type"` // triage, backlog, unstarted, started, ...
+						} `json:"state"`
+					} `json:"nodes"`
+				} `json:"assignedIssues"`
+			} `json:"viewer"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := doJSON(ctx, "POST", linearURL, l.headers, body, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("linear: %s", result.Errors[0].Message)
+	}
+
+	nodes := result.Data.Viewer.AssignedIssues.Nodes
+	issues := make([]Issue, 0, len(nodes))
+	for _, n := range nodes {
+		category := CategoryTodo
+		if n.State.Type == "started" {
+			category = CategoryInProgress
+		}
+		issues = append(issues, Issue{
+			Key:      n.Identifier,
+			Title:    n.Title,
+			Status:   n.State.Name,
+			Category: category,
+			URL:      n.URL,
+		})
+	}
+	return issues, nil
+}
@@ -0,0 +1,299 @@
+// Package tracker provides a Stream Deck module for issues assigned to you
+// in Jira or Linear.
+package tracker
+
+import (
+	"context"
+	"image"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/metrics"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	// pollInterval is how often issues are refetched unless configured.
+	pollInterval = 2 * time.Minute
+
+	// overlayTimeout is how long the overlay stays up without input.
+	overlayTimeout = 5 * time.Second
+
+	// itemsPerPage is how many issues the overlay shows at once, one per key.
+	itemsPerPage = 8
+)
+
+// Module implements the issue tracker module.
+type Module struct {
+	module.BaseModule
+	module.Freshness
+
+	appCfg   *config.Config
+	interval time.Duration // between fetches
+	provider Provider
+
+	mu     sync.RWMutex
+	issues []Issue
+	loaded bool // at least one fetch succeeded
+
+	// Overlay state
+	overlayOpen bool
+	currentPage int // Current page in pagination (0-indexed)
+
+	// Fonts
+	labelFace      font.Face
+	numberFace     font.Face
+	titleFace      font.Face
+	stripTitleFace font.Face
+	stripLabelFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new tracker module.
+func New(appCfg *config.Config) *Module {
+	interval := appCfg.PollInterval("tracker", pollInterval)
+	return &Module{
+		BaseModule: module.NewBaseModule("tracker"),
+		Freshness:  module.Freshness{StaleAfter: 3 * interval},
+		appCfg:     appCfg,
+		interval:   interval,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "tracker"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	var cfg config.TrackerConfig
+	if m.appCfg != nil {
+		cfg = m.appCfg.Tracker
+	}
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return err
+	}
+	m.provider = provider
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+
+	go m.pollIssues(ctx)
+
+	m.Log().Info("Module initialized", "provider", provider.Name())
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// pollIssues fetches issues now and every interval.
+func (m *Module) pollIssues(ctx context.Context) {
+	m.fetchIssues(ctx)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.fetchIssues(ctx)
+		}
+	}
+}
+
+// fetchIssues refreshes the issue list, keeping the previous one on failure.
+func (m *Module) fetchIssues(ctx context.Context) {
+	start := time.Now()
+	issues, err := m.provider.Issues(ctx)
+	metrics.ObserveFetch(m.ID(), start, err)
+	m.Fetched(err)
+	if err != nil {
+		m.Log().Warn("Failed to fetch issues", "err", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.issues = issues
+	m.loaded = true
+	m.mu.Unlock()
+}
+
+// getIssues returns the current issue list and whether it has been fetched.
+func (m *Module) getIssues() ([]Issue, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.issues, m.loaded
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if len(m.resources.Keys) == 0 {
+		return nil
+	}
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderCountsButton(),
+	}
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey opens the issue overlay on press.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	m.mu.Lock()
+	m.overlayOpen = true
+	m.currentPage = 0
+	m.mu.Unlock()
+
+	return nil
+}
+
+// HandleDial processes dial events.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	return nil
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
+
+// IsOverlayActive returns true if the issue overlay is visible.
+func (m *Module) IsOverlayActive() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.overlayOpen
+}
+
+// OverlayTimeout returns how long the overlay stays up without input.
+func (m *Module) OverlayTimeout() time.Duration {
+	return overlayTimeout
+}
+
+// CloseOverlay dismisses the issue overlay.
+func (m *Module) CloseOverlay() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.overlayOpen = false
+}
+
+// HandleOverlayDial processes dial events when the overlay is active.
+// Dial4 (right knob) controls pagination: rotate to change page, click to dismiss overlay.
+func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
+	if id != module.Dial4 {
+		return nil
+	}
+
+	issues, _ := m.getIssues()
+	totalPages := pageCount(len(issues))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch event.Type {
+	case module.DialRotate:
+		if event.Delta > 0 && m.currentPage < totalPages-1 {
+			m.currentPage++
+		} else if event.Delta < 0 && m.currentPage > 0 {
+			m.currentPage--
+		}
+
+	case module.DialRelease:
+		m.overlayOpen = false
+	}
+
+	return nil
+}
+
+// HandleOverlayKey opens the issue on the pressed key in the browser.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	issues, _ := m.getIssues()
+	m.mu.RLock()
+	currentPage := m.currentPage
+	m.mu.RUnlock()
+
+	// Key1-Key8 map to issues on the current page
+	index := currentPage*itemsPerPage + int(id) - 1
+	if index >= 0 && index < len(issues) && issues[index].URL != "" {
+		m.openURL(issues[index].URL)
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch processes touch strip events when the overlay is active.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
+
+// RenderOverlayKeys returns images for all 8 keys showing the current page of issues.
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	issues, _ := m.getIssues()
+	m.mu.RLock()
+	start := m.currentPage * itemsPerPage
+	m.mu.RUnlock()
+
+	keys := make(map[module.KeyID]image.Image)
+	for i := range itemsPerPage {
+		keyID := module.Key1 + module.KeyID(i)
+		if start+i < len(issues) {
+			keys[keyID] = m.renderIssueKey(issues[start+i])
+		} else {
+			keys[keyID] = m.renderEmptyKey()
+		}
+	}
+	return keys
+}
+
+// RenderOverlayStrip returns the touch strip image for the overlay.
+func (m *Module) RenderOverlayStrip() image.Image {
+	issues, _ := m.getIssues()
+	m.mu.RLock()
+	currentPage := m.currentPage
+	m.mu.RUnlock()
+
+	return m.renderOverlayStrip(issues, currentPage)
+}
+
+// openURL opens a URL in the default browser.
+func (m *Module) openURL(url string) {
+	if err := exec.Command("open", url).Start(); err != nil {
+		m.Log().Warn("Failed to open URL", "url", url, "err", err)
+	}
+}
+
+// pageCount returns how many overlay pages n issues fill, at least one.
+func pageCount(n int) int {
+	if n == 0 {
+		return 1
+	}
+	return (n + itemsPerPage - 1) / itemsPerPage
+}
@@ -0,0 +1,176 @@
+package tracker
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// Colors
+var (
+	colorStripBg    = color.RGBA{30, 30, 30, 255}
+	colorDimGray    = color.RGBA{110, 110, 110, 255}
+	colorTodo       = color.RGBA{140, 150, 170, 255}
+	colorInProgress = color.RGBA{80, 150, 240, 255}
+)
+
+// maxStatusRows is how many statuses fit on the counts key.
+const maxStatusRows = 4
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() error {
+	var err error
+	if m.labelFace, err = render.NewFace(render.Bold, 11); err != nil {
+		return err
+	}
+	if m.numberFace, err = render.NewFace(render.Bold, 13); err != nil {
+		return err
+	}
+	if m.titleFace, err = render.NewFace(render.Regular, 10); err != nil {
+		return err
+	}
+	if m.stripTitleFace, err = render.NewFace(render.Bold, 18); err != nil {
+		return err
+	}
+	if m.stripLabelFace, err = render.NewFace(render.Bold, 14); err != nil {
+		return err
+	}
+	return nil
+}
+
+// categoryColor returns the accent color for a status category.
+func categoryColor(c Category) color.Color {
+	if c == CategoryInProgress {
+		return colorInProgress
+	}
+	return colorTodo
+}
+
+// renderCountsButton renders the key showing assigned issue counts by status.
+func (m *Module) renderCountsButton() image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+
+	issues, loaded := m.getIssues()
+	if len(issues) == 0 {
+		render.DrawTextCentered(img, "Issues", render.KeySize/2, 30, m.labelFace, colorDimGray)
+		count := "-"
+		if loaded {
+			count = "0"
+		}
+		render.DrawTextCentered(img, count, render.KeySize/2, 52, m.stripTitleFace, render.ColorWhite)
+		return img
+	}
+
+	counts := countByStatus(issues)
+	if len(counts) > maxStatusRows {
+		// Fold the rest into a last "Other" row
+		other := StatusCount{Status: "Other", Category: CategoryTodo}
+		for _, c := range counts[maxStatusRows-1:] {
+			other.Count += c.Count
+		}
+		counts = append(counts[:maxStatusRows-1:maxStatusRows-1], other)
+	}
+
+	y := 10 + (maxStatusRows-len(counts))*7
+	for _, c := range counts {
+		m.drawStatRow(img, y, c.Status, c.Count, categoryColor(c.Category))
+		y += 14
+	}
+	return img
+}
+
+// drawStatRow draws a row with a colored marker, status label, and count.
+func (m *Module) drawStatRow(img *image.RGBA, y int, label string, count int, col color.Color) {
+	draw.Draw(img, image.Rect(6, y+2, 12, y+8), &image.Uniform{col}, image.Point{}, draw.Src)
+
+	countStr := fmt.Sprintf("%d", count)
+	countWidth := font.MeasureString(m.numberFace, countStr).Ceil()
+	render.DrawText(img, countStr, render.KeySize-6-countWidth, y+9, m.numberFace, render.ColorWhite)
+
+	label = render.TruncateText(label, m.labelFace, render.KeySize-6-countWidth-4-16)
+	render.DrawText(img, label, 16, y+9, m.labelFace, colorDimGray)
+}
+
+// renderIssueKey renders a single issue on a key.
+func (m *Module) renderIssueKey(is Issue) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+	accent := categoryColor(is.Category)
+
+	// Status bar at top
+	draw.Draw(img, image.Rect(0, 0, render.KeySize, 4), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	const maxWidth = render.KeySize - 8
+	render.DrawText(img, render.TruncateText(is.Key, m.labelFace, maxWidth), 4, 17, m.labelFace, accent)
+	render.DrawText(img, render.TruncateText(is.Status, m.titleFace, maxWidth), 4, 29, m.titleFace, colorDimGray)
+
+	title := is.Title
+	redact := m.Redaction()
+	if redact == module.RedactGeneric {
+		title = "Issue"
+	}
+	y := 42
+	for _, line := range render.WrapText(title, m.titleFace, maxWidth, 3) {
+		render.DrawText(img, line, 4, y, m.titleFace, render.ColorWhite)
+		y += 11
+	}
+	if redact == module.RedactBlur {
+		render.Blur(img, image.Rect(0, 33, render.KeySize, render.KeySize))
+	}
+	return img
+}
+
+// renderEmptyKey renders an empty key for the overlay.
+func (m *Module) renderEmptyKey() image.Image {
+	return render.NewKey(render.ColorKeyBg)
+}
+
+// renderOverlayStrip renders the touch strip for the issue overlay: counts by
+// status on the left and pagination on the right, above Dial4.
+func (m *Module) renderOverlayStrip(issues []Issue, currentPage int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 100))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorStripBg}, image.Point{}, draw.Src)
+
+	if len(issues) == 0 {
+		render.DrawTextCentered(img, "No issues", 300, 55, m.stripTitleFace, colorDimGray)
+	} else {
+		m.drawStatusSummary(img, countByStatus(issues))
+	}
+
+	centerX := 700
+	pageStr := fmt.Sprintf("%d/%d", currentPage+1, pageCount(len(issues)))
+	render.DrawTextCentered(img, pageStr, centerX, 40, m.stripTitleFace, render.ColorWhite)
+	render.DrawTextCentered(img, "<< turn >>", centerX, 65, m.stripLabelFace, colorDimGray)
+	render.DrawTextCentered(img, "click=back", centerX, 88, m.stripLabelFace, colorDimGray)
+
+	return img
+}
+
+// drawStatusSummary draws status counts in two rows of three.
+func (m *Module) drawStatusSummary(img *image.RGBA, counts []StatusCount) {
+	const (
+		perRow    = 3
+		colWidth  = 195
+		rowHeight = 40
+	)
+	for i, c := range counts {
+		if i >= 2*perRow {
+			break
+		}
+		x := 15 + (i%perRow)*colWidth
+		y := 35 + (i/perRow)*rowHeight
+
+		draw.Draw(img, image.Rect(x, y-11, x+8, y-3), &image.Uniform{categoryColor(c.Category)}, image.Point{}, draw.Src)
+
+		countStr := fmt.Sprintf("%d", c.Count)
+		countWidth := font.MeasureString(m.stripTitleFace, countStr).Ceil()
+		label := render.TruncateText(c.Status, m.stripLabelFace, colWidth-30-countWidth)
+		render.DrawText(img, label, x+14, y, m.stripLabelFace, colorDimGray)
+		labelWidth := font.MeasureString(m.stripLabelFace, label).Ceil()
+		render.DrawText(img, countStr, x+14+labelWidth+8, y+1, m.stripTitleFace, render.ColorWhite)
+	}
+}
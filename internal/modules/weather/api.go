@@ -5,40 +5,50 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
 	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/httpx"
+)
+
+// Provider names accepted in weather.provider.
+const (
+	ProviderOpenWeatherMap = "openweathermap"
+	ProviderOpenMeteo      = "open-meteo"
+	ProviderNWS            = "nws"
 )
 
-// OneCallResponse represents the OpenWeatherMap One Call 3.0 API response.
-type OneCallResponse struct {
-	Current struct {
-		Temp      float64 `json:"temp"`
-		FeelsLike float64 `json:"feels_like"`
-		Humidity  int     `json:"humidity"`
-		WindSpeed float64 `json:"wind_speed"`
-		Weather   []struct {
-			ID          int    `json:"id"`
-			Main        string `json:"main"`
-			Description string `json:"description"`
-			Icon        string `json:"icon"`
-		} `json:"weather"`
-	} `json:"current"`
-	Minutely []struct {
-		Dt            int64   `json:"dt"`            // Unix timestamp
-		Precipitation float64 `json:"precipitation"` // mm/h
-	} `json:"minutely"`
-	Daily []struct {
-		Temp struct {
-			Min float64 `json:"min"`
-			Max float64 `json:"max"`
-		} `json:"temp"`
-		Weather []struct {
-			ID          int    `json:"id"`
-			Main        string `json:"main"`
-			Description string `json:"description"`
-			Icon        string `json:"icon"`
-		} `json:"weather"`
-	} `json:"daily"`
+// Provider fetches conditions and forecast for a location from one weather
+// service. Temperatures are in °F and wind speeds in mph.
+type Provider interface {
+	// Name returns the provider's display name.
+	Name() string
+
+	// Fetch returns the current report for lat, lon.
+	Fetch(ctx context.Context, lat, lon float64) (Report, error)
+}
+
+// Report is everything one fetch returns.
+type Report struct {
+	Current CurrentWeather
+	Daily   DailyForecast
+	Precip  PrecipForecast
+	Outlook Outlook
+}
+
+// newProvider returns the provider selected in cfg.
+func newProvider(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case ProviderOpenWeatherMap:
+		return &openWeatherMap{apiKey: cfg.APIKey}, nil
+	case ProviderOpenMeteo:
+		return &openMeteo{}, nil
+	case ProviderNWS:
+		return &nws{}, nil
+	default:
+		return nil, fmt.Errorf("unknown weather provider %q (want %s, %s, or %s)",
+			cfg.Provider, ProviderOpenWeatherMap, ProviderOpenMeteo, ProviderNWS)
+	}
 }
 
 // CurrentWeather holds current weather conditions.
@@ -60,6 +70,60 @@ type DailyForecast struct {
 	Icon      string
 }
 
+// Outlook holds the forecast shown in the forecast overlay and any severe
+// weather alerts.
+type Outlook struct {
+	Hourly []HourForecast // next 12 hours
+	Days   []DayForecast  // today and the following days, up to 8
+	Alerts []Alert
+}
+
+// Alert is a severe weather alert from a national warning system.
+type Alert struct {
+	Event  string // headline, e.g. "Winter Storm Warning"
+	Sender string
+	Start  time.Time
+	End    time.Time
+}
+
+// Active reports whether the alert is in effect at t.
+func (a Alert) Active(t time.Time) bool {
+	return !t.Before(a.Start) && t.Before(a.End)
+}
+
+// ActiveAlerts returns the alerts in effect at t.
+func (o Outlook) ActiveAlerts(t time.Time) []Alert {
+	var active []Alert
+	for _, a := range o.Alerts {
+		if a.Active(t) {
+			active = append(active, a)
+		}
+	}
+	return active
+}
+
+// HourForecast holds one hour of the hourly forecast.
+type HourForecast struct {
+	Time time.Time
+	Temp float64
+	Pop  float64 // probability of precipitation, 0-1
+}
+
+// DayForecast holds one day of the daily forecast.
+type DayForecast struct {
+	Time    time.Time
+	TempMin float64
+	TempMax float64
+	Pop     float64
+	Icon    string
+}
+
+// Forecast lengths shown in the overlay.
+const (
+	outlookHours = 12
+	outlookDays  = 8
+)
+
 // PrecipForecast holds precipitation forecast info.
 type PrecipForecast struct {
 	Active      bool   // Currently precipitating
@@ -69,74 +133,66 @@ type PrecipForecast struct {
 	Description string // Human-readable description
 }
 
-// fetchOneCall fetches weather data from the One Call 3.0 API.
-func fetchOneCall(ctx context.Context, apiKey string, lat, lon float64) (CurrentWeather, DailyForecast, PrecipForecast, error) {
-	baseURL := "https://api.openweathermap.org/data/3.0/onecall"
-
-	params := url.Values{}
-	params.Set("lat", fmt.Sprintf("%.6f", lat))
-	params.Set("lon", fmt.Sprintf("%.6f", lon))
-	params.Set("appid", apiKey)
-	params.Set("units", "imperial")
-	params.Set("exclude", "hourly,alerts")
-
-	reqURL := baseURL + "?" + params.Encode()
-
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+// Probe validates the weather config and makes one real API call, so
+// credential and subscription problems surface before the daemon runs.
+func Probe(ctx context.Context, appCfg *config.Config) error {
+	cfg, err := loadConfig(appCfg)
 	if err != nil {
-		return CurrentWeather{}, DailyForecast{}, PrecipForecast{}, fmt.Errorf("create request: %w", err)
+		return err
 	}
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	provider, err := newProvider(cfg)
 	if err != nil {
-		return CurrentWeather{}, DailyForecast{}, PrecipForecast{}, fmt.Errorf("fetch weather: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
+	_, err = provider.Fetch(ctx, cfg.Lat, cfg.Lon)
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return CurrentWeather{}, DailyForecast{}, PrecipForecast{}, fmt.Errorf("API error: %s", resp.Status)
+// ProviderName returns the display name of the configured provider, for
+// diagnostics.
+func ProviderName(appCfg *config.Config) string {
+	if cfg, err := loadConfig(appCfg); err == nil {
+		if provider, err := newProvider(cfg); err == nil {
+			return provider.Name()
+		}
 	}
+	return "Weather"
+}
 
-	var data OneCallResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return CurrentWeather{}, DailyForecast{}, PrecipForecast{}, fmt.Errorf("decode response: %w", err)
-	}
+// httpClient is shared by all providers.
+var httpClient = httpx.New("weather", 10*time.Second)
 
-	current := CurrentWeather{
-		Temp:      data.Current.Temp,
-		FeelsLike: data.Current.FeelsLike,
-		Humidity:  data.Current.Humidity,
-		WindSpeed: data.Current.WindSpeed,
+// getJSON fetches url and decodes its JSON body into v. Headers are added
+// to the request as given.
+func getJSON(ctx context.Context, url string, v any, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
 	}
-
-	if len(data.Current.Weather) > 0 {
-		current.Condition = data.Current.Weather[0].Main
-		current.Description = data.Current.Weather[0].Description
-		current.Icon = data.Current.Weather[0].Icon
+	for k, val := range headers {
+		req.Header.Set(k, val)
 	}
 
-	var daily DailyForecast
-	if len(data.Daily) > 0 {
-		daily.TempMin = data.Daily[0].Temp.Min
-		daily.TempMax = data.Daily[0].Temp.Max
-		if len(data.Daily[0].Weather) > 0 {
-			daily.Condition = data.Daily[0].Weather[0].Main
-			daily.Icon = data.Daily[0].Weather[0].Icon
-		}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch weather: %w", err)
 	}
+	defer resp.Body.Close()
 
-	precip := analyzePrecipitation(data.Minutely, current.Condition)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
 
-	return current, daily, precip, nil
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
 }
 
-// analyzePrecipitation analyzes minutely data to determine precipitation status.
-func analyzePrecipitation(minutely []struct {
-	Dt            int64   `json:"dt"`
-	Precipitation float64 `json:"precipitation"`
-}, condition string) PrecipForecast {
-	if len(minutely) == 0 {
+// analyzePrecipitation determines precipitation status from a near-term
+// series of precipitation rates in mm/h, one every step starting now.
+func analyzePrecipitation(rates []float64, step time.Duration, condition string) PrecipForecast {
+	if len(rates) == 0 {
 		return PrecipForecast{}
 	}
 
@@ -145,7 +201,8 @@ func analyzePrecipitation(minutely []struct {
 
 	const threshold = 0.1 // mm/h threshold to consider it precipitating
 
-	isActive := minutely[0].Precipitation >= threshold
+	isActive := rates[0] >= threshold
+	minutes := func(i int) int { return int(time.Duration(i) * step / time.Minute) }
 
 	var forecast PrecipForecast
 	forecast.Active = isActive
@@ -153,22 +210,22 @@ func analyzePrecipitation(minutely []struct {
 
 	if isActive {
 		// Find when precip ends
-		for i, m := range minutely {
-			if m.Precipitation < threshold {
-				forecast.EndsIn = i
-				forecast.Description = fmt.Sprintf("%s ending in %d min", precipType, i)
+		for i, rate := range rates {
+			if rate < threshold {
+				forecast.EndsIn = minutes(i)
+				forecast.Description = fmt.Sprintf("%s ending in %d min", precipType, forecast.EndsIn)
 				break
 			}
 		}
 		if forecast.EndsIn == 0 {
-			forecast.Description = fmt.Sprintf("%s for 60+ min", precipType)
+			forecast.Description = fmt.Sprintf("%s for %d+ min", precipType, minutes(len(rates)-1))
 		}
 	} else {
 		// Find when precip starts
-		for i, m := range minutely {
-			if m.Precipitation >= threshold {
-				forecast.StartsIn = i
-				forecast.Description = fmt.Sprintf("%s in %d min", precipType, i)
+		for i, rate := range rates {
+			if rate >= threshold {
+				forecast.StartsIn = minutes(i)
+				forecast.Description = fmt.Sprintf("%s in %d min", precipType, forecast.StartsIn)
 				break
 			}
 		}
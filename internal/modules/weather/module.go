@@ -5,40 +5,62 @@ import (
 	"context"
 	"fmt"
 	"image"
-	"log"
 	"os/exec"
-	"strconv"
 	"sync"
 	"time"
 
 	"github.com/phinze/belowdeck/internal/config"
-	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/metrics"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/reachability"
+	"github.com/phinze/belowdeck/internal/units"
 	"golang.org/x/image/font"
 )
 
+// forecastTimeout is how long the forecast overlay stays open.
+const forecastTimeout = 15 * time.Second
+
+// alertNotifyDuration is how long a new alert's headline is flashed.
+const alertNotifyDuration = 10 * time.Second
+
 // Config holds the weather module configuration.
 type Config struct {
-	APIKey string
-	Lat    float64
-	Lon    float64
+	Provider string
+	APIKey   string // OpenWeatherMap only
+	Lat      float64
+	Lon      float64
 }
 
+// pollInterval is how often weather is fetched unless configured.
+const pollInterval = 10 * time.Minute
+
 // Module implements the weather display module.
 type Module struct {
 	module.BaseModule
+	module.Freshness
+
+	appCfg   *config.Config
+	config   Config
+	interval time.Duration // between fetches
 
-	device  device.Device
-	appCfg  *config.Config
-	config  Config
+	provider Provider
+	units    units.System
 
 	// State
 	state *weatherState
 	mu    sync.RWMutex
 
+	// Alerts already announced, by alertKey, so each is flashed only once
+	seenAlerts map[string]bool
+
+	// Overlay state
+	forecastOpen bool
+
 	// Fonts
 	tempSmallFace font.Face
 	conditionFace font.Face
+	smallFace     font.Face
+	dayFace       font.Face
 
 	// Cancel function for polling
 	pollCancel context.CancelFunc
@@ -50,6 +72,7 @@ type weatherState struct {
 	Current   CurrentWeather
 	Daily     DailyForecast
 	Precip    PrecipForecast
+	Outlook   Outlook
 	LastFetch time.Time
 }
 
@@ -63,22 +86,32 @@ func (s *weatherState) get() (CurrentWeather, DailyForecast, PrecipForecast) {
 	return s.Current, s.Daily, s.Precip
 }
 
-func (s *weatherState) update(current CurrentWeather, daily DailyForecast, precip PrecipForecast) {
+func (s *weatherState) getOutlook() Outlook {
+	s.RLock()
+	defer s.RUnlock()
+	return s.Outlook
+}
+
+func (s *weatherState) update(current CurrentWeather, daily DailyForecast, precip PrecipForecast, outlook Outlook) {
 	s.Lock()
 	defer s.Unlock()
 	s.Current = current
 	s.Daily = daily
 	s.Precip = precip
+	s.Outlook = outlook
 	s.LastFetch = time.Now()
 }
 
 // New creates a new Weather module.
-func New(dev device.Device, appCfg *config.Config) *Module {
+func New(appCfg *config.Config) *Module {
+	interval := appCfg.PollInterval("weather", pollInterval)
 	return &Module{
 		BaseModule: module.NewBaseModule("weather"),
-		device:     dev,
+		Freshness:  module.Freshness{StaleAfter: 3 * interval},
 		appCfg:     appCfg,
+		interval:   interval,
 		state:      newWeatherState(),
+		seenAlerts: make(map[string]bool),
 	}
 }
 
@@ -101,6 +134,11 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	}
 	m.config = config
 
+	if m.provider, err = newProvider(config); err != nil {
+		return err
+	}
+	m.units = m.appCfg.UnitSystem()
+
 	// Initialize fonts
 	if err := m.initFonts(); err != nil {
 		return err
@@ -111,7 +149,7 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	m.pollCancel = cancel
 	go m.pollWeather(pollCtx)
 
-	log.Printf("Weather module initialized (lat=%.4f, lon=%.4f)", m.config.Lat, m.config.Lon)
+	m.Log().Info("Module initialized", "provider", m.provider.Name(), "lat", m.config.Lat, "lon", m.config.Lon)
 	return nil
 }
 
@@ -129,8 +167,13 @@ func loadConfig(appCfg *config.Config) (Config, error) {
 		return Config{}, fmt.Errorf("no configuration provided")
 	}
 
+	provider := appCfg.Weather.Provider
+	if provider == "" {
+		provider = ProviderOpenWeatherMap
+	}
+
 	apiKey := appCfg.Weather.APIKey
-	if apiKey == "" {
+	if provider == ProviderOpenWeatherMap && apiKey == "" {
 		return Config{}, fmt.Errorf("OpenWeatherMap API key not configured")
 	}
 
@@ -138,56 +181,65 @@ func loadConfig(appCfg *config.Config) (Config, error) {
 		return Config{}, fmt.Errorf("weather lat/lon not configured")
 	}
 
-	lat, err := strconv.ParseFloat(appCfg.Weather.Lat, 64)
+	lat, lon, err := appCfg.Weather.Coordinates()
 	if err != nil {
-		return Config{}, fmt.Errorf("invalid weather lat: %w", err)
-	}
-
-	lon, err := strconv.ParseFloat(appCfg.Weather.Lon, 64)
-	if err != nil {
-		return Config{}, fmt.Errorf("invalid weather lon: %w", err)
+		return Config{}, fmt.Errorf("weather: %w", err)
 	}
 
 	return Config{
-		APIKey: apiKey,
-		Lat:    lat,
-		Lon:    lon,
+		Provider: provider,
+		APIKey:   apiKey,
+		Lat:      lat,
+		Lon:      lon,
 	}, nil
 }
 
-// pollWeather fetches weather data periodically.
+// pollWeather fetches weather data now and every interval while the
+// network is reachable.
 func (m *Module) pollWeather(ctx context.Context) {
-	// Fetch immediately on start
-	m.fetchWeather(ctx)
-
-	ticker := time.NewTicker(10 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			m.fetchWeather(ctx)
-		}
-	}
+	reachability.Poll(ctx, m.interval, m.fetchWeather)
 }
 
-// fetchWeather fetches current weather from the API.
+// fetchWeather fetches current weather from the provider.
 func (m *Module) fetchWeather(ctx context.Context) {
-	current, daily, precip, err := fetchOneCall(ctx, m.config.APIKey, m.config.Lat, m.config.Lon)
+	start := time.Now()
+	report, err := m.provider.Fetch(ctx, m.config.Lat, m.config.Lon)
+	metrics.ObserveFetch(m.ID(), start, err)
+	m.Fetched(err)
 	if err != nil {
-		log.Printf("Weather fetch error: %v", err)
+		m.Log().Warn("Fetch error", "provider", m.provider.Name(), "err", err)
 		return
 	}
+	current, daily, precip, outlook := report.Current, report.Daily, report.Precip, report.Outlook
+
+	m.state.update(current, daily, precip, outlook)
+	m.Log().Info("Weather updated",
+		"temp", current.Temp, "feels_like", current.FeelsLike, "conditions", current.Description,
+		"high", daily.TempMax, "low", daily.TempMin, "precip", precip.Description)
+
+	m.announceAlerts(outlook.ActiveAlerts(time.Now()))
+}
 
-	m.state.update(current, daily, precip)
-	precipInfo := ""
-	if precip.Description != "" {
-		precipInfo = " | " + precip.Description
+// announceAlerts flashes the headline of each alert not seen before and
+// forgets alerts that have ended. Only fetchWeather calls it, so seenAlerts
+// needs no lock.
+func (m *Module) announceAlerts(alerts []Alert) {
+	seen := make(map[string]bool, len(alerts))
+	for _, a := range alerts {
+		key := alertKey(a)
+		seen[key] = true
+		if m.seenAlerts[key] {
+			continue
+		}
+		m.Log().Warn("Weather alert", "event", a.Event, "sender", a.Sender, "until", a.End)
+		m.Notify(module.Notification{Text: a.Event, Color: colorAlert, Duration: alertNotifyDuration})
 	}
-	log.Printf("Weather updated: %.0f°F (feels %.0f°F) %s (H:%.0f° L:%.0f°)%s",
-		current.Temp, current.FeelsLike, current.Description, daily.TempMax, daily.TempMin, precipInfo)
+	m.seenAlerts = seen
+}
+
+// alertKey identifies an alert across fetches.
+func alertKey(a Alert) string {
+	return fmt.Sprintf("%s|%s|%d", a.Sender, a.Event, a.Start.Unix())
 }
 
 // RenderKeys returns images for the module's keys.
@@ -198,17 +250,15 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 
 // RenderStrip returns the touch strip image.
 func (m *Module) RenderStrip() image.Image {
-	if !m.device.GetTouchStripSupported() {
+	if !m.Resources().Device.HasStrip() {
 		return nil
 	}
 
-	rect, err := m.device.GetTouchStripImageRectangle()
-	if err != nil {
-		return nil
-	}
+	rect := m.Resources().Device.StripRect
 
 	current, daily, precip := m.state.get()
-	return m.renderStrip(rect, current, daily, precip)
+	alert := len(m.state.getOutlook().ActiveAlerts(time.Now())) > 0
+	return m.renderStrip(rect, current, daily, precip, alert)
 }
 
 // HandleKey processes key events.
@@ -223,13 +273,92 @@ func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
 	return nil
 }
 
-// HandleStripTouch processes touch strip events.
+// HandleStripTouch opens the forecast overlay on tap and the Weather app on
+// long tap.
 func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
-	if event.Type != module.TouchTap {
-		return nil
+	switch event.Type {
+	case module.TouchTap:
+		if len(m.state.getOutlook().Hourly) == 0 {
+			return nil
+		}
+		m.Log().Debug("Strip tap: opening forecast")
+		m.openForecast()
+	case module.TouchLongTap:
+		m.Log().Debug("Strip long tap: opening Weather")
+		go exec.Command("open", "-a", "Weather").Run()
 	}
+	return nil
+}
+
+// openForecast shows the forecast overlay.
+func (m *Module) openForecast() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forecastOpen = true
+}
+
+// closeForecast dismisses the forecast overlay.
+func (m *Module) closeForecast() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forecastOpen = false
+}
+
+// IsOverlayActive returns true if the forecast overlay is visible.
+func (m *Module) IsOverlayActive() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.forecastOpen
+}
+
+// OverlayTimeout returns how long the forecast stays up without input.
+func (m *Module) OverlayTimeout() time.Duration {
+	return forecastTimeout
+}
+
+// CloseOverlay dismisses the forecast.
+func (m *Module) CloseOverlay() {
+	m.closeForecast()
+}
+
+// RenderOverlayKeys returns a daily forecast tile for each of the 8 keys.
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	days := m.state.getOutlook().Days
 
-	log.Println("Strip tap: opening Weather")
-	go exec.Command("open", "-a", "Weather").Run()
+	keys := make(map[module.KeyID]image.Image)
+	for i := 0; i < 8; i++ {
+		if i < len(days) {
+			keys[module.KeyID(i+1)] = m.renderDayKey(days[i], i == 0)
+		} else {
+			keys[module.KeyID(i+1)] = m.renderEmptyKey()
+		}
+	}
+	return keys
+}
+
+// RenderOverlayStrip returns the hourly forecast graph across the full strip.
+func (m *Module) RenderOverlayStrip() image.Image {
+	rect := m.Resources().Device.StripRect
+	return m.renderHourlyStrip(rect, m.state.getOutlook().Hourly)
+}
+
+// HandleOverlayKey ignores key presses; the forecast is read-only.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	return nil
+}
+
+// HandleOverlayDial dismisses the forecast on dial press.
+func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
+	if event.Type == module.DialRelease {
+		m.closeForecast()
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch dismisses the forecast on tap.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	if event.Type == module.TouchTap {
+		m.closeForecast()
+	}
 	return nil
 }
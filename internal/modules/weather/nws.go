@@ -0,0 +1,255 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nwsHeaders are sent with every NWS request; the API rejects requests
+// without a User-Agent.
+var nwsHeaders = map[string]string{
+	"User-Agent": "belowdeck (https://github.com/phinze/belowdeck)",
+	"Accept":     "application/geo+json",
+}
+
+// nws fetches from the US National Weather Service API, which is free,
+// needs no API key, and only covers the United States. It has no
+// minute-by-minute precipitation, so the strip's precipitation summary
+// stays empty; current conditions come from the hourly forecast's current
+// hour rather than a station observation.
+type nws struct {
+	// Forecast URLs for the location, looked up once from /points
+	point             string
+	forecastURL       string
+	forecastHourlyURL string
+}
+
+// Name returns the provider's display name.
+func (p *nws) Name() string {
+	return "National Weather Service"
+}
+
+// nwsPointResponse represents the /points response.
+type nwsPointResponse struct {
+	Properties struct {
+		Forecast       string `json:"forecast"`
+		ForecastHourly string `json:"forecastHourly"`
+	} `json:"properties"`
+}
+
+// nwsForecastResponse represents a /forecast or /forecast/hourly response.
+type nwsForecastResponse struct {
+	Properties struct {
+		Periods []nwsPeriod `json:"periods"`
+	} `json:"properties"`
+}
+
+type nwsPeriod struct {
+	StartTime                  time.Time `json:"startTime"`
+	EndTime                    time.Time `json:"endTime"`
+	IsDaytime                  bool      `json:"isDaytime"`
+	Temperature                float64   `json:"temperature"`
+	WindSpeed                  string    `json:"windSpeed"` // e.g. "10 mph" or "5 to 10 mph"
+	ShortForecast              string    `json:"shortForecast"`
+	Icon                       string    `json:"icon"`
+	ProbabilityOfPrecipitation nwsValue  `json:"probabilityOfPrecipitation"`
+	RelativeHumidity           nwsValue  `json:"relativeHumidity"`
+}
+
+type nwsValue struct {
+	Value float64 `json:"value"`
+}
+
+// nwsAlertsResponse represents the /alerts/active response.
+type nwsAlertsResponse struct {
+	Features []struct {
+		Properties struct {
+			Event      string    `json:"event"`
+			SenderName string    `json:"senderName"`
+			Effective  time.Time `json:"effective"`
+			Onset      time.Time `json:"onset"`
+			Expires    time.Time `json:"expires"`
+			Ends       time.Time `json:"ends"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// Fetch fetches the hourly and daily forecasts and active alerts for the location.
+func (p *nws) Fetch(ctx context.Context, lat, lon float64) (Report, error) {
+	point := fmt.Sprintf("%.4f,%.4f", lat, lon)
+	if p.point != point {
+		var pt nwsPointResponse
+		if err := getJSON(ctx, "https://api.weather.gov/points/"+point, &pt, nwsHeaders); err != nil {
+			return Report{}, fmt.Errorf("look up forecast office: %w", err)
+		}
+		p.point = point
+		p.forecastURL = pt.Properties.Forecast
+		p.forecastHourlyURL = pt.Properties.ForecastHourly
+	}
+
+	var hourly, daily nwsForecastResponse
+	if err := getJSON(ctx, p.forecastHourlyURL, &hourly, nwsHeaders); err != nil {
+		return Report{}, fmt.Errorf("hourly forecast: %w", err)
+	}
+	if err := getJSON(ctx, p.forecastURL, &daily, nwsHeaders); err != nil {
+		return Report{}, fmt.Errorf("forecast: %w", err)
+	}
+	var alerts nwsAlertsResponse
+	if err := getJSON(ctx, "https://api.weather.gov/alerts/active?point="+url.QueryEscape(point), &alerts, nwsHeaders); err != nil {
+		return Report{}, fmt.Errorf("alerts: %w", err)
+	}
+
+	now := time.Now()
+	var r Report
+
+	// Hours from the current one onward; the first stands in for current conditions
+	var hours []nwsPeriod
+	for _, h := range hourly.Properties.Periods {
+		if h.EndTime.After(now) {
+			hours = append(hours, h)
+		}
+	}
+	if len(hours) == 0 {
+		return Report{}, fmt.Errorf("hourly forecast has no current period")
+	}
+
+	cur := hours[0]
+	main, icon := nwsCondition(cur.Icon)
+	r.Current = CurrentWeather{
+		Temp:        cur.Temperature,
+		FeelsLike:   cur.Temperature,
+		Humidity:    int(cur.RelativeHumidity.Value),
+		WindSpeed:   parseWindSpeed(cur.WindSpeed),
+		Condition:   main,
+		Description: strings.ToLower(cur.ShortForecast),
+		Icon:        icon + dayNightSuffix(cur.IsDaytime),
+	}
+
+	for _, h := range hours {
+		if len(r.Outlook.Hourly) >= outlookHours {
+			break
+		}
+		r.Outlook.Hourly = append(r.Outlook.Hourly, HourForecast{
+			Time: h.StartTime,
+			Temp: h.Temperature,
+			Pop:  h.ProbabilityOfPrecipitation.Value / 100,
+		})
+	}
+
+	r.Outlook.Days = nwsDays(hours, daily.Properties.Periods)
+	if len(r.Outlook.Days) > 0 {
+		today := r.Outlook.Days[0]
+		r.Daily = DailyForecast{TempMin: today.TempMin, TempMax: today.TempMax, Icon: today.Icon}
+	}
+
+	for _, f := range alerts.Features {
+		a := f.Properties
+		start, end := a.Onset, a.Ends
+		if start.IsZero() {
+			start = a.Effective
+		}
+		if end.IsZero() {
+			end = a.Expires
+		}
+		r.Outlook.Alerts = append(r.Outlook.Alerts, Alert{Event: a.Event, Sender: a.SenderName, Start: start, End: end})
+	}
+
+	return r, nil
+}
+
+// nwsDays builds daily forecasts: the high and low from the hourly
+// forecast for each date, and the icon and precipitation chance from the
+// 12-hour forecast, preferring the daytime period.
+func nwsDays(hours, periods []nwsPeriod) []DayForecast {
+	var days []DayForecast
+	index := make(map[string]int)
+	for _, h := range hours {
+		date := h.StartTime.Format(time.DateOnly)
+		i, ok := index[date]
+		if !ok {
+			if len(days) >= outlookDays {
+				break
+			}
+			y, m, d := h.StartTime.Date()
+			i = len(days)
+			index[date] = i
+			days = append(days, DayForecast{
+				Time:    time.Date(y, m, d, 0, 0, 0, 0, h.StartTime.Location()),
+				TempMin: h.Temperature,
+				TempMax: h.Temperature,
+			})
+		}
+		days[i].TempMin = min(days[i].TempMin, h.Temperature)
+		days[i].TempMax = max(days[i].TempMax, h.Temperature)
+	}
+
+	for _, p := range periods {
+		i, ok := index[p.StartTime.Format(time.DateOnly)]
+		if !ok || (days[i].Icon != "" && !p.IsDaytime) {
+			continue
+		}
+		_, icon := nwsCondition(p.Icon)
+		days[i].Icon = icon + dayNightSuffix(p.IsDaytime)
+		days[i].Pop = p.ProbabilityOfPrecipitation.Value / 100
+	}
+	return days
+}
+
+// nwsCondition maps an NWS icon URL, e.g.
+// https://api.weather.gov/icons/land/day/rain,40/tsra,60?size=medium, to a
+// main condition and an OpenWeatherMap icon code without its day/night
+// suffix. The first condition in the URL wins.
+func nwsCondition(iconURL string) (main, icon string) {
+	u, err := url.Parse(iconURL)
+	if err != nil {
+		return "Clouds", "03"
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	code := path.Base(u.Path)
+	for i, part := range parts {
+		if (part == "day" || part == "night") && i+1 < len(parts) {
+			code = parts[i+1]
+			break
+		}
+	}
+	code, _, _ = strings.Cut(code, ",")
+
+	switch code {
+	case "skc", "few", "hot", "cold", "wind_skc", "wind_few":
+		return "Clear", "01"
+	case "sct", "wind_sct":
+		return "Clouds", "02"
+	case "bkn", "wind_bkn":
+		return "Clouds", "03"
+	case "ovc", "wind_ovc":
+		return "Clouds", "04"
+	case "rain", "rain_showers", "rain_showers_hi":
+		return "Rain", "10"
+	case "snow", "blizzard":
+		return "Snow", "13"
+	case "rain_snow", "rain_sleet", "snow_sleet", "sleet", "fzra", "rain_fzra", "snow_fzra":
+		return "Sleet", "13"
+	case "tsra", "tsra_sct", "tsra_hi", "tornado", "hurricane", "tropical_storm":
+		return "Thunderstorm", "11"
+	case "fog", "haze", "smoke", "dust":
+		return "Fog", "50"
+	default:
+		return "Clouds", "03"
+	}
+}
+
+// parseWindSpeed returns the first number in an NWS wind speed such as
+// "5 to 10 mph".
+func parseWindSpeed(s string) float64 {
+	for _, field := range strings.Fields(s) {
+		if v, err := strconv.ParseFloat(field, 64); err == nil {
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,187 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// openMeteo fetches from Open-Meteo, which is free and needs no API key.
+// It has no severe weather alerts.
+type openMeteo struct{}
+
+// Name returns the provider's display name.
+func (p *openMeteo) Name() string {
+	return "Open-Meteo"
+}
+
+// openMeteoResponse represents the Open-Meteo forecast API response, with
+// times as Unix timestamps.
+type openMeteoResponse struct {
+	Current struct {
+		Temp        float64 `json:"temperature_2m"`
+		FeelsLike   float64 `json:"apparent_temperature"`
+		Humidity    int     `json:"relative_humidity_2m"`
+		WindSpeed   float64 `json:"wind_speed_10m"`
+		WeatherCode int     `json:"weather_code"`
+		IsDay       int     `json:"is_day"`
+	} `json:"current"`
+	Minutely15 struct {
+		Time          []int64   `json:"time"`
+		Precipitation []float64 `json:"precipitation"` // mm over the preceding 15 minutes
+	} `json:"minutely_15"`
+	Hourly struct {
+		Time []int64   `json:"time"`
+		Temp []float64 `json:"temperature_2m"`
+		Pop  []float64 `json:"precipitation_probability"` // percent
+	} `json:"hourly"`
+	Daily struct {
+		Time        []int64   `json:"time"`
+		WeatherCode []int     `json:"weather_code"`
+		TempMax     []float64 `json:"temperature_2m_max"`
+		TempMin     []float64 `json:"temperature_2m_min"`
+		Pop         []float64 `json:"precipitation_probability_max"` // percent
+	} `json:"daily"`
+}
+
+// precipHorizon is how far ahead Open-Meteo's 15-minute precipitation is
+// scanned for the strip's precipitation summary.
+const precipHorizon = 2 * time.Hour
+
+// Fetch fetches weather data from the Open-Meteo forecast API.
+func (p *openMeteo) Fetch(ctx context.Context, lat, lon float64) (Report, error) {
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%.6f", lat))
+	params.Set("longitude", fmt.Sprintf("%.6f", lon))
+	params.Set("current", "temperature_2m,apparent_temperature,relative_humidity_2m,wind_speed_10m,weather_code,is_day")
+	params.Set("minutely_15", "precipitation")
+	params.Set("hourly", "temperature_2m,precipitation_probability")
+	params.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max")
+	params.Set("temperature_unit", "fahrenheit")
+	params.Set("wind_speed_unit", "mph")
+	params.Set("timeformat", "unixtime")
+	params.Set("timezone", "auto")
+	params.Set("forecast_days", fmt.Sprint(outlookDays))
+
+	var data openMeteoResponse
+	if err := getJSON(ctx, "https://api.open-meteo.com/v1/forecast?"+params.Encode(), &data, nil); err != nil {
+		return Report{}, err
+	}
+
+	now := time.Now()
+	var r Report
+
+	main, desc, icon := wmoCondition(data.Current.WeatherCode)
+	r.Current = CurrentWeather{
+		Temp:        data.Current.Temp,
+		FeelsLike:   data.Current.FeelsLike,
+		Humidity:    data.Current.Humidity,
+		WindSpeed:   data.Current.WindSpeed,
+		Condition:   main,
+		Description: desc,
+		Icon:        icon + dayNightSuffix(data.Current.IsDay == 1),
+	}
+
+	// Rates for the current 15-minute slot onward
+	var rates []float64
+	for i, t := range data.Minutely15.Time {
+		slotEnd := time.Unix(t, 0)
+		if slotEnd.Before(now) || i >= len(data.Minutely15.Precipitation) {
+			continue
+		}
+		if slotEnd.After(now.Add(precipHorizon)) {
+			break
+		}
+		rates = append(rates, data.Minutely15.Precipitation[i]*4)
+	}
+	r.Precip = analyzePrecipitation(rates, 15*time.Minute, main)
+
+	// Hours from the current one onward
+	for i, t := range data.Hourly.Time {
+		hour := time.Unix(t, 0)
+		if hour.Add(time.Hour).Before(now) || i >= len(data.Hourly.Temp) {
+			continue
+		}
+		if len(r.Outlook.Hourly) >= outlookHours {
+			break
+		}
+		r.Outlook.Hourly = append(r.Outlook.Hourly, HourForecast{
+			Time: hour,
+			Temp: data.Hourly.Temp[i],
+			Pop:  percentAt(data.Hourly.Pop, i),
+		})
+	}
+
+	for i, t := range data.Daily.Time {
+		if i >= outlookDays || i >= len(data.Daily.WeatherCode) || i >= len(data.Daily.TempMax) || i >= len(data.Daily.TempMin) {
+			break
+		}
+		dayMain, _, dayIcon := wmoCondition(data.Daily.WeatherCode[i])
+		day := DayForecast{
+			Time:    time.Unix(t, 0),
+			TempMin: data.Daily.TempMin[i],
+			TempMax: data.Daily.TempMax[i],
+			Pop:     percentAt(data.Daily.Pop, i),
+			Icon:    dayIcon + "d",
+		}
+		r.Outlook.Days = append(r.Outlook.Days, day)
+		if i == 0 {
+			r.Daily = DailyForecast{TempMin: day.TempMin, TempMax: day.TempMax, Condition: dayMain, Icon: day.Icon}
+		}
+	}
+
+	return r, nil
+}
+
+// percentAt returns values[i] as a fraction, or 0 if it's missing.
+func percentAt(values []float64, i int) float64 {
+	if i >= len(values) {
+		return 0
+	}
+	return values[i] / 100
+}
+
+// dayNightSuffix returns the OpenWeatherMap icon suffix for day or night.
+func dayNightSuffix(day bool) string {
+	if day {
+		return "d"
+	}
+	return "n"
+}
+
+// wmoCondition maps a WMO weather interpretation code to a main condition,
+// a description, and an OpenWeatherMap icon code without its day/night
+// suffix, which is what rendering expects.
+func wmoCondition(code int) (main, description, icon string) {
+	switch code {
+	case 0:
+		return "Clear", "clear sky", "01"
+	case 1:
+		return "Clouds", "mainly clear", "02"
+	case 2:
+		return "Clouds", "partly cloudy", "03"
+	case 3:
+		return "Clouds", "overcast", "04"
+	case 45, 48:
+		return "Fog", "fog", "50"
+	case 51, 53, 55:
+		return "Drizzle", "drizzle", "09"
+	case 56, 57:
+		return "Sleet", "freezing drizzle", "09"
+	case 61, 63, 65:
+		return "Rain", "rain", "10"
+	case 66, 67:
+		return "Sleet", "freezing rain", "10"
+	case 71, 73, 75, 77:
+		return "Snow", "snow", "13"
+	case 80, 81, 82:
+		return "Rain", "rain showers", "09"
+	case 85, 86:
+		return "Snow", "snow showers", "13"
+	case 95, 96, 99:
+		return "Thunderstorm", "thunderstorm", "11"
+	default:
+		return "Clouds", "cloudy", "03"
+	}
+}
@@ -0,0 +1,147 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// openWeatherMap fetches from the OpenWeatherMap One Call 3.0 API, which
+// needs an API key with the "One Call by Call" subscription.
+type openWeatherMap struct {
+	apiKey string
+}
+
+// Name returns the provider's display name.
+func (p *openWeatherMap) Name() string {
+	return "OpenWeatherMap"
+}
+
+// OneCallResponse represents the OpenWeatherMap One Call 3.0 API response.
+type OneCallResponse struct {
+	Current struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Humidity  int     `json:"humidity"`
+		WindSpeed float64 `json:"wind_speed"`
+		Weather   []struct {
+			ID          int    `json:"id"`
+			Main        string `json:"main"`
+			Description string `json:"description"`
+			Icon        string `json:"icon"`
+		} `json:"weather"`
+	} `json:"current"`
+	Minutely []struct {
+		Dt            int64   `json:"dt"`            // Unix timestamp
+		Precipitation float64 `json:"precipitation"` // mm/h
+	} `json:"minutely"`
+	Hourly []struct {
+		Dt   int64   `json:"dt"`
+		Temp float64 `json:"temp"`
+		Pop  float64 `json:"pop"` // probability of precipitation, 0-1
+	} `json:"hourly"`
+	Daily []struct {
+		Dt   int64   `json:"dt"`
+		Pop  float64 `json:"pop"`
+		Temp struct {
+			Min float64 `json:"min"`
+			Max float64 `json:"max"`
+		} `json:"temp"`
+		Weather []struct {
+			ID          int    `json:"id"`
+			Main        string `json:"main"`
+			Description string `json:"description"`
+			Icon        string `json:"icon"`
+		} `json:"weather"`
+	} `json:"daily"`
+	Alerts []struct {
+		SenderName string `json:"sender_name"`
+		Event      string `json:"event"`
+		Start      int64  `json:"start"`
+		End        int64  `json:"end"`
+	} `json:"alerts"`
+}
+
+// Fetch fetches weather data from the One Call 3.0 API.
+func (p *openWeatherMap) Fetch(ctx context.Context, lat, lon float64) (Report, error) {
+	baseURL := "https://api.openweathermap.org/data/3.0/onecall"
+
+	params := url.Values{}
+	params.Set("lat", fmt.Sprintf("%.6f", lat))
+	params.Set("lon", fmt.Sprintf("%.6f", lon))
+	params.Set("appid", p.apiKey)
+	params.Set("units", "imperial")
+
+	var data OneCallResponse
+	if err := getJSON(ctx, baseURL+"?"+params.Encode(), &data, nil); err != nil {
+		return Report{}, err
+	}
+
+	current := CurrentWeather{
+		Temp:      data.Current.Temp,
+		FeelsLike: data.Current.FeelsLike,
+		Humidity:  data.Current.Humidity,
+		WindSpeed: data.Current.WindSpeed,
+	}
+
+	if len(data.Current.Weather) > 0 {
+		current.Condition = data.Current.Weather[0].Main
+		current.Description = data.Current.Weather[0].Description
+		current.Icon = data.Current.Weather[0].Icon
+	}
+
+	var daily DailyForecast
+	if len(data.Daily) > 0 {
+		daily.TempMin = data.Daily[0].Temp.Min
+		daily.TempMax = data.Daily[0].Temp.Max
+		if len(data.Daily[0].Weather) > 0 {
+			daily.Condition = data.Daily[0].Weather[0].Main
+			daily.Icon = data.Daily[0].Weather[0].Icon
+		}
+	}
+
+	rates := make([]float64, len(data.Minutely))
+	for i, m := range data.Minutely {
+		rates[i] = m.Precipitation
+	}
+	precip := analyzePrecipitation(rates, time.Minute, current.Condition)
+
+	var outlook Outlook
+	for i, h := range data.Hourly {
+		if i >= outlookHours {
+			break
+		}
+		outlook.Hourly = append(outlook.Hourly, HourForecast{
+			Time: time.Unix(h.Dt, 0),
+			Temp: h.Temp,
+			Pop:  h.Pop,
+		})
+	}
+	for i, d := range data.Daily {
+		if i >= outlookDays {
+			break
+		}
+		day := DayForecast{
+			Time:    time.Unix(d.Dt, 0),
+			TempMin: d.Temp.Min,
+			TempMax: d.Temp.Max,
+			Pop:     d.Pop,
+		}
+		if len(d.Weather) > 0 {
+			day.Icon = d.Weather[0].Icon
+		}
+		outlook.Days = append(outlook.Days, day)
+	}
+
+	for _, a := range data.Alerts {
+		outlook.Alerts = append(outlook.Alerts, Alert{
+			Event:  a.Event,
+			Sender: a.SenderName,
+			Start:  time.Unix(a.Start, 0),
+			End:    time.Unix(a.End, 0),
+		})
+	}
+
+	return Report{Current: current, Daily: daily, Precip: precip, Outlook: outlook}, nil
+}
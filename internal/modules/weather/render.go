@@ -5,11 +5,9 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"log"
 	"strings"
 
-	"github.com/srwiley/oksvg"
-	"github.com/srwiley/rasterx"
+	"github.com/phinze/belowdeck/internal/render"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
@@ -22,35 +20,6 @@ var fontBold []byte
 //go:embed fonts/PublicSans-Regular.ttf
 var fontRegular []byte
 
-// Weather icons
-//
-//go:embed icons/sun.svg
-var iconSunSVG string
-
-//go:embed icons/moon.svg
-var iconMoonSVG string
-
-//go:embed icons/cloud.svg
-var iconCloudSVG string
-
-//go:embed icons/cloud-sun.svg
-var iconCloudSunSVG string
-
-//go:embed icons/cloud-moon.svg
-var iconCloudMoonSVG string
-
-//go:embed icons/cloud-rain.svg
-var iconCloudRainSVG string
-
-//go:embed icons/cloud-snow.svg
-var iconCloudSnowSVG string
-
-//go:embed icons/cloud-lightning.svg
-var iconCloudLightningSVG string
-
-//go:embed icons/cloud-fog.svg
-var iconCloudFogSVG string
-
 // Colors
 var (
 	colorSunny      = color.RGBA{255, 200, 50, 255}  // Yellow/gold for sunny
@@ -63,6 +32,9 @@ var (
 	colorKeyBg      = color.RGBA{40, 40, 40, 255}
 	colorWhite      = color.RGBA{255, 255, 255, 255}
 	colorGray       = color.RGBA{160, 160, 160, 255}
+	colorPrecipBar  = color.RGBA{100, 149, 237, 110} // Translucent blue for precip chance
+	colorTempLine   = color.RGBA{255, 200, 50, 255}
+	colorAlert      = color.RGBA{220, 40, 40, 255} // Red for severe weather alerts
 )
 
 // initFonts initializes the font faces for rendering.
@@ -96,11 +68,31 @@ func (m *Module) initFonts() error {
 		return fmt.Errorf("create condition face: %w", err)
 	}
 
+	// Forecast overlay labels
+	m.smallFace, err = opentype.NewFace(ttRegular, &opentype.FaceOptions{
+		Size:    12,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return fmt.Errorf("create small face: %w", err)
+	}
+
+	m.dayFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
+		Size:    14,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return fmt.Errorf("create day face: %w", err)
+	}
+
 	return nil
 }
 
-// renderStrip renders the weather strip segment.
-func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily DailyForecast, precip PrecipForecast) image.Image {
+// renderStrip renders the weather strip segment, with a red badge in the
+// corner while a severe weather alert is active.
+func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily DailyForecast, precip PrecipForecast, alert bool) image.Image {
 	// Create full-size image but only fill our region (400-800)
 	img := image.NewRGBA(rect)
 	h := rect.Dy()
@@ -121,9 +113,9 @@ func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily
 	// Right text: 620-790 (high/low, precip)
 
 	// ICON (left side)
-	iconSVG, iconColor := getWeatherIcon(current.Icon)
+	iconName, iconColor := getWeatherIcon(current.Icon)
 	iconSize := 70
-	iconImg := renderSVGIcon(iconSVG, iconSize, iconColor)
+	iconImg := render.Icon(iconName, iconSize, iconColor)
 	iconX := 405
 	iconY := (h - iconSize) / 2
 	iconRect := image.Rect(iconX, iconY, iconX+iconSize, iconY+iconSize)
@@ -133,11 +125,11 @@ func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily
 	leftX := 490
 
 	// Current temperature (large)
-	tempStr := fmt.Sprintf("%.0f°", current.Temp)
+	tempStr := m.units.Temp(current.Temp)
 	m.drawText(img, tempStr, leftX, 38, m.tempSmallFace, colorWhite)
 
 	// Feels like
-	feelsStr := fmt.Sprintf("Feels %.0f°", current.FeelsLike)
+	feelsStr := "Feels " + m.units.Temp(current.FeelsLike)
 	m.drawText(img, feelsStr, leftX, 60, m.conditionFace, colorGray)
 
 	// Condition text
@@ -155,7 +147,7 @@ func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily
 
 	// High/Low
 	if daily.TempMax != 0 || daily.TempMin != 0 {
-		hiLoStr := fmt.Sprintf("H:%.0f° L:%.0f°", daily.TempMax, daily.TempMin)
+		hiLoStr := fmt.Sprintf("H:%s L:%s", m.units.Temp(daily.TempMax), m.units.Temp(daily.TempMin))
 		m.drawText(img, hiLoStr, rightX, 38, m.conditionFace, colorWhite)
 	}
 
@@ -168,10 +160,137 @@ func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily
 		m.drawText(img, precip.Description, rightX, 60, m.conditionFace, precipColor)
 	}
 
+	// Wind
+	m.drawText(img, "Wind "+m.units.Speed(current.WindSpeed), rightX, 82, m.conditionFace, colorGray)
+
+	if alert {
+		drawAlertBadge(img, 782, 18)
+	}
+
+	return img
+}
+
+// drawAlertBadge draws a red circle with an exclamation mark centered at (cx, cy).
+func drawAlertBadge(img *image.RGBA, cx, cy int) {
+	const r = 10
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				img.Set(cx+x, cy+y, colorAlert)
+			}
+		}
+	}
+	draw.Draw(img, image.Rect(cx-1, cy-6, cx+2, cy+2), &image.Uniform{colorWhite}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(cx-1, cy+4, cx+2, cy+7), &image.Uniform{colorWhite}, image.Point{}, draw.Src)
+}
+
+// renderHourlyStrip renders the forecast overlay strip: a temperature curve
+// over the next hours with precipitation chance as bars behind it.
+func (m *Module) renderHourlyStrip(rect image.Rectangle, hours []HourForecast) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	if len(hours) == 0 {
+		return img
+	}
+
+	w, h := rect.Dx(), rect.Dy()
+	colW := w / len(hours)
+	centerX := func(i int) int { return i*colW + colW/2 }
+
+	// Layout (top to bottom): temp labels, curve (28-58), hour labels at the
+	// bottom; precip bars rise from just above the hour labels
+	const curveTop, curveBottom = 28, 58
+	barBottom := h - 18
+
+	minTemp, maxTemp := hours[0].Temp, hours[0].Temp
+	for _, hr := range hours {
+		minTemp = min(minTemp, hr.Temp)
+		maxTemp = max(maxTemp, hr.Temp)
+	}
+	tempY := func(t float64) int {
+		if maxTemp == minTemp {
+			return (curveTop + curveBottom) / 2
+		}
+		return curveBottom - int((t-minTemp)/(maxTemp-minTemp)*(curveBottom-curveTop))
+	}
+
+	// Precipitation chance bars
+	for i, hr := range hours {
+		if hr.Pop <= 0 {
+			continue
+		}
+		barH := int(hr.Pop * float64(barBottom-curveTop))
+		bar := image.Rect(centerX(i)-colW/2+6, barBottom-barH, centerX(i)+colW/2-6, barBottom)
+		draw.Draw(img, bar, &image.Uniform{colorPrecipBar}, image.Point{}, draw.Over)
+	}
+
+	// Temperature curve
+	for i := 1; i < len(hours); i++ {
+		drawLine(img, centerX(i-1), tempY(hours[i-1].Temp), centerX(i), tempY(hours[i].Temp), colorTempLine)
+	}
+
+	for i, hr := range hours {
+		x, y := centerX(i), tempY(hr.Temp)
+		draw.Draw(img, image.Rect(x-2, y-2, x+3, y+3), &image.Uniform{colorTempLine}, image.Point{}, draw.Src)
+		m.drawTextCentered(img, m.units.Temp(hr.Temp), x, y-7, m.smallFace, colorWhite)
+		m.drawTextCentered(img, hr.Time.Format("3pm"), x, h-4, m.smallFace, colorGray)
+	}
+
+	return img
+}
+
+// renderDayKey renders one day of the daily forecast: day name, condition
+// icon, and high/low.
+func (m *Module) renderDayKey(day DayForecast, today bool) image.Image {
+	const size = 72
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	name := day.Time.Format("Mon")
+	if today {
+		name = "Today"
+	}
+	m.drawTextCentered(img, name, size/2, 15, m.dayFace, colorWhite)
+
+	iconName, iconColor := getWeatherIcon(day.Icon)
+	iconSize := 30
+	icon := render.Icon(iconName, iconSize, iconColor)
+	iconX := (size - iconSize) / 2
+	draw.Draw(img, image.Rect(iconX, 19, iconX+iconSize, 19+iconSize), icon, image.Point{}, draw.Over)
+
+	m.drawTextCentered(img, m.units.Temp(day.TempMax)+" / "+m.units.Temp(day.TempMin), size/2, 64, m.smallFace, colorGray)
+
+	return img
+}
+
+// renderEmptyKey renders a blank key for overlay slots without a day.
+func (m *Module) renderEmptyKey() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 72, 72))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
 	return img
 }
 
-// getWeatherIcon returns the appropriate SVG and color for an OpenWeatherMap icon code.
+// drawLine draws a 2px line from (x0, y0) to (x1, y1).
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, col color.Color) {
+	steps := max(abs(x1-x0), abs(y1-y0))
+	for s := 0; s <= steps; s++ {
+		x, y := x0, y0
+		if steps > 0 {
+			x = x0 + (x1-x0)*s/steps
+			y = y0 + (y1-y0)*s/steps
+		}
+		draw.Draw(img, image.Rect(x, y, x+2, y+2), &image.Uniform{col}, image.Point{}, draw.Src)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// getWeatherIcon returns the icon name and color for an OpenWeatherMap icon code.
 func getWeatherIcon(iconCode string) (string, color.Color) {
 	// OpenWeatherMap icon codes:
 	// 01d/01n - clear sky
@@ -189,54 +308,28 @@ func getWeatherIcon(iconCode string) (string, color.Color) {
 	switch {
 	case strings.HasPrefix(iconCode, "01"):
 		if isNight {
-			return iconMoonSVG, colorNight
+			return "moon", colorNight
 		}
-		return iconSunSVG, colorSunny
+		return "sun", colorSunny
 	case strings.HasPrefix(iconCode, "02"):
 		if isNight {
-			return iconCloudMoonSVG, colorNight
+			return "cloud-moon", colorNight
 		}
-		return iconCloudSunSVG, colorSunny
+		return "cloud-sun", colorSunny
 	case strings.HasPrefix(iconCode, "03"), strings.HasPrefix(iconCode, "04"):
-		return iconCloudSVG, colorCloudy
+		return "cloud", colorCloudy
 	case strings.HasPrefix(iconCode, "09"), strings.HasPrefix(iconCode, "10"):
-		return iconCloudRainSVG, colorRain
+		return "cloud-rain", colorRain
 	case strings.HasPrefix(iconCode, "11"):
-		return iconCloudLightningSVG, colorStorm
+		return "cloud-lightning", colorStorm
 	case strings.HasPrefix(iconCode, "13"):
-		return iconCloudSnowSVG, colorSnow
+		return "cloud-snow", colorSnow
 	case strings.HasPrefix(iconCode, "50"):
-		return iconCloudFogSVG, colorCloudy
+		return "cloud-fog", colorCloudy
 	default:
 		// Default to cloud
-		return iconCloudSVG, colorCloudy
-	}
-}
-
-// renderSVGIcon renders an SVG string to an image with the given size and color.
-func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
-	// Replace currentColor with the actual color
-	r, g, b, _ := iconColor.RGBA()
-	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
-	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
-
-	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
-	if err != nil {
-		log.Printf("Failed to parse SVG: %v", err)
-		return image.NewRGBA(image.Rect(0, 0, size, size))
+		return "cloud", colorCloudy
 	}
-
-	img := image.NewRGBA(image.Rect(0, 0, size, size))
-	// Transparent background for icon
-	draw.Draw(img, img.Bounds(), &image.Uniform{color.Transparent}, image.Point{}, draw.Src)
-
-	icon.SetTarget(0, 0, float64(size), float64(size))
-
-	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
-	raster := rasterx.NewDasher(size, size, scanner)
-	icon.Draw(raster, 1.0)
-
-	return img
 }
 
 // drawText draws text at the given position.
@@ -250,3 +343,9 @@ func (m *Module) drawText(img *image.RGBA, text string, x, y int, face font.Face
 	d.DrawString(text)
 }
 
+// drawTextCentered draws text horizontally centered at the given position.
+func (m *Module) drawTextCentered(img *image.RGBA, text string, centerX, y int, face font.Face, col color.Color) {
+	width := font.MeasureString(face, text).Ceil()
+	m.drawText(img, text, centerX-width/2, y, face, col)
+}
+
@@ -0,0 +1,60 @@
+package weather
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render/rendertest"
+	"github.com/phinze/belowdeck/internal/units"
+)
+
+var stripRect = image.Rect(0, 0, 800, 100)
+
+// Fixture weather, in the providers' Fahrenheit and mph.
+var (
+	fixtureCurrent = CurrentWeather{Temp: 68, FeelsLike: 65, WindSpeed: 12, Condition: "Clouds", Description: "broken clouds", Icon: "04d"}
+	fixtureDaily   = DailyForecast{TempMin: 54, TempMax: 72, Condition: "Rain", Icon: "10d"}
+	fixturePrecip  = PrecipForecast{StartsIn: 40, Type: "Rain", Description: "Rain in 40 min"}
+)
+
+// fixtureHours starts at 9am on a fixed day, warming into the afternoon
+// with rain likely late.
+func fixtureHours() []HourForecast {
+	start := time.Date(2025, time.June, 2, 9, 0, 0, 0, time.UTC)
+	temps := []float64{61, 63, 66, 68, 70, 72, 71, 69, 66, 63, 60, 58}
+	pops := []float64{0, 0, 0, 0.1, 0.2, 0.4, 0.7, 0.8, 0.6, 0.3, 0, 0}
+	hours := make([]HourForecast, len(temps))
+	for i := range hours {
+		hours[i] = HourForecast{Time: start.Add(time.Duration(i) * time.Hour), Temp: temps[i], Pop: pops[i]}
+	}
+	return hours
+}
+
+func newTestModule(t *testing.T, system units.System) *Module {
+	t.Helper()
+	m := &Module{units: system}
+	if err := m.initFonts(); err != nil {
+		t.Fatalf("initFonts: %v", err)
+	}
+	return m
+}
+
+func TestRenderStripGolden(t *testing.T) {
+	m := newTestModule(t, units.Imperial)
+	rendertest.Golden(t, "strip", m.renderStrip(stripRect, fixtureCurrent, fixtureDaily, fixturePrecip, false))
+	rendertest.Golden(t, "strip_alert", m.renderStrip(stripRect, fixtureCurrent, fixtureDaily, PrecipForecast{}, true))
+	rendertest.Golden(t, "strip_loading", m.renderStrip(stripRect, CurrentWeather{}, DailyForecast{}, PrecipForecast{}, false))
+
+	metric := newTestModule(t, units.Metric)
+	rendertest.Golden(t, "strip_metric", metric.renderStrip(stripRect, fixtureCurrent, fixtureDaily, fixturePrecip, false))
+}
+
+func TestRenderForecastGolden(t *testing.T) {
+	m := newTestModule(t, units.Imperial)
+	rendertest.Golden(t, "hourly_strip", m.renderHourlyStrip(stripRect, fixtureHours()))
+
+	day := DayForecast{Time: time.Date(2025, time.June, 3, 12, 0, 0, 0, time.UTC), TempMin: 52, TempMax: 70, Pop: 0.6, Icon: "11d"}
+	rendertest.Golden(t, "day_key", m.renderDayKey(day, false))
+	rendertest.Golden(t, "day_key_today", m.renderDayKey(day, true))
+}
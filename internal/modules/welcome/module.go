@@ -0,0 +1,183 @@
+// Package welcome shows a first-run guide on the deck when there's no
+// config file: how to run belowdeck setup, a QR code linking the setup
+// docs, and which of the layout's modules still need settings. It takes
+// over the deck as an overlay until a key is pressed.
+package welcome
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// DocsURL is the setup documentation the QR code links to.
+const DocsURL = "https://github.com/phinze/belowdeck#setup"
+
+// configPollInterval is how often the module checks whether setup has
+// written the config file.
+const configPollInterval = 2 * time.Second
+
+// Module implements the first-run guide.
+type Module struct {
+	module.BaseModule
+
+	appCfg  *config.Config
+	modules []string // layout module IDs, in order
+
+	code *image.RGBA // QR code for DocsURL, sized for the strip or a key
+
+	titleFace font.Face
+	textFace  font.Face
+	smallFace font.Face
+
+	mu        sync.RWMutex
+	dismissed bool
+	saved     bool // setup has written the config file since startup
+}
+
+// New creates the guide for the modules in the layout, by ID.
+func New(appCfg *config.Config, modules []string) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("welcome"),
+		appCfg:     appCfg,
+		modules:    modules,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "welcome"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	if err := m.initFonts(); err != nil {
+		return err
+	}
+	side := render.KeySize
+	if res.Device.HasStrip() {
+		side = res.Device.StripRect.Dy()
+	}
+	code, err := render.QR(DocsURL, side)
+	if err != nil {
+		return err
+	}
+	m.code = code
+
+	go m.watchConfig(ctx)
+
+	m.Log().Info("No config file, showing setup guide", "path", config.DefaultConfigPath())
+	return nil
+}
+
+// watchConfig notices when belowdeck setup writes the config file, so the
+// guide can say to restart.
+func (m *Module) watchConfig(ctx context.Context) {
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if config.Exists() {
+				m.mu.Lock()
+				m.saved = true
+				m.mu.Unlock()
+				return
+			}
+		}
+	}
+}
+
+func (m *Module) getState() (dismissed, saved bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dismissed, m.saved
+}
+
+// dismiss hides the guide, showing the deck as laid out.
+func (m *Module) dismiss() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.dismissed {
+		m.Log().Info("Setup guide dismissed")
+	}
+	m.dismissed = true
+}
+
+// IsOverlayActive reports whether the guide is showing.
+func (m *Module) IsOverlayActive() bool {
+	dismissed, _ := m.getState()
+	return !dismissed
+}
+
+// RenderOverlayKeys returns the guide's keys: instructions, the QR code if
+// there's no strip to put it on, a key per module, and a skip key last.
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	_, saved := m.getState()
+	count := m.Resources().Device.KeyCount
+	keys := make(map[module.KeyID]image.Image, count)
+
+	next := module.Key1
+	keys[next] = m.renderInstructionKey(saved)
+	next++
+	if !m.Resources().Device.HasStrip() && int(next) < count {
+		keys[next] = m.renderQRKey()
+		next++
+	}
+	for _, id := range m.modules {
+		if int(next) >= count {
+			break
+		}
+		keys[next] = m.renderModuleKey(id, Missing(id, m.appCfg))
+		next++
+	}
+	for ; int(next) < count; next++ {
+		keys[next] = m.renderBlankKey()
+	}
+	keys[module.KeyID(count)] = m.renderSkipKey()
+	return keys
+}
+
+// RenderOverlayStrip returns the guide's strip: what to do, and the QR code.
+func (m *Module) RenderOverlayStrip() image.Image {
+	if !m.Resources().Device.HasStrip() {
+		return nil
+	}
+	rect := m.Resources().Device.StripRect
+	_, saved := m.getState()
+	return m.renderStrip(rect, saved)
+}
+
+// HandleOverlayKey dismisses the guide on any key press.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if event.Pressed {
+		m.dismiss()
+	}
+	return nil
+}
+
+// HandleOverlayDial dismisses the guide on a dial press.
+func (m *Module) HandleOverlayDial(id module.DialID, event module.DialEvent) error {
+	if event.Type == module.DialPress {
+		m.dismiss()
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch ignores touches, so tapping near the QR code
+// while scanning it doesn't dismiss the guide.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
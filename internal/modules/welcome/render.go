@@ -0,0 +1,102 @@
+package welcome
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+var (
+	colorReady  = color.RGBA{52, 199, 89, 255}
+	colorNeeds  = color.RGBA{255, 159, 10, 255}
+	colorAccent = color.RGBA{10, 132, 255, 255}
+)
+
+func (m *Module) initFonts() error {
+	var err error
+	if m.titleFace, err = render.NewFace(render.Bold, 22); err != nil {
+		return err
+	}
+	if m.textFace, err = render.NewFace(render.Bold, 12); err != nil {
+		return err
+	}
+	m.smallFace, err = render.NewFace(render.Regular, 11)
+	return err
+}
+
+// renderInstructionKey says what to run, or, once setup has written the
+// config, to restart.
+func (m *Module) renderInstructionKey(saved bool) image.Image {
+	img := render.NewKey(colorAccent)
+	lines := []string{"Run", "belowdeck", "setup"}
+	if saved {
+		lines = []string{"Config", "saved:", "restart"}
+	}
+	for i, line := range lines {
+		render.DrawTextCentered(img, line, render.KeySize/2, 24+i*16, m.textFace, render.ColorWhite)
+	}
+	return img
+}
+
+// renderQRKey draws the docs QR code filling a key.
+func (m *Module) renderQRKey() image.Image {
+	return m.code
+}
+
+// renderModuleKey shows a module's name, a word per line, and whether it's
+// ready or what it needs.
+func (m *Module) renderModuleKey(id, needs string) image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+	words := strings.Fields(name(id))
+	if len(words) > 2 {
+		words = []string{strings.Join(words, " ")}
+	}
+	y := 28 - 7*(len(words)-1)
+	for i, word := range words {
+		word = render.TruncateText(word, m.textFace, render.KeySize-6)
+		render.DrawTextCentered(img, word, render.KeySize/2, y+i*14, m.textFace, render.ColorWhite)
+	}
+
+	status, col := "Ready", colorReady
+	if needs != "" {
+		status, col = "No "+needs, colorNeeds
+	}
+	status = render.TruncateText(status, m.smallFace, render.KeySize-6)
+	render.DrawTextCentered(img, status, render.KeySize/2, 58, m.smallFace, col)
+	return img
+}
+
+func (m *Module) renderBlankKey() image.Image {
+	return render.NewKey(render.ColorBackground)
+}
+
+// renderSkipKey shows the deck as laid out when pressed, like any key.
+func (m *Module) renderSkipKey() image.Image {
+	img := render.NewKey(render.ColorKeyBg)
+	render.DrawTextCentered(img, "Skip", render.KeySize/2, render.KeySize/2+5, m.textFace, render.ColorGray)
+	return img
+}
+
+// renderStrip draws the instructions on the left and the QR code on the
+// right of the strip.
+func (m *Module) renderStrip(rect image.Rectangle, saved bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(img, img.Bounds(), &image.Uniform{render.ColorBackground}, image.Point{}, draw.Src)
+
+	side := m.code.Bounds().Dx()
+	x := rect.Dx() - side
+	draw.Draw(img, image.Rect(x, 0, x+side, side), m.code, image.Point{}, draw.Src)
+
+	title, hint := "Welcome to belowdeck", `Run "belowdeck setup" in a terminal to connect your services.`
+	if saved {
+		title, hint = "Config saved", "Restart belowdeck to load it."
+	}
+	width := x - 32
+	render.DrawText(img, render.TruncateText(title, m.titleFace, width), 16, 36, m.titleFace, render.ColorWhite)
+	render.DrawText(img, render.TruncateText(hint, m.smallFace, width), 16, 60, m.smallFace, render.ColorGray)
+	render.DrawText(img, render.TruncateText("Scan for the setup guide  ·  press any key to skip", m.smallFace, width), 16, 82, m.smallFace, render.ColorDimGray)
+	return img
+}
@@ -0,0 +1,114 @@
+package welcome
+
+import "github.com/phinze/belowdeck/internal/config"
+
+// names are the module names shown on the guide's keys.
+var names = map[string]string{
+	"nowplaying":    "Now Playing",
+	"weather":       "Weather",
+	"homeassistant": "Home Assistant",
+	"github":        "GitHub",
+	"tracker":       "Tracker",
+	"mail":          "Mail",
+	"ci":            "CI",
+	"mqtt":          "MQTT",
+	"launcher":      "Launcher",
+	"countdown":     "Countdown",
+	"sysstats":      "System",
+	"network":       "Network",
+	"keylight":      "Key Light",
+	"hue":           "Hue",
+	"kiosk":         "Kiosk",
+	"totp":          "TOTP",
+	"mirror":        "Mirror",
+	"prompter":      "Prompter",
+}
+
+// name returns the display name for module id.
+func name(id string) string {
+	if n, ok := names[id]; ok {
+		return n
+	}
+	return id
+}
+
+// Missing returns a short note of what module id still needs before it
+// can start, or "" if it has what it needs. Modules that work without
+// settings, or find them elsewhere (GitHub uses the gh CLI's login), are
+// never missing anything.
+func Missing(id string, cfg *config.Config) string {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	switch id {
+	case "weather":
+		w := cfg.Weather
+		if w.Lat == "" || w.Lon == "" {
+			return "location"
+		}
+		if (w.Provider == "" || w.Provider == "openweathermap") && w.APIKey == "" {
+			return "API key"
+		}
+	case "homeassistant":
+		h := cfg.HomeAssistant
+		if h.Server == "" {
+			return "server"
+		}
+		if h.Token == "" {
+			return "token"
+		}
+		if h.RingLightEntity == "" {
+			return "entities"
+		}
+	case "hue":
+		if cfg.Hue.Bridge == "" || cfg.Hue.AppKey == "" {
+			return "pairing"
+		}
+	case "tracker":
+		if cfg.Tracker.Provider == "" {
+			return "provider"
+		}
+		if cfg.Tracker.Token == "" {
+			return "token"
+		}
+	case "mail":
+		if cfg.Mail.Provider == "" {
+			return "provider"
+		}
+		if cfg.Mail.Password == "" {
+			return "password"
+		}
+	case "ci":
+		if cfg.CI.Provider == "" || len(cfg.CI.Pipelines) == 0 {
+			return "pipelines"
+		}
+		if cfg.CI.Token == "" {
+			return "token"
+		}
+	case "mqtt":
+		if cfg.MQTT.Broker == "" {
+			return "broker"
+		}
+	case "launcher":
+		if len(cfg.Launcher.Buttons) == 0 {
+			return "buttons"
+		}
+	case "countdown":
+		if len(cfg.Countdown.Events) == 0 {
+			return "events"
+		}
+	case "totp":
+		if len(cfg.TOTP.Accounts) == 0 {
+			return "accounts"
+		}
+	case "mirror":
+		if cfg.Mirror.Width == 0 || cfg.Mirror.Height == 0 {
+			return "rectangle"
+		}
+	case "prompter":
+		if cfg.Prompter.File == "" {
+			return "file"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,287 @@
+// Package yabai provides a Stream Deck module for switching spaces and windows via yabai.
+package yabai
+
+import (
+	"context"
+	"image"
+	"os/exec"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// Module implements the yabai space/window switcher.
+type Module struct {
+	module.BaseModule
+
+	enabled bool
+
+	// State
+	mu      sync.RWMutex
+	spaces  []Space
+	windows []Window
+
+	// Fonts
+	indexFace font.Face
+	appFace   font.Face
+
+	// Resources
+	resources module.Resources
+
+	// Cancel function for polling
+	pollCancel context.CancelFunc
+}
+
+// New creates a new yabai module.
+func New() *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("yabai"),
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "yabai"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	if _, err := exec.LookPath("yabai"); err != nil {
+		m.Log().Info("Module disabled: yabai not found in PATH")
+		m.enabled = false
+		return nil
+	}
+
+	var err error
+	if m.indexFace, err = render.NewFace(render.Bold, 26); err != nil {
+		return err
+	}
+	if m.appFace, err = render.NewFace(render.Regular, 11); err != nil {
+		return err
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	m.pollCancel = cancel
+	go m.pollYabai(pollCtx)
+
+	m.enabled = true
+	m.Log().Info("Module initialized")
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	if m.pollCancel != nil {
+		m.pollCancel()
+	}
+	return m.BaseModule.Stop()
+}
+
+// pollYabai refreshes space and window state. yabai queries are local and
+// cheap, so poll often enough that the keys track keyboard-driven switches.
+func (m *Module) pollYabai(ctx context.Context) {
+	m.refresh(ctx)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refresh(ctx)
+		}
+	}
+}
+
+// refresh queries yabai for spaces and windows.
+func (m *Module) refresh(ctx context.Context) {
+	spaces, err := querySpaces(ctx)
+	if err != nil {
+		m.Log().Warn("Query failed", "err", err)
+		return
+	}
+	windows, err := queryWindows(ctx)
+	if err != nil {
+		m.Log().Warn("Query failed", "err", err)
+		return
+	}
+
+	sort.Slice(spaces, func(i, j int) bool { return spaces[i].Index < spaces[j].Index })
+
+	m.mu.Lock()
+	m.spaces = spaces
+	m.windows = windows
+	m.mu.Unlock()
+}
+
+// getState returns the last known spaces and windows.
+func (m *Module) getState() ([]Space, []Window) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.spaces, m.windows
+}
+
+// spaceApp returns the app to show for a space: its focused window's app if
+// it has one, else the first visible window's.
+func spaceApp(space Space, windows []Window) string {
+	var first string
+	for _, w := range windows {
+		if w.Space != space.Index || w.Minimized {
+			continue
+		}
+		if w.HasFocus {
+			return w.App
+		}
+		if first == "" {
+			first = w.App
+		}
+	}
+	return first
+}
+
+// spaceForKey returns the space shown on a key: the module's Nth key shows space N.
+func (m *Module) spaceForKey(id module.KeyID, spaces []Space) (Space, bool) {
+	for i, k := range m.resources.Keys {
+		if k == id && i < len(spaces) {
+			return spaces[i], true
+		}
+	}
+	return Space{}, false
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled {
+		return nil
+	}
+
+	spaces, windows := m.getState()
+	keys := make(map[module.KeyID]image.Image)
+	for i, k := range m.resources.Keys {
+		if i < len(spaces) {
+			keys[k] = m.renderSpaceKey(spaces[i], spaceApp(spaces[i], windows))
+		} else {
+			keys[k] = render.NewKey(render.ColorBackground)
+		}
+	}
+	return keys
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	return nil
+}
+
+// HandleKey focuses the key's space.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	spaces, _ := m.getState()
+	space, ok := m.spaceForKey(id, spaces)
+	if !ok || space.HasFocus {
+		return nil
+	}
+
+	m.Log().Info("Focus space", "space", space.Index)
+	go func() {
+		if err := focusSpace(m.Context(), space.Index); err != nil {
+			m.Log().Warn("Failed to focus space", "err", err)
+		}
+		m.refresh(m.Context())
+	}()
+	return nil
+}
+
+// HandleDial cycles through the focused app's windows.
+func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
+	if !m.enabled || event.Type != module.DialRotate || event.Delta == 0 {
+		return nil
+	}
+
+	_, windows := m.getState()
+	target, ok := nextAppWindow(windows, int(event.Delta))
+	if !ok {
+		return nil
+	}
+
+	// Update focus optimistically so quick successive ticks keep advancing
+	// (copying, since renders may hold the previous slice)
+	m.mu.Lock()
+	updated := make([]Window, len(m.windows))
+	for i, w := range m.windows {
+		w.HasFocus = w.ID == target.ID
+		updated[i] = w
+	}
+	m.windows = updated
+	m.mu.Unlock()
+
+	go func() {
+		if err := focusWindow(m.Context(), target.ID); err != nil {
+			m.Log().Warn("Failed to focus window", "err", err)
+		}
+	}()
+	return nil
+}
+
+// nextAppWindow returns the window delta steps away from the focused one
+// among windows of the same app, ordered by ID.
+func nextAppWindow(windows []Window, delta int) (Window, bool) {
+	var focused *Window
+	for i := range windows {
+		if windows[i].HasFocus {
+			focused = &windows[i]
+			break
+		}
+	}
+	if focused == nil {
+		return Window{}, false
+	}
+
+	var same []Window
+	for _, w := range windows {
+		if w.App == focused.App && !w.Minimized {
+			same = append(same, w)
+		}
+	}
+	if len(same) < 2 {
+		return Window{}, false
+	}
+	sort.Slice(same, func(i, j int) bool { return same[i].ID < same[j].ID })
+
+	cur := 0
+	for i, w := range same {
+		if w.ID == focused.ID {
+			cur = i
+		}
+	}
+	n := len(same)
+	return same[((cur+delta)%n+n)%n], true
+}
+
+// HandleStripTouch processes touch strip events.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	return nil
+}
+
+// spaceTitle returns the space's label, or its index.
+func spaceTitle(space Space) string {
+	if space.Label != "" {
+		return space.Label
+	}
+	return strconv.Itoa(space.Index)
+}
@@ -0,0 +1,32 @@
+package yabai
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+var colorFocusedBg = color.RGBA{40, 80, 150, 255} // Blue
+
+// renderSpaceKey renders a space's index (or label) with its app below.
+// The focused space is highlighted.
+func (m *Module) renderSpaceKey(space Space, app string) image.Image {
+	bg, titleColor := render.ColorKeyBg, render.ColorGray
+	if space.HasFocus {
+		bg, titleColor = colorFocusedBg, render.ColorWhite
+	} else if space.Visible {
+		titleColor = render.ColorWhite
+	}
+	img := render.NewKey(bg)
+
+	title := render.TruncateText(spaceTitle(space), m.indexFace, render.KeySize-6)
+	render.DrawTextCentered(img, title, render.KeySize/2, 40, m.indexFace, titleColor)
+
+	if app != "" {
+		app = render.TruncateText(app, m.appFace, render.KeySize-6)
+		render.DrawTextCentered(img, app, render.KeySize/2, 62, m.appFace, render.ColorGray)
+	}
+
+	return img
+}
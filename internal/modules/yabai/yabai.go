@@ -0,0 +1,88 @@
+package yabai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds each yabai invocation.
+const commandTimeout = 2 * time.Second
+
+// Space is the subset of `yabai -m query --spaces` output we use.
+type Space struct {
+	Index    int    `json:"index"`
+	Label    string `json:"label"`
+	Display  int    `json:"display"`
+	HasFocus bool   `json:"has-focus"`
+	Visible  bool   `json:"is-visible"`
+	Windows  []int  `json:"windows"`
+}
+
+// Window is the subset of `yabai -m query --windows` output we use.
+type Window struct {
+	ID        int    `json:"id"`
+	App       string `json:"app"`
+	Title     string `json:"title"`
+	Space     int    `json:"space"`
+	HasFocus  bool   `json:"has-focus"`
+	Minimized bool   `json:"is-minimized"`
+}
+
+// yabai runs a yabai command and returns its stdout.
+func yabai(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "yabai", append([]string{"-m"}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("yabai %s: %s", strings.Join(args, " "), strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("yabai %s: %w", strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+// querySpaces returns all spaces across displays.
+func querySpaces(ctx context.Context) ([]Space, error) {
+	out, err := yabai(ctx, "query", "--spaces")
+	if err != nil {
+		return nil, err
+	}
+	var spaces []Space
+	if err := json.Unmarshal(out, &spaces); err != nil {
+		return nil, fmt.Errorf("parsing spaces: %w", err)
+	}
+	return spaces, nil
+}
+
+// queryWindows returns all windows across spaces.
+func queryWindows(ctx context.Context) ([]Window, error) {
+	out, err := yabai(ctx, "query", "--windows")
+	if err != nil {
+		return nil, err
+	}
+	var windows []Window
+	if err := json.Unmarshal(out, &windows); err != nil {
+		return nil, fmt.Errorf("parsing windows: %w", err)
+	}
+	return windows, nil
+}
+
+// focusSpace switches to a space by index. Requires yabai's scripting addition.
+func focusSpace(ctx context.Context, index int) error {
+	_, err := yabai(ctx, "space", "--focus", strconv.Itoa(index))
+	return err
+}
+
+// focusWindow focuses a window by ID.
+func focusWindow(ctx context.Context, id int) error {
+	_, err := yabai(ctx, "window", "--focus", strconv.Itoa(id))
+	return err
+}
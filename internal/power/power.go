@@ -0,0 +1,151 @@
+// Package power reports the system going to sleep and waking up. Watch
+// returns the platform's notifier; Simulator stands in for it where there
+// is no real sleep, such as the emulator and tests.
+package power
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a system power transition.
+type Event int
+
+const (
+	Sleep Event = iota + 1 // the system is about to sleep
+	Wake                   // the system has woken up
+)
+
+func (e Event) String() string {
+	switch e {
+	case Sleep:
+		return "sleep"
+	case Wake:
+		return "wake"
+	default:
+		return "unknown"
+	}
+}
+
+// Notifier delivers power events.
+type Notifier interface {
+	// Events returns the channel events are delivered on. It is never
+	// closed; a notifier that can't watch the system simply never fires.
+	Events() <-chan Event
+}
+
+// eventBuffer is how many events a notifier holds for a slow reader
+// before dropping new ones.
+const eventBuffer = 4
+
+// source is the channel behind Watch and Simulator.
+type source struct {
+	ch chan Event
+}
+
+func newSource() *source {
+	return &source{ch: make(chan Event, eventBuffer)}
+}
+
+// Events returns the channel events are delivered on.
+func (s *source) Events() <-chan Event {
+	return s.ch
+}
+
+// send delivers e without blocking the platform callback.
+func (s *source) send(e Event) {
+	select {
+	case s.ch <- e:
+	default:
+	}
+}
+
+// Simulator is a Notifier whose events are sent by calling Sleep and Wake.
+type Simulator struct {
+	*source
+}
+
+// NewSimulator creates a simulator with no events pending.
+func NewSimulator() *Simulator {
+	return &Simulator{newSource()}
+}
+
+// Sleep sends a sleep event.
+func (s *Simulator) Sleep() {
+	s.send(Sleep)
+}
+
+// Wake sends a wake event.
+func (s *Simulator) Wake() {
+	s.send(Wake)
+}
+
+// Wakes reduces a notifier's events to wake signals for a reconnect loop.
+// At most one wake is pending at a time, since several wakes before the
+// loop gets to them call for only one reconnect.
+type Wakes struct {
+	ch chan struct{}
+}
+
+// WatchWakes forwards n's wake events until ctx is canceled.
+func WatchWakes(ctx context.Context, n Notifier) *Wakes {
+	w := &Wakes{ch: make(chan struct{}, 1)}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-n.Events():
+				if e != Wake {
+					continue
+				}
+				select {
+				case w.ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// C returns the channel signaled on wake.
+func (w *Wakes) C() <-chan struct{} {
+	return w.ch
+}
+
+// Drain discards a pending wake. Call it once a device is connected: a wake
+// from before the device enumerated would otherwise immediately tear down
+// the new connection.
+func (w *Wakes) Drain() bool {
+	select {
+	case <-w.ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// Retry settings for ProbeAfterWake. After wake, USB devices may take
+// several seconds to enumerate.
+const (
+	wakeProbeAttempts = 10
+	wakeProbeInterval = 500 * time.Millisecond
+)
+
+// ProbeAfterWake calls probe until it reports success, giving devices a
+// few seconds to come back after a wake. It returns false if they don't or
+// ctx is canceled first.
+func ProbeAfterWake(ctx context.Context, probe func() bool) bool {
+	for i := 0; i < wakeProbeAttempts; i++ {
+		if probe() {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wakeProbeInterval):
+		}
+	}
+	return false
+}
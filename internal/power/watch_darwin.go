@@ -0,0 +1,30 @@
+package power
+
+import (
+	"context"
+
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/prashantgupta24/mac-sleep-notifier/notifier"
+)
+
+// Watch returns a notifier for the system's sleep and wake events, from the
+// IOKit power notifications. These can't be unsubscribed from, so it keeps
+// watching after ctx is canceled.
+func Watch(ctx context.Context) Notifier {
+	logger := logging.For("power")
+	n := newSource()
+	activities := notifier.GetInstance().Start()
+	go func() {
+		for activity := range activities {
+			switch activity.Type {
+			case notifier.Sleep:
+				logger.Info("System sleep detected")
+				n.send(Sleep)
+			case notifier.Awake:
+				logger.Info("System wake detected")
+				n.send(Wake)
+			}
+		}
+	}()
+	return n
+}
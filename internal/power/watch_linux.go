@@ -0,0 +1,60 @@
+package power
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/phinze/belowdeck/internal/logging"
+)
+
+// Watch returns a notifier for the system's sleep and wake events, from
+// logind's PrepareForSleep signal, until ctx is canceled. Without a system
+// bus it never fires; udev still reports the deck reappearing.
+func Watch(ctx context.Context) Notifier {
+	logger := logging.For("power")
+	n := newSource()
+
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		logger.Warn("Sleep/wake detection unavailable", "err", err)
+		return n
+	}
+	err = conn.AddMatchSignal(
+		dbus.WithMatchInterface("org.freedesktop.login1.Manager"),
+		dbus.WithMatchMember("PrepareForSleep"),
+	)
+	if err != nil {
+		logger.Warn("Sleep/wake detection unavailable", "err", err)
+		conn.Close()
+		return n
+	}
+
+	signals := make(chan *dbus.Signal, 4)
+	conn.Signal(signals)
+	go func() {
+		defer conn.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-signals:
+				// The argument is true going to sleep and false on wake
+				if len(sig.Body) != 1 {
+					continue
+				}
+				sleeping, ok := sig.Body[0].(bool)
+				if !ok {
+					continue
+				}
+				if sleeping {
+					logger.Info("System sleep detected")
+					n.send(Sleep)
+				} else {
+					logger.Info("System wake detected")
+					n.send(Wake)
+				}
+			}
+		}
+	}()
+	return n
+}
@@ -0,0 +1,38 @@
+package power
+
+import (
+	"context"
+
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/phinze/belowdeck/internal/winmsg"
+)
+
+const (
+	wmPowerBroadcast      = 0x0218
+	pbtAPMSuspend         = 0x0004
+	pbtAPMResumeAutomatic = 0x0012
+)
+
+// Watch returns a notifier for the system's sleep and wake events, from the
+// WM_POWERBROADCAST sent to a hidden window, until ctx is canceled.
+func Watch(ctx context.Context) Notifier {
+	logger := logging.For("power")
+	n := newSource()
+	err := winmsg.Listen(ctx, "BelowdeckPower", nil, func(msg uint32, wParam, lParam uintptr) {
+		if msg != wmPowerBroadcast {
+			return
+		}
+		switch wParam {
+		case pbtAPMSuspend:
+			logger.Info("System sleep detected")
+			n.send(Sleep)
+		case pbtAPMResumeAutomatic:
+			logger.Info("System wake detected")
+			n.send(Wake)
+		}
+	})
+	if err != nil {
+		logger.Warn("Sleep/wake detection unavailable", "err", err)
+	}
+	return n
+}
@@ -0,0 +1,108 @@
+// Package reachability tracks whether the network can be reached, so
+// modules polling a service can pause while it can't, rather than waiting
+// out a timeout every interval, and fetch as soon as it's back.
+//
+// It probes by connecting a UDP socket to a public address, which sends
+// nothing but fails when there's no route off the machine, as when Wi-Fi
+// is off or hasn't joined a network yet.
+package reachability
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/logging"
+)
+
+// checkInterval is how often reachability is probed.
+const checkInterval = 5 * time.Second
+
+// probeAddrs are public addresses, IPv4 and IPv6, a route to either of
+// which counts as reachable. Nothing is sent to them.
+var probeAddrs = []string{"1.1.1.1:53", "[2606:4700:4700::1111]:53"}
+
+var (
+	startOnce sync.Once
+
+	mu      sync.Mutex
+	online  = true
+	changed = make(chan struct{}) // closed and replaced when online changes
+)
+
+// start begins probing, once per process.
+func start() {
+	startOnce.Do(func() {
+		update(probe())
+		go func() {
+			for {
+				time.Sleep(checkInterval)
+				update(probe())
+			}
+		}()
+	})
+}
+
+// probe reports whether there's a route to any of probeAddrs.
+func probe() bool {
+	for _, addr := range probeAddrs {
+		conn, err := net.Dial("udp", addr)
+		if err == nil {
+			conn.Close()
+			return true
+		}
+	}
+	return false
+}
+
+// update records whether the network is reachable, waking pollers if that
+// changed.
+func update(reachable bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if reachable == online {
+		return
+	}
+	online = reachable
+	close(changed)
+	changed = make(chan struct{})
+	if reachable {
+		logging.For("reachability").Info("Network reachable, resuming polling")
+	} else {
+		logging.For("reachability").Info("Network unreachable, pausing polling")
+	}
+}
+
+// state returns whether the network is reachable and a channel closed when
+// that changes.
+func state() (bool, <-chan struct{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	return online, changed
+}
+
+// Poll calls fetch now and every interval until ctx is done. While the
+// network is unreachable fetches are skipped, and once it's back the
+// missed fetch happens right away.
+func Poll(ctx context.Context, interval time.Duration, fetch func(ctx context.Context)) {
+	start()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	due := true
+	for {
+		reachable, change := state()
+		if reachable && due {
+			fetch(ctx)
+			due = false
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			due = true
+		case <-change:
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// defaultFrameDelay is how long a frame shows when its delay is unset, as
+// browsers treat GIF frames with no delay.
+const defaultFrameDelay = 100 * time.Millisecond
+
+// Animation is a key image that changes over time, such as a spinner, an
+// animated icon, or a pulsing badge. Return one from RenderKeys like any
+// other image: the coordinator shows whichever frame is due and renders
+// again when the next one is. As an image.Image it is its first frame, so
+// code that doesn't know about animations draws that.
+//
+// Animations loop, timed from the wall clock rather than from when they
+// were created, so a module can build a new one each render without
+// restarting it, and animations of the same length stay in step.
+type Animation struct {
+	Frames []image.Image
+	Delays []time.Duration // how long each frame shows; defaultFrameDelay if unset
+}
+
+// NewAnimation returns an animation showing each frame for delay.
+func NewAnimation(frames []image.Image, delay time.Duration) *Animation {
+	delays := make([]time.Duration, len(frames))
+	for i := range delays {
+		delays[i] = delay
+	}
+	return &Animation{Frames: frames, Delays: delays}
+}
+
+// ColorModel implements image.Image.
+func (a *Animation) ColorModel() color.Model {
+	return a.Frames[0].ColorModel()
+}
+
+// Bounds implements image.Image.
+func (a *Animation) Bounds() image.Rectangle {
+	return a.Frames[0].Bounds()
+}
+
+// At implements image.Image.
+func (a *Animation) At(x, y int) color.Color {
+	return a.Frames[0].At(x, y)
+}
+
+// delay returns how long frame i shows.
+func (a *Animation) delay(i int) time.Duration {
+	if i < len(a.Delays) && a.Delays[i] > 0 {
+		return a.Delays[i]
+	}
+	return defaultFrameDelay
+}
+
+// Frame returns the frame showing at t and how long until the next one.
+func (a *Animation) Frame(t time.Time) (image.Image, time.Duration) {
+	var total time.Duration
+	for i := range a.Frames {
+		total += a.delay(i)
+	}
+	pos := time.Duration(t.UnixNano() % int64(total))
+	for i, frame := range a.Frames {
+		d := a.delay(i)
+		if pos < d {
+			return frame, d - pos
+		}
+		pos -= d
+	}
+	return a.Frames[0], a.delay(0) // unreachable
+}
+
+// Map returns an animation of fn applied to each frame, with the same
+// timing. Use it to draw an animated icon into a key: map once and keep
+// the result, since it draws every frame.
+func (a *Animation) Map(fn func(frame image.Image) image.Image) *Animation {
+	frames := make([]image.Image, len(a.Frames))
+	for i, f := range a.Frames {
+		frames[i] = fn(f)
+	}
+	return &Animation{Frames: frames, Delays: a.Delays}
+}
+
+// Pulse returns an animation of src fading toward col and back over
+// period, for drawing attention to a key.
+func Pulse(src image.Image, col color.Color, period time.Duration) *Animation {
+	const steps = 10
+	b := src.Bounds()
+	fill := &image.Uniform{col}
+	frames := make([]image.Image, steps)
+	for i := range frames {
+		// Toward col for the first half of the steps, back for the second
+		level := float64(min(i, steps-i)) / (steps / 2)
+		mask := &image.Uniform{color.Alpha{uint8(level * 160)}}
+		img := image.NewRGBA(b)
+		draw.Draw(img, b, src, b.Min, draw.Src)
+		draw.DrawMask(img, b, fill, image.Point{}, mask, image.Point{}, draw.Over)
+		frames[i] = img
+	}
+	return NewAnimation(frames, period/steps)
+}
+
+// LoadGIF loads an animated GIF and scales its frames to size x size. A GIF
+// with one frame loads as a still image.
+func LoadGIF(path string, size int) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	if len(g.Image) == 1 {
+		return Scale(g.Image[0], size), nil
+	}
+
+	// Frames may cover only part of the canvas, drawn over what the
+	// previous frames left according to their disposal
+	canvas := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	anim := &Animation{
+		Frames: make([]image.Image, len(g.Image)),
+		Delays: make([]time.Duration, len(g.Image)),
+	}
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+		if disposal(g, i) == gif.DisposalPrevious {
+			previous = image.NewRGBA(canvas.Bounds())
+			draw.Draw(previous, previous.Bounds(), canvas, image.Point{}, draw.Src)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		anim.Frames[i] = Scale(canvas, size)
+		anim.Delays[i] = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+
+		switch disposal(g, i) {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return anim, nil
+}
+
+// disposal returns how frame i of g is cleared before the next frame.
+func disposal(g *gif.GIF, i int) byte {
+	if i < len(g.Disposal) {
+		return g.Disposal[i]
+	}
+	return 0
+}
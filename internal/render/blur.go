@@ -0,0 +1,34 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+// blurBlock is the side of the squares Blur averages, in pixels: enough to
+// hide the 9-12px text modules draw on keys and the strip.
+const blurBlock = 6
+
+// Blur obscures r in img by averaging it in blocks, so text there keeps its
+// place and length but can't be read.
+func Blur(img *image.RGBA, r image.Rectangle) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y += blurBlock {
+		for x := r.Min.X; x < r.Max.X; x += blurBlock {
+			block := image.Rect(x, y, x+blurBlock, y+blurBlock).Intersect(r)
+			var sr, sg, sb, sa, n int
+			for by := block.Min.Y; by < block.Max.Y; by++ {
+				for bx := block.Min.X; bx < block.Max.X; bx++ {
+					c := img.RGBAAt(bx, by)
+					sr, sg, sb, sa, n = sr+int(c.R), sg+int(c.G), sb+int(c.B), sa+int(c.A), n+1
+				}
+			}
+			avg := color.RGBA{uint8(sr / n), uint8(sg / n), uint8(sb / n), uint8(sa / n)}
+			for by := block.Min.Y; by < block.Max.Y; by++ {
+				for bx := block.Min.X; bx < block.Max.X; bx++ {
+					img.SetRGBA(bx, by, avg)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // register decoders for LoadIcon
+	_ "image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/config"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/draw"
+)
+
+// SVGIcon renders an SVG string to an image with the given size and color.
+// "currentColor" in the SVG is replaced with iconColor, so Lucide icons tint.
+func SVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
+	// Replace currentColor with the actual color
+	r, g, b, _ := iconColor.RGBA()
+	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
+
+	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
+	if err != nil {
+		slog.Warn("Failed to parse SVG", "err", err)
+		return image.NewRGBA(image.Rect(0, 0, size, size))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	icon.SetTarget(0, 0, float64(size), float64(size))
+
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	raster := rasterx.NewDasher(size, size, scanner)
+	icon.Draw(raster, 1.0)
+
+	return img
+}
+
+// LoadIcon loads an SVG, PNG, JPEG, or GIF file and scales it to size x
+// size. SVGs are tinted with iconColor; bitmaps keep their own colors. An
+// animated GIF loads as an *Animation.
+func LoadIcon(path string, size int, iconColor color.Color) (image.Image, error) {
+	path = config.ExpandHome(path)
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return SVGIcon(string(data), size, iconColor), nil
+	}
+	if strings.EqualFold(filepath.Ext(path), ".gif") {
+		return LoadGIF(path, size)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return Scale(src, size), nil
+}
+
+// Scale fits src into a size x size square, preserving aspect ratio.
+func Scale(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := size, size
+	if b.Dx() > b.Dy() {
+		h = size * b.Dy() / b.Dx()
+	} else if b.Dy() > b.Dx() {
+		w = size * b.Dx() / b.Dy()
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	x, y := (size-w)/2, (size-h)/2
+	draw.CatmullRom.Scale(dst, image.Rect(x, y, x+w, y+h), src, b, draw.Over, nil)
+	return dst
+}
+
+// Tint recolors a template image (such as an SF Symbol), keeping its alpha
+// channel as a mask.
+func Tint(src image.Image, col color.Color) image.Image {
+	dst := image.NewRGBA(src.Bounds())
+	draw.DrawMask(dst, dst.Bounds(), &image.Uniform{col}, image.Point{}, src, src.Bounds().Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,117 @@
+package render
+
+import (
+	"embed"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/phinze/belowdeck/internal/config"
+)
+
+// builtinIcons are the Lucide icons modules draw, by name.
+//
+//go:embed icons/*.svg
+var builtinIcons embed.FS
+
+// iconExts are the file types looked up in the icon directory, in order.
+var iconExts = []string{".svg", ".png", ".gif", ".jpg", ".jpeg"}
+
+var (
+	iconMu    sync.Mutex
+	iconDir   string
+	iconCache = make(map[iconKey]loadedIcon)
+)
+
+type iconKey struct {
+	spec  string
+	size  int
+	color color.RGBA
+}
+
+type loadedIcon struct {
+	img image.Image
+	err error
+}
+
+// SetIconDir sets the icon pack directory, whose files are found by name
+// ahead of the built-in icons, so an icon there named like a built-in one
+// restyles every module that draws it. Call it before modules start.
+func SetIconDir(dir string) {
+	iconMu.Lock()
+	defer iconMu.Unlock()
+	iconDir = dir
+	clear(iconCache)
+}
+
+// FindIcon returns the icon spec names at size x size, tinted with
+// iconColor where it's a template. A spec containing a slash is an image
+// file, as for LoadIcon; anything else is a name, looked up as a file in
+// the icon directory (name.svg, .png, .gif, or .jpg), then among the
+// built-in Lucide icons, then as an SF Symbol. Results are cached, failures
+// included, so it's cheap to call on every render; the image is shared, so
+// draw it onto another rather than drawing on it.
+func FindIcon(spec string, size int, iconColor color.Color) (image.Image, error) {
+	key := iconKey{spec, size, color.RGBAModel.Convert(iconColor).(color.RGBA)}
+
+	iconMu.Lock()
+	cached, ok := iconCache[key]
+	dir := iconDir
+	iconMu.Unlock()
+	if ok {
+		return cached.img, cached.err
+	}
+
+	img, err := findIcon(spec, dir, size, iconColor)
+	iconMu.Lock()
+	iconCache[key] = loadedIcon{img, err}
+	iconMu.Unlock()
+	return img, err
+}
+
+func findIcon(spec, dir string, size int, iconColor color.Color) (image.Image, error) {
+	if strings.Contains(spec, "/") {
+		return LoadIcon(spec, size, iconColor)
+	}
+
+	if dir != "" {
+		dir = config.ExpandHome(dir)
+		for _, ext := range iconExts {
+			path := filepath.Join(dir, spec+ext)
+			if _, err := os.Stat(path); err == nil {
+				return LoadIcon(path, size, iconColor)
+			}
+		}
+	}
+	if data, err := builtinIcons.ReadFile("icons/" + spec + ".svg"); err == nil {
+		return SVGIcon(string(data), size, iconColor), nil
+	}
+	img, err := SFSymbol(spec, size, iconColor)
+	if err != nil {
+		return nil, fmt.Errorf("no icon named %q in the icon directory or built-in icons, and %w", spec, err)
+	}
+	return img, nil
+}
+
+// Icon returns a built-in icon by name, as FindIcon does. Modules use it
+// for their own icons, which always exist unless the icon directory
+// replaces one with a broken file; then it logs once and returns a blank
+// image.
+func Icon(name string, size int, iconColor color.Color) image.Image {
+	img, err := FindIcon(name, size, iconColor)
+	if err != nil {
+		iconMu.Lock()
+		key := iconKey{name, size, color.RGBAModel.Convert(iconColor).(color.RGBA)}
+		blank := image.NewRGBA(image.Rect(0, 0, size, size))
+		iconCache[key] = loadedIcon{blank, nil}
+		iconMu.Unlock()
+		slog.Warn("Failed to load icon", "icon", name, "err", err)
+		return blank
+	}
+	return img
+}
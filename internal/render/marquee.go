@@ -0,0 +1,68 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"sync"
+	"time"
+
+	"golang.org/x/image/font"
+)
+
+// Marquee timing and spacing.
+const (
+	marqueeSpeed = 30              // pixels per second
+	marqueePause = 2 * time.Second // hold at the start of each pass
+	marqueeGap   = 40              // pixels between the text and its repeat
+)
+
+// Marquee draws text that is too wide for its space scrolling across
+// successive frames, pausing at the start of each pass; text that fits is
+// drawn in place. The zero value is ready to use. Keep one Marquee per line
+// of text, since it restarts the scroll whenever its text changes.
+type Marquee struct {
+	mu    sync.Mutex
+	text  string
+	start time.Time
+}
+
+// Draw draws text with its baseline at y, clipped to the span of width
+// pixels starting at x.
+func (mq *Marquee) Draw(img *image.RGBA, text string, x, y, width int, face font.Face, col color.Color) {
+	textW := font.MeasureString(face, text).Ceil()
+	if textW <= width {
+		DrawText(img, text, x, y, face, col)
+		return
+	}
+
+	metrics := face.Metrics()
+	clip := image.Rect(x, y-metrics.Ascent.Ceil(), x+width, y+metrics.Descent.Ceil())
+	dst, ok := img.SubImage(clip).(*image.RGBA)
+	if !ok || dst.Rect.Empty() {
+		return
+	}
+
+	cycle := textW + marqueeGap
+	offset := mq.offset(text, cycle, time.Now())
+	DrawText(dst, text, x-offset, y, face, col)
+	DrawText(dst, text, x-offset+cycle, y, face, col)
+}
+
+// offset returns how far text has scrolled at now, where one pass moves it
+// cycle pixels.
+func (mq *Marquee) offset(text string, cycle int, now time.Time) int {
+	mq.mu.Lock()
+	if text != mq.text || mq.start.IsZero() {
+		mq.text = text
+		mq.start = now
+	}
+	elapsed := now.Sub(mq.start)
+	mq.mu.Unlock()
+
+	scroll := time.Duration(cycle) * time.Second / marqueeSpeed
+	t := elapsed % (marqueePause + scroll)
+	if t < marqueePause {
+		return 0
+	}
+	return int((t - marqueePause).Seconds() * marqueeSpeed)
+}
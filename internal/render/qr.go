@@ -0,0 +1,25 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render/qr"
+)
+
+// qrBorder is the quiet zone around a QR code, in modules. Two is less than
+// the spec's four, but phones scan it fine off the light square and it
+// leaves bigger modules on a 72px key.
+const qrBorder = 2
+
+// QR draws text, such as a URL or a qr.WiFi string, as a QR code on a white
+// square of side size: KeySize for a key, or the strip's height. Text over
+// 213 bytes is an error. Encoding takes a moment, so render once per text
+// and reuse the image.
+func QR(text string, size int) (*image.RGBA, error) {
+	code, err := qr.Encode(text)
+	if err != nil {
+		return nil, err
+	}
+	return code.Image(size, qrBorder, color.Black, color.White), nil
+}
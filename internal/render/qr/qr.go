@@ -0,0 +1,64 @@
+// Package qr encodes short text, such as a URL, as a QR code for drawing on
+// a key or the strip. Encoding is done by github.com/skip2/go-qrcode at error
+// correction level M, limited to versions 1-10 (up to 213 bytes), which is
+// all the deck has room for; see render.QR for drawing one.
+package qr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// maxVersion is the largest version encoded; later ones are too dense to
+// scan off a key.
+const maxVersion = 10
+
+// Code is an encoded QR code.
+type Code struct {
+	modules [][]bool // [y][x], true is dark
+}
+
+// Encode encodes text in the smallest version that holds it.
+func Encode(text string) (*Code, error) {
+	q, err := qrcode.New(text, qrcode.Medium)
+	if err != nil || q.VersionNumber > maxVersion {
+		return nil, fmt.Errorf("qr: %d bytes is too long to encode", len(text))
+	}
+	q.DisableBorder = true
+	return &Code{modules: q.Bitmap()}, nil
+}
+
+// Size returns the number of modules per side, without a quiet zone.
+func (c *Code) Size() int {
+	return len(c.modules)
+}
+
+// Dark reports whether the module at (x, y) is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Image draws the code as dark modules on a light square of side px, with
+// a quiet zone of at least border modules. Modules are whole pixels, so
+// the code is centered with any leftover as extra margin.
+func (c *Code) Image(px, border int, dark, light color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, px, px))
+	draw.Draw(img, img.Bounds(), &image.Uniform{light}, image.Point{}, draw.Src)
+	size := c.Size()
+	scale := max(px/(size+2*border), 1)
+	off := (px - scale*size) / 2
+	fill := &image.Uniform{dark}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if c.modules[y][x] {
+				r := image.Rect(off+x*scale, off+y*scale, off+(x+1)*scale, off+(y+1)*scale)
+				draw.Draw(img, r, fill, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,23 @@
+package qr
+
+import "strings"
+
+// wifiEscaper escapes the characters the Wi-Fi payload format reserves.
+var wifiEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`, `"`, `\"`)
+
+// WiFi returns the text of a QR code that joins a Wi-Fi network when
+// scanned with a phone camera. security is WPA, WEP, or empty for an open
+// network.
+func WiFi(ssid, password, security string) string {
+	var b strings.Builder
+	b.WriteString("WIFI:")
+	if security != "" {
+		b.WriteString("T:" + security + ";")
+	}
+	b.WriteString("S:" + wifiEscaper.Replace(ssid) + ";")
+	if security != "" {
+		b.WriteString("P:" + wifiEscaper.Replace(password) + ";")
+	}
+	b.WriteString(";")
+	return b.String()
+}
@@ -0,0 +1,168 @@
+// Package render provides drawing helpers shared by modules: fonts, text,
+// and icons in the house style.
+package render
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+//go:embed fonts/PublicSans-Regular.ttf
+var fontRegular []byte
+
+// KeySize is the edge length of a Stream Deck Plus key image.
+const KeySize = 72
+
+// Common colors
+var (
+	ColorBackground = color.RGBA{25, 25, 25, 255}
+	ColorKeyBg      = color.RGBA{40, 40, 40, 255}
+	ColorWhite      = color.RGBA{255, 255, 255, 255}
+	ColorGray       = color.RGBA{160, 160, 160, 255}
+	ColorDimGray    = color.RGBA{80, 80, 80, 255}
+)
+
+// Weight selects a font weight.
+type Weight int
+
+const (
+	Regular Weight = iota
+	Bold
+)
+
+var (
+	parseOnce   sync.Once
+	parsedFonts [2]*opentype.Font
+	parseErr    error
+)
+
+// NewFace returns a PublicSans face at the given weight and size.
+func NewFace(w Weight, size float64) (font.Face, error) {
+	parseOnce.Do(func() {
+		if parsedFonts[Regular], parseErr = opentype.Parse(fontRegular); parseErr != nil {
+			parseErr = fmt.Errorf("parse regular font: %w", parseErr)
+			return
+		}
+		if parsedFonts[Bold], parseErr = opentype.Parse(fontBold); parseErr != nil {
+			parseErr = fmt.Errorf("parse bold font: %w", parseErr)
+		}
+	})
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	face, err := opentype.NewFace(parsedFonts[w], &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create face: %w", err)
+	}
+	return face, nil
+}
+
+// NewKey returns a key-sized image filled with bg.
+func NewKey(bg color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, KeySize, KeySize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+	return img
+}
+
+// DrawText draws text with its baseline at the given position.
+func DrawText(img *image.RGBA, text string, x, y int, face font.Face, col color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(col),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
+	}
+	d.DrawString(text)
+}
+
+// DrawTextCentered draws text horizontally centered at the given position.
+func DrawTextCentered(img *image.RGBA, text string, centerX, y int, face font.Face, col color.Color) {
+	width := font.MeasureString(face, text).Ceil()
+	DrawText(img, text, centerX-width/2, y, face, col)
+}
+
+// TruncateText truncates text to fit within maxWidth, adding an ellipsis if needed.
+func TruncateText(text string, face font.Face, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+
+	runes := []rune(text)
+	for i := len(runes); i > 0; i-- {
+		truncated := string(runes[:i]) + "..."
+		if font.MeasureString(face, truncated).Ceil() <= maxWidth {
+			return truncated
+		}
+	}
+	return "..."
+}
+
+// FormatAge formats how long ago something happened compactly: "now"
+// under a minute, then e.g. "5m", "3h", or "2d".
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// WrapText splits text into lines no wider than maxWidth. With maxLines
+// over zero, the last line gets whatever is left, truncated. A word too
+// long for a line of its own is truncated.
+func WrapText(text string, face font.Face, maxWidth, maxLines int) []string {
+	var lines []string
+	var line string
+	words := strings.Fields(text)
+	for i, word := range words {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth || line == "" {
+			line = candidate
+			continue
+		}
+		lines = append(lines, TruncateText(line, face, maxWidth))
+		if len(lines) == maxLines-1 {
+			// Last line gets everything left
+			rest := strings.Join(words[i:], " ")
+			return append(lines, TruncateText(rest, face, maxWidth))
+		}
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, TruncateText(line, face, maxWidth))
+	}
+	return lines
+}
+
+// DrawIcon draws icon centered horizontally in img with its top edge at y.
+func DrawIcon(img *image.RGBA, icon image.Image, y int) {
+	b := icon.Bounds()
+	x := (img.Bounds().Dx() - b.Dx()) / 2
+	draw.Draw(img, image.Rect(x, y, x+b.Dx(), y+b.Dy()), icon, b.Min, draw.Over)
+}
@@ -0,0 +1,131 @@
+// Package rendertest compares rendered key and strip images against golden
+// PNGs, so visual changes to a module's renderer show up in go test.
+//
+// Golden images live in the calling package's testdata directory. After an
+// intended change, regenerate a package's images with -update, for example
+//
+//	go test ./internal/modules/weather -update
+//
+// and review the new PNGs before committing them.
+package rendertest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden images with the rendered output")
+
+// Tolerances absorb small anti-aliasing differences between font and
+// rasterizer versions without hiding a moved or recolored element.
+const (
+	// channelTolerance is how far a color channel may differ before the
+	// pixel counts as changed.
+	channelTolerance = 8
+
+	// pixelTolerance is the fraction of pixels that may change.
+	pixelTolerance = 0.002
+)
+
+// Golden compares img against testdata/name.png, failing t if they differ
+// beyond the tolerances. On failure the rendered image is saved as
+// testdata/name.got.png for comparison. With -update, the golden image is
+// written instead.
+func Golden(t testing.TB, name string, img image.Image) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".png")
+	gotPath := filepath.Join("testdata", name+".got.png")
+	if *update {
+		if err := writePNG(path, img); err != nil {
+			t.Fatalf("writing golden image: %v", err)
+		}
+		os.Remove(gotPath)
+		return
+	}
+
+	want, err := readPNG(path)
+	if err != nil {
+		t.Fatalf("reading golden image (run with -update to create it): %v", err)
+	}
+	got := toRGBA(img)
+
+	if err := compare(got, want); err != nil {
+		if werr := writePNG(gotPath, got); werr != nil {
+			t.Logf("saving rendered image: %v", werr)
+		}
+		t.Errorf("%s: %v; rendered image saved to %s", name, err, gotPath)
+		return
+	}
+	os.Remove(gotPath)
+}
+
+// compare reports how got differs from want, or nil if within tolerance.
+func compare(got, want *image.RGBA) error {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return fmt.Errorf("size %v, want %v", got.Bounds().Size(), want.Bounds().Size())
+	}
+
+	w, h := got.Bounds().Dx(), got.Bounds().Dy()
+	changed := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			g := got.RGBAAt(got.Rect.Min.X+x, got.Rect.Min.Y+y)
+			e := want.RGBAAt(want.Rect.Min.X+x, want.Rect.Min.Y+y)
+			if diff(g.R, e.R) > channelTolerance || diff(g.G, e.G) > channelTolerance ||
+				diff(g.B, e.B) > channelTolerance || diff(g.A, e.A) > channelTolerance {
+				changed++
+			}
+		}
+	}
+	if limit := int(pixelTolerance * float64(w*h)); changed > limit {
+		return fmt.Errorf("%d of %d pixels differ (at most %d may)", changed, w*h, limit)
+	}
+	return nil
+}
+
+func diff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// toRGBA returns img as an RGBA image with its origin at (0, 0).
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+func readPNG(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return toRGBA(img), nil
+}
+
+func writePNG(path string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, toRGBA(img)); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+	"github.com/ebitengine/purego/objc"
+	"golang.org/x/image/tiff"
+)
+
+var (
+	appKitOnce sync.Once
+	appKitErr  error
+)
+
+// loadAppKit makes the NSImage classes available to the Objective-C runtime.
+func loadAppKit() error {
+	appKitOnce.Do(func() {
+		_, appKitErr = purego.Dlopen("/System/Library/Frameworks/AppKit.framework/AppKit", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	})
+	return appKitErr
+}
+
+// SFSymbol renders a named SF Symbol (e.g. "music.note") at the given size,
+// tinted with iconColor.
+func SFSymbol(name string, size int, iconColor color.Color) (image.Image, error) {
+	if err := loadAppKit(); err != nil {
+		return nil, fmt.Errorf("loading AppKit: %w", err)
+	}
+
+	// Convenience constructors return autoreleased objects
+	pool := objc.ID(objc.GetClass("NSAutoreleasePool")).Send(objc.RegisterName("new"))
+	defer pool.Send(objc.RegisterName("drain"))
+
+	nsName := objc.ID(objc.GetClass("NSString")).Send(objc.RegisterName("stringWithUTF8String:"), name)
+	img := objc.ID(objc.GetClass("NSImage")).Send(objc.RegisterName("imageWithSystemSymbolName:accessibilityDescription:"), nsName, objc.ID(0))
+	if img == 0 {
+		return nil, fmt.Errorf("unknown SF Symbol %q", name)
+	}
+
+	// Rasterize at roughly the target size so scaling doesn't blur the strokes
+	cfg := objc.ID(objc.GetClass("NSImageSymbolConfiguration")).Send(objc.RegisterName("configurationWithPointSize:weight:"), float64(size)*0.8, float64(0))
+	if sized := img.Send(objc.RegisterName("imageWithSymbolConfiguration:"), cfg); sized != 0 {
+		img = sized
+	}
+
+	data := img.Send(objc.RegisterName("TIFFRepresentation"))
+	if data == 0 {
+		return nil, fmt.Errorf("SF Symbol %q: no image data", name)
+	}
+	length := objc.Send[uint64](data, objc.RegisterName("length"))
+	ptr := objc.Send[unsafe.Pointer](data, objc.RegisterName("bytes"))
+	raw := bytes.Clone(unsafe.Slice((*byte)(ptr), length))
+
+	src, err := tiff.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("SF Symbol %q: %w", name, err)
+	}
+	return Scale(Tint(src, iconColor), size), nil
+}
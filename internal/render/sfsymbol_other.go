@@ -0,0 +1,14 @@
+//go:build !darwin
+
+package render
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// SFSymbol is only available on macOS.
+func SFSymbol(name string, size int, iconColor color.Color) (image.Image, error) {
+	return nil, errors.New("SF Symbols require macOS")
+}
@@ -0,0 +1,252 @@
+// Package replay records deck input to a file and plays it back against the
+// emulator or the fake device, to reproduce interaction bugs.
+//
+// Recordings are taken from the coordinator's event stream, so they hold
+// the input as the deck sent it: raw dial deltas, before any tuning, and
+// key and dial holds as measured on release. Each line of a recording is
+// one Input as JSON.
+package replay
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/events"
+)
+
+// Input is one recorded interaction. Presses are recorded once, with how
+// long they were held.
+type Input struct {
+	// At is when the input happened, in milliseconds from the start of the
+	// recording.
+	At int64 `json:"at_ms"`
+
+	// Type is one of the events package's input types: key, dial_rotate,
+	// dial_press, strip_touch, or strip_swipe.
+	Type string `json:"type"`
+
+	Key    int   `json:"key,omitempty"`
+	Dial   int   `json:"dial,omitempty"`
+	Delta  int   `json:"delta,omitempty"`
+	HoldMS int64 `json:"hold_ms,omitempty"`
+
+	Touch string `json:"touch,omitempty"` // "short" or "long"
+	X     int    `json:"x,omitempty"`
+	Y     int    `json:"y,omitempty"`
+	ToX   int    `json:"to_x,omitempty"`
+	ToY   int    `json:"to_y,omitempty"`
+}
+
+// hold returns how long a press was held.
+func (in Input) hold() time.Duration {
+	return time.Duration(in.HoldMS) * time.Millisecond
+}
+
+// Record writes the deck's input to w until ctx is done. A press is written
+// when it's released, so lines aren't always in time order; Load sorts
+// them. Presses still held when ctx is done are written as held until then.
+func Record(ctx context.Context, w io.Writer) error {
+	ch, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	start := time.Now()
+	at := func(t time.Time) int64 { return t.Sub(start).Milliseconds() }
+
+	// Presses waiting for their release, by type and key or dial
+	type press struct {
+		typ string
+		id  int
+	}
+	held := make(map[press]time.Time)
+
+	write := func(in Input) error {
+		if err := enc.Encode(in); err != nil {
+			return err
+		}
+		// Flushed as it goes, so a crash loses nothing
+		return bw.Flush()
+	}
+
+	for {
+		var e events.Event
+		select {
+		case <-ctx.Done():
+			now := time.Now()
+			for p, t := range held {
+				in := Input{At: at(t), Type: p.typ, HoldMS: now.Sub(t).Milliseconds()}
+				if p.typ == events.TypeKey {
+					in.Key = p.id
+				} else {
+					in.Dial = p.id
+				}
+				if err := write(in); err != nil {
+					return err
+				}
+			}
+			return nil
+		case e = <-ch:
+		}
+
+		var in Input
+		switch e.Type {
+		case events.TypeKey, events.TypeDialPress:
+			p := press{typ: e.Type, id: e.Key}
+			if e.Type == events.TypeDialPress {
+				p.id = e.Dial
+			}
+			if e.Pressed != nil && *e.Pressed {
+				held[p] = e.Time
+				continue
+			}
+			t, ok := held[p]
+			if !ok {
+				// Pressed before the recording started
+				continue
+			}
+			delete(held, p)
+			in = Input{At: at(t), Type: e.Type, Key: e.Key, Dial: e.Dial, HoldMS: e.DurationMS}
+		case events.TypeDialRotate:
+			in = Input{At: at(e.Time), Type: e.Type, Dial: e.Dial, Delta: e.Delta}
+		case events.TypeStripTouch:
+			in = Input{At: at(e.Time), Type: e.Type, Touch: e.Touch, X: e.X, Y: e.Y}
+		case events.TypeStripSwipe:
+			in = Input{At: at(e.Time), Type: e.Type, X: e.X, Y: e.Y, ToX: e.ToX, ToY: e.ToY}
+		default:
+			continue
+		}
+		if err := write(in); err != nil {
+			return err
+		}
+	}
+}
+
+// Load reads a recording, returning its inputs in time order.
+func Load(r io.Reader) ([]Input, error) {
+	var inputs []Input
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var in Input
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		switch in.Type {
+		case events.TypeKey, events.TypeDialRotate, events.TypeDialPress, events.TypeStripTouch, events.TypeStripSwipe:
+		default:
+			return nil, fmt.Errorf("line %d: unknown input type %q", n, in.Type)
+		}
+		inputs = append(inputs, in)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(inputs, func(a, b Input) int {
+		return cmp.Compare(a.At, b.At)
+	})
+	return inputs, nil
+}
+
+// Injector is a device that input can be injected into. The emulator and
+// the fake device implement it.
+type Injector interface {
+	InjectKeyPress(key device.KeyID, hold time.Duration) error
+	InjectDialPress(dial device.DialID, hold time.Duration) error
+	InjectDialRotate(dial device.DialID, delta int8) error
+	InjectStripTouch(touchType device.TouchStripTouchType, p image.Point) error
+	InjectStripSwipe(origin, destination image.Point) error
+}
+
+// Replay plays inputs into dev, keeping their timing, until they run out or
+// ctx is done. Inputs are injected one at a time, in order; a press runs
+// alongside the inputs that came during its hold, and is waited for before
+// any that came after it. speed scales the timing (2 plays twice as fast),
+// and 0 plays without waiting, which suits the fake device. The fake
+// device simulates holds, ending them at once, so input that relies on one
+// key being held while another is used only replays faithfully against
+// the emulator. Errors from the deck's handlers don't stop the replay;
+// they're returned together at the end.
+func Replay(ctx context.Context, dev Injector, inputs []Input, speed float64) error {
+	start := time.Now()
+
+	// Presses still being held
+	type pending struct {
+		end  int64 // At of the release
+		done chan error
+	}
+	var presses []pending
+	var errs []error
+	wait := func(until int64) {
+		presses = slices.DeleteFunc(presses, func(p pending) bool {
+			if p.end > until {
+				return false
+			}
+			if err := <-p.done; err != nil {
+				errs = append(errs, err)
+			}
+			return true
+		})
+	}
+
+	for _, in := range inputs {
+		if speed > 0 {
+			due := start.Add(time.Duration(float64(in.At) / speed * float64(time.Millisecond)))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Until(due)):
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		wait(in.At)
+
+		switch in.Type {
+		case events.TypeKey, events.TypeDialPress:
+			done := make(chan error, 1)
+			go func() {
+				if in.Type == events.TypeKey {
+					done <- dev.InjectKeyPress(device.KeyID(in.Key), in.hold())
+				} else {
+					done <- dev.InjectDialPress(device.DialID(in.Dial), in.hold())
+				}
+			}()
+			presses = append(presses, pending{end: in.At + in.HoldMS, done: done})
+			if speed == 0 {
+				// Nothing to overlap with when there's no waiting
+				wait(in.At + in.HoldMS)
+			}
+		case events.TypeDialRotate:
+			if err := dev.InjectDialRotate(device.DialID(in.Dial), int8(in.Delta)); err != nil {
+				errs = append(errs, err)
+			}
+		case events.TypeStripTouch:
+			touch := device.TOUCH_STRIP_TOUCH_TYPE_SHORT
+			if in.Touch == "long" {
+				touch = device.TOUCH_STRIP_TOUCH_TYPE_LONG
+			}
+			if err := dev.InjectStripTouch(touch, image.Pt(in.X, in.Y)); err != nil {
+				errs = append(errs, err)
+			}
+		case events.TypeStripSwipe:
+			if err := dev.InjectStripSwipe(image.Pt(in.X, in.Y), image.Pt(in.ToX, in.ToY)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	wait(math.MaxInt64)
+	return errors.Join(errs...)
+}
@@ -0,0 +1,134 @@
+// Package state persists small pieces of runtime state, such as the page
+// showing and running timers, so they survive restarts and device
+// reconnects. Values are stored by key as JSON in one file under
+// $XDG_STATE_HOME/belowdeck (default ~/.local/state/belowdeck).
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/logging"
+)
+
+// saveDelay batches bursts of saves, such as a dial spinning a timer, into
+// one write.
+const saveDelay = time.Second
+
+var (
+	mu        sync.Mutex
+	values    map[string]json.RawMessage // nil until first loaded
+	saveTimer *time.Timer
+)
+
+// DefaultPath returns the state file path.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "belowdeck", "state.json")
+}
+
+// Load decodes the value saved under key into v, reporting whether one was
+// found. A missing or unreadable state file is treated as empty.
+func Load(key string, v any) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	raw, ok := values[key]
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		logging.For("state").Warn("Ignoring saved state", "key", key, "err", err)
+		return false
+	}
+	return true
+}
+
+// Save stores v under key. The file is written shortly after, so callers
+// can save on every change.
+func Save(key string, v any) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		logging.For("state").Warn("Failed to encode state", "key", key, "err", err)
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+	values[key] = raw
+	if saveTimer == nil {
+		saveTimer = time.AfterFunc(saveDelay, func() {
+			if err := Flush(); err != nil {
+				logging.For("state").Warn("Failed to save state", "err", err)
+			}
+		})
+	}
+}
+
+// Flush writes pending changes immediately. The daemon calls it on shutdown.
+func Flush() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if saveTimer == nil {
+		return nil
+	}
+	saveTimer.Stop()
+	saveTimer = nil
+
+	path := DefaultPath()
+	if path == "" {
+		return errors.New("no home directory for state file")
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write then rename so a crash mid-write can't leave a truncated file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// load reads the state file on first use. Must be called with mu held.
+func load() {
+	if values != nil {
+		return
+	}
+	values = make(map[string]json.RawMessage)
+
+	path := DefaultPath()
+	if path == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logging.For("state").Warn("Failed to read state", "path", path, "err", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		logging.For("state").Warn("Ignoring corrupt state file", "path", path, "err", err)
+		values = make(map[string]json.RawMessage)
+	}
+}
@@ -0,0 +1,50 @@
+// Package units converts and formats measurements for the configured unit
+// system. Modules keep values in imperial units internally and convert only
+// for display.
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// System is a unit system, set globally by the units config setting.
+type System string
+
+const (
+	Imperial System = "imperial" // °F, mph
+	Metric   System = "metric"   // °C, km/h
+)
+
+// Parse returns the System named by s. Empty means Imperial.
+func Parse(s string) (System, error) {
+	switch System(strings.ToLower(s)) {
+	case "", Imperial:
+		return Imperial, nil
+	case Metric:
+		return Metric, nil
+	default:
+		return "", fmt.Errorf("unknown units %q (want imperial or metric)", s)
+	}
+}
+
+// TempValue converts a temperature in °F to the system's unit.
+func (s System) TempValue(fahrenheit float64) float64 {
+	if s == Metric {
+		return (fahrenheit - 32) * 5 / 9
+	}
+	return fahrenheit
+}
+
+// Temp formats a temperature given in °F as whole degrees, e.g. "72°".
+func (s System) Temp(fahrenheit float64) string {
+	return fmt.Sprintf("%.0f°", s.TempValue(fahrenheit))
+}
+
+// Speed formats a speed given in mph, e.g. "10 mph" or "16 km/h".
+func (s System) Speed(mph float64) string {
+	if s == Metric {
+		return fmt.Sprintf("%.0f km/h", mph*1.609344)
+	}
+	return fmt.Sprintf("%.0f mph", mph)
+}
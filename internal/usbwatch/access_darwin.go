@@ -0,0 +1,38 @@
+package usbwatch
+
+import "github.com/ebitengine/purego"
+
+// Access is the process's Input Monitoring permission, which macOS requires
+// before it delivers HID input reports (key presses, dial turns).
+type Access int
+
+const (
+	AccessGranted Access = iota
+	AccessDenied
+	AccessUnknown // not yet requested; macOS prompts on first use
+)
+
+// kIOHIDRequestTypeListenEvent is the IOHIDRequestType for Input Monitoring.
+const kIOHIDRequestTypeListenEvent = 1
+
+var ioHIDCheckAccess func(requestType uint32) uint32
+
+func init() {
+	iokit, err := purego.Dlopen("/System/Library/Frameworks/IOKit.framework/IOKit", purego.RTLD_LAZY|purego.RTLD_GLOBAL)
+	if err != nil {
+		panic(err)
+	}
+	purego.RegisterLibFunc(&ioHIDCheckAccess, iokit, "IOHIDCheckAccess")
+}
+
+// InputMonitoringAccess reports whether this process may listen to HID input.
+func InputMonitoringAccess() Access {
+	switch ioHIDCheckAccess(kIOHIDRequestTypeListenEvent) {
+	case 0:
+		return AccessGranted
+	case 1:
+		return AccessDenied
+	default:
+		return AccessUnknown
+	}
+}
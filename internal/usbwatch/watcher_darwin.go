@@ -2,11 +2,13 @@ package usbwatch
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"runtime"
 	"unsafe"
 
 	"github.com/ebitengine/purego"
+	"github.com/phinze/belowdeck/internal/logging"
 )
 
 // CF and IOKit type aliases matching usbhid conventions.
@@ -94,6 +96,7 @@ func init() {
 type watcherCtx struct {
 	ch       chan<- struct{}
 	vendorID uint16
+	logger   *slog.Logger
 }
 
 func deviceMatchingCallback(_ unsafe.Pointer, _ ioReturn, _ uintptr, device ioHIDDeviceRef) {
@@ -110,7 +113,7 @@ func deviceMatchingCallback(_ unsafe.Pointer, _ ioReturn, _ uintptr, device ioHI
 		return
 	}
 
-	log.Printf("USB device arrived (vendor 0x%04x)", vid)
+	callbackCtx.logger.Info("USB device arrived", "vendor", fmt.Sprintf("0x%04x", vid))
 	select {
 	case callbackCtx.ch <- struct{}{}:
 	default:
@@ -153,6 +156,7 @@ func Watch(ctx context.Context, vendorID uint16) <-chan struct{} {
 	wctx := &watcherCtx{
 		ch:       ch,
 		vendorID: vendorID,
+		logger:   logging.For("usbwatch"),
 	}
 	callbackCtx = wctx
 
@@ -162,7 +166,7 @@ func Watch(ctx context.Context, vendorID uint16) <-chan struct{} {
 
 		mgr := ioHIDManagerCreate(kCFAllocatorDefault, kIOHIDOptionsTypeNone)
 		if rv := ioHIDManagerOpen(mgr, kIOHIDOptionsTypeNone); rv != kIOReturnSuccess {
-			log.Printf("usbwatch: failed to open IOHIDManager: 0x%08x", rv)
+			wctx.logger.Error("Failed to open IOHIDManager", "ret", fmt.Sprintf("0x%08x", rv))
 			return
 		}
 
@@ -179,13 +183,13 @@ func Watch(ctx context.Context, vendorID uint16) <-chan struct{} {
 			cfRunLoopStop(rl)
 		}()
 
-		log.Println("usbwatch: listening for USB HID device arrivals")
+		wctx.logger.Info("Listening for USB HID device arrivals")
 		cfRunLoopRun()
 
 		ioHIDManagerClose(mgr, kIOHIDOptionsTypeNone)
 		cfRelease(cfTypeRef(mgr))
 		callbackCtx = nil
-		log.Println("usbwatch: stopped")
+		wctx.logger.Info("Stopped")
 	}()
 
 	return ch
@@ -0,0 +1,80 @@
+package usbwatch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/logging"
+)
+
+// kernelGroup is the netlink multicast group the kernel sends uevents on.
+const kernelGroup = 1
+
+// settleDelay gives udev time to apply permission rules to a new hidraw
+// node before we signal that it can be opened.
+const settleDelay = 250 * time.Millisecond
+
+// Watch returns a channel that receives a signal each time a hidraw device
+// with the given vendor ID appears. Listens for kernel uevents on a netlink
+// socket for zero-CPU-cost waiting. The watcher stops when ctx is cancelled.
+func Watch(ctx context.Context, vendorID uint16) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	logger := logging.For("usbwatch")
+
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		logger.Error("Failed to open uevent socket", "err", err)
+		return ch
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: kernelGroup}); err != nil {
+		logger.Error("Failed to bind uevent socket", "err", err)
+		syscall.Close(fd)
+		return ch
+	}
+
+	// A non-blocking fd is registered with the runtime poller, so closing
+	// the file unblocks Read
+	sock := os.NewFile(uintptr(fd), "uevent")
+	go func() {
+		<-ctx.Done()
+		sock.Close()
+	}()
+
+	// HID devices appear as .../0003:0FD9:0084.0001/hidraw/hidraw3
+	match := []byte(fmt.Sprintf(":%04X:", vendorID))
+
+	go func() {
+		logger.Info("Listening for hidraw device arrivals")
+		buf := make([]byte, 16<<10)
+		for {
+			n, err := sock.Read(buf)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("Reading uevents failed", "err", err)
+				}
+				logger.Info("Stopped")
+				return
+			}
+
+			// Messages are "action@devpath" followed by NUL-separated KEY=value pairs
+			header, _, _ := bytes.Cut(buf[:n], []byte{0})
+			action, devpath, ok := bytes.Cut(header, []byte("@"))
+			if !ok || string(action) != "add" || !bytes.Contains(devpath, []byte("/hidraw/")) || !bytes.Contains(devpath, match) {
+				continue
+			}
+
+			logger.Info("USB device arrived", "vendor", fmt.Sprintf("0x%04x", vendorID), "path", string(devpath))
+			time.Sleep(settleDelay)
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return ch
+}
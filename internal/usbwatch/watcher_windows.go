@@ -0,0 +1,97 @@
+package usbwatch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/phinze/belowdeck/internal/logging"
+	"github.com/phinze/belowdeck/internal/winmsg"
+)
+
+const (
+	wmDeviceChange           = 0x0219
+	dbtDeviceArrival         = 0x8000
+	dbtDevtypDeviceInterface = 5
+	deviceNotifyWindowHandle = 0
+)
+
+// guidDevinterfaceHID is GUID_DEVINTERFACE_HID.
+var guidDevinterfaceHID = syscall.GUID{
+	Data1: 0x4D1E55B2, Data2: 0xF16F, Data3: 0x11CF,
+	Data4: [8]byte{0x88, 0xCB, 0x00, 0x11, 0x11, 0x00, 0x00, 0x30},
+}
+
+var procRegisterDeviceNotificationW = syscall.NewLazyDLL("user32.dll").NewProc("RegisterDeviceNotificationW")
+
+// devBroadcastDeviceInterface is DEV_BROADCAST_DEVICEINTERFACE_W; name is
+// a NUL-terminated string running past the end of the struct.
+type devBroadcastDeviceInterface struct {
+	size       uint32
+	deviceType uint32
+	reserved   uint32
+	classGUID  syscall.GUID
+	name       [1]uint16
+}
+
+// Watch returns a channel that receives a signal each time a USB HID device
+// with the given vendor ID appears. Uses WM_DEVICECHANGE notifications to a
+// hidden window for zero-CPU-cost waiting. The watcher stops when ctx is
+// cancelled.
+func Watch(ctx context.Context, vendorID uint16) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	logger := logging.For("usbwatch")
+
+	// Interface paths look like \\?\HID#VID_0FD9&PID_0084#...
+	match := fmt.Sprintf("VID_%04X", vendorID)
+
+	register := func(hwnd uintptr) error {
+		filter := devBroadcastDeviceInterface{
+			deviceType: dbtDevtypDeviceInterface,
+			classGUID:  guidDevinterfaceHID,
+		}
+		filter.size = uint32(unsafe.Sizeof(filter))
+		if r, _, err := procRegisterDeviceNotificationW.Call(hwnd, uintptr(unsafe.Pointer(&filter)), deviceNotifyWindowHandle); r == 0 {
+			return fmt.Errorf("RegisterDeviceNotification: %w", err)
+		}
+		return nil
+	}
+
+	handle := func(msg uint32, wParam, lParam uintptr) {
+		if msg != wmDeviceChange || wParam != dbtDeviceArrival || lParam == 0 {
+			return
+		}
+		dev := *(**devBroadcastDeviceInterface)(unsafe.Pointer(&lParam))
+		if dev.deviceType != dbtDevtypDeviceInterface {
+			return
+		}
+		name := utf16String(&dev.name[0])
+		if !strings.Contains(strings.ToUpper(name), match) {
+			return
+		}
+
+		logger.Info("USB device arrived", "vendor", fmt.Sprintf("0x%04x", vendorID), "path", name)
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+
+	if err := winmsg.Listen(ctx, "BelowdeckUSBWatch", register, handle); err != nil {
+		logger.Error("Failed to watch for device arrivals", "err", err)
+		return ch
+	}
+	logger.Info("Listening for USB HID device arrivals")
+	return ch
+}
+
+// utf16String reads the NUL-terminated UTF-16 string starting at p.
+func utf16String(p *uint16) string {
+	var s []uint16
+	for ptr := unsafe.Pointer(p); *(*uint16)(ptr) != 0; ptr = unsafe.Add(ptr, 2) {
+		s = append(s, *(*uint16)(ptr))
+	}
+	return syscall.UTF16ToString(s)
+}
@@ -0,0 +1,164 @@
+// Package winmsg runs hidden windows that receive Windows broadcast
+// messages, such as device arrivals and power events, which are only
+// delivered to windows.
+package winmsg
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procCreateWindowExW  = user32.NewProc("CreateWindowExW")
+	procDefWindowProcW   = user32.NewProc("DefWindowProcW")
+	procDestroyWindow    = user32.NewProc("DestroyWindow")
+	procDispatchMessageW = user32.NewProc("DispatchMessageW")
+	procGetMessageW      = user32.NewProc("GetMessageW")
+	procPostMessageW     = user32.NewProc("PostMessageW")
+	procPostQuitMessage  = user32.NewProc("PostQuitMessage")
+	procRegisterClassExW = user32.NewProc("RegisterClassExW")
+	procTranslateMessage = user32.NewProc("TranslateMessage")
+	procGetModuleHandleW = kernel32.NewProc("GetModuleHandleW")
+)
+
+const (
+	wmDestroy = 0x0002
+	wmClose   = 0x0010
+
+	errorClassAlreadyExists syscall.Errno = 1410
+)
+
+// wndClassEx is WNDCLASSEXW.
+type wndClassEx struct {
+	size       uint32
+	style      uint32
+	wndProc    uintptr
+	clsExtra   int32
+	wndExtra   int32
+	instance   uintptr
+	icon       uintptr
+	cursor     uintptr
+	background uintptr
+	menuName   *uint16
+	className  *uint16
+	iconSm     uintptr
+}
+
+// msg is MSG.
+type msg struct {
+	hwnd     uintptr
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	pt       struct{ x, y int32 }
+	lPrivate uint32
+}
+
+// Handler is called on the window's thread for each message it receives.
+type Handler func(msg uint32, wParam, lParam uintptr)
+
+var (
+	mu       sync.Mutex
+	handlers = make(map[uintptr]Handler)
+
+	// wndProc is shared by every window; callbacks are a limited resource
+	wndProc = syscall.NewCallback(func(hwnd, msg, wParam, lParam uintptr) uintptr {
+		mu.Lock()
+		h := handlers[hwnd]
+		mu.Unlock()
+		if h != nil {
+			h(uint32(msg), wParam, lParam)
+		}
+		if msg == wmDestroy {
+			procPostQuitMessage.Call(0)
+		}
+		r, _, _ := procDefWindowProcW.Call(hwnd, msg, wParam, lParam)
+		return r
+	})
+)
+
+// Listen creates a hidden top-level window of class name, calls setup with
+// its handle, and passes the window's messages to h until ctx is canceled.
+// Top-level windows, unlike message-only ones, also receive broadcasts like
+// WM_POWERBROADCAST. Listen returns once the window is set up; messages are
+// handled on a goroutine of their own.
+func Listen(ctx context.Context, name string, setup func(hwnd uintptr) error, h Handler) error {
+	errCh := make(chan error, 1)
+	go func() {
+		// A window's messages go to the thread that created it
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		hwnd, err := createWindow(name)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		mu.Lock()
+		handlers[hwnd] = h
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(handlers, hwnd)
+			mu.Unlock()
+		}()
+
+		if setup != nil {
+			if err := setup(hwnd); err != nil {
+				procDestroyWindow.Call(hwnd)
+				errCh <- err
+				return
+			}
+		}
+		errCh <- nil
+
+		// Closing the window destroys it, which ends the loop below
+		go func() {
+			<-ctx.Done()
+			procPostMessageW.Call(hwnd, wmClose, 0, 0)
+		}()
+
+		var m msg
+		for {
+			r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(r) <= 0 {
+				return
+			}
+			procTranslateMessage.Call(uintptr(unsafe.Pointer(&m)))
+			procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+		}
+	}()
+	return <-errCh
+}
+
+// createWindow registers window class name, if it isn't already, and
+// creates a hidden window of it.
+func createWindow(name string) (uintptr, error) {
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	className, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := wndClassEx{wndProc: wndProc, instance: instance, className: className}
+	wc.size = uint32(unsafe.Sizeof(wc))
+	if r, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 && err != errorClassAlreadyExists {
+		return 0, fmt.Errorf("RegisterClassEx: %w", err)
+	}
+
+	hwnd, _, err := procCreateWindowExW.Call(0,
+		uintptr(unsafe.Pointer(className)), uintptr(unsafe.Pointer(className)),
+		0, 0, 0, 0, 0, 0, 0, instance, 0)
+	if hwnd == 0 {
+		return 0, fmt.Errorf("CreateWindowEx: %w", err)
+	}
+	return hwnd, nil
+}
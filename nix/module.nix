@@ -10,8 +10,9 @@ with lib;
 let
   cfg = config.services.belowdeck;
 
-  # Generate config.yaml from settings attrset
-  configFile = pkgs.writeText "belowdeck-config.yaml" (builtins.toJSON cfg.settings);
+  # Generate config.yaml from settings attrset. Settings written for the
+  # version 1 format stay valid; set settings.version if they're newer.
+  configFile = pkgs.writeText "belowdeck-config.yaml" (builtins.toJSON ({ version = 1; } // cfg.settings));
 in
 {
   options.services.belowdeck = {
@@ -30,10 +31,14 @@ in
       description = "User account that will run the daemon.";
     };
 
-    mediaControlPath = mkOption {
-      type = types.str;
-      default = "/opt/homebrew/bin/media-control";
-      description = "Path to the media-control binary (Homebrew-only dependency).";
+    logFile = mkOption {
+      type = types.nullOr types.str;
+      default = "/Users/${cfg.user}/Library/Logs/belowdeck/belowdeck.log";
+      defaultText = literalExpression ''"/Users/''${cfg.user}/Library/Logs/belowdeck/belowdeck.log"'';
+      description = ''
+        Log file written by the daemon and rotated by size. Set to null to
+        log to stderr (captured in /tmp/belowdeck.log).
+      '';
     };
 
     settings = mkOption {
@@ -51,6 +56,7 @@ in
             server = "https://ha.example.com/";
             ring_light_entity = "light.ring_light";
             office_light_entity = "light.office";
+            entities = [ "climate.office" "media_player.living_room" ];
           };
         }
       '';
@@ -81,7 +87,6 @@ in
         "/bin"
         "/usr/sbin"
         "/sbin"
-        (builtins.dirOf cfg.mediaControlPath)
         "/etc/profiles/per-user/${cfg.user}/bin"
       ];
       serviceConfig = {
@@ -90,6 +95,9 @@ in
         ];
         EnvironmentVariables = {
           BELOWDECK_CONFIG = "${configFile}";
+        }
+        // optionalAttrs (cfg.logFile != null) {
+          BELOWDECK_LOG_FILE = cfg.logFile;
         };
         KeepAlive = true;
         RunAtLoad = true;